@@ -0,0 +1,93 @@
+package api
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"cmon/internal/metrics"
+	"cmon/internal/session"
+)
+
+// recordCache keeps complaint-record responses for a short while, keyed by
+// API ID, so the worker pool, the summary backfill and the bot's detail
+// view do not each fetch the same record within one cycle. A status change
+// made through this package (assignComplaint) drops the complaint's entry;
+// callers that need the portal's current view call InvalidateRecord first.
+type recordCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration // 0 disables the cache
+	entries map[string]cachedRecord
+}
+
+type cachedRecord struct {
+	body    []byte
+	fetched time.Time
+}
+
+// records is the process-wide cache. Disabled until SetRecordCacheTTL.
+var records = &recordCache{entries: make(map[string]cachedRecord)}
+
+// cacheNow is swapped by tests to age entries.
+var cacheNow = time.Now
+
+// SetRecordCacheTTL sets how long a fetched complaint record is reused;
+// 0 (the default) fetches every time. Boot-time, like SetRecordEndpoint.
+func SetRecordCacheTTL(ttl time.Duration) {
+	records.mu.Lock()
+	defer records.mu.Unlock()
+	records.ttl = ttl
+	clear(records.entries)
+}
+
+// InvalidateRecord drops the cached record of apiID, so the next fetch
+// goes to the portal.
+func InvalidateRecord(apiID string) {
+	records.mu.Lock()
+	defer records.mu.Unlock()
+	delete(records.entries, apiID)
+}
+
+// GetRecordJSON returns the complaint-record response for apiID, from the
+// cache while it is fresh and from the portal otherwise.
+func GetRecordJSON(sc *session.Client, apiID string) ([]byte, error) {
+	if body, ok := records.get(apiID); ok {
+		metrics.RecordCacheHitsTotal.Inc()
+		return body, nil
+	}
+	body, err := sc.GetJSON(RecordURL(apiID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch complaint record: %w", err)
+	}
+	if len(body) > 0 {
+		records.put(apiID, body)
+	}
+	return body, nil
+}
+
+func (c *recordCache) get(apiID string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[apiID]
+	if !ok || c.ttl <= 0 || cacheNow().Sub(e.fetched) >= c.ttl {
+		return nil, false
+	}
+	return e.body, true
+}
+
+// put stores body and sweeps expired entries, so the cache holds no more
+// than one TTL's worth of fetches.
+func (c *recordCache) put(apiID string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ttl <= 0 {
+		return
+	}
+	now := cacheNow()
+	for id, e := range c.entries {
+		if now.Sub(e.fetched) >= c.ttl {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[apiID] = cachedRecord{body: body, fetched: now}
+}
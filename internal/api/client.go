@@ -13,6 +13,7 @@ package api
 
 import (
 	"cmon/internal/config"
+	"cmon/internal/proxy"
 	"net/http"
 	"time"
 )
@@ -89,6 +90,8 @@ func NewHTTPClient(cfg *config.Config) *http.Client {
 	return &http.Client{
 		Timeout: cfg.HTTPTimeout,
 		Transport: &http.Transport{
+			Proxy: proxy.Func,
+
 			// Connection pool settings
 			MaxIdleConns:        cfg.HTTPMaxConns, // Total idle connections
 			MaxIdleConnsPerHost: cfg.HTTPMaxConns / 10,  // Per-host idle connections
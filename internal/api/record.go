@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cmon/internal/session"
+)
+
+// DefaultRecordEndpoint is the DGVCL production complaint-record API; the
+// complaint's API ID is appended.
+const DefaultRecordEndpoint = "https://complaint.dgvcl.com/api/complaint-record/"
+
+// recordEndpoint is the active endpoint. Mutated only from
+// SetRecordEndpoint (boot-time, single-threaded) and from package tests.
+var recordEndpoint = DefaultRecordEndpoint
+
+// SetRecordEndpoint installs the URL prefix RecordURL builds on. Like
+// SetResolveEndpoint, an empty string is a no-op.
+func SetRecordEndpoint(url string) {
+	if url == "" {
+		return
+	}
+	recordEndpoint = url
+}
+
+// RecordURL returns the complaint-record API URL for apiID.
+func RecordURL(apiID string) string {
+	return recordEndpoint + apiID
+}
+
+// Record is the portal's current view of one complaint: the
+// "complaintdetail" object plus any history rows (assignments, status
+// changes, remarks) the response carries alongside it.
+type Record struct {
+	Detail  map[string]interface{}
+	History []map[string]interface{}
+}
+
+// FetchComplaintRecord GETs the complaint record through the authenticated
+// session, or reuses one fetched within the record cache's TTL (see
+// GetRecordJSON). Local complaints have no portal record.
+func FetchComplaintRecord(sc *session.Client, apiID string) (*Record, error) {
+	if IsLocalID(apiID) {
+		return nil, fmt.Errorf("complaint %s is local and has no portal record", apiID)
+	}
+	body, err := GetRecordJSON(sc, apiID)
+	if err != nil {
+		return nil, err
+	}
+	return parseRecord(body)
+}
+
+// parseRecord decodes a complaint-record response. The history key is not
+// fixed across portal versions, so every top-level array of objects is
+// taken as history, in key order.
+func parseRecord(body []byte) (*Record, error) {
+	var full map[string]interface{}
+	if err := json.Unmarshal(body, &full); err != nil {
+		return nil, fmt.Errorf("failed to parse complaint record: %w", err)
+	}
+	detail, ok := full["complaintdetail"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("complaintdetail missing in API response")
+	}
+
+	rec := &Record{Detail: detail}
+	keys := make([]string, 0, len(full))
+	for k := range full {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		rows, ok := full[k].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, row := range rows {
+			if m, ok := row.(map[string]interface{}); ok {
+				rec.History = append(rec.History, m)
+			}
+		}
+	}
+	return rec, nil
+}
+
+// Status returns the record's complain_status, e.g. "Registered".
+func (r *Record) Status() string {
+	s, _ := r.Detail["complain_status"].(string)
+	return strings.TrimSpace(s)
+}
+
+// IsOpen reports whether the record's status still shows the complaint as
+// awaiting work. An empty or unrecognised status is not open.
+func (r *Record) IsOpen() bool {
+	status := strings.ToLower(r.Status())
+	for _, open := range []string{"register", "pending", "assign", "open", "progress"} {
+		if strings.Contains(status, open) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolution returns who resolved the complaint and the remark they
+// entered, from the latest history row carrying a remark, else from the
+// detail. The portal has used several key names for both over time.
+func (r *Record) Resolution() (by, remark string) {
+	rows := append([]map[string]interface{}{r.Detail}, r.History...)
+	for i := len(rows) - 1; i >= 0; i-- {
+		if remark = firstString(rows[i], "remark", "remarks", "resolve_remark", "resolution_remark"); remark != "" {
+			return firstString(rows[i], "resolved_by", "updated_by", "user_name", "assign_to", "created_by"), remark
+		}
+	}
+	return firstString(r.Detail, "resolved_by", "updated_by"), ""
+}
+
+// firstString returns the first of keys that holds a non-blank string.
+func firstString(m map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := m[k].(string); ok && strings.TrimSpace(s) != "" {
+			return strings.TrimSpace(s)
+		}
+	}
+	return ""
+}
+
+// IsLocalID reports whether apiID belongs to a complaint registered in cmon
+// rather than on the portal.
+func IsLocalID(apiID string) bool {
+	lower := strings.ToLower(apiID)
+	return strings.HasPrefix(lower, "local") || strings.HasPrefix(lower, "l-") || strings.HasPrefix(lower, "vld")
+}
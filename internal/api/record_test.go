@@ -0,0 +1,132 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchComplaintRecord(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"complaintdetail": {"complain_no": "123", "complain_status": "Assigned"},
+			"complainthistory": [
+				{"created_at": "2026-03-01 10:00", "status": "Registered"},
+				{"created_at": "2026-03-01 11:30", "status": "Assigned", "assign_to": "JE Tokarva"}
+			],
+			"message": "ok"
+		}`)
+	}))
+	defer srv.Close()
+	prev := recordEndpoint
+	recordEndpoint = srv.URL + "/api/complaint-record/"
+	t.Cleanup(func() { recordEndpoint = prev })
+
+	rec, err := FetchComplaintRecord(newTestClient(t), "456")
+	if err != nil {
+		t.Fatalf("FetchComplaintRecord: %v", err)
+	}
+	if path != "/api/complaint-record/456" {
+		t.Errorf("requested %q", path)
+	}
+	if rec.Detail["complain_status"] != "Assigned" {
+		t.Errorf("detail = %v", rec.Detail)
+	}
+	if len(rec.History) != 2 || rec.History[1]["assign_to"] != "JE Tokarva" {
+		t.Errorf("history = %v", rec.History)
+	}
+}
+
+func TestFetchComplaintRecordRejectsLocalAndMalformed(t *testing.T) {
+	if _, err := FetchComplaintRecord(nil, "LOCAL-1"); err == nil {
+		t.Error("local complaint should have no portal record")
+	}
+	if _, err := parseRecord([]byte(`{"message":"not found"}`)); err == nil {
+		t.Error("response without complaintdetail should fail")
+	}
+}
+
+func TestRecordIsOpen(t *testing.T) {
+	for status, want := range map[string]bool{
+		"Registered":  true,
+		"Assigned":    true,
+		"In Progress": true,
+		"Resolved":    false,
+		"Closed":      false,
+		"":            false,
+	} {
+		rec := &Record{Detail: map[string]interface{}{"complain_status": status}}
+		if got := rec.IsOpen(); got != want {
+			t.Errorf("IsOpen(%q) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestRecordResolution(t *testing.T) {
+	rec := &Record{
+		Detail: map[string]interface{}{"complain_status": "Resolved"},
+		History: []map[string]interface{}{
+			{"status": "Assigned", "assign_to": "JE Tokarva", "remark": "sent lineman"},
+			{"status": "Resolved", "resolved_by": "JE Tokarva", "remark": " fuse replaced "},
+			{"status": "Closed"},
+		},
+	}
+	if by, remark := rec.Resolution(); by != "JE Tokarva" || remark != "fuse replaced" {
+		t.Errorf("Resolution() = %q, %q", by, remark)
+	}
+	if by, remark := (&Record{Detail: map[string]interface{}{}}).Resolution(); by != "" || remark != "" {
+		t.Errorf("empty record Resolution() = %q, %q", by, remark)
+	}
+}
+
+func TestRecordCache(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"complaintdetail": {"complain_no": "123", "call": %d}}`, calls)
+	}))
+	defer srv.Close()
+	prev := recordEndpoint
+	recordEndpoint = srv.URL + "/api/complaint-record/"
+	resolvePrev := resolveEndpoint
+	resolveEndpoint = srv.URL + "/api/complaint-assign-process"
+	now := time.Now()
+	cacheNow = func() time.Time { return now }
+	SetRecordCacheTTL(time.Minute)
+	t.Cleanup(func() {
+		recordEndpoint, resolveEndpoint, cacheNow = prev, resolvePrev, time.Now
+		SetRecordCacheTTL(0)
+	})
+	sc := newTestClient(t)
+
+	fetch := func() float64 {
+		t.Helper()
+		rec, err := FetchComplaintRecord(sc, "456")
+		if err != nil {
+			t.Fatalf("FetchComplaintRecord: %v", err)
+		}
+		return rec.Detail["call"].(float64)
+	}
+	if fetch() != 1 || fetch() != 1 {
+		t.Errorf("second fetch within the TTL should be cached; API calls = %d", calls)
+	}
+
+	now = now.Add(time.Minute)
+	if got := fetch(); got != 2 {
+		t.Errorf("fetch after the TTL = call %v, want a fresh one", got)
+	}
+
+	// Resolving changes the record; the next read must see it.
+	if err := ResolveComplaint(sc, "456", "done", false); err != nil {
+		t.Fatalf("ResolveComplaint: %v", err)
+	}
+	if got := fetch(); got != 4 {
+		t.Errorf("fetch after resolving = call %v, want a fresh one", got)
+	}
+}
@@ -56,9 +56,29 @@ func SetResolveEndpoint(url string) {
 // Returns:
 //   - error: API call failure or HTTP error, nil on success
 func ResolveComplaint(sc *session.Client, apiID string, remark string, debugMode bool) error {
-	lowerID := strings.ToLower(apiID)
-	if strings.HasPrefix(lowerID, "local") || strings.HasPrefix(lowerID, "l-") || strings.HasPrefix(lowerID, "vld") {
-		log.Printf("  ✓ [LOCAL] Bypassing website resolution for local complaint ID: %s", apiID)
+	return assignComplaint(sc, apiID, AssignResolved, remark, debugMode)
+}
+
+// Assignment types the portal's assign endpoint accepts as
+// complaint_AsignType.
+const (
+	AssignResolved = "resolved"
+	// AssignPending puts a complaint back in the pending list, undoing a
+	// resolution.
+	AssignPending = "pending"
+)
+
+// ReopenComplaint moves a complaint resolved by ResolveComplaint back to
+// pending on the DGVCL website, through the same assignment endpoint with
+// complaint_AsignType=pending. Used to undo a resolution.
+func ReopenComplaint(sc *session.Client, apiID string, remark string, debugMode bool) error {
+	return assignComplaint(sc, apiID, AssignPending, remark, debugMode)
+}
+
+// assignComplaint posts an assignment of apiID to assignType with remark.
+func assignComplaint(sc *session.Client, apiID, assignType, remark string, debugMode bool) error {
+	if IsLocalID(apiID) {
+		log.Printf("  ✓ [LOCAL] Bypassing website %s for local complaint ID: %s", assignType, apiID)
 		return nil
 	}
 
@@ -66,21 +86,24 @@ func ResolveComplaint(sc *session.Client, apiID string, remark string, debugMode
 
 	formData := url.Values{
 		"complaint_id":        {apiID},
-		"complaint_AsignType": {"resolved"},
+		"complaint_AsignType": {assignType},
 		"remark":              {remark},
 	}
 
-	log.Printf("  → Marking complaint %s as resolved on website...\n", apiID)
+	log.Printf("  → Marking complaint %s as %s on website...\n", apiID, assignType)
 
 	if debugMode {
 		log.Printf("  🐛 DEBUG MODE: Skipping API call\n")
 		log.Printf("  🐛 Would POST: %s\n", apiURL)
 		log.Printf("  🐛 With body: %s\n", formData.Encode())
-		log.Printf("  ✓ [DEBUG] Simulated successful resolution\n")
+		log.Printf("  ✓ [DEBUG] Simulated successful %s assignment\n", assignType)
 		return nil
 	}
 
 	metrics.ResolveCallsTotal.Inc()
+	// Whatever the outcome, the cached record may no longer be the
+	// portal's.
+	defer InvalidateRecord(apiID)
 	responseBody, err := sc.PostForm(apiURL, formData)
 	if err != nil {
 		metrics.ResolveFailuresTotal.Inc()
@@ -95,7 +118,7 @@ func ResolveComplaint(sc *session.Client, apiID string, remark string, debugMode
 		return fmt.Errorf("API call failed: %s", responseText[6:])
 	}
 
-	log.Printf("  ✓ Successfully marked complaint %s as resolved on website\n", apiID)
+	log.Printf("  ✓ Successfully marked complaint %s as %s on website\n", apiID, assignType)
 	log.Printf("  → API Response: %s\n", responseText)
 
 	return nil
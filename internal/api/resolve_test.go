@@ -85,6 +85,26 @@ func TestResolveComplaintSendsExpectedFormFields(t *testing.T) {
 	}
 }
 
+// TestReopenComplaintAssignsPending verifies an undo posts to the same
+// assignment endpoint with complaint_AsignType=pending.
+func TestReopenComplaintAssignsPending(t *testing.T) {
+	var assignType, remark string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assignType = r.PostFormValue("complaint_AsignType")
+		remark = r.PostFormValue("remark")
+		_, _ = w.Write([]byte("OK"))
+	}))
+	defer srv.Close()
+	withEndpoint(t, srv.URL)
+
+	if err := ReopenComplaint(newTestClient(t), "API-123", "resolution undone", false); err != nil {
+		t.Fatalf("ReopenComplaint: %v", err)
+	}
+	if assignType != AssignPending || remark != "resolution undone" {
+		t.Errorf("posted complaint_AsignType=%q remark=%q, want pending and the remark", assignType, remark)
+	}
+}
+
 // TestResolveComplaintSurfacesERRORResponse verifies the contract that the
 // DGVCL portal signals an application-level failure with an "ERROR:" prefix
 // in a 200 response — must turn into a Go error, not a silent OK.
@@ -0,0 +1,101 @@
+// Package backup takes timestamped snapshots of the complaint database into
+// a backups directory and keeps only the newest few.
+//
+// Snapshots are written under a temporary name and renamed into place, so a
+// crash mid-backup never leaves a truncated file that looks like a good
+// backup.
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// File names are cmon-<UTC timestamp>.db; the layout sorts in time order.
+const (
+	filePrefix = "cmon-"
+	fileExt    = ".db"
+	timeLayout = "20060102-150405"
+)
+
+// Source produces a consistent snapshot at path; *storage.Storage
+// implements it.
+type Source interface {
+	Backup(ctx context.Context, path string) error
+}
+
+// Run writes a new snapshot of src into dir (created if missing) and then
+// removes all but the newest keep snapshots (keep <= 0 keeps all). Returns
+// the new snapshot's path.
+func Run(ctx context.Context, src Source, dir string, keep int, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("create backup directory: %w", err)
+	}
+	path := filepath.Join(dir, FileName(now))
+	tmp := path + ".tmp"
+	_ = os.Remove(tmp) // left over from a crashed backup
+
+	if err := src.Backup(ctx, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return "", fmt.Errorf("snapshot database: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return "", fmt.Errorf("finish backup: %w", err)
+	}
+
+	if keep > 0 {
+		if err := prune(dir, keep); err != nil {
+			return path, fmt.Errorf("prune old backups: %w", err)
+		}
+	}
+	return path, nil
+}
+
+// FileName is the name of a snapshot taken at t.
+func FileName(t time.Time) string {
+	return filePrefix + t.UTC().Format(timeLayout) + fileExt
+}
+
+// List returns the snapshots in dir, newest first.
+func List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileExt) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileExt)
+		if _, err := time.Parse(timeLayout, stamp); err != nil {
+			continue // not one of ours
+		}
+		out = append(out, filepath.Join(dir, name))
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(out)))
+	return out, nil
+}
+
+func prune(dir string, keep int) error {
+	snapshots, err := List(dir)
+	if err != nil {
+		return err
+	}
+	for i := keep; i < len(snapshots); i++ {
+		if err := os.Remove(snapshots[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
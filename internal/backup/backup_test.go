@@ -0,0 +1,59 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fileSource struct{ err error }
+
+func (f fileSource) Backup(_ context.Context, path string) error {
+	if f.err != nil {
+		os.WriteFile(path, []byte("partial"), 0o600)
+		return f.err
+	}
+	return os.WriteFile(path, []byte("snapshot"), 0o600)
+}
+
+func TestRunKeepsNewestSnapshots(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "backups")
+	start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 4; i++ {
+		path, err := Run(context.Background(), fileSource{}, dir, 2, start.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatalf("Run %d: %v", i, err)
+		}
+		if got, _ := os.ReadFile(path); string(got) != "snapshot" {
+			t.Errorf("snapshot %s: got %q", path, got)
+		}
+	}
+	os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o600)
+
+	got, err := List(dir)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	want := []string{
+		filepath.Join(dir, "cmon-20260310-120000.db"),
+		filepath.Join(dir, "cmon-20260310-110000.db"),
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("kept: got %v, want %v", got, want)
+	}
+}
+
+func TestRunFailureLeavesNoSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Run(context.Background(), fileSource{err: errors.New("disk full")}, dir, 3, time.Now()); err == nil {
+		t.Fatal("Run should fail")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("failed backup left files behind: %v", entries)
+	}
+}
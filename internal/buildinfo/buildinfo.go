@@ -0,0 +1,93 @@
+// Package buildinfo reports which build of cmon is running, for /health,
+// the startup message and /version. Version, Commit and BuildTime are set
+// at build time, as the release workflow does:
+//
+//	go build -ldflags "-X cmon/internal/buildinfo.Version=v1.2.3 \
+//	    -X cmon/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	    -X cmon/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Without them a build from a git checkout still knows its commit, which
+// the Go toolchain records in the binary; Get falls back to that.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Set with -ldflags "-X"; see the package doc.
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = "" // RFC 3339, UTC
+)
+
+// Info describes the running build.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+	GoVersion string `json:"go_version"`
+	// Modified is set when the binary was built from a checkout with
+	// uncommitted changes, so Commit alone does not describe it.
+	Modified bool `json:"modified,omitempty"`
+}
+
+// Get returns the running build's info.
+func Get() Info {
+	bi, _ := debug.ReadBuildInfo() // nil when unavailable
+	return fromBuildInfo(bi)
+}
+
+// fromBuildInfo fills the fields -ldflags left unset from bi, the
+// toolchain's record of the build; bi may be nil.
+func fromBuildInfo(bi *debug.BuildInfo) Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	if bi == nil {
+		return info
+	}
+	if info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = s.Value
+			}
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+	return info
+}
+
+// ShortCommit is the first seven characters of Commit, "dirty"-suffixed
+// when Modified; empty when the commit is unknown.
+func (i Info) ShortCommit() string {
+	c := i.Commit
+	if len(c) > 7 {
+		c = c[:7]
+	}
+	if c != "" && i.Modified {
+		c += "-dirty"
+	}
+	return c
+}
+
+// String is a one-line summary such as "v1.2.3 (a1b2c3d, built
+// 2026-10-17T08:00:00Z)".
+func (i Info) String() string {
+	var extra []string
+	if c := i.ShortCommit(); c != "" {
+		extra = append(extra, c)
+	}
+	if i.BuildTime != "" {
+		extra = append(extra, "built "+i.BuildTime)
+	}
+	if len(extra) == 0 {
+		return i.Version
+	}
+	return i.Version + " (" + strings.Join(extra, ", ") + ")"
+}
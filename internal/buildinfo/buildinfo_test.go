@@ -0,0 +1,55 @@
+package buildinfo
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestFromBuildInfo(t *testing.T) {
+	t.Cleanup(func() { Version, Commit, BuildTime = "dev", "", "" })
+
+	vcs := &debug.BuildInfo{
+		Main: debug.Module{Version: "(devel)"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "0123456789abcdef"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+	got := fromBuildInfo(vcs)
+	if got.Version != "dev" || got.Commit != "0123456789abcdef" || !got.Modified || got.GoVersion == "" {
+		t.Errorf("toolchain fallback: %+v", got)
+	}
+	if s := got.String(); s != "dev (0123456-dirty)" {
+		t.Errorf("String() = %q", s)
+	}
+
+	Version, Commit, BuildTime = "v1.2.3", "fedcba9876543210", "2026-10-17T08:00:00Z"
+	got = fromBuildInfo(vcs)
+	if got.Version != "v1.2.3" || got.Commit != "fedcba9876543210" || got.BuildTime != "2026-10-17T08:00:00Z" {
+		t.Errorf("ldflags should win over the toolchain record: %+v", got)
+	}
+
+	Version, Commit, BuildTime = "dev", "", ""
+	got = fromBuildInfo(&debug.BuildInfo{Main: debug.Module{Version: "v1.4.0"}})
+	if got.Version != "v1.4.0" || got.String() != "v1.4.0" {
+		t.Errorf("go install version: %+v", got)
+	}
+	if got := fromBuildInfo(nil); got.Version != "dev" || got.Commit != "" {
+		t.Errorf("no build info: %+v", got)
+	}
+}
+
+func TestString(t *testing.T) {
+	for _, tc := range []struct {
+		info Info
+		want string
+	}{
+		{Info{Version: "v1.2.3", Commit: "a1b2c3d4e5", BuildTime: "2026-10-17T08:00:00Z"}, "v1.2.3 (a1b2c3d, built 2026-10-17T08:00:00Z)"},
+		{Info{Version: "v1.2.3", BuildTime: "2026-10-17T08:00:00Z"}, "v1.2.3 (built 2026-10-17T08:00:00Z)"},
+		{Info{Version: "dev"}, "dev"},
+	} {
+		if got := tc.info.String(); got != tc.want {
+			t.Errorf("%+v.String() = %q, want %q", tc.info, got, tc.want)
+		}
+	}
+}
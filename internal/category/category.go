@@ -0,0 +1,150 @@
+// Package category sorts complaints into types — no supply, transformer
+// failure, voltage, meter, billing — from their descriptions, so they can
+// be routed to the team that handles them and measured against that
+// type's regulatory target.
+//
+// Keyword rules decide first. A description no keyword matches can be
+// passed to an optional Fallback (Gemini); without one, or when it cannot
+// decide, the complaint is Other.
+package category
+
+import (
+	"context"
+	"log"
+	"strings"
+	"unicode"
+)
+
+// Category keys, as stored with complaints and used in
+// TELEGRAM_CATEGORY_ROUTES, SLA_TARGETS and exports.
+const (
+	Transformer = "transformer"
+	Line        = "line"
+	Voltage     = "voltage"
+	Meter       = "meter"
+	Billing     = "billing"
+	NoSupply    = "no_supply"
+	Other       = "other"
+)
+
+// Category is a complaint type.
+type Category struct {
+	Key   string
+	Label string
+	// keywords start a word of the description, so "transformer" also
+	// matches "transformers". Checked in the order of categories.
+	keywords []string
+}
+
+// categories are checked in order, specific faults before the general
+// "no supply" they usually also mention.
+var categories = []Category{
+	{Key: Transformer, Label: "Transformer failure",
+		keywords: []string{"transformer", "tc fail", "tc burn", "dtr"}},
+	{Key: Line, Label: "Line or cable fault",
+		keywords: []string{"wire", "line", "cable", "conductor", "pole", "jumper"}},
+	{Key: Voltage, Label: "Voltage problem",
+		keywords: []string{"voltage", "fluctuat"}},
+	{Key: Meter, Label: "Meter complaint",
+		keywords: []string{"meter"}},
+	{Key: Billing, Label: "Billing complaint",
+		keywords: []string{"bill", "payment"}},
+	{Key: NoSupply, Label: "No supply",
+		keywords: []string{"no supply", "no power", "no light", "no electricity", "supply off", "power cut", "power off", "fuse", "light gone", "light off", "lite nathi", "light nathi"}},
+	{Key: Other, Label: "Other"},
+}
+
+// All returns the categories in report order, Other last.
+func All() []Category {
+	return append([]Category(nil), categories...)
+}
+
+// Keys returns the category keys in report order.
+func Keys() []string {
+	keys := make([]string, len(categories))
+	for i, c := range categories {
+		keys[i] = c.Key
+	}
+	return keys
+}
+
+// Known reports whether key names a category.
+func Known(key string) bool {
+	for _, c := range categories {
+		if c.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Label returns the display name of key, or key itself when unknown.
+func Label(key string) string {
+	for _, c := range categories {
+		if c.Key == key {
+			return c.Label
+		}
+	}
+	return key
+}
+
+// Match returns the key of the first category whose keywords appear in
+// description, or "" when none do.
+func Match(description string) string {
+	padded := " " + strings.Join(strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+	for _, c := range categories {
+		for _, k := range c.keywords {
+			if strings.Contains(padded, " "+k) {
+				return c.Key
+			}
+		}
+	}
+	return ""
+}
+
+// Classifier assigns categories. A nil Classifier uses the keyword rules
+// alone.
+type Classifier struct {
+	// Fallback picks the category of a description no keyword matches,
+	// given the categories to choose from; it returns a category key, or
+	// "" when it cannot tell. Nil classifies such complaints as Other.
+	Fallback func(ctx context.Context, description string, categories []Category) (string, error)
+}
+
+// Classify returns the category key of description: by keywords, else by
+// the Fallback, else Other. A Fallback error or an unknown answer is
+// logged and counts as Other.
+func (c *Classifier) Classify(ctx context.Context, description string) string {
+	if key := Match(description); key != "" {
+		return key
+	}
+	if c == nil || c.Fallback == nil || strings.TrimSpace(description) == "" {
+		return Other
+	}
+	key, err := c.Fallback(ctx, description, All())
+	if err != nil {
+		log.Printf("⚠️  Category fallback failed: %v", err)
+		return Other
+	}
+	if key = strings.ToLower(strings.TrimSpace(key)); Known(key) {
+		return key
+	}
+	if key != "" {
+		log.Printf("⚠️  Category fallback answered unknown category %q", key)
+	}
+	return Other
+}
+
+// Of returns the stored category of a complaint, or the keyword category
+// of its description for complaints stored before categories were.
+func Of(stored, description string) string {
+	if stored != "" {
+		return stored
+	}
+	if key := Match(description); key != "" {
+		return key
+	}
+	return Other
+}
@@ -0,0 +1,73 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	for desc, want := range map[string]string{
+		"No supply since morning, transformer burnt": Transformer,
+		"Wire down near school":                      Line,
+		"LOW VOLTAGE in the evening":                 Voltage,
+		"Meter display blank":                        Meter,
+		"Wrong bill amount":                          Billing,
+		"no power":                                   NoSupply,
+		"Fuse gone":                                  NoSupply,
+		"LITE NATHI":                                 NoSupply,
+		"Street light request":                       "",
+		"Water pipeline burst":                       "",
+		"":                                           "",
+	} {
+		if got := Match(desc); got != want {
+			t.Errorf("Match(%q) = %q, want %q", desc, got, want)
+		}
+	}
+}
+
+func TestClassifyFallback(t *testing.T) {
+	var asked []string
+	c := &Classifier{Fallback: func(_ context.Context, desc string, cats []Category) (string, error) {
+		asked = append(asked, desc)
+		switch desc {
+		case "Dim bulbs all evening":
+			return " Voltage\n", nil
+		case "Sparks":
+			return "fire", nil
+		}
+		return "", errors.New("rate limited")
+	}}
+	ctx := context.Background()
+	for desc, want := range map[string]string{
+		"Fuse gone":             NoSupply, // keywords decide; no fallback
+		"Dim bulbs all evening": Voltage,
+		"Sparks":                Other, // unknown answer
+		"Something odd":         Other, // fallback error
+		"  ":                    Other, // nothing to ask about
+	} {
+		if got := c.Classify(ctx, desc); got != want {
+			t.Errorf("Classify(%q) = %q, want %q", desc, got, want)
+		}
+	}
+	if len(asked) != 3 {
+		t.Errorf("fallback asked about %q, want only the three unmatched descriptions", asked)
+	}
+
+	var none *Classifier
+	if got := none.Classify(ctx, "Something odd"); got != Other {
+		t.Errorf("nil Classifier = %q, want other", got)
+	}
+}
+
+func TestOf(t *testing.T) {
+	if got := Of(Billing, "no power"); got != Billing {
+		t.Errorf("Of kept %q, want the stored billing", got)
+	}
+	if got := Of("", "no power"); got != NoSupply {
+		t.Errorf("Of(\"\", no power) = %q, want no_supply", got)
+	}
+	if got := Of("", "odd"); got != Other {
+		t.Errorf("Of(\"\", odd) = %q, want other", got)
+	}
+}
@@ -0,0 +1,251 @@
+// Package charts renders trend charts from the complaint history: how many
+// complaints arrived each day, and how long resolved ones took. They are
+// drawn with gg in the summary images' fonts and theme, for the weekly
+// report and the /chart command.
+package charts
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"math"
+	"sort"
+	"time"
+
+	"cmon/internal/storage"
+	"cmon/internal/summary"
+
+	"github.com/fogleman/gg"
+)
+
+// Bar is one bar of a chart.
+type Bar struct {
+	Label string
+	Count int
+}
+
+// DailyVolume counts complaints first seen on each day from from up to,
+// not including, to's day, in from's location. Days with none get a zero
+// bar so gaps show.
+func DailyVolume(entries []storage.HistoryEntry, from, to time.Time) []Bar {
+	loc := from.Location()
+	first := startOfDay(from)
+	counts := make(map[string]int)
+	for _, e := range entries {
+		if e.FirstSeenAt.IsZero() {
+			continue
+		}
+		seen := e.FirstSeenAt.In(loc)
+		if seen.Before(first) || !seen.Before(to) {
+			continue
+		}
+		counts[seen.Format(time.DateOnly)]++
+	}
+
+	var bars []Bar
+	for day := first; day.Before(to); day = day.AddDate(0, 0, 1) {
+		bars = append(bars, Bar{Label: day.Format("02 Jan"), Count: counts[day.Format(time.DateOnly)]})
+	}
+	return bars
+}
+
+// resolutionBuckets are the resolution-time histogram's bars, each up to
+// (not including) upTo; the last catches everything longer.
+var resolutionBuckets = []struct {
+	label string
+	upTo  time.Duration
+}{
+	{"< 4h", 4 * time.Hour},
+	{"4–12h", 12 * time.Hour},
+	{"12–24h", 24 * time.Hour},
+	{"1–2d", 48 * time.Hour},
+	{"2–3d", 72 * time.Hour},
+	{"3–7d", 7 * 24 * time.Hour},
+	{"> 7d", 0},
+}
+
+// Resolution is the resolution-time distribution of complaints resolved in
+// a period.
+type Resolution struct {
+	Bars     []Bar
+	Resolved int
+	Median   time.Duration
+}
+
+// ResolutionTimes buckets how long each complaint resolved in [from, to)
+// stayed open, from first seen to resolved.
+func ResolutionTimes(entries []storage.HistoryEntry, from, to time.Time) Resolution {
+	r := Resolution{Bars: make([]Bar, len(resolutionBuckets))}
+	for i, b := range resolutionBuckets {
+		r.Bars[i].Label = b.label
+	}
+
+	var took []time.Duration
+	for _, e := range entries {
+		if e.ResolvedAt.IsZero() || e.FirstSeenAt.IsZero() || e.ResolvedAt.Before(from) || !e.ResolvedAt.Before(to) {
+			continue
+		}
+		d := max(e.ResolvedAt.Sub(e.FirstSeenAt), 0)
+		took = append(took, d)
+		for i, b := range resolutionBuckets {
+			if b.upTo == 0 || d < b.upTo {
+				r.Bars[i].Count++
+				break
+			}
+		}
+	}
+
+	r.Resolved = len(took)
+	if len(took) > 0 {
+		sort.Slice(took, func(i, j int) bool { return took[i] < took[j] })
+		r.Median = took[len(took)/2]
+		if len(took)%2 == 0 {
+			r.Median = (took[len(took)/2-1] + took[len(took)/2]) / 2
+		}
+	}
+	return r
+}
+
+// RenderVolume draws DailyVolume bars under title.
+func RenderVolume(title string, bars []Bar) ([]byte, error) {
+	total, peak := 0, Bar{}
+	for _, b := range bars {
+		total += b.Count
+		if b.Count > peak.Count {
+			peak = b
+		}
+	}
+	subtitle := fmt.Sprintf("%d complaints", total)
+	if peak.Count > 0 {
+		subtitle += fmt.Sprintf(" · busiest day %s (%d)", peak.Label, peak.Count)
+	}
+	return renderBars(title, subtitle, bars)
+}
+
+// RenderResolution draws a ResolutionTimes histogram under title.
+func RenderResolution(title string, r Resolution) ([]byte, error) {
+	subtitle := "No complaints resolved"
+	if r.Resolved > 0 {
+		subtitle = fmt.Sprintf("%d resolved · median %s", r.Resolved, FormatDuration(r.Median))
+	}
+	return renderBars(title, subtitle, r.Bars)
+}
+
+// FormatDuration renders d as "2d 5h", "5h 20m" or "20m".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	mins := int(d % time.Hour / time.Minute)
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case hours > 0 && mins > 0:
+		return fmt.Sprintf("%dh %dm", hours, mins)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dm", mins)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// Chart layout, in logical pixels; drawn at scale× for sharp text after
+// Telegram's recompression, like the summary images.
+const (
+	scale        = 2
+	chartWidth   = 1600
+	chartHeight  = 900
+	plotLeft     = 100
+	plotRight    = 60
+	plotTop      = 150
+	plotBottom   = 110
+	maxXLabels   = 12
+	maxBarLabels = 40
+)
+
+// renderBars draws a titled vertical bar chart with a light grid.
+func renderBars(title, subtitle string, bars []Bar) ([]byte, error) {
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("nothing to chart")
+	}
+	boldFont, err := summary.FontFile(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bold font: %w", err)
+	}
+	regularFont, err := summary.FontFile(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load regular font: %w", err)
+	}
+	theme := summary.CurrentTheme()
+
+	w, h := float64(chartWidth*scale), float64(chartHeight*scale)
+	dc := gg.NewContext(int(w), int(h))
+	dc.SetColor(theme.Background)
+	dc.Clear()
+
+	if err := dc.LoadFontFace(boldFont, 36*scale); err != nil {
+		return nil, err
+	}
+	dc.SetColor(theme.Title)
+	dc.DrawStringAnchored(title, w/2, 55*scale, 0.5, 0.5)
+	if err := dc.LoadFontFace(regularFont, 22*scale); err != nil {
+		return nil, err
+	}
+	dc.SetColor(theme.Footer)
+	dc.DrawStringAnchored(subtitle, w/2, 100*scale, 0.5, 0.5)
+
+	left, right := float64(plotLeft*scale), w-plotRight*scale
+	top, bottom := float64(plotTop*scale), h-plotBottom*scale
+
+	maxCount := 0
+	for _, b := range bars {
+		maxCount = max(maxCount, b.Count)
+	}
+	step := max(int(math.Ceil(float64(maxCount)/4)), 1)
+	yMax := float64(step * 4)
+
+	// Grid lines with their values
+	if err := dc.LoadFontFace(regularFont, 18*scale); err != nil {
+		return nil, err
+	}
+	dc.SetLineWidth(1 * scale)
+	for i := 0; i <= 4; i++ {
+		y := bottom - (bottom-top)*float64(i)/4
+		dc.SetColor(theme.Border)
+		dc.DrawLine(left, y, right, y)
+		dc.Stroke()
+		dc.SetColor(theme.Footer)
+		dc.DrawStringAnchored(fmt.Sprintf("%d", step*i), left-12*scale, y, 1, 0.5)
+	}
+
+	slot := (right - left) / float64(len(bars))
+	labelEvery := max(int(math.Ceil(float64(len(bars))/maxXLabels)), 1)
+	for i, b := range bars {
+		x := left + slot*float64(i)
+		barH := (bottom - top) * float64(b.Count) / yMax
+		if b.Count > 0 {
+			dc.SetColor(theme.HeaderBg)
+			dc.DrawRectangle(x+slot*0.15, bottom-barH, slot*0.7, barH)
+			dc.Fill()
+			if len(bars) <= maxBarLabels {
+				dc.SetColor(theme.Text)
+				dc.DrawStringAnchored(fmt.Sprintf("%d", b.Count), x+slot/2, bottom-barH-14*scale, 0.5, 0.5)
+			}
+		}
+		if i%labelEvery == 0 {
+			dc.SetColor(theme.Text)
+			dc.DrawStringAnchored(b.Label, x+slot/2, bottom+30*scale, 0.5, 0.5)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dc.Image()); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,75 @@
+package charts
+
+import (
+	"testing"
+	"time"
+
+	"cmon/internal/storage"
+)
+
+func TestDailyVolume(t *testing.T) {
+	from := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	entries := []storage.HistoryEntry{
+		{ComplaintID: "before", FirstSeenAt: from.Add(-time.Minute)},
+		{ComplaintID: "a", FirstSeenAt: from.Add(2 * time.Hour)},
+		{ComplaintID: "b", FirstSeenAt: from.Add(23 * time.Hour)},
+		{ComplaintID: "c", FirstSeenAt: to.Add(-time.Hour)},
+		{ComplaintID: "after", FirstSeenAt: to},
+	}
+
+	got := DailyVolume(entries, from, to)
+	want := []Bar{{"08 Mar", 2}, {"09 Mar", 0}, {"10 Mar", 1}}
+	if len(got) != len(want) {
+		t.Fatalf("DailyVolume = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("bar %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestResolutionTimes(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	resolved := func(id string, seen time.Time, took time.Duration) storage.HistoryEntry {
+		return storage.HistoryEntry{ComplaintID: id, FirstSeenAt: seen, ResolvedAt: seen.Add(took)}
+	}
+	entries := []storage.HistoryEntry{
+		resolved("fast", from, time.Hour),
+		resolved("day", from, 30*time.Hour),
+		resolved("slow", from, 10*24*time.Hour), // resolved after to
+		resolved("week", from.AddDate(0, 0, -8), 8*24*time.Hour),
+		{ComplaintID: "open", FirstSeenAt: from},
+	}
+
+	r := ResolutionTimes(entries, from, to)
+	if r.Resolved != 3 {
+		t.Fatalf("Resolved = %d, want 3", r.Resolved)
+	}
+	if r.Median != 30*time.Hour {
+		t.Errorf("Median = %v, want 30h", r.Median)
+	}
+	counts := map[string]int{}
+	for _, b := range r.Bars {
+		counts[b.Label] = b.Count
+	}
+	if counts["< 4h"] != 1 || counts["1–2d"] != 1 || counts["> 7d"] != 1 {
+		t.Errorf("bars = %v, want one each in < 4h, 1–2d and > 7d", r.Bars)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		20 * time.Minute:              "20m",
+		5 * time.Hour:                 "5h",
+		5*time.Hour + 20*time.Minute:  "5h 20m",
+		48 * time.Hour:                "2d",
+		53*time.Hour + 10*time.Minute: "2d 5h",
+	} {
+		if got := FormatDuration(d); got != want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package complaint
+
+import "sync"
+
+// Absences counts, per complaint, the consecutive complete fetches it has
+// been missing from the dashboard. One missed page or a row the portal
+// briefly hides must not resolve a live complaint, so resolution waits
+// until the count reaches a threshold.
+type Absences struct {
+	mu      sync.Mutex
+	missing map[string]int
+}
+
+// NewAbsences returns an empty tracker.
+func NewAbsences() *Absences {
+	return &Absences{missing: make(map[string]int)}
+}
+
+// Seen starts a complete fetch: every complaint in active is on the
+// dashboard again, so its count starts over.
+func (a *Absences) Seen(active []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, id := range active {
+		delete(a.missing, id)
+	}
+}
+
+// Miss records that complaintID was missing from the current fetch and
+// returns how many consecutive fetches it has now been missing from.
+func (a *Absences) Miss(complaintID string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.missing[complaintID]++
+	return a.missing[complaintID]
+}
+
+// Forget drops complaintID once it is resolved.
+func (a *Absences) Forget(complaintID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.missing, complaintID)
+}
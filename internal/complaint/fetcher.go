@@ -6,19 +6,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"cmon/internal/belt"
+	"cmon/internal/category"
 	"cmon/internal/config"
 	"cmon/internal/errors"
+	"cmon/internal/eventbus"
+	"cmon/internal/feeder"
+	"cmon/internal/filter"
+	"cmon/internal/flags"
+	"cmon/internal/geocode"
 	"cmon/internal/metrics"
+	"cmon/internal/notify"
+	"cmon/internal/outage"
+	"cmon/internal/quality"
 	"cmon/internal/session"
+	"cmon/internal/shard"
 	"cmon/internal/storage"
-	"cmon/internal/telegram"
-	"cmon/internal/translate"
-	"cmon/internal/whatsapp"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -29,25 +38,113 @@ import (
 //   - Main thread: Navigates pages and scrapes complaint links via HTTP + goquery
 //   - Worker pool: Processes complaints concurrently via HTTP API calls
 //   - Storage: Deduplicates and persists data
-//   - Telegram: Sends notifications
+//   - Bus: Publishes new complaints and outage alerts to every subscriber
 type Fetcher struct {
-	sc         *session.Client
-	storage    *storage.Storage
-	tg         *telegram.Client
-	wa         *whatsapp.Client
-	cfg        *config.Config
-	translator *translate.Translator
+	sc      *session.Client
+	storage *storage.Storage
+	bus     *eventbus.Bus
+	cfg     *config.Config
+	// translations receives each announced complaint for its Gujarati
+	// translation; nil leaves them English-only.
+	translations *TranslationQueue
+
+	// Outage groups new complaints by area across fetch cycles. Optional —
+	// nil disables clustering. Set after New by the daemon, which owns the
+	// detector so its window outlives individual Fetcher instances.
+	Outage *outage.Detector
+
+	// Feeders tags new complaints with their feeder and groups outage
+	// clusters by it. Optional — nil leaves complaints untagged.
+	Feeders *feeder.Map
+
+	// Categories classifies new complaints from their descriptions.
+	// Optional — nil uses the keyword rules alone.
+	Categories *category.Classifier
+
+	// Filter holds the ignore rules; complaints they match are stored but
+	// not announced. Optional — nil announces every complaint.
+	Filter *filter.Rules
+
+	// Geocoder enriches new complaints with coordinates and a Maps link.
+	// Optional — nil skips the step.
+	Geocoder *geocode.Geocoder
+
+	// Flags holds the runtime feature toggles. Optional — nil leaves every
+	// feature on.
+	Flags *flags.Flags
+
+	// Shard limits processing to the complaints this instance owns when
+	// several instances split one portal account. The zero value owns all.
+	Shard shard.Shard
+
+	// Importing stores new complaints without publishing them, for the
+	// initial import (see ImportDue); they are collected in Imported so
+	// the caller can report them as a whole.
+	Importing bool
+	Imported  []storage.Record
+
+	// DryRun only lists new complaints, in NewIDs, without fetching their
+	// details, saving or announcing them, and leaves dead letters alone;
+	// storage is read, never written (cmon fetch-once --diff).
+	DryRun bool
+	NewIDs []string
+
+	stats CycleStats
+
+	// batch buffers each cycle's new complaints so pages are saved in
+	// BATCH_SIZE transactions; pendingByConsumer counts the buffered ones
+	// per consumer, as they are not in history yet.
+	batch             *storage.Batcher
+	pendingByConsumer map[string]int
 }
 
-// New creates a new complaint fetcher.
-func New(sc *session.Client, storage *storage.Storage, tg *telegram.Client, wa *whatsapp.Client, cfg *config.Config, translator *translate.Translator) *Fetcher {
+// CycleStats counts the work done by one FetchAll, for the health
+// monitor's fetch-cycle history.
+type CycleStats struct {
+	Pages            int // dashboard pages scraped
+	NewComplaints    int // complaints saved as new
+	FailedComplaints int // complaints dead-lettered
+
+	Collected int  // distinct complaint IDs found across all pages
+	Reported  int  // total the dashboard reports; 0 when it does not say
+	Truncated bool // MAX_PAGES stopped pagination before the last page
+}
+
+// Incomplete reports whether the IDs FetchAll returned may miss active
+// complaints: pagination stopped at MAX_PAGES, or fewer IDs were collected
+// than the dashboard reports. The missing ones are unknown, not resolved,
+// so a caller must not mark complaints resolved from such a list.
+func (s CycleStats) Incomplete() bool {
+	return s.Truncated || s.Collected < s.Reported
+}
+
+// Gap describes an incomplete cycle for logs and health, or "".
+func (s CycleStats) Gap() string {
+	switch {
+	case !s.Incomplete():
+		return ""
+	case s.Truncated && s.Reported > 0:
+		return fmt.Sprintf("collected %d of %d complaints; MAX_PAGES stopped pagination after %d pages", s.Collected, s.Reported, s.Pages)
+	case s.Truncated:
+		return fmt.Sprintf("MAX_PAGES stopped pagination after %d pages (%d complaints collected)", s.Pages, s.Collected)
+	}
+	return fmt.Sprintf("collected %d of %d complaints the dashboard reports", s.Collected, s.Reported)
+}
+
+// Stats returns what the last FetchAll did, as far as it got.
+func (f *Fetcher) Stats() CycleStats {
+	return f.stats
+}
+
+// New creates a new complaint fetcher. New complaints and outage alerts are
+// published on bus; a nil bus drops them.
+func New(sc *session.Client, storage *storage.Storage, bus *eventbus.Bus, cfg *config.Config, translations *TranslationQueue) *Fetcher {
 	return &Fetcher{
-		sc:         sc,
-		storage:    storage,
-		tg:         tg,
-		wa:         wa,
-		cfg:        cfg,
-		translator: translator,
+		sc:           sc,
+		storage:      storage,
+		bus:          bus,
+		cfg:          cfg,
+		translations: translations,
 	}
 }
 
@@ -59,8 +156,19 @@ func New(sc *session.Client, storage *storage.Storage, tg *telegram.Client, wa *
 // Returns:
 //   - []string: List of all active complaint IDs found
 //   - error: Session expiry, navigation failure, or other critical errors
-func (f *Fetcher) FetchAll(baseURL string) ([]string, error) {
+func (f *Fetcher) FetchAll(baseURL string) (ids []string, err error) {
 	var allActiveComplaintIDs []string
+	f.stats = CycleStats{}
+
+	// New complaints are saved and announced in batches; whatever is still
+	// buffered goes out when the cycle ends, however it ends.
+	f.batch = f.storage.NewBatcher(f.cfg.BatchSize, f.cfg.FlushInterval)
+	f.pendingByConsumer = make(map[string]int)
+	defer func() {
+		if ferr := f.batch.Flush(); ferr != nil && err == nil {
+			ids, err = nil, fmt.Errorf("failed to save complaint records: %w", ferr)
+		}
+	}()
 
 	// Fetch first page
 	doc, err := f.sc.GetDoc(baseURL)
@@ -73,15 +181,25 @@ func (f *Fetcher) FetchAll(baseURL string) ([]string, error) {
 		return nil, errors.NewSessionExpiredError("dashboard navigation showed login form")
 	}
 
-	// Verify table is present
+	// Verify table is present. A missing table on a 200 page is usually the
+	// portal's maintenance/error page; classify it so the caller skips retries.
 	if doc.Find("#dataTable").Length() == 0 {
+		if sig := session.PortalErrorSignature(doc); sig != "" {
+			return nil, errors.NewPortalUnavailableError("dashboard served an error page", 200, sig)
+		}
 		return nil, errors.NewFetchError("dashboard loaded but #dataTable not found", nil)
 	}
 
+	f.stats.Reported, _ = reportedTotal(doc)
+	seen := make(map[string]bool)
+
 	currentPage := 1
+	pageURL := baseURL
+	byParam := false // doc was reached by incrementing the page parameter
 	for {
 		if currentPage > f.cfg.MaxPages {
 			slog.Warn("reached maximum page limit; stopping pagination", "max_pages", f.cfg.MaxPages)
+			f.stats.Truncated = true
 			break
 		}
 
@@ -89,13 +207,34 @@ func (f *Fetcher) FetchAll(baseURL string) ([]string, error) {
 		if err != nil {
 			return nil, errors.NewFetchError(fmt.Sprintf("failed to scrape page %d", currentPage), err)
 		}
+		fresh := 0
+		for _, id := range pageIDs {
+			if !seen[id] {
+				seen[id] = true
+				f.stats.Collected++
+				fresh++
+			}
+		}
+		if byParam && fresh == 0 {
+			// Past the last page: the portal served an empty page or
+			// the last one again.
+			break
+		}
+		if len(pageIDs) == 0 {
+			if err := checkEmptyPage(doc, currentPage); err != nil {
+				return nil, err
+			}
+		}
 		allActiveComplaintIDs = append(allActiveComplaintIDs, pageIDs...)
+		f.stats.Pages++
 
 		// Find next page URL from current document
-		nextURL := getNextPageURL(doc)
+		var nextURL string
+		nextURL, byParam = f.nextPageURL(doc, pageURL, len(seen))
 		if nextURL == "" {
 			break
 		}
+		pageURL = nextURL
 
 		doc, err = f.sc.GetDoc(nextURL)
 		if err != nil {
@@ -107,12 +246,26 @@ func (f *Fetcher) FetchAll(baseURL string) ([]string, error) {
 			return nil, errors.NewSessionExpiredError("session expired during pagination")
 		}
 		if doc.Find("#dataTable").Length() == 0 {
+			if sig := session.PortalErrorSignature(doc); sig != "" {
+				return nil, errors.NewPortalUnavailableError(fmt.Sprintf("page %d served an error page", currentPage+1), 200, sig)
+			}
 			return nil, errors.NewFetchError(fmt.Sprintf("page %d loaded but #dataTable not found", currentPage+1), nil)
 		}
 
 		currentPage++
 	}
 
+	if f.DryRun {
+		return allActiveComplaintIDs, nil
+	}
+
+	// Fetch failures that left the dashboard have nothing left to retry.
+	if n, err := f.storage.PruneDeadLetters(allActiveComplaintIDs); err != nil {
+		slog.Warn("failed to prune dead letters", "error", err)
+	} else if n > 0 {
+		slog.Info("dropped dead letters no longer on the dashboard", "count", n)
+	}
+
 	return allActiveComplaintIDs, nil
 }
 
@@ -136,11 +289,19 @@ func (f *Fetcher) scrapePage(doc *goquery.Document) ([]string, error) {
 		}
 		seenOnPage[complaint.ComplaintNumber] = true
 
-		if f.storage.IsNew(complaint.ComplaintNumber) {
+		// Complaints that failed earlier wait out their backoff.
+		// Buffered ones are not saved yet but are already handled.
+		if f.Shard.Owns(complaint.ComplaintNumber) && f.storage.IsNew(complaint.ComplaintNumber) && !f.batch.Buffered(complaint.ComplaintNumber) && f.storage.RetryDue(complaint.ComplaintNumber) {
 			newComplaints = append(newComplaints, complaint)
 		}
 	}
 
+	if f.DryRun {
+		for _, c := range newComplaints {
+			f.NewIDs = append(f.NewIDs, c.ComplaintNumber)
+		}
+		return allIDsOnPage, nil
+	}
 	if len(newComplaints) > 0 {
 		if err := f.processComplaintsConcurrently(newComplaints); err != nil {
 			return nil, err
@@ -153,11 +314,31 @@ func (f *Fetcher) scrapePage(doc *goquery.Document) ([]string, error) {
 // processComplaintsConcurrently processes complaints using a worker pool.
 func (f *Fetcher) processComplaintsConcurrently(complaints []Link) error {
 	apiIDMap := make(map[string]string)
+	linkMap := make(map[string]Link, len(complaints))
 	for _, c := range complaints {
 		apiIDMap[c.ComplaintNumber] = c.APIID
+		linkMap[c.ComplaintNumber] = c
+	}
+
+	// Phase 0: ignore rules, on what the dashboard row shows, so a
+	// filtered complaint costs no detail fetch.
+	var skipped []storage.Record
+	if f.Filter != nil {
+		kept := make([]Link, 0, len(complaints))
+		for _, c := range complaints {
+			details, _ := c.RowDetails()
+			if !f.skip(c.ComplaintNumber, c.APIID, details, false, &skipped) {
+				kept = append(kept, c)
+			}
+		}
+		if len(kept) == 0 {
+			return f.saveSkipped(skipped)
+		}
+		complaints = kept
 	}
 
-	pool := NewWorkerPool(f.sc, f.cfg.WorkerPoolSize, len(complaints))
+	seenAt := time.Now()
+	pool := NewWorkerPool(f.sc, f.cfg.WorkerPoolMin, f.cfg.WorkerPoolSize, len(complaints))
 
 	go func() {
 		for _, complaint := range complaints {
@@ -169,6 +350,24 @@ func (f *Fetcher) processComplaintsConcurrently(complaints []Link) error {
 	var results []ProcessResult
 	for result := range pool.Results() {
 		if result.Error != nil {
+			// The dashboard row still names the consumer and place: announce
+			// from it rather than not at all. The summary backfills the
+			// rest from the detail API later.
+			if details, ok := linkMap[result.ComplaintID].RowDetails(); ok {
+				slog.Warn("detail fetch failed; using the dashboard row", "complaint", result.ComplaintID, "error", result.Error)
+				name, _ := details.ComplainantName.(string)
+				if name == "" {
+					name = "Unknown"
+				}
+				results = append(results, ProcessResult{
+					ComplaintID:  result.ComplaintID,
+					ConsumerName: name,
+					Details:      details,
+					FromRow:      true,
+				})
+				continue
+			}
+			f.deadLetter(result.ComplaintID, apiIDMap[result.ComplaintID], result.Error)
 			continue
 		}
 		results = append(results, result)
@@ -181,19 +380,26 @@ func (f *Fetcher) processComplaintsConcurrently(complaints []Link) error {
 		return nil
 	}
 
-	safeStr := func(v interface{}) string {
-		if v == nil {
-			return ""
+	// The rules again, now on the details.
+	if f.Filter != nil {
+		kept := results[:0]
+		for _, res := range results {
+			if !f.skip(res.ComplaintID, apiIDMap[res.ComplaintID], res.Details, true, &skipped) {
+				kept = append(kept, res)
+			}
 		}
-		return fmt.Sprintf("%v", v)
+		results = kept
 	}
-
-	// Phase 2: Translate each complaint individually.
-	// BatchTranslateToGujarati takes exactly 3 texts [name, desc, addr] for ONE complaint.
-	type translationResult struct {
-		name, desc, addr string
+	if err := f.saveSkipped(skipped); err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return nil
 	}
-	translations := make([]translationResult, len(results))
+
+	// Phase 2: Enrich each complaint. The Gujarati translation comes later,
+	// from the translation queue, so it never holds up the announcement.
+	coords := make([]geocode.Point, len(results))
 
 	for i, res := range results {
 		match := belt.Resolve(
@@ -203,50 +409,51 @@ func (f *Fetcher) processComplaintsConcurrently(complaints []Link) error {
 		)
 		res.Details.Village = match.Village
 		res.Details.Belt = match.Belt
-		results[i].Details = res.Details
-
-		name := safeStr(res.Details.ComplainantName)
-		desc := safeStr(res.Details.Description)
-		loc := safeStr(res.Details.ExactLocation)
-		area := safeStr(res.Details.Area)
-		addr := fmt.Sprintf("%s, %s", loc, area)
-
-		if f.translator != nil {
-			texts := []string{name, desc, addr}
-			translateCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			out, err := f.translator.BatchTranslateToGujarati(translateCtx, texts)
-			cancel()
-			if err != nil {
-				translations[i] = translationResult{name, desc, addr}
-			} else {
-				translations[i] = translationResult{out[0], out[1], out[2]}
+		res.Details.Feeder = f.Feeders.Lookup(match.Village, safeStr(res.Details.Area), safeStr(res.Details.ExactLocation))
+		catCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		res.Details.Category = f.Categories.Classify(catCtx, safeStr(res.Details.Description))
+		cancel()
+		if f.Geocoder != nil {
+			query := geocode.Query(safeStr(res.Details.ExactLocation), safeStr(res.Details.Area), match.Village, "Gujarat")
+			geoCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			if p, ok := f.Geocoder.Lookup(geoCtx, query); ok {
+				coords[i] = p
 			}
-		} else {
-			translations[i] = translationResult{name, desc, addr}
+			cancel()
+			res.Details.MapsURL = geocode.MapsURL(coords[i], query)
 		}
-	}
-
-	type notification struct {
-		ComplaintID   string
-		ComplaintJSON string
-		GujaratiText  string
-		WAText        string
+		results[i].Details = res.Details
 	}
 
 	// Phase 3: Persist complaint records before any external side effects.
 	var recordsToSave []storage.Record
 	var notifications []notification
+	loc := f.cfg.Location()
+	monthStart := StartOfMonth(time.Now().In(loc))
 	for i, res := range results {
-		gujoName := translations[i].name
-		gujoDesc := translations[i].desc
-		gujoAddr := translations[i].addr
-
-		gujaratiText := ""
-		if gujoName != "" || gujoDesc != "" || gujoAddr != "" {
-			gujaratiText = fmt.Sprintf("👤 %s\n💬 %s\n📍 %s", gujoName, gujoDesc, gujoAddr)
+		if consumerNo := strings.TrimSpace(safeStr(res.Details.ConsumerNo)); consumerNo != "" {
+			prior, err := f.storage.CountConsumerComplaintsSince(consumerNo, monthStart, res.ComplaintID)
+			if err != nil {
+				slog.Warn("consumer history lookup failed", "complaint", res.ComplaintID, "error", err)
+			}
+			prior += f.pendingByConsumer[consumerNo]
+			f.pendingByConsumer[consumerNo]++
+			res.Details.RepeatNote = RepeatNote(prior + 1)
+		}
+		issues := quality.Check(quality.Input{
+			ConsumerNo:    safeStr(res.Details.ConsumerNo),
+			MobileNo:      safeStr(res.Details.MobileNo),
+			ExactLocation: safeStr(res.Details.ExactLocation),
+			Area:          safeStr(res.Details.Area),
+			Description:   safeStr(res.Details.Description),
+		})
+		res.Details.DataIssues = quality.Summary(issues)
+		if res.FromRow {
+			// The gaps are ours, not the consumer's: keep them out of the
+			// data-quality report and say why the message is short.
+			issues = nil
+			res.Details.DataIssues = rowOnlyNote
 		}
-
-		prettyJSON, _ := json.MarshalIndent(res.Details, "  ", "  ")
 
 		record := storage.Record{
 			ComplaintID:  res.ComplaintID,
@@ -254,103 +461,363 @@ func (f *Fetcher) processComplaintsConcurrently(complaints []Link) error {
 			ConsumerName: res.ConsumerName,
 			Village:      res.Details.Village,
 			Belt:         res.Details.Belt,
+			Category:     res.Details.Category,
 			ConsumerNo:   safeStr(res.Details.ConsumerNo),
 			MobileNo:     safeStr(res.Details.MobileNo),
 			Address:      safeStr(res.Details.ExactLocation),
 			Area:         safeStr(res.Details.Area),
 			Description:  safeStr(res.Details.Description),
 			ComplainDate: safeStr(res.Details.ComplainDate),
+			DataIssues:   quality.Encode(issues),
+			Latitude:     coords[i].Lat,
+			Longitude:    coords[i].Lon,
+		}
+		n := notification{
+			ComplaintID: res.ComplaintID,
+			Notify:      NotifyComplaint(res.Details, Translation{}),
+		}
+		n.Notify.SeenAt = seenAt
+		// Must run before SaveMultiple, which re-opens the history row.
+		if prev, ok, err := f.storage.GetResolvedHistory(res.ComplaintID); err != nil {
+			slog.Warn("resolution history lookup failed", "complaint", res.ComplaintID, "error", err)
+		} else if ok {
+			slog.Info("complaint re-opened", "complaint", res.ComplaintID, "resolved_at", prev.ResolvedAt)
+			metrics.ComplaintsReopenedTotal.Inc()
+			n.Notify.Reopened = &notify.Reopen{
+				ResolvedAt:        prev.ResolvedAt.In(loc),
+				TelegramMessageID: prev.TelegramMessageID,
+			}
+		}
+		if !f.Importing {
+			if payload, err := json.Marshal(n.Notify); err == nil {
+				record.Announce = string(payload)
+			}
 		}
 		recordsToSave = append(recordsToSave, record)
-		notifications = append(notifications, notification{
-			ComplaintID:   res.ComplaintID,
-			ComplaintJSON: string(prettyJSON),
-			GujaratiText:  gujaratiText,
-			WAText:        BuildWhatsAppMessage(res.Details, gujaratiText),
-		})
+		notifications = append(notifications, n)
 	}
 
 	// Complaint identity and metadata must be durable before we emit channel
-	// notifications, otherwise the DB can fall behind visible side effects.
-	if len(recordsToSave) > 0 {
-		if err := f.storage.SaveMultiple(recordsToSave); err != nil {
-			return fmt.Errorf("failed to save complaint records: %w", err)
-		}
-		metrics.ComplaintsSeenTotal.Add(uint64(len(recordsToSave)))
+	// notifications, otherwise the DB can fall behind visible side effects:
+	// the batch runs announce only after it has saved the records.
+	if len(recordsToSave) == 0 {
+		return nil
 	}
+	if err := f.batch.Add(recordsToSave, func() { f.announce(recordsToSave, notifications) }); err != nil {
+		return fmt.Errorf("failed to save complaint records: %w", err)
+	}
+	return nil
+}
 
-	// Phase 4: Telegram notifications + message ID persistence
-	if f.tg != nil {
-		for _, n := range notifications {
-			msgID, err := f.tg.SendComplaintMessage(n.ComplaintJSON, n.ComplaintID, n.GujaratiText)
-			if err != nil {
-				slog.Warn("failed to send Telegram complaint message", "complaint", n.ComplaintID, "error", err)
-				continue
-			}
-			if msgID == "" {
-				slog.Warn("Telegram sent complaint but returned no message ID", "complaint", n.ComplaintID)
+// announce runs once a page's new complaints are saved: it clears their
+// dead letters, feeds outage clustering and publishes them. Their
+// announcement journal entries go once the channels have had them (for a
+// complaint queued for translation, once the translation is delivered); a
+// crash before that leaves them for RecoverAnnouncements.
+func (f *Fetcher) announce(recordsToSave []storage.Record, notifications []notification) {
+	var queued map[string]bool // awaiting their translation
+	defer func() {
+		for _, r := range recordsToSave {
+			if r.Announce == "" || queued[r.ComplaintID] {
 				continue
 			}
-			if err := f.storage.SetMessageID(n.ComplaintID, msgID); err != nil {
-				slog.Warn("failed to persist Telegram message ID", "complaint", n.ComplaintID, "error", err)
+			if err := f.storage.JournalDoneFor(storage.JournalAnnounce, r.ComplaintID); err != nil {
+				slog.Warn("failed to clear announcement journal", "complaint", r.ComplaintID, "error", err)
 			}
 		}
+	}()
+	// Everything buffered is saved together, so none is pending any more.
+	clear(f.pendingByConsumer)
+	metrics.ComplaintsSeenTotal.Add(uint64(len(recordsToSave)))
+	f.stats.NewComplaints += len(recordsToSave)
+	for _, r := range recordsToSave {
+		if err := f.storage.ClearDeadLetter(r.ComplaintID); err != nil {
+			slog.Warn("failed to clear dead letter", "complaint", r.ComplaintID, "error", err)
+		}
+	}
+	if f.Importing {
+		f.Imported = append(f.Imported, recordsToSave...)
+		return
 	}
 
-	// Phase 5: WhatsApp Notifications
-	// Sends are intentionally sequential with a 1s gap between each one.
-	// whatsmeow prefetches encryption sessions from its internal SQLite DB when
-	// sending — firing multiple sends too quickly causes SQLITE_BUSY contention.
-	if f.wa != nil {
-		for i, n := range notifications {
-			if i > 0 {
-				time.Sleep(1 * time.Second)
+	// Phase 3b: Outage clustering. Alerts go out before the individual
+	// messages so the aggregated view lands first in the chat.
+	if f.Outage != nil {
+		obs := make([]outage.Observation, 0, len(recordsToSave))
+		for _, r := range recordsToSave {
+			area := r.Village
+			if area == "" {
+				area = r.Area
 			}
-
-			if err := f.wa.SendComplaintMessage(n.WAText, n.ComplaintID, f.storage); err != nil {
-				slog.Warn("failed to send WhatsApp complaint message", "complaint", n.ComplaintID, "error", err)
+			obs = append(obs, outage.Observation{
+				ComplaintID:  r.ComplaintID,
+				Area:         area,
+				Belt:         r.Belt,
+				Feeder:       f.Feeders.Lookup(r.Village, r.Area, r.Address),
+				ConsumerName: r.ConsumerName,
+				Description:  r.Description,
+			})
+		}
+		clusters := f.Outage.Observe(obs)
+		for _, c := range clusters.Alerts {
+			slog.Info("possible outage detected", "area", c.Area, "belt", c.Belt, "feeder", c.Feeder, "complaints", len(c.Members))
+			metrics.OutageAlertsTotal.Inc()
+			if err := f.bus.Publish(eventbus.AlertRaised{Alert: notify.Alert{
+				Kind:    notify.AlertOutage,
+				Title:   c.Title(),
+				Message: OutageDetails(c),
+				Outage:  &c,
+				Time:    time.Now(),
+			}}); err != nil {
+				slog.Warn("failed to send outage alert", "area", c.Area, "error", err)
+			}
+		}
+		if f.cfg.OutageSuppressIndividual && len(clusters.Clustered) > 0 {
+			kept := notifications[:0]
+			for _, n := range notifications {
+				if !clusters.Clustered[n.ComplaintID] {
+					kept = append(kept, n)
+				}
 			}
+			slog.Info("suppressed individual messages for clustered complaints", "count", len(notifications)-len(kept))
+			notifications = kept
+		}
+	}
+
+	// Phase 4: Publish. Subscribers (notification channels, WhatsApp) react;
+	// the Telegram channel persists message IDs itself.
+	// The batch goes out as one event so channels can bundle it.
+	if len(notifications) == 0 {
+		return
+	}
+	batch := make([]notify.Complaint, len(notifications))
+	for i, n := range notifications {
+		batch[i] = n.Notify
+	}
+	translations := f.translations
+	if !f.Flags.Enabled(flags.Translation) {
+		translations = nil
+	}
+	queued = publishNew(f.bus, translations, batch)
+}
+
+// publishNew announces batch as one ComplaintsNew event and, when
+// translations is set, queues each complaint for its Gujarati translation:
+// the batch goes out marked TranslationPending so channels that cannot
+// edit hold it back until then. Returns the complaints queued, whose
+// announcements stay journaled until their translation is delivered.
+func publishNew(bus *eventbus.Bus, translations *TranslationQueue, batch []notify.Complaint) map[string]bool {
+	if translations != nil {
+		for i := range batch {
+			batch[i].TranslationPending = true
 		}
 	}
+	if err := bus.Publish(eventbus.ComplaintsNew{Complaints: batch}); err != nil {
+		slog.Warn("failed to send complaint notifications", "complaints", len(batch), "error", err)
+	}
+	queued := make(map[string]bool)
+	for _, c := range batch {
+		queued[c.Number] = translations.Add(c)
+	}
+	return queued
+}
+
+// RecoverAnnouncements publishes the new complaints a crash or kill left
+// saved but unannounced as one ComplaintsNew batch, queued on translations
+// (nil when translation is off) like fresh ones. Channels that track what
+// they sent (Telegram, WhatsApp) skip a complaint that already reached
+// them, so only the missing messages go out. Call once at startup, after
+// the channels have subscribed to bus. Returns how many were published.
+func RecoverAnnouncements(stor *storage.Storage, bus *eventbus.Bus, translations *TranslationQueue) int {
+	var batch []notify.Complaint
+	_, err := stor.ReplayJournal(storage.JournalAnnounce, func(e storage.JournalEntry) error {
+		var c notify.Complaint
+		if err := json.Unmarshal([]byte(e.Payload), &c); err != nil {
+			slog.Warn("dropping unreadable announcement", "complaint", e.ComplaintID, "error", err)
+			return nil
+		}
+		slog.Info("announcing complaint interrupted by a restart", "complaint", e.ComplaintID, "saved_at", e.CreatedAt)
+		// The replayed entry goes; a new one stays until the complaint is
+		// announced (and translated), as for a fresh announcement.
+		if _, err := stor.JournalAppend(storage.JournalAnnounce, e.ComplaintID, e.Payload); err != nil {
+			slog.Warn("failed to journal recovered announcement", "complaint", e.ComplaintID, "error", err)
+		}
+		batch = append(batch, c)
+		return nil
+	})
+	if err != nil {
+		slog.Warn("failed to replay announcement journal", "error", err)
+	}
+	if len(batch) == 0 {
+		return 0
+	}
+	queued := publishNew(bus, translations, batch)
+	for _, c := range batch {
+		if queued[c.Number] {
+			continue
+		}
+		if err := stor.JournalDoneFor(storage.JournalAnnounce, c.Number); err != nil {
+			slog.Warn("failed to clear announcement journal", "complaint", c.Number, "error", err)
+		}
+	}
+	return len(batch)
+}
+
+// skip applies the ignore rules to a new complaint; final is set once d
+// holds the fetched details rather than the dashboard row. A filtered
+// complaint is added to skipped, to be stored without an announcement.
+func (f *Fetcher) skip(id, apiID string, d Details, final bool, skipped *[]storage.Record) bool {
+	match := belt.Resolve(safeStr(d.Area), safeStr(d.ExactLocation), safeStr(d.Description))
+	reason := f.Filter.Skip(filter.Complaint{
+		ConsumerNo:  safeStr(d.ConsumerNo),
+		Description: safeStr(d.Description),
+		Places:      []string{safeStr(d.Area), safeStr(d.ExactLocation), match.Village},
+	}, final)
+	if reason == "" {
+		return false
+	}
+	slog.Info("complaint filtered; not announcing", "complaint", id, "reason", reason)
+	metrics.ComplaintsFilteredTotal.Inc()
+	*skipped = append(*skipped, storage.Record{
+		ComplaintID:  id,
+		APIID:        apiID,
+		ConsumerName: safeStr(d.ComplainantName),
+		Village:      match.Village,
+		Belt:         match.Belt,
+		ConsumerNo:   safeStr(d.ConsumerNo),
+		MobileNo:     safeStr(d.MobileNo),
+		Address:      safeStr(d.ExactLocation),
+		Area:         safeStr(d.Area),
+		Description:  safeStr(d.Description),
+		ComplainDate: safeStr(d.ComplainDate),
+	})
+	return true
+}
 
+// saveSkipped stores filtered complaints, so they are not fetched again.
+func (f *Fetcher) saveSkipped(skipped []storage.Record) error {
+	if len(skipped) == 0 {
+		return nil
+	}
+	if err := f.batch.Add(skipped, nil); err != nil {
+		return fmt.Errorf("failed to save filtered complaints: %w", err)
+	}
 	return nil
 }
 
-// BuildWhatsAppMessage formats complaint details as plain text for WhatsApp.
-func BuildWhatsAppMessage(details Details, gujaratiText string) string {
+// safeStr renders a detail field, "" for a missing one.
+func safeStr(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// rowOnlyNote replaces the data-quality line of a complaint announced from
+// its dashboard row.
+const rowOnlyNote = "details unavailable from the portal; shown from the dashboard list"
+
+// notification is a new complaint waiting to be published.
+type notification struct {
+	ComplaintID string
+	Notify      notify.Complaint
+}
+
+// deadLetter records a complaint whose details could not be processed so
+// it is retried with backoff rather than on every cycle.
+func (f *Fetcher) deadLetter(complaintID, apiID string, cause error) {
+	f.stats.FailedComplaints++
+	dl, err := f.storage.RecordFailure(complaintID, apiID, storage.StageFetch, cause.Error(), "")
+	if err != nil {
+		slog.Warn("failed to record dead letter", "complaint", complaintID, "error", err)
+		return
+	}
+	metrics.DeadLettersTotal.Inc()
+	slog.Warn("complaint dead-lettered", "complaint", complaintID, "attempts", dl.Attempts, "next_retry", dl.NextRetryAt)
+}
+
+// OutageDetails is the plain-text body of an outage alert (belt, feeder
+// with its complaints per area, window and member complaints) without the
+// title line.
+func OutageDetails(c outage.Cluster) string {
+	var b strings.Builder
+	if c.Belt != "" {
+		fmt.Fprintf(&b, "%s Belt: %s\n", belt.StyleFor(c.Belt).Emoji, belt.DisplayName(c.Belt))
+	}
+	if c.Feeder != "" {
+		fmt.Fprintf(&b, "🔌 Feeder: %s (%s)\n", c.Feeder, c.Spread())
+	}
+	fmt.Fprintf(&b, "Within the last %s:\n", c.Window)
+	for _, m := range c.Members {
+		fmt.Fprintf(&b, "\n• %s", m.ComplaintID)
+		if m.ConsumerName != "" {
+			fmt.Fprintf(&b, " — %s", m.ConsumerName)
+		}
+	}
+	return b.String()
+}
+
+// RepeatNote returns the "🔁 3rd complaint this month from this consumer"
+// annotation for the nth complaint (counting the current one) from a
+// consumer this month, or "" for a first complaint.
+func RepeatNote(n int) string {
+	if n < 2 {
+		return ""
+	}
+	return fmt.Sprintf("🔁 %s complaint this month from this consumer", ordinal(n))
+}
+
+// ordinal formats n as "2nd", "3rd", "11th", "21st", ...
+func ordinal(n int) string {
+	suffix := "th"
+	switch n % 100 {
+	case 11, 12, 13:
+	default:
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+// StartOfMonth returns midnight on the first of t's month in t's
+// location; callers pass a time in the configured zone (TZ_OVERRIDE),
+// the one operators think in when they say "this month".
+func StartOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// NotifyComplaint converts enriched complaint details and their translation
+// into the channel-agnostic payload used by notify.Notifier implementations.
+func NotifyComplaint(details Details, tr Translation) notify.Complaint {
 	str := func(v interface{}) string {
 		if v == nil {
 			return ""
 		}
 		return fmt.Sprintf("%v", v)
 	}
-
-	msg := fmt.Sprintf(
-		"📋 Complaint: %s\n\n"+
-			"%s Belt: %s\n"+
-			"👤 %s\n"+
-			"📞 %s\n"+
-			"🆔 Consumer: %s\n"+
-			"📅 %s\n\n"+
-			"💬 Details:\n%s\n"+
-			"📍 %s, %s",
-		str(details.ComplainNo),
-		belt.StyleFor(details.Belt).Emoji,
-		belt.DisplayName(details.Belt),
-		str(details.ComplainantName),
-		str(details.MobileNo),
-		str(details.ConsumerNo),
-		str(details.ComplainDate),
-		str(details.Description),
-		str(details.ExactLocation),
-		str(details.Area),
-	)
-
-	if gujaratiText != "" {
-		msg += "\n\n" + strings.Repeat("─", 10) + "\n" + gujaratiText
-	}
-
-	return msg
+	return tr.apply(notify.Complaint{
+		Number:          str(details.ComplainNo),
+		Belt:            details.Belt,
+		Village:         details.Village,
+		Feeder:          details.Feeder,
+		Category:        details.Category,
+		ComplainantName: str(details.ComplainantName),
+		MobileNo:        str(details.MobileNo),
+		ConsumerNo:      str(details.ConsumerNo),
+		ComplainDate:    str(details.ComplainDate),
+		Description:     str(details.Description),
+		ExactLocation:   str(details.ExactLocation),
+		Area:            str(details.Area),
+		MapsURL:         details.MapsURL,
+		RepeatNote:      details.RepeatNote,
+		DataIssues:      details.DataIssues,
+	})
 }
 
 func displayBelt(name string) string {
@@ -362,6 +829,11 @@ var onclickRe = regexp.MustCompile(`openModelData\((\d+)\)`)
 
 // extractLinks extracts complaint number + API ID pairs from the #dataTable rows.
 func extractLinks(doc *goquery.Document) []Link {
+	var headers []string
+	doc.Find("#dataTable thead th").Each(func(_ int, th *goquery.Selection) {
+		headers = append(headers, strings.ToLower(strings.Join(strings.Fields(th.Text()), " ")))
+	})
+
 	var links []Link
 	doc.Find("#dataTable tbody tr").Each(func(_ int, row *goquery.Selection) {
 		anchor := row.Find(`a[onclick*="openModelData"]`)
@@ -374,14 +846,106 @@ func extractLinks(doc *goquery.Document) []Link {
 		if len(m) < 2 || complaintNumber == "" {
 			return
 		}
-		links = append(links, Link{
+		link := Link{
 			ComplaintNumber: complaintNumber,
 			APIID:           m[1],
-		})
+		}
+		if len(headers) > 0 {
+			link.Row = make(map[string]string, len(headers))
+			row.Find("td").Each(func(i int, td *goquery.Selection) {
+				if i < len(headers) && headers[i] != "" {
+					link.Row[headers[i]] = strings.Join(strings.Fields(td.Text()), " ")
+				}
+			})
+		}
+		links = append(links, link)
 	})
 	return links
 }
 
+// reportedTotalRe matches the table footer the portal prints under the
+// dashboard, e.g. "Showing 1 to 10 of 57 entries" or "Showing 10 of 57".
+var reportedTotalRe = regexp.MustCompile(`(?i)showing\s+[\d,]+\s+(?:to\s+[\d,]+\s+)?of\s+([\d,]+)`)
+
+// reportedTotal returns the complaint count the page reports, if it says.
+func reportedTotal(doc *goquery.Document) (int, bool) {
+	m := reportedTotalRe.FindStringSubmatch(doc.Find("body").Text())
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.ReplaceAll(m[1], ",", ""))
+	return n, err == nil
+}
+
+// checkEmptyPage tells an empty dashboard from one whose markup no longer
+// matches extractLinks: a page that yielded no links but reports complaints
+// is a ScrapeSchemaError carrying the page HTML. Without a count on the
+// page the two cannot be told apart and the page is taken as empty.
+func checkEmptyPage(doc *goquery.Document, page int) error {
+	total, ok := reportedTotal(doc)
+	if !ok || total == 0 {
+		return nil
+	}
+	html, err := doc.Html()
+	if err != nil {
+		html = ""
+	}
+	return errors.NewScrapeSchemaError("no complaint links in #dataTable", page, total, []byte(html))
+}
+
+// nextPageURL returns the page after doc, fetched from pageURL, by the
+// configured pagination strategy, and whether it was found by the page
+// parameter rather than a link. collected is how many complaints the
+// pages so far held; in auto mode the parameter is only tried while the
+// portal reports more, or has pagination but no count.
+func (f *Fetcher) nextPageURL(doc *goquery.Document, pageURL string, collected int) (string, bool) {
+	mode := f.cfg.Pagination
+	if mode != config.PaginationParam {
+		if next := getNextPageURL(doc); next != "" {
+			return next, false
+		}
+		if mode == config.PaginationLinks {
+			return "", false
+		}
+		if total, ok := reportedTotal(doc); ok {
+			if collected >= total {
+				return "", false
+			}
+		} else if doc.Find("ul.pagination").Length() == 0 {
+			return "", false
+		}
+	}
+	param := f.cfg.PageParam
+	if param == "" {
+		param = "page"
+	}
+	next, err := incrementPageParam(pageURL, param)
+	if err != nil {
+		slog.Warn("cannot paginate by URL parameter", "url", pageURL, "error", err)
+		return "", false
+	}
+	return next, true
+}
+
+// incrementPageParam returns rawURL with its param query value one higher;
+// a URL without it is page 1.
+func incrementPageParam(rawURL, param string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	page := 1
+	if v := q.Get(param); v != "" {
+		if page, err = strconv.Atoi(v); err != nil {
+			return "", fmt.Errorf("%s=%q is not a page number", param, v)
+		}
+	}
+	q.Set(param, strconv.Itoa(page+1))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
 // getNextPageURL finds the URL for the next page in pagination.
 //
 // Detection strategy:
@@ -1,16 +1,26 @@
 package complaint
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path"
 	"strings"
 	"testing"
+	"time"
 
+	"cmon/internal/api"
 	"cmon/internal/config"
+	"cmon/internal/errors"
+	"cmon/internal/eventbus"
+	"cmon/internal/filter"
+	"cmon/internal/notify"
 	"cmon/internal/session"
+	"cmon/internal/shard"
 	"cmon/internal/storage"
+	"cmon/internal/translate"
 )
 
 func withTempCWD(t *testing.T) {
@@ -82,7 +92,7 @@ func TestFetchAllFailsOnIncompletePagination(t *testing.T) {
 		t.Fatalf("new session client: %v", err)
 	}
 
-	fetcher := New(sc, stor, nil, nil, &config.Config{
+	fetcher := New(sc, stor, nil, &config.Config{
 		MaxPages:       5,
 		WorkerPoolSize: 1,
 	}, nil)
@@ -95,3 +105,571 @@ func TestFetchAllFailsOnIncompletePagination(t *testing.T) {
 		t.Fatalf("expected pagination error, got %v", err)
 	}
 }
+
+func TestRepeatNote(t *testing.T) {
+	cases := map[int]string{
+		0:  "",
+		1:  "",
+		2:  "🔁 2nd complaint this month from this consumer",
+		3:  "🔁 3rd complaint this month from this consumer",
+		4:  "🔁 4th complaint this month from this consumer",
+		11: "🔁 11th complaint this month from this consumer",
+		21: "🔁 21st complaint this month from this consumer",
+	}
+	for n, want := range cases {
+		if got := RepeatNote(n); got != want {
+			t.Errorf("RepeatNote(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestFetchAllSkipsComplaintsOwnedByOtherShards(t *testing.T) {
+	withTempCWD(t)
+
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = stor.Close()
+	})
+
+	// Pick a slot that owns neither complaint on the page.
+	other := shard.Shard{Count: 10}
+	for other.Owns("CMP-1") || other.Owns("CMP-2") {
+		other.Index++
+	}
+
+	detailHits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/page1" {
+			detailHits++
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `
+			<table id="dataTable">
+				<tbody>
+					<tr><td><a onclick="openModelData(1)">CMP-1</a></td></tr>
+					<tr><td><a onclick="openModelData(2)">CMP-2</a></td></tr>
+				</tbody>
+			</table>
+		`)
+	}))
+	defer server.Close()
+
+	sc, err := session.New(1000, 1000, 0)
+	if err != nil {
+		t.Fatalf("new session client: %v", err)
+	}
+	fetcher := New(sc, stor, nil, &config.Config{MaxPages: 5, WorkerPoolSize: 1}, nil)
+	fetcher.Shard = other
+
+	ids, err := fetcher.FetchAll(server.URL + "/page1")
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if len(ids) != 2 {
+		t.Errorf("active IDs = %v; every page ID must be reported for resolution tracking", ids)
+	}
+	if detailHits != 0 {
+		t.Errorf("fetched details %d times for complaints owned by other shards", detailHits)
+	}
+	if !stor.IsNew("CMP-1") || !stor.IsNew("CMP-2") {
+		t.Error("complaints owned by other shards should not be stored")
+	}
+}
+
+func TestFetchAllReportsUnrecognisedMarkup(t *testing.T) {
+	withTempCWD(t)
+
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = stor.Close()
+	})
+
+	var page string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, page)
+	}))
+	defer server.Close()
+
+	sc, err := session.New(1000, 1000, 0)
+	if err != nil {
+		t.Fatalf("new session client: %v", err)
+	}
+	fetcher := New(sc, stor, nil, &config.Config{MaxPages: 5, WorkerPoolSize: 1}, nil)
+
+	// The portal renamed the link handler: rows are there, links are not.
+	page = `
+		<table id="dataTable">
+			<tbody>
+				<tr><td><a onclick="showComplaint(1)">CMP-1</a></td></tr>
+			</tbody>
+		</table>
+		<div class="dataTables_info">Showing 1 to 10 of 1,234 entries</div>
+	`
+	_, err = fetcher.FetchAll(server.URL)
+	serr := errors.AsScrapeSchema(err)
+	if serr == nil {
+		t.Fatalf("FetchAll error = %v, want ScrapeSchemaError", err)
+	}
+	if serr.Page != 1 || serr.Reported != 1234 || !strings.Contains(string(serr.HTML), "showComplaint(1)") {
+		t.Errorf("ScrapeSchemaError = page %d, reported %d, html %q", serr.Page, serr.Reported, serr.HTML)
+	}
+
+	// A genuinely empty dashboard is not an error.
+	for _, footer := range []string{"Showing 0 to 0 of 0 entries", ""} {
+		page = `<table id="dataTable"><tbody><tr><td colspan="5">No data available in table</td></tr></tbody></table>` + footer
+		if ids, err := fetcher.FetchAll(server.URL); err != nil || len(ids) != 0 {
+			t.Errorf("empty dashboard (%q): ids %v, err %v", footer, ids, err)
+		}
+	}
+}
+
+func TestFetchAllFlagsIncompleteList(t *testing.T) {
+	withTempCWD(t)
+
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = stor.Close()
+	})
+
+	// Two pages of one complaint each; the footer claims three.
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			http.NotFound(w, r)
+			return
+		}
+		next := `<li class="page-item disabled"><a class="page-link">›</a></li>`
+		id := "2"
+		if r.URL.Query().Get("page") == "" {
+			next = fmt.Sprintf(`<li class="page-item"><a class="page-link" href="%s/?page=2" rel="next">›</a></li>`, serverURL)
+			id = "1"
+		}
+		fmt.Fprintf(w, `<table id="dataTable"><tbody>
+			<tr><td><a onclick="openModelData(%s)">CMP-%s</a></td></tr>
+			</tbody></table>
+			<div class="dataTables_info">Showing 1 to 1 of 3 entries</div>
+			<ul class="pagination">%s</ul>`, id, id, next)
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	sc, err := session.New(1000, 1000, 0)
+	if err != nil {
+		t.Fatalf("new session client: %v", err)
+	}
+	// Owning neither complaint keeps detail fetches out of the test.
+	other := shard.Shard{Count: 10}
+	for other.Owns("CMP-1") || other.Owns("CMP-2") {
+		other.Index++
+	}
+	cfg := &config.Config{MaxPages: 1, WorkerPoolSize: 1}
+	fetcher := New(sc, stor, nil, cfg, nil)
+	fetcher.Shard = other
+
+	if _, err := fetcher.FetchAll(server.URL + "/"); err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	stats := fetcher.Stats()
+	if !stats.Truncated || stats.Collected != 1 || stats.Reported != 3 || !stats.Incomplete() {
+		t.Errorf("MAX_PAGES=1 stats = %+v, want truncated 1 of 3", stats)
+	}
+	if gap := stats.Gap(); !strings.Contains(gap, "1 of 3") || !strings.Contains(gap, "MAX_PAGES") {
+		t.Errorf("Gap() = %q", gap)
+	}
+
+	cfg.MaxPages = 5
+	fetcher = New(sc, stor, nil, cfg, nil)
+	fetcher.Shard = other
+	if _, err := fetcher.FetchAll(server.URL + "/"); err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	stats = fetcher.Stats()
+	if stats.Truncated || stats.Collected != 2 || !stats.Incomplete() || stats.Gap() != "collected 2 of 3 complaints the dashboard reports" {
+		t.Errorf("short list stats = %+v, gap %q", stats, stats.Gap())
+	}
+}
+
+func TestFetchAllPaginatesByURLParameter(t *testing.T) {
+	withTempCWD(t)
+
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = stor.Close()
+	})
+
+	// Three pages whose pagination is labelled in Gujarati, so no link
+	// reads as "Next"; page 4 is empty.
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Query().Get("page"))
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+		}
+		rows := ""
+		if page <= "3" {
+			rows = fmt.Sprintf(`<tr><td><a onclick="openModelData(%s)">CMP-%s</a></td></tr>`, page, page)
+		}
+		fmt.Fprintf(w, `<table id="dataTable"><tbody>%s</tbody></table>
+			<ul class="pagination"><li class="page-item"><a class="page-link" href="?page=2">આગળ</a></li></ul>`, rows)
+	}))
+	defer server.Close()
+
+	sc, err := session.New(1000, 1000, 0)
+	if err != nil {
+		t.Fatalf("new session client: %v", err)
+	}
+	for _, mode := range []string{config.PaginationAuto, config.PaginationParam} {
+		requested = nil
+		fetcher := New(sc, stor, nil, &config.Config{MaxPages: 10, WorkerPoolSize: 1, Pagination: mode}, nil)
+		fetcher.DryRun = true
+		ids, err := fetcher.FetchAll(server.URL + "/dashboard?cStatus=2")
+		if err != nil {
+			t.Fatalf("%s: FetchAll: %v", mode, err)
+		}
+		if strings.Join(ids, ",") != "CMP-1,CMP-2,CMP-3" || fetcher.Stats().Pages != 3 {
+			t.Errorf("%s: ids = %v over %d pages, want CMP-1..3 over 3", mode, ids, fetcher.Stats().Pages)
+		}
+		if strings.Join(requested, ",") != ",2,3,4" {
+			t.Errorf("%s: requested pages %q", mode, requested)
+		}
+	}
+
+	// Links only: the Gujarati label is not followed.
+	fetcher := New(sc, stor, nil, &config.Config{MaxPages: 10, WorkerPoolSize: 1, Pagination: config.PaginationLinks}, nil)
+	fetcher.DryRun = true
+	if ids, err := fetcher.FetchAll(server.URL + "/dashboard"); err != nil || len(ids) != 1 {
+		t.Errorf("links: ids = %v, %v; want the first page only", ids, err)
+	}
+}
+
+func TestFetchAllAnnouncesFromRowWhenDetailsFail(t *testing.T) {
+	withTempCWD(t)
+
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = stor.Close()
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/page1" {
+			http.NotFound(w, r) // the detail API is down
+			return
+		}
+		fmt.Fprint(w, `<table id="dataTable">
+			<thead><tr><th>Complaint No</th><th>Consumer No</th><th>Consumer Name</th><th>Mobile No</th><th>Area</th><th>Complain Date</th><th>Status</th></tr></thead>
+			<tbody>
+				<tr><td><a onclick="openModelData(7)">CMP-7</a></td><td>1234</td><td> Asha  Patel </td><td>9876543210</td><td>Limdi</td><td>01/03/2026 10:15</td><td>Pending</td></tr>
+			</tbody>
+		</table>`)
+	}))
+	defer server.Close()
+	api.SetRecordEndpoint(server.URL + "/api/complaint-record/")
+	t.Cleanup(func() { api.SetRecordEndpoint(api.DefaultRecordEndpoint) })
+
+	sc, err := session.New(1000, 1000, 0)
+	if err != nil {
+		t.Fatalf("new session client: %v", err)
+	}
+	bus := eventbus.New()
+	var announced []notify.Complaint
+	bus.Subscribe("test", func(e eventbus.Event) error {
+		if e, ok := e.(eventbus.ComplaintsNew); ok {
+			announced = append(announced, e.Complaints...)
+		}
+		return nil
+	})
+	fetcher := New(sc, stor, bus, &config.Config{MaxPages: 5, WorkerPoolSize: 1}, nil)
+	if _, err := fetcher.FetchAll(server.URL + "/page1"); err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+
+	if stor.GetConsumerName("CMP-7") != "Asha Patel" || stor.GetArea("CMP-7") != "Limdi" || stor.GetComplainDate("CMP-7") != "01/03/2026 10:15" {
+		t.Errorf("stored name %q, area %q, date %q; want the row's", stor.GetConsumerName("CMP-7"), stor.GetArea("CMP-7"), stor.GetComplainDate("CMP-7"))
+	}
+	if len(announced) != 1 || announced[0].ComplainantName != "Asha Patel" || announced[0].MobileNo != "9876543210" || announced[0].DataIssues != rowOnlyNote {
+		t.Errorf("announced %+v, want CMP-7 from its row", announced)
+	}
+	if dls, _ := stor.GetDeadLetters(); len(dls) != 0 {
+		t.Errorf("dead letters = %+v, want none for a complaint announced from its row", dls)
+	}
+}
+
+func TestFetchAllStoresFilteredComplaintsQuietly(t *testing.T) {
+	withTempCWD(t)
+
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = stor.Close()
+	})
+
+	var detailHits []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/page1" {
+			detailHits = append(detailHits, path.Base(r.URL.Path))
+			fmt.Fprintf(w, `{"complaintdetail": {"complain_no": "CMP-%s", "consumer_no": "55%s", "description": "test entry, please ignore", "area": "Limdi"}}`, path.Base(r.URL.Path), path.Base(r.URL.Path))
+			return
+		}
+		fmt.Fprint(w, `<table id="dataTable">
+			<thead><tr><th>Complaint No</th><th>Consumer No</th><th>Area</th></tr></thead>
+			<tbody>
+				<tr><td><a onclick="openModelData(1)">CMP-1</a></td><td>TEST-1</td><td>Limdi</td></tr>
+				<tr><td><a onclick="openModelData(2)">CMP-2</a></td><td>552</td><td>Limdi</td></tr>
+			</tbody>
+		</table>`)
+	}))
+	defer server.Close()
+	api.SetRecordEndpoint(server.URL + "/api/complaint-record/")
+	t.Cleanup(func() { api.SetRecordEndpoint(api.DefaultRecordEndpoint) })
+
+	rules, err := filter.New([]string{"TEST-1"}, "please ignore", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc, err := session.New(1000, 1000, 0)
+	if err != nil {
+		t.Fatalf("new session client: %v", err)
+	}
+	bus := eventbus.New()
+	announced := 0
+	bus.Subscribe("test", func(e eventbus.Event) error {
+		if e, ok := e.(eventbus.ComplaintsNew); ok {
+			announced += len(e.Complaints)
+		}
+		return nil
+	})
+	fetcher := New(sc, stor, bus, &config.Config{MaxPages: 5, WorkerPoolSize: 1}, nil)
+	fetcher.Filter = rules
+	if _, err := fetcher.FetchAll(server.URL + "/page1"); err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+
+	// The test account is filtered on its row, the test description once
+	// its details are in; both are stored so they are not fetched again.
+	if len(detailHits) != 1 || detailHits[0] != "2" {
+		t.Errorf("detail fetches = %v, want only CMP-2's", detailHits)
+	}
+	if announced != 0 {
+		t.Errorf("announced %d complaints, want none", announced)
+	}
+	if stor.IsNew("CMP-1") || stor.IsNew("CMP-2") {
+		t.Error("filtered complaints should still be stored")
+	}
+}
+
+func TestNotifyComplaintCarriesTranslation(t *testing.T) {
+	details := Details{ComplainNo: "123", ComplainantName: "RAMESH", Description: "LITE NATHI"}
+
+	c := NotifyComplaint(details, Translation{Name: "રમેશ", Description: "લાઇટ નથી"})
+	if c.NameGu != "રમેશ" || c.DescriptionGu != "લાઇટ નથી" || c.AddressGu != "" {
+		t.Errorf("Gujarati fields = %q / %q / %q", c.NameGu, c.DescriptionGu, c.AddressGu)
+	}
+	if c.Translation != "👤 રમેશ\n💬 લાઇટ નથી" {
+		t.Errorf("Translation block = %q", c.Translation)
+	}
+
+	// A skipped translation sends English only, with the reason.
+	c = NotifyComplaint(details, Translation{Note: "Gujarati translation skipped (rate limited)"})
+	if c.Translation != "" || c.NameGu != "" || c.TranslationNote == "" {
+		t.Errorf("skipped translation = %+v", c)
+	}
+}
+
+func TestInitialImportStoresQuietly(t *testing.T) {
+	withTempCWD(t)
+
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = stor.Close()
+	})
+
+	if ImportDue(stor, "off") {
+		t.Error("INITIAL_IMPORT=off should never import quietly")
+	}
+	if !ImportDue(stor, "digest") {
+		t.Fatal("an empty database should import quietly")
+	}
+
+	bus := eventbus.New()
+	published := 0
+	bus.Subscribe("test", func(eventbus.Event) error {
+		published++
+		return nil
+	})
+	fetcher := New(nil, stor, bus, &config.Config{}, nil)
+	fetcher.Importing = true
+	records := []storage.Record{
+		{ComplaintID: "CMP-1", Village: "Valod"},
+		{ComplaintID: "CMP-2", Village: "Valod"},
+		{ComplaintID: "CMP-3", Area: "Bardoli"},
+	}
+	if err := stor.SaveMultiple(records); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	fetcher.announce(records, []notification{{ComplaintID: "CMP-1"}, {ComplaintID: "CMP-2"}, {ComplaintID: "CMP-3"}})
+	if published != 0 || len(fetcher.Imported) != 3 {
+		t.Errorf("import published %d events and collected %d complaints, want 0 and 3", published, len(fetcher.Imported))
+	}
+
+	digest := ImportDigest(fetcher.Imported)
+	if digest.Kind != notify.AlertImported || !strings.HasPrefix(digest.Message, "3 complaints") ||
+		!strings.Contains(digest.Message, "• Valod: 2\n• Bardoli: 1") {
+		t.Errorf("digest = %+v", digest)
+	}
+
+	if err := MarkImported(stor); err != nil {
+		t.Fatalf("MarkImported: %v", err)
+	}
+	if ImportDue(stor, "digest") {
+		t.Error("import should not repeat once done")
+	}
+}
+
+func TestInitialImportSkipsExistingDatabase(t *testing.T) {
+	withTempCWD(t)
+
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = stor.Close()
+	})
+	if err := stor.SaveMultiple([]storage.Record{{ComplaintID: "CMP-1"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	if ImportDue(stor, "silent") {
+		t.Error("a database upgraded with complaints in it should not import quietly")
+	}
+	if err := stor.Remove("CMP-1"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if ImportDue(stor, "silent") {
+		t.Error("emptying the queue later should not trigger an import")
+	}
+}
+
+func TestTranslationQueue(t *testing.T) {
+	cooldown := translateCooldown
+	translateCooldown = time.Millisecond
+	t.Cleanup(func() { translateCooldown = cooldown })
+
+	// Gemini answers 429 once, then translates; CMP-2 stays rate limited.
+	calls := map[string]int{}
+	batch := func(_ context.Context, texts []string) ([]string, error) {
+		calls[texts[0]]++
+		if texts[0] == "Suresh" || calls[texts[0]] == 1 {
+			return nil, translate.ErrRateLimited
+		}
+		return []string{"રમેશ", "લાઇટ નથી", texts[2]}, nil
+	}
+	done := make(chan notify.Complaint, 2)
+	q := newTranslationQueue(batch, 6000, func(c notify.Complaint) { done <- c })
+	t.Cleanup(q.Stop)
+
+	q.Add(notify.Complaint{Number: "CMP-1", ComplainantName: "Ramesh", Description: "LITE NATHI", ExactLocation: "12", Area: "Vapi"})
+	q.Add(notify.Complaint{Number: "CMP-2", ComplainantName: "Suresh"})
+
+	got := <-done
+	if got.Number != "CMP-1" || got.NameGu != "રમેશ" || got.DescriptionGu != "લાઇટ નથી" || got.AddressGu != "" || got.TranslationNote != "" {
+		t.Errorf("translated = %+v; want the Gujarati name and description, the address unchanged", got)
+	}
+	if !strings.Contains(got.Translation, "👤 રમેશ") || got.Description != "LITE NATHI" {
+		t.Errorf("translated = %+v; want the block added to the English fields", got)
+	}
+	got = <-done
+	if got.Number != "CMP-2" || got.NameGu != "" || got.TranslationNote != "Gujarati translation skipped (rate limited)" {
+		t.Errorf("rate limited = %+v; want only the note", got)
+	}
+	if calls["Ramesh"] != 2 || calls["Suresh"] != translateAttempts {
+		t.Errorf("calls = %v; want CMP-1 retried once and CMP-2 %d times", calls, translateAttempts)
+	}
+
+	var nilQueue *TranslationQueue
+	if nilQueue.Add(notify.Complaint{Number: "CMP-3"}) {
+		t.Error("a nil queue (translation off) should accept nothing")
+	}
+}
+
+func TestRecoverAnnouncementsPublishesOneBatch(t *testing.T) {
+	withTempCWD(t)
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]storage.Record{
+		{ComplaintID: "CMP-1", Announce: `{"complain_no":"CMP-1","consumer_name":"Ramesh"}`},
+		{ComplaintID: "CMP-2", Announce: `{"complain_no":"CMP-2","consumer_name":"Suresh"}`},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	journaled := func() (ids []string) {
+		stor.ReplayJournal(storage.JournalAnnounce, func(e storage.JournalEntry) error {
+			ids = append(ids, e.ComplaintID)
+			return fmt.Errorf("keep %s", e.ComplaintID)
+		})
+		return ids
+	}
+
+	bus := eventbus.New()
+	var events []eventbus.Event
+	bus.Subscribe("test", func(e eventbus.Event) error {
+		events = append(events, e)
+		return nil
+	})
+	release := make(chan struct{})
+	done := make(chan notify.Complaint, 2)
+	q := newTranslationQueue(func(_ context.Context, texts []string) ([]string, error) {
+		<-release
+		return []string{"ગુ", "ગુ", texts[2]}, nil
+	}, 6000, func(c notify.Complaint) {
+		stor.JournalDoneFor(storage.JournalAnnounce, c.Number)
+		done <- c
+	})
+	t.Cleanup(q.Stop)
+
+	if n := RecoverAnnouncements(stor, bus, q); n != 2 {
+		t.Fatalf("recovered %d, want 2", n)
+	}
+	if len(events) != 1 {
+		t.Fatalf("published %d events, want one batch", len(events))
+	}
+	batch, ok := events[0].(eventbus.ComplaintsNew)
+	if !ok || len(batch.Complaints) != 2 || batch.Complaints[0].Number != "CMP-1" || !batch.Complaints[1].TranslationPending {
+		t.Fatalf("published %+v; want CMP-1 and CMP-2 as one batch awaiting translation", events[0])
+	}
+	if got := strings.Join(journaled(), ","); got != "CMP-1,CMP-2" {
+		t.Errorf("journal = %s; want both kept until translated", got)
+	}
+
+	close(release)
+	<-done
+	<-done
+	if got := journaled(); len(got) != 0 {
+		t.Errorf("journal = %v after the translations; want it empty", got)
+	}
+}
@@ -0,0 +1,77 @@
+package complaint
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"cmon/internal/notify"
+	"cmon/internal/storage"
+)
+
+// importDoneKey is the setting stamped once the initial import is done, so
+// only a new or wiped database imports quietly.
+const importDoneKey = "initial_import_done"
+
+// ImportDue reports whether the next fetch is the initial import: mode
+// (INITIAL_IMPORT) is "silent" or "digest" and the database has never
+// held complaints. A database found holding some is marked done whatever
+// the mode, so turning the setting on later, or an empty queue, never
+// silences new complaints.
+func ImportDue(stor *storage.Storage, mode string) bool {
+	if _, done := stor.GetSetting(importDoneKey); done {
+		return false
+	}
+	if len(stor.GetAllSeenComplaints()) > 0 {
+		if err := MarkImported(stor); err != nil {
+			slog.Warn("failed to record the initial import", "error", err)
+		}
+		return false
+	}
+	return mode == "silent" || mode == "digest"
+}
+
+// MarkImported records that the initial import is done.
+func MarkImported(stor *storage.Storage) error {
+	return stor.SetSetting(importDoneKey, time.Now().UTC().Format(time.RFC3339))
+}
+
+// ImportDigest is the alert standing in for the complaints the initial
+// import stored without a message each: how many, per village.
+func ImportDigest(records []storage.Record) notify.Alert {
+	counts := make(map[string]int)
+	for _, r := range records {
+		place := r.Village
+		if place == "" {
+			place = r.Area
+		}
+		if place == "" {
+			place = "Unknown village"
+		}
+		counts[place]++
+	}
+	places := make([]string, 0, len(counts))
+	for p := range counts {
+		places = append(places, p)
+	}
+	sort.Slice(places, func(i, j int) bool {
+		if counts[places[i]] != counts[places[j]] {
+			return counts[places[i]] > counts[places[j]]
+		}
+		return places[i] < places[j]
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d complaints already on the dashboard were stored without a message each; the next summary lists them.\n", len(records))
+	for _, p := range places {
+		fmt.Fprintf(&b, "\n• %s: %d", p, counts[p])
+	}
+	return notify.Alert{
+		Kind:    notify.AlertImported,
+		Title:   "Initial import",
+		Message: b.String(),
+		Time:    time.Now(),
+	}
+}
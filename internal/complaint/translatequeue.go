@@ -0,0 +1,156 @@
+package complaint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"cmon/internal/metrics"
+	"cmon/internal/notify"
+	"cmon/internal/translate"
+)
+
+// translateTimeout bounds one complaint's Gemini call.
+const translateTimeout = 30 * time.Second
+
+// translationQueueSize bounds the complaints waiting for a translation;
+// beyond it new ones stay English-only.
+const translationQueueSize = 256
+
+// translateAttempts is how often a rate-limited complaint is tried before
+// it is left English-only.
+const translateAttempts = 3
+
+// translateCooldown is how long the queue waits after Gemini answers 429
+// before its next call. A variable so tests can shorten it.
+var translateCooldown = time.Minute
+
+// TranslationQueue translates new complaints in the background so their
+// messages never wait on Gemini: a complaint is announced in English and
+// handed to Add, and once its Gujarati translation is ready done is called
+// with the translated complaint, for the channels to edit their message.
+//
+// Complaints are translated one at a time, paced by a token bucket to
+// perMinute Gemini calls. A 429 pauses the queue for a cooldown and the
+// complaint is tried again; one still rate-limited after translateAttempts
+// (or failing otherwise) is passed to done with only a Note, so the
+// message says why it has no Gujarati.
+type TranslationQueue struct {
+	batch   func(ctx context.Context, texts []string) ([]string, error)
+	limiter *rate.Limiter
+	done    func(notify.Complaint)
+	jobs    chan notify.Complaint
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stopped chan struct{}
+}
+
+// NewTranslationQueue starts a queue translating with t. Returns nil when
+// t is nil (translation off); a nil queue accepts nothing.
+func NewTranslationQueue(t *translate.Translator, perMinute int, done func(notify.Complaint)) *TranslationQueue {
+	if t == nil {
+		return nil
+	}
+	return newTranslationQueue(t.BatchTranslateToGujarati, perMinute, done)
+}
+
+func newTranslationQueue(batch func(context.Context, []string) ([]string, error), perMinute int, done func(notify.Complaint)) *TranslationQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &TranslationQueue{
+		batch:   batch,
+		limiter: rate.NewLimiter(rate.Every(time.Minute/time.Duration(max(perMinute, 1))), 1),
+		done:    done,
+		jobs:    make(chan notify.Complaint, translationQueueSize),
+		ctx:     ctx,
+		cancel:  cancel,
+		stopped: make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// Add queues c for translation; announce c with TranslationPending set
+// first. When q is full c is passed to done straight away, with a Note
+// and no translation, so channels holding it back still send it. Add
+// reports false, and does nothing, when q is nil (translation off).
+func (q *TranslationQueue) Add(c notify.Complaint) bool {
+	if q == nil {
+		return false
+	}
+	select {
+	case q.jobs <- c:
+		metrics.TranslationQueueLength.Set(int64(len(q.jobs)))
+	default:
+		slog.Warn("translation queue full; complaint stays English-only", "complaint", c.Number)
+		q.done(Translation{Note: "Gujarati translation skipped (too many queued)"}.apply(c))
+	}
+	return true
+}
+
+// Stop abandons the queued complaints and waits for the call in flight to
+// return. Their announcements stay journaled, so RecoverAnnouncements
+// queues them again on the next start.
+func (q *TranslationQueue) Stop() {
+	if q == nil {
+		return
+	}
+	q.cancel()
+	<-q.stopped
+}
+
+func (q *TranslationQueue) run() {
+	defer close(q.stopped)
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case c := <-q.jobs:
+			metrics.TranslationQueueLength.Set(int64(len(q.jobs)))
+			if tr, ok := q.translate(c); ok {
+				q.done(tr.apply(c))
+			}
+		}
+	}
+}
+
+// translate renders c's name, description and address in Gujarati script,
+// waiting its turn and retrying through rate limits. ok is false when the
+// queue was stopped first.
+func (q *TranslationQueue) translate(c notify.Complaint) (tr Translation, ok bool) {
+	addr := fmt.Sprintf("%s, %s", c.ExactLocation, c.Area)
+	texts := []string{c.ComplainantName, c.Description, addr}
+	for attempt := 1; ; attempt++ {
+		if err := q.limiter.Wait(q.ctx); err != nil {
+			return Translation{}, false
+		}
+		ctx, cancel := context.WithTimeout(q.ctx, translateTimeout)
+		out, err := q.batch(ctx, texts)
+		cancel()
+		if q.ctx.Err() != nil {
+			return Translation{}, false
+		}
+		switch {
+		case errors.Is(err, translate.ErrRateLimited) && attempt < translateAttempts:
+			metrics.TranslationsRateLimitedTotal.Inc()
+			slog.Info("gemini rate limited; pausing translations", "complaint", c.Number, "attempt", attempt, "cooldown", translateCooldown)
+			select {
+			case <-q.ctx.Done():
+				return Translation{}, false
+			case <-time.After(translateCooldown):
+			}
+		case errors.Is(err, translate.ErrRateLimited):
+			metrics.TranslationsRateLimitedTotal.Inc()
+			return Translation{Note: "Gujarati translation skipped (rate limited)"}, true
+		case err != nil || len(out) < 3:
+			slog.Warn("translation failed", "complaint", c.Number, "error", err)
+			return Translation{Note: "Gujarati translation unavailable"}, true
+		default:
+			return newTranslation(out, texts[0], texts[1], texts[2]), true
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package complaint
+
+import (
+	"fmt"
+	"strings"
+
+	"cmon/internal/notify"
+)
+
+// Translation is the Gujarati-script rendering of a complaint's free-text
+// fields. A field the translator returned unchanged is left empty so
+// channels don't print the English twice. Note is set when translation
+// was attempted but skipped.
+type Translation struct {
+	Name        string
+	Description string
+	Address     string
+	Note        string
+}
+
+// newTranslation builds a Translation from Gemini's answer for name,
+// desc and addr.
+func newTranslation(out []string, name, desc, addr string) Translation {
+	changed := func(gu, en string) string {
+		if strings.TrimSpace(gu) == strings.TrimSpace(en) {
+			return ""
+		}
+		return gu
+	}
+	return Translation{
+		Name:        changed(out[0], name),
+		Description: changed(out[1], desc),
+		Address:     changed(out[2], addr),
+	}
+}
+
+// apply returns c carrying the translation, no longer pending.
+func (t Translation) apply(c notify.Complaint) notify.Complaint {
+	c.TranslationPending = false
+	c.Translation = t.Block()
+	c.NameGu = t.Name
+	c.DescriptionGu = t.Description
+	c.AddressGu = t.Address
+	c.TranslationNote = t.Note
+	return c
+}
+
+// Block is the translation as one "👤 / 💬 / 📍" block, for channels that
+// show it under the English message. Empty when nothing was translated.
+func (t Translation) Block() string {
+	var lines []string
+	for _, l := range []struct{ icon, text string }{
+		{"👤", t.Name}, {"💬", t.Description}, {"📍", t.Address},
+	} {
+		if l.text != "" {
+			lines = append(lines, fmt.Sprintf("%s %s", l.icon, l.text))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
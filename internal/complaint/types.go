@@ -1,6 +1,11 @@
 // Package complaint provides types and structures for complaint data.
 package complaint
 
+import (
+	"sort"
+	"strings"
+)
+
 // Link represents a complaint link extracted from the dashboard table.
 //
 // Fields:
@@ -10,9 +15,67 @@ package complaint
 // Why two IDs:
 //   - ComplaintNumber: User-facing, shown in Telegram messages
 //   - APIID: Backend ID, used for API calls to fetch details/resolve
+//
+// Row holds the row's visible cells keyed by their column header, lower
+// case (e.g. "consumer", "date"); nil when the table has no header row.
+// It stands in for the details when the detail API fails (RowDetails).
 type Link struct {
 	ComplaintNumber string
 	APIID           string
+	Row             map[string]string
+}
+
+// rowColumns maps Details fields to the words their dashboard column
+// header contains, in order of preference. A name is never taken from a
+// number column ("Consumer No").
+var rowColumns = []struct {
+	field    func(*Details) *interface{}
+	words    []string
+	noNumber bool
+}{
+	{func(d *Details) *interface{} { return &d.ComplainantName }, []string{"name", "complainant", "consumer"}, true},
+	{func(d *Details) *interface{} { return &d.ConsumerNo }, []string{"consumer no", "consumer number"}, false},
+	{func(d *Details) *interface{} { return &d.MobileNo }, []string{"mobile", "phone"}, false},
+	{func(d *Details) *interface{} { return &d.Area }, []string{"area", "village"}, false},
+	{func(d *Details) *interface{} { return &d.ExactLocation }, []string{"address", "location"}, false},
+	{func(d *Details) *interface{} { return &d.Description }, []string{"description", "detail", "remark"}, false},
+	{func(d *Details) *interface{} { return &d.ComplainDate }, []string{"date"}, false},
+}
+
+// RowDetails builds what Details it can from the dashboard row, and
+// reports whether the row had anything beyond the complaint number.
+func (l Link) RowDetails() (Details, bool) {
+	d := Details{ComplainNo: l.ComplaintNumber}
+	found := false
+	for _, col := range rowColumns {
+		if v := l.rowValue(col.words, col.noNumber); v != "" {
+			*col.field(&d) = v
+			found = true
+		}
+	}
+	return d, found
+}
+
+// rowValue returns the non-empty cell under the first header, in
+// alphabetical order, containing the first of words that matches any;
+// with noNumber, "... no" and "... number" headers are skipped.
+func (l Link) rowValue(words []string, noNumber bool) string {
+	headers := make([]string, 0, len(l.Row))
+	for h := range l.Row {
+		headers = append(headers, h)
+	}
+	sort.Strings(headers)
+	for _, w := range words {
+		for _, h := range headers {
+			if noNumber && (strings.HasSuffix(h, " no") || strings.Contains(h, "number")) {
+				continue
+			}
+			if v := l.Row[h]; v != "" && strings.Contains(h, w) {
+				return v
+			}
+		}
+	}
+	return ""
 }
 
 // Details represents the full complaint information from the API.
@@ -29,6 +92,11 @@ type Link struct {
 //   - complain_date: When complaint was filed
 //   - exact_location: Specific location of issue
 //   - area: General area/locality
+//
+// RepeatNote, DataIssues and MapsURL are filled in by the fetcher (not the
+// API): RepeatNote when the same consumer has already complained this month,
+// DataIssues with a readable list of intake problems (quality.Summary),
+// MapsURL when geocoding enrichment is enabled. Channels render them verbatim.
 type Details struct {
 	ComplainNo      interface{} `json:"complain_no"`
 	ConsumerNo      interface{} `json:"consumer_no"`
@@ -40,6 +108,11 @@ type Details struct {
 	Area            interface{} `json:"area"`
 	Village         string      `json:"village,omitempty"`
 	Belt            string      `json:"belt,omitempty"`
+	Feeder          string      `json:"feeder,omitempty"`
+	Category        string      `json:"category,omitempty"`
+	RepeatNote      string      `json:"repeat_note,omitempty"`
+	DataIssues      string      `json:"data_issues,omitempty"`
+	MapsURL         string      `json:"maps_url,omitempty"`
 }
 
 // ProcessResult represents the result of processing a single complaint.
@@ -59,4 +132,8 @@ type ProcessResult struct {
 	ConsumerName string
 	Details      Details
 	Error        error
+
+	// FromRow is set when the detail fetch failed and Details come from
+	// the dashboard row instead.
+	FromRow bool
 }
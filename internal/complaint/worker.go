@@ -5,11 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"runtime/debug"
 	"sync"
+	"time"
 
+	"cmon/internal/api"
+	"cmon/internal/crash"
 	"cmon/internal/session"
 )
 
+// workerIdleTimeout is how long a worker above the pool's minimum waits
+// for a job before it exits.
+var workerIdleTimeout = 5 * time.Second
+
 // Worker represents a single worker in the complaint processing pool.
 //
 // Workers now use an HTTP session client instead of a ChromeDP browser context.
@@ -19,63 +27,105 @@ type Worker struct {
 	jobs    <-chan Link
 	results chan<- ProcessResult
 	sc      *session.Client
-	wg      *sync.WaitGroup
+	pool    *WorkerPool
 }
 
 // WorkerPool manages a pool of concurrent complaint processing workers.
+//
+// The pool starts minWorkers workers and adds one, up to maxWorkers, each
+// time a submitted job finds more jobs queued than workers running — a
+// backlog after downtime gets the full pool, a quiet cycle keeps one or
+// two. Workers above the minimum exit after workerIdleTimeout without a
+// job.
 type WorkerPool struct {
-	workers     []*Worker
-	jobs        chan Link
-	results     chan ProcessResult
-	wg          sync.WaitGroup
-	workerCount int
+	jobs    chan Link
+	results chan ProcessResult
+	wg      sync.WaitGroup
+	sc      *session.Client
+
+	minWorkers, maxWorkers int
+
+	mu     sync.Mutex
+	active int // workers running
+	peak   int // most workers running at once
+	nextID int
 }
 
 // NewWorkerPool creates a new worker pool for concurrent complaint processing.
 //
 // Parameters:
 //   - sc: Authenticated session client (shared across all workers)
-//   - workerCount: Number of concurrent workers
+//   - minWorkers: Workers kept running however small the backlog (at least 1)
+//   - maxWorkers: Most concurrent workers under a backlog
 //   - batchSize: Number of jobs to be submitted (sizes the channel to prevent deadlock)
-func NewWorkerPool(sc *session.Client, workerCount int, batchSize int) *WorkerPool {
-	slog.Info("creating worker pool", "workers", workerCount, "batch_size", batchSize)
+func NewWorkerPool(sc *session.Client, minWorkers, maxWorkers int, batchSize int) *WorkerPool {
+	maxWorkers = max(maxWorkers, 1)
+	minWorkers = min(max(minWorkers, 1), maxWorkers)
+	slog.Info("creating worker pool", "min_workers", minWorkers, "max_workers", maxWorkers, "batch_size", batchSize)
 
 	// Channel must be at least as large as the batch to avoid the deadlock where
 	// the submission goroutine blocks while workers wait for results.
 	chSize := batchSize
-	if chSize < workerCount*2 {
-		chSize = workerCount * 2
+	if chSize < maxWorkers*2 {
+		chSize = maxWorkers * 2
 	}
 
 	pool := &WorkerPool{
-		workers:     make([]*Worker, workerCount),
-		// Size the channel to workerCount*2 as a minimum so Submit never blocks
-		// on small batches. The actual size will be adjusted per-batch in Submit.
-		jobs:        make(chan Link, chSize),
-		results:     make(chan ProcessResult, chSize),
-		workerCount: workerCount,
+		jobs:       make(chan Link, chSize),
+		results:    make(chan ProcessResult, chSize),
+		sc:         sc,
+		minWorkers: minWorkers,
+		maxWorkers: maxWorkers,
 	}
 
-	for i := 0; i < workerCount; i++ {
-		worker := &Worker{
-			id:      i + 1,
-			jobs:    pool.jobs,
-			results: pool.results,
-			sc:      sc,
-			wg:      &pool.wg,
-		}
-		pool.workers[i] = worker
-		pool.wg.Add(1)
-		go worker.start()
+	pool.mu.Lock()
+	for i := 0; i < minWorkers; i++ {
+		pool.spawnLocked()
 	}
+	pool.mu.Unlock()
 
-	slog.Info("worker pool started", "workers", workerCount)
+	slog.Info("worker pool started", "workers", minWorkers)
 	return pool
 }
 
-// Submit adds a complaint to the processing queue.
+// spawnLocked starts one more worker. p.mu must be held.
+func (p *WorkerPool) spawnLocked() {
+	p.nextID++
+	p.active++
+	p.peak = max(p.peak, p.active)
+	worker := &Worker{
+		id:      p.nextID,
+		jobs:    p.jobs,
+		results: p.results,
+		sc:      p.sc,
+		pool:    p,
+	}
+	p.wg.Add(1)
+	go worker.start()
+}
+
+// Submit adds a complaint to the processing queue, starting another
+// worker when the queue has outgrown the running ones.
 func (p *WorkerPool) Submit(complaint Link) {
 	p.jobs <- complaint
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.active < p.maxWorkers && len(p.jobs) > p.active {
+		p.spawnLocked()
+		slog.Debug("worker pool scaled up", "workers", p.active, "backlog", len(p.jobs))
+	}
+}
+
+// retire reports whether an idle worker may exit, keeping minWorkers.
+func (p *WorkerPool) retire() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.active <= p.minWorkers {
+		return false
+	}
+	p.active--
+	return true
 }
 
 // Close closes the job channel and waits for all workers to finish.
@@ -83,6 +133,14 @@ func (p *WorkerPool) Close() {
 	close(p.jobs)
 	p.wg.Wait()
 	close(p.results)
+	slog.Info("worker pool stopped", "peak_workers", p.Peak())
+}
+
+// Peak returns the most workers that ran at once.
+func (p *WorkerPool) Peak() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.peak
 }
 
 // Results returns the results channel for collecting processed complaints.
@@ -92,19 +150,50 @@ func (p *WorkerPool) Results() <-chan ProcessResult {
 
 // start begins the worker's processing loop.
 func (w *Worker) start() {
-	defer w.wg.Done()
-	for job := range w.jobs {
-		result := w.processComplaint(job)
-		w.results <- result
-		if result.Error != nil {
-			slog.Error("failed to process complaint",
-				"worker", w.id,
-				"complaint", job.ComplaintNumber,
-				"error", result.Error)
+	defer w.pool.wg.Done()
+	idle := time.NewTimer(workerIdleTimeout)
+	defer idle.Stop()
+	for {
+		select {
+		case job, ok := <-w.jobs:
+			if !ok {
+				return
+			}
+			result := w.processSafely(job)
+			w.results <- result
+			if result.Error != nil {
+				slog.Error("failed to process complaint",
+					"worker", w.id,
+					"complaint", job.ComplaintNumber,
+					"error", result.Error)
+			}
+			idle.Reset(workerIdleTimeout)
+		case <-idle.C:
+			if w.pool.retire() {
+				slog.Debug("idle worker exited", "worker", w.id)
+				return
+			}
+			idle.Reset(workerIdleTimeout)
 		}
 	}
 }
 
+// processSafely runs processComplaint, turning a panic into a failed
+// result so the worker keeps draining jobs and the fetch cycle is not left
+// waiting for a result that never comes.
+func (w *Worker) processSafely(job Link) (result ProcessResult) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			crash.Report(fmt.Sprintf("complaint worker %d", w.id), rec, debug.Stack())
+			result = ProcessResult{
+				ComplaintID: job.ComplaintNumber,
+				Error:       fmt.Errorf("panic while processing: %v", rec),
+			}
+		}
+	}()
+	return w.processComplaint(job)
+}
+
 // processComplaint fetches complaint details via an authenticated HTTP GET.
 //
 // Processing flow:
@@ -114,9 +203,7 @@ func (w *Worker) start() {
 //  4. Extract consumer name
 //  5. Return result with Details struct
 func (w *Worker) processComplaint(complaint Link) ProcessResult {
-	apiURL := fmt.Sprintf("https://complaint.dgvcl.com/api/complaint-record/%s", complaint.APIID)
-
-	body, err := w.sc.GetJSON(apiURL)
+	body, err := api.GetRecordJSON(w.sc, complaint.APIID)
 	if err != nil {
 		return ProcessResult{
 			ComplaintID: complaint.ComplaintNumber,
@@ -0,0 +1,120 @@
+package complaint
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"cmon/internal/api"
+	"cmon/internal/session"
+)
+
+// newRecordServer serves the complaint-record API for the pool's workers,
+// holding every response until release is closed so a backlog can build.
+func newRecordServer(t *testing.T, release <-chan struct{}) *session.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		fmt.Fprintf(w, `{"complaintdetail":{"complain_no":%q,"complainant_name":"Asha Patel"}}`, path.Base(r.URL.Path))
+	}))
+	t.Cleanup(server.Close)
+	api.SetRecordEndpoint(server.URL + "/api/complaint-record/")
+	t.Cleanup(func() { api.SetRecordEndpoint(api.DefaultRecordEndpoint) })
+
+	sc, err := session.New(1000, 1000, 0)
+	if err != nil {
+		t.Fatalf("new session client: %v", err)
+	}
+	return sc
+}
+
+// submitAll queues n jobs whose API IDs start with prefix, unique per test
+// so the record cache can't answer them.
+func submitAll(pool *WorkerPool, prefix string, from, n int) {
+	for i := from; i < from+n; i++ {
+		pool.Submit(Link{ComplaintNumber: fmt.Sprintf("CMP-%d", i), APIID: fmt.Sprintf("%s%d", prefix, i)})
+	}
+}
+
+// collect closes pool and returns its results, failing on any error.
+func collect(t *testing.T, pool *WorkerPool) map[string]bool {
+	t.Helper()
+	pool.Close()
+	got := make(map[string]bool)
+	for result := range pool.Results() {
+		if result.Error != nil {
+			t.Errorf("%s: %v", result.ComplaintID, result.Error)
+		}
+		got[result.ComplaintID] = true
+	}
+	return got
+}
+
+func TestWorkerPoolScalesUpForBacklog(t *testing.T) {
+	release := make(chan struct{})
+	sc := newRecordServer(t, release)
+
+	pool := NewWorkerPool(sc, 1, 4, 20)
+	submitAll(pool, "backlog-", 0, 20)
+	if got := pool.Peak(); got != 4 {
+		t.Errorf("Peak() under a backlog = %d, want maxWorkers 4", got)
+	}
+	close(release)
+	if got := collect(t, pool); len(got) != 20 {
+		t.Errorf("got %d results, want 20", len(got))
+	}
+}
+
+func TestWorkerPoolSmallBatchStaysAtMinimum(t *testing.T) {
+	release := make(chan struct{})
+	sc := newRecordServer(t, release)
+
+	pool := NewWorkerPool(sc, 2, 8, 2)
+	submitAll(pool, "small-", 0, 2)
+	close(release)
+	if got := collect(t, pool); len(got) != 2 {
+		t.Errorf("got %d results, want 2", len(got))
+	}
+	if got := pool.Peak(); got != 2 {
+		t.Errorf("Peak() for a small batch = %d, want minWorkers 2", got)
+	}
+}
+
+func TestWorkerPoolRetiresIdleWorkers(t *testing.T) {
+	defer func(d time.Duration) { workerIdleTimeout = d }(workerIdleTimeout)
+	workerIdleTimeout = 20 * time.Millisecond
+
+	release := make(chan struct{})
+	sc := newRecordServer(t, release)
+
+	pool := NewWorkerPool(sc, 1, 4, 13)
+	submitAll(pool, "idle-", 0, 12)
+	close(release)
+
+	active := func() int {
+		pool.mu.Lock()
+		defer pool.mu.Unlock()
+		return pool.active
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for active() > 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := active(); got != 1 {
+		t.Fatalf("%d workers still running once idle, want minWorkers 1", got)
+	}
+	if got := pool.Peak(); got != 4 {
+		t.Errorf("Peak() = %d, want 4 before the idle workers retired", got)
+	}
+
+	// The remaining worker still takes jobs, and every result is in before
+	// Close returns.
+	submitAll(pool, "idle-", 12, 1)
+	got := collect(t, pool)
+	if len(got) != 13 {
+		t.Errorf("got %d results, want 13", len(got))
+	}
+}
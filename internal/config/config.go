@@ -13,12 +13,20 @@ package config
 
 import (
 	_ "embed"
+	"encoding/hex"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"cmon/internal/category"
+	"cmon/internal/proxy"
+
 	"github.com/joho/godotenv"
 )
 
@@ -43,7 +51,20 @@ type Config struct {
 	// URLs for the DGVCL portal
 	LoginURL     string // Login page URL
 	ComplaintURL string // Dashboard URL with filters applied
+
+	// ComplaintStatuses are the portal cStatus values fetched each cycle,
+	// one dashboard view apiece, substituted into ComplaintURL (e.g. "2,3"
+	// for pending and assigned). Empty fetches ComplaintURL as it is.
+	ComplaintStatuses []string
+	// Pagination is how the fetcher finds the dashboard's next page:
+	// PaginationLinks follows the "Next" link, PaginationParam increments
+	// PageParam in the URL, PaginationAuto (the default) follows the link
+	// and falls back to the parameter when the page has none, as with
+	// pagination labels in Gujarati.
+	Pagination string
+	PageParam  string
 	ResolveURL   string // POST endpoint that marks a complaint as resolved
+	RecordURL    string // Complaint-record API; the complaint's API ID is appended
 
 	// Authentication credentials (required)
 	Username string // DGVCL portal username
@@ -54,15 +75,34 @@ type Config struct {
 	LoginRetryDelay time.Duration // Delay between login retry attempts
 	MaxFetchRetries int           // Maximum fetch attempts before alerting
 
+	// AlertReminderInterval is how often a critical alert is repeated while
+	// the failure lasts; 0 sends it once. A "recovered" message follows
+	// when a fetch succeeds again either way.
+	AlertReminderInterval time.Duration
+
 	// Pagination limits to prevent infinite loops
 	MaxPages int // Maximum number of pages to fetch per cycle
 
+	// ResolveConfirmations is how many consecutive complete fetches a
+	// complaint must be missing from before it is treated as resolved
+	// (0 or 1 = the first). Its portal record is checked as well.
+	ResolveConfirmations int
+
 	// Timing configuration for different operations
 	FetchInterval     time.Duration // How often to check for new complaints
 	FetchTimeout      time.Duration // Maximum time for entire fetch operation
 	NavigationTimeout time.Duration // Maximum time for page navigation
 	WaitTimeout       time.Duration // Maximum time to wait for elements
 
+	// SessionKeepAlive is how often the dashboard is refreshed between
+	// fetches so the portal session does not idle out; 0 disables it.
+	SessionKeepAlive time.Duration
+	// SessionTTL is how long a portal login lasts; SessionReloginMargin
+	// before it runs out the daemon logs in again between fetches, so a
+	// fetch never meets the expiry. 0 disables it.
+	SessionTTL           time.Duration
+	SessionReloginMargin time.Duration
+
 	// Telegram configuration (optional)
 	TelegramBotToken string // Telegram bot API token
 	TelegramChatID   string // Telegram chat ID for notifications
@@ -73,35 +113,404 @@ type Config struct {
 	// Parsed from TELEGRAM_BELT_ROUTES env, format: "belt=chatID,belt=chatID".
 	TelegramBeltRoutes map[string]string
 
+	// TelegramCategoryRoutes maps a complaint category (category.NoSupply,
+	// ...) to the chat its complaints go to, ahead of TelegramBeltRoutes;
+	// "chatID:topicID" posts into a forum topic of that chat. Parsed from
+	// TELEGRAM_CATEGORY_ROUTES, format: "billing=-100123,meter=-100456:7".
+	TelegramCategoryRoutes map[string]string
+
+	// TelegramAdminChatID receives operator prompts (the human captcha
+	// fallback) and is the only chat whose admin commands (/debug,
+	// /loglevel, /flag, /backup) are honoured. Defaults to TelegramChatID,
+	// where only TelegramAdmins may use them.
+	TelegramAdminChatID string
+
+	// TelegramLifecycleNotices posts "started" and "shutting down" messages
+	// to TelegramAdminChatID, so unexpected restarts show up in the chat.
+	TelegramLifecycleNotices bool
+
+	// TelegramDetailsButton adds a "🔍 Details" button to complaint messages
+	// that replies with the complaint's live status from the portal.
+	TelegramDetailsButton bool
+
+	// TelegramAckClaims turns the "👀 Seen" button into "👀 Acknowledge":
+	// the first person to tap it claims the complaint, the message shows
+	// "Being handled by …", and only they or TelegramAdmins can resolve it.
+	TelegramAckClaims bool
+	// TelegramAdmins are the @usernames or numeric user IDs who may
+	// resolve complaints someone else has claimed.
+	TelegramAdmins []string
+
+	// TelegramDigestThreshold bundles a fetch cycle's new complaints for
+	// one chat into a single digest message, with a resolve button per
+	// complaint, when there are more than this many. 0 sends one message
+	// per complaint.
+	TelegramDigestThreshold int
+
+	// ResolutionRemarks are canned resolution notes ("Fuse replaced", "DO
+	// replaced") offered as buttons after "Mark as Resolved". Parsed from a
+	// "|"-separated RESOLUTION_REMARKS, since remarks may contain commas.
+	// Empty keeps the typed-note prompt only.
+	ResolutionRemarks []string
+
+	// ResolveUndoWindow is how long a resolved complaint's message offers an
+	// "↩️ Undo" button that reopens it on the portal. 0 disables undo.
+	ResolveUndoWindow time.Duration
+
+	// ConversationTimeout drops a remarks prompt left unanswered this long,
+	// deleting it from the chat. 0 keeps it until answered.
+	ConversationTimeout time.Duration
+
+	// RedactPII masks mobile and consumer numbers to their last four digits
+	// in Telegram and WhatsApp complaint messages and summary images, the /events live
+	// feed and MQTT, for large groups. Storage, the dashboard and /details keep them
+	// in full.
+	RedactPII bool
+
+	// BotLanguage is the language of the bot's own texts — prompts,
+	// resolved cards, alerts, summary captions: "en" or "gu" (Gujarati).
+	BotLanguage string
+
+	// Timezone is the IANA zone name (e.g. "Asia/Kolkata") used for
+	// displayed times, schedules and local complaint IDs; see Location.
+	Timezone string
+
+	// Human-in-the-loop captcha. After CaptchaHumanAfter consecutive failures
+	// of the automatic solver, the captcha is posted to TelegramAdminChatID
+	// and a reply within CaptchaHumanTimeout is used as the answer.
+	// 0 disables the fallback.
+	CaptchaHumanAfter   int
+	CaptchaHumanTimeout time.Duration
+
 	// WhatsApp configuration (optional)
 	WhatsAppRecipientJID  string // Target JID, e.g. 919876543210@s.whatsapp.net
 	WhatsAppDBPath        string // Path to SQLite session DB (default: whatsapp.db)
 	WhatsAppResolveEnabled bool   // Allow resolve-by-reply from WhatsApp (default false)
 
+	// NotifyChannels restricts which configured notification channels are
+	// used ("telegram", "email", "webhook", "slack", "discord", "sms"). Empty
+	// means every configured channel. Parsed from NOTIFY_CHANNELS as
+	// "telegram,email".
+	NotifyChannels []string
+
+	// Email configuration (optional). Enabled when EmailSMTPHost and EmailTo
+	// are both set; new complaints and critical alerts are then emailed to
+	// every address in EmailTo. Parsed from EMAIL_TO as "a@x.in,b@y.in".
+	EmailSMTPHost     string
+	EmailSMTPPort     int
+	EmailSMTPUsername string
+	EmailSMTPPassword string
+	EmailFrom         string
+	EmailTo           []string
+
+	// Webhook sink (optional). Every new complaint, resolution and alert is
+	// POSTed as a JSON event to each URL in WebhookURLs, signed with
+	// WebhookSecret when set. Parsed from WEBHOOK_URLS as "https://a,https://b".
+	WebhookURLs       []string
+	WebhookSecret     string
+	WebhookMaxRetries int
+
+	// HookPlugins lists Go plugins (.so files) exporting a hooks.Hook that
+	// is told about new complaints, resolutions and fetch failures. Parsed
+	// from HOOK_PLUGINS as "/opt/cmon/erp.so,/opt/cmon/audit.so".
+	HookPlugins []string
+
+	// Slack channel (optional). Enabled when SlackBotToken and SlackChannelID
+	// are set; SlackSigningSecret additionally turns on the resolve button,
+	// whose clicks Slack sends to /slack/interactions on the health server.
+	SlackBotToken      string
+	SlackChannelID     string
+	SlackSigningSecret string
+
+	// Discord channel (optional). Enabled when DiscordBotToken and
+	// DiscordChannelID are set; DiscordPublicKey (the application's hex
+	// Ed25519 key) turns on the resolve button, served at /discord/interactions.
+	DiscordBotToken  string
+	DiscordChannelID string
+	DiscordPublicKey string
+
+	// SMS paging (optional). Critical alerts only, sent through SMSProvider
+	// ("twilio" or "msg91") to every E.164 number in SMSTo, so someone is
+	// paged even when Telegram itself is what broke. SMSCooldown limits
+	// repeats of the same alert. Parsed from SMS_TO as "+9198…,+9197…".
+	SMSProvider      string
+	SMSTo            []string
+	SMSCooldown      time.Duration
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	TwilioFrom       string
+	MSG91AuthKey     string
+	MSG91TemplateID  string
+
 	// Health check server configuration
 	HealthCheckPort string // Port for health check HTTP server
 
+	// HealthMaxFetchAge is how old the last successful fetch may be before
+	// /health reports the scraper unhealthy. Defaults to three fetch
+	// intervals.
+	HealthMaxFetchAge time.Duration
+
+	// GRPCAddr is where the gRPC API listens, e.g. ":9090"; empty disables
+	// it. GRPCToken, when set, must be sent by clients as a bearer token;
+	// without one the API may only listen on a loopback address.
+	GRPCAddr  string
+	GRPCToken string
+
+	// MQTTBrokerURL is the MQTT broker new/resolved complaints and open
+	// counts per belt are published to (tcp://, ssl://, ws://…); empty
+	// disables it. Topics go under MQTTTopicPrefix.
+	MQTTBrokerURL   string
+	MQTTTopicPrefix string
+	MQTTClientID    string
+	MQTTUsername    string
+	MQTTPassword    string
+	MQTTQoS         int
+
+	// HeartbeatURL is a dead-man's-switch push URL (healthchecks.io, Uptime
+	// Kuma) pinged after every successful fetch cycle. Empty disables it.
+	HeartbeatURL string
+
+	// DebugEndpoints mounts /debug/pprof/ and /debug/stats on the health
+	// server, for diagnosing memory growth. Off by default: the endpoints
+	// are unauthenticated.
+	DebugEndpoints bool
+
 	// LogFormat selects the structured logger output: "text" (terminal-friendly
 	// logfmt-style) or "json" (parseable by log aggregators). Defaults to "text".
 	LogFormat string
 
-	// ScheduledSummaries is a list of HH:MM (IST) times at which the daemon
+	// LogFile additionally writes logs to this file (console output stays
+	// on), rotating it at LogMaxSizeMB and keeping LogMaxBackups rotated
+	// files, none older than LogMaxAgeDays (0 = no age limit). Empty logs
+	// to the console only.
+	LogFile       string
+	LogMaxSizeMB  int
+	LogMaxBackups int
+	LogMaxAgeDays int
+
+	// ScheduledSummaries is a list of HH:MM times (in Timezone) at which the daemon
 	// will auto-post a /summary cycle to Telegram + WhatsApp. Empty disables
 	// the feature. Parsed in LoadConfig from a comma-separated env value
 	// like "09:00,18:00".
 	ScheduledSummaries []string
 
+	// WeeklyReport is "<weekday> HH:MM" (e.g. "Mon 09:00", local time): when
+	// the weekly Telegram report with trend charts is posted. Empty disables.
+	WeeklyReport string
+
+	// RollCallTime is the HH:MM (local time) at which a "morning status"
+	// message is posted to Telegram and pinned in place of the previous
+	// day's. Empty disables.
+	RollCallTime string
+
+	// QuietHours is "HH:MM-HH:MM" (local time, may span midnight): routine
+	// new-complaint Telegram messages arriving then are queued and sent as
+	// a digest once it ends. Complaints matching UrgentKeywords and alerts
+	// still go out at once. Empty disables.
+	QuietHours string
+	// UrgentKeywords mark a complaint HIGH priority when its description
+	// contains one, case-insensitively. Parsed from a comma-separated
+	// QUIET_HOURS_URGENT.
+	UrgentKeywords []string
+
+	// Outage clustering. When OutageClusterThreshold complaints from the same
+	// village/area arrive within OutageClusterWindow, a single "possible
+	// outage" alert is sent. Threshold 0 disables clustering.
+	// OutageSuppressIndividual skips the per-complaint messages for complaints
+	// that join an already-alerted cluster.
+	OutageClusterThreshold   int
+	OutageClusterWindow      time.Duration
+	OutageSuppressIndividual bool
+
+	// Ignore rules (see package filter): new complaints from
+	// IgnoreConsumers, with a description matching IgnoreDescription (a
+	// case-insensitive regular expression), or, when NotifyAreas is set,
+	// from no listed area are stored but never announced.
+	IgnoreConsumers   []string
+	IgnoreDescription string
+	NotifyAreas       []string
+
+	// FeederMapFile maps localities to feeders, one "locality = feeder"
+	// per line (see package feeder). Complaints are tagged with their
+	// feeder, outage clusters grouped by it and open complaints counted
+	// per feeder. Empty disables feeders.
+	FeederMapFile string
+
+	// InitialImport keeps the first fetch into an empty database (a new
+	// install, or after the database was wiped) from sending a message per
+	// complaint already on the dashboard: "silent" stores them without
+	// messages, "digest" also sends one alert listing them. Empty or "off"
+	// announces each one as usual.
+	InitialImport string
+
+	// Geocoding enrichment (optional). GeocodeProvider is "nominatim" (look
+	// up coordinates), "link" (Maps search link only, no lookups) or empty
+	// (disabled). SummaryMapEnabled adds a rendered map of pending complaint
+	// locations to the scheduled summary.
+	GeocodeProvider   string
+	GeocodeURL        string
+	GeocodeUserAgent  string
+	GeocodeCountry    string
+	SummaryMapEnabled bool
+
+	// UnseenReminderDelay enables acknowledgment tracking: complaint messages
+	// get a "👀 Seen" button (reactions count too), and complaints nobody
+	// acknowledged within this delay are re-posted in a reminder. 0 disables.
+	UnseenReminderDelay time.Duration
+
+	// AgingAfter and OverdueAfter tint summary-image rows amber and red
+	// once a complaint has been pending that long; 0 turns a tint off.
+	AgingAfter   time.Duration
+	OverdueAfter time.Duration
+
+	// SLATargets overrides the regulatory resolution targets of monthly SLA
+	// reports per complaint category: "no_supply=4h, transformer=24h".
+	// Parsed by SLATargetOverrides.
+	SLATargets string
+
+	// AgeFooter adds a "⏳ pending N days" footer to Telegram complaint
+	// messages, updated daily while the complaint stays open.
+	AgeFooter bool
+
+	// SummaryTheme is the summary-image theme: "light", "dark" or a JSON
+	// theme file (see summary.LoadTheme). SummaryTitle and SummaryBranding,
+	// when set, override the theme's title and footer branding.
+	SummaryTheme    string
+	SummaryTitle    string
+	SummaryBranding string
+	// SummaryMaxRows is the most complaints drawn in one summary image;
+	// longer tables are split into "Page i/n" images. 0 never splits.
+	SummaryMaxRows int
+	// SummaryGroupBy groups the combined summary by "belt", "area" or
+	// "feeder" (needs FeederMapFile), the latter two with subtotals and a
+	// bar chart.
+	SummaryGroupBy string
+
+	// MessageTemplate is a template file overriding the complaint message
+	// layout for Telegram and/or WhatsApp (see package msgtmpl). Empty
+	// keeps the built-in layout.
+	MessageTemplate string
+	// BilingualFields are the complaint fields (name, description, address)
+	// whose Gujarati translation is shown under the English line; other
+	// translated fields go in a block at the end. Empty = all in the block.
+	BilingualFields []string
+
+	// Sharding across instances. Set ShardCount > 1 on every instance and a
+	// distinct ShardIndex (0..ShardCount-1) on each; an instance only
+	// notifies for complaints hashed to its index. Instances share nothing,
+	// so give each its own TELEGRAM_BOT_TOKEN (all may post to one chat) so
+	// button presses reach the instance that owns the complaint.
+	ShardIndex int
+	ShardCount int
+
+	// History retention. When HistoryRetentionDays > 0, resolved complaint
+	// history older than that is deleted once a day — but only after it has
+	// been exported to a .tar.gz in RetentionExportDir and, when
+	// BackupUploadURL is set, PUT to BackupUploadURL/<archive name>.
+	HistoryRetentionDays int
+	RetentionExportDir   string
+	BackupUploadURL      string
+
+	// Telegram message cleanup. When TelegramCleanupDays > 0, the messages
+	// of complaints resolved longer ago than that are removed from their
+	// chat once a day: deleted, or with TelegramCleanupAction "unpin" only
+	// unpinned. TelegramCleanupArchive first posts a daily archive message
+	// listing them.
+	TelegramCleanupDays    int
+	TelegramCleanupAction  string
+	TelegramCleanupArchive bool
+
+	// DataDir holds files kept alongside the database; photos attached to
+	// resolutions are saved under DataDir/attachments.
+	DataDir string
+
+	// Database backups. Every BackupInterval (0 = off) a consistent snapshot
+	// of the database is written to BackupDir; only the newest BackupKeep
+	// are kept (0 = keep all).
+	BackupInterval time.Duration
+	BackupDir      string
+	BackupKeep     int
+
+	// Update checks. Every UpdateCheckInterval (0 = off) the latest GitHub
+	// release of UpdateRepo is compared with the running version and the
+	// admin chat is told about a newer one. UpdateAutoApply also installs
+	// it and restarts.
+	UpdateCheckInterval time.Duration
+	UpdateRepo          string
+	UpdateAutoApply     bool
+
+	// Leader election for redundant replicas sharing a working directory.
+	// With LeaderElection on, only the replica holding the lease fetches
+	// and notifies; the others wait until it has gone unrenewed for
+	// LeaderLeaseTTL. InstanceID names this replica in the lease, which is
+	// kept in LeaderLeaseFile (default DataDir/cmon-leader.db).
+	LeaderElection  bool
+	LeaderLeaseTTL  time.Duration
+	InstanceID      string
+	LeaderLeaseFile string
+
+	// StorageEncryptionKey (64 hex chars) encrypts consumer names, phone
+	// numbers and addresses in cmon.db and its backups with AES-256-GCM.
+	// StorageEncryptionKeyFile reads the key from a file instead, so it
+	// need not sit in the environment. Empty = stored in the clear.
+	StorageEncryptionKey     string
+	StorageEncryptionKeyFile string
+
 	// Debug mode - skips actual API calls for testing
 	DebugMode bool
 
+	// PortalTrace logs every portal request/response (method, URL, status,
+	// timing). When PortalTraceHARDir is also set, each fetch cycle is
+	// written there as a HAR file for offline analysis. Credentials and
+	// tokens are redacted.
+	PortalTrace       bool
+	PortalTraceHARDir string
+
+	// Portal TLS. Certificates are verified against the system roots plus
+	// TLSCAFile (a PEM bundle, for the portal's private CA). TLSPins are
+	// base64 SHA-256 public-key pins; without TLSCAFile a matching pin
+	// stands in for chain verification. InsecureTLS turns verification off.
+	TLSCAFile   string
+	TLSPins     []string
+	InsecureTLS bool
+
+	// Outbound proxy. ProxyURL (http://, https:// or socks5://) carries all
+	// outbound traffic except to the ProxyBypass hosts (NO_PROXY syntax).
+	// Empty = the standard HTTP_PROXY / HTTPS_PROXY / NO_PROXY variables.
+	ProxyURL    string
+	ProxyBypass []string
+
 	// Google Cloud Translation (optional)
 	GeminiAPIKey string // Gemini API key for Gujarati transliteration
+	// VoiceTranscribe sends voice-note resolution remarks to Gemini for a
+	// transcription to submit as the portal remark. Needs GeminiAPIKey.
+	VoiceTranscribe bool
+	// CategoryGeminiFallback asks Gemini for the category of a complaint
+	// whose description no keyword rule matches, instead of filing it
+	// under "other". Needs GeminiAPIKey.
+	CategoryGeminiFallback bool
+	// GeminiTranslateRPM paces the translation queue: complaints are sent
+	// in English and translated in the background at most this many per
+	// minute, their messages edited as each translation arrives.
+	GeminiTranslateRPM int
 
 	// Performance tuning
-	WorkerPoolSize int           // Number of concurrent workers for complaint processing
+	WorkerPoolSize int           // Most concurrent workers for complaint processing, under a backlog
+	WorkerPoolMin  int           // Workers kept however small the backlog
 	HTTPMaxConns   int           // Maximum HTTP connections in pool
 	HTTPTimeout    time.Duration // HTTP client timeout
 
+	// RecordCacheTTL is how long a fetched complaint record (detail JSON)
+	// is reused before the DGVCL API is asked again; 0 disables the cache.
+	RecordCacheTTL time.Duration
+
+	// New complaints are saved (and then announced) in transactions of up
+	// to BatchSize, or once the oldest buffered one has waited
+	// FlushInterval; the rest at the end of each fetch cycle. BatchSize
+	// <= 1 saves each page straight away.
+	BatchSize     int
+	FlushInterval time.Duration
+
 	// API rate limiting (DGVCL upstream returns 429 if we burst too fast)
 	APIRateLimitRPS   float64 // Sustained req/s ceiling for the DGVCL API
 	APIRateLimitBurst int     // Token-bucket burst size
@@ -147,7 +556,11 @@ func LoadConfig() (*Config, error) {
 		// URLs - can be overridden via env vars if portal URLs change
 		LoginURL:     getEnvOrDefault("LOGIN_URL", "https://complaint.dgvcl.com/"),
 		ComplaintURL: getEnvOrDefault("COMPLAINT_URL", "https://complaint.dgvcl.com/dashboard_complaint_list?from_date=&to_date=&honame=1&coname=21&doname=24&sdoname=87&cStatus=2&commobile="),
+		ComplaintStatuses: parseURLList(os.Getenv("COMPLAINT_STATUSES")),
+		Pagination:        strings.ToLower(getEnvOrDefault("PORTAL_PAGINATION", PaginationAuto)),
+		PageParam:         getEnvOrDefault("PORTAL_PAGE_PARAM", "page"),
 		ResolveURL:   getEnvOrDefault("DGVCL_RESOLVE_URL", "https://complaint.dgvcl.com/api/complaint-assign-process"),
+		RecordURL:    getEnvOrDefault("DGVCL_RECORD_URL", "https://complaint.dgvcl.com/api/complaint-record/"),
 
 		// Authentication - REQUIRED, no defaults
 		Username: os.Getenv("DGVCL_USERNAME"),
@@ -158,19 +571,50 @@ func LoadConfig() (*Config, error) {
 		LoginRetryDelay: getEnvDuration("LOGIN_RETRY_DELAY", 5*time.Second), // 5s between retries
 		MaxFetchRetries: getEnvInt("MAX_FETCH_RETRIES", 2),      // 2 retries for fetch operations
 
+		AlertReminderInterval: getEnvDuration("ALERT_REMINDER_INTERVAL", time.Hour),
+
 		// Pagination - default 5 pages to balance coverage vs speed
 		MaxPages: getEnvInt("MAX_PAGES", 5),
 
+		ResolveConfirmations: getEnvInt("RESOLVE_CONFIRMATIONS", 2),
+
 		// Timing - tuned for typical portal response times
 		FetchInterval:     getEnvDuration("FETCH_INTERVAL", 15*time.Minute),     // Check every 15 minutes
 		FetchTimeout:      getEnvDuration("FETCH_TIMEOUT", 10*time.Minute),      // 10 min total fetch timeout
 		NavigationTimeout: getEnvDuration("NAVIGATION_TIMEOUT", 60*time.Second), // 60s for page loads
 		WaitTimeout:       getEnvDuration("WAIT_TIMEOUT", 45*time.Second),       // 45s for element waits
 
+		SessionKeepAlive:     getEnvDuration("SESSION_KEEPALIVE_INTERVAL", 5*time.Minute),
+		SessionTTL:           getEnvDuration("SESSION_TTL", 0),
+		SessionReloginMargin: getEnvDuration("SESSION_RELOGIN_MARGIN", 5*time.Minute),
+
 		// Telegram - optional, notifications disabled if not set
-		TelegramBotToken:   os.Getenv("TELEGRAM_BOT_TOKEN"),
-		TelegramChatID:     os.Getenv("TELEGRAM_CHAT_ID"),
-		TelegramBeltRoutes: parseBeltRoutes(os.Getenv("TELEGRAM_BELT_ROUTES")),
+		TelegramBotToken:    os.Getenv("TELEGRAM_BOT_TOKEN"),
+		TelegramChatID:      os.Getenv("TELEGRAM_CHAT_ID"),
+		TelegramBeltRoutes:  parseBeltRoutes(os.Getenv("TELEGRAM_BELT_ROUTES")),
+		TelegramAdminChatID: getEnvOrDefault("TELEGRAM_ADMIN_CHAT_ID", os.Getenv("TELEGRAM_CHAT_ID")),
+
+		// Complaint categories - no routing, keyword rules without Gemini.
+		TelegramCategoryRoutes: parseBeltRoutes(os.Getenv("TELEGRAM_CATEGORY_ROUTES")),
+		CategoryGeminiFallback: getEnvOrDefault("CATEGORY_GEMINI_FALLBACK", "false") == "true",
+
+		TelegramDetailsButton: getEnvOrDefault("TELEGRAM_DETAILS_BUTTON", "true") == "true",
+		TelegramAckClaims:     getEnvOrDefault("TELEGRAM_ACK_CLAIMS", "false") == "true",
+		TelegramAdmins:        parseUserList(os.Getenv("TELEGRAM_ADMINS")),
+		ResolutionRemarks:     parseRemarkList(os.Getenv("RESOLUTION_REMARKS")),
+		ResolveUndoWindow:     getEnvDuration("RESOLVE_UNDO_WINDOW", 60*time.Second),
+		ConversationTimeout:   getEnvDuration("CONVERSATION_TIMEOUT", 0),
+		RedactPII:             getEnvOrDefault("REDACT_PII", "false") == "true",
+
+		TelegramDigestThreshold: getEnvInt("TELEGRAM_DIGEST_THRESHOLD", 0),
+		BotLanguage:             strings.ToLower(strings.TrimSpace(getEnvOrDefault("BOT_LANGUAGE", "en"))),
+		Timezone:                strings.TrimSpace(getEnvOrDefault("TZ_OVERRIDE", "Asia/Kolkata")),
+
+		TelegramLifecycleNotices: getEnvOrDefault("TELEGRAM_LIFECYCLE_NOTICES", "true") == "true",
+
+		// Human captcha fallback - off by default.
+		CaptchaHumanAfter:   getEnvInt("CAPTCHA_HUMAN_AFTER", 0),
+		CaptchaHumanTimeout: getEnvDuration("CAPTCHA_HUMAN_TIMEOUT", 10*time.Minute),
 
 		// WhatsApp - optional, notifications disabled if not set.
 		// Resolve-by-reply defaults to true now that the flow is fully
@@ -179,25 +623,187 @@ func LoadConfig() (*Config, error) {
 		WhatsAppDBPath:         getEnvOrDefault("WHATSAPP_DB_PATH", "whatsapp.db"),
 		WhatsAppResolveEnabled: getEnvOrDefault("WHATSAPP_RESOLVE_ENABLED", "true") == "true",
 
-		// Health check - default port 8080
-		HealthCheckPort: getEnvOrDefault("HEALTH_CHECK_PORT", "8080"),
+		// Health check - default port 8080; fetch age limit derived below.
+		HealthCheckPort:   getEnvOrDefault("HEALTH_CHECK_PORT", "8080"),
+		HealthMaxFetchAge: getEnvDuration("HEALTH_MAX_FETCH_AGE", 0),
+		DebugEndpoints:    getEnvOrDefault("DEBUG_ENDPOINTS", "false") == "true",
+		HeartbeatURL:      os.Getenv("HEARTBEAT_URL"),
+
+		// gRPC API - disabled unless an address is set.
+		GRPCAddr:  os.Getenv("GRPC_ADDR"),
+		GRPCToken: os.Getenv("GRPC_TOKEN"),
+
+		// MQTT - disabled unless a broker is set.
+		MQTTBrokerURL:   os.Getenv("MQTT_BROKER_URL"),
+		MQTTTopicPrefix: getEnvOrDefault("MQTT_TOPIC_PREFIX", "cmon"),
+		MQTTClientID:    getEnvOrDefault("MQTT_CLIENT_ID", "cmon"),
+		MQTTUsername:    os.Getenv("MQTT_USERNAME"),
+		MQTTPassword:    os.Getenv("MQTT_PASSWORD"),
+		MQTTQoS:         getEnvInt("MQTT_QOS", 1),
 
 		// Log format - default text mode for terminal use
 		LogFormat: getEnvOrDefault("LOG_FORMAT", "text"),
 
+		// File logging - off unless LOG_FILE is set
+		LogFile:       os.Getenv("LOG_FILE"),
+		LogMaxSizeMB:  getEnvInt("LOG_MAX_SIZE", 100),
+		LogMaxBackups: getEnvInt("LOG_MAX_BACKUPS", 5),
+		LogMaxAgeDays: getEnvInt("LOG_MAX_AGE_DAYS", 0),
+
 		// Scheduled summaries - empty by default (feature opt-in).
 		ScheduledSummaries: parseScheduleList(os.Getenv("SCHEDULED_SUMMARIES")),
+		WeeklyReport:       strings.TrimSpace(os.Getenv("WEEKLY_REPORT")),
+		RollCallTime:       strings.TrimSpace(os.Getenv("ROLL_CALL_TIME")),
+
+		// Quiet hours - off by default; urgent keywords catch hazards.
+		QuietHours:     strings.TrimSpace(os.Getenv("QUIET_HOURS")),
+		UrgentKeywords: parseKeywordList(getEnvOrDefault("QUIET_HOURS_URGENT", "fire,spark,shock,wire down,accident")),
+
+		// Outage clustering - 5 complaints per area within an hour by default.
+		OutageClusterThreshold:   getEnvInt("OUTAGE_CLUSTER_THRESHOLD", 5),
+		OutageClusterWindow:      getEnvDuration("OUTAGE_CLUSTER_WINDOW", time.Hour),
+		OutageSuppressIndividual: getEnvOrDefault("OUTAGE_SUPPRESS_INDIVIDUAL", "false") == "true",
+
+		// Ignore rules - none by default.
+		IgnoreConsumers:   parseURLList(os.Getenv("IGNORE_CONSUMERS")),
+		IgnoreDescription: os.Getenv("IGNORE_DESCRIPTION_REGEX"),
+		NotifyAreas:       parseURLList(os.Getenv("NOTIFY_AREAS")),
+
+		// Feeder map - off unless a file is set.
+		FeederMapFile: os.Getenv("FEEDER_MAP_FILE"),
+
+		// Initial import - off by default: every complaint is announced.
+		InitialImport: strings.ToLower(strings.TrimSpace(os.Getenv("INITIAL_IMPORT"))),
+
+		// Geocoding - disabled by default. The public Nominatim endpoint
+		// requires an identifying User-Agent and max 1 req/s.
+		GeocodeProvider:   strings.ToLower(strings.TrimSpace(os.Getenv("GEOCODE_PROVIDER"))),
+		GeocodeURL:        getEnvOrDefault("GEOCODE_URL", "https://nominatim.openstreetmap.org/search"),
+		GeocodeUserAgent:  getEnvOrDefault("GEOCODE_USER_AGENT", "cmon-complaint-monitor/1.0"),
+		GeocodeCountry:    getEnvOrDefault("GEOCODE_COUNTRY", "in"),
+		SummaryMapEnabled: getEnvOrDefault("SUMMARY_MAP_ENABLED", "false") == "true",
+
+		// Unseen-complaint reminder - off by default.
+		UnseenReminderDelay: getEnvDuration("UNSEEN_REMINDER_DELAY", 0),
+
+		// Complaint aging - summary tints on, message footer off.
+		AgingAfter:   getEnvDuration("AGE_AGING_AFTER", 24*time.Hour),
+		OverdueAfter: getEnvDuration("AGE_OVERDUE_AFTER", 72*time.Hour),
+		SLATargets:   strings.TrimSpace(os.Getenv("SLA_TARGETS")),
+		AgeFooter:    getEnvOrDefault("TELEGRAM_AGE_FOOTER", "false") == "true",
+
+		// Summary image look - light theme, built-in title.
+		SummaryTheme:    getEnvOrDefault("SUMMARY_THEME", "light"),
+		SummaryTitle:    strings.TrimSpace(os.Getenv("SUMMARY_TITLE")),
+		SummaryBranding: strings.TrimSpace(os.Getenv("SUMMARY_BRANDING")),
+		SummaryMaxRows:  getEnvInt("SUMMARY_MAX_ROWS", 25),
+		SummaryGroupBy:  strings.ToLower(getEnvOrDefault("SUMMARY_GROUP_BY", "belt")),
+
+		// Custom complaint message layout - built-in by default.
+		MessageTemplate: strings.TrimSpace(os.Getenv("MESSAGE_TEMPLATE")),
+		BilingualFields: parseBilingualFields(getEnvOrDefault("BILINGUAL_FIELDS", "name,description,address")),
+
+		// Sharding - off by default (one instance owns everything).
+		ShardIndex: getEnvInt("SHARD_INDEX", 0),
+		ShardCount: getEnvInt("SHARD_COUNT", 1),
+
+		// History retention - off by default (history is kept forever).
+		HistoryRetentionDays: getEnvInt("HISTORY_RETENTION_DAYS", 0),
+		RetentionExportDir:   getEnvOrDefault("RETENTION_EXPORT_DIR", "exports"),
+		BackupUploadURL:      os.Getenv("BACKUP_UPLOAD_URL"),
+
+		// Telegram message cleanup - off by default.
+		TelegramCleanupDays:    getEnvInt("TELEGRAM_CLEANUP_DAYS", 0),
+		TelegramCleanupAction:  strings.ToLower(strings.TrimSpace(getEnvOrDefault("TELEGRAM_CLEANUP_ACTION", "delete"))),
+		TelegramCleanupArchive: getEnvOrDefault("TELEGRAM_CLEANUP_ARCHIVE", "false") == "true",
+
+		DataDir: getEnvOrDefault("DATA_DIR", "."),
+
+		// Database backups - off by default.
+		BackupInterval: getEnvDuration("BACKUP_INTERVAL", 0),
+		BackupDir:      getEnvOrDefault("BACKUP_DIR", "backups"),
+		BackupKeep:     getEnvInt("BACKUP_KEEP", 7),
+
+		// Update checks - off by default.
+		UpdateCheckInterval: getEnvDuration("UPDATE_CHECK_INTERVAL", 0),
+		UpdateRepo:          strings.TrimSpace(getEnvOrDefault("UPDATE_REPO", "Satyam085/cmon")),
+		UpdateAutoApply:     getEnvOrDefault("UPDATE_AUTO_APPLY", "false") == "true",
+
+		// Leader election - off by default (a single instance).
+		LeaderElection:  getEnvOrDefault("LEADER_ELECTION", "false") == "true",
+		LeaderLeaseTTL:  getEnvDuration("LEADER_LEASE_TTL", 30*time.Second),
+		InstanceID:      strings.TrimSpace(getEnvOrDefault("INSTANCE_ID", defaultInstanceID())),
+		LeaderLeaseFile: strings.TrimSpace(os.Getenv("LEADER_LEASE_FILE")),
+
+		// Encryption at rest - off unless a key is given.
+		StorageEncryptionKey:     os.Getenv("STORAGE_ENCRYPTION_KEY"),
+		StorageEncryptionKeyFile: os.Getenv("STORAGE_ENCRYPTION_KEY_FILE"),
+
+		NotifyChannels: parseChannelList(os.Getenv("NOTIFY_CHANNELS")),
+
+		// Email - disabled unless host and recipients are set.
+		EmailSMTPHost:     os.Getenv("EMAIL_SMTP_HOST"),
+		EmailSMTPPort:     getEnvInt("EMAIL_SMTP_PORT", 587),
+		EmailSMTPUsername: os.Getenv("EMAIL_SMTP_USERNAME"),
+		EmailSMTPPassword: os.Getenv("EMAIL_SMTP_PASSWORD"),
+		EmailFrom:         os.Getenv("EMAIL_FROM"),
+		EmailTo:           parseEmailList(os.Getenv("EMAIL_TO")),
+
+		// Webhook - disabled unless URLs are set.
+		WebhookURLs:       parseURLList(os.Getenv("WEBHOOK_URLS")),
+		WebhookSecret:     os.Getenv("WEBHOOK_SECRET"),
+		WebhookMaxRetries: getEnvInt("WEBHOOK_MAX_RETRIES", 3),
+
+		HookPlugins: parseURLList(os.Getenv("HOOK_PLUGINS")),
+
+		// Slack / Discord - disabled unless token and channel are set.
+		SlackBotToken:      os.Getenv("SLACK_BOT_TOKEN"),
+		SlackChannelID:     os.Getenv("SLACK_CHANNEL_ID"),
+		SlackSigningSecret: os.Getenv("SLACK_SIGNING_SECRET"),
+		DiscordBotToken:    os.Getenv("DISCORD_BOT_TOKEN"),
+		DiscordChannelID:   os.Getenv("DISCORD_CHANNEL_ID"),
+		DiscordPublicKey:   os.Getenv("DISCORD_PUBLIC_KEY"),
+
+		// SMS - disabled unless a provider is set.
+		SMSProvider:      strings.ToLower(strings.TrimSpace(os.Getenv("SMS_PROVIDER"))),
+		SMSTo:            parsePhoneList(os.Getenv("SMS_TO")),
+		SMSCooldown:      getEnvDuration("SMS_COOLDOWN", 15*time.Minute),
+		TwilioAccountSID: os.Getenv("TWILIO_ACCOUNT_SID"),
+		TwilioAuthToken:  os.Getenv("TWILIO_AUTH_TOKEN"),
+		TwilioFrom:       os.Getenv("TWILIO_FROM"),
+		MSG91AuthKey:     os.Getenv("MSG91_AUTH_KEY"),
+		MSG91TemplateID:  os.Getenv("MSG91_TEMPLATE_ID"),
 
 		// Debug mode - default false (production mode)
 		DebugMode: getEnvOrDefault("DEBUG_MODE", "false") == "true",
 
+		// Portal request tracing - off by default; HAR output opt-in.
+		PortalTrace:       getEnvOrDefault("PORTAL_TRACE", "false") == "true",
+		PortalTraceHARDir: os.Getenv("PORTAL_TRACE_HAR_DIR"),
+
+		// Portal TLS - verified by default; INSECURE_TLS is an explicit opt-out.
+		TLSCAFile:   os.Getenv("TLS_CA_FILE"),
+		TLSPins:     parsePinList(os.Getenv("TLS_PINNED_SHA256")),
+		InsecureTLS: getEnvOrDefault("INSECURE_TLS", "false") == "true",
+
+		// Outbound proxy - optional, environment proxies apply otherwise
+		ProxyURL:    os.Getenv("PROXY_URL"),
+		ProxyBypass: parseURLList(os.Getenv("PROXY_BYPASS")),
+
 		// Google Cloud Translation (optional)
-		GeminiAPIKey: os.Getenv("GEMINI_API_KEY"),
+		GeminiAPIKey:       os.Getenv("GEMINI_API_KEY"),
+		VoiceTranscribe:    getEnvOrDefault("VOICE_TRANSCRIBE", "false") == "true",
+		GeminiTranslateRPM: getEnvInt("GEMINI_TRANSLATE_RPM", 10),
 
 		// Performance tuning - optimized defaults
-		WorkerPoolSize: getEnvInt("WORKER_POOL_SIZE", 10),      // 10 concurrent workers
+		WorkerPoolSize: getEnvInt("WORKER_POOL_SIZE", 10),      // up to 10 concurrent workers
+		WorkerPoolMin:  getEnvInt("WORKER_POOL_MIN", 1),
 		HTTPMaxConns:   getEnvInt("HTTP_MAX_CONNS", 100),       // 100 connection pool size
 		HTTPTimeout:    getEnvDuration("HTTP_TIMEOUT", 30*time.Second), // 30s HTTP timeout
+		BatchSize:      getEnvInt("BATCH_SIZE", 50),
+		FlushInterval:  getEnvDuration("FLUSH_INTERVAL", 30*time.Second),
+
+		RecordCacheTTL: getEnvDuration("RECORD_CACHE_TTL", time.Minute),
 
 		// API rate limiting - keeps us under the DGVCL portal's 429 threshold
 		APIRateLimitRPS:   getEnvFloat("API_RATE_LIMIT_RPS", 3.0),
@@ -205,6 +811,13 @@ func LoadConfig() (*Config, error) {
 		APIMaxRetries429:  getEnvInt("API_MAX_RETRIES_429", 5),
 	}
 
+	if cfg.HealthMaxFetchAge == 0 {
+		cfg.HealthMaxFetchAge = 3 * cfg.FetchInterval
+	}
+	if cfg.LeaderLeaseFile == "" {
+		cfg.LeaderLeaseFile = filepath.Join(cfg.DataDir, "cmon-leader.db")
+	}
+
 	// Step 4: Validate required fields
 	if err := cfg.Validate(); err != nil {
 		return nil, err
@@ -238,20 +851,409 @@ func (c *Config) Validate() error {
 	if c.ComplaintURL == "" {
 		return fmt.Errorf("COMPLAINT_URL cannot be empty")
 	}
+	for _, s := range c.ComplaintStatuses {
+		if _, err := strconv.Atoi(s); err != nil {
+			return fmt.Errorf("COMPLAINT_STATUSES entry %q is not a portal status number", s)
+		}
+	}
+	switch c.Pagination {
+	case "", PaginationAuto, PaginationLinks:
+	case PaginationParam:
+		if c.PageParam == "" {
+			return fmt.Errorf("PORTAL_PAGE_PARAM cannot be empty with PORTAL_PAGINATION=param")
+		}
+	default:
+		return fmt.Errorf("PORTAL_PAGINATION must be auto, links or param, got %q", c.Pagination)
+	}
+	if len(c.ComplaintStatuses) > 0 {
+		if _, err := url.Parse(c.ComplaintURL); err != nil {
+			return fmt.Errorf("COMPLAINT_URL is not a valid URL: %w", err)
+		}
+	}
 
 	// Validate numeric values are positive
 	if c.MaxPages < 1 {
 		return fmt.Errorf("MAX_PAGES must be at least 1, got %d", c.MaxPages)
 	}
+	if c.ResolveConfirmations < 0 {
+		return fmt.Errorf("RESOLVE_CONFIRMATIONS cannot be negative, got %d", c.ResolveConfirmations)
+	}
 	if c.WorkerPoolSize < 1 {
 		return fmt.Errorf("WORKER_POOL_SIZE must be at least 1, got %d", c.WorkerPoolSize)
 	}
+	if c.IgnoreDescription != "" {
+		if _, err := regexp.Compile(c.IgnoreDescription); err != nil {
+			return fmt.Errorf("IGNORE_DESCRIPTION_REGEX is not a valid regular expression: %w", err)
+		}
+	}
+	if c.GeminiAPIKey != "" && c.GeminiTranslateRPM < 1 {
+		return fmt.Errorf("GEMINI_TRANSLATE_RPM must be at least 1, got %d", c.GeminiTranslateRPM)
+	}
+	if c.RecordCacheTTL < 0 {
+		return fmt.Errorf("RECORD_CACHE_TTL cannot be negative, got %v", c.RecordCacheTTL)
+	}
+	if c.WorkerPoolMin < 1 || c.WorkerPoolMin > c.WorkerPoolSize {
+		return fmt.Errorf("WORKER_POOL_MIN must be between 1 and WORKER_POOL_SIZE (%d), got %d", c.WorkerPoolSize, c.WorkerPoolMin)
+	}
+	if c.InsecureTLS && (c.TLSCAFile != "" || len(c.TLSPins) > 0) {
+		return fmt.Errorf("INSECURE_TLS cannot be combined with TLS_CA_FILE or TLS_PINNED_SHA256")
+	}
+	if c.ProxyURL != "" {
+		if _, err := proxy.Parse(c.ProxyURL); err != nil {
+			return fmt.Errorf("PROXY_URL: %w", err)
+		}
+	} else if len(c.ProxyBypass) > 0 {
+		return fmt.Errorf("PROXY_BYPASS needs PROXY_URL (use NO_PROXY with HTTP_PROXY)")
+	}
+	if c.SessionKeepAlive < 0 {
+		return fmt.Errorf("SESSION_KEEPALIVE_INTERVAL cannot be negative, got %v", c.SessionKeepAlive)
+	}
+	if c.SessionTTL < 0 || c.SessionReloginMargin < 0 {
+		return fmt.Errorf("SESSION_TTL and SESSION_RELOGIN_MARGIN cannot be negative, got %v and %v", c.SessionTTL, c.SessionReloginMargin)
+	}
+	if c.SessionTTL > 0 && c.SessionReloginMargin >= c.SessionTTL {
+		return fmt.Errorf("SESSION_RELOGIN_MARGIN (%v) must be shorter than SESSION_TTL (%v)", c.SessionReloginMargin, c.SessionTTL)
+	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("BATCH_SIZE cannot be negative, got %d", c.BatchSize)
+	}
+	if c.FlushInterval < 0 {
+		return fmt.Errorf("FLUSH_INTERVAL cannot be negative, got %v", c.FlushInterval)
+	}
+	switch c.GeocodeProvider {
+	case "", "nominatim", "link":
+	default:
+		return fmt.Errorf("GEOCODE_PROVIDER must be nominatim, link or empty, got %q", c.GeocodeProvider)
+	}
+	if c.OutageClusterThreshold < 0 {
+		return fmt.Errorf("OUTAGE_CLUSTER_THRESHOLD cannot be negative, got %d", c.OutageClusterThreshold)
+	}
+	if c.OutageClusterThreshold > 0 && c.OutageClusterWindow <= 0 {
+		return fmt.Errorf("OUTAGE_CLUSTER_WINDOW must be positive when clustering is enabled, got %v", c.OutageClusterWindow)
+	}
+	switch c.InitialImport {
+	case "", "off", "silent", "digest":
+	default:
+		return fmt.Errorf("INITIAL_IMPORT must be off, silent or digest, got %q", c.InitialImport)
+	}
+	for _, ch := range c.NotifyChannels {
+		if !knownNotifyChannels[ch] {
+			return fmt.Errorf("NOTIFY_CHANNELS contains unknown channel %q (want telegram, email, webhook, slack, discord, sms)", ch)
+		}
+	}
+	for _, f := range c.BilingualFields {
+		if !knownBilingualFields[f] {
+			return fmt.Errorf("BILINGUAL_FIELDS contains unknown field %q (want name, description, address or none)", f)
+		}
+	}
+	if c.EmailSMTPHost != "" && len(c.EmailTo) > 0 {
+		if c.EmailFrom == "" {
+			return fmt.Errorf("EMAIL_FROM is required when email notifications are enabled")
+		}
+		if c.EmailSMTPPort <= 0 || c.EmailSMTPPort > 65535 {
+			return fmt.Errorf("EMAIL_SMTP_PORT must be between 1 and 65535, got %d", c.EmailSMTPPort)
+		}
+	}
+	for _, u := range c.WebhookURLs {
+		if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("WEBHOOK_URLS contains invalid URL %q (want http:// or https://)", u)
+		}
+	}
+	if c.WebhookMaxRetries < 0 {
+		return fmt.Errorf("WEBHOOK_MAX_RETRIES cannot be negative, got %d", c.WebhookMaxRetries)
+	}
+	if c.GRPCAddr != "" {
+		host, _, err := net.SplitHostPort(c.GRPCAddr)
+		if err != nil {
+			return fmt.Errorf("GRPC_ADDR must be host:port or :port, got %q", c.GRPCAddr)
+		}
+		if c.GRPCToken == "" && !loopback(host) {
+			return fmt.Errorf("GRPC_ADDR %q is reachable from other hosts; set GRPC_TOKEN or listen on 127.0.0.1", c.GRPCAddr)
+		}
+	}
+	if err := c.validateMQTT(); err != nil {
+		return err
+	}
+	if err := c.validateCategoryRoutes(); err != nil {
+		return err
+	}
+	for _, p := range c.HookPlugins {
+		if !strings.HasSuffix(p, ".so") {
+			return fmt.Errorf("HOOK_PLUGINS contains %q, which is not a .so plugin", p)
+		}
+	}
+	if c.DiscordPublicKey != "" {
+		if key, err := hex.DecodeString(c.DiscordPublicKey); err != nil || len(key) != 32 {
+			return fmt.Errorf("DISCORD_PUBLIC_KEY must be 64 hex characters, got %q", c.DiscordPublicKey)
+		}
+	}
+	if err := c.validateSMS(); err != nil {
+		return err
+	}
+	if c.CaptchaHumanAfter < 0 {
+		return fmt.Errorf("CAPTCHA_HUMAN_AFTER cannot be negative, got %d", c.CaptchaHumanAfter)
+	}
+	if c.CaptchaHumanAfter > 0 && c.CaptchaHumanTimeout <= 0 {
+		return fmt.Errorf("CAPTCHA_HUMAN_TIMEOUT must be positive when the captcha fallback is enabled, got %v", c.CaptchaHumanTimeout)
+	}
+	if c.LogFile != "" && c.LogMaxSizeMB <= 0 {
+		return fmt.Errorf("LOG_MAX_SIZE must be positive (megabytes), got %d", c.LogMaxSizeMB)
+	}
+	if c.LogMaxBackups < 0 || c.LogMaxAgeDays < 0 {
+		return fmt.Errorf("LOG_MAX_BACKUPS and LOG_MAX_AGE_DAYS cannot be negative, got %d and %d", c.LogMaxBackups, c.LogMaxAgeDays)
+	}
+	if c.AlertReminderInterval < 0 {
+		return fmt.Errorf("ALERT_REMINDER_INTERVAL cannot be negative, got %v", c.AlertReminderInterval)
+	}
+	if c.HealthMaxFetchAge < 0 {
+		return fmt.Errorf("HEALTH_MAX_FETCH_AGE cannot be negative, got %v", c.HealthMaxFetchAge)
+	}
+	if c.TelegramDigestThreshold < 0 {
+		return fmt.Errorf("TELEGRAM_DIGEST_THRESHOLD cannot be negative, got %d", c.TelegramDigestThreshold)
+	}
+	if c.ResolveUndoWindow < 0 {
+		return fmt.Errorf("RESOLVE_UNDO_WINDOW cannot be negative, got %v", c.ResolveUndoWindow)
+	}
+	if c.ConversationTimeout < 0 {
+		return fmt.Errorf("CONVERSATION_TIMEOUT cannot be negative, got %v", c.ConversationTimeout)
+	}
+	if c.UnseenReminderDelay < 0 {
+		return fmt.Errorf("UNSEEN_REMINDER_DELAY cannot be negative, got %v", c.UnseenReminderDelay)
+	}
+	if c.AgingAfter < 0 || c.OverdueAfter < 0 {
+		return fmt.Errorf("AGE_AGING_AFTER and AGE_OVERDUE_AFTER cannot be negative, got %v and %v", c.AgingAfter, c.OverdueAfter)
+	}
+	if _, err := c.SLATargetOverrides(); err != nil {
+		return err
+	}
+	if _, _, ok := c.WeeklyReportTime(); c.WeeklyReport != "" && !ok {
+		return fmt.Errorf("WEEKLY_REPORT must be \"<weekday> HH:MM\" like \"Mon 09:00\", got %q", c.WeeklyReport)
+	}
+	if c.RollCallTime != "" && !validHHMM(c.RollCallTime) {
+		return fmt.Errorf("ROLL_CALL_TIME must be HH:MM, got %q", c.RollCallTime)
+	}
+	if _, _, ok := c.QuietHoursRange(); c.QuietHours != "" && !ok {
+		return fmt.Errorf("QUIET_HOURS must be \"HH:MM-HH:MM\" like \"22:00-07:00\", got %q", c.QuietHours)
+	}
+	if c.SummaryMaxRows < 0 {
+		return fmt.Errorf("SUMMARY_MAX_ROWS cannot be negative, got %d", c.SummaryMaxRows)
+	}
+	switch c.BotLanguage {
+	case "", "en", "gu":
+	default:
+		return fmt.Errorf("BOT_LANGUAGE must be en or gu, got %q", c.BotLanguage)
+	}
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		return fmt.Errorf("TZ_OVERRIDE must be an IANA timezone like Asia/Kolkata, got %q", c.Timezone)
+	}
+	switch c.SummaryGroupBy {
+	case "", "belt", "area":
+	case "feeder":
+		if c.FeederMapFile == "" {
+			return fmt.Errorf("SUMMARY_GROUP_BY=feeder requires FEEDER_MAP_FILE")
+		}
+	default:
+		return fmt.Errorf("SUMMARY_GROUP_BY must be belt, area or feeder, got %q", c.SummaryGroupBy)
+	}
+	if c.ShardCount < 0 {
+		return fmt.Errorf("SHARD_COUNT cannot be negative, got %d", c.ShardCount)
+	}
+	if c.ShardCount > 1 && (c.ShardIndex < 0 || c.ShardIndex >= c.ShardCount) {
+		return fmt.Errorf("SHARD_INDEX must be between 0 and %d, got %d", c.ShardCount-1, c.ShardIndex)
+	}
+	if c.HistoryRetentionDays < 0 {
+		return fmt.Errorf("HISTORY_RETENTION_DAYS cannot be negative, got %d", c.HistoryRetentionDays)
+	}
+	if c.HistoryRetentionDays > 0 && c.RetentionExportDir == "" {
+		return fmt.Errorf("RETENTION_EXPORT_DIR is required when history retention is enabled")
+	}
+	if c.TelegramCleanupDays < 0 {
+		return fmt.Errorf("TELEGRAM_CLEANUP_DAYS cannot be negative, got %d", c.TelegramCleanupDays)
+	}
+	switch c.TelegramCleanupAction {
+	case "", "delete", "unpin":
+	default:
+		return fmt.Errorf("TELEGRAM_CLEANUP_ACTION must be delete or unpin, got %q", c.TelegramCleanupAction)
+	}
+	// Cleanup finds messages through the history rows retention deletes.
+	if c.TelegramCleanupDays > 0 && c.HistoryRetentionDays > 0 && c.TelegramCleanupDays >= c.HistoryRetentionDays {
+		return fmt.Errorf("TELEGRAM_CLEANUP_DAYS (%d) must be less than HISTORY_RETENTION_DAYS (%d)", c.TelegramCleanupDays, c.HistoryRetentionDays)
+	}
+	if c.BackupInterval < 0 {
+		return fmt.Errorf("BACKUP_INTERVAL cannot be negative, got %v", c.BackupInterval)
+	}
+	if c.BackupKeep < 0 {
+		return fmt.Errorf("BACKUP_KEEP cannot be negative, got %d", c.BackupKeep)
+	}
+	if c.BackupInterval > 0 && c.BackupDir == "" {
+		return fmt.Errorf("BACKUP_DIR is required when backups are enabled")
+	}
+	if c.UpdateCheckInterval < 0 {
+		return fmt.Errorf("UPDATE_CHECK_INTERVAL cannot be negative, got %v", c.UpdateCheckInterval)
+	}
+	if owner, name, ok := strings.Cut(c.UpdateRepo, "/"); c.UpdateCheckInterval > 0 && (!ok || owner == "" || name == "" || strings.Contains(name, "/")) {
+		return fmt.Errorf("UPDATE_REPO must be a GitHub \"owner/name\", got %q", c.UpdateRepo)
+	}
+	if c.LeaderElection && c.LeaderLeaseTTL < 3*time.Second {
+		return fmt.Errorf("LEADER_LEASE_TTL must be at least 3s, got %v", c.LeaderLeaseTTL)
+	}
+	if c.LeaderElection && c.InstanceID == "" {
+		return fmt.Errorf("INSTANCE_ID is required when leader election is enabled")
+	}
+	if _, err := c.EncryptionKey(); err != nil {
+		return err
+	}
+	if c.BackupUploadURL != "" {
+		if parsed, err := url.Parse(c.BackupUploadURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("BACKUP_UPLOAD_URL is not a valid http:// or https:// URL: %q", c.BackupUploadURL)
+		}
+	}
+
+	return nil
+}
+
+// ComplaintView is one dashboard listing fetched each cycle.
+type ComplaintView struct {
+	Status string // cStatus value; "" for COMPLAINT_URL as configured
+	URL    string
+}
+
+// Pagination strategies (PORTAL_PAGINATION).
+const (
+	PaginationAuto  = "auto"
+	PaginationLinks = "links"
+	PaginationParam = "param"
+)
 
+// ComplaintViews returns the dashboard views to fetch: ComplaintURL with
+// cStatus set to each of ComplaintStatuses, or ComplaintURL alone.
+func (c *Config) ComplaintViews() []ComplaintView {
+	u, err := url.Parse(c.ComplaintURL)
+	if len(c.ComplaintStatuses) == 0 || err != nil {
+		return []ComplaintView{{URL: c.ComplaintURL}}
+	}
+	views := make([]ComplaintView, 0, len(c.ComplaintStatuses))
+	for _, s := range c.ComplaintStatuses {
+		q := u.Query()
+		q.Set("cStatus", s)
+		v := *u
+		v.RawQuery = q.Encode()
+		views = append(views, ComplaintView{Status: s, URL: v.String()})
+	}
+	return views
+}
+
+// validateSMS checks the SMS settings when SMS_PROVIDER is set.
+// EncryptionKey returns the storage encryption key from
+// STORAGE_ENCRYPTION_KEY or STORAGE_ENCRYPTION_KEY_FILE, or nil when
+// neither is set.
+func (c *Config) EncryptionKey() ([]byte, error) {
+	raw, source := c.StorageEncryptionKey, "STORAGE_ENCRYPTION_KEY"
+	if raw != "" && c.StorageEncryptionKeyFile != "" {
+		return nil, fmt.Errorf("set only one of STORAGE_ENCRYPTION_KEY and STORAGE_ENCRYPTION_KEY_FILE")
+	}
+	if c.StorageEncryptionKeyFile != "" {
+		data, err := os.ReadFile(c.StorageEncryptionKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("STORAGE_ENCRYPTION_KEY_FILE: %w", err)
+		}
+		raw, source = strings.TrimSpace(string(data)), "STORAGE_ENCRYPTION_KEY_FILE"
+	}
+	if raw == "" {
+		return nil, nil
+	}
+	key, err := hex.DecodeString(raw)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("%s must be 64 hex characters (32 bytes), e.g. from `openssl rand -hex 32`", source)
+	}
+	return key, nil
+}
+
+func (c *Config) validateSMS() error {
+	switch c.SMSProvider {
+	case "":
+		return nil
+	case "twilio":
+		if c.TwilioAccountSID == "" || c.TwilioAuthToken == "" || c.TwilioFrom == "" {
+			return fmt.Errorf("SMS_PROVIDER=twilio requires TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM")
+		}
+	case "msg91":
+		if c.MSG91AuthKey == "" || c.MSG91TemplateID == "" {
+			return fmt.Errorf("SMS_PROVIDER=msg91 requires MSG91_AUTH_KEY and MSG91_TEMPLATE_ID")
+		}
+	default:
+		return fmt.Errorf("SMS_PROVIDER must be twilio or msg91, got %q", c.SMSProvider)
+	}
+	if len(c.SMSTo) == 0 {
+		return fmt.Errorf("SMS_TO is required when SMS_PROVIDER is set")
+	}
+	for _, n := range c.SMSTo {
+		if !e164Re.MatchString(n) {
+			return fmt.Errorf("SMS_TO contains %q; numbers must be E.164, e.g. +919876543210", n)
+		}
+	}
+	if c.SMSCooldown < 0 {
+		return fmt.Errorf("SMS_COOLDOWN cannot be negative, got %v", c.SMSCooldown)
+	}
 	return nil
 }
 
+func (c *Config) validateMQTT() error {
+	if c.MQTTBrokerURL == "" {
+		return nil
+	}
+	u, err := url.Parse(c.MQTTBrokerURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("MQTT_BROKER_URL is not a valid URL: %q", c.MQTTBrokerURL)
+	}
+	switch u.Scheme {
+	case "tcp", "mqtt", "ssl", "tls", "mqtts", "ws", "wss":
+	default:
+		return fmt.Errorf("MQTT_BROKER_URL must use tcp://, ssl:// or ws:// (or mqtt, mqtts, tls, wss), got %q", c.MQTTBrokerURL)
+	}
+	if c.MQTTTopicPrefix == "" || strings.ContainsAny(c.MQTTTopicPrefix, "+#") {
+		return fmt.Errorf("MQTT_TOPIC_PREFIX must be non-empty and free of + and # wildcards, got %q", c.MQTTTopicPrefix)
+	}
+	if c.MQTTQoS < 0 || c.MQTTQoS > 2 {
+		return fmt.Errorf("MQTT_QOS must be 0, 1 or 2, got %d", c.MQTTQoS)
+	}
+	return nil
+}
+
+// validateCategoryRoutes checks TELEGRAM_CATEGORY_ROUTES names known
+// categories and chat IDs, with an optional positive topic ID.
+func (c *Config) validateCategoryRoutes() error {
+	for key, dest := range c.TelegramCategoryRoutes {
+		if !category.Known(key) {
+			return fmt.Errorf("TELEGRAM_CATEGORY_ROUTES: unknown category %q (known: %s)", key, strings.Join(category.Keys(), ", "))
+		}
+		chat, topic, hasTopic := strings.Cut(dest, ":")
+		if _, err := strconv.ParseInt(chat, 10, 64); err != nil && !strings.HasPrefix(chat, "@") {
+			return fmt.Errorf("TELEGRAM_CATEGORY_ROUTES: %s must route to a chat ID or @channel, got %q", key, dest)
+		}
+		if n, err := strconv.Atoi(topic); hasTopic && (err != nil || n <= 0) {
+			return fmt.Errorf("TELEGRAM_CATEGORY_ROUTES: %s topic must be a positive message thread ID, got %q", key, dest)
+		}
+	}
+	return nil
+}
+
+// e164Re matches an E.164 phone number.
+var e164Re = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
 // Helper functions for environment variable parsing
 
+// defaultInstanceID names this replica for leader election when
+// INSTANCE_ID is unset: host name and process ID, unique among replicas
+// sharing a directory.
+func defaultInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "cmon"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
 // getEnvOrDefault returns the environment variable value or a default if not set
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -339,6 +1341,149 @@ func parseScheduleList(raw string) []string {
 	return out
 }
 
+// knownNotifyChannels lists the channel names accepted in NOTIFY_CHANNELS.
+var knownNotifyChannels = map[string]bool{
+	"telegram": true,
+	"email":    true,
+	"webhook":  true,
+	"slack":    true,
+	"discord":  true,
+	"sms":      true,
+}
+
+// parseChannelList splits a comma-separated NOTIFY_CHANNELS value into
+// lowercase channel names. An empty input yields a nil slice.
+func parseChannelList(raw string) []string {
+	var out []string
+	for _, tok := range strings.Split(raw, ",") {
+		tok = strings.ToLower(strings.TrimSpace(tok))
+		if tok == "" {
+			continue
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+// knownBilingualFields lists the field names accepted in BILINGUAL_FIELDS.
+var knownBilingualFields = map[string]bool{
+	"name":        true,
+	"description": true,
+	"address":     true,
+}
+
+// parseBilingualFields splits BILINGUAL_FIELDS like NOTIFY_CHANNELS; "none"
+// turns the bilingual layout off.
+func parseBilingualFields(raw string) []string {
+	if strings.EqualFold(strings.TrimSpace(raw), "none") {
+		return nil
+	}
+	return parseChannelList(raw)
+}
+
+// parsePhoneList splits a comma-separated SMS_TO value, dropping spaces
+// inside numbers ("+91 98765 43210"). Validate checks each entry.
+func parsePhoneList(raw string) []string {
+	var out []string
+	for _, tok := range strings.Split(raw, ",") {
+		tok = strings.Join(strings.Fields(tok), "")
+		if tok == "" {
+			continue
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+// parseURLList splits a comma-separated WEBHOOK_URLS (or
+// COMPLAINT_STATUSES, HOOK_PLUGINS) value. Validate checks each entry. An
+// empty input yields a nil slice.
+func parseURLList(raw string) []string {
+	var out []string
+	for _, tok := range strings.Split(raw, ",") {
+		tok = strings.TrimSpace(tok)
+		if tok == "" {
+			continue
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+// parsePinList splits a comma-separated TLS_PINNED_SHA256 value. Pins are
+// base64, so case is kept. An empty input yields a nil slice.
+func parsePinList(raw string) []string {
+	var out []string
+	for _, tok := range strings.Split(raw, ",") {
+		tok = strings.TrimSpace(tok)
+		if tok == "" {
+			continue
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+// parseUserList splits a comma-separated TELEGRAM_ADMINS value into
+// lower-cased "@username" or numeric-ID entries; a bare username gets its
+// "@". An empty input yields a nil slice.
+func parseUserList(raw string) []string {
+	var out []string
+	for _, tok := range strings.Split(raw, ",") {
+		tok = strings.ToLower(strings.TrimSpace(tok))
+		if tok == "" {
+			continue
+		}
+		if _, err := strconv.ParseInt(tok, 10, 64); err != nil && !strings.HasPrefix(tok, "@") {
+			tok = "@" + tok
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+// parseRemarkList splits a "|"-separated RESOLUTION_REMARKS value. An
+// empty input yields a nil slice.
+func parseRemarkList(raw string) []string {
+	var out []string
+	for _, tok := range strings.Split(raw, "|") {
+		tok = strings.TrimSpace(tok)
+		if tok == "" {
+			continue
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+// parseKeywordList splits a comma-separated QUIET_HOURS_URGENT value into
+// lower-cased keywords. An empty input yields a nil slice.
+func parseKeywordList(raw string) []string {
+	var out []string
+	for _, tok := range strings.Split(raw, ",") {
+		tok = strings.ToLower(strings.TrimSpace(tok))
+		if tok == "" {
+			continue
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+// parseEmailList splits a comma-separated EMAIL_TO value. Entries without
+// an "@" are dropped. An empty input yields a nil slice.
+func parseEmailList(raw string) []string {
+	var out []string
+	for _, tok := range strings.Split(raw, ",") {
+		tok = strings.TrimSpace(tok)
+		if !strings.Contains(tok, "@") {
+			continue
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
 // validHHMM checks the 24-hour HH:MM format. Strictly two digits for both
 // fields so "9:5" doesn't smuggle in an off-by-an-hour misinterpretation.
 func validHHMM(s string) bool {
@@ -356,6 +1501,82 @@ func validHHMM(s string) bool {
 	return true
 }
 
+// loopback reports whether a listen host only accepts local connections:
+// "localhost" or a loopback IP. An empty host listens on every interface.
+func loopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// WeeklyReportTime parses WeeklyReport into its weekday and HH:MM; ok is
+// false when it is empty or malformed. Weekdays are English names or their
+// three-letter abbreviations, in any case.
+func (c *Config) WeeklyReportTime() (day time.Weekday, hhmm string, ok bool) {
+	fields := strings.Fields(c.WeeklyReport)
+	if len(fields) != 2 || !validHHMM(fields[1]) {
+		return 0, "", false
+	}
+	name := strings.ToLower(fields[0])
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToLower(d.String())
+		if name == full || name == full[:3] {
+			return d, fields[1], true
+		}
+	}
+	return 0, "", false
+}
+
+// SLATargetOverrides parses SLATargets into targets by category key
+// (lower-cased); an unknown category is an error. An empty SLATargets
+// yields a nil map.
+func (c *Config) SLATargetOverrides() (map[string]time.Duration, error) {
+	var out map[string]time.Duration
+	for _, tok := range strings.Split(c.SLATargets, ",") {
+		if tok = strings.TrimSpace(tok); tok == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(tok, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		d, err := time.ParseDuration(strings.TrimSpace(val))
+		if !ok || key == "" || err != nil || d <= 0 {
+			return nil, fmt.Errorf("SLA_TARGETS entries must be category=duration like \"no_supply=4h\", got %q", tok)
+		}
+		if !category.Known(key) {
+			return nil, fmt.Errorf("SLA_TARGETS: unknown category %q (known: %s)", key, strings.Join(category.Keys(), ", "))
+		}
+		if out == nil {
+			out = make(map[string]time.Duration)
+		}
+		out[key] = d
+	}
+	return out, nil
+}
+
+// QuietHoursRange splits QuietHours into its start and end HH:MM; ok is
+// false when it is empty, malformed, or starts and ends at the same time.
+func (c *Config) QuietHoursRange() (start, end string, ok bool) {
+	start, end, found := strings.Cut(c.QuietHours, "-")
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	if !found || !validHHMM(start) || !validHHMM(end) || start == end {
+		return "", "", false
+	}
+	return start, end, true
+}
+
+// Location loads Timezone; an empty one is UTC, as with time.LoadLocation.
+// Validate has already rejected unknown names, so the UTC fallback on
+// error only guards configs that skipped it.
+func (c *Config) Location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
 // getEnvDuration returns the environment variable as a duration or a default if not set/invalid.
 //
 // Accepts standard Go duration strings like "5s", "10m", "1h30m"
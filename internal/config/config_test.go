@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -127,6 +130,7 @@ func TestValidateRejectsMissingFields(t *testing.T) {
 			ComplaintURL:   "https://x/dash",
 			MaxPages:       5,
 			WorkerPoolSize: 10,
+			WorkerPoolMin:  1,
 		}
 	}
 
@@ -170,6 +174,24 @@ func TestValidateRejectsMissingFields(t *testing.T) {
 		}
 	})
 
+	t.Run("non-numeric complaint status errors", func(t *testing.T) {
+		c := good()
+		c.ComplaintStatuses = []string{"2", "assigned"}
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "COMPLAINT_STATUSES") {
+			t.Errorf("COMPLAINT_STATUSES=2,assigned should error mentioning COMPLAINT_STATUSES; got %v", err)
+		}
+	})
+
+	t.Run("negative age threshold errors", func(t *testing.T) {
+		c := good()
+		c.OverdueAfter = -time.Hour
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "AGE_OVERDUE_AFTER") {
+			t.Errorf("OverdueAfter=-1h should error mentioning AGE_OVERDUE_AFTER; got %v", err)
+		}
+	})
+
 	t.Run("zero max pages errors", func(t *testing.T) {
 		c := good()
 		c.MaxPages = 0
@@ -187,6 +209,15 @@ func TestValidateRejectsMissingFields(t *testing.T) {
 		}
 	})
 
+	t.Run("negative resolve confirmations errors", func(t *testing.T) {
+		c := good()
+		c.ResolveConfirmations = -1
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "RESOLVE_CONFIRMATIONS") {
+			t.Errorf("ResolveConfirmations=-1 should error mentioning RESOLVE_CONFIRMATIONS; got %v", err)
+		}
+	})
+
 	t.Run("zero worker pool errors", func(t *testing.T) {
 		c := good()
 		c.WorkerPoolSize = 0
@@ -195,6 +226,602 @@ func TestValidateRejectsMissingFields(t *testing.T) {
 			t.Errorf("WorkerPoolSize=0 should error mentioning WORKER_POOL_SIZE; got %v", err)
 		}
 	})
+
+	t.Run("unknown pagination errors", func(t *testing.T) {
+		c := good()
+		c.Pagination = "scroll"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "PORTAL_PAGINATION") {
+			t.Errorf("Pagination=scroll should error mentioning PORTAL_PAGINATION; got %v", err)
+		}
+	})
+
+	t.Run("invalid ignore pattern errors", func(t *testing.T) {
+		c := good()
+		c.IgnoreDescription = "test("
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "IGNORE_DESCRIPTION_REGEX") {
+			t.Errorf("IgnoreDescription=%q should error mentioning IGNORE_DESCRIPTION_REGEX; got %v", c.IgnoreDescription, err)
+		}
+	})
+
+	t.Run("negative record cache TTL errors", func(t *testing.T) {
+		c := good()
+		c.RecordCacheTTL = -time.Second
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "RECORD_CACHE_TTL") {
+			t.Errorf("RecordCacheTTL<0 should error mentioning RECORD_CACHE_TTL; got %v", err)
+		}
+	})
+
+	t.Run("zero translation rate errors with a Gemini key", func(t *testing.T) {
+		c := good()
+		c.GeminiAPIKey = "key"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "GEMINI_TRANSLATE_RPM") {
+			t.Errorf("GeminiTranslateRPM=0 should error mentioning GEMINI_TRANSLATE_RPM; got %v", err)
+		}
+	})
+
+	t.Run("worker pool minimum above size errors", func(t *testing.T) {
+		c := good()
+		c.WorkerPoolMin = 11
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "WORKER_POOL_MIN") {
+			t.Errorf("WorkerPoolMin > WorkerPoolSize should error mentioning WORKER_POOL_MIN; got %v", err)
+		}
+	})
+
+	t.Run("outage clustering without window errors", func(t *testing.T) {
+		c := good()
+		c.OutageClusterThreshold = 5
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "OUTAGE_CLUSTER_WINDOW") {
+			t.Errorf("threshold without window should error mentioning OUTAGE_CLUSTER_WINDOW; got %v", err)
+		}
+	})
+
+	t.Run("unknown geocode provider errors", func(t *testing.T) {
+		c := good()
+		c.GeocodeProvider = "google"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "GEOCODE_PROVIDER") {
+			t.Errorf("unknown provider should error mentioning GEOCODE_PROVIDER; got %v", err)
+		}
+	})
+
+	t.Run("unknown initial import mode errors", func(t *testing.T) {
+		c := good()
+		c.InitialImport = "quiet"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "INITIAL_IMPORT") {
+			t.Errorf("unknown mode should error mentioning INITIAL_IMPORT; got %v", err)
+		}
+	})
+
+	t.Run("unknown notify channel errors", func(t *testing.T) {
+		c := good()
+		c.NotifyChannels = []string{"telegram", "pager"}
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "NOTIFY_CHANNELS") {
+			t.Errorf("unknown channel should error mentioning NOTIFY_CHANNELS; got %v", err)
+		}
+	})
+
+	t.Run("unknown bilingual field errors", func(t *testing.T) {
+		c := good()
+		c.BilingualFields = []string{"name", "mobile"}
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "BILINGUAL_FIELDS") {
+			t.Errorf("unknown field should error mentioning BILINGUAL_FIELDS; got %v", err)
+		}
+	})
+
+	t.Run("email without sender errors", func(t *testing.T) {
+		c := good()
+		c.EmailSMTPHost = "smtp.example.com"
+		c.EmailSMTPPort = 587
+		c.EmailTo = []string{"ops@dgvcl.in"}
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "EMAIL_FROM") {
+			t.Errorf("email without sender should error mentioning EMAIL_FROM; got %v", err)
+		}
+	})
+
+	t.Run("invalid webhook URL errors", func(t *testing.T) {
+		c := good()
+		c.WebhookURLs = []string{"https://hooks.example.com/cmon", "ftp://example.com"}
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "WEBHOOK_URLS") {
+			t.Errorf("non-http webhook URL should error mentioning WEBHOOK_URLS; got %v", err)
+		}
+	})
+
+	t.Run("malformed Discord public key errors", func(t *testing.T) {
+		c := good()
+		c.DiscordPublicKey = "not-hex"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "DISCORD_PUBLIC_KEY") {
+			t.Errorf("bad Discord key should error mentioning DISCORD_PUBLIC_KEY; got %v", err)
+		}
+	})
+
+	t.Run("SMS provider needs credentials and E.164 numbers", func(t *testing.T) {
+		c := good()
+		c.SMSProvider = "twilio"
+		c.SMSTo = []string{"+919876543210"}
+		if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "TWILIO_ACCOUNT_SID") {
+			t.Errorf("twilio without credentials should error mentioning TWILIO_ACCOUNT_SID; got %v", err)
+		}
+		c.TwilioAccountSID, c.TwilioAuthToken, c.TwilioFrom = "AC1", "tok", "+15005550006"
+		c.SMSTo = []string{"9876543210"}
+		if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "SMS_TO") {
+			t.Errorf("number without country code should error mentioning SMS_TO; got %v", err)
+		}
+	})
+
+	t.Run("shard index out of range errors", func(t *testing.T) {
+		c := good()
+		c.ShardCount = 3
+		c.ShardIndex = 3
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "SHARD_INDEX") {
+			t.Errorf("index == count should error mentioning SHARD_INDEX; got %v", err)
+		}
+	})
+
+	t.Run("negative history retention errors", func(t *testing.T) {
+		c := good()
+		c.HistoryRetentionDays = -1
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "HISTORY_RETENTION_DAYS") {
+			t.Errorf("negative retention should error mentioning HISTORY_RETENTION_DAYS; got %v", err)
+		}
+	})
+
+	t.Run("negative batch size errors", func(t *testing.T) {
+		c := good()
+		c.BatchSize = -1
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "BATCH_SIZE") {
+			t.Errorf("negative batch size should error mentioning BATCH_SIZE; got %v", err)
+		}
+	})
+
+	t.Run("negative flush interval errors", func(t *testing.T) {
+		c := good()
+		c.FlushInterval = -time.Second
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "FLUSH_INTERVAL") {
+			t.Errorf("negative interval should error mentioning FLUSH_INTERVAL; got %v", err)
+		}
+	})
+
+	t.Run("insecure TLS with a CA bundle errors", func(t *testing.T) {
+		c := good()
+		c.InsecureTLS = true
+		c.TLSCAFile = "/etc/ssl/dgvcl.pem"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "INSECURE_TLS") {
+			t.Errorf("INSECURE_TLS with TLS_CA_FILE should error mentioning INSECURE_TLS; got %v", err)
+		}
+	})
+
+	t.Run("unsupported proxy scheme errors", func(t *testing.T) {
+		c := good()
+		c.ProxyURL = "ftp://proxy.office:21"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "PROXY_URL") {
+			t.Errorf("ftp proxy should error mentioning PROXY_URL; got %v", err)
+		}
+	})
+
+	t.Run("proxy bypass without proxy errors", func(t *testing.T) {
+		c := good()
+		c.ProxyBypass = []string{"localhost"}
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "PROXY_BYPASS") {
+			t.Errorf("PROXY_BYPASS alone should error mentioning PROXY_BYPASS; got %v", err)
+		}
+	})
+
+	t.Run("negative keep-alive interval errors", func(t *testing.T) {
+		c := good()
+		c.SessionKeepAlive = -time.Minute
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "SESSION_KEEPALIVE_INTERVAL") {
+			t.Errorf("negative interval should error mentioning SESSION_KEEPALIVE_INTERVAL; got %v", err)
+		}
+	})
+
+	t.Run("SESSION_RELOGIN_MARGIN must be shorter than SESSION_TTL", func(t *testing.T) {
+		c := good()
+		c.SessionTTL = 2 * time.Hour
+		c.SessionReloginMargin = 5 * time.Minute
+		if err := c.Validate(); err != nil {
+			t.Errorf("5m margin on a 2h TTL should validate; got %v", err)
+		}
+		c.SessionReloginMargin = 2 * time.Hour
+		if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "SESSION_RELOGIN_MARGIN") {
+			t.Errorf("margin equal to the TTL should error mentioning SESSION_RELOGIN_MARGIN; got %v", err)
+		}
+		c.SessionTTL = -time.Hour
+		if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "SESSION_TTL") {
+			t.Errorf("negative TTL should error mentioning SESSION_TTL; got %v", err)
+		}
+	})
+
+	t.Run("negative backup interval errors", func(t *testing.T) {
+		c := good()
+		c.BackupInterval = -time.Hour
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "BACKUP_INTERVAL") {
+			t.Errorf("negative interval should error mentioning BACKUP_INTERVAL; got %v", err)
+		}
+	})
+
+	t.Run("negative backup keep errors", func(t *testing.T) {
+		c := good()
+		c.BackupKeep = -1
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "BACKUP_KEEP") {
+			t.Errorf("negative keep should error mentioning BACKUP_KEEP; got %v", err)
+		}
+	})
+
+	t.Run("storage encryption key must be 32 hex bytes", func(t *testing.T) {
+		c := good()
+		c.StorageEncryptionKey = "abcd"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "STORAGE_ENCRYPTION_KEY") {
+			t.Errorf("short key should error mentioning STORAGE_ENCRYPTION_KEY; got %v", err)
+		}
+	})
+
+	t.Run("storage encryption key from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "key")
+		os.WriteFile(path, []byte(strings.Repeat("ab", 32)+"\n"), 0o600)
+		c := good()
+		c.StorageEncryptionKeyFile = path
+		if err := c.Validate(); err != nil {
+			t.Fatalf("Validate: %v", err)
+		}
+		if key, _ := c.EncryptionKey(); len(key) != 32 || key[0] != 0xab {
+			t.Errorf("key: got %x", key)
+		}
+	})
+
+	t.Run("captcha fallback needs a timeout", func(t *testing.T) {
+		c := good()
+		c.CaptchaHumanAfter = 3
+		c.CaptchaHumanTimeout = 0
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "CAPTCHA_HUMAN_TIMEOUT") {
+			t.Errorf("zero timeout with fallback enabled should error mentioning CAPTCHA_HUMAN_TIMEOUT; got %v", err)
+		}
+	})
+
+	t.Run("log file needs a positive max size", func(t *testing.T) {
+		c := good()
+		c.LogFile = "logs/cmon.log"
+		c.LogMaxSizeMB = 0
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "LOG_MAX_SIZE") {
+			t.Errorf("zero size with LOG_FILE should error mentioning LOG_MAX_SIZE; got %v", err)
+		}
+	})
+
+	t.Run("negative alert reminder interval errors", func(t *testing.T) {
+		c := good()
+		c.AlertReminderInterval = -time.Minute
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "ALERT_REMINDER_INTERVAL") {
+			t.Errorf("negative interval should error mentioning ALERT_REMINDER_INTERVAL; got %v", err)
+		}
+	})
+
+	t.Run("negative health max fetch age errors", func(t *testing.T) {
+		c := good()
+		c.HealthMaxFetchAge = -time.Minute
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "HEALTH_MAX_FETCH_AGE") {
+			t.Errorf("negative age should error mentioning HEALTH_MAX_FETCH_AGE; got %v", err)
+		}
+	})
+
+	t.Run("malformed weekly report time errors", func(t *testing.T) {
+		c := good()
+		c.WeeklyReport = "Funday 09:00"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "WEEKLY_REPORT") {
+			t.Errorf("WeeklyReport=Funday should error mentioning WEEKLY_REPORT; got %v", err)
+		}
+	})
+
+	t.Run("malformed roll-call time errors", func(t *testing.T) {
+		c := good()
+		c.RollCallTime = "9am"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "ROLL_CALL_TIME") {
+			t.Errorf("RollCallTime=9am should error mentioning ROLL_CALL_TIME; got %v", err)
+		}
+	})
+
+	t.Run("malformed quiet hours error", func(t *testing.T) {
+		for _, v := range []string{"22:00", "10pm-7am", "22:00-22:00"} {
+			c := good()
+			c.QuietHours = v
+			err := c.Validate()
+			if err == nil || !strings.Contains(err.Error(), "QUIET_HOURS") {
+				t.Errorf("QuietHours=%q should error mentioning QUIET_HOURS; got %v", v, err)
+			}
+		}
+		c := good()
+		c.QuietHours = "22:00 - 07:00"
+		if start, end, ok := c.QuietHoursRange(); !ok || start != "22:00" || end != "07:00" {
+			t.Errorf("QuietHoursRange = %q, %q, %v; want 22:00, 07:00", start, end, ok)
+		}
+	})
+
+	t.Run("negative summary max rows errors", func(t *testing.T) {
+		c := good()
+		c.SummaryMaxRows = -1
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "SUMMARY_MAX_ROWS") {
+			t.Errorf("SummaryMaxRows=-1 should error mentioning SUMMARY_MAX_ROWS; got %v", err)
+		}
+	})
+
+	t.Run("unknown summary grouping errors", func(t *testing.T) {
+		c := good()
+		c.SummaryGroupBy = "feeder"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "SUMMARY_GROUP_BY") {
+			t.Errorf("SummaryGroupBy=feeder should error mentioning SUMMARY_GROUP_BY; got %v", err)
+		}
+	})
+
+	t.Run("feeder summary grouping needs a feeder map", func(t *testing.T) {
+		c := good()
+		c.SummaryGroupBy = "feeder"
+		c.FeederMapFile = "feeders.txt"
+		if err := c.Validate(); err != nil {
+			t.Errorf("SummaryGroupBy=feeder with FEEDER_MAP_FILE should be valid; got %v", err)
+		}
+	})
+
+	t.Run("unknown bot language errors", func(t *testing.T) {
+		c := good()
+		c.BotLanguage = "fr"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "BOT_LANGUAGE") {
+			t.Errorf("BotLanguage=fr should error mentioning BOT_LANGUAGE; got %v", err)
+		}
+	})
+
+	t.Run("unknown TZ_OVERRIDE errors", func(t *testing.T) {
+		c := good()
+		c.Timezone = "Mars/Olympus_Mons"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "TZ_OVERRIDE") {
+			t.Errorf("Timezone=Mars/Olympus_Mons should error mentioning TZ_OVERRIDE; got %v", err)
+		}
+	})
+
+	t.Run("negative TELEGRAM_CLEANUP_DAYS errors", func(t *testing.T) {
+		c := good()
+		c.TelegramCleanupDays = -1
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "TELEGRAM_CLEANUP_DAYS") {
+			t.Errorf("TelegramCleanupDays=-1 should error mentioning TELEGRAM_CLEANUP_DAYS; got %v", err)
+		}
+	})
+
+	t.Run("unknown TELEGRAM_CLEANUP_ACTION errors", func(t *testing.T) {
+		c := good()
+		c.TelegramCleanupAction = "archive"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "TELEGRAM_CLEANUP_ACTION") {
+			t.Errorf("TelegramCleanupAction=archive should error mentioning TELEGRAM_CLEANUP_ACTION; got %v", err)
+		}
+	})
+
+	t.Run("TELEGRAM_CLEANUP_DAYS must come before history retention", func(t *testing.T) {
+		c := good()
+		c.TelegramCleanupDays, c.HistoryRetentionDays, c.RetentionExportDir = 90, 90, "exports"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "TELEGRAM_CLEANUP_DAYS") {
+			t.Errorf("cleanup at 90 days with retention at 90 should error mentioning TELEGRAM_CLEANUP_DAYS; got %v", err)
+		}
+		c.TelegramCleanupDays = 30
+		if err := c.Validate(); err != nil {
+			t.Errorf("cleanup at 30 days with retention at 90: %v", err)
+		}
+	})
+
+	t.Run("short LEADER_LEASE_TTL errors when election is on", func(t *testing.T) {
+		c := good()
+		c.LeaderLeaseTTL = time.Second
+		if err := c.Validate(); err != nil {
+			t.Errorf("TTL is ignored with election off; got %v", err)
+		}
+		c.LeaderElection, c.InstanceID = true, "a"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "LEADER_LEASE_TTL") {
+			t.Errorf("LeaderLeaseTTL=1s should error mentioning LEADER_LEASE_TTL; got %v", err)
+		}
+	})
+
+	t.Run("leader election needs an INSTANCE_ID", func(t *testing.T) {
+		c := good()
+		c.LeaderElection, c.LeaderLeaseTTL = true, 30*time.Second
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "INSTANCE_ID") {
+			t.Errorf("empty InstanceID should error mentioning INSTANCE_ID; got %v", err)
+		}
+	})
+
+	t.Run("HOOK_PLUGINS entries must be .so files", func(t *testing.T) {
+		c := good()
+		c.HookPlugins = []string{"/opt/cmon/erp.so", "/opt/cmon/erp.go"}
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "erp.go") {
+			t.Errorf("a non-.so HOOK_PLUGINS entry should error naming it; got %v", err)
+		}
+	})
+
+	t.Run("GRPC_ADDR must include a port", func(t *testing.T) {
+		c := good()
+		c.GRPCAddr = "localhost"
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "GRPC_ADDR") {
+			t.Errorf("GRPCAddr without a port should error mentioning GRPC_ADDR; got %v", err)
+		}
+		c.GRPCAddr = ":9090"
+		c.GRPCToken = "s3cret"
+		if err := c.Validate(); err != nil {
+			t.Errorf("GRPCAddr :9090 should be valid; got %v", err)
+		}
+	})
+
+	t.Run("GRPC_ADDR without a token must be loopback", func(t *testing.T) {
+		c := good()
+		for _, addr := range []string{":9090", "0.0.0.0:9090", "10.0.0.5:9090"} {
+			c.GRPCAddr = addr
+			if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "GRPC_TOKEN") {
+				t.Errorf("GRPCAddr %s without a token should error mentioning GRPC_TOKEN; got %v", addr, err)
+			}
+		}
+		for _, addr := range []string{"127.0.0.1:9090", "localhost:9090", "[::1]:9090"} {
+			c.GRPCAddr = addr
+			if err := c.Validate(); err != nil {
+				t.Errorf("GRPCAddr %s without a token should be valid; got %v", addr, err)
+			}
+		}
+	})
+
+	t.Run("MQTT broker URL, topic prefix and QoS are checked", func(t *testing.T) {
+		c := good()
+		c.MQTTBrokerURL = "http://broker:1883"
+		c.MQTTTopicPrefix = "cmon"
+		c.MQTTQoS = 1
+		if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "MQTT_BROKER_URL") {
+			t.Errorf("http:// broker should error mentioning MQTT_BROKER_URL; got %v", err)
+		}
+		c.MQTTBrokerURL = "tcp://broker:1883"
+		if err := c.Validate(); err != nil {
+			t.Errorf("tcp:// broker should be valid; got %v", err)
+		}
+		c.MQTTTopicPrefix = "cmon/#"
+		if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "MQTT_TOPIC_PREFIX") {
+			t.Errorf("wildcard topic prefix should error mentioning MQTT_TOPIC_PREFIX; got %v", err)
+		}
+		c.MQTTTopicPrefix = "cmon"
+		c.MQTTQoS = 3
+		if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "MQTT_QOS") {
+			t.Errorf("QoS 3 should error mentioning MQTT_QOS; got %v", err)
+		}
+	})
+
+	t.Run("SLA_TARGETS is category=duration pairs", func(t *testing.T) {
+		c := good()
+		c.SLATargets = "No_Supply=4h, transformer=24h"
+		if err := c.Validate(); err != nil {
+			t.Fatalf("valid SLA_TARGETS rejected: %v", err)
+		}
+		got, _ := c.SLATargetOverrides()
+		if len(got) != 2 || got["no_supply"] != 4*time.Hour || got["transformer"] != 24*time.Hour {
+			t.Errorf("SLATargetOverrides = %v", got)
+		}
+		for _, bad := range []string{"no_supply", "no_supply=soon", "=4h", "meter=-1h", "sparks=1h"} {
+			c.SLATargets = bad
+			if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "SLA_TARGETS") {
+				t.Errorf("SLA_TARGETS=%q should error mentioning SLA_TARGETS; got %v", bad, err)
+			}
+		}
+	})
+
+	t.Run("TELEGRAM_CATEGORY_ROUTES needs known categories and chat IDs", func(t *testing.T) {
+		c := good()
+		c.TelegramCategoryRoutes = map[string]string{"billing": "-1001234", "meter": "-1005678:7", "line": "@linecrew"}
+		if err := c.Validate(); err != nil {
+			t.Fatalf("valid routes rejected: %v", err)
+		}
+		for _, bad := range []map[string]string{
+			{"sparks": "-1001234"},
+			{"billing": "accounts"},
+			{"billing": "-1001234:general"},
+			{"billing": "-1001234:0"},
+		} {
+			c.TelegramCategoryRoutes = bad
+			if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "TELEGRAM_CATEGORY_ROUTES") {
+				t.Errorf("routes %v should error mentioning TELEGRAM_CATEGORY_ROUTES; got %v", bad, err)
+			}
+		}
+	})
+
+	t.Run("negative UPDATE_CHECK_INTERVAL errors", func(t *testing.T) {
+		c := good()
+		c.UpdateCheckInterval = -time.Hour
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "UPDATE_CHECK_INTERVAL") {
+			t.Errorf("UpdateCheckInterval=-1h should error mentioning UPDATE_CHECK_INTERVAL; got %v", err)
+		}
+	})
+
+	t.Run("malformed UPDATE_REPO errors when checks are on", func(t *testing.T) {
+		for _, v := range []string{"", "cmon", "/cmon", "owner/", "a/b/c"} {
+			c := good()
+			c.UpdateCheckInterval = 24 * time.Hour
+			c.UpdateRepo = v
+			err := c.Validate()
+			if err == nil || !strings.Contains(err.Error(), "UPDATE_REPO") {
+				t.Errorf("UpdateRepo=%q should error mentioning UPDATE_REPO; got %v", v, err)
+			}
+		}
+		c := good()
+		c.UpdateCheckInterval = 24 * time.Hour
+		c.UpdateRepo = "Satyam085/cmon"
+		if err := c.Validate(); err != nil {
+			t.Errorf("UpdateRepo=Satyam085/cmon: %v", err)
+		}
+	})
+
+	t.Run("negative digest threshold errors", func(t *testing.T) {
+		c := good()
+		c.TelegramDigestThreshold = -1
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "TELEGRAM_DIGEST_THRESHOLD") {
+			t.Errorf("negative threshold should error mentioning TELEGRAM_DIGEST_THRESHOLD; got %v", err)
+		}
+	})
+
+	t.Run("negative undo window errors", func(t *testing.T) {
+		c := good()
+		c.ResolveUndoWindow = -time.Second
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "RESOLVE_UNDO_WINDOW") {
+			t.Errorf("negative window should error mentioning RESOLVE_UNDO_WINDOW; got %v", err)
+		}
+	})
+
+	t.Run("negative conversation timeout errors", func(t *testing.T) {
+		c := good()
+		c.ConversationTimeout = -time.Minute
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "CONVERSATION_TIMEOUT") {
+			t.Errorf("negative timeout should error mentioning CONVERSATION_TIMEOUT; got %v", err)
+		}
+	})
+
+	t.Run("negative unseen reminder delay errors", func(t *testing.T) {
+		c := good()
+		c.UnseenReminderDelay = -time.Minute
+		err := c.Validate()
+		if err == nil || !strings.Contains(err.Error(), "UNSEEN_REMINDER_DELAY") {
+			t.Errorf("negative delay should error mentioning UNSEEN_REMINDER_DELAY; got %v", err)
+		}
+	})
 }
 
 // TestLoadConfigEnvOverridesEmbedded covers the env-var precedence rule: a
@@ -211,6 +838,8 @@ func TestLoadConfigEnvOverridesEmbedded(t *testing.T) {
 	t.Setenv("API_RATE_LIMIT_RPS", "0.5")
 	t.Setenv("WHATSAPP_RESOLVE_ENABLED", "true")
 	t.Setenv("LOG_FORMAT", "json")
+	t.Setenv("DATA_DIR", "/var/lib/cmon")
+	t.Setenv("LEADER_LEASE_FILE", "")
 
 	cfg, err := LoadConfig()
 	if err != nil {
@@ -235,6 +864,9 @@ func TestLoadConfigEnvOverridesEmbedded(t *testing.T) {
 	if cfg.FetchInterval != 7*time.Minute {
 		t.Errorf("FetchInterval: got %s, want 7m", cfg.FetchInterval)
 	}
+	if cfg.HealthMaxFetchAge != 21*time.Minute {
+		t.Errorf("HealthMaxFetchAge: got %s, want three fetch intervals (21m)", cfg.HealthMaxFetchAge)
+	}
 	if cfg.APIRateLimitRPS != 0.5 {
 		t.Errorf("APIRateLimitRPS: got %v, want 0.5", cfg.APIRateLimitRPS)
 	}
@@ -244,6 +876,9 @@ func TestLoadConfigEnvOverridesEmbedded(t *testing.T) {
 	if cfg.LogFormat != "json" {
 		t.Errorf("LogFormat: got %q, want json", cfg.LogFormat)
 	}
+	if cfg.LeaderLeaseFile != filepath.Join("/var/lib/cmon", "cmon-leader.db") {
+		t.Errorf("LeaderLeaseFile: got %q, want it under DATA_DIR", cfg.LeaderLeaseFile)
+	}
 }
 
 // TestLoadConfigEmbeddedFallbackUsedWhenEnvUnset confirms the precedence
@@ -281,6 +916,9 @@ func TestLoadConfigHardcodedDefaultUsedWhenAbsentFromEnvAndEmbedded(t *testing.T
 	if cfg.LogFormat != "text" {
 		t.Errorf("LogFormat default: got %q, want text", cfg.LogFormat)
 	}
+	if cfg.DebugEndpoints {
+		t.Error("DebugEndpoints default: got true, want false (unauthenticated pprof)")
+	}
 }
 
 // TestLoadConfigInvalidDurationFallsBackToDefault verifies that a bad
@@ -301,6 +939,23 @@ func TestLoadConfigInvalidDurationFallsBackToDefault(t *testing.T) {
 	}
 }
 
+func TestComplaintViews(t *testing.T) {
+	c := &Config{ComplaintURL: "https://x/dash?coname=21&cStatus=2"}
+	if views := c.ComplaintViews(); len(views) != 1 || views[0] != (ComplaintView{URL: c.ComplaintURL}) {
+		t.Errorf("no statuses: %+v", views)
+	}
+
+	c.ComplaintStatuses = []string{"2", "3"}
+	views := c.ComplaintViews()
+	want := []ComplaintView{
+		{Status: "2", URL: "https://x/dash?cStatus=2&coname=21"},
+		{Status: "3", URL: "https://x/dash?cStatus=3&coname=21"},
+	}
+	if len(views) != len(want) || views[0] != want[0] || views[1] != want[1] {
+		t.Errorf("views = %+v, want %+v", views, want)
+	}
+}
+
 func TestParseBeltRoutes(t *testing.T) {
 	cases := []struct {
 		name string
@@ -382,6 +1037,90 @@ func TestParseScheduleList(t *testing.T) {
 	}
 }
 
+func TestWeeklyReportTime(t *testing.T) {
+	for raw, want := range map[string]string{
+		"Mon 09:00":        "Monday 09:00 true",
+		"friday 18:30":     "Friday 18:30 true",
+		"  SUN   07:05  ":  "Sunday 07:05 true",
+		"":                 "Sunday  false",
+		"Mon":              "Sunday  false",
+		"Mon 9:00":         "Sunday  false",
+		"Monday 09:00 IST": "Sunday  false",
+	} {
+		c := &Config{WeeklyReport: raw}
+		day, hhmm, ok := c.WeeklyReportTime()
+		if got := fmt.Sprintf("%s %s %v", day, hhmm, ok); got != want {
+			t.Errorf("WeeklyReportTime(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestLocation(t *testing.T) {
+	for raw, want := range map[string]string{
+		"Asia/Kolkata":     "Asia/Kolkata",
+		"America/New_York": "America/New_York",
+		"":                 "UTC",
+		"Nowhere/Special":  "UTC",
+	} {
+		c := &Config{Timezone: raw}
+		if got := c.Location().String(); got != want {
+			t.Errorf("Location() for %q = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestParseEmailList(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"single", "ops@dgvcl.in", []string{"ops@dgvcl.in"}},
+		{"two with spaces", " ops@dgvcl.in , je@dgvcl.in ", []string{"ops@dgvcl.in", "je@dgvcl.in"}},
+		{"drops token without @", "ops@dgvcl.in, nobody,,", []string{"ops@dgvcl.in"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parseEmailList(tc.in)
+			if len(got) != len(tc.want) {
+				t.Fatalf("len: got %d (%v), want %d (%v)", len(got), got, len(tc.want), tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("[%d]: got %q, want %q", i, got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseRemarkList(t *testing.T) {
+	got := parseRemarkList(" Fuse replaced | Line fault repaired, supply restored ||")
+	if len(got) != 2 || got[0] != "Fuse replaced" || got[1] != "Line fault repaired, supply restored" {
+		t.Errorf("got %q", got)
+	}
+	if parseRemarkList("") != nil {
+		t.Error("empty input should yield nil")
+	}
+}
+
+func TestParseUserList(t *testing.T) {
+	got := parseUserList(" @SDO_Valod, je_buhari ,123456789,, ")
+	want := []string{"@sdo_valod", "@je_buhari", "123456789"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("[%d]: got %q, want %q", i, got[i], want[i])
+		}
+	}
+	if parseUserList("") != nil {
+		t.Error("empty input should yield nil")
+	}
+}
+
 // TestLoadConfigBoolFlagOnlyTrueLiteral confirms WHATSAPP_RESOLVE_ENABLED is
 // strict "true" — anything other than that exact string is false. The
 // strictness is intentional: a flag that mutates external state should reject
@@ -0,0 +1,62 @@
+// Package crash keeps a panic in one long-running component — the fetch
+// loop, the Telegram and WhatsApp handlers, a complaint worker — from
+// taking the whole daemon down. A recovered panic is logged with its stack
+// and handed to the Reporter (main posts it to the admin chat); the
+// component then carries on, or package supervisor starts it again.
+package crash
+
+import (
+	"log"
+	"runtime/debug"
+	"sync"
+
+	"cmon/internal/metrics"
+)
+
+// Reporter is told about every recovered panic: the component it happened
+// in, the value passed to panic and the goroutine's stack.
+type Reporter func(component string, value any, stack []byte)
+
+var (
+	mu       sync.RWMutex
+	reporter Reporter
+)
+
+// SetReporter installs r as the destination for crash reports; nil (the
+// default) only logs them.
+func SetReporter(r Reporter) {
+	mu.Lock()
+	reporter = r
+	mu.Unlock()
+}
+
+// Report logs a recovered panic and passes it to the Reporter. Callers
+// that recover themselves use it; most should defer Recover instead.
+func Report(component string, value any, stack []byte) {
+	metrics.PanicsTotal.Inc()
+	log.Printf("💥 Panic in %s: %v\n%s", component, value, stack)
+
+	mu.RLock()
+	r := reporter
+	mu.RUnlock()
+	if r == nil {
+		return
+	}
+	// A failing reporter must not turn one panic into a crash.
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("⚠️  Crash reporter panicked: %v", rec)
+		}
+	}()
+	r(component, value, stack)
+}
+
+// Recover stops a panic in the calling function and reports it. It must be
+// deferred directly:
+//
+//	defer crash.Recover("telegram update")
+func Recover(component string) {
+	if rec := recover(); rec != nil {
+		Report(component, rec, debug.Stack())
+	}
+}
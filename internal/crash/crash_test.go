@@ -0,0 +1,35 @@
+package crash
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecoverReportsPanic(t *testing.T) {
+	var got []string
+	SetReporter(func(component string, value any, stack []byte) {
+		if !strings.Contains(string(stack), "crash.TestRecoverReportsPanic") {
+			t.Errorf("stack should include the panicking function:\n%s", stack)
+		}
+		got = append(got, component+": "+value.(string))
+	})
+	t.Cleanup(func() { SetReporter(nil) })
+
+	func() {
+		defer Recover("fetch loop")
+		panic("boom")
+	}()
+	if len(got) != 1 || got[0] != "fetch loop: boom" {
+		t.Errorf("reports = %v", got)
+	}
+}
+
+func TestRecoverSurvivesPanickingReporter(t *testing.T) {
+	SetReporter(func(string, any, []byte) { panic("reporter broke") })
+	t.Cleanup(func() { SetReporter(nil) })
+
+	func() {
+		defer Recover("fetch loop")
+		panic("boom")
+	}()
+}
@@ -5,7 +5,10 @@
 // about what went wrong and can be used for specific recovery strategies.
 package errors
 
-import "fmt"
+import (
+	stderrors "errors"
+	"fmt"
+)
 
 // SessionExpiredError indicates that the user session has expired and needs re-authentication.
 //
@@ -90,6 +93,86 @@ func NewFetchError(msg string, err error) *FetchError {
 	return &FetchError{Message: msg, Err: err}
 }
 
+// PortalUnavailableError indicates the DGVCL portal served a known error or
+// maintenance page instead of the requested content.
+//
+// This error is returned when:
+//   - The portal answers with HTTP 503 (Laravel maintenance mode)
+//   - A 5xx response body matches a known error-page signature
+//   - A 200 response is actually a maintenance / "server error" page
+//
+// Recovery strategy: none in-cycle. The outage is on the portal side, so
+// retrying or re-logging in only adds load; wait for the next fetch interval.
+type PortalUnavailableError struct {
+	Message    string
+	StatusCode int    // HTTP status of the offending response (0 if unknown)
+	Signature  string // which known signature matched, for logs and /health
+}
+
+func (e *PortalUnavailableError) Error() string {
+	if e.StatusCode > 0 {
+		return fmt.Sprintf("portal unavailable (HTTP %d, %s): %s", e.StatusCode, e.Signature, e.Message)
+	}
+	return fmt.Sprintf("portal unavailable (%s): %s", e.Signature, e.Message)
+}
+
+// NewPortalUnavailableError creates a new portal-unavailable error with context
+func NewPortalUnavailableError(msg string, statusCode int, signature string) *PortalUnavailableError {
+	return &PortalUnavailableError{Message: msg, StatusCode: statusCode, Signature: signature}
+}
+
+// IsPortalUnavailable reports whether err, or any error it wraps, is a
+// PortalUnavailableError. Unlike the other Is* helpers this walks the chain
+// because portal pages are detected deep in the session client and then
+// wrapped by FetchError / LoginFailedError on the way up.
+func IsPortalUnavailable(err error) bool {
+	var pe *PortalUnavailableError
+	return stderrors.As(err, &pe)
+}
+
+// AsPortalUnavailable returns the PortalUnavailableError in err's chain, or
+// nil if there is none.
+func AsPortalUnavailable(err error) *PortalUnavailableError {
+	var pe *PortalUnavailableError
+	if stderrors.As(err, &pe) {
+		return pe
+	}
+	return nil
+}
+
+// ScrapeSchemaError indicates a dashboard page loaded with its complaint
+// table, but no complaint links could be extracted although the portal
+// reports complaints — the table markup has most likely changed.
+//
+// Recovery strategy: none in-cycle. The page will parse the same way on a
+// retry; alert an operator with the page HTML and leave stored complaints
+// alone, since treating them as resolved would wipe them.
+type ScrapeSchemaError struct {
+	Message  string
+	Page     int    // dashboard page that failed to parse, 1-based
+	Reported int    // complaint count the page itself reports
+	HTML     []byte // the page, for debugging
+}
+
+func (e *ScrapeSchemaError) Error() string {
+	return fmt.Sprintf("dashboard markup not recognised on page %d (portal reports %d complaints, none parsed): %s", e.Page, e.Reported, e.Message)
+}
+
+// NewScrapeSchemaError creates a new scrape schema error with the page HTML
+func NewScrapeSchemaError(msg string, page, reported int, html []byte) *ScrapeSchemaError {
+	return &ScrapeSchemaError{Message: msg, Page: page, Reported: reported, HTML: html}
+}
+
+// AsScrapeSchema returns the ScrapeSchemaError in err's chain, or nil if
+// there is none.
+func AsScrapeSchema(err error) *ScrapeSchemaError {
+	var se *ScrapeSchemaError
+	if stderrors.As(err, &se) {
+		return se
+	}
+	return nil
+}
+
 // IsLoginFailed checks if the error is a login failure error
 func IsLoginFailed(err error) bool {
 	_, ok := err.(*LoginFailedError)
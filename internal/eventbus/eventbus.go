@@ -0,0 +1,226 @@
+// Package eventbus decouples what happened — a new complaint, a resolution,
+// an alert — from everything that reacts to it.
+//
+// The fetcher and the resolution flows publish typed events on a Bus; each
+// integration (the notification channels, WhatsApp, …) subscribes once at
+// startup. Adding an integration is then a Subscribe call in main rather
+// than another branch in the fetcher.
+//
+// Delivery is synchronous and in subscription order, so publishers keep
+// their ordering guarantees: a complaint is persisted before ComplaintNew
+// is published, and ComplaintResolved is published before the complaint
+// leaves storage, so subscribers can still look up what they stored.
+// Subscribers that are slow and need no storage (the webhook sink, with its
+// retries) use SubscribeAsync instead, which queues events on a channel.
+package eventbus
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"cmon/internal/metrics"
+	"cmon/internal/notify"
+)
+
+// Topics, as returned by Event.Topic.
+const (
+	TopicComplaintNew        = "complaint.new"
+	TopicComplaintResolved   = "complaint.resolved"
+	TopicComplaintTranslated = "complaint.translated"
+	TopicAlert               = "alert"
+	TopicFetchFailed         = "fetch.failed"
+)
+
+// Event is anything published on a Bus.
+type Event interface {
+	Topic() string
+}
+
+// ComplaintNew is published once a new complaint (scraped or registered
+// locally) has been persisted.
+type ComplaintNew struct {
+	Complaint notify.Complaint
+}
+
+// Topic implements Event.
+func (ComplaintNew) Topic() string { return TopicComplaintNew }
+
+// ComplaintsNew is published once per fetch cycle for the new complaints
+// it persisted, so channels can bundle a large batch (see
+// notify.BatchSender). It shares ComplaintNew's topic.
+type ComplaintsNew struct {
+	Complaints []notify.Complaint
+}
+
+// Topic implements Event.
+func (ComplaintsNew) Topic() string { return TopicComplaintNew }
+
+// ComplaintTranslated is published when the Gujarati translation of an
+// announced complaint is ready (or has been given up on, with the reason
+// in TranslationNote), for channels to edit the complaint's message or,
+// when they held it back, to send it now.
+type ComplaintTranslated struct {
+	Complaint notify.Complaint
+}
+
+// Topic implements Event.
+func (ComplaintTranslated) Topic() string { return TopicComplaintTranslated }
+
+// ComplaintResolved is published when a complaint is resolved, before it is
+// removed from storage.
+type ComplaintResolved struct {
+	Status notify.Status
+}
+
+// Topic implements Event.
+func (ComplaintResolved) Topic() string { return TopicComplaintResolved }
+
+// AlertRaised is published for operational alerts: critical failures,
+// portal down/up and detected outages.
+type AlertRaised struct {
+	Alert notify.Alert
+}
+
+// Topic implements Event.
+func (AlertRaised) Topic() string { return TopicAlert }
+
+// FetchFailed is published when a fetch cycle has used up its retries.
+// Silent cycles (triggered from the dashboard) are published too, so
+// subscribers that only count failures see them; alerting ones skip them.
+type FetchFailed struct {
+	Err      error
+	Attempts int
+	Silent   bool
+	Time     time.Time
+}
+
+// Topic implements Event.
+func (FetchFailed) Topic() string { return TopicFetchFailed }
+
+// Handler reacts to one event. Handlers switch on the concrete type and
+// ignore events they don't care about.
+type Handler func(Event) error
+
+type subscriber struct {
+	name string
+	h    Handler
+}
+
+// Bus fans published events out to its subscribers. The zero value is
+// ready to use, and a nil *Bus drops everything, so tests and partial
+// setups can pass nil.
+type Bus struct {
+	mu   sync.RWMutex
+	subs []subscriber
+}
+
+// New returns an empty Bus.
+func New() *Bus { return &Bus{} }
+
+// Subscribe adds h under name (used to label its errors). Subscribers are
+// called in the order they were added.
+func (b *Bus) Subscribe(name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, subscriber{name: name, h: h})
+}
+
+// Publish delivers e to every subscriber. One subscriber failing never
+// stops delivery to the rest; failures are joined into the returned error,
+// each prefixed with the subscriber's name.
+func (b *Bus) Publish(e Event) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.RLock()
+	subs := b.subs
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, s := range subs {
+		if err := s.h(e); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SubscribeAsync adds h under name like Subscribe, but delivers to it from
+// its own goroutine through a queue of up to buffer events, so a slow
+// subscriber never holds up the publisher. Events published while the
+// queue is full are dropped and counted. As Publish has returned by the
+// time h runs, h's errors are logged rather than returned.
+//
+// The returned stop takes the subscriber off the bus and waits for the
+// events already queued to be delivered.
+func (b *Bus) SubscribeAsync(name string, buffer int, h Handler) (stop func()) {
+	var (
+		mu     sync.Mutex
+		closed bool
+		queue  = make(chan Event, buffer)
+		done   = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		for e := range queue {
+			if err := h(e); err != nil {
+				log.Printf("⚠️  %s: %v", name, err)
+			}
+		}
+	}()
+	b.Subscribe(name, func(e Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return nil
+		}
+		select {
+		case queue <- e:
+		default:
+			metrics.EventsDroppedTotal.Inc()
+			log.Printf("⚠️  %s is falling behind; dropped a %s event", name, e.Topic())
+		}
+		return nil
+	})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			mu.Lock()
+			closed = true
+			close(queue)
+			mu.Unlock()
+			<-done
+		})
+	}
+}
+
+// Notify subscribes a notify.Notifier: new complaints are sent (or, on a
+// channel that cannot edit, held back until their translation is done),
+// translations and resolutions edit the earlier message and alerts are
+// forwarded. Failed fetch cycles go to channels that report each one
+// (notify.FetchFailureSender), unless Silent.
+func Notify(n notify.Notifier) Handler {
+	return func(e Event) error {
+		switch e := e.(type) {
+		case ComplaintNew:
+			return notify.SendComplaint(n, e.Complaint)
+		case ComplaintsNew:
+			return notify.SendComplaints(n, e.Complaints)
+		case ComplaintTranslated:
+			return notify.EditTranslation(n, e.Complaint)
+		case ComplaintResolved:
+			return n.EditStatus(e.Status)
+		case AlertRaised:
+			return n.SendAlert(e.Alert)
+		case FetchFailed:
+			if s, ok := n.(notify.FetchFailureSender); ok && !e.Silent {
+				return s.SendFetchFailure(e.Err, e.Attempts, e.Time)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,138 @@
+package eventbus
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"cmon/internal/metrics"
+	"cmon/internal/notify"
+)
+
+// recorder is a notify.Notifier that logs every call.
+type recorder struct{ calls []string }
+
+func (r *recorder) Name() string { return "rec" }
+func (r *recorder) SendComplaint(c notify.Complaint) error {
+	r.calls = append(r.calls, "complaint "+c.Number)
+	return nil
+}
+func (r *recorder) SendAlert(a notify.Alert) error {
+	r.calls = append(r.calls, "alert "+a.Title)
+	return nil
+}
+func (r *recorder) EditStatus(s notify.Status) error {
+	r.calls = append(r.calls, "status "+s.ComplaintID)
+	return nil
+}
+
+func TestNilBusDropsEvents(t *testing.T) {
+	var b *Bus
+	if err := b.Publish(ComplaintNew{}); err != nil {
+		t.Errorf("nil bus: %v", err)
+	}
+}
+
+func TestPublishReachesEverySubscriberInOrder(t *testing.T) {
+	b := New()
+	var order []string
+	b.Subscribe("first", func(e Event) error {
+		order = append(order, "first:"+e.Topic())
+		return errors.New("boom")
+	})
+	b.Subscribe("second", func(e Event) error {
+		order = append(order, "second:"+e.Topic())
+		return nil
+	})
+
+	err := b.Publish(AlertRaised{})
+	if err == nil || !strings.Contains(err.Error(), "first: boom") {
+		t.Errorf("error should name the failing subscriber; got %v", err)
+	}
+	if strings.Join(order, ",") != "first:alert,second:alert" {
+		t.Errorf("delivery order: %v", order)
+	}
+}
+
+func TestNotifyAdapter(t *testing.T) {
+	rec := &recorder{}
+	b := New()
+	b.Subscribe("notify", Notify(rec))
+
+	b.Publish(ComplaintNew{Complaint: notify.Complaint{Number: "1"}})
+	b.Publish(ComplaintsNew{Complaints: []notify.Complaint{{Number: "2"}, {Number: "3"}}})
+	b.Publish(ComplaintResolved{Status: notify.Status{ComplaintID: "1"}})
+	b.Publish(AlertRaised{Alert: notify.Alert{Title: "Portal down"}})
+
+	if got := strings.Join(rec.calls, ","); got != "complaint 1,complaint 2,complaint 3,status 1,alert Portal down" {
+		t.Errorf("calls: %s", got)
+	}
+}
+
+// failureRecorder also reports each failed fetch cycle, like the webhook.
+type failureRecorder struct{ recorder }
+
+func (r *failureRecorder) SendFetchFailure(err error, attempts int, _ time.Time) error {
+	r.calls = append(r.calls, fmt.Sprintf("fetch failed %v x%d", err, attempts))
+	return nil
+}
+
+func TestNotifyReportsFetchFailures(t *testing.T) {
+	plain, reporter := &recorder{}, &failureRecorder{}
+	b := New()
+	b.Subscribe("plain", Notify(plain))
+	b.Subscribe("reporter", Notify(reporter))
+
+	b.Publish(FetchFailed{Err: errors.New("timeout"), Attempts: 3})
+	b.Publish(FetchFailed{Err: errors.New("busy"), Attempts: 1, Silent: true})
+	b.Publish(FetchFailed{Err: errors.New("timeout"), Attempts: 3})
+
+	if len(plain.calls) != 0 {
+		t.Errorf("a channel without SendFetchFailure got %v", plain.calls)
+	}
+	if got := strings.Join(reporter.calls, ","); got != "fetch failed timeout x3,fetch failed timeout x3" {
+		t.Errorf("calls: %s, want every non-silent failure", got)
+	}
+}
+
+func TestSubscribeAsyncQueuesWithoutBlocking(t *testing.T) {
+	b := New()
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	var got []string
+	stop := b.SubscribeAsync("slow", 2, func(e Event) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		got = append(got, e.(AlertRaised).Alert.Title)
+		return errors.New("logged, not returned")
+	})
+
+	// The first event is taken by the waiting goroutine, two fill the
+	// queue and the fourth is dropped — none of it blocks Publish.
+	dropped := metrics.EventsDroppedTotal.Value()
+	for _, title := range []string{"a", "b", "c", "d"} {
+		if err := b.Publish(AlertRaised{Alert: notify.Alert{Title: title}}); err != nil {
+			t.Fatalf("Publish(%s): %v", title, err)
+		}
+		if title == "a" {
+			<-started
+		}
+	}
+	if n := metrics.EventsDroppedTotal.Value() - dropped; n != 1 {
+		t.Errorf("dropped %d events, want 1", n)
+	}
+
+	close(release)
+	stop()
+	if strings.Join(got, ",") != "a,b,c" {
+		t.Errorf("delivered %v, want a,b,c", got)
+	}
+	if err := b.Publish(AlertRaised{}); err != nil {
+		t.Errorf("Publish after stop: %v", err)
+	}
+	stop() // idempotent
+}
@@ -0,0 +1,126 @@
+// Package feeder maps complaint localities to the feeders that supply
+// them, so complaints can be counted the way the field team thinks — per
+// feeder rather than per address.
+//
+// The map is a text file (FEEDER_MAP_FILE) with one locality per line:
+//
+//	# locality = feeder
+//	Tokarva            = Bajipura AG
+//	Titva              = Bajipura JGY
+//	Valod Station Road = Valod Town
+//
+// Localities are matched case-insensitively, ignoring punctuation and
+// extra spaces: first exactly against each place a complaint names (its
+// resolved village, area, exact location), then as whole words inside
+// them, the longest locality winning.
+package feeder
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Map looks up the feeder of a locality. A nil Map knows no feeders.
+type Map struct {
+	feeders    map[string]string // normalised locality → feeder
+	localities []string          // normalised localities, longest first
+}
+
+// Load reads the map file at path.
+func Load(path string) (*Map, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("feeder map: %w", err)
+	}
+	defer f.Close()
+	m, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("feeder map %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Parse reads "locality = feeder" lines; blank lines and lines starting
+// with # are skipped. A locality listed twice with different feeders is
+// an error.
+func Parse(r io.Reader) (*Map, error) {
+	m := &Map{feeders: make(map[string]string)}
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		locality, feeder, ok := strings.Cut(line, "=")
+		key, feeder := normalize(locality), strings.TrimSpace(feeder)
+		if !ok || key == "" || feeder == "" {
+			return nil, fmt.Errorf("line %d: want \"locality = feeder\", got %q", n, line)
+		}
+		if prev, dup := m.feeders[key]; dup && prev != feeder {
+			return nil, fmt.Errorf("line %d: %q is mapped to both %q and %q", n, strings.TrimSpace(locality), prev, feeder)
+		}
+		m.feeders[key] = feeder
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	for key := range m.feeders {
+		m.localities = append(m.localities, key)
+	}
+	sort.Slice(m.localities, func(i, j int) bool {
+		if len(m.localities[i]) != len(m.localities[j]) {
+			return len(m.localities[i]) > len(m.localities[j])
+		}
+		return m.localities[i] < m.localities[j]
+	})
+	return m, nil
+}
+
+// Len returns the number of localities in the map.
+func (m *Map) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.feeders)
+}
+
+// Lookup returns the feeder of the first of places that names a mapped
+// locality, most specific first (village, area, exact location), or ""
+// when none does.
+func (m *Map) Lookup(places ...string) string {
+	if m == nil {
+		return ""
+	}
+	norm := make([]string, len(places))
+	for i, p := range places {
+		norm[i] = normalize(p)
+		if f, ok := m.feeders[norm[i]]; ok {
+			return f
+		}
+	}
+	for _, p := range norm {
+		if p == "" {
+			continue
+		}
+		padded := " " + p + " "
+		for _, key := range m.localities {
+			if strings.Contains(padded, " "+key+" ") {
+				return m.feeders[key]
+			}
+		}
+	}
+	return ""
+}
+
+// normalize lower-cases s and reduces it to letters and digits separated
+// by single spaces.
+func normalize(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
@@ -0,0 +1,61 @@
+package feeder
+
+import (
+	"strings"
+	"testing"
+)
+
+const sample = `
+# locality = feeder
+Tokarva            = Bajipura AG
+Titva              = Bajipura JGY
+Valod              = Valod Town
+Valod Station Road = Valod Industrial
+`
+
+func TestLookup(t *testing.T) {
+	m, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 4 {
+		t.Errorf("Len = %d, want 4", m.Len())
+	}
+	for _, tc := range []struct {
+		places []string
+		want   string
+	}{
+		{[]string{"TOKARVA", "anything"}, "Bajipura AG"},
+		{[]string{"", "titva."}, "Bajipura JGY"},
+		{[]string{"", "near bus stand, Valod"}, "Valod Town"},
+		{[]string{"", "Valod", "Shop 4, valod station-road"}, "Valod Town"},
+		{[]string{"", "", "Shop 4, valod station-road"}, "Valod Industrial"},
+		{[]string{"Tokarvagam"}, ""},
+		{nil, ""},
+	} {
+		if got := m.Lookup(tc.places...); got != tc.want {
+			t.Errorf("Lookup(%q) = %q, want %q", tc.places, got, tc.want)
+		}
+	}
+
+	var none *Map
+	if got := none.Lookup("Tokarva"); got != "" || none.Len() != 0 {
+		t.Errorf("nil Map: Lookup = %q, Len = %d; want nothing", got, none.Len())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, in := range []string{
+		"Tokarva",
+		"= Bajipura AG",
+		"Tokarva =",
+		"Tokarva = Bajipura AG\ntokarva = Titva",
+	} {
+		if _, err := Parse(strings.NewReader(in)); err == nil {
+			t.Errorf("Parse(%q) should fail", in)
+		}
+	}
+	if _, err := Parse(strings.NewReader("Tokarva = A\nTOKARVA = A")); err != nil {
+		t.Errorf("a locality repeated with the same feeder is fine; got %v", err)
+	}
+}
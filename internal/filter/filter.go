@@ -0,0 +1,90 @@
+// Package filter decides which new complaints are not announced: internal
+// test accounts, descriptions matching an ignore pattern, and, when a list
+// of areas is configured, complaints from anywhere else. A filtered
+// complaint is still stored, so it is not fetched again every cycle, but no
+// channel hears about it.
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rules are the configured ignore rules. A nil *Rules filters nothing.
+type Rules struct {
+	consumers   map[string]bool
+	description *regexp.Regexp
+	areas       []string // lower case
+}
+
+// New builds Rules from IGNORE_CONSUMERS, IGNORE_DESCRIPTION_REGEX and
+// NOTIFY_AREAS. Returns nil when none is set.
+func New(ignoreConsumers []string, ignoreDescription string, onlyAreas []string) (*Rules, error) {
+	if len(ignoreConsumers) == 0 && ignoreDescription == "" && len(onlyAreas) == 0 {
+		return nil, nil
+	}
+	r := &Rules{consumers: make(map[string]bool, len(ignoreConsumers))}
+	for _, c := range ignoreConsumers {
+		r.consumers[strings.TrimSpace(c)] = true
+	}
+	if ignoreDescription != "" {
+		re, err := regexp.Compile("(?i)" + ignoreDescription)
+		if err != nil {
+			return nil, fmt.Errorf("invalid description pattern: %w", err)
+		}
+		r.description = re
+	}
+	for _, a := range onlyAreas {
+		if a = strings.ToLower(strings.TrimSpace(a)); a != "" {
+			r.areas = append(r.areas, a)
+		}
+	}
+	return r, nil
+}
+
+// Complaint is what the rules look at. An empty field is unknown.
+type Complaint struct {
+	ConsumerNo  string
+	Description string
+	// Places are the complaint's area, exact location and village; the
+	// area rule passes a complaint when any of them contains a listed
+	// area.
+	Places []string
+}
+
+// Skip returns why c is not to be announced, or "" to announce it. Rules
+// on unknown fields pass, so Skip can run on the dashboard row before the
+// details are fetched; with final set, the details are all there is and a
+// complaint no listed area matches is skipped even without places.
+func (r *Rules) Skip(c Complaint, final bool) string {
+	if r == nil {
+		return ""
+	}
+	if c.ConsumerNo != "" && r.consumers[strings.TrimSpace(c.ConsumerNo)] {
+		return "ignored consumer " + c.ConsumerNo
+	}
+	if r.description != nil && c.Description != "" && r.description.MatchString(c.Description) {
+		return "description matches the ignore pattern"
+	}
+	if len(r.areas) == 0 {
+		return ""
+	}
+	known := false
+	for _, p := range c.Places {
+		p = strings.ToLower(p)
+		if p == "" {
+			continue
+		}
+		known = true
+		for _, a := range r.areas {
+			if strings.Contains(p, a) {
+				return ""
+			}
+		}
+	}
+	if known || final {
+		return "outside the notified areas"
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package filter
+
+import "testing"
+
+func TestSkip(t *testing.T) {
+	r, err := New([]string{"TEST-001"}, `\btest\b`, []string{"Limdi", "dahod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name  string
+		c     Complaint
+		final bool
+		skip  bool
+	}{
+		{"test account", Complaint{ConsumerNo: "TEST-001", Places: []string{"Limdi"}}, false, true},
+		{"test description", Complaint{Description: "Ignore: TEST complaint", Places: []string{"Limdi"}}, false, true},
+		{"listed area", Complaint{ConsumerNo: "12", Description: "no supply", Places: []string{"", "Near Limdi bus stand"}}, true, false},
+		{"other area", Complaint{Places: []string{"Jhalod"}}, false, true},
+		{"area unknown on the row", Complaint{ConsumerNo: "12"}, false, false},
+		{"area unknown in the details", Complaint{ConsumerNo: "12"}, true, true},
+	}
+	for _, tc := range cases {
+		if got := r.Skip(tc.c, tc.final); (got != "") != tc.skip {
+			t.Errorf("%s: Skip = %q, want skip %v", tc.name, got, tc.skip)
+		}
+	}
+
+	var none *Rules
+	if got := none.Skip(Complaint{ConsumerNo: "TEST-001"}, true); got != "" {
+		t.Errorf("nil Rules skipped: %q", got)
+	}
+	if r, err := New(nil, "", nil); r != nil || err != nil {
+		t.Errorf("New with no rules = %v, %v; want nil", r, err)
+	}
+	if _, err := New(nil, "(", nil); err == nil {
+		t.Error("an invalid pattern should fail")
+	}
+}
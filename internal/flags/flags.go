@@ -0,0 +1,136 @@
+// Package flags holds the runtime toggles operators flip from the Telegram
+// admin chat: feature kill-switches and the log level. Every change is
+// persisted so it survives a restart, which makes a temporary diagnostic
+// (e.g. debug logging) possible without a redeploy — and means it must be
+// switched back off explicitly.
+//
+// Flags gate features that are already configured; turning a flag on does
+// not enable a feature whose config (API key, schedule, …) is missing.
+package flags
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"cmon/internal/logging"
+)
+
+// Name identifies a feature flag.
+type Name string
+
+const (
+	// Translation gates Gujarati translation of new complaints.
+	Translation Name = "translation"
+	// Summaries gates the scheduled summary posts.
+	Summaries Name = "summaries"
+	// Reminders gates the unseen-complaint reminder.
+	Reminders Name = "reminders"
+)
+
+// All lists every flag in display order.
+var All = []Name{Translation, Summaries, Reminders}
+
+// logLevelKey is the settings key the log level is persisted under.
+const logLevelKey = "log_level"
+
+// Store persists settings; *storage.Storage implements it.
+type Store interface {
+	GetSetting(key string) (string, bool)
+	SetSetting(key, value string) error
+}
+
+// Flags is the set of runtime toggles. A nil *Flags reports every flag as
+// enabled, so callers need no nil checks when the feature is not wired up.
+type Flags struct {
+	store Store
+
+	mu       sync.RWMutex
+	disabled map[Name]bool
+}
+
+// New loads persisted flags and log level from store. Flags default to on.
+func New(store Store) *Flags {
+	f := &Flags{store: store, disabled: make(map[Name]bool)}
+	for _, n := range All {
+		if v, ok := store.GetSetting(settingKey(n)); ok && v == "off" {
+			f.disabled[n] = true
+			log.Printf("🚩 Feature %q is switched off (runtime flag)", n)
+		}
+	}
+	if v, ok := store.GetSetting(logLevelKey); ok {
+		if err := logging.SetLevel(v); err != nil {
+			log.Printf("⚠️  Ignoring saved log level: %v", err)
+		} else {
+			log.Printf("🚩 Log level %s (runtime setting)", logging.Level())
+		}
+	}
+	return f
+}
+
+// Parse maps a user-typed flag name to a Name.
+func Parse(name string) (Name, bool) {
+	n := Name(strings.ToLower(strings.TrimSpace(name)))
+	for _, known := range All {
+		if n == known {
+			return n, true
+		}
+	}
+	return "", false
+}
+
+// Enabled reports whether feature n is switched on.
+func (f *Flags) Enabled(n Name) bool {
+	if f == nil {
+		return true
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return !f.disabled[n]
+}
+
+// Set switches feature n on or off and persists the change.
+func (f *Flags) Set(n Name, on bool) error {
+	value := "on"
+	if !on {
+		value = "off"
+	}
+	if err := f.store.SetSetting(settingKey(n), value); err != nil {
+		return fmt.Errorf("failed to save flag %s: %w", n, err)
+	}
+	f.mu.Lock()
+	f.disabled[n] = !on
+	f.mu.Unlock()
+	log.Printf("🚩 Feature %q switched %s", n, value)
+	return nil
+}
+
+// SetLogLevel changes the log level and persists it.
+func (f *Flags) SetLogLevel(name string) error {
+	if err := logging.SetLevel(name); err != nil {
+		return err
+	}
+	if err := f.store.SetSetting(logLevelKey, logging.Level()); err != nil {
+		return fmt.Errorf("failed to save log level: %w", err)
+	}
+	log.Printf("🚩 Log level set to %s", logging.Level())
+	return nil
+}
+
+// Status renders every flag and the log level, one per line, e.g.
+// "translation: on".
+func (f *Flags) Status() string {
+	lines := make([]string, 0, len(All)+1)
+	for _, n := range All {
+		state := "on"
+		if !f.Enabled(n) {
+			state = "off"
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", n, state))
+	}
+	lines = append(lines, "log level: "+logging.Level())
+	return strings.Join(lines, "\n")
+}
+
+func settingKey(n Name) string { return "flag." + string(n) }
@@ -0,0 +1,68 @@
+package flags
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"cmon/internal/logging"
+)
+
+type memStore map[string]string
+
+func (m memStore) GetSetting(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+func (m memStore) SetSetting(key, value string) error {
+	m[key] = value
+	return nil
+}
+
+func TestFlagsPersistAcrossRestart(t *testing.T) {
+	t.Cleanup(func() { logging.SetLevel(slog.LevelInfo.String()) })
+	store := memStore{}
+
+	f := New(store)
+	if !f.Enabled(Translation) || !f.Enabled(Summaries) {
+		t.Fatal("flags should default to on")
+	}
+	if err := f.Set(Translation, false); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := f.SetLogLevel("debug"); err != nil {
+		t.Fatalf("SetLogLevel: %v", err)
+	}
+	logging.SetLevel("info")
+
+	restarted := New(store)
+	if restarted.Enabled(Translation) {
+		t.Error("translation should stay off after restart")
+	}
+	if !restarted.Enabled(Reminders) {
+		t.Error("untouched flag should stay on")
+	}
+	if logging.Level() != "debug" {
+		t.Errorf("log level after restart: got %q, want debug", logging.Level())
+	}
+	if got := restarted.Status(); !strings.Contains(got, "translation: off") || !strings.Contains(got, "log level: debug") {
+		t.Errorf("Status:\n%s", got)
+	}
+}
+
+func TestNilFlagsAreEnabled(t *testing.T) {
+	var f *Flags
+	if !f.Enabled(Summaries) {
+		t.Error("nil Flags should report every feature enabled")
+	}
+}
+
+func TestParse(t *testing.T) {
+	if n, ok := Parse(" Translation "); !ok || n != Translation {
+		t.Errorf("Parse(Translation) = %q, %v", n, ok)
+	}
+	if _, ok := Parse("weather"); ok {
+		t.Error("unknown flag should not parse")
+	}
+}
@@ -0,0 +1,209 @@
+// Package geocode turns a complaint's free-text location into coordinates
+// and a Google Maps link, so linemen can open the spot directly from the
+// notification.
+//
+// Providers:
+//   - "nominatim": OpenStreetMap Nominatim search API (default public
+//     endpoint or a self-hosted one). Rate-limited to 1 req/s per the public
+//     usage policy, with a persistent cache so each address is looked up once.
+//   - "link": no network calls — every complaint gets a Maps *search* link
+//     built from its location text.
+//
+// Lookup failures are never fatal: the caller falls back to a search link.
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Point is a WGS84 coordinate.
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// Valid reports whether p holds real coordinates (0,0 is used as "unknown";
+// it is in the Atlantic, far from any DGVCL consumer).
+func (p Point) Valid() bool {
+	return p.Lat != 0 || p.Lon != 0
+}
+
+// Cache persists lookups across restarts. found=false entries record misses
+// so an ungeocodable address isn't retried every cycle.
+type Cache interface {
+	GetGeocode(query string) (p Point, found bool, ok bool)
+	PutGeocode(query string, p Point, found bool) error
+}
+
+// Geocoder resolves location text to coordinates.
+type Geocoder struct {
+	provider  string
+	endpoint  string
+	userAgent string
+	country   string
+	http      *http.Client
+	limiter   *rate.Limiter
+	cache     Cache
+
+	mu  sync.Mutex
+	mem map[string]memEntry // process-local cache in front of Cache
+}
+
+type memEntry struct {
+	p     Point
+	found bool
+}
+
+// Options configures New.
+type Options struct {
+	Provider  string // "nominatim" or "link"; anything else disables geocoding
+	Endpoint  string // Nominatim search URL
+	UserAgent string // required by the Nominatim usage policy
+	Country   string // ISO country code filter, e.g. "in"
+	Cache     Cache  // optional persistent cache
+}
+
+// New returns a Geocoder, or nil when opts.Provider is empty/unknown (the
+// enrichment step is then skipped entirely).
+func New(opts Options) *Geocoder {
+	switch opts.Provider {
+	case "nominatim", "link":
+	default:
+		return nil
+	}
+	return &Geocoder{
+		provider:  opts.Provider,
+		endpoint:  opts.Endpoint,
+		userAgent: opts.UserAgent,
+		country:   opts.Country,
+		http:      &http.Client{Timeout: 10 * time.Second},
+		limiter:   rate.NewLimiter(rate.Every(time.Second), 1),
+		cache:     opts.Cache,
+		mem:       make(map[string]memEntry),
+	}
+}
+
+// Query joins location parts into the search text used for both lookup and
+// the fallback link, skipping blanks and repeated parts ("Tokarva, Tokarva").
+func Query(parts ...string) string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, p := range parts {
+		p = strings.Join(strings.Fields(p), " ")
+		key := strings.ToLower(p)
+		if p == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, p)
+	}
+	return strings.Join(out, ", ")
+}
+
+// Lookup resolves query to a point. ok=false means no coordinates (provider
+// is "link", nothing matched, or the request failed).
+func (g *Geocoder) Lookup(ctx context.Context, query string) (Point, bool) {
+	if g == nil || g.provider != "nominatim" || strings.TrimSpace(query) == "" {
+		return Point{}, false
+	}
+	key := strings.ToLower(strings.TrimSpace(query))
+
+	g.mu.Lock()
+	if e, hit := g.mem[key]; hit {
+		g.mu.Unlock()
+		return e.p, e.found
+	}
+	g.mu.Unlock()
+
+	if g.cache != nil {
+		if p, found, hit := g.cache.GetGeocode(key); hit {
+			g.remember(key, p, found)
+			return p, found
+		}
+	}
+
+	p, found, err := g.nominatim(ctx, query)
+	if err != nil {
+		// Transient: don't cache, try again next time.
+		return Point{}, false
+	}
+	g.remember(key, p, found)
+	if g.cache != nil {
+		_ = g.cache.PutGeocode(key, p, found)
+	}
+	return p, found
+}
+
+func (g *Geocoder) remember(key string, p Point, found bool) {
+	g.mu.Lock()
+	g.mem[key] = memEntry{p: p, found: found}
+	g.mu.Unlock()
+}
+
+func (g *Geocoder) nominatim(ctx context.Context, query string) (Point, bool, error) {
+	if err := g.limiter.Wait(ctx); err != nil {
+		return Point{}, false, err
+	}
+
+	v := url.Values{}
+	v.Set("q", query)
+	v.Set("format", "json")
+	v.Set("limit", "1")
+	if g.country != "" {
+		v.Set("countrycodes", g.country)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.endpoint+"?"+v.Encode(), nil)
+	if err != nil {
+		return Point{}, false, err
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := g.http.Do(req)
+	if err != nil {
+		return Point{}, false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Point{}, false, fmt.Errorf("nominatim returned HTTP %d", resp.StatusCode)
+	}
+
+	var results []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return Point{}, false, fmt.Errorf("decode nominatim response: %w", err)
+	}
+	if len(results) == 0 {
+		return Point{}, false, nil
+	}
+	lat, err1 := strconv.ParseFloat(results[0].Lat, 64)
+	lon, err2 := strconv.ParseFloat(results[0].Lon, 64)
+	if err1 != nil || err2 != nil {
+		return Point{}, false, fmt.Errorf("invalid coordinates %q,%q", results[0].Lat, results[0].Lon)
+	}
+	return Point{Lat: lat, Lon: lon}, true, nil
+}
+
+// MapsURL returns a Google Maps link: pinned coordinates when p is valid,
+// otherwise a search for query. Empty when both are empty.
+func MapsURL(p Point, query string) string {
+	if p.Valid() {
+		return fmt.Sprintf("https://www.google.com/maps?q=%.6f,%.6f", p.Lat, p.Lon)
+	}
+	if strings.TrimSpace(query) == "" {
+		return ""
+	}
+	return "https://www.google.com/maps/search/?api=1&query=" + url.QueryEscape(query)
+}
@@ -0,0 +1,132 @@
+package geocode
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		parts []string
+		want  string
+	}{
+		{"joins parts", []string{"Near Temple", "Tokarva"}, "Near Temple, Tokarva"},
+		{"skips blanks", []string{"", "  ", "Tokarva"}, "Tokarva"},
+		{"dedupes case-insensitively", []string{"Tokarva", "TOKARVA"}, "Tokarva"},
+		{"collapses whitespace", []string{"  Main   Road "}, "Main Road"},
+		{"all empty", []string{"", ""}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Query(tt.parts...); got != tt.want {
+				t.Errorf("Query(%q) = %q, want %q", tt.parts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapsURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		p     Point
+		query string
+		want  string
+	}{
+		{"pinned", Point{Lat: 21.1702, Lon: 72.8311}, "ignored", "https://www.google.com/maps?q=21.170200,72.831100"},
+		{"search fallback", Point{}, "Near Temple, Tokarva", "https://www.google.com/maps/search/?api=1&query=Near+Temple%2C+Tokarva"},
+		{"nothing", Point{}, " ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MapsURL(tt.p, tt.query); got != tt.want {
+				t.Errorf("MapsURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDisabled(t *testing.T) {
+	for _, provider := range []string{"", "google"} {
+		if g := New(Options{Provider: provider}); g != nil {
+			t.Errorf("New(%q) = %v, want nil", provider, g)
+		}
+	}
+	// A nil Geocoder is safe to call.
+	var g *Geocoder
+	if _, ok := g.Lookup(context.Background(), "Tokarva"); ok {
+		t.Error("nil Geocoder Lookup returned ok")
+	}
+}
+
+type memCache map[string]memEntry
+
+func (m memCache) GetGeocode(q string) (Point, bool, bool) {
+	e, ok := m[q]
+	return e.p, e.found, ok
+}
+
+func (m memCache) PutGeocode(q string, p Point, found bool) error {
+	m[q] = memEntry{p: p, found: found}
+	return nil
+}
+
+func TestLookupNominatim(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("User-Agent") != "cmon-test" {
+			t.Errorf("User-Agent = %q", r.Header.Get("User-Agent"))
+		}
+		if r.URL.Query().Get("countrycodes") != "in" {
+			t.Errorf("countrycodes = %q", r.URL.Query().Get("countrycodes"))
+		}
+		if strings.Contains(r.URL.Query().Get("q"), "Nowhere") {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{"lat":"21.1702","lon":"72.8311"}]`))
+	}))
+	defer srv.Close()
+
+	cache := memCache{}
+	g := New(Options{Provider: "nominatim", Endpoint: srv.URL, UserAgent: "cmon-test", Country: "in", Cache: cache})
+	ctx := context.Background()
+
+	p, ok := g.Lookup(ctx, "Tokarva")
+	if !ok || p.Lat != 21.1702 || p.Lon != 72.8311 {
+		t.Fatalf("Lookup = %+v, %v", p, ok)
+	}
+	if _, ok := g.Lookup(ctx, "tokarva "); !ok {
+		t.Error("second lookup should hit the cache")
+	}
+	if _, ok := g.Lookup(ctx, "Nowhere"); ok {
+		t.Error("empty result should not be ok")
+	}
+	if e, hit := cache["nowhere"]; !hit || e.found {
+		t.Errorf("miss should be cached as not found; got %+v hit=%v", e, hit)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("server calls = %d, want 2", n)
+	}
+
+	// A fresh Geocoder with the same persistent cache makes no requests.
+	g2 := New(Options{Provider: "nominatim", Endpoint: srv.URL, UserAgent: "cmon-test", Country: "in", Cache: cache})
+	if _, ok := g2.Lookup(ctx, "Tokarva"); !ok {
+		t.Error("persistent cache lookup failed")
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("server calls after restart = %d, want 2", n)
+	}
+}
+
+func TestLookupLinkProviderSkipsNetwork(t *testing.T) {
+	g := New(Options{Provider: "link", Endpoint: "http://127.0.0.1:0"})
+	if _, ok := g.Lookup(context.Background(), "Tokarva"); ok {
+		t.Error("link provider should never return coordinates")
+	}
+}
@@ -0,0 +1,166 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"cmon/internal/supervisor"
+)
+
+// Component and overall states, from best to worst. "starting" is only
+// used for the overall status, before the first fetch has finished, and
+// "standby" only by the standby server's /readyz.
+const (
+	StateHealthy   = "healthy"
+	StateDegraded  = "degraded"
+	StateUnhealthy = "unhealthy"
+	StateStarting  = "starting"
+	StateStandby   = "standby"
+)
+
+// Component check pacing: results are reused for checkTTL so frequent
+// probes don't hit Telegram or the database on every request, and a check
+// that hangs is reported as failed after checkTimeout.
+const (
+	checkTTL     = 30 * time.Second
+	checkTimeout = 5 * time.Second
+)
+
+// CheckFunc probes one component; nil means it is working.
+type CheckFunc func(ctx context.Context) error
+
+// ComponentStatus is one entry of Status.Components.
+type ComponentStatus struct {
+	Status    string `json:"status"` // healthy, degraded or unhealthy
+	Detail    string `json:"detail,omitempty"`
+	CheckedAt string `json:"checked_at,omitempty"`
+}
+
+type componentCheck struct {
+	name     string
+	critical bool
+	fn       CheckFunc
+
+	last      ComponentStatus
+	checkedAt time.Time
+}
+
+// AddCheck registers a component check reported under name. A failing
+// critical check makes the service unhealthy; any other failing check
+// only degrades it. Checks run when /health or /readyz is requested.
+func (m *Monitor) AddCheck(name string, critical bool, fn CheckFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checks = append(m.checks, &componentCheck{name: name, critical: critical, fn: fn})
+}
+
+// Check runs the component checks whose results are older than checkTTL,
+// concurrently, and returns the resulting status.
+func (m *Monitor) Check(ctx context.Context) Status {
+	m.mu.RLock()
+	var stale []*componentCheck
+	for _, c := range m.checks {
+		if time.Since(c.checkedAt) >= checkTTL {
+			stale = append(stale, c)
+		}
+	}
+	m.mu.RUnlock()
+
+	results := make([]ComponentStatus, len(stale))
+	var wg sync.WaitGroup
+	for i, c := range stale {
+		wg.Add(1)
+		go func(i int, c *componentCheck) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	now := time.Now()
+	for i, c := range stale {
+		c.last, c.checkedAt = results[i], now
+	}
+	m.mu.Unlock()
+
+	return m.GetStatus()
+}
+
+// runCheck calls c.fn with checkTimeout. The check keeps running in the
+// background if it ignores ctx; its late result is dropped.
+func runCheck(ctx context.Context, c *componentCheck) ComponentStatus {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- c.fn(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out after %s", checkTimeout)
+	}
+
+	st := ComponentStatus{Status: StateHealthy, CheckedAt: time.Now().In(location).Format("2006-01-02 15:04:05")}
+	if err != nil {
+		st.Status = StateDegraded
+		if c.critical {
+			st.Status = StateUnhealthy
+		}
+		st.Detail = err.Error()
+	}
+	return st
+}
+
+// fetchComponentLocked judges the scraper from the fetch history. A failed
+// fetch is unhealthy unless the portal itself is down, which only degrades
+// the service; a success older than MaxFetchAge means the loop is stuck,
+// and one that missed complaints (SetScrapeGap) degrades it.
+// Caller holds m.mu.
+func (m *Monitor) fetchComponentLocked(now time.Time) ComponentStatus {
+	switch {
+	case m.lastFetchStatus == "not started":
+		return ComponentStatus{Status: StateHealthy, Detail: "no fetch yet"}
+	case m.lastFetchStatus != "success" && m.portalStatus == "unavailable":
+		return ComponentStatus{Status: StateDegraded, Detail: "DGVCL portal unavailable: " + m.portalError}
+	case m.lastFetchStatus != "success":
+		return ComponentStatus{Status: StateUnhealthy, Detail: m.lastFetchStatus}
+	case m.MaxFetchAge > 0 && now.Sub(m.lastFetchSuccessAt) > m.MaxFetchAge:
+		return ComponentStatus{
+			Status: StateUnhealthy,
+			Detail: fmt.Sprintf("last successful fetch %s ago (limit %s)", now.Sub(m.lastFetchSuccessAt).Round(time.Second), m.MaxFetchAge),
+		}
+	case m.scrapeGap != "":
+		return ComponentStatus{Status: StateDegraded, Detail: "incomplete fetch: " + m.scrapeGap}
+	}
+	return ComponentStatus{Status: StateHealthy}
+}
+
+// goroutinesComponent degrades the service while any supervised task is
+// waiting to restart after a crash.
+func goroutinesComponent(tasks []supervisor.TaskStatus) ComponentStatus {
+	var down []string
+	for _, t := range tasks {
+		if t.State == supervisor.StateRestarting {
+			down = append(down, fmt.Sprintf("%s restarting after panic: %s", t.Name, t.LastCrash))
+		}
+	}
+	if len(down) > 0 {
+		return ComponentStatus{Status: StateDegraded, Detail: strings.Join(down, "; ")}
+	}
+	return ComponentStatus{Status: StateHealthy}
+}
+
+// worseState returns the worse of two component states.
+func worseState(a, b string) string {
+	rank := map[string]int{StateHealthy: 0, StateDegraded: 1, StateUnhealthy: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
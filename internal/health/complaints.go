@@ -174,6 +174,11 @@ var complaintsPageTemplate = template.Must(template.New("complaints-page").Parse
       color: var(--danger);
       border-color: rgba(220,38,38,0.18);
     }
+    .status-chip.degraded {
+      background: rgba(180,83,9,0.10);
+      color: var(--warn);
+      border-color: rgba(180,83,9,0.18);
+    }
     .status-chip.loading {
       background: var(--accent-dim);
       color: var(--accent);
@@ -397,6 +402,7 @@ var complaintsPageTemplate = template.Must(template.New("complaints-page").Parse
     .banner.info { background: var(--accent-soft); border-color: rgba(31,95,232,0.15); color: var(--accent); }
     .banner.error { background: var(--danger-dim); border-color: rgba(220,38,38,0.18); color: var(--danger); }
     .banner.success { background: var(--success-dim); border-color: rgba(21,128,61,0.18); color: var(--success); }
+    .banner.warn { background: rgba(180,83,9,0.10); border-color: rgba(180,83,9,0.18); color: var(--warn); }
     .banner-text { color: var(--text-2); flex: 1; }
     .banner-text strong { font-weight: 600; color: var(--text); }
 
@@ -1651,8 +1657,9 @@ const distBar = $("distBar");
 
       // Status chip
       function setChip(status) {
-        statusChip.className = "status-chip " + (status === "healthy" ? "healthy" : status === "loading" ? "loading" : "unhealthy");
-        statusChip.textContent = status === "healthy" ? "Operational" : status === "loading" ? "Loading" : "Offline";
+        const known = { healthy: "Operational", degraded: "Degraded", loading: "Loading" };
+        statusChip.className = "status-chip " + (known[status] ? status : "unhealthy");
+        statusChip.textContent = known[status] || "Offline";
       }
 
       // Banner
@@ -2302,7 +2309,7 @@ const distBar = $("distBar");
           render();
 
           const status = payload.status.status;
-          setChip(status === "healthy" ? "healthy" : status === "starting" ? "loading" : "unhealthy");
+          setChip(status === "starting" ? "loading" : status);
           updateWSStatus(wsConnected);
           if (status === "healthy") {
             if (!silent) {
@@ -2316,6 +2323,14 @@ const distBar = $("distBar");
                 "<strong>Dashboard ready.</strong> " + payload.total_count + " pending complaints loaded."
               );
             }
+          } else if (status === "degraded") {
+            const problems = Object.entries(payload.status.components || {})
+              .filter(([, c]) => c.status !== "healthy")
+              .map(([name, c]) => esc(name) + (c.detail ? ": " + esc(c.detail) : ""));
+            setBanner(
+              "warn",
+              "<strong>Degraded.</strong> " + (problems.join("; ") || "A component check failed.")
+            );
           } else if (status === "starting") {
             setBanner(
               "info",
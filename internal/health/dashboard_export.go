@@ -119,7 +119,7 @@ func buildExportRows(monitor *Monitor, sc *session.Client, stor *storage.Storage
 
 // exportFilename returns a date-stamped filename like
 // cmon-complaints-2026-05-10.csv so a browser save-as defaults to a
-// human-readable name. IST date because the operator works in IST.
+// human-readable name, dated in the operator's zone (see SetLocation).
 func exportFilename(ext string) string {
-	return "cmon-complaints-" + time.Now().Format("2006-01-02") + "." + ext
+	return "cmon-complaints-" + time.Now().In(location).Format("2006-01-02") + "." + ext
 }
@@ -24,7 +24,7 @@ func buildComplaintDashboardPayload(monitor *Monitor, sc *session.Client, stor *
 	activeIDs := stor.GetAllSeenComplaints()
 	if len(activeIDs) == 0 {
 		return complaintDashboardPayload{
-			GeneratedAt: time.Now().Format("02 Jan 2006, 03:04 PM"),
+			GeneratedAt: time.Now().In(location).Format("02 Jan 2006, 03:04 PM"),
 			TotalCount:  0,
 			GroupCount:  0,
 			Status:      status,
@@ -36,7 +36,7 @@ func buildComplaintDashboardPayload(monitor *Monitor, sc *session.Client, stor *
 	if err != nil {
 		if strings.Contains(err.Error(), "no pending complaints found") || strings.Contains(err.Error(), "no complaints with valid API IDs") {
 			return complaintDashboardPayload{
-				GeneratedAt: time.Now().Format("02 Jan 2006, 03:04 PM"),
+				GeneratedAt: time.Now().In(location).Format("02 Jan 2006, 03:04 PM"),
 				TotalCount:  0,
 				GroupCount:  0,
 				Status:      status,
@@ -64,7 +64,7 @@ func buildComplaintDashboardPayload(monitor *Monitor, sc *session.Client, stor *
 	}
 
 	return complaintDashboardPayload{
-		GeneratedAt: time.Now().Format("02 Jan 2006, 03:04 PM"),
+		GeneratedAt: time.Now().In(location).Format("02 Jan 2006, 03:04 PM"),
 		TotalCount:  totalCount,
 		GroupCount:  len(groups),
 		Status:      status,
@@ -0,0 +1,68 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+)
+
+// RuntimeStats is the /debug/stats payload: enough to spot goroutine or
+// heap growth in the long-running process without attaching a profiler.
+type RuntimeStats struct {
+	Goroutines       int    `json:"goroutines"`
+	HeapAllocBytes   uint64 `json:"heap_alloc_bytes"`
+	HeapInuseBytes   uint64 `json:"heap_inuse_bytes"`
+	HeapObjects      uint64 `json:"heap_objects"`
+	SysBytes         uint64 `json:"sys_bytes"`
+	NumGC            uint32 `json:"num_gc"`
+	GCPauseTotal     string `json:"gc_pause_total"`
+	LastGC           string `json:"last_gc,omitempty"`
+	WebSocketClients int    `json:"websocket_clients"`
+}
+
+// readRuntimeStats snapshots the runtime. runtime.ReadMemStats briefly
+// stops the world, which is fine at probe frequency.
+func readRuntimeStats() RuntimeStats {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	st := RuntimeStats{
+		Goroutines:     runtime.NumGoroutine(),
+		HeapAllocBytes: ms.HeapAlloc,
+		HeapInuseBytes: ms.HeapInuse,
+		HeapObjects:    ms.HeapObjects,
+		SysBytes:       ms.Sys,
+		NumGC:          ms.NumGC,
+		GCPauseTotal:   time.Duration(ms.PauseTotalNs).String(),
+	}
+	if ms.LastGC != 0 {
+		st.LastGC = time.Unix(0, int64(ms.LastGC)).In(location).Format("2006-01-02 15:04:05")
+	}
+	if WSHub != nil {
+		st.WebSocketClients = WSHub.ClientCount()
+	}
+	return st
+}
+
+// DebugRoutes returns the opt-in diagnostics endpoints, for StartServer's
+// routes map:
+//   - /debug/pprof/: the standard net/http/pprof profiles
+//   - /debug/stats: RuntimeStats as JSON
+//
+// They expose internals and profiling can be expensive, so main only
+// mounts them when DEBUG_ENDPOINTS=true.
+func DebugRoutes() map[string]http.Handler {
+	return map[string]http.Handler{
+		"/debug/pprof/":        http.HandlerFunc(pprof.Index),
+		"/debug/pprof/cmdline": http.HandlerFunc(pprof.Cmdline),
+		"/debug/pprof/profile": http.HandlerFunc(pprof.Profile),
+		"/debug/pprof/symbol":  http.HandlerFunc(pprof.Symbol),
+		"/debug/pprof/trace":   http.HandlerFunc(pprof.Trace),
+		"/debug/stats": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(readRuntimeStats())
+		}),
+	}
+}
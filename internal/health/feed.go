@@ -0,0 +1,166 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"cmon/internal/eventbus"
+	"cmon/internal/notify"
+)
+
+// feedBuffer is how many events a /events client may fall behind before
+// it is disconnected; EventSource reconnects on its own.
+const feedBuffer = 64
+
+// feedKeepAlive is how often an idle /events stream gets a comment line,
+// so proxies and the browser don't time it out.
+const feedKeepAlive = 30 * time.Second
+
+// Feed streams bus events to browsers as Server-Sent Events — the live
+// board in the control room opens an EventSource on /events. Each event
+// carries the same JSON as the webhook sink, with the webhook event name
+// ("complaint.new", "portal.down", …) as the SSE event type.
+//
+// Subscribe Handle to the event bus and mount the Feed at /events.
+type Feed struct {
+	// Redact masks mobile and consumer numbers (REDACT_PII): a wall board
+	// is as wide an audience as a broadcast group.
+	Redact bool
+
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+	closed  bool
+}
+
+// NewFeed returns a Feed with no clients.
+func NewFeed() *Feed {
+	return &Feed{clients: make(map[chan []byte]struct{})}
+}
+
+// Handle is an eventbus.Handler. It never blocks: a client whose buffer
+// is full is disconnected.
+func (f *Feed) Handle(e eventbus.Event) error {
+	var events []notify.WebhookEvent
+	switch e := e.(type) {
+	case eventbus.ComplaintNew:
+		events = append(events, notify.ComplaintEvent(f.complaint(e.Complaint)))
+	case eventbus.ComplaintsNew:
+		for _, c := range e.Complaints {
+			events = append(events, notify.ComplaintEvent(f.complaint(c)))
+		}
+	case eventbus.ComplaintResolved:
+		events = append(events, notify.ResolvedEvent(e.Status))
+	case eventbus.AlertRaised:
+		events = append(events, notify.AlertEvent(e.Alert))
+	case eventbus.FetchFailed:
+		if !e.Silent {
+			events = append(events, notify.FetchFailedEvent(e.Err, e.Attempts, e.Time))
+		}
+	}
+
+	for _, ev := range events {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return fmt.Errorf("encode %s event: %w", ev.Event, err)
+		}
+		msg := []byte(fmt.Sprintf("id: %s\nevent: %s\ndata: %s\n\n", ev.ID, ev.Event, data))
+
+		f.mu.Lock()
+		for ch := range f.clients {
+			select {
+			case ch <- msg:
+			default:
+				delete(f.clients, ch)
+				close(ch)
+			}
+		}
+		f.mu.Unlock()
+	}
+	return nil
+}
+
+func (f *Feed) complaint(c notify.Complaint) notify.Complaint {
+	if f.Redact {
+		return c.Redacted()
+	}
+	return c
+}
+
+// ServeHTTP streams events to one client until it disconnects, falls
+// behind or the Feed is closed.
+func (f *Feed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	ch := make(chan []byte, feedBuffer)
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	f.clients[ch] = struct{}{}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		if _, ok := f.clients[ch]; ok {
+			delete(f.clients, ch)
+			close(ch)
+		}
+		f.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx: don't buffer the stream
+	fmt.Fprint(w, "retry: 5000\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(feedKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if _, err := w.Write(msg); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// ClientCount returns the number of connected /events clients.
+func (f *Feed) ClientCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.clients)
+}
+
+// Close disconnects every client and refuses new ones. Register it with
+// the server's RegisterOnShutdown: Shutdown does not end open streams.
+func (f *Feed) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return
+	}
+	f.closed = true
+	for ch := range f.clients {
+		delete(f.clients, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,91 @@
+package health
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cmon/internal/eventbus"
+	"cmon/internal/notify"
+)
+
+// readEvent reads one SSE message (up to its blank line) from r.
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v (got %q)", err, lines)
+		}
+		if line == "\n" {
+			return strings.Join(lines, "")
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestFeedStreamsEvents(t *testing.T) {
+	feed := NewFeed()
+	feed.Redact = true
+	srv := httptest.NewServer(feed)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body := bufio.NewReader(resp.Body)
+	if got := readEvent(t, body); got != "retry: 5000\n" {
+		t.Errorf("preamble = %q", got)
+	}
+	for deadline := time.Now().Add(5 * time.Second); feed.ClientCount() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("client never registered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	feed.Handle(eventbus.ComplaintsNew{Complaints: []notify.Complaint{{Number: "C-1", MobileNo: "9876543210"}}})
+	feed.Handle(eventbus.ComplaintResolved{Status: notify.Status{ComplaintID: "C-1"}})
+
+	ev := readEvent(t, body)
+	if !strings.Contains(ev, "event: complaint.new\n") || !strings.Contains(ev, `"complain_no":"C-1"`) {
+		t.Errorf("first event = %q", ev)
+	}
+	if strings.Contains(ev, "9876543210") || !strings.Contains(ev, "3210") {
+		t.Errorf("mobile number should be masked: %q", ev)
+	}
+	if ev = readEvent(t, body); !strings.Contains(ev, "event: complaint.resolved\n") {
+		t.Errorf("second event = %q", ev)
+	}
+
+	// Close ends open streams so server shutdown isn't held up.
+	feed.Close()
+	if rest, err := io.ReadAll(body); err != nil || len(rest) != 0 {
+		t.Errorf("after Close: %q, %v; want a clean end of stream", rest, err)
+	}
+	if feed.ClientCount() != 0 {
+		t.Errorf("%d clients left after Close", feed.ClientCount())
+	}
+}
+
+func TestFeedDropsSlowClient(t *testing.T) {
+	feed := NewFeed()
+	ch := make(chan []byte, feedBuffer)
+	feed.clients[ch] = struct{}{}
+	for range feedBuffer + 1 {
+		feed.Handle(eventbus.AlertRaised{Alert: notify.Alert{Kind: notify.AlertPortalDown}})
+	}
+	if feed.ClientCount() != 0 {
+		t.Error("a client that fell behind should be dropped")
+	}
+}
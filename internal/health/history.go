@@ -0,0 +1,72 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// fetchHistorySize is how many fetch cycles the Monitor remembers.
+const fetchHistorySize = 50
+
+// FetchCycle is one fetch cycle: a scheduled or dashboard-triggered run,
+// retries and re-logins included.
+type FetchCycle struct {
+	StartedAt        time.Time     `json:"started_at"`
+	Duration         time.Duration `json:"duration_ns"`
+	Pages            int           `json:"pages"`
+	NewComplaints    int           `json:"new_complaints"`
+	FailedComplaints int           `json:"failed_complaints"`
+	// Errors counts failed attempts and complaints that failed to process.
+	Errors int    `json:"errors"`
+	Status string `json:"status"` // "success" or the final error
+	// Incomplete describes complaints the cycle could not reach (see
+	// SetScrapeGap); empty when the dashboard was read in full.
+	Incomplete string `json:"incomplete,omitempty"`
+}
+
+// RecordFetchCycle adds c to the fetch history, dropping the oldest cycle
+// once fetchHistorySize are kept. It does not change the fetch status;
+// callers still report the outcome with UpdateFetchStatus.
+func (m *Monitor) RecordFetchCycle(c FetchCycle) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cycles = append(m.cycles, c)
+	if len(m.cycles) > fetchHistorySize {
+		m.cycles = append(m.cycles[:0], m.cycles[len(m.cycles)-fetchHistorySize:]...)
+	}
+}
+
+// FetchHistory returns the remembered fetch cycles, newest first.
+func (m *Monitor) FetchHistory() []FetchCycle {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]FetchCycle, len(m.cycles))
+	for i, c := range m.cycles {
+		out[len(out)-1-i] = c
+	}
+	return out
+}
+
+// serveFetchHistory answers /health/history with FetchHistory as JSON.
+// Durations are also given in seconds for people reading it with curl.
+func serveFetchHistory(monitor *Monitor) http.HandlerFunc {
+	type cycleJSON struct {
+		FetchCycle
+		StartedAt       string  `json:"started_at"`
+		DurationSeconds float64 `json:"duration_seconds"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		history := monitor.FetchHistory()
+		out := make([]cycleJSON, len(history))
+		for i, c := range history {
+			out[i] = cycleJSON{
+				FetchCycle:      c,
+				StartedAt:       c.StartedAt.In(location).Format("2006-01-02 15:04:05"),
+				DurationSeconds: c.Duration.Round(time.Millisecond).Seconds(),
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(out)
+	}
+}
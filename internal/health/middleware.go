@@ -0,0 +1,40 @@
+package health
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// Middleware wraps the dashboard server's handler. Middlewares passed to
+// StartServer see every request, including /ws; one that replaces the
+// ResponseWriter must keep http.Hijacker working or WebSocket upgrades
+// fail.
+type Middleware func(http.Handler) http.Handler
+
+// chain wraps h so that mws[0] is the outermost middleware, i.e. the first
+// to see a request.
+func chain(h http.Handler, mws ...Middleware) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// recoverPanics turns a panicking handler into a 500 instead of letting
+// net/http log it and drop the connection. It is always the outermost
+// middleware.
+func recoverPanics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				log.Printf("⚠️  Panic serving %s %s: %v\n%s", r.Method, r.URL.Path, rec, debug.Stack())
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
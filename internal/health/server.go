@@ -1,7 +1,9 @@
 // Package health provides health check and monitoring for the CMON application.
 //
 // This package implements:
-//   - HTTP health check endpoint
+//   - HTTP health check endpoints (/health, /livez, /readyz)
+//   - Fetch cycle history (/health/history)
+//   - Per-component checks with healthy/degraded/unhealthy states
 //   - Application metrics tracking
 //   - Uptime monitoring
 //   - Status reporting
@@ -14,9 +16,11 @@ import (
 	"sync"
 	"time"
 
+	"cmon/internal/buildinfo"
 	"cmon/internal/metrics"
 	"cmon/internal/session"
 	"cmon/internal/storage"
+	"cmon/internal/supervisor"
 )
 
 // Status represents the application health status.
@@ -24,7 +28,9 @@ import (
 // This is returned by the /health endpoint for monitoring tools.
 //
 // Fields:
-//   - Status: Overall health status ("healthy", "unhealthy", "starting")
+//   - Status: Overall health status ("healthy", "degraded", "unhealthy",
+//     "starting"): the worst component state, or "starting" until the
+//     first fetch finishes
 //   - Uptime: How long the application has been running
 //   - LastFetchTime: When the last complaint fetch completed (success or fail)
 //   - LastFetchStatus: Status of last fetch ("success" or error message)
@@ -33,13 +39,30 @@ import (
 //     that recently errored even if LastFetchTime moves on each retry.
 //   - ConsecutiveErrors: Number of consecutive failed fetches since the most
 //     recent success. 0 when healthy. Useful as an alerting threshold.
+//   - Portal: DGVCL portal state ("unknown", "ok", "unavailable"). Separates
+//     "the portal is down for maintenance" from "cmon itself is broken".
+//   - PortalError: Signature / detail of the current portal outage.
+//   - PortalUnavailableSince: When the current portal outage was first seen.
+//   - Components: Per-component state: "fetch" plus every check added
+//     with AddCheck (as of its last run).
 type Status struct {
-	Status             string `json:"status"`
-	Uptime             string `json:"uptime"`
-	LastFetchTime      string `json:"last_fetch_time"`
-	LastFetchStatus    string `json:"last_fetch_status"`
-	LastFetchSuccessAt string `json:"last_fetch_success_at"`
-	ConsecutiveErrors  int    `json:"consecutive_errors"`
+	Status                 string `json:"status"`
+	Uptime                 string `json:"uptime"`
+	LastFetchTime          string `json:"last_fetch_time"`
+	LastFetchStatus        string `json:"last_fetch_status"`
+	LastFetchSuccessAt     string `json:"last_fetch_success_at"`
+	ConsecutiveErrors      int    `json:"consecutive_errors"`
+	Portal                 string `json:"portal"`
+	PortalError            string `json:"portal_error,omitempty"`
+	PortalUnavailableSince string `json:"portal_unavailable_since,omitempty"`
+
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+
+	// Build identifies the running binary: version, commit, build time.
+	Build buildinfo.Info `json:"build"`
+
+	// Goroutines is the liveness of the supervised background tasks.
+	Goroutines []supervisor.TaskStatus `json:"goroutines,omitempty"`
 }
 
 // Monitor tracks application health metrics.
@@ -53,7 +76,23 @@ type Monitor struct {
 	lastFetchStatus    string
 	lastFetchSuccessAt time.Time
 	consecutiveErrors  int
+	portalStatus       string
+	portalError        string
+	portalDownSince    time.Time
+	scrapeGap          string
+	checks             []*componentCheck
+	cycles             []FetchCycle
 	mu                 sync.RWMutex
+
+	// MaxFetchAge is how old the last successful fetch may get before the
+	// scraper is reported unhealthy. Zero disables the age check. Set
+	// after NewMonitor.
+	MaxFetchAge time.Duration
+
+	// Supervisor, when set, reports the background tasks under
+	// "goroutines"; one waiting to restart after a crash degrades the
+	// service. Set after NewMonitor.
+	Supervisor *supervisor.Supervisor
 }
 
 // NewMonitor creates a new health monitor.
@@ -68,6 +107,7 @@ func NewMonitor() *Monitor {
 	return &Monitor{
 		startTime:       time.Now(),
 		lastFetchStatus: "not started",
+		portalStatus:    "unknown",
 	}
 }
 
@@ -91,11 +131,63 @@ func (m *Monitor) UpdateFetchStatus(status string) {
 	if status == "success" {
 		m.lastFetchSuccessAt = now
 		m.consecutiveErrors = 0
+		m.setPortalAvailableLocked()
 	} else {
 		m.consecutiveErrors++
 	}
 }
 
+// MarkPortalUnavailable records that the portal served an error or
+// maintenance page. Returns true when this starts a new outage (the portal
+// was previously "ok" or "unknown"), so callers can alert once per outage
+// rather than on every fetch cycle.
+func (m *Monitor) MarkPortalUnavailable(detail string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.portalError = detail
+	if m.portalStatus == "unavailable" {
+		return false
+	}
+	m.portalStatus = "unavailable"
+	m.portalDownSince = time.Now()
+	return true
+}
+
+// MarkPortalAvailable records that the portal answered normally. Returns
+// true when this ends an outage. UpdateFetchStatus("success") calls this
+// implicitly.
+func (m *Monitor) MarkPortalAvailable() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.setPortalAvailableLocked()
+}
+
+func (m *Monitor) setPortalAvailableLocked() bool {
+	recovered := m.portalStatus == "unavailable"
+	m.portalStatus = "ok"
+	m.portalError = ""
+	m.portalDownSince = time.Time{}
+	return recovered
+}
+
+// location is the zone timestamps on the health endpoints and dashboard
+// are shown in; see SetLocation. Mutated only at boot.
+var location = time.Local
+
+// SetLocation sets the zone of displayed timestamps (TZ_OVERRIDE).
+func SetLocation(loc *time.Location) {
+	location = loc
+}
+
+// SetScrapeGap records that the last successful fetch could not collect
+// every complaint the dashboard lists (gap says how); "" clears it. The
+// fetch component is degraded while a gap is set.
+func (m *Monitor) SetScrapeGap(gap string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scrapeGap = gap
+}
+
 // GetStatus returns the current health status.
 //
 // Thread-safety:
@@ -107,35 +199,56 @@ func (m *Monitor) GetStatus() Status {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	uptime := time.Since(m.startTime)
+	now := time.Now()
+	uptime := now.Sub(m.startTime)
 
-	// Derive real health: "not started" is fine (startup phase),
-	// "success" is healthy, anything else means the last fetch failed.
-	overallStatus := "healthy"
-	switch {
-	case m.lastFetchStatus == "not started":
-		overallStatus = "starting"
-	case m.lastFetchStatus != "success":
-		overallStatus = "unhealthy"
+	// Overall health is the worst component state. Before the first fetch
+	// finishes the service is "starting" unless something is already
+	// unhealthy.
+	components := map[string]ComponentStatus{"fetch": m.fetchComponentLocked(now)}
+	tasks := m.Supervisor.Status()
+	if len(tasks) > 0 {
+		components["goroutines"] = goroutinesComponent(tasks)
+	}
+	for _, c := range m.checks {
+		if !c.checkedAt.IsZero() {
+			components[c.name] = c.last
+		}
+	}
+	overallStatus := StateHealthy
+	for _, c := range components {
+		overallStatus = worseState(overallStatus, c.Status)
+	}
+	if m.lastFetchStatus == "not started" && overallStatus != StateUnhealthy {
+		overallStatus = StateStarting
 	}
 
 	lastFetchTime := ""
 	if !m.lastFetchTime.IsZero() {
-		lastFetchTime = m.lastFetchTime.Format("2006-01-02 15:04:05")
+		lastFetchTime = m.lastFetchTime.In(location).Format("2006-01-02 15:04:05")
 	}
 	lastFetchSuccessAt := ""
 	if !m.lastFetchSuccessAt.IsZero() {
-		lastFetchSuccessAt = m.lastFetchSuccessAt.Format("2006-01-02 15:04:05")
+		lastFetchSuccessAt = m.lastFetchSuccessAt.In(location).Format("2006-01-02 15:04:05")
 	}
 
-	return Status{
+	st := Status{
 		Status:             overallStatus,
 		Uptime:             uptime.String(),
 		LastFetchTime:      lastFetchTime,
 		LastFetchStatus:    m.lastFetchStatus,
 		LastFetchSuccessAt: lastFetchSuccessAt,
 		ConsecutiveErrors:  m.consecutiveErrors,
+		Portal:             m.portalStatus,
+		PortalError:        m.portalError,
+		Components:         components,
+		Build:              buildinfo.Get(),
+		Goroutines:         tasks,
 	}
+	if !m.portalDownSince.IsZero() {
+		st.PortalUnavailableSince = m.portalDownSince.In(location).Format("2006-01-02 15:04:05")
+	}
+	return st
 }
 
 // registerStatusEndpoints wires /metrics (Prometheus-compatible) and /health
@@ -147,16 +260,74 @@ func registerStatusEndpoints(mux *http.ServeMux, monitor *Monitor) {
 	// at scrape time.
 	mux.Handle("/metrics", metrics.Handler())
 
-	// JSON health endpoint for external probes. Returns 200 when healthy or
-	// starting, 503 when unhealthy — so a probe can alert on HTTP code alone.
+	// JSON health endpoint for external probes. Returns 200 when healthy,
+	// degraded or starting, 503 when unhealthy — so a probe can alert on
+	// HTTP code alone and read the body for which component is at fault.
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		s := monitor.GetStatus()
+		s := monitor.Check(r.Context())
 		w.Header().Set("Content-Type", "application/json")
-		if s.Status == "unhealthy" {
+		if s.Status == StateUnhealthy {
 			w.WriteHeader(http.StatusServiceUnavailable)
 		}
 		_ = json.NewEncoder(w).Encode(s)
 	})
+
+	// Recent fetch cycles, newest first, to spot slow or flaky cycles.
+	mux.HandleFunc("/health/history", serveFetchHistory(monitor))
+
+	// Kubernetes liveness: the process is up and serving HTTP. Deliberately
+	// independent of the portal and the channels — restarting cmon does
+	// not fix either.
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte("ok\n"))
+	})
+
+	// Kubernetes readiness: 200 once the first fetch has finished and
+	// while nothing is unhealthy (degraded is still ready), 503 otherwise.
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		s := monitor.Check(r.Context())
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if s.Status == StateStarting || s.Status == StateUnhealthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_, _ = w.Write([]byte(s.Status + "\n"))
+	})
+}
+
+// registerStandbyEndpoints mounts the probes of a replica waiting for the
+// leader lease: it is alive, but not ready to take traffic.
+func registerStandbyEndpoints(mux *http.ServeMux) {
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(StateStandby + "\n"))
+	})
+}
+
+// StartStandbyServer serves only /livez and /readyz on port while a
+// LEADER_ELECTION standby waits for the lease, so orchestrators can tell a
+// waiting replica from a hung one. The caller shuts it down to free the
+// port just before StartServer.
+func StartStandbyServer(port string) *http.Server {
+	mux := http.NewServeMux()
+	registerStandbyEndpoints(mux)
+	srv := &http.Server{
+		Addr:              "0.0.0.0:" + port,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		log.Printf("✓ Standby probe server started on %s", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("⚠️  Standby probe server error: %v", err)
+		}
+	}()
+	return srv
 }
 
 // RefreshFunc is called by the dashboard to trigger a full scrape cycle
@@ -180,7 +351,9 @@ type RegisterLocalFunc func(consumerName, mobileNo, consumerNo, village, belt, a
 //   - GET /: Returns the pending complaints dashboard
 //   - GET /data: Returns dashboard JSON data
 //   - GET /ws: WebSocket endpoint for real-time updates
-//   - GET /health: JSON health probe
+//   - GET /health: JSON health probe with per-component states
+//   - GET /health/history: The last fetch cycles as JSON
+//   - GET /livez, /readyz: Kubernetes liveness and readiness probes
 //   - GET /metrics: Prometheus-compatible metrics
 //   - GET /register: Returns the standalone registration page
 //   - POST /register-local: JSON API endpoint to register custom complaints
@@ -193,6 +366,14 @@ type RegisterLocalFunc func(consumerName, mobileNo, consumerNo, village, belt, a
 //   - refreshFn: Optional function to trigger a scrape cycle before returning data
 //   - resolveFn: Callback to resolve a complaint (supporting custom local ones)
 //   - registerLocalFn: Callback to register a local complaint
+//   - routes: Extra handlers mounted as-is, keyed by pattern (Slack/Discord
+//     interaction endpoints, which authenticate requests themselves)
+//   - middleware: Wraps every route, first listed outermost (auth, request
+//     logging, rate limiting for new endpoints). Panics are always recovered
+//     outside of these.
+//
+// The server uses its own ServeMux, never http.DefaultServeMux. Shutdown on
+// the returned server also disconnects WebSocket clients and stops WSHub.
 func StartServer(
 	monitor *Monitor,
 	port string,
@@ -201,6 +382,8 @@ func StartServer(
 	refreshFn RefreshFunc,
 	resolveFn ResolveCallbackFunc,
 	registerLocalFn RegisterLocalFunc,
+	routes map[string]http.Handler,
+	middleware ...Middleware,
 ) *http.Server {
 	WSHub = NewHub()
 	go WSHub.Run()
@@ -208,6 +391,9 @@ func StartServer(
 	mux := http.NewServeMux()
 	registerComplaintDashboard(mux, monitor, sc, stor, refreshFn, resolveFn, registerLocalFn)
 	registerStatusEndpoints(mux, monitor)
+	for pattern, h := range routes {
+		mux.Handle(pattern, h)
+	}
 
 	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
 		WSHub.ServeHTTP(w, r)
@@ -216,9 +402,13 @@ func StartServer(
 	srv := &http.Server{
 		// Bind only to loopback — the dashboard has no authentication.
 		// Expose it externally only via a reverse proxy with auth if needed.
-		Addr:    "0.0.0.0:" + port,
-		Handler: mux,
+		Addr:              "0.0.0.0:" + port,
+		Handler:           recoverPanics(chain(mux, middleware...)),
+		ReadHeaderTimeout: 10 * time.Second,
 	}
+	// Hijacked WebSocket connections are not tracked by Shutdown; close
+	// them ourselves so dashboard tabs don't hold the process open.
+	srv.RegisterOnShutdown(WSHub.Close)
 
 	go func() {
 		log.Printf("✓ Dashboard server started on %s", srv.Addr)
@@ -1,11 +1,20 @@
 package health
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"cmon/internal/supervisor"
+
+	"github.com/gorilla/websocket"
 )
 
 func TestHealthEndpointStartingState(t *testing.T) {
@@ -136,3 +145,306 @@ func TestSuccessResetsConsecutiveErrors(t *testing.T) {
 		t.Errorf("Status after recovery: got %q, want healthy", got.Status)
 	}
 }
+
+// TestPortalOutageTransitions verifies the portal state only reports a new
+// outage once, and that a successful fetch clears it.
+func TestPortalOutageTransitions(t *testing.T) {
+	monitor := NewMonitor()
+	if got := monitor.GetStatus().Portal; got != "unknown" {
+		t.Errorf("initial Portal: got %q, want unknown", got)
+	}
+
+	if !monitor.MarkPortalUnavailable("service unavailable") {
+		t.Error("first MarkPortalUnavailable should report a new outage")
+	}
+	if monitor.MarkPortalUnavailable("server error") {
+		t.Error("repeat MarkPortalUnavailable should not report a new outage")
+	}
+	s := monitor.GetStatus()
+	if s.Portal != "unavailable" || s.PortalError != "server error" || s.PortalUnavailableSince == "" {
+		t.Errorf("during outage: got portal=%q error=%q since=%q", s.Portal, s.PortalError, s.PortalUnavailableSince)
+	}
+
+	monitor.UpdateFetchStatus("success")
+	s = monitor.GetStatus()
+	if s.Portal != "ok" || s.PortalError != "" || s.PortalUnavailableSince != "" {
+		t.Errorf("after success: got portal=%q error=%q since=%q", s.Portal, s.PortalError, s.PortalUnavailableSince)
+	}
+	if monitor.MarkPortalAvailable() {
+		t.Error("MarkPortalAvailable should not report recovery when already ok")
+	}
+}
+
+func TestComponentChecksDegradeOrFail(t *testing.T) {
+	monitor := NewMonitor()
+	monitor.UpdateFetchStatus("success")
+	monitor.AddCheck("telegram", false, func(context.Context) error { return errors.New("getMe: 401 Unauthorized") })
+	monitor.AddCheck("storage", true, func(context.Context) error { return nil })
+
+	s := monitor.Check(context.Background())
+	if s.Status != StateDegraded {
+		t.Errorf("failing non-critical check: got %q, want degraded", s.Status)
+	}
+	if c := s.Components["telegram"]; c.Status != StateDegraded || !strings.Contains(c.Detail, "401") {
+		t.Errorf("telegram component: %+v", c)
+	}
+	if c := s.Components["storage"]; c.Status != StateHealthy {
+		t.Errorf("storage component: %+v", c)
+	}
+
+	broken := NewMonitor()
+	broken.UpdateFetchStatus("success")
+	broken.AddCheck("storage", true, func(context.Context) error { return errors.New("disk I/O error") })
+	if s := broken.Check(context.Background()); s.Status != StateUnhealthy {
+		t.Errorf("failing critical check: got %q, want unhealthy", s.Status)
+	}
+}
+
+func TestGoroutinesComponent(t *testing.T) {
+	var wg sync.WaitGroup
+	sup := supervisor.New(&wg)
+	sup.MinBackoff = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() { cancel(); wg.Wait() }()
+
+	monitor := NewMonitor()
+	monitor.UpdateFetchStatus("success")
+	monitor.Supervisor = sup
+	sup.Go(ctx, "session keep-alive", func(ctx context.Context) { <-ctx.Done() })
+	if s := monitor.GetStatus(); s.Status != StateHealthy || len(s.Goroutines) != 1 || s.Goroutines[0].State != supervisor.StateRunning {
+		t.Errorf("running task: %+v", s)
+	}
+
+	sup.Go(ctx, "Telegram handler", func(context.Context) { panic("boom") })
+	deadline := time.Now().Add(time.Second)
+	for {
+		s := monitor.GetStatus()
+		if s.Status == StateDegraded {
+			if c := s.Components["goroutines"]; !strings.Contains(c.Detail, "Telegram handler restarting after panic: boom") {
+				t.Errorf("goroutines component: %+v", c)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("crashed task should degrade the service: %+v", s)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFetchComponent(t *testing.T) {
+	monitor := NewMonitor()
+	monitor.MaxFetchAge = time.Hour
+	monitor.UpdateFetchStatus("success")
+	if s := monitor.GetStatus(); s.Status != StateHealthy {
+		t.Errorf("fresh success: got %q", s.Status)
+	}
+
+	monitor.mu.Lock()
+	monitor.lastFetchSuccessAt = time.Now().Add(-2 * time.Hour)
+	monitor.mu.Unlock()
+	s := monitor.GetStatus()
+	if s.Status != StateUnhealthy || !strings.Contains(s.Components["fetch"].Detail, "last successful fetch") {
+		t.Errorf("stale success: got %q %+v", s.Status, s.Components["fetch"])
+	}
+
+	// A portal outage is the portal's problem, not ours: degraded.
+	outage := NewMonitor()
+	outage.UpdateFetchStatus("success")
+	outage.UpdateFetchStatus("error: portal unavailable")
+	outage.MarkPortalUnavailable("service unavailable")
+	if s := outage.GetStatus(); s.Status != StateDegraded {
+		t.Errorf("portal outage: got %q, want degraded", s.Status)
+	}
+}
+
+func TestProbeEndpoints(t *testing.T) {
+	monitor := NewMonitor()
+	mux := http.NewServeMux()
+	registerStatusEndpoints(mux, monitor)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	code := func(path string) int {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if got := code("/livez"); got != http.StatusOK {
+		t.Errorf("/livez while starting: got %d, want 200", got)
+	}
+	if got := code("/readyz"); got != http.StatusServiceUnavailable {
+		t.Errorf("/readyz while starting: got %d, want 503", got)
+	}
+
+	monitor.UpdateFetchStatus("success")
+	monitor.MarkPortalUnavailable("maintenance")
+	monitor.UpdateFetchStatus("error: maintenance")
+	if got := code("/readyz"); got != http.StatusOK {
+		t.Errorf("/readyz while degraded: got %d, want 200", got)
+	}
+	if got := code("/health"); got != http.StatusOK {
+		t.Errorf("/health while degraded: got %d, want 200", got)
+	}
+
+	monitor.UpdateFetchStatus("success")
+	monitor.UpdateFetchStatus("error: parse failure")
+	if got := code("/readyz"); got != http.StatusServiceUnavailable {
+		t.Errorf("/readyz while unhealthy: got %d, want 503", got)
+	}
+	if got := code("/livez"); got != http.StatusOK {
+		t.Errorf("/livez while unhealthy: got %d, want 200", got)
+	}
+}
+
+func TestStandbyProbes(t *testing.T) {
+	mux := http.NewServeMux()
+	registerStandbyEndpoints(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for path, want := range map[string]int{"/livez": http.StatusOK, "/readyz": http.StatusServiceUnavailable} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("%s on standby: got %d, want %d", path, resp.StatusCode, want)
+		}
+		if path == "/readyz" && string(body) != StateStandby+"\n" {
+			t.Errorf("/readyz body: got %q", body)
+		}
+	}
+}
+
+func TestMiddlewareChainAndRecover(t *testing.T) {
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/boom", func(http.ResponseWriter, *http.Request) { panic("boom") })
+
+	srv := httptest.NewServer(recoverPanics(chain(mux, tag("outer"), tag("inner"))))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/boom")
+	if err != nil {
+		t.Fatalf("GET /boom: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("panicking handler: got %d, want 500", resp.StatusCode)
+	}
+	if strings.Join(order, ",") != "outer,inner" {
+		t.Errorf("middleware order: got %v", order)
+	}
+}
+
+func TestHubCloseDisconnectsClients(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	for hub.ClientCount() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	hub.Close()
+	hub.Close() // idempotent
+	hub.BroadcastRefresh()
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseNoStatusReceived) {
+		t.Errorf("read after Close: got %v, want close frame", err)
+	}
+}
+
+func TestDebugStatsEndpoint(t *testing.T) {
+	mux := http.NewServeMux()
+	for pattern, h := range DebugRoutes() {
+		mux.Handle(pattern, h)
+	}
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/debug/stats")
+	if err != nil {
+		t.Fatalf("GET /debug/stats: %v", err)
+	}
+	defer resp.Body.Close()
+	var st RuntimeStats
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if st.Goroutines == 0 || st.HeapAllocBytes == 0 {
+		t.Errorf("stats look empty: %+v", st)
+	}
+
+	resp, err = http.Get(srv.URL + "/debug/pprof/goroutine?debug=1")
+	if err != nil {
+		t.Fatalf("GET goroutine profile: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("goroutine profile: got %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestFetchHistory(t *testing.T) {
+	monitor := NewMonitor()
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < fetchHistorySize+5; i++ {
+		monitor.RecordFetchCycle(FetchCycle{StartedAt: base.Add(time.Duration(i) * time.Minute), Pages: i, Status: "success"})
+	}
+
+	history := monitor.FetchHistory()
+	if len(history) != fetchHistorySize {
+		t.Fatalf("kept %d cycles, want %d", len(history), fetchHistorySize)
+	}
+	if history[0].Pages != fetchHistorySize+4 || history[len(history)-1].Pages != 5 {
+		t.Errorf("want newest first and oldest dropped: first=%d last=%d", history[0].Pages, history[len(history)-1].Pages)
+	}
+
+	mux := http.NewServeMux()
+	registerStatusEndpoints(mux, monitor)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	monitor.RecordFetchCycle(FetchCycle{StartedAt: base.Add(time.Hour), Duration: 1500 * time.Millisecond, Errors: 1, Status: "error: timeout"})
+	resp, err := http.Get(srv.URL + "/health/history")
+	if err != nil {
+		t.Fatalf("GET /health/history: %v", err)
+	}
+	defer resp.Body.Close()
+	var got []struct {
+		StartedAt       string  `json:"started_at"`
+		DurationSeconds float64 `json:"duration_seconds"`
+		Errors          int     `json:"errors"`
+		Status          string  `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got) != fetchHistorySize || got[0].Status != "error: timeout" || got[0].DurationSeconds != 1.5 || got[0].StartedAt != "2026-03-10 10:00:00" {
+		t.Errorf("newest entry: %+v (of %d)", got[0], len(got))
+	}
+}
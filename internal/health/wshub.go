@@ -29,6 +29,8 @@ type Hub struct {
 	broadcast  chan []byte
 	register   chan *Client
 	unregister chan *Client
+	done       chan struct{}
+	closeOnce  sync.Once
 	mu         sync.RWMutex
 }
 
@@ -38,12 +40,28 @@ func NewHub() *Hub {
 		broadcast:  make(chan []byte, 256),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		done:       make(chan struct{}),
 	}
 }
 
+// Close stops Run and disconnects every client with a close frame. Safe to
+// call more than once; broadcasts after Close are dropped.
+func (h *Hub) Close() {
+	h.closeOnce.Do(func() { close(h.done) })
+}
+
 func (h *Hub) Run() {
 	for {
 		select {
+		case <-h.done:
+			h.mu.Lock()
+			for client := range h.clients {
+				close(client.send)
+				delete(h.clients, client)
+			}
+			h.mu.Unlock()
+			return
+
 		case client := <-h.register:
 			h.mu.Lock()
 			h.clients[client] = true
@@ -93,7 +111,12 @@ func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		send: make(chan []byte, 256),
 	}
 
-	h.register <- client
+	select {
+	case h.register <- client:
+	case <-h.done:
+		conn.Close()
+		return
+	}
 
 	go client.writePump()
 	go client.readPump()
@@ -101,7 +124,10 @@ func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 func (c *Client) readPump() {
 	defer func() {
-		c.hub.unregister <- c
+		select {
+		case c.hub.unregister <- c:
+		case <-c.hub.done:
+		}
 		c.conn.Close()
 	}()
 
@@ -170,7 +196,10 @@ func (h *Hub) BroadcastMessage(msg BroadcastMessage) {
 		log.Printf("⚠️ Failed to marshal broadcast message: %v", err)
 		return
 	}
-	h.broadcast <- data
+	select {
+	case h.broadcast <- data:
+	case <-h.done:
+	}
 }
 
 func (h *Hub) BroadcastRefresh() {
@@ -0,0 +1,56 @@
+// Package heartbeat pings a dead-man's-switch service (healthchecks.io,
+// Uptime Kuma push monitors, Cronitor, …) after each successful fetch
+// cycle. The service alerts when the pings stop, which catches what the
+// in-process health server cannot: cmon itself crashing, hanging or the
+// host going away.
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// pingTimeout bounds one ping so a slow monitoring service never holds up
+// the fetch loop.
+const pingTimeout = 10 * time.Second
+
+// Pinger sends heartbeats to one push URL. A nil *Pinger is valid and does
+// nothing, so callers don't need to check whether heartbeats are enabled.
+type Pinger struct {
+	url    string
+	client *http.Client
+}
+
+// New returns a Pinger for url, or nil when url is empty.
+func New(url string) *Pinger {
+	if url == "" {
+		return nil
+	}
+	return &Pinger{url: url, client: &http.Client{Timeout: pingTimeout}}
+}
+
+// Ping sends one heartbeat: a GET to the push URL, which every supported
+// service accepts. Any 2xx response counts as delivered.
+func (p *Pinger) Ping(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return fmt.Errorf("heartbeat request: %w", err)
+	}
+	req.Header.Set("User-Agent", "cmon-heartbeat")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("heartbeat ping: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("heartbeat ping: HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package heartbeat
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNilPingerIsNoop(t *testing.T) {
+	p := New("")
+	if p != nil {
+		t.Fatal("empty URL should disable heartbeats")
+	}
+	if err := p.Ping(context.Background()); err != nil {
+		t.Errorf("nil Ping: %v", err)
+	}
+}
+
+func TestPing(t *testing.T) {
+	var paths []string
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.RequestURI())
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	p := New(srv.URL + "/ping/abc?status=up")
+	if err := p.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "GET /ping/abc?status=up" {
+		t.Errorf("requests: got %v", paths)
+	}
+
+	status = http.StatusNotFound
+	if err := p.Ping(context.Background()); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("non-2xx should error with the status; got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+// Package hooks lets site-specific code — pushing complaints to a
+// municipal ERP, say — run alongside cmon without forking it.
+//
+// A hook implements Hook and is either compiled in, by a file in package
+// main (or a package it imports) that calls Register from init:
+//
+//	func init() { hooks.Register("erp", &erpHook{}) }
+//
+// or built as a Go plugin (go build -buildmode=plugin) exporting a
+// variable named Hook, and listed in HOOK_PLUGINS. Plugins must be built
+// with the same Go version and module versions as cmon, and need a cgo
+// build of cmon to load.
+//
+// main subscribes every hook to the event bus on its own queue, so a slow
+// or failing hook never holds up fetching or the other channels.
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"plugin"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+
+	"cmon/internal/crash"
+	"cmon/internal/eventbus"
+	"cmon/internal/notify"
+)
+
+// Hook reacts to what cmon sees. Errors are logged; they never affect
+// cmon itself. Embed Base to implement only some of the methods.
+type Hook interface {
+	// OnNewComplaint is called once a new complaint has been saved.
+	OnNewComplaint(c notify.Complaint) error
+	// OnResolved is called when a complaint is resolved.
+	OnResolved(s notify.Status) error
+	// OnFetchError is called when a fetch cycle has failed every retry.
+	OnFetchError(err error) error
+}
+
+// Base implements Hook with methods that do nothing.
+type Base struct{}
+
+// OnNewComplaint implements Hook.
+func (Base) OnNewComplaint(notify.Complaint) error { return nil }
+
+// OnResolved implements Hook.
+func (Base) OnResolved(notify.Status) error { return nil }
+
+// OnFetchError implements Hook.
+func (Base) OnFetchError(error) error { return nil }
+
+// Registration is a hook and the name it is known by in logs.
+type Registration struct {
+	Name string
+	Hook Hook
+}
+
+var (
+	mu         sync.Mutex
+	registered = make(map[string]Hook)
+)
+
+// Register makes h known under name. It is meant to be called from init
+// and panics if h is nil or name is taken.
+func Register(name string, h Hook) {
+	mu.Lock()
+	defer mu.Unlock()
+	if h == nil {
+		panic("hooks: Register of nil hook " + name)
+	}
+	if _, dup := registered[name]; dup {
+		panic("hooks: Register called twice for " + name)
+	}
+	registered[name] = h
+}
+
+// Registered returns the compiled-in hooks, sorted by name.
+func Registered() []Registration {
+	mu.Lock()
+	defer mu.Unlock()
+	out := make([]Registration, 0, len(registered))
+	for name, h := range registered {
+		out = append(out, Registration{Name: name, Hook: h})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// LoadPlugin opens the Go plugin at path and returns its exported Hook
+// variable, named after the file.
+func LoadPlugin(path string) (Registration, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return Registration{}, fmt.Errorf("load hook plugin %s: %w", path, err)
+	}
+	sym, err := p.Lookup("Hook")
+	if err != nil {
+		return Registration{}, fmt.Errorf("hook plugin %s: %w", path, err)
+	}
+	// Lookup returns a pointer to the variable: *Hook when it is declared
+	// as a Hook, otherwise a pointer to the concrete type.
+	var h Hook
+	switch v := sym.(type) {
+	case *Hook:
+		h = *v
+	case Hook:
+		h = v
+	}
+	if h == nil {
+		return Registration{}, fmt.Errorf("hook plugin %s: Hook is a %T, which does not implement hooks.Hook", path, sym)
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return Registration{Name: name, Hook: h}, nil
+}
+
+// Handler adapts r to the event bus. A panic in the hook is reported like
+// any other crash and returned as an error.
+func Handler(r Registration) eventbus.Handler {
+	return func(e eventbus.Event) (err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				crash.Report("hook "+r.Name, rec, debug.Stack())
+				err = fmt.Errorf("panicked: %v", rec)
+			}
+		}()
+		switch e := e.(type) {
+		case eventbus.ComplaintNew:
+			return r.Hook.OnNewComplaint(e.Complaint)
+		case eventbus.ComplaintsNew:
+			var errs []error
+			for _, c := range e.Complaints {
+				if err := r.Hook.OnNewComplaint(c); err != nil {
+					errs = append(errs, fmt.Errorf("complaint %s: %w", c.Number, err))
+				}
+			}
+			return errors.Join(errs...)
+		case eventbus.ComplaintResolved:
+			return r.Hook.OnResolved(e.Status)
+		case eventbus.FetchFailed:
+			return r.Hook.OnFetchError(e.Err)
+		}
+		return nil
+	}
+}
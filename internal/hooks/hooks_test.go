@@ -0,0 +1,88 @@
+package hooks
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"cmon/internal/crash"
+	"cmon/internal/eventbus"
+	"cmon/internal/notify"
+)
+
+// erp records what it is told, like a site's ERP push would.
+type erp struct {
+	Base
+	got []string
+}
+
+func (h *erp) OnNewComplaint(c notify.Complaint) error {
+	h.got = append(h.got, "new "+c.Number)
+	if c.Number == "bad" {
+		return errors.New("ERP rejected it")
+	}
+	return nil
+}
+
+func (h *erp) OnResolved(s notify.Status) error {
+	if s.ComplaintID == "boom" {
+		panic("nil map in ERP client")
+	}
+	h.got = append(h.got, "resolved "+s.ComplaintID)
+	return nil
+}
+
+func TestHandlerDispatchesToHook(t *testing.T) {
+	h := &erp{}
+	handle := Handler(Registration{Name: "erp", Hook: h})
+
+	events := []eventbus.Event{
+		eventbus.ComplaintNew{Complaint: notify.Complaint{Number: "1"}},
+		eventbus.ComplaintsNew{Complaints: []notify.Complaint{{Number: "2"}, {Number: "bad"}, {Number: "3"}}},
+		eventbus.ComplaintResolved{Status: notify.Status{ComplaintID: "1"}},
+		eventbus.FetchFailed{Err: errors.New("portal down")}, // Base ignores it
+		eventbus.AlertRaised{},
+	}
+	var errs []string
+	for _, e := range events {
+		if err := handle(e); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if got := strings.Join(h.got, ","); got != "new 1,new 2,new bad,new 3,resolved 1" {
+		t.Errorf("hook saw %s", got)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0], "complaint bad: ERP rejected it") {
+		t.Errorf("errors = %q", errs)
+	}
+}
+
+func TestHandlerRecoversHookPanic(t *testing.T) {
+	var reported string
+	crash.SetReporter(func(component string, value any, stack []byte) { reported = component })
+	t.Cleanup(func() { crash.SetReporter(nil) })
+
+	err := Handler(Registration{Name: "erp", Hook: &erp{}})(eventbus.ComplaintResolved{Status: notify.Status{ComplaintID: "boom"}})
+	if err == nil || !strings.Contains(err.Error(), "panicked") {
+		t.Errorf("err = %v, want the panic as an error", err)
+	}
+	if reported != "hook erp" {
+		t.Errorf("crash reported for %q, want hook erp", reported)
+	}
+}
+
+func TestRegisterSortsAndRejectsDuplicates(t *testing.T) {
+	t.Cleanup(func() { registered = make(map[string]Hook) })
+	Register("zeta", Base{})
+	Register("alpha", Base{})
+	got := Registered()
+	if len(got) != 2 || got[0].Name != "alpha" || got[1].Name != "zeta" {
+		t.Errorf("Registered() = %+v", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a name twice should panic")
+		}
+	}()
+	Register("alpha", Base{})
+}
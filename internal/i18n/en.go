@@ -0,0 +1,171 @@
+package i18n
+
+// en is the English catalog. Keys are grouped by where the text appears;
+// values with %-verbs are formatted by T.
+var en = map[string]string{
+	// Complaint message buttons
+	"button.resolve":     "✅ Mark as Resolved",
+	"button.seen":        "👀 Seen",
+	"button.acknowledge": "👀 Acknowledge",
+	"button.details":     "🔍 Details",
+
+	// Resolve prompts
+	"resolve.prompt.quick": "📝 %s, pick the remarks for complaint <b>%s</b>\n👤 %s:",
+	"resolve.prompt.note":  "📝 %s, enter remarks for complaint <b>%s</b> (or reply with a voice note or photo)\n👤 %s:",
+	"resolve.placeholder":  "Enter resolution details...",
+	"resolve.ack":          "Please send your remarks",
+	"resolve.custom":       "✏️ Custom…",
+	"resolve.cancel":       "❌ Cancel",
+	"resolve.cancelled":    "Resolution cancelled",
+	"resolve.resolving":    "Resolving…",
+	"resolve.unknown":      "Unknown remark, please pick again",
+	"resolve.reply_please": "Please reply with your remarks",
+	"resolve.already":      "ℹ️ Complaint <b>%s</b> was already resolved.",
+	"resolve.failed":       "❌ Failed to mark complaint %s as resolved on website: %s\nPlease try again or contact support.",
+	"resolve.edit_failed":  "❌ Complaint %s was marked as resolved on the website, but I could not update the original Telegram message.",
+	"resolve.no_api_id":    "❌ Error: Cannot resolve complaint %s (API ID not found).",
+
+	// Resolved card
+	"resolved.title":       "RESOLVED",
+	"resolved.title.local": "RESOLVED (LOCAL)",
+	"resolved.complaint":   "Complaint #%s",
+	"resolved.unknown":     "Unknown",
+
+	// Alerts
+	"alert.critical.title":   "CRITICAL ALERT - CMON SERVICE",
+	"alert.recovered.title":  "RECOVERED - CMON SERVICE",
+	"alert.error_type":       "Error Type:",
+	"alert.error_message":    "Error Message:",
+	"alert.retries":          "Retry Attempts:",
+	"alert.timestamp":        "Timestamp:",
+	"alert.action":           "Action Required:",
+	"alert.action.check":     "Please check the service immediately.",
+	"alert.portal.down":      "DGVCL PORTAL UNAVAILABLE",
+	"alert.portal.detail":    "Detail:",
+	"alert.portal.since":     "Since:",
+	"alert.portal.down.body": "The portal is serving an error or maintenance page. Fetching will resume automatically once it recovers.",
+	"alert.portal.up":        "DGVCL portal is back",
+	"alert.portal.recovered": "Recovered at:",
+	"alert.outage.belt":      "%s Belt: %s",
+	"alert.outage.feeder":    "🔌 Feeder: %s (%s)",
+	"alert.outage.window":    "Within the last %s:",
+
+	// Digests
+	"digest.new":     "📦 <b>%d new complaints</b>",
+	"digest.pending": "📦 <b>%d complaints still pending</b>",
+	"digest.hint":    "Tap a number below to resolve it.",
+	"digest.done":    "📦 ✅ <b>Every complaint in this digest is resolved.</b>",
+
+	// Message cleanup archive
+	"archive.title": "<b>Archive</b> — %d resolved complaints cleared from this chat:",
+	"archive.line":  "• <code>%s</code>%s — resolved %s",
+
+	// Summaries
+	"summary.generating":         "📊 <b>Generating summary...</b>\nFetching details for all pending complaints.",
+	"summary.generating_belt":    "📊 <b>Generating belt-wise summary...</b>\nRendering one image per belt.",
+	"summary.none":               "ℹ️ No pending complaints found.",
+	"summary.none_match":         "ℹ️ No pending complaints match <code>%s</code>.",
+	"summary.caption":            "📋 %d Pending Complaints",
+	"summary.caption_belt":       "📋 %s Belt — %d Pending Complaints",
+	"summary.matching":           " matching %s",
+	"summary.page":               " (page %d/%d)",
+	"summary.render_failed":      "❌ Failed to render summary image: %s",
+	"summary.send_failed":        "❌ Failed to send summary image: %s",
+	"summary.render_belt_failed": "❌ Failed to render belt summary images: %s",
+	"summary.send_belt_failed":   "❌ Failed to send %s belt summary image: %s",
+
+	// Undo button
+	"undo.button":      "↩️ Undo (%s)",
+	"undo.too_late":    "Too late to undo",
+	"undo.failed":      "Undo failed on the website",
+	"undo.done":        "Resolution undone",
+	"undo.not_allowed": "Only %s or an admin can undo this resolution",
+
+	// /resolveall
+	"resolveall.admins_only": "❌ Only admins can use /resolveall.",
+	"resolveall.usage":       "Usage: <code>/resolveall area [| remark]</code> or <code>/resolveall ID ID … [| remark]</code>",
+	"resolveall.unknown":     "❌ Not in active storage: <code>%s</code>",
+	"resolveall.none":        "ℹ️ No pending complaints in <b>%s</b>.",
+	"resolveall.listed":      "listed",
+	"resolveall.in_area":     "in %s",
+	"resolveall.confirm":     "⚠️ Resolve <b>%d</b> complaints %s on the website?\n📝 Remark: <i>%s</i>\n\n<code>%s</code>",
+	"resolveall.button":      "✅ Resolve %d",
+	"resolveall.cancelled":   "Cancelled",
+	"resolveall.progress":    "⏳ Resolving %d complaints %s… %d/%d",
+	"resolveall.stopped":     "🛑 Stopped after %d/%d complaints %s.",
+	"resolveall.done":        "✅ Resolved %d/%d complaints %s.",
+	"resolveall.not_done":    "\n⚠️ %d not resolved — see the messages above.",
+
+	// Captcha relay
+	"captcha.timeout":     "⌛ No captcha answer received in time — login will be retried.",
+	"captcha.got_it":      "✓ Got it, logging in…",
+	"captcha.prompt":      "🧩 Automatic captcha solving keeps failing. Reply to this message with the answer within %s to log in.",
+	"captcha.placeholder": "Captcha answer",
+
+	// Details button
+	"details.untracked":  "Complaint is no longer tracked",
+	"details.local":      "Local complaint: no portal record",
+	"details.no_session": "Portal session unavailable",
+	"details.fetching":   "Fetching live status…",
+	"details.failed":     "❌ Could not fetch complaint <b>%s</b> from the portal: %s",
+
+	// Acknowledgment claims
+	"ack.claimed": "Being handled by %s — only they or an admin can resolve it",
+
+	// Notes
+	"note.failed": "❌ Could not save the note on <b>%s</b>.",
+	"note.added":  "📝 Note added to complaint <b>%s</b>.",
+
+	// Commands
+	"command.cancelled":          "❌ Resolution cancelled.",
+	"command.move.unknown_belt":  "❌ Unknown belt <b>%s</b>.\nValid belts: <code>%s</code>",
+	"command.move.not_stored":    "❌ Complaint <b>%s</b> is not in active storage.",
+	"command.move.failed":        "❌ Failed to update complaint <b>%s</b>.",
+	"command.move.done":          "✅ Complaint <b>%s</b> moved from <b>%s</b> to <b>%s</b>.",
+	"command.consumer.usage":     "<b>Consumer history</b>\n\nUsage: <code>/consumer consumer_no</code>",
+	"command.consumer.failed":    "❌ Failed to load history for consumer <b>%s</b>.",
+	"command.consumer.none":      "🔍 No complaints on record for consumer <b>%s</b>.",
+	"command.consumer.title":     "🔍 <b>Consumer %s</b>",
+	"command.consumer.count":     "\n%d complaint(s) on record",
+	"command.consumer.latest":    " (latest shown)",
+	"command.consumer.open":      "🟠 open",
+	"command.consumer.resolved":  "✅ resolved %s",
+	"command.deadletters.failed": "❌ Failed to load the failed-complaint list.",
+	"command.chart.failed":       "❌ Failed to build chart: %s",
+	"command.chart.last_days":    "last %d days",
+	"command.chart.usage":        "❌ Usage: <code>/chart [volume|resolution] [days]</code>, days 1–%d (default %d).",
+
+	// Charts and the weekly report
+	"chart.volume.title":       "Complaints per day — %s",
+	"chart.volume.caption":     "📈 Complaint volume — %s",
+	"chart.resolution.title":   "Time to resolve — %s",
+	"chart.resolution.caption": "⏱️ Resolution time — %s",
+	"weekly.title":             "📈 <b>Weekly report</b> (%s – %s)\n🆕 %d new complaints\n✅ %d resolved",
+	"weekly.median":            ", median %s",
+	"weekly.pending":           "\n📋 %d still pending",
+	"weekly.period":            "this week",
+
+	// Morning roll-call
+	"rollcall.title":              "☀️ <b>Morning status</b> — %s\n📋 %d pending complaints",
+	"rollcall.oldest":             "\n⏳ Oldest: <code>%s</code>",
+	"rollcall.pending_for":        ", pending %s",
+	"rollcall.resolved_yesterday": "\n✅ %d resolved yesterday",
+
+	// Admin chat notices
+	"notice.started":       "🟢 <b>CMON started</b> (%s, resumed %d pending complaints)",
+	"notice.shutdown":      "🔴 <b>CMON shutting down</b> (%s)",
+	"notice.crash":         "💥 <b>Panic in %s</b> — recovered, still running",
+	"notice.update":        "⬆️ <b>Update available: %s</b>\nRunning %s.",
+	"notice.updating":      "⬆️ <b>Updating to %s</b>\nWas running %s; restarting now.",
+	"notice.release_notes": "\n<a href=\"%s\">Release notes</a>",
+
+	// /slareport
+	"slareport.usage":       "❌ Usage: <code>/slareport [YYYY-MM]</code> (default: last month).",
+	"slareport.failed":      "❌ Failed to build SLA report: %s",
+	"slareport.title":       "📑 <b>SLA compliance, %s</b>",
+	"slareport.none":        "\nNo complaints were resolved that month.",
+	"slareport.total":       "\n✅ %d of %d resolved within target (%.1f%%)",
+	"slareport.row":         "\n• %s: %.1f%% of %d",
+	"slareport.caption":     "📑 SLA report, %s",
+	"slareport.send_failed": "❌ Failed to send the %s report: %s",
+}
@@ -0,0 +1,171 @@
+package i18n
+
+// gu is the Gujarati catalog, key for key with en. Portal names (DGVCL,
+// CMON) and HTML markup are kept as they are.
+var gu = map[string]string{
+	// Complaint message buttons
+	"button.resolve":     "✅ નિકાલ થયો",
+	"button.seen":        "👀 જોયું",
+	"button.acknowledge": "👀 હું સંભાળું છું",
+	"button.details":     "🔍 વિગતો",
+
+	// Resolve prompts
+	"resolve.prompt.quick": "📝 %s, ફરિયાદ <b>%s</b> માટે રિમાર્ક પસંદ કરો\n👤 %s:",
+	"resolve.prompt.note":  "📝 %s, ફરિયાદ <b>%s</b> માટે રિમાર્ક લખો (અથવા વૉઇસ નોટ કે ફોટો મોકલો)\n👤 %s:",
+	"resolve.placeholder":  "નિકાલની વિગત લખો...",
+	"resolve.ack":          "કૃપા કરીને તમારા રિમાર્ક મોકલો",
+	"resolve.custom":       "✏️ બીજું…",
+	"resolve.cancel":       "❌ રદ કરો",
+	"resolve.cancelled":    "નિકાલ રદ કર્યો",
+	"resolve.resolving":    "નિકાલ થઈ રહ્યો છે…",
+	"resolve.unknown":      "અજાણ્યો રિમાર્ક, ફરી પસંદ કરો",
+	"resolve.reply_please": "કૃપા કરીને તમારા રિમાર્ક જવાબમાં મોકલો",
+	"resolve.already":      "ℹ️ ફરિયાદ <b>%s</b> નો નિકાલ પહેલેથી થઈ ગયો છે.",
+	"resolve.failed":       "❌ ફરિયાદ %s નો વેબસાઇટ પર નિકાલ થઈ શક્યો નહીં: %s\nફરી પ્રયાસ કરો અથવા સપોર્ટનો સંપર્ક કરો.",
+	"resolve.edit_failed":  "❌ ફરિયાદ %s નો વેબસાઇટ પર નિકાલ થયો, પણ મૂળ Telegram સંદેશ બદલી શકાયો નહીં.",
+	"resolve.no_api_id":    "❌ ભૂલ: ફરિયાદ %s નો નિકાલ થઈ શકતો નથી (API ID મળ્યું નથી).",
+
+	// Resolved card
+	"resolved.title":       "નિકાલ થયો",
+	"resolved.title.local": "નિકાલ થયો (લોકલ)",
+	"resolved.complaint":   "ફરિયાદ #%s",
+	"resolved.unknown":     "અજાણ્યું",
+
+	// Alerts
+	"alert.critical.title":   "ગંભીર ચેતવણી - CMON સેવા",
+	"alert.recovered.title":  "ફરી ચાલુ - CMON સેવા",
+	"alert.error_type":       "ભૂલનો પ્રકાર:",
+	"alert.error_message":    "ભૂલ સંદેશ:",
+	"alert.retries":          "પ્રયાસો:",
+	"alert.timestamp":        "સમય:",
+	"alert.action":           "કાર્યવાહી જરૂરી:",
+	"alert.action.check":     "કૃપા કરીને તરત જ સેવા તપાસો.",
+	"alert.portal.down":      "DGVCL પોર્ટલ ઉપલબ્ધ નથી",
+	"alert.portal.detail":    "વિગત:",
+	"alert.portal.since":     "ક્યારથી:",
+	"alert.portal.down.body": "પોર્ટલ ભૂલ અથવા જાળવણીનું પાનું બતાવે છે. પોર્ટલ ફરી ચાલુ થતાં ફરિયાદો આપમેળે મેળવવાનું શરૂ થશે.",
+	"alert.portal.up":        "DGVCL પોર્ટલ ફરી ચાલુ છે",
+	"alert.portal.recovered": "ફરી ચાલુ થયાનો સમય:",
+	"alert.outage.belt":      "%s બેલ્ટ: %s",
+	"alert.outage.feeder":    "🔌 ફીડર: %s (%s)",
+	"alert.outage.window":    "છેલ્લા %s માં:",
+
+	// Digests
+	"digest.new":     "📦 <b>%d નવી ફરિયાદો</b>",
+	"digest.pending": "📦 <b>%d ફરિયાદો હજી બાકી</b>",
+	"digest.hint":    "નિકાલ કરવા નીચે નંબર દબાવો.",
+	"digest.done":    "📦 ✅ <b>આ યાદીની બધી ફરિયાદોનો નિકાલ થયો.</b>",
+
+	// Message cleanup archive
+	"archive.title": "<b>આર્કાઇવ</b> — નિકાલ થયેલી %d ફરિયાદો આ ચેટમાંથી દૂર કરી:",
+	"archive.line":  "• <code>%s</code>%s — નિકાલ %s",
+
+	// Summaries
+	"summary.generating":         "📊 <b>સારાંશ તૈયાર થઈ રહ્યો છે...</b>\nબધી બાકી ફરિયાદોની વિગતો મેળવાય છે.",
+	"summary.generating_belt":    "📊 <b>બેલ્ટ મુજબ સારાંશ તૈયાર થઈ રહ્યો છે...</b>\nદરેક બેલ્ટનું એક ચિત્ર.",
+	"summary.none":               "ℹ️ કોઈ બાકી ફરિયાદ મળી નથી.",
+	"summary.none_match":         "ℹ️ <code>%s</code> સાથે મેળ ખાતી કોઈ બાકી ફરિયાદ નથી.",
+	"summary.caption":            "📋 %d બાકી ફરિયાદો",
+	"summary.caption_belt":       "📋 %s બેલ્ટ — %d બાકી ફરિયાદો",
+	"summary.matching":           " (%s મુજબ)",
+	"summary.page":               " (પાનું %d/%d)",
+	"summary.render_failed":      "❌ સારાંશ ચિત્ર બનાવી શકાયું નહીં: %s",
+	"summary.send_failed":        "❌ સારાંશ ચિત્ર મોકલી શકાયું નહીં: %s",
+	"summary.render_belt_failed": "❌ બેલ્ટ મુજબ સારાંશ ચિત્રો બનાવી શકાયા નહીં: %s",
+	"summary.send_belt_failed":   "❌ %s બેલ્ટનું સારાંશ ચિત્ર મોકલી શકાયું નહીં: %s",
+
+	// Undo button
+	"undo.button":      "↩️ પાછું લો (%s)",
+	"undo.too_late":    "પાછું લેવા માટે મોડું થઈ ગયું",
+	"undo.failed":      "વેબસાઇટ પર પાછું લઈ શકાયું નહીં",
+	"undo.done":        "નિકાલ પાછો લીધો",
+	"undo.not_allowed": "ફક્ત %s અથવા એડમિન આ નિકાલ પાછો લઈ શકે",
+
+	// /resolveall
+	"resolveall.admins_only": "❌ /resolveall ફક્ત એડમિન વાપરી શકે છે.",
+	"resolveall.usage":       "ઉપયોગ: <code>/resolveall area [| remark]</code> અથવા <code>/resolveall ID ID … [| remark]</code>",
+	"resolveall.unknown":     "❌ સક્રિય યાદીમાં નથી: <code>%s</code>",
+	"resolveall.none":        "ℹ️ <b>%s</b> માં કોઈ બાકી ફરિયાદ નથી.",
+	"resolveall.listed":      "(યાદી મુજબ)",
+	"resolveall.in_area":     "(%s માં)",
+	"resolveall.confirm":     "⚠️ વેબસાઇટ પર <b>%d</b> ફરિયાદો %s નો નિકાલ કરવો છે?\n📝 રિમાર્ક: <i>%s</i>\n\n<code>%s</code>",
+	"resolveall.button":      "✅ %d નો નિકાલ કરો",
+	"resolveall.cancelled":   "રદ કર્યું",
+	"resolveall.progress":    "⏳ %d ફરિયાદો %s નો નિકાલ થઈ રહ્યો છે… %d/%d",
+	"resolveall.stopped":     "🛑 %d/%d ફરિયાદો %s પછી અટકાવ્યું.",
+	"resolveall.done":        "✅ %d/%d ફરિયાદો %s નો નિકાલ થયો.",
+	"resolveall.not_done":    "\n⚠️ %d નો નિકાલ થયો નથી — ઉપરના સંદેશા જુઓ.",
+
+	// Captcha relay
+	"captcha.timeout":     "⌛ સમયસર કેપ્ચાનો જવાબ ન મળ્યો — લોગિન ફરી પ્રયાસ કરાશે.",
+	"captcha.got_it":      "✓ મળી ગયું, લોગિન થઈ રહ્યું છે…",
+	"captcha.prompt":      "🧩 આપમેળે કેપ્ચા ઉકેલવામાં વારંવાર નિષ્ફળતા. લોગિન કરવા %s ની અંદર આ સંદેશાના જવાબમાં કેપ્ચા લખો.",
+	"captcha.placeholder": "કેપ્ચાનો જવાબ",
+
+	// Details button
+	"details.untracked":  "આ ફરિયાદ હવે ટ્રેક થતી નથી",
+	"details.local":      "સ્થાનિક ફરિયાદ: પોર્ટલ પર રેકોર્ડ નથી",
+	"details.no_session": "પોર્ટલ સત્ર ઉપલબ્ધ નથી",
+	"details.fetching":   "તાજી સ્થિતિ મેળવાય છે…",
+	"details.failed":     "❌ ફરિયાદ <b>%s</b> પોર્ટલ પરથી મેળવી શકાઈ નહીં: %s",
+
+	// Acknowledgment claims
+	"ack.claimed": "%s સંભાળી રહ્યા છે — ફક્ત તેઓ અથવા એડમિન નિકાલ કરી શકે",
+
+	// Notes
+	"note.failed": "❌ <b>%s</b> પર નોંધ સાચવી શકાઈ નહીં.",
+	"note.added":  "📝 ફરિયાદ <b>%s</b> પર નોંધ ઉમેરી.",
+
+	// Commands
+	"command.cancelled":          "❌ નિકાલ રદ કર્યો.",
+	"command.move.unknown_belt":  "❌ અજાણ્યો બેલ્ટ <b>%s</b>.\nમાન્ય બેલ્ટ: <code>%s</code>",
+	"command.move.not_stored":    "❌ ફરિયાદ <b>%s</b> સક્રિય યાદીમાં નથી.",
+	"command.move.failed":        "❌ ફરિયાદ <b>%s</b> બદલી શકાઈ નહીં.",
+	"command.move.done":          "✅ ફરિયાદ <b>%s</b> <b>%s</b> માંથી <b>%s</b> માં ખસેડી.",
+	"command.consumer.usage":     "<b>ગ્રાહક ઇતિહાસ</b>\n\nઉપયોગ: <code>/consumer consumer_no</code>",
+	"command.consumer.failed":    "❌ ગ્રાહક <b>%s</b> નો ઇતિહાસ મેળવી શકાયો નહીં.",
+	"command.consumer.none":      "🔍 ગ્રાહક <b>%s</b> ની કોઈ ફરિયાદ નોંધાયેલી નથી.",
+	"command.consumer.title":     "🔍 <b>ગ્રાહક %s</b>",
+	"command.consumer.count":     "\n%d ફરિયાદ(ો) નોંધાયેલી",
+	"command.consumer.latest":    " (તાજેતરની બતાવી)",
+	"command.consumer.open":      "🟠 બાકી",
+	"command.consumer.resolved":  "✅ નિકાલ %s",
+	"command.deadletters.failed": "❌ નિષ્ફળ ફરિયાદોની યાદી મેળવી શકાઈ નહીં.",
+	"command.chart.failed":       "❌ ચાર્ટ બનાવી શકાયો નહીં: %s",
+	"command.chart.last_days":    "છેલ્લા %d દિવસ",
+	"command.chart.usage":        "❌ ઉપયોગ: <code>/chart [volume|resolution] [days]</code>, દિવસ 1–%d (મૂળભૂત %d).",
+
+	// Charts and the weekly report
+	"chart.volume.title":       "દરરોજની ફરિયાદો — %s",
+	"chart.volume.caption":     "📈 ફરિયાદોની સંખ્યા — %s",
+	"chart.resolution.title":   "નિકાલનો સમય — %s",
+	"chart.resolution.caption": "⏱️ નિકાલનો સમય — %s",
+	"weekly.title":             "📈 <b>સાપ્તાહિક રિપોર્ટ</b> (%s – %s)\n🆕 %d નવી ફરિયાદો\n✅ %d નો નિકાલ",
+	"weekly.median":            ", મધ્યક %s",
+	"weekly.pending":           "\n📋 %d હજી બાકી",
+	"weekly.period":            "આ અઠવાડિયે",
+
+	// Morning roll-call
+	"rollcall.title":              "☀️ <b>સવારની સ્થિતિ</b> — %s\n📋 %d બાકી ફરિયાદો",
+	"rollcall.oldest":             "\n⏳ સૌથી જૂની: <code>%s</code>",
+	"rollcall.pending_for":        ", %s થી બાકી",
+	"rollcall.resolved_yesterday": "\n✅ ગઈકાલે %d નો નિકાલ",
+
+	// Admin chat notices
+	"notice.started":       "🟢 <b>CMON શરૂ થયું</b> (%s, %d બાકી ફરિયાદો ફરી લીધી)",
+	"notice.shutdown":      "🔴 <b>CMON બંધ થઈ રહ્યું છે</b> (%s)",
+	"notice.crash":         "💥 <b>%s માં પેનિક</b> — સંભાળી લીધું, હજી ચાલુ છે",
+	"notice.update":        "⬆️ <b>અપડેટ ઉપલબ્ધ: %s</b>\nહાલ %s ચાલે છે.",
+	"notice.updating":      "⬆️ <b>%s પર અપડેટ થાય છે</b>\nપહેલાં %s ચાલતું હતું; હવે ફરી શરૂ થાય છે.",
+	"notice.release_notes": "\n<a href=\"%s\">રિલીઝ નોંધ</a>",
+
+	// /slareport
+	"slareport.usage":       "❌ ઉપયોગ: <code>/slareport [YYYY-MM]</code> (મૂળભૂત: ગયો મહિનો).",
+	"slareport.failed":      "❌ SLA રિપોર્ટ બનાવી શકાયો નહીં: %s",
+	"slareport.title":       "📑 <b>SLA પાલન, %s</b>",
+	"slareport.none":        "\nએ મહિને કોઈ ફરિયાદનો નિકાલ થયો નથી.",
+	"slareport.total":       "\n✅ %d નો નિકાલ સમયમર્યાદામાં, કુલ %d માંથી (%.1f%%)",
+	"slareport.row":         "\n• %s: %.1f%% (%d માંથી)",
+	"slareport.caption":     "📑 SLA રિપોર્ટ, %s",
+	"slareport.send_failed": "❌ %s રિપોર્ટ મોકલી શકાયો નહીં: %s",
+}
@@ -0,0 +1,79 @@
+// Package i18n holds the bot's own texts — prompts, resolved cards, alerts
+// and summary captions — in English and Gujarati, so field teams can run
+// the bot in either (BOT_LANGUAGE).
+//
+// Texts are looked up by key with T, in the language chosen once at
+// startup with SetLanguage. Complaint fields (names, descriptions) are not
+// translated here; see package translate and BILINGUAL_FIELDS for those.
+// The summary table images keep their English headings: their font has no
+// Gujarati glyphs.
+package i18n
+
+import (
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Languages, as set in BOT_LANGUAGE.
+const (
+	English  = "en"
+	Gujarati = "gu"
+)
+
+// catalogs maps a language to its texts by key. English is complete and
+// the fallback for keys another catalog lacks.
+var catalogs = map[string]map[string]string{
+	English:  en,
+	Gujarati: gu,
+}
+
+var (
+	mu   sync.RWMutex
+	lang = English
+)
+
+// Supported reports whether l is a language with a catalog.
+func Supported(l string) bool {
+	_, ok := catalogs[l]
+	return ok
+}
+
+// SetLanguage selects the language T answers in. Empty means English; an
+// unsupported one is logged and leaves English.
+func SetLanguage(l string) {
+	if l == "" {
+		l = English
+	}
+	if !Supported(l) {
+		log.Printf("⚠️  Unsupported bot language %q; using English", l)
+		l = English
+	}
+	mu.Lock()
+	lang = l
+	mu.Unlock()
+}
+
+// Language returns the language T answers in.
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return lang
+}
+
+// T returns the text for key in the current language, formatted with args
+// as by fmt.Sprintf when there are any. A key the language lacks falls
+// back to English, and an unknown key comes back as itself so the gap
+// shows in the chat rather than as an empty message.
+func T(key string, args ...any) string {
+	text, ok := catalogs[Language()][key]
+	if !ok {
+		if text, ok = en[key]; !ok {
+			text = key
+		}
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
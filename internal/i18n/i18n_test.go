@@ -0,0 +1,101 @@
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var verb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// TestCatalogsMatchEnglish keeps every catalog complete and its format
+// verbs in step with English, so a translated text never renders
+// "%!d(MISSING)".
+func TestCatalogsMatchEnglish(t *testing.T) {
+	for name, cat := range catalogs {
+		for key, want := range en {
+			got, ok := cat[key]
+			if !ok {
+				t.Errorf("%s: missing %q", name, key)
+				continue
+			}
+			if w, g := verb.FindAllString(want, -1), verb.FindAllString(got, -1); strings.Join(w, " ") != strings.Join(g, " ") {
+				t.Errorf("%s %q: verbs %v, want %v", name, key, g, w)
+			}
+		}
+		var extra []string
+		for key := range cat {
+			if _, ok := en[key]; !ok {
+				extra = append(extra, key)
+			}
+		}
+		sort.Strings(extra)
+		if len(extra) > 0 {
+			t.Errorf("%s: keys not in English: %v", name, extra)
+		}
+	}
+}
+
+// usedKey matches the catalog keys the code passes to T as literals.
+var usedKey = regexp.MustCompile(`i18n\.T\("([^"]+)"`)
+
+// TestUsedKeysExist scans the module's Go sources so a key used in code but
+// missing from English (and so shown to users as the bare key) fails here.
+func TestUsedKeysExist(t *testing.T) {
+	root := filepath.Join("..", "..")
+	var checked int
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name := d.Name(); path != root && (strings.HasPrefix(name, ".") || name == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, m := range usedKey.FindAllSubmatch(src, -1) {
+			checked++
+			if _, ok := en[string(m[1])]; !ok {
+				t.Errorf("%s: key %q is not in the English catalog", path, m[1])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checked == 0 {
+		t.Fatal("found no i18n.T calls; is the module root right?")
+	}
+}
+
+func TestT(t *testing.T) {
+	t.Cleanup(func() { SetLanguage(English) })
+
+	if got := T("summary.caption", 3); got != "📋 3 Pending Complaints" {
+		t.Errorf("English caption = %q", got)
+	}
+	SetLanguage(Gujarati)
+	if got := T("summary.caption", 3); got != "📋 3 બાકી ફરિયાદો" {
+		t.Errorf("Gujarati caption = %q", got)
+	}
+	if got := T("no.such.key"); got != "no.such.key" {
+		t.Errorf("unknown key = %q, want the key back", got)
+	}
+	SetLanguage("fr")
+	if Language() != English {
+		t.Errorf("unsupported language left %q, want English", Language())
+	}
+}
@@ -0,0 +1,156 @@
+// Package leader lets redundant cmon replicas agree on which one runs.
+// Replicas share a working directory (cmon.db must be shared anyway, so a
+// takeover starts from the same state); a lease row in a small SQLite file
+// there names the current leader and expires unless renewed. The leader
+// fetches and notifies; the others wait warm and take over once the lease
+// runs out.
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// DefaultFile is the lease database's name under DATA_DIR unless
+// LEADER_LEASE_FILE says otherwise. It is kept apart from cmon.db so a
+// standby polling the lease never contends with the leader's writes.
+const DefaultFile = "cmon-leader.db"
+
+// leaseName is the row replicas compete for.
+const leaseName = "cmon"
+
+// ErrLost is returned by Hold when another replica owns the lease.
+var ErrLost = errors.New("leadership lost to another instance")
+
+// Lease is one replica's handle on the shared lease.
+type Lease struct {
+	// ID names this replica in the lease; it must differ between replicas.
+	ID string
+	// TTL is how long the lease lasts without renewal: how long a standby
+	// waits after the leader dies. It is renewed every TTL/3.
+	TTL time.Duration
+
+	db  *sql.DB
+	now func() time.Time
+}
+
+// Open opens (creating if needed) the lease database at path for the
+// replica id.
+func Open(path, id string, ttl time.Duration) (*Lease, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("open lease database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS leases (
+		name TEXT PRIMARY KEY,
+		holder TEXT NOT NULL,
+		expires_at INTEGER NOT NULL
+	)`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create lease table: %w", err)
+	}
+	return &Lease{ID: id, TTL: ttl, db: db, now: time.Now}, nil
+}
+
+// TryAcquire takes or renews the lease, reporting whether this replica
+// holds it now. It succeeds when the lease is free, expired or already
+// ours.
+func (l *Lease) TryAcquire() (bool, error) {
+	now := l.now()
+	res, err := l.db.Exec(`
+		INSERT INTO leases (name, holder, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET holder = excluded.holder, expires_at = excluded.expires_at
+		WHERE leases.holder = excluded.holder OR leases.expires_at <= ?
+	`, leaseName, l.ID, now.Add(l.TTL).UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return false, fmt.Errorf("acquire lease: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("acquire lease: %w", err)
+	}
+	return n == 1, nil
+}
+
+// Holder returns the replica holding the lease, or "" when it is free or
+// expired.
+func (l *Lease) Holder() (string, error) {
+	var holder string
+	err := l.db.QueryRow(`SELECT holder FROM leases WHERE name = ? AND expires_at > ?`, leaseName, l.now().UnixMilli()).Scan(&holder)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return holder, err
+}
+
+// Release gives the lease up if this replica holds it, so a standby can
+// take over at once instead of waiting out the TTL.
+func (l *Lease) Release() error {
+	_, err := l.db.Exec(`DELETE FROM leases WHERE name = ? AND holder = ?`, leaseName, l.ID)
+	return err
+}
+
+// Close closes the lease database. It does not release the lease.
+func (l *Lease) Close() error {
+	return l.db.Close()
+}
+
+// Acquire blocks until this replica holds the lease or ctx is done.
+func (l *Lease) Acquire(ctx context.Context) error {
+	logged := ""
+	for {
+		ok, err := l.TryAcquire()
+		switch {
+		case err != nil:
+			log.Printf("⚠️  Leader election: %v", err)
+		case ok:
+			return nil
+		default:
+			if holder, _ := l.Holder(); holder != logged {
+				log.Printf("⏸️  Standing by: %s is the leader", holder)
+				logged = holder
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(l.TTL / 3):
+		}
+	}
+}
+
+// Hold renews the lease every TTL/3 until ctx is done, when it returns
+// nil. It returns ErrLost as soon as another replica has the lease, and
+// also once renewals have failed for a whole TTL: by then a standby may
+// have taken over.
+func (l *Lease) Hold(ctx context.Context) error {
+	ticker := time.NewTicker(l.TTL / 3)
+	defer ticker.Stop()
+	renewed := l.now()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		ok, err := l.TryAcquire()
+		switch {
+		case err != nil:
+			log.Printf("⚠️  Failed to renew the leader lease: %v", err)
+			if l.now().Sub(renewed) >= l.TTL {
+				return fmt.Errorf("%w: lease not renewed for %s", ErrLost, l.TTL)
+			}
+		case !ok:
+			return ErrLost
+		default:
+			renewed = l.now()
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openPair(t *testing.T, ttl time.Duration) (a, b *Lease) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	var err error
+	if a, err = Open(path, "a", ttl); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { a.Close() })
+	if b, err = Open(path, "b", ttl); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { b.Close() })
+	return a, b
+}
+
+func TestLeaseTakeoverAfterExpiry(t *testing.T) {
+	a, b := openPair(t, time.Minute)
+	clock := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return clock }
+	b.now = a.now
+
+	if ok, err := a.TryAcquire(); !ok || err != nil {
+		t.Fatalf("a on a free lease: %v, %v", ok, err)
+	}
+	if ok, err := b.TryAcquire(); ok || err != nil {
+		t.Fatalf("b while a holds it: %v, %v", ok, err)
+	}
+	if h, _ := b.Holder(); h != "a" {
+		t.Errorf("Holder = %q, want a", h)
+	}
+
+	clock = clock.Add(50 * time.Second)
+	if ok, _ := a.TryAcquire(); !ok {
+		t.Fatal("a should renew its own lease")
+	}
+	clock = clock.Add(50 * time.Second) // within the renewed TTL
+	if ok, _ := b.TryAcquire(); ok {
+		t.Fatal("b took a renewed lease")
+	}
+
+	clock = clock.Add(11 * time.Second) // a stopped renewing a minute ago
+	if h, _ := b.Holder(); h != "" {
+		t.Errorf("expired lease still held by %q", h)
+	}
+	if ok, _ := b.TryAcquire(); !ok {
+		t.Fatal("b should take over an expired lease")
+	}
+	if ok, _ := a.TryAcquire(); ok {
+		t.Fatal("a must not win back a lease b holds")
+	}
+}
+
+func TestReleaseHandsOverAtOnce(t *testing.T) {
+	a, b := openPair(t, time.Hour)
+	if ok, _ := a.TryAcquire(); !ok {
+		t.Fatal("a should get a free lease")
+	}
+	if err := b.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if h, _ := a.Holder(); h != "a" {
+		t.Fatalf("b released a's lease; holder = %q", h)
+	}
+	if err := a.Release(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := b.Acquire(ctx); err != nil {
+		t.Fatalf("b.Acquire after release: %v", err)
+	}
+}
+
+func TestHoldReportsLoss(t *testing.T) {
+	a, b := openPair(t, 30*time.Millisecond)
+	if ok, _ := a.TryAcquire(); !ok {
+		t.Fatal("a should get a free lease")
+	}
+	// b steals it, as if a had been paused past its TTL.
+	if _, err := b.db.Exec(`UPDATE leases SET holder = 'b'`); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- a.Hold(context.Background()) }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrLost) {
+			t.Errorf("Hold = %v, want ErrLost", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Hold did not notice the lost lease")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Hold(ctx); err != nil {
+		t.Errorf("Hold on a cancelled context = %v, want nil", err)
+	}
+}
@@ -2,7 +2,8 @@
 //
 // The default text mode (LOG_FORMAT=text or unset) installs slog's text
 // handler so terminal output stays human-readable. LOG_FORMAT=json installs
-// slog.JSONHandler instead so log aggregators can parse every line.
+// slog.JSONHandler instead so log aggregators can parse every line. LOG_FILE
+// additionally writes the same output to a size-rotated file (RotatingFile).
 //
 // In both modes, the standard library's package-level `log` logger is
 // rerouted through slog so packages still using `log.Printf` produce output
@@ -12,6 +13,7 @@
 package logging
 
 import (
+	"fmt"
 	"io"
 	"log"
 	"log/slog"
@@ -19,16 +21,25 @@ import (
 	"strings"
 )
 
+// level is shared by every handler Setup installs so SetLevel can change
+// verbosity at runtime (the /loglevel admin command). Defaults to INFO.
+var level = new(slog.LevelVar)
+
 // Setup installs the slog default handler implied by format and re-routes
 // the stdlib log package to it. format is matched case-insensitively; an
-// unrecognised value falls back to text mode.
-func Setup(format string) {
+// unrecognised value falls back to text mode. Output goes to stderr and to
+// every extra writer (LOG_FILE's RotatingFile), each record in one Write.
+func Setup(format string, extra ...io.Writer) {
+	var out io.Writer = os.Stderr
+	if len(extra) > 0 {
+		out = io.MultiWriter(append([]io.Writer{os.Stderr}, extra...)...)
+	}
 	var handler slog.Handler
 	switch strings.ToLower(strings.TrimSpace(format)) {
 	case "json":
-		handler = slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo})
+		handler = slog.NewJSONHandler(out, &slog.HandlerOptions{Level: level})
 	default:
-		handler = slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo})
+		handler = slog.NewTextHandler(out, &slog.HandlerOptions{Level: level})
 	}
 	slog.SetDefault(slog.New(handler))
 
@@ -39,6 +50,22 @@ func Setup(format string) {
 	log.SetOutput(slogWriter{})
 }
 
+// SetLevel changes the minimum level logged: "debug", "info", "warn" or
+// "error" (case-insensitive). It takes effect immediately.
+func SetLevel(name string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return fmt.Errorf("unknown log level %q (want debug, info, warn, error)", name)
+	}
+	level.Set(l)
+	return nil
+}
+
+// Level returns the current minimum level in lowercase, e.g. "info".
+func Level() string {
+	return strings.ToLower(level.Level().String())
+}
+
 // slogWriter forwards each Write to slog.Default at INFO level. The stdlib
 // log package writes one full line per Write call, so this is one log entry.
 type slogWriter struct{}
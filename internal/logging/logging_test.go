@@ -111,3 +111,22 @@ func TestSetupUnknownFormatFallsBackToText(t *testing.T) {
 		t.Fatal("Setup left slog.Default nil")
 	}
 }
+
+func TestSetLevel(t *testing.T) {
+	t.Cleanup(func() { level.Set(slog.LevelInfo) })
+
+	for _, name := range []string{"debug", "WARN", " error ", "info"} {
+		if err := SetLevel(name); err != nil {
+			t.Fatalf("SetLevel(%q): %v", name, err)
+		}
+		if got, want := Level(), strings.ToLower(strings.TrimSpace(name)); got != want {
+			t.Errorf("Level() after SetLevel(%q) = %q, want %q", name, got, want)
+		}
+	}
+	if err := SetLevel("verbose"); err == nil {
+		t.Error("unknown level should error")
+	}
+	if Level() != "info" {
+		t.Errorf("failed SetLevel should leave the level alone, got %q", Level())
+	}
+}
@@ -0,0 +1,153 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeLayout stamps rotated files in UTC, e.g.
+// cmon-20260310-091500.000.log.
+const backupTimeLayout = "20060102-150405.000"
+
+// rotateNow is swapped by tests to control backup names and ages.
+var rotateNow = time.Now
+
+// RotatingFile is an io.Writer appending to a log file that is rotated once
+// it would grow past maxSize. Rotated files are renamed with a timestamp
+// next to the original; only the newest maxBackups are kept, and any older
+// than maxAge are removed. Safe for concurrent use.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	maxAge     time.Duration
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+// OpenRotating opens (or creates) path for appending, creating its
+// directory if needed. maxSize <= 0 never rotates; maxBackups <= 0 keeps
+// every backup and maxAge <= 0 never expires one.
+func OpenRotating(path string, maxSize int64, maxBackups int, maxAge time.Duration) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups, maxAge: maxAge}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create log directory: %w", err)
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write implements io.Writer. A single write larger than maxSize still goes
+// to one file rather than being split.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.f == nil {
+		return 0, os.ErrClosed
+	}
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			// Keep logging to the current file rather than losing lines.
+			fmt.Fprintf(os.Stderr, "log rotation failed: %v\n", err)
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file. Later writes fail with os.ErrClosed.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+// rotate moves the current file aside and starts a new one. Caller holds
+// r.mu.
+func (r *RotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+	base, ext := r.backupParts()
+	backup := base + rotateNow().UTC().Format(backupTimeLayout) + ext
+	if err := os.Rename(r.path, backup); err != nil {
+		// Reopen so writes keep working even though rotation failed.
+		if oerr := r.open(); oerr != nil {
+			return oerr
+		}
+		return err
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+	r.prune()
+	return nil
+}
+
+// backupParts splits path into the prefix and extension backups share:
+// "logs/cmon.log" → "logs/cmon-", ".log".
+func (r *RotatingFile) backupParts() (string, string) {
+	ext := filepath.Ext(r.path)
+	return strings.TrimSuffix(r.path, ext) + "-", ext
+}
+
+// prune removes backups beyond maxBackups and older than maxAge. Errors are
+// ignored: a leftover backup is harmless.
+func (r *RotatingFile) prune() {
+	base, ext := r.backupParts()
+	matches, err := filepath.Glob(base + "*" + ext)
+	if err != nil {
+		return
+	}
+	type backup struct {
+		path string
+		at   time.Time
+	}
+	var backups []backup
+	for _, m := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(m, base), ext)
+		at, err := time.Parse(backupTimeLayout, stamp)
+		if err != nil {
+			continue // not one of ours
+		}
+		backups = append(backups, backup{m, at})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].at.After(backups[j].at) })
+
+	cutoff := rotateNow().Add(-r.maxAge)
+	for i, b := range backups {
+		if (r.maxBackups > 0 && i >= r.maxBackups) || (r.maxAge > 0 && b.at.Before(cutoff)) {
+			_ = os.Remove(b.path)
+		}
+	}
+}
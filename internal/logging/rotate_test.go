@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFileRotatesAndPrunes(t *testing.T) {
+	clock := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	rotateNow = func() time.Time { return clock }
+	t.Cleanup(func() { rotateNow = time.Now })
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs", "cmon.log")
+	r, err := OpenRotating(path, 10, 2, 0)
+	if err != nil {
+		t.Fatalf("OpenRotating: %v", err)
+	}
+	defer r.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		clock = clock.Add(time.Minute)
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	if got, _ := os.ReadFile(path); string(got) != "fourth\n" {
+		t.Errorf("current file: got %q", got)
+	}
+	backups, _ := filepath.Glob(filepath.Join(dir, "logs", "cmon-*.log"))
+	sort.Strings(backups)
+	if len(backups) != 2 {
+		t.Fatalf("want the 2 newest backups, got %v", backups)
+	}
+	if got, _ := os.ReadFile(backups[1]); string(got) != "third\n" || !strings.HasSuffix(backups[1], "cmon-20260310-090400.000.log") {
+		t.Errorf("newest backup %s: got %q", backups[1], got)
+	}
+}
+
+func TestRotatingFileExpiresOldBackups(t *testing.T) {
+	clock := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	rotateNow = func() time.Time { return clock }
+	t.Cleanup(func() { rotateNow = time.Now })
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cmon.log")
+	stale := filepath.Join(dir, "cmon-20260301-000000.000.log")
+	foreign := filepath.Join(dir, "cmon-notes.log")
+	for _, p := range []string{stale, foreign} {
+		if err := os.WriteFile(p, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(path, []byte("existing line\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := OpenRotating(path, 16, 0, 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("OpenRotating: %v", err)
+	}
+	defer r.Close()
+	if _, err := r.Write([]byte("new line\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("backup older than maxAge should be removed")
+	}
+	if _, err := os.Stat(foreign); err != nil {
+		t.Error("files that aren't backups must be left alone")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cmon-20260310-090000.000.log")); err != nil {
+		t.Errorf("existing content should be rotated on the first overflowing write: %v", err)
+	}
+}
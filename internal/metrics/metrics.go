@@ -10,6 +10,8 @@
 // Counters are monotonically increasing uint64s. Gauges are int64 settable
 // from anywhere. Labelled gauges are populated at scrape time by a callback
 // (used for "open complaints by belt") so they always reflect live state.
+// Counter vectors and histograms take an optional label (e.g. the Telegram
+// API method) whose values appear as they are first used.
 package metrics
 
 import (
@@ -71,6 +73,89 @@ func (g *Gauge) SetToCurrentTime(unixSeconds int64) {
 	g.Set(unixSeconds)
 }
 
+// CounterVec is a family of counters told apart by one label.
+type CounterVec struct {
+	name, help, labelKey string
+	mu                   sync.Mutex
+	values               map[string]uint64
+}
+
+// Inc adds one to the counter for label.
+func (c *CounterVec) Inc(label string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.values[label]++
+	c.mu.Unlock()
+}
+
+// Value returns the counter for label, for tests.
+func (c *CounterVec) Value(label string) uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[label]
+}
+
+// Histogram counts observations (e.g. latencies in seconds) into
+// cumulative buckets, with their sum and count, per value of its label
+// when it has one.
+type Histogram struct {
+	name, help, labelKey string
+	buckets              []float64 // upper bounds, ascending; +Inf is implied
+	mu                   sync.Mutex
+	series               map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// Observe records v in a histogram without a label.
+func (h *Histogram) Observe(v float64) {
+	h.ObserveLabel("", v)
+}
+
+// ObserveLabel records v in the series for label.
+func (h *Histogram) ObserveLabel(label string, v float64) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.series[label]
+	if s == nil {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[label] = s
+	}
+	for i, le := range h.buckets {
+		if v <= le {
+			s.counts[i]++
+			break
+		}
+	}
+	s.sum += v
+	s.count++
+}
+
+// Count returns the observations recorded for label, for tests.
+func (h *Histogram) Count(label string) uint64 {
+	if h == nil {
+		return 0
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s := h.series[label]; s != nil {
+		return s.count
+	}
+	return 0
+}
+
 // labelledGauge is a gauge family populated by a callback at scrape time.
 // fn returns label-value → numeric-value; the label key is fixed at construction.
 type labelledGauge struct {
@@ -83,6 +168,8 @@ type Registry struct {
 	mu             sync.RWMutex
 	counters       []*Counter
 	gauges         []*Gauge
+	counterVecs    []*CounterVec
+	histograms     []*Histogram
 	labelledGauges []*labelledGauge
 }
 
@@ -119,6 +206,37 @@ func (r *Registry) NewGauge(name, help string) *Gauge {
 	return g
 }
 
+// NewCounterVec creates and registers a counter family labelled by
+// labelKey. Panics on duplicate name.
+func (r *Registry) NewCounterVec(name, help, labelKey string) *CounterVec {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.counterVecs {
+		if c.name == name {
+			panic("metrics: duplicate counter " + name)
+		}
+	}
+	c := &CounterVec{name: name, help: help, labelKey: labelKey, values: make(map[string]uint64)}
+	r.counterVecs = append(r.counterVecs, c)
+	return c
+}
+
+// NewHistogram creates and registers a histogram with the given ascending
+// bucket upper bounds, labelled by labelKey unless it is empty. Panics on
+// duplicate name.
+func (r *Registry) NewHistogram(name, help, labelKey string, buckets []float64) *Histogram {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, h := range r.histograms {
+		if h.name == name {
+			panic("metrics: duplicate histogram " + name)
+		}
+	}
+	h := &Histogram{name: name, help: help, labelKey: labelKey, buckets: buckets, series: make(map[string]*histogramSeries)}
+	r.histograms = append(r.histograms, h)
+	return h
+}
+
 // RegisterLabelledGauge registers a callback-based gauge family. The callback
 // is invoked on every scrape and must return label-value → numeric-value.
 // Use this for metrics derived from live storage (open complaints by belt).
@@ -153,6 +271,16 @@ func (r *Registry) Encode(w io.Writer) error {
 			return err
 		}
 	}
+	for _, c := range r.counterVecs {
+		if err := c.encode(w); err != nil {
+			return err
+		}
+	}
+	for _, h := range r.histograms {
+		if err := h.encode(w); err != nil {
+			return err
+		}
+	}
 	for _, lg := range r.labelledGauges {
 		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n",
 			lg.name, lg.help, lg.name); err != nil {
@@ -175,6 +303,63 @@ func (r *Registry) Encode(w io.Writer) error {
 	return nil
 }
 
+func (c *CounterVec) encode(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name); err != nil {
+		return err
+	}
+	for _, k := range sortedKeys(c.values) {
+		if _, err := fmt.Fprintf(w, "%s{%s=\"%s\"} %d\n", c.name, c.labelKey, escapeLabelValue(k), c.values[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *Histogram) encode(w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name); err != nil {
+		return err
+	}
+	for _, k := range sortedKeys(h.series) {
+		s := h.series[k]
+		labels := ""
+		if h.labelKey != "" {
+			labels = fmt.Sprintf("%s=\"%s\",", h.labelKey, escapeLabelValue(k))
+		}
+		var cumulative uint64
+		for i, le := range h.buckets {
+			cumulative += s.counts[i]
+			if _, err := fmt.Fprintf(w, "%s_bucket{%sle=\"%g\"} %d\n", h.name, labels, le, cumulative); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket{%sle=\"+Inf\"} %d\n", h.name, labels, s.count); err != nil {
+			return err
+		}
+		suffix := ""
+		if labels != "" {
+			suffix = "{" + strings.TrimSuffix(labels, ",") + "}"
+		}
+		if _, err := fmt.Fprintf(w, "%s_sum%s %g\n%s_count%s %d\n", h.name, suffix, s.sum, h.name, suffix, s.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns m's keys in order, so scrape diffs stay readable.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // escapeLabelValue escapes the three characters the Prometheus text format
 // requires escaping inside a label value: backslash, double quote, newline.
 func escapeLabelValue(v string) string {
@@ -110,3 +110,35 @@ func TestDuplicateNamePanics(t *testing.T) {
 	}()
 	r.NewCounter("x", "")
 }
+
+func TestEncodeHistogramAndCounterVec(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogram("req_seconds", "req description", "method", []float64{0.5, 2})
+	h.ObserveLabel("sendMessage", 0.2)
+	h.ObserveLabel("sendMessage", 1)
+	h.ObserveLabel("sendMessage", 5)
+	c := r.NewCounterVec("req_errors_total", "errors", "method")
+	c.Inc("editMessageText")
+	plain := r.NewHistogram("e2e_seconds", "e2e", "", []float64{60})
+	plain.Observe(30)
+
+	var buf bytes.Buffer
+	if err := r.Encode(&buf); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	for _, want := range []string{
+		"# TYPE req_seconds histogram\n",
+		`req_seconds_bucket{method="sendMessage",le="0.5"} 1` + "\n",
+		`req_seconds_bucket{method="sendMessage",le="2"} 2` + "\n",
+		`req_seconds_bucket{method="sendMessage",le="+Inf"} 3` + "\n",
+		`req_seconds_sum{method="sendMessage"} 6.2` + "\n",
+		`req_seconds_count{method="sendMessage"} 3` + "\n",
+		`req_errors_total{method="editMessageText"} 1` + "\n",
+		`e2e_seconds_bucket{le="60"} 1` + "\n",
+		"e2e_seconds_count 1\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, buf.String())
+		}
+	}
+}
@@ -21,10 +21,30 @@ var (
 		"cmon_fetch_failures_total",
 		"Total number of complaint fetch cycles that ended in error.",
 	)
+	PortalErrorsTotal = Default.NewCounter(
+		"cmon_portal_errors_total",
+		"Total number of fetch cycles that hit a portal error or maintenance page.",
+	)
 	ComplaintsSeenTotal = Default.NewCounter(
 		"cmon_complaints_seen_total",
 		"Total number of new complaints observed (post-dedupe).",
 	)
+	DeadLettersTotal = Default.NewCounter(
+		"cmon_dead_letters_total",
+		"Total number of complaint processing failures recorded for retry.",
+	)
+	ComplaintsFilteredTotal = Default.NewCounter(
+		"cmon_complaints_filtered_total",
+		"Total number of new complaints stored without an announcement because an ignore rule matched.",
+	)
+	ComplaintsReopenedTotal = Default.NewCounter(
+		"cmon_complaints_reopened_total",
+		"Total number of resolved complaints that reappeared on the dashboard.",
+	)
+	OutageAlertsTotal = Default.NewCounter(
+		"cmon_outage_alerts_total",
+		"Total number of area-level possible-outage alerts raised.",
+	)
 	ResolveCallsTotal = Default.NewCounter(
 		"cmon_resolve_calls_total",
 		"Total number of DGVCL resolve API calls made.",
@@ -49,6 +69,63 @@ var (
 		"cmon_whatsapp_send_failures_total",
 		"Total number of failed WhatsApp outbound sends.",
 	)
+	PanicsTotal = Default.NewCounter(
+		"cmon_panics_total",
+		"Total number of panics recovered in the fetch loop, handlers and workers.",
+	)
+	EventsDroppedTotal = Default.NewCounter(
+		"cmon_events_dropped_total",
+		"Total number of events dropped because an asynchronous subscriber's queue was full.",
+	)
+	ComplaintsResolvedTotal = Default.NewCounter(
+		"cmon_complaints_resolved_total",
+		"Total number of complaints resolved.",
+	)
+	ComplaintsResolvedOverdueTotal = Default.NewCounter(
+		"cmon_complaints_resolved_overdue_total",
+		"Total number of complaints resolved after AGE_OVERDUE_AFTER.",
+	)
+	ResolutionSecondsTotal = Default.NewCounter(
+		"cmon_resolution_seconds_total",
+		"Total seconds from first seen to resolved over all resolved complaints; divide by cmon_complaints_resolved_total for the mean.",
+	)
+
+	TelegramRequestErrorsTotal = Default.NewCounterVec(
+		"cmon_telegram_request_errors_total",
+		"Total number of Telegram Bot API requests that failed (network error or ok=false other than 429), by method.",
+		"method",
+	)
+	TelegramRateLimitedTotal = Default.NewCounterVec(
+		"cmon_telegram_rate_limited_total",
+		"Total number of Telegram Bot API requests answered with 429 Too Many Requests, by method.",
+		"method",
+	)
+	TelegramRequestSeconds = Default.NewHistogram(
+		"cmon_telegram_request_duration_seconds",
+		"Telegram Bot API request latency per attempt, by method (getUpdates is a long poll).",
+		"method",
+		[]float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	)
+	TelegramNotificationSeconds = Default.NewHistogram(
+		"cmon_telegram_notification_latency_seconds",
+		"Seconds from a complaint being found on the dashboard to its Telegram message being sent, quiet-hours holds and retries included.",
+		"",
+		[]float64{5, 15, 30, 60, 120, 300, 600, 1800, 3600, 14400},
+	)
+
+	RecordCacheHitsTotal = Default.NewCounter(
+		"cmon_record_cache_hits_total",
+		"Total number of complaint-record lookups served from the cache instead of the DGVCL API.",
+	)
+
+	TranslationQueueLength = Default.NewGauge(
+		"cmon_translation_queue_length",
+		"Number of announced complaints waiting for their Gujarati translation.",
+	)
+	TranslationsRateLimitedTotal = Default.NewCounter(
+		"cmon_translations_rate_limited_total",
+		"Total number of Gemini translation calls answered with 429 Too Many Requests.",
+	)
 
 	LastFetchSuccessUnixSeconds = Default.NewGauge(
 		"cmon_last_fetch_success_unix_seconds",
@@ -77,6 +154,28 @@ func RegisterOpenComplaintsByBelt(fn func() map[string]int) {
 	)
 }
 
+// RegisterOpenComplaintsByFeeder wires the `cmon_open_complaints_by_feeder`
+// gauge family to a live storage query, like RegisterOpenComplaintsByBelt.
+// Call it only when a feeder map is configured.
+func RegisterOpenComplaintsByFeeder(fn func() map[string]int) {
+	if fn == nil {
+		return
+	}
+	Default.RegisterLabelledGauge(
+		"cmon_open_complaints_by_feeder",
+		"Number of currently-open (unresolved) complaints, partitioned by feeder (empty when the locality is unmapped).",
+		"feeder",
+		func() map[string]float64 {
+			counts := fn()
+			out := make(map[string]float64, len(counts))
+			for k, v := range counts {
+				out[k] = float64(v)
+			}
+			return out
+		},
+	)
+}
+
 // Handler returns an http.Handler that serves the default registry.
 func Handler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
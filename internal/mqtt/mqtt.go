@@ -0,0 +1,270 @@
+// Package mqtt publishes complaint events to an MQTT broker for SCADA and
+// IoT dashboards. New and resolved complaints go out as JSON (the webhook
+// format), and the open-complaint count of each belt as a retained plain
+// number, so a dashboard that subscribes late sees the current counts at
+// once. Topics, under the configured prefix:
+//
+//	<prefix>/complaints/new       complaint.new and complaint.reopened events
+//	<prefix>/complaints/resolved  complaint.resolved events
+//	<prefix>/open/<belt>          open complaints in the belt (retained)
+//	<prefix>/open/total           all open complaints (retained)
+//	<prefix>/status               "online", or "offline" once cmon is gone (retained)
+//
+// Belt names are lower-cased for topics, with anything but letters,
+// digits, "-" and "_" replaced by "_"; complaints without a belt count
+// under "unknown".
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"cmon/internal/eventbus"
+	"cmon/internal/metrics"
+	"cmon/internal/notify"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+// queueSize is how many messages may wait for the broker before new ones
+// are dropped.
+const queueSize = 256
+
+// publishTimeout bounds the wait for the broker to acknowledge a message.
+const publishTimeout = 10 * time.Second
+
+// Config configures New.
+type Config struct {
+	BrokerURL   string // tcp://host:1883, ssl://host:8883, ws://host/mqtt
+	ClientID    string
+	Username    string
+	Password    string
+	TopicPrefix string
+	QoS         byte
+	// Redact masks mobile and consumer numbers in complaint payloads.
+	Redact bool
+}
+
+// message is one MQTT publish waiting in the queue.
+type message struct {
+	topic    string
+	retained bool
+	payload  []byte
+}
+
+// Publisher is an eventbus subscriber that publishes to the broker.
+type Publisher struct {
+	cfg    Config
+	counts func() map[string]int // open complaints by belt (storage)
+	send   func(message) error
+	client paho.Client // nil in tests
+
+	queue chan message
+	done  chan struct{}
+
+	mu     sync.Mutex
+	last   map[string]int // count topics as last queued; nil = send all
+	closed bool
+}
+
+// New connects to the broker in the background (reconnecting as needed)
+// and returns the Publisher; a broker that is down at startup is retried
+// rather than failing. counts returns the open complaints by belt, as
+// storage.GetPendingCountsByBelt does.
+func New(cfg Config, counts func() map[string]int) *Publisher {
+	p := newPublisher(cfg, counts, nil)
+
+	status := p.topic("status")
+	opts := paho.NewClientOptions().
+		AddBroker(cfg.BrokerURL).
+		SetClientID(cfg.ClientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetBinaryWill(status, []byte("offline"), cfg.QoS, true).
+		SetOnConnectHandler(func(paho.Client) {
+			log.Printf("✓ MQTT connected to %s", cfg.BrokerURL)
+			p.enqueue(message{topic: status, retained: true, payload: []byte("online")})
+			p.resync()
+		}).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			log.Printf("⚠️  MQTT connection lost, reconnecting: %v", err)
+		})
+	p.client = paho.NewClient(opts)
+	p.send = func(m message) error {
+		t := p.client.Publish(m.topic, cfg.QoS, m.retained, m.payload)
+		if !t.WaitTimeout(publishTimeout) {
+			return fmt.Errorf("publish to %s: no acknowledgement within %s", m.topic, publishTimeout)
+		}
+		return t.Error()
+	}
+	p.client.Connect()
+	go p.run()
+	return p
+}
+
+// newPublisher builds a Publisher around send without starting it.
+func newPublisher(cfg Config, counts func() map[string]int, send func(message) error) *Publisher {
+	return &Publisher{
+		cfg:    cfg,
+		counts: counts,
+		send:   send,
+		queue:  make(chan message, queueSize),
+		done:   make(chan struct{}),
+	}
+}
+
+// run publishes queued messages until Close.
+func (p *Publisher) run() {
+	defer close(p.done)
+	for m := range p.queue {
+		if err := p.send(m); err != nil {
+			log.Printf("⚠️  MQTT: %v", err)
+		}
+	}
+}
+
+// Close publishes what is queued, marks cmon offline and disconnects.
+func (p *Publisher) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	p.queue <- message{topic: p.topic("status"), retained: true, payload: []byte("offline")}
+	close(p.queue)
+	p.mu.Unlock()
+
+	<-p.done
+	if p.client != nil {
+		p.client.Disconnect(250)
+	}
+}
+
+// Handle is an eventbus.Handler. It only queues messages, so a slow or
+// unreachable broker never holds up the publisher.
+func (p *Publisher) Handle(e eventbus.Event) error {
+	switch e := e.(type) {
+	case eventbus.ComplaintNew:
+		p.publishEvent("complaints/new", notify.ComplaintEvent(p.complaint(e.Complaint)))
+		p.publishCounts(false, "")
+	case eventbus.ComplaintsNew:
+		for _, c := range e.Complaints {
+			p.publishEvent("complaints/new", notify.ComplaintEvent(p.complaint(c)))
+		}
+		p.publishCounts(false, "")
+	case eventbus.ComplaintResolved:
+		p.publishEvent("complaints/resolved", notify.ResolvedEvent(e.Status))
+		// Published before the complaint leaves storage: don't count it.
+		p.publishCounts(true, e.Status.Belt)
+	}
+	return nil
+}
+
+func (p *Publisher) complaint(c notify.Complaint) notify.Complaint {
+	if p.cfg.Redact {
+		return c.Redacted()
+	}
+	return c
+}
+
+func (p *Publisher) publishEvent(topic string, ev notify.WebhookEvent) {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		log.Printf("⚠️  MQTT: encode %s event: %v", ev.Event, err)
+		return
+	}
+	p.enqueue(message{topic: p.topic(topic), payload: payload})
+}
+
+// resync queues every count again, as after a reconnect.
+func (p *Publisher) resync() {
+	p.mu.Lock()
+	p.last = nil
+	p.mu.Unlock()
+	p.publishCounts(false, "")
+}
+
+// publishCounts queues the count topics that changed since the last call,
+// leaving out one complaint of belt when resolving is set. A belt whose
+// last complaint was resolved is published as 0.
+func (p *Publisher) publishCounts(resolving bool, belt string) {
+	next := map[string]int{"total": 0}
+	for b, n := range p.counts() {
+		next[beltTopic(b)] += n
+		next["total"] += n
+	}
+	if resolving {
+		if t := beltTopic(belt); next[t] > 0 {
+			next[t]--
+			next["total"]--
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	changed := make(map[string]int)
+	for t, n := range next {
+		if old, ok := p.last[t]; !ok || old != n {
+			changed[t] = n
+		}
+	}
+	for t := range p.last {
+		if _, ok := next[t]; !ok {
+			changed[t] = 0
+			next[t] = 0
+		}
+	}
+	p.last = next
+	for t, n := range changed {
+		p.enqueueLocked(message{topic: p.topic("open/" + t), retained: true, payload: []byte(strconv.Itoa(n))})
+	}
+}
+
+func (p *Publisher) enqueue(m message) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.enqueueLocked(m)
+}
+
+// enqueueLocked queues m, or drops it when the queue is full. A dropped
+// count would leave a stale retained value, so after a drop every count
+// is sent again with the next update.
+func (p *Publisher) enqueueLocked(m message) {
+	if p.closed {
+		return
+	}
+	select {
+	case p.queue <- m:
+	default:
+		metrics.EventsDroppedTotal.Inc()
+		log.Printf("⚠️  MQTT is falling behind; dropped a message for %s", m.topic)
+		p.last = nil
+	}
+}
+
+func (p *Publisher) topic(suffix string) string {
+	return strings.TrimSuffix(p.cfg.TopicPrefix, "/") + "/" + suffix
+}
+
+// beltTopic is the topic level for belt b.
+func beltTopic(b string) string {
+	b = strings.ToLower(strings.TrimSpace(b))
+	if b == "" {
+		return "unknown"
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, b)
+}
@@ -0,0 +1,117 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"cmon/internal/eventbus"
+	"cmon/internal/notify"
+)
+
+// recorder collects what a Publisher sends, by topic.
+type recorder struct {
+	mu   sync.Mutex
+	sent map[string][]string
+}
+
+func (r *recorder) send(m message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sent == nil {
+		r.sent = make(map[string][]string)
+	}
+	r.sent[m.topic] = append(r.sent[m.topic], string(m.payload))
+	return nil
+}
+
+// last returns the last payload sent to topic.
+func (r *recorder) last(topic string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ps := r.sent[topic]
+	if len(ps) == 0 {
+		return ""
+	}
+	return ps[len(ps)-1]
+}
+
+func TestPublishesEventsAndCounts(t *testing.T) {
+	open := map[string]int{}
+	rec := &recorder{}
+	p := newPublisher(Config{TopicPrefix: "dgvcl/", Redact: true}, func() map[string]int { return open }, rec.send)
+	go p.run()
+
+	open["Tokarva"] = 1
+	open["Mota Varachha"] = 1
+	p.Handle(eventbus.ComplaintsNew{Complaints: []notify.Complaint{
+		{Number: "C-1", Belt: "Tokarva", MobileNo: "9876543210"},
+		{Number: "C-2", Belt: "Mota Varachha"},
+	}})
+	// Resolutions are published while the complaint is still stored.
+	p.Handle(eventbus.ComplaintResolved{Status: notify.Status{ComplaintID: "C-1", Belt: "Tokarva"}})
+	delete(open, "Tokarva")
+	p.Close()
+
+	if got := rec.sent["dgvcl/complaints/new"]; len(got) != 2 {
+		t.Fatalf("complaints/new got %d messages, want 2", len(got))
+	}
+	var ev struct {
+		Event string           `json:"event"`
+		Data  notify.Complaint `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(rec.sent["dgvcl/complaints/new"][0]), &ev); err != nil {
+		t.Fatal(err)
+	}
+	if ev.Event != "complaint.new" || ev.Data.Number != "C-1" || ev.Data.MobileNo == "9876543210" {
+		t.Errorf("complaints/new payload = %+v, want a redacted complaint.new for C-1", ev)
+	}
+	if got := rec.last("dgvcl/complaints/resolved"); got == "" {
+		t.Error("nothing published to complaints/resolved")
+	}
+
+	for topic, want := range map[string]string{
+		"dgvcl/open/tokarva":       "0",
+		"dgvcl/open/mota_varachha": "1",
+		"dgvcl/open/total":         "1",
+		"dgvcl/status":             "offline",
+	} {
+		if got := rec.last(topic); got != want {
+			t.Errorf("%s = %q, want %q", topic, got, want)
+		}
+	}
+	// The unchanged count is not sent again.
+	if n := len(rec.sent["dgvcl/open/mota_varachha"]); n != 1 {
+		t.Errorf("open/mota_varachha sent %d times, want 1", n)
+	}
+}
+
+func TestResyncSendsEveryCount(t *testing.T) {
+	rec := &recorder{}
+	p := newPublisher(Config{TopicPrefix: "cmon"}, func() map[string]int { return map[string]int{"": 2} }, rec.send)
+	go p.run()
+
+	p.Handle(eventbus.ComplaintNew{Complaint: notify.Complaint{Number: "C-1"}})
+	p.resync()
+	p.Close()
+
+	if n := len(rec.sent["cmon/open/unknown"]); n != 2 {
+		t.Errorf("open/unknown sent %d times, want 2 (update and resync)", n)
+	}
+	if got := rec.last("cmon/open/total"); got != "2" {
+		t.Errorf("open/total = %q, want 2", got)
+	}
+}
+
+func TestBeltTopic(t *testing.T) {
+	for in, want := range map[string]string{
+		"Tokarva":        "tokarva",
+		" Mota/Varachha": "mota_varachha",
+		"a+b#c":          "a_b_c",
+		"":               "unknown",
+	} {
+		if got := beltTopic(in); got != want {
+			t.Errorf("beltTopic(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+// Package msgtmpl renders complaint notifications from Go templates so the
+// layout (fields, order, language, emoji) can be changed per deployment
+// without code changes.
+//
+// A MESSAGE_TEMPLATE file is either a single template body used for every
+// channel, or a set of named blocks:
+//
+//	{{define "telegram"}}…{{end}}
+//	{{define "whatsapp"}}…{{end}}
+//
+// A channel without a block keeps its built-in layout. Telegram templates
+// are executed with html/template, so every field is escaped for
+// ParseMode=HTML and <b>/<i>/<a> tags in the template itself are kept;
+// WhatsApp templates produce plain text.
+//
+// Translated fields are laid out bilingually: each field selected by
+// BILINGUAL_FIELDS gets its Gujarati line (NameGu, DescriptionGu,
+// AddressGu) right under the English one, and the rest are gathered in a
+// Translation block at the end.
+package msgtmpl
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"cmon/internal/belt"
+	"cmon/internal/notify"
+)
+
+// Channel template names in a MESSAGE_TEMPLATE file.
+const (
+	Telegram = "telegram"
+	WhatsApp = "whatsapp"
+)
+
+// Fields that can be shown bilingually (BILINGUAL_FIELDS).
+const (
+	FieldName        = "name"
+	FieldDescription = "description"
+	FieldAddress     = "address"
+)
+
+// AllBilingual lists every bilingual field; it is the default.
+var AllBilingual = []string{FieldName, FieldDescription, FieldAddress}
+
+// Complaint is the data a complaint template sees. All fields are plain
+// text; empty ones are best guarded with {{with}}.
+type Complaint struct {
+	Number      string
+	Belt        string // display name, e.g. "Tokarva"
+	BeltEmoji   string
+	Name        string
+	Mobile      string
+	ConsumerNo  string
+	Date        string
+	Description string
+	Location    string
+	Area        string
+	Village     string
+	Feeder      string // empty when the locality is not in FEEDER_MAP_FILE
+	MapsURL     string
+	RepeatNote  string
+	DataIssues  string
+
+	// Gujarati lines for the bilingual fields, empty when not translated
+	// or not selected.
+	NameGu        string
+	DescriptionGu string
+	AddressGu     string
+	// Translation holds the translated fields that are not shown
+	// bilingually, as one block.
+	Translation string
+	// TranslationNote explains a skipped translation (e.g. rate limited)
+	// so English-only messages don't look broken.
+	TranslationNote string
+
+	// Reopened is true when the complaint was resolved before and is back
+	// on the dashboard; ResolvedAt is when ("02 Jan 15:04", local time).
+	Reopened   bool
+	ResolvedAt string
+}
+
+// view builds the template data for c, splitting its translation between
+// the bilingual lines and the trailing block.
+func (s *Set) view(c notify.Complaint) Complaint {
+	v := Complaint{
+		Number:      c.Number,
+		Belt:        belt.DisplayName(c.Belt),
+		BeltEmoji:   belt.StyleFor(c.Belt).Emoji,
+		Name:        c.ComplainantName,
+		Mobile:      c.MobileNo,
+		ConsumerNo:  c.ConsumerNo,
+		Date:        c.ComplainDate,
+		Description: c.Description,
+		Location:    c.ExactLocation,
+		Area:        c.Area,
+		Village:     c.Village,
+		Feeder:      c.Feeder,
+		MapsURL:     c.MapsURL,
+		RepeatNote:  c.RepeatNote,
+		DataIssues:  c.DataIssues,
+
+		TranslationNote: c.TranslationNote,
+	}
+	if r := c.Reopened; r != nil {
+		v.Reopened = true
+		if !r.ResolvedAt.IsZero() {
+			v.ResolvedAt = r.ResolvedAt.Format(resolvedAtLayout)
+		}
+	}
+	var block []string
+	for _, f := range []struct {
+		name, icon, text string
+		line             *string
+	}{
+		{FieldName, "👤", c.NameGu, &v.NameGu},
+		{FieldDescription, "💬", c.DescriptionGu, &v.DescriptionGu},
+		{FieldAddress, "📍", c.AddressGu, &v.AddressGu},
+	} {
+		switch {
+		case f.text == "":
+		case s.bilingual[f.name]:
+			*f.line = f.text
+		default:
+			block = append(block, f.icon+" "+f.text)
+		}
+	}
+	v.Translation = strings.Join(block, "\n")
+	return v
+}
+
+// resolvedAtLayout formats a re-opened complaint's earlier resolution.
+const resolvedAtLayout = "02 Jan 15:04"
+
+// defaultTelegram is the built-in Telegram layout. The "👤 " line is read
+// back from the message text when the complaint is resolved, so custom
+// templates should keep it.
+const defaultTelegram = `{{if .Reopened}}♻️ <b>RE-OPENED</b>{{with .ResolvedAt}} — resolved {{.}}{{end}}
+{{end}}{{with .DataIssues}}⚠️ <b>Intake issues:</b> {{.}}
+{{end}}📋 Complaint : {{.Number}}
+
+{{.BeltEmoji}} Belt: {{.Belt}}
+{{- with .Feeder}}
+🔌 Feeder: {{.}}
+{{- end}}
+👤 {{.Name}}
+{{- with .NameGu}}
+     <i>{{.}}</i>
+{{- end}}
+📞 {{.Mobile}}
+🆔 Consumer: {{.ConsumerNo}}
+📅 {{.Date}}
+
+💬 <b>Details:</b>
+{{.Description}}
+{{- with .DescriptionGu}}
+<i>{{.}}</i>
+{{- end}}
+📍 {{.Location}}, {{.Area}}
+{{- with .AddressGu}}
+     <i>{{.}}</i>
+{{- end}}
+{{- with .MapsURL}}
+🗺️ <a href="{{.}}">Open in Google Maps</a>
+{{- end}}
+{{- with .RepeatNote}}
+
+<b>{{.}}</b>
+{{- end}}
+{{- with .Translation}}
+
+──────────
+{{.}}
+{{- end}}
+{{- with .TranslationNote}}
+
+🌐 {{.}}
+{{- end}}`
+
+// defaultWhatsApp is the built-in WhatsApp layout.
+const defaultWhatsApp = `{{if .Reopened}}♻️ RE-OPENED{{with .ResolvedAt}} — resolved {{.}}{{end}}
+{{end}}{{with .DataIssues}}⚠️ Intake issues: {{.}}
+{{end}}📋 Complaint: {{.Number}}
+
+{{.BeltEmoji}} Belt: {{.Belt}}
+{{- with .Feeder}}
+🔌 Feeder: {{.}}
+{{- end}}
+👤 {{.Name}}
+{{- with .NameGu}}
+     _{{.}}_
+{{- end}}
+📞 {{.Mobile}}
+🆔 Consumer: {{.ConsumerNo}}
+📅 {{.Date}}
+
+💬 Details:
+{{.Description}}
+{{- with .DescriptionGu}}
+_{{.}}_
+{{- end}}
+📍 {{.Location}}, {{.Area}}
+{{- with .AddressGu}}
+     _{{.}}_
+{{- end}}
+{{- with .MapsURL}}
+🗺️ {{.}}
+{{- end}}
+{{- with .RepeatNote}}
+
+{{.}}
+{{- end}}
+{{- with .Translation}}
+
+──────────
+{{.}}
+{{- end}}
+{{- with .TranslationNote}}
+
+🌐 {{.}}
+{{- end}}`
+
+// Set holds the complaint templates for each channel and the fields shown
+// bilingually. A nil *Set renders the built-in layouts with every field
+// bilingual.
+type Set struct {
+	telegram  *htmltemplate.Template
+	whatsapp  *texttemplate.Template
+	bilingual map[string]bool
+}
+
+var defaults = &Set{
+	telegram:  htmltemplate.Must(htmltemplate.New(Telegram).Parse(defaultTelegram)),
+	whatsapp:  texttemplate.Must(texttemplate.New(WhatsApp).Parse(defaultWhatsApp)),
+	bilingual: fieldSet(AllBilingual),
+}
+
+func fieldSet(fields []string) map[string]bool {
+	m := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		m[f] = true
+	}
+	return m
+}
+
+// Default returns the built-in layouts.
+func Default() *Set { return defaults }
+
+// Load reads a MESSAGE_TEMPLATE file (empty path = built-in layouts) and
+// shows the given fields bilingually. The templates are test-rendered
+// against a sample complaint so a typo in a field name fails at startup
+// rather than on the first complaint.
+func Load(path string, bilingual []string) (*Set, error) {
+	set := &Set{telegram: defaults.telegram, whatsapp: defaults.whatsapp}
+	if path != "" {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read message template: %w", err)
+		}
+		if set, err = Parse(string(src)); err != nil {
+			return nil, fmt.Errorf("message template %s: %w", path, err)
+		}
+	}
+	set.bilingual = fieldSet(bilingual)
+	return set, nil
+}
+
+// Parse builds a Set from template source, with every field bilingual;
+// see the package doc for the file layout.
+func Parse(src string) (*Set, error) {
+	text, err := texttemplate.New("file").Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.New("file").Parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &Set{telegram: defaults.telegram, whatsapp: defaults.whatsapp, bilingual: defaults.bilingual}
+	named := false
+	if t := html.Lookup(Telegram); t != nil {
+		set.telegram, named = t, true
+	}
+	if t := text.Lookup(WhatsApp); t != nil {
+		set.whatsapp, named = t, true
+	}
+	if !named {
+		if strings.TrimSpace(src) == "" {
+			return nil, fmt.Errorf("template is empty")
+		}
+		set.telegram, set.whatsapp = html, text
+	}
+
+	sample := notify.Complaint{
+		Number: "12345", Belt: "Sample", ComplainantName: "Name", MobileNo: "9999999999",
+		ConsumerNo: "1", ComplainDate: "01/01/2026", Description: "Description", ExactLocation: "Location",
+		Area: "Area", Village: "Village", MapsURL: "https://maps.google.com/", RepeatNote: "Note",
+		DataIssues: "Issue", NameGu: "નામ", DescriptionGu: "વિગત", AddressGu: "સરનામું",
+		TranslationNote: "Note", Reopened: &notify.Reopen{ResolvedAt: time.Now()},
+	}
+	for _, ch := range []string{Telegram, WhatsApp} {
+		if _, err := set.Render(ch, sample); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// Render formats c for channel (Telegram or WhatsApp). Leading and
+// trailing whitespace is trimmed so template files may end in a newline.
+func (s *Set) Render(channel string, c notify.Complaint) (string, error) {
+	if s == nil {
+		s = defaults
+	}
+	v := s.view(c)
+	var b bytes.Buffer
+	var err error
+	switch channel {
+	case Telegram:
+		err = s.telegram.Execute(&b, v)
+	case WhatsApp:
+		err = s.whatsapp.Execute(&b, v)
+	default:
+		return "", fmt.Errorf("unknown message channel %q", channel)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to render %s message: %w", channel, err)
+	}
+	out := strings.TrimSpace(b.String())
+	if out == "" {
+		return "", fmt.Errorf("%s template rendered an empty message", channel)
+	}
+	return out, nil
+}
@@ -0,0 +1,173 @@
+package msgtmpl
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"cmon/internal/notify"
+)
+
+var sample = notify.Complaint{
+	Number:          "123",
+	Belt:            "Buhari",
+	ComplainantName: "R&D <Works>",
+	MobileNo:        "9876543210",
+	ConsumerNo:      "555",
+	ComplainDate:    "01/03/2026",
+	Description:     "Wire down",
+	ExactLocation:   "Main Rd",
+	Area:            "Tokarva",
+}
+
+func TestDefaultLayouts(t *testing.T) {
+	got, err := Default().Render(WhatsApp, sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "📋 Complaint: 123\n\n🟢 Belt: Buhari\n👤 R&D <Works>\n📞 9876543210\n🆔 Consumer: 555\n📅 01/03/2026\n\n💬 Details:\nWire down\n📍 Main Rd, Tokarva"
+	if got != want {
+		t.Errorf("whatsapp =\n%q\nwant\n%q", got, want)
+	}
+
+	c := sample
+	c.MapsURL = "https://maps.google.com/?q=1&z=2"
+	c.Feeder = "Bajipura AG"
+	got, err = (*Set)(nil).Render(Telegram, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"📋 Complaint : 123\n",
+		"🟢 Belt: Buhari\n🔌 Feeder: Bajipura AG\n👤",
+		"👤 R&amp;D &lt;Works&gt;\n",
+		"📍 Main Rd, Tokarva\n🗺️ <a href=\"https://maps.google.com/?q=1&amp;z=2\">Open in Google Maps</a>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("telegram missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestBilingualLayout(t *testing.T) {
+	c := sample
+	c.NameGu = "આર એન્ડ ડી"
+	c.DescriptionGu = "વાયર પડી ગયો"
+	c.AddressGu = "મેઈન રોડ, ટોકરવા"
+
+	set, err := Load("", []string{FieldName, FieldDescription})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := set.Render(Telegram, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"👤 R&amp;D &lt;Works&gt;\n     <i>આર એન્ડ ડી</i>\n📞",
+		"Wire down\n<i>વાયર પડી ગયો</i>\n📍 Main Rd, Tokarva\n",
+		// address is not bilingual: it goes in the trailing block
+		"──────────\n📍 મેઈન રોડ, ટોકરવા",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("telegram missing %q:\n%s", want, got)
+		}
+	}
+
+	got, _ = set.Render(WhatsApp, c)
+	if !strings.Contains(got, "👤 R&D <Works>\n     _આર એન્ડ ડી_\n") {
+		t.Errorf("whatsapp bilingual name missing:\n%s", got)
+	}
+
+	// No bilingual fields: everything translated goes in the block.
+	set, _ = Load("", nil)
+	got, _ = set.Render(WhatsApp, c)
+	if !strings.HasSuffix(got, "──────────\n👤 આર એન્ડ ડી\n💬 વાયર પડી ગયો\n📍 મેઈન રોડ, ટોકરવા") {
+		t.Errorf("whatsapp block layout:\n%s", got)
+	}
+}
+
+func TestSkippedTranslationNote(t *testing.T) {
+	c := sample
+	c.TranslationNote = "Gujarati translation skipped (rate limited)"
+	got, err := Default().Render(Telegram, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(got, "📍 Main Rd, Tokarva\n\n🌐 Gujarati translation skipped (rate limited)") || strings.Contains(got, "──") {
+		t.Errorf("telegram =\n%s", got)
+	}
+}
+
+func TestReopenedHeader(t *testing.T) {
+	c := sample
+	c.Reopened = &notify.Reopen{ResolvedAt: time.Date(2026, 3, 9, 14, 5, 0, 0, time.Local)}
+	got, err := Default().Render(Telegram, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got, "♻️ <b>RE-OPENED</b> — resolved 09 Mar 14:05\n📋 Complaint : 123\n") {
+		t.Errorf("telegram =\n%s", got)
+	}
+
+	c.Reopened = &notify.Reopen{}
+	got, _ = Default().Render(WhatsApp, c)
+	if !strings.HasPrefix(got, "♻️ RE-OPENED\n📋 Complaint: 123\n") {
+		t.Errorf("whatsapp =\n%s", got)
+	}
+}
+
+func TestParseSingleBodyAppliesToEveryChannel(t *testing.T) {
+	set, err := Parse("શિકાયત {{.Number}} — {{.Name}}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := set.Render(Telegram, sample); got != "શિકાયત 123 — R&amp;D &lt;Works&gt;" {
+		t.Errorf("telegram = %q", got)
+	}
+	if got, _ := set.Render(WhatsApp, sample); got != "શિકાયત 123 — R&D <Works>" {
+		t.Errorf("whatsapp = %q", got)
+	}
+}
+
+func TestParseNamedBlocks(t *testing.T) {
+	set, err := Parse(`{{define "telegram"}}<b>{{.Number}}</b> {{.Description}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := set.Render(Telegram, sample); got != "<b>123</b> Wire down" {
+		t.Errorf("telegram = %q", got)
+	}
+	// No whatsapp block: the built-in layout stays.
+	if got, _ := set.Render(WhatsApp, sample); !strings.HasPrefix(got, "📋 Complaint: 123") {
+		t.Errorf("whatsapp = %q, want built-in layout", got)
+	}
+}
+
+func TestParseRejectsBrokenTemplates(t *testing.T) {
+	for name, src := range map[string]string{
+		"syntax":        "{{.Number",
+		"unknown field": "{{.Consumer}}",
+		"empty":         "  \n",
+		"empty output":  `{{define "whatsapp"}}{{if false}}x{{end}}{{end}}`,
+	} {
+		if _, err := Parse(src); err == nil {
+			t.Errorf("%s: Parse(%q) succeeded", name, src)
+		}
+	}
+}
+
+func TestLoadWithoutPathUsesDefaults(t *testing.T) {
+	set, err := Load("", AllBilingual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := set.Render(Telegram, sample)
+	b, _ := Default().Render(Telegram, sample)
+	if a != b {
+		t.Errorf("Load(\"\") renders %q, want the built-in %q", a, b)
+	}
+	if _, err := Load("does-not-exist.tmpl", nil); err == nil {
+		t.Error("missing file should fail")
+	}
+}
@@ -0,0 +1,402 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"cmon/internal/belt"
+)
+
+// discordResolvePrefix starts the custom_id of the resolve button; the
+// complaint number follows.
+const discordResolvePrefix = "resolve:"
+
+// Discord interaction and response types used here.
+const (
+	discordInteractionPing      = 1
+	discordInteractionComponent = 3
+
+	discordResponsePong           = 1
+	discordResponseDeferredUpdate = 6
+)
+
+// DiscordConfig configures NewDiscord.
+type DiscordConfig struct {
+	BotToken  string
+	ChannelID string
+	// PublicKey is the application's hex Ed25519 key, used to verify
+	// interaction requests. Empty leaves the resolve button off.
+	PublicKey string
+	// APIBase overrides https://discord.com/api/v10 for tests.
+	APIBase string
+	// Location is the zone resolved times are shown in; nil uses time.Local.
+	Location *time.Location
+}
+
+// Discord posts complaints to a Discord channel as embeds with a resolve
+// button, and edits them in place when the complaint is resolved.
+type Discord struct {
+	cfg       DiscordConfig
+	publicKey ed25519.PublicKey
+	refs      MessageRefs
+	client    *http.Client
+}
+
+// NewDiscord returns a Discord Notifier, or nil when the bot token or
+// channel is missing. An unparseable PublicKey disables the button.
+func NewDiscord(cfg DiscordConfig, refs MessageRefs) *Discord {
+	if cfg.BotToken == "" || cfg.ChannelID == "" {
+		return nil
+	}
+	if cfg.APIBase == "" {
+		cfg.APIBase = "https://discord.com/api/v10"
+	}
+	d := &Discord{cfg: cfg, refs: refs, client: &http.Client{Timeout: 30 * time.Second}}
+	if key, err := hex.DecodeString(cfg.PublicKey); err == nil && len(key) == ed25519.PublicKeySize {
+		d.publicKey = key
+	} else if cfg.PublicKey != "" {
+		log.Printf("⚠️  DISCORD_PUBLIC_KEY is not a valid Ed25519 key; resolve button disabled")
+	}
+	log.Printf("✓ Discord notifications enabled for channel %s", cfg.ChannelID)
+	return d
+}
+
+// Name implements Notifier.
+func (d *Discord) Name() string { return "discord" }
+
+// SendComplaint implements Notifier. The message ID is stored so EditStatus
+// can replace the message later.
+func (d *Discord) SendComplaint(c Complaint) error {
+	msg := discordMessage{Embeds: []discordEmbed{discordComplaintEmbed(c)}}
+	if d.publicKey != nil {
+		msg.Components = []discordComponent{{
+			Type: 1, // action row
+			Components: []discordComponent{{
+				Type:     2, // button
+				Style:    3, // success (green)
+				Label:    "Mark as Resolved",
+				Emoji:    &discordEmoji{Name: "✅"},
+				CustomID: discordResolvePrefix + c.Number,
+			}},
+		}}
+	}
+
+	var resp struct {
+		ID string `json:"id"`
+	}
+	if err := d.call(http.MethodPost, "/channels/"+d.cfg.ChannelID+"/messages", msg, &resp); err != nil {
+		return err
+	}
+	if err := d.refs.SetMessageRef(d.Name(), c.Number, resp.ID); err != nil {
+		return fmt.Errorf("failed to persist Discord message ID: %w", err)
+	}
+	return nil
+}
+
+// SendAlert implements Notifier.
+func (d *Discord) SendAlert(a Alert) error {
+	embed := discordEmbed{Title: "⚠️ " + a.Title, Description: a.Message, Color: 0xF0B232}
+	switch {
+	case a.Critical():
+		embed.Title = "🚨 CRITICAL ALERT - CMON: " + a.Title
+		embed.Color = 0xDA373C
+	case a.Recovered():
+		embed.Title = "✅ RECOVERED - CMON: " + a.Title
+		embed.Color = 0x23A55A
+	}
+	if a.RetryCount > 0 {
+		embed.Fields = append(embed.Fields, discordField{Name: "Retry attempts", Value: fmt.Sprint(a.RetryCount), Inline: true})
+	}
+	if !a.Time.IsZero() {
+		embed.Timestamp = a.Time.UTC().Format(time.RFC3339)
+	}
+	return d.call(http.MethodPost, "/channels/"+d.cfg.ChannelID+"/messages", discordMessage{Embeds: []discordEmbed{embed}}, nil)
+}
+
+// EditStatus implements Notifier: the embed is replaced with a resolved
+// card and the button is removed.
+func (d *Discord) EditStatus(st Status) error {
+	id := d.refs.GetMessageRef(d.Name(), st.ComplaintID)
+	if id == "" {
+		return nil // not posted to Discord
+	}
+	return d.updateResolved(d.cfg.ChannelID, id, st)
+}
+
+func (d *Discord) updateResolved(channelID, messageID string, st Status) error {
+	if d.cfg.Location != nil {
+		st.Time = st.Time.In(d.cfg.Location)
+	}
+	return d.call(http.MethodPatch, "/channels/"+channelID+"/messages/"+messageID, discordMessage{
+		Embeds:     []discordEmbed{discordResolvedEmbed(st)},
+		Components: []discordComponent{},
+	}, nil)
+}
+
+// call sends a REST request and decodes the JSON response into out (may be nil).
+func (d *Discord) call(method, path string, payload, out interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode Discord request: %w", err)
+	}
+	req, err := http.NewRequest(method, d.cfg.APIBase+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if strings.HasPrefix(path, "/channels/") {
+		req.Header.Set("Authorization", "Bot "+d.cfg.BotToken)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Discord %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("Discord %s %s: read response: %w", method, path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Discord %s %s: HTTP %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	if out != nil {
+		return json.Unmarshal(raw, out)
+	}
+	return nil
+}
+
+// InteractionHandler serves the application's Interactions Endpoint URL.
+// Discord requires an answer within 3s, so a resolve click is acknowledged
+// with a deferred update and resolved in the background; failures are sent
+// to the clicking user only, as an ephemeral follow-up.
+func (d *Discord) InteractionHandler(resolve ResolveFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if !verifyDiscordSignature(d.publicKey, r.Header, body) {
+			http.Error(w, "invalid request signature", http.StatusUnauthorized)
+			return
+		}
+		var in discordInteraction
+		if err := json.Unmarshal(body, &in); err != nil {
+			http.Error(w, "bad payload", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch in.Type {
+		case discordInteractionPing:
+			json.NewEncoder(w).Encode(map[string]int{"type": discordResponsePong})
+		case discordInteractionComponent:
+			json.NewEncoder(w).Encode(map[string]int{"type": discordResponseDeferredUpdate})
+			if id := strings.TrimPrefix(in.Data.CustomID, discordResolvePrefix); id != in.Data.CustomID && id != "" {
+				go d.handleResolve(resolve, in, id)
+			}
+		default:
+			http.Error(w, "unsupported interaction", http.StatusBadRequest)
+		}
+	})
+}
+
+func (d *Discord) handleResolve(resolve ResolveFunc, in discordInteraction, complaintID string) {
+	by := in.userName()
+	log.Printf("📞 Discord: %s pressed resolve on complaint %s", by, complaintID)
+
+	err := resolve(complaintID, by, "Discord")
+	switch {
+	case err == nil:
+		return
+	case errors.Is(err, ErrAlreadyResolved):
+		// Resolved elsewhere; the message may still show the button.
+		if in.Message.ID != "" {
+			if err := d.updateResolved(in.ChannelID, in.Message.ID, Status{ComplaintID: complaintID, Time: time.Now()}); err != nil {
+				log.Printf("⚠️  Failed to update Discord message for %s: %v", complaintID, err)
+			}
+		}
+		d.followUp(in, fmt.Sprintf("ℹ️ Complaint %s was already resolved.", complaintID))
+	default:
+		log.Printf("⚠️  Discord resolve failed for %s: %v", complaintID, err)
+		d.followUp(in, fmt.Sprintf("❌ Failed to resolve complaint %s: %v", complaintID, err))
+	}
+}
+
+// followUp sends an ephemeral message to the user behind an interaction.
+func (d *Discord) followUp(in discordInteraction, text string) {
+	path := fmt.Sprintf("/webhooks/%s/%s", in.ApplicationID, in.Token)
+	if err := d.call(http.MethodPost, path, map[string]interface{}{"content": text, "flags": 64}, nil); err != nil {
+		log.Printf("⚠️  Failed to reply to Discord interaction: %v", err)
+	}
+}
+
+// verifyDiscordSignature checks X-Signature-Ed25519 over the timestamp
+// header followed by the raw body.
+func verifyDiscordSignature(key ed25519.PublicKey, h http.Header, body []byte) bool {
+	if key == nil {
+		return false
+	}
+	sig, err := hex.DecodeString(h.Get("X-Signature-Ed25519"))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+	msg := append([]byte(h.Get("X-Signature-Timestamp")), body...)
+	return ed25519.Verify(key, msg, sig)
+}
+
+type discordInteraction struct {
+	Type          int    `json:"type"`
+	ApplicationID string `json:"application_id"`
+	Token         string `json:"token"`
+	ChannelID     string `json:"channel_id"`
+	Data          struct {
+		CustomID string `json:"custom_id"`
+	} `json:"data"`
+	Message struct {
+		ID string `json:"id"`
+	} `json:"message"`
+	Member *struct {
+		User discordUser `json:"user"`
+	} `json:"member"`
+	User *discordUser `json:"user"`
+}
+
+type discordUser struct {
+	Username   string `json:"username"`
+	GlobalName string `json:"global_name"`
+}
+
+// userName returns who clicked: member.user in a guild, user in a DM.
+func (in discordInteraction) userName() string {
+	u := in.User
+	if in.Member != nil {
+		u = &in.Member.User
+	}
+	if u == nil {
+		return "unknown"
+	}
+	if u.GlobalName != "" {
+		return u.GlobalName
+	}
+	return u.Username
+}
+
+type discordMessage struct {
+	Content    string             `json:"content,omitempty"`
+	Embeds     []discordEmbed     `json:"embeds"`
+	Components []discordComponent `json:"components"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description,omitempty"`
+	URL         string         `json:"url,omitempty"`
+	Color       int            `json:"color,omitempty"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Footer      *discordFooter `json:"footer,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+type discordComponent struct {
+	Type       int                `json:"type"`
+	Style      int                `json:"style,omitempty"`
+	Label      string             `json:"label,omitempty"`
+	Emoji      *discordEmoji      `json:"emoji,omitempty"`
+	CustomID   string             `json:"custom_id,omitempty"`
+	Components []discordComponent `json:"components,omitempty"`
+}
+
+type discordEmoji struct {
+	Name string `json:"name"`
+}
+
+// discordComplaintEmbed lays a complaint out like the Telegram message,
+// coloured by belt. The title links to Google Maps when geocoded.
+func discordComplaintEmbed(c Complaint) discordEmbed {
+	e := discordEmbed{
+		Title:       c.Headline(),
+		URL:         c.MapsURL,
+		Description: "💬 " + c.Description,
+		Color:       discordColor(belt.StyleFor(c.Belt).Fill),
+		Fields: []discordField{
+			{Name: "Belt", Value: belt.StyleFor(c.Belt).Emoji + " " + belt.DisplayName(c.Belt), Inline: true},
+			{Name: "Complainant", Value: orDash(c.ComplainantName), Inline: true},
+			{Name: "Mobile", Value: orDash(c.MobileNo), Inline: true},
+			{Name: "Consumer", Value: orDash(c.ConsumerNo), Inline: true},
+			{Name: "Date", Value: orDash(c.ComplainDate), Inline: true},
+			{Name: "Location", Value: orDash(strings.Trim(c.ExactLocation+", "+c.Area, ", "))},
+		},
+	}
+	if c.DataIssues != "" {
+		e.Description = "⚠️ **Intake issues:** " + c.DataIssues + "\n\n" + e.Description
+	}
+	if c.RepeatNote != "" {
+		e.Fields = append(e.Fields, discordField{Name: "History", Value: c.RepeatNote})
+	}
+	if c.Translation != "" {
+		e.Footer = &discordFooter{Text: c.Translation}
+	}
+	return e
+}
+
+func discordResolvedEmbed(st Status) discordEmbed {
+	title := "✅ RESOLVED"
+	if st.Local {
+		title = "✅ RESOLVED (LOCAL)"
+	}
+	desc := "Complaint #" + st.ComplaintID
+	if st.ConsumerName != "" {
+		desc += "\n👤 " + st.ConsumerName
+	}
+	if r := st.Resolution(); r != "" {
+		desc += "\n📝 " + r
+	}
+	return discordEmbed{
+		Title:       title,
+		Description: desc + "\n🕐 " + st.Time.Format("02 Jan 2006, 03:04 PM"),
+		Color:       0x23A55A,
+	}
+}
+
+// discordColor packs c into Discord's 0xRRGGBB integer.
+func discordColor(c color.Color) int {
+	if c == nil {
+		return 0
+	}
+	r, g, b, _ := c.RGBA()
+	return int(r>>8)<<16 | int(g>>8)<<8 | int(b>>8)
+}
+
+// orDash keeps embed fields from being empty, which Discord rejects.
+func orDash(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "—"
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signedDiscordRequest(priv ed25519.PrivateKey, body string) *http.Request {
+	ts := "1700000000"
+	req := httptest.NewRequest(http.MethodPost, "/discord/interactions", strings.NewReader(body))
+	req.Header.Set("X-Signature-Timestamp", ts)
+	req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(priv, []byte(ts+body))))
+	return req
+}
+
+func TestDiscordSendThenEditStatus(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if got := r.Header.Get("Authorization"); got != "Bot tok" {
+			t.Errorf("Authorization: got %q", got)
+		}
+		fmt.Fprint(w, `{"id":"555"}`)
+	}))
+	defer srv.Close()
+
+	refs := &memRefs{}
+	d := NewDiscord(DiscordConfig{BotToken: "tok", ChannelID: "42", APIBase: srv.URL}, refs)
+	if err := d.SendComplaint(Complaint{Number: "12345", Belt: "Buhari"}); err != nil {
+		t.Fatalf("SendComplaint: %v", err)
+	}
+	if got := refs.GetMessageRef("discord", "12345"); got != "555" {
+		t.Errorf("stored message ID: got %q", got)
+	}
+	if err := d.EditStatus(Status{ComplaintID: "12345", Time: time.Now()}); err != nil {
+		t.Fatalf("EditStatus: %v", err)
+	}
+	if want := []string{"POST /channels/42/messages", "PATCH /channels/42/messages/555"}; fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("calls: got %v, want %v", calls, want)
+	}
+}
+
+func TestDiscordInteractionHandler(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewDiscord(DiscordConfig{BotToken: "tok", ChannelID: "42", PublicKey: hex.EncodeToString(pub)}, &memRefs{})
+	got := make(chan string, 1)
+	h := d.InteractionHandler(func(complaintID, by, via string) error {
+		got <- complaintID + "/" + by + "/" + via
+		return nil
+	})
+
+	t.Run("ping", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, signedDiscordRequest(priv, `{"type":1}`))
+		var resp struct{ Type int }
+		json.NewDecoder(rec.Body).Decode(&resp)
+		if rec.Code != http.StatusOK || resp.Type != discordResponsePong {
+			t.Errorf("PING: got %d %+v, want PONG", rec.Code, resp)
+		}
+	})
+
+	t.Run("resolve button", func(t *testing.T) {
+		body := `{"type":3,"data":{"custom_id":"resolve:12345"},"member":{"user":{"username":"ravi"}}}`
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, signedDiscordRequest(priv, body))
+		var resp struct{ Type int }
+		json.NewDecoder(rec.Body).Decode(&resp)
+		if resp.Type != discordResponseDeferredUpdate {
+			t.Errorf("response type: got %d, want deferred update", resp.Type)
+		}
+		select {
+		case v := <-got:
+			if v != "12345/ravi/Discord" {
+				t.Errorf("resolve args: got %q", v)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("resolve was not called")
+		}
+	})
+
+	t.Run("bad signature", func(t *testing.T) {
+		req := signedDiscordRequest(priv, `{"type":1}`)
+		req.Header.Set("X-Signature-Timestamp", "1700000001")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("status: got %d, want 401", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,227 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"html/template"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"cmon/internal/belt"
+)
+
+// EmailConfig configures NewEmail.
+type EmailConfig struct {
+	Host     string
+	Port     int // 465 = implicit TLS; anything else uses STARTTLS when offered
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// Email sends notifications over SMTP as HTML mail.
+type Email struct {
+	cfg EmailConfig
+
+	// send delivers one message; swapped by tests. Defaults to sendMail.
+	send func(cfg EmailConfig, msg []byte) error
+}
+
+// NewEmail returns an email Notifier, or nil when cfg.Host or cfg.To is
+// empty (the channel is then simply not added).
+func NewEmail(cfg EmailConfig) *Email {
+	if cfg.Host == "" || len(cfg.To) == 0 {
+		return nil
+	}
+	log.Printf("✓ Email notifications enabled for %d recipient(s)", len(cfg.To))
+	return &Email{cfg: cfg, send: sendMail}
+}
+
+// Name implements Notifier.
+func (e *Email) Name() string { return "email" }
+
+// SendComplaint implements Notifier. The body mirrors the Telegram complaint
+// message so both channels read the same.
+func (e *Email) SendComplaint(c Complaint) error {
+	var body bytes.Buffer
+	if err := complaintTmpl.Execute(&body, complaintView{
+		Complaint: c,
+		BeltEmoji: belt.StyleFor(c.Belt).Emoji,
+		BeltName:  belt.DisplayName(c.Belt),
+	}); err != nil {
+		return fmt.Errorf("render complaint email: %w", err)
+	}
+	subject := fmt.Sprintf("%s — %s belt", c.Headline(), belt.DisplayName(c.Belt))
+	return e.deliver(subject, body.String())
+}
+
+// EditStatus implements Notifier. Sent mail can't be edited, and a
+// follow-up mail per resolution would double inbox volume, so resolutions
+// are not emailed.
+func (e *Email) EditStatus(Status) error { return nil }
+
+// SendAlert implements Notifier.
+func (e *Email) SendAlert(a Alert) error {
+	if a.Time.IsZero() {
+		a.Time = time.Now()
+	}
+	var body bytes.Buffer
+	if err := alertTmpl.Execute(&body, a); err != nil {
+		return fmt.Errorf("render alert email: %w", err)
+	}
+	subject := "⚠️ CMON: " + a.Title
+	switch {
+	case a.Critical():
+		subject = "🚨 CRITICAL ALERT - CMON: " + a.Title
+	case a.Recovered():
+		subject = "✅ RECOVERED - CMON: " + a.Title
+	}
+	return e.deliver(subject, body.String())
+}
+
+func (e *Email) deliver(subject, html string) error {
+	msg, err := buildMessage(e.cfg.From, e.cfg.To, subject, html)
+	if err != nil {
+		return err
+	}
+	if err := e.send(e.cfg, msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// buildMessage assembles an RFC 5322 message with a quoted-printable HTML
+// body. The subject is RFC 2047-encoded so emoji survive every client.
+func buildMessage(from string, to []string, subject, html string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&b)
+	if _, err := qp.Write([]byte(html)); err != nil {
+		return nil, fmt.Errorf("encode email body: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return nil, fmt.Errorf("encode email body: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// sendMail delivers msg. Port 465 speaks TLS from the first byte, which
+// smtp.SendMail can't do; other ports go through SendMail, which upgrades
+// with STARTTLS whenever the server offers it.
+func sendMail(cfg EmailConfig, msg []byte) error {
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	if cfg.Port != 465 {
+		return smtp.SendMail(addr, auth, cfg.From, cfg.To, msg)
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 30 * time.Second}, "tcp", addr, &tls.Config{ServerName: cfg.Host})
+	if err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(cfg.From); err != nil {
+		return err
+	}
+	for _, rcpt := range cfg.To {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+type complaintView struct {
+	Complaint
+	BeltEmoji string
+	BeltName  string
+}
+
+var complaintTmpl = template.Must(template.New("complaint").Parse(`<div style="font-family:sans-serif;font-size:14px;line-height:1.5">
+{{- if .DataIssues}}
+<p>⚠️ <b>Intake issues:</b> {{.DataIssues}}</p>
+{{- end}}
+{{- if .Reopened}}
+<p>♻️ <b>RE-OPENED</b> — resolved {{.Reopened.ResolvedAt.Local.Format "02 Jan 2006 15:04"}}, now back on the dashboard.</p>
+{{- end}}
+<p>📋 Complaint : {{.Number}}</p>
+<p>{{.BeltEmoji}} Belt: {{.BeltName}}<br>
+👤 {{.ComplainantName}}<br>
+📞 {{.MobileNo}}<br>
+🆔 Consumer: {{.ConsumerNo}}<br>
+📅 {{.ComplainDate}}</p>
+<p>💬 <b>Details:</b><br>{{.Description}}</p>
+<p>📍 {{.ExactLocation}}, {{.Area}}
+{{- if .MapsURL}}<br>
+🗺️ <a href="{{.MapsURL}}">Open in Google Maps</a>
+{{- end}}</p>
+{{- if .RepeatNote}}
+<p><b>{{.RepeatNote}}</b></p>
+{{- end}}
+{{- if .Translation}}
+<hr>
+<p style="white-space:pre-line">{{.Translation}}</p>
+{{- end}}
+</div>
+`))
+
+var alertTmpl = template.Must(template.New("alert").Parse(`<div style="font-family:sans-serif;font-size:14px;line-height:1.5">
+{{- if .Critical}}
+<p>🚨 <b>CRITICAL ALERT - CMON SERVICE</b></p>
+<p><b>Error Type:</b> {{.Title}}<br>
+<b>Error Message:</b> {{.Message}}<br>
+{{- if .RetryCount}}
+<b>Retry Attempts:</b> {{.RetryCount}}<br>
+{{- end}}
+<b>Timestamp:</b> {{.Time.Format "2006-01-02 15:04:05"}}</p>
+<p>⚠️ <b>Action Required:</b> Please check the service immediately.</p>
+{{- else if .Recovered}}
+<p>✅ <b>RECOVERED - CMON SERVICE</b></p>
+<p><b>Error Type:</b> {{.Title}}</p>
+<p style="white-space:pre-line">{{.Message}}</p>
+<p><b>Timestamp:</b> {{.Time.Format "2006-01-02 15:04:05"}}</p>
+{{- else}}
+<p>⚠️ <b>{{.Title}}</b></p>
+<p style="white-space:pre-line">{{.Message}}</p>
+<p><b>Timestamp:</b> {{.Time.Format "2006-01-02 15:04:05"}}</p>
+{{- end}}
+</div>
+`))
@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewEmailDisabled(t *testing.T) {
+	if e := NewEmail(EmailConfig{To: []string{"ops@dgvcl.in"}}); e != nil {
+		t.Error("missing host should disable email")
+	}
+	if e := NewEmail(EmailConfig{Host: "smtp.example.com"}); e != nil {
+		t.Error("missing recipients should disable email")
+	}
+}
+
+// captureEmail returns an Email whose sends are recorded instead of hitting SMTP.
+func captureEmail(t *testing.T) (*Email, *[]byte) {
+	t.Helper()
+	var sent []byte
+	e := NewEmail(EmailConfig{Host: "smtp.example.com", Port: 587, From: "cmon@dgvcl.in", To: []string{"ops@dgvcl.in", "je@dgvcl.in"}})
+	e.send = func(cfg EmailConfig, msg []byte) error {
+		sent = msg
+		return nil
+	}
+	return e, &sent
+}
+
+// decode parses a captured message into its decoded subject and HTML body.
+func decode(t *testing.T, raw []byte) (*mail.Message, string, string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decode subject: %v", err)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	return msg, subject, string(body)
+}
+
+func TestEmailSendComplaint(t *testing.T) {
+	e, sent := captureEmail(t)
+	err := e.SendComplaint(Complaint{
+		Number:          "12345",
+		Belt:            "Buhari",
+		ComplainantName: "Ramesh <Patel>",
+		Description:     "No supply since morning",
+		ExactLocation:   "Near temple",
+		Area:            "Tokarva",
+		MapsURL:         "https://www.google.com/maps?q=21.1,73.2",
+		RepeatNote:      "🔁 2nd complaint this month from this consumer",
+		DataIssues:      "missing mobile number",
+	})
+	if err != nil {
+		t.Fatalf("SendComplaint: %v", err)
+	}
+
+	msg, subject, body := decode(t, *sent)
+	if subject != "📋 Complaint 12345 — Buhari belt" {
+		t.Errorf("subject: got %q", subject)
+	}
+	if got := msg.Header.Get("To"); got != "ops@dgvcl.in, je@dgvcl.in" {
+		t.Errorf("To: got %q", got)
+	}
+	for _, want := range []string{
+		"⚠️ <b>Intake issues:</b> missing mobile number",
+		"📋 Complaint : 12345",
+		"🟢 Belt: Buhari",
+		"👤 Ramesh &lt;Patel&gt;",
+		"📍 Near temple, Tokarva",
+		`<a href="https://www.google.com/maps?q=21.1,73.2">Open in Google Maps</a>`,
+		"<b>🔁 2nd complaint this month from this consumer</b>",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "<hr>") {
+		t.Error("translation separator should be omitted without a translation")
+	}
+}
+
+func TestEmailSendAlert(t *testing.T) {
+	e, sent := captureEmail(t)
+	at := time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)
+	if err := e.SendAlert(Alert{Title: "Fetch/Login Failure", Message: "timeout", RetryCount: 3, Kind: AlertCritical, Time: at}); err != nil {
+		t.Fatalf("SendAlert: %v", err)
+	}
+	_, subject, body := decode(t, *sent)
+	if subject != "🚨 CRITICAL ALERT - CMON: Fetch/Login Failure" {
+		t.Errorf("subject: got %q", subject)
+	}
+	for _, want := range []string{"<b>Error Message:</b> timeout", "<b>Retry Attempts:</b> 3", "2026-03-10 09:30:00", "Action Required"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+
+	if err := e.SendAlert(Alert{Kind: AlertPortalUp, Title: "DGVCL portal is back", Time: at}); err != nil {
+		t.Fatalf("SendAlert: %v", err)
+	}
+	_, subject, body = decode(t, *sent)
+	if subject != "⚠️ CMON: DGVCL portal is back" || strings.Contains(body, "Action Required") {
+		t.Errorf("informational alert: subject %q body %s", subject, body)
+	}
+
+	if err := e.SendAlert(Alert{Kind: AlertRecovered, Title: "Fetch/Login Failure", Message: "Down for 2h0m0s", Time: at}); err != nil {
+		t.Fatalf("SendAlert: %v", err)
+	}
+	_, subject, body = decode(t, *sent)
+	if subject != "✅ RECOVERED - CMON: Fetch/Login Failure" || !strings.Contains(body, "Down for 2h0m0s") || strings.Contains(body, "Action Required") {
+		t.Errorf("recovery alert: subject %q body %s", subject, body)
+	}
+}
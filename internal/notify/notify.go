@@ -0,0 +1,326 @@
+// Package notify defines the channel-agnostic notification contract.
+//
+// Each delivery channel (Telegram, email, …) implements Notifier; the daemon
+// builds the active channels from config once at startup, wraps them in a
+// Multi and subscribes that to the event bus (see package eventbus), so the
+// fetcher and alert paths never need to know which channels are enabled.
+//
+// Payloads are plain strings rather than complaint.Details so channel
+// packages don't depend on the complaint package (which depends on them).
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cmon/internal/outage"
+)
+
+// Complaint is a new complaint, already enriched by the fetcher. The JSON
+// tags match complaint.Details so channels that consume the Details JSON
+// (Telegram) can take a marshalled Complaint unchanged.
+type Complaint struct {
+	Number          string `json:"complain_no"`
+	Belt            string `json:"belt,omitempty"` // raw belt name; channels style it via the belt package
+	ComplainantName string `json:"complainant_name"`
+	MobileNo        string `json:"mobile_no"`
+	ConsumerNo      string `json:"consumer_no"`
+	ComplainDate    string `json:"complain_date"`
+	Description     string `json:"description"`
+	ExactLocation   string `json:"exact_location"`
+	Area            string `json:"area"`
+	Village         string `json:"village,omitempty"`
+	Feeder          string `json:"feeder,omitempty"`      // from FEEDER_MAP_FILE, empty when unmapped
+	Category        string `json:"category,omitempty"`    // category key detected at intake, e.g. "no_supply"
+	MapsURL         string `json:"maps_url,omitempty"`    // Google Maps link, empty when geocoding is off
+	RepeatNote      string `json:"repeat_note,omitempty"` // "🔁 3rd complaint this month…", empty for a first complaint
+	DataIssues      string `json:"data_issues,omitempty"` // readable intake problems, empty when clean
+	Translation     string `json:"-"`                     // Gujarati block, empty when translation is off
+
+	// Gujarati-script name, details and address, empty when translation is
+	// off or failed (then TranslationNote says why, e.g. rate limited).
+	// Channels with a bilingual layout show them under the English lines.
+	NameGu          string `json:"name_gu,omitempty"`
+	DescriptionGu   string `json:"description_gu,omitempty"`
+	AddressGu       string `json:"address_gu,omitempty"`
+	TranslationNote string `json:"translation_note,omitempty"`
+	// TranslationPending is set while the translation is still being made:
+	// channels that can add it to their message later (TranslationEditor)
+	// send the complaint straight away, the others hold it back until
+	// EditTranslation brings the translated complaint.
+	TranslationPending bool `json:"-"`
+
+	// Reopened is set when the complaint was resolved earlier and has
+	// reappeared on the dashboard; nil for a genuinely new complaint.
+	Reopened *Reopen `json:"reopened,omitempty"`
+
+	// SeenAt is when the complaint was found on the dashboard (or
+	// registered locally), for the discovery-to-message latency; zero
+	// when unknown.
+	SeenAt time.Time `json:"seen_at,omitzero"`
+}
+
+// Reopen describes the earlier resolution of a re-opened complaint.
+type Reopen struct {
+	ResolvedAt        time.Time `json:"resolved_at"`             // in the zone it is shown in
+	TelegramMessageID string    `json:"tg_message_id,omitempty"` // original message, empty if unknown
+}
+
+// Headline is the complaint's title line: "📋 Complaint N", or
+// "♻️ RE-OPENED: Complaint N" when it was resolved before.
+func (c Complaint) Headline() string {
+	if c.Reopened != nil {
+		return "♻️ RE-OPENED: Complaint " + c.Number
+	}
+	return "📋 Complaint " + c.Number
+}
+
+// Redacted returns c with the mobile and consumer numbers masked by
+// MaskNumber, for chats too wide to see them in full (REDACT_PII).
+func (c Complaint) Redacted() Complaint {
+	c.MobileNo = MaskNumber(c.MobileNo)
+	c.ConsumerNo = MaskNumber(c.ConsumerNo)
+	return c
+}
+
+// Urgent reports whether c is HIGH priority: its description contains one
+// of keywords ("fire", "wire down"), matched case-insensitively. Urgent
+// complaints are delivered even during quiet hours.
+func (c Complaint) Urgent(keywords []string) bool {
+	desc := strings.ToLower(c.Description)
+	for _, k := range keywords {
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" && strings.Contains(desc, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskNumber hides all but the last four characters of s:
+// "9876543210" → "••••••3210". Values of four or fewer are left as they are.
+func MaskNumber(s string) string {
+	s = strings.TrimSpace(s)
+	r := []rune(s)
+	if len(r) <= 4 {
+		return s
+	}
+	return strings.Repeat("•", len(r)-4) + string(r[len(r)-4:])
+}
+
+// AlertKind tells channels which of their alert formats to use.
+type AlertKind int
+
+const (
+	// AlertCritical means the service needs someone to look at it
+	// (fetch/login retries exhausted).
+	AlertCritical AlertKind = iota
+	// AlertPortalDown and AlertPortalUp bracket a DGVCL portal outage.
+	AlertPortalDown
+	AlertPortalUp
+	// AlertOutage is a "possible outage" cluster of complaints from one area.
+	AlertOutage
+	// AlertRecovered closes an AlertCritical with the same Title once a
+	// fetch succeeds again.
+	AlertRecovered
+	// AlertImported lists the complaints the initial import stored without
+	// a message each (INITIAL_IMPORT=digest).
+	AlertImported
+)
+
+// Alert is an operational message about the service or the network.
+type Alert struct {
+	Kind       AlertKind
+	Title      string // e.g. "Fetch/Login Failure", "DGVCL portal unavailable"
+	Message    string
+	RetryCount int             // attempts made before giving up; 0 when not applicable
+	Outage     *outage.Cluster // set for AlertOutage
+	Time       time.Time
+}
+
+// Critical reports whether a is a critical alert.
+func (a Alert) Critical() bool { return a.Kind == AlertCritical }
+
+// Recovered reports whether a announces the end of a critical failure.
+func (a Alert) Recovered() bool { return a.Kind == AlertRecovered }
+
+// Status is a change in a complaint's state that channels reflect on the
+// message they sent earlier (Telegram edits it in place).
+type Status struct {
+	ComplaintID  string
+	ConsumerName string
+	Belt         string
+	Category     string
+	Local        bool // locally registered complaint, resolved from the dashboard
+	Time         time.Time
+
+	// Channel is the channel the complaint was resolved from ("telegram",
+	// "whatsapp"), which has already updated its own messages; empty when
+	// it was resolved on the portal or the dashboard.
+	Channel string
+
+	// ResolvedBy and Remark are who closed the complaint on the portal and
+	// what they wrote there, when its record says; empty otherwise.
+	ResolvedBy string
+	Remark     string
+}
+
+// Resolution renders the portal's account of the resolution, e.g.
+// "Resolved on portal by JE Tokarva: fuse replaced", or "" when unknown.
+func (s Status) Resolution() string {
+	line := "Resolved on portal"
+	switch {
+	case s.ResolvedBy == "" && s.Remark == "":
+		return ""
+	case s.ResolvedBy != "":
+		line += " by " + s.ResolvedBy
+	}
+	if s.Remark != "" {
+		line += ": " + s.Remark
+	}
+	return line
+}
+
+// Notifier delivers complaints and alerts to one channel. Implementations
+// must be safe for sequential use from the fetch loop and alert paths.
+type Notifier interface {
+	// Name identifies the channel in logs, e.g. "email".
+	Name() string
+	SendComplaint(c Complaint) error
+	SendAlert(a Alert) error
+	// EditStatus marks a previously sent complaint as resolved. Channels
+	// that cannot edit what they sent may ignore it.
+	EditStatus(s Status) error
+}
+
+// BatchSender is implemented by channels that can announce several new
+// complaints at once, such as Telegram's digest message.
+type BatchSender interface {
+	SendComplaints(cs []Complaint) error
+}
+
+// SendComplaint announces c on n, unless n must wait for c's translation
+// (see Complaint.TranslationPending).
+func SendComplaint(n Notifier, c Complaint) error {
+	if awaitsTranslation(n, c) {
+		return nil
+	}
+	return n.SendComplaint(c)
+}
+
+// SendComplaints announces cs on n: in one call when n is a BatchSender,
+// otherwise one complaint at a time. Complaints n must wait to see
+// translated are left out. Every complaint is attempted; the failures are
+// joined.
+func SendComplaints(n Notifier, cs []Complaint) error {
+	ready := make([]Complaint, 0, len(cs))
+	for _, c := range cs {
+		if !awaitsTranslation(n, c) {
+			ready = append(ready, c)
+		}
+	}
+	if len(ready) == 0 {
+		return nil
+	}
+	if b, ok := n.(BatchSender); ok {
+		return b.SendComplaints(ready)
+	}
+	var errs []error
+	for _, c := range ready {
+		if err := n.SendComplaint(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// TranslationEditor is implemented by channels that can edit a complaint's
+// message once its Gujarati translation arrives, such as Telegram.
+type TranslationEditor interface {
+	EditTranslation(c Complaint) error
+}
+
+// EditTranslation delivers c's finished translation on n: a
+// TranslationEditor adds it to the message it sent, any other channel held
+// c back and sends it now.
+func EditTranslation(n Notifier, c Complaint) error {
+	if e, ok := n.(TranslationEditor); ok {
+		return e.EditTranslation(c)
+	}
+	return n.SendComplaint(c)
+}
+
+// awaitsTranslation reports whether n holds c back until it is translated:
+// the translation is on its way and n could not add it afterwards.
+func awaitsTranslation(n Notifier, c Complaint) bool {
+	_, edits := n.(TranslationEditor)
+	return c.TranslationPending && !edits
+}
+
+// FetchFailureSender is implemented by channels that report every failed
+// fetch cycle, such as the webhook, rather than only the critical alert
+// raised for the first one of an ongoing failure.
+type FetchFailureSender interface {
+	SendFetchFailure(err error, attempts int, at time.Time) error
+}
+
+// MessageRefs persists what a channel needs to edit a message it sent (a
+// Slack ts, a Discord message ID), keyed by channel name and complaint.
+type MessageRefs interface {
+	GetMessageRef(channel, complaintID string) string
+	SetMessageRef(channel, complaintID, ref string) error
+}
+
+// ResolveFunc resolves a complaint on the portal on behalf of a chat user
+// who pressed a channel's resolve button, then updates every channel via
+// EditStatus. by names the user, via the channel ("Slack").
+type ResolveFunc func(complaintID, by, via string) error
+
+// ErrAlreadyResolved is returned by a ResolveFunc when the complaint is no
+// longer open, e.g. because it was resolved from another channel.
+var ErrAlreadyResolved = errors.New("complaint already resolved")
+
+// Multi fans every call out to all of its channels. One channel failing
+// never stops delivery to the others; the failures are joined into the
+// returned error, each prefixed with the channel name.
+type Multi []Notifier
+
+// Name implements Notifier.
+func (m Multi) Name() string { return "multi" }
+
+// SendComplaint implements Notifier.
+func (m Multi) SendComplaint(c Complaint) error {
+	return m.each(func(n Notifier) error { return SendComplaint(n, c) })
+}
+
+// SendComplaints implements BatchSender, letting each channel batch cs its
+// own way.
+func (m Multi) SendComplaints(cs []Complaint) error {
+	return m.each(func(n Notifier) error { return SendComplaints(n, cs) })
+}
+
+// SendAlert implements Notifier.
+func (m Multi) SendAlert(a Alert) error {
+	return m.each(func(n Notifier) error { return n.SendAlert(a) })
+}
+
+// EditStatus implements Notifier.
+func (m Multi) EditStatus(s Status) error {
+	return m.each(func(n Notifier) error { return n.EditStatus(s) })
+}
+
+// EditTranslation implements TranslationEditor.
+func (m Multi) EditTranslation(c Complaint) error {
+	return m.each(func(n Notifier) error { return EditTranslation(n, c) })
+}
+
+func (m Multi) each(fn func(Notifier) error) error {
+	var errs []error
+	for _, n := range m {
+		if err := fn(n); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", n.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
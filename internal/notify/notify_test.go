@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// recorder is a Notifier that records calls and optionally fails.
+type recorder struct {
+	name  string
+	fail  bool
+	calls []string
+}
+
+func (r *recorder) Name() string { return r.name }
+
+func (r *recorder) record(call string) error {
+	r.calls = append(r.calls, call)
+	if r.fail {
+		return errors.New("boom")
+	}
+	return nil
+}
+
+func (r *recorder) SendComplaint(c Complaint) error { return r.record("complaint:" + c.Number) }
+func (r *recorder) SendAlert(a Alert) error         { return r.record("alert:" + a.Title) }
+func (r *recorder) EditStatus(s Status) error       { return r.record("status:" + s.ComplaintID) }
+
+func TestMultiFansOutPastFailures(t *testing.T) {
+	bad := &recorder{name: "telegram", fail: true}
+	good := &recorder{name: "email"}
+	m := Multi{bad, good}
+
+	err := m.SendComplaint(Complaint{Number: "123"})
+	if err == nil || !strings.Contains(err.Error(), "telegram: boom") {
+		t.Errorf("error should name the failing channel; got %v", err)
+	}
+	if len(good.calls) != 1 || good.calls[0] != "complaint:123" {
+		t.Errorf("later channel should still be called; got %v", good.calls)
+	}
+
+	if err := (Multi{good}).SendAlert(Alert{Title: "x"}); err != nil {
+		t.Errorf("all channels ok should return nil; got %v", err)
+	}
+	if err := (Multi{good}).EditStatus(Status{ComplaintID: "123"}); err != nil {
+		t.Errorf("EditStatus: %v", err)
+	}
+	if got := strings.Join(good.calls, ","); got != "complaint:123,alert:x,status:123" {
+		t.Errorf("calls: got %s", got)
+	}
+}
+
+// editor is a recorder that can add a translation to what it sent.
+type editor struct{ recorder }
+
+func (e *editor) EditTranslation(c Complaint) error { return e.record("translated:" + c.Number) }
+
+func TestMultiHoldsPendingTranslationsForChannelsThatCannotEdit(t *testing.T) {
+	tg := &editor{recorder{name: "telegram"}}
+	email := &recorder{name: "email"}
+	m := Multi{tg, email}
+
+	pending := Complaint{Number: "1", TranslationPending: true}
+	if err := m.SendComplaints([]Complaint{pending, {Number: "2"}}); err != nil {
+		t.Fatalf("SendComplaints: %v", err)
+	}
+	if err := m.SendComplaint(Complaint{Number: "3", TranslationPending: true}); err != nil {
+		t.Fatalf("SendComplaint: %v", err)
+	}
+	if got := strings.Join(email.calls, ","); got != "complaint:2" {
+		t.Errorf("email calls = %s, want the pending complaints held back", got)
+	}
+
+	pending.TranslationPending, pending.NameGu = false, "રમેશ"
+	if err := m.EditTranslation(pending); err != nil {
+		t.Fatalf("EditTranslation: %v", err)
+	}
+	if got := strings.Join(email.calls, ","); got != "complaint:2,complaint:1" {
+		t.Errorf("email calls = %s, want complaint 1 sent once translated", got)
+	}
+	if got := strings.Join(tg.calls, ","); got != "complaint:1,complaint:2,complaint:3,translated:1" {
+		t.Errorf("telegram calls = %s, want everything at once and the translation as an edit", got)
+	}
+}
+
+func TestEmptyMultiIsNoop(t *testing.T) {
+	var m Multi
+	if err := m.SendComplaint(Complaint{}); err != nil {
+		t.Errorf("empty Multi: %v", err)
+	}
+}
+
+func TestRedactedMasksNumbers(t *testing.T) {
+	c := Complaint{Number: "C-1", ComplainantName: "Asha", MobileNo: "9876543210", ConsumerNo: " 11223344 "}
+	r := c.Redacted()
+	if r.MobileNo != "••••••3210" || r.ConsumerNo != "••••3344" {
+		t.Errorf("got mobile %q consumer %q", r.MobileNo, r.ConsumerNo)
+	}
+	if r.ComplainantName != "Asha" || c.MobileNo != "9876543210" {
+		t.Errorf("Redacted changed other fields or the original: %+v / %+v", r, c)
+	}
+	if got := MaskNumber("123"); got != "123" {
+		t.Errorf("short value: got %q", got)
+	}
+}
+
+func TestUrgentMatchesKeywords(t *testing.T) {
+	keywords := []string{"fire", " Wire Down "}
+	for desc, want := range map[string]bool{
+		"Sparks and FIRE near pole": true,
+		"wire down on the road":     true,
+		"No supply since morning":   false,
+	} {
+		if got := (Complaint{Description: desc}).Urgent(keywords); got != want {
+			t.Errorf("Urgent(%q) = %v, want %v", desc, got, want)
+		}
+	}
+	if (Complaint{Description: "fire"}).Urgent(nil) {
+		t.Error("no keywords should mean nothing is urgent")
+	}
+}
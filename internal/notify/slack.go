@@ -0,0 +1,359 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"cmon/internal/belt"
+)
+
+// slackResolveAction is the action_id of the "Mark as Resolved" button.
+const slackResolveAction = "resolve"
+
+// SlackConfig configures NewSlack.
+type SlackConfig struct {
+	BotToken  string // xoxb-… token with chat:write
+	ChannelID string
+	// SigningSecret verifies interaction requests. Empty leaves the resolve
+	// button off, since clicks could not be authenticated.
+	SigningSecret string
+	// APIBase overrides https://slack.com/api for tests.
+	APIBase string
+	// Location is the zone resolved times are shown in; nil uses time.Local.
+	Location *time.Location
+}
+
+// Slack posts complaints to a Slack channel as Block Kit messages with a
+// resolve button, and edits them in place when the complaint is resolved.
+type Slack struct {
+	cfg    SlackConfig
+	refs   MessageRefs
+	client *http.Client
+}
+
+// NewSlack returns a Slack Notifier, or nil when the bot token or channel
+// is missing.
+func NewSlack(cfg SlackConfig, refs MessageRefs) *Slack {
+	if cfg.BotToken == "" || cfg.ChannelID == "" {
+		return nil
+	}
+	if cfg.APIBase == "" {
+		cfg.APIBase = "https://slack.com/api"
+	}
+	log.Printf("✓ Slack notifications enabled for channel %s", cfg.ChannelID)
+	return &Slack{cfg: cfg, refs: refs, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Name implements Notifier.
+func (s *Slack) Name() string { return "slack" }
+
+// SendComplaint implements Notifier. The message ts is stored so EditStatus
+// can replace the message later.
+func (s *Slack) SendComplaint(c Complaint) error {
+	blocks := slackComplaintBlocks(c)
+	if s.cfg.SigningSecret != "" {
+		blocks = append(blocks, slackBlock{
+			"type": "actions",
+			"elements": []slackBlock{{
+				"type":      "button",
+				"action_id": slackResolveAction,
+				"value":     c.Number,
+				"style":     "primary",
+				"text":      slackText("plain_text", "✅ Mark as Resolved"),
+				"confirm": slackBlock{
+					"title":   slackText("plain_text", "Resolve complaint?"),
+					"text":    slackText("mrkdwn", fmt.Sprintf("Mark complaint *%s* as resolved on the DGVCL portal?", slackEscape(c.Number))),
+					"confirm": slackText("plain_text", "Resolve"),
+					"deny":    slackText("plain_text", "Cancel"),
+				},
+			}},
+		})
+	}
+
+	var resp struct {
+		TS string `json:"ts"`
+	}
+	if err := s.call("chat.postMessage", slackBlock{
+		"channel": s.cfg.ChannelID,
+		"text":    fmt.Sprintf("%s — %s", c.Headline(), c.ComplainantName),
+		"blocks":  blocks,
+	}, &resp); err != nil {
+		return err
+	}
+	if err := s.refs.SetMessageRef(s.Name(), c.Number, resp.TS); err != nil {
+		return fmt.Errorf("failed to persist Slack message ts: %w", err)
+	}
+	return nil
+}
+
+// SendAlert implements Notifier.
+func (s *Slack) SendAlert(a Alert) error {
+	title := "⚠️ " + a.Title
+	switch {
+	case a.Critical():
+		title = "🚨 CRITICAL ALERT - CMON: " + a.Title
+	case a.Recovered():
+		title = "✅ RECOVERED - CMON: " + a.Title
+	}
+	text := fmt.Sprintf("*%s*", slackEscape(title))
+	if a.Message != "" {
+		text += "\n" + slackEscape(a.Message)
+	}
+	if a.RetryCount > 0 {
+		text += fmt.Sprintf("\nRetry attempts: %d", a.RetryCount)
+	}
+	return s.call("chat.postMessage", slackBlock{"channel": s.cfg.ChannelID, "text": text}, nil)
+}
+
+// EditStatus implements Notifier: the complaint message is replaced with a
+// short resolved card and loses its button.
+func (s *Slack) EditStatus(st Status) error {
+	ts := s.refs.GetMessageRef(s.Name(), st.ComplaintID)
+	if ts == "" {
+		return nil // not posted to Slack (e.g. before the channel was enabled)
+	}
+	return s.updateResolved(s.cfg.ChannelID, ts, st)
+}
+
+func (s *Slack) updateResolved(channel, ts string, st Status) error {
+	if s.cfg.Location != nil {
+		st.Time = st.Time.In(s.cfg.Location)
+	}
+	text := slackResolvedText(st)
+	return s.call("chat.update", slackBlock{
+		"channel": channel,
+		"ts":      ts,
+		"text":    text,
+		"blocks":  []slackBlock{{"type": "section", "text": slackText("mrkdwn", text)}},
+	}, nil)
+}
+
+// call POSTs a Web API method and decodes the result into out (may be nil).
+func (s *Slack) call(method string, payload interface{}, out interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode Slack %s: %w", method, err)
+	}
+	req, err := http.NewRequest(http.MethodPost, s.cfg.APIBase+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+s.cfg.BotToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Slack %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("Slack %s: read response: %w", method, err)
+	}
+
+	// The Web API reports failures as 200 + {"ok":false,"error":"…"}.
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return fmt.Errorf("Slack %s: HTTP %d: %s", method, resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	if !result.OK {
+		return fmt.Errorf("Slack %s: %s", method, result.Error)
+	}
+	if out != nil {
+		return json.Unmarshal(raw, out)
+	}
+	return nil
+}
+
+// InteractionHandler serves Slack's interactivity request URL. A click on
+// the resolve button is acknowledged straight away (Slack allows 3s) and
+// resolved in the background; failures are reported to the clicking user
+// only, via the interaction's response_url.
+func (s *Slack) InteractionHandler(resolve ResolveFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if !verifySlackSignature(s.cfg.SigningSecret, r.Header, body, time.Now()) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		var p slackInteraction
+		if err := json.Unmarshal([]byte(form.Get("payload")), &p); err != nil {
+			http.Error(w, "bad payload", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+
+		for _, a := range p.Actions {
+			if p.Type != "block_actions" || a.ActionID != slackResolveAction || a.Value == "" {
+				continue
+			}
+			go s.handleResolve(resolve, p, a.Value)
+		}
+	})
+}
+
+func (s *Slack) handleResolve(resolve ResolveFunc, p slackInteraction, complaintID string) {
+	by := p.User.Username
+	if by == "" {
+		by = p.User.Name
+	}
+	log.Printf("📞 Slack: %s pressed resolve on complaint %s", by, complaintID)
+
+	err := resolve(complaintID, by, "Slack")
+	switch {
+	case err == nil:
+		return
+	case errors.Is(err, ErrAlreadyResolved):
+		// Resolved elsewhere; the message may still show the button.
+		if p.Container.MessageTS != "" {
+			if err := s.updateResolved(p.Container.ChannelID, p.Container.MessageTS, Status{ComplaintID: complaintID, Time: time.Now()}); err != nil {
+				log.Printf("⚠️  Failed to update Slack message for %s: %v", complaintID, err)
+			}
+		}
+		s.respond(p.ResponseURL, fmt.Sprintf("ℹ️ Complaint %s was already resolved.", complaintID))
+	default:
+		log.Printf("⚠️  Slack resolve failed for %s: %v", complaintID, err)
+		s.respond(p.ResponseURL, fmt.Sprintf("❌ Failed to resolve complaint %s: %v", complaintID, err))
+	}
+}
+
+// respond posts an ephemeral message to the user who clicked.
+func (s *Slack) respond(responseURL, text string) {
+	if responseURL == "" {
+		return
+	}
+	body, _ := json.Marshal(slackBlock{"response_type": "ephemeral", "replace_original": false, "text": text})
+	resp, err := s.client.Post(responseURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Printf("⚠️  Failed to reply to Slack interaction: %v", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+type slackInteraction struct {
+	Type string `json:"type"`
+	User struct {
+		Username string `json:"username"`
+		Name     string `json:"name"`
+	} `json:"user"`
+	Container struct {
+		ChannelID string `json:"channel_id"`
+		MessageTS string `json:"message_ts"`
+	} `json:"container"`
+	ResponseURL string `json:"response_url"`
+	Actions     []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+// verifySlackSignature checks X-Slack-Signature ("v0=" + hex HMAC-SHA256 of
+// "v0:<timestamp>:<body>") and rejects timestamps more than five minutes
+// from now to stop replays.
+func verifySlackSignature(secret string, h http.Header, body []byte, now time.Time) bool {
+	if secret == "" {
+		return false
+	}
+	ts, err := strconv.ParseInt(h.Get("X-Slack-Request-Timestamp"), 10, 64)
+	if err != nil {
+		return false
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > 5*time.Minute || d < -5*time.Minute {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%d:", ts)
+	mac.Write(body)
+	want := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(want), []byte(h.Get("X-Slack-Signature")))
+}
+
+// slackBlock is a loosely-typed Block Kit object.
+type slackBlock map[string]interface{}
+
+func slackText(kind, text string) slackBlock {
+	return slackBlock{"type": kind, "text": text}
+}
+
+// slackComplaintBlocks lays a complaint out like the Telegram message.
+func slackComplaintBlocks(c Complaint) []slackBlock {
+	var blocks []slackBlock
+	if c.DataIssues != "" {
+		blocks = append(blocks, slackBlock{"type": "context", "elements": []slackBlock{
+			slackText("mrkdwn", "⚠️ *Intake issues:* "+slackEscape(c.DataIssues)),
+		}})
+	}
+	blocks = append(blocks,
+		slackBlock{"type": "header", "text": slackText("plain_text", c.Headline())},
+		slackBlock{"type": "section", "fields": []slackBlock{
+			slackText("mrkdwn", fmt.Sprintf("%s *Belt:* %s", belt.StyleFor(c.Belt).Emoji, slackEscape(belt.DisplayName(c.Belt)))),
+			slackText("mrkdwn", "👤 "+slackEscape(c.ComplainantName)),
+			slackText("mrkdwn", "📞 "+slackEscape(c.MobileNo)),
+			slackText("mrkdwn", "🆔 Consumer: "+slackEscape(c.ConsumerNo)),
+			slackText("mrkdwn", "📅 "+slackEscape(c.ComplainDate)),
+		}},
+		slackBlock{"type": "section", "text": slackText("mrkdwn", "💬 *Details:*\n"+slackEscape(c.Description))},
+	)
+	location := fmt.Sprintf("📍 %s, %s", slackEscape(c.ExactLocation), slackEscape(c.Area))
+	if c.MapsURL != "" {
+		location += fmt.Sprintf("\n🗺️ <%s|Open in Google Maps>", c.MapsURL)
+	}
+	blocks = append(blocks, slackBlock{"type": "section", "text": slackText("mrkdwn", location)})
+	if c.RepeatNote != "" {
+		blocks = append(blocks, slackBlock{"type": "context", "elements": []slackBlock{slackText("mrkdwn", "*"+slackEscape(c.RepeatNote)+"*")}})
+	}
+	if c.Translation != "" {
+		blocks = append(blocks, slackBlock{"type": "divider"}, slackBlock{"type": "section", "text": slackText("mrkdwn", slackEscape(c.Translation))})
+	}
+	return blocks
+}
+
+func slackResolvedText(st Status) string {
+	title := "RESOLVED"
+	if st.Local {
+		title = "RESOLVED (LOCAL)"
+	}
+	text := fmt.Sprintf("✅ *%s*\nComplaint #%s", title, slackEscape(st.ComplaintID))
+	if st.ConsumerName != "" {
+		text += "\n👤 " + slackEscape(st.ConsumerName)
+	}
+	if r := st.Resolution(); r != "" {
+		text += "\n📝 " + slackEscape(r)
+	}
+	return text + "\n🕐 " + st.Time.Format("02 Jan 2006, 03:04 PM")
+}
+
+// slackEscape escapes the three characters mrkdwn treats as control
+// sequences.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
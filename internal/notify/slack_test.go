@@ -0,0 +1,156 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memRefs is an in-memory MessageRefs.
+type memRefs struct {
+	mu sync.Mutex
+	m  map[string]string
+}
+
+func (r *memRefs) GetMessageRef(channel, complaintID string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.m[channel+"/"+complaintID]
+}
+
+func (r *memRefs) SetMessageRef(channel, complaintID, ref string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.m == nil {
+		r.m = map[string]string{}
+	}
+	r.m[channel+"/"+complaintID] = ref
+	return nil
+}
+
+func signSlack(secret string, ts int64, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%d:%s", ts, body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestSlackSendThenEditStatus(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	var posted map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, r.URL.Path)
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path == "/chat.postMessage" {
+			posted = body
+		}
+		if r.URL.Path == "/chat.update" && body["ts"] != "1700000000.000100" {
+			t.Errorf("chat.update ts: got %v", body["ts"])
+		}
+		fmt.Fprint(w, `{"ok":true,"ts":"1700000000.000100"}`)
+	}))
+	defer srv.Close()
+
+	refs := &memRefs{}
+	s := NewSlack(SlackConfig{BotToken: "xoxb-1", ChannelID: "C1", SigningSecret: "s", APIBase: srv.URL}, refs)
+	if err := s.SendComplaint(Complaint{Number: "12345", ComplainantName: "Ramesh", Belt: "Buhari"}); err != nil {
+		t.Fatalf("SendComplaint: %v", err)
+	}
+	if got := refs.GetMessageRef("slack", "12345"); got != "1700000000.000100" {
+		t.Errorf("stored ts: got %q", got)
+	}
+	if !strings.Contains(fmt.Sprint(posted["blocks"]), slackResolveAction) {
+		t.Error("resolve button missing with a signing secret configured")
+	}
+	if err := s.EditStatus(Status{ComplaintID: "12345", Time: time.Now()}); err != nil {
+		t.Fatalf("EditStatus: %v", err)
+	}
+	if err := s.EditStatus(Status{ComplaintID: "99999", Time: time.Now()}); err != nil {
+		t.Fatalf("EditStatus for unposted complaint: %v", err)
+	}
+	if want := []string{"/chat.postMessage", "/chat.update"}; fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("calls: got %v, want %v", calls, want)
+	}
+}
+
+func TestSlackAPIErrorSurfaces(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"ok":false,"error":"channel_not_found"}`)
+	}))
+	defer srv.Close()
+
+	s := NewSlack(SlackConfig{BotToken: "xoxb-1", ChannelID: "C1", APIBase: srv.URL}, &memRefs{})
+	err := s.SendAlert(Alert{Kind: AlertCritical, Title: "Login failed"})
+	if err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Errorf("want channel_not_found error, got %v", err)
+	}
+}
+
+func TestVerifySlackSignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte("payload=%7B%7D")
+	h := http.Header{}
+	h.Set("X-Slack-Request-Timestamp", "1700000000")
+	h.Set("X-Slack-Signature", signSlack("s3cret", now.Unix(), string(body)))
+
+	if !verifySlackSignature("s3cret", h, body, now) {
+		t.Error("valid signature rejected")
+	}
+	if verifySlackSignature("other", h, body, now) {
+		t.Error("wrong secret accepted")
+	}
+	if verifySlackSignature("s3cret", h, body, now.Add(10*time.Minute)) {
+		t.Error("stale timestamp accepted")
+	}
+}
+
+func TestSlackInteractionResolves(t *testing.T) {
+	s := NewSlack(SlackConfig{BotToken: "xoxb-1", ChannelID: "C1", SigningSecret: "s3cret"}, &memRefs{})
+	got := make(chan string, 1)
+	h := s.InteractionHandler(func(complaintID, by, via string) error {
+		got <- complaintID + "/" + by + "/" + via
+		return nil
+	})
+
+	payload := `{"type":"block_actions","user":{"username":"ravi"},"actions":[{"action_id":"resolve","value":"12345"}]}`
+	body := url.Values{"payload": {payload}}.Encode()
+	ts := time.Now().Unix()
+	req := httptest.NewRequest(http.MethodPost, "/slack/interactions", strings.NewReader(body))
+	req.Header.Set("X-Slack-Request-Timestamp", fmt.Sprint(ts))
+	req.Header.Set("X-Slack-Signature", signSlack("s3cret", ts, body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status: got %d", rec.Code)
+	}
+	select {
+	case v := <-got:
+		if v != "12345/ravi/Slack" {
+			t.Errorf("resolve args: got %q", v)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("resolve was not called")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/slack/interactions", strings.NewReader(body))
+	req.Header.Set("X-Slack-Request-Timestamp", fmt.Sprint(ts))
+	req.Header.Set("X-Slack-Signature", "v0=deadbeef")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("bad signature: got status %d, want 401", rec.Code)
+	}
+}
@@ -0,0 +1,211 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SMS providers accepted in SMSConfig.Provider.
+const (
+	SMSProviderTwilio = "twilio"
+	SMSProviderMSG91  = "msg91"
+)
+
+// smsMaxLen keeps a page within two concatenated GSM segments; anything
+// longer is cut, since the full detail is on the other channels.
+const smsMaxLen = 300
+
+// SMSConfig configures NewSMS.
+type SMSConfig struct {
+	Provider string   // SMSProviderTwilio or SMSProviderMSG91
+	To       []string // E.164 numbers, e.g. +919876543210
+
+	// Twilio
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	TwilioFrom       string
+
+	// MSG91 (Flow API). The DLT template must take ##title## and ##message##.
+	MSG91AuthKey    string
+	MSG91TemplateID string
+
+	// Cooldown suppresses repeat pages with the same title, so a portal
+	// that stays down does not page on every retry cycle. 0 pages every time.
+	Cooldown time.Duration
+
+	// APIBase overrides the provider's API root for tests.
+	APIBase string
+}
+
+// SMS pages the on-call phone(s) for critical alerts only. It sits beside
+// Telegram rather than behind it, so a broken bot token or an unreachable
+// Telegram still reaches someone.
+type SMS struct {
+	cfg    SMSConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time // alert title -> last page
+	now      func() time.Time
+}
+
+// NewSMS returns an SMS Notifier, or nil when no provider or recipient is
+// configured.
+func NewSMS(cfg SMSConfig) *SMS {
+	if cfg.Provider == "" || len(cfg.To) == 0 {
+		return nil
+	}
+	if cfg.APIBase == "" {
+		switch cfg.Provider {
+		case SMSProviderTwilio:
+			cfg.APIBase = "https://api.twilio.com"
+		case SMSProviderMSG91:
+			cfg.APIBase = "https://control.msg91.com"
+		}
+	}
+	log.Printf("✓ SMS critical alerts enabled via %s for %d recipient(s)", cfg.Provider, len(cfg.To))
+	return &SMS{
+		cfg:      cfg,
+		client:   &http.Client{Timeout: 15 * time.Second},
+		lastSent: map[string]time.Time{},
+		now:      time.Now,
+	}
+}
+
+// Name implements Notifier.
+func (s *SMS) Name() string { return "sms" }
+
+// SendComplaint implements Notifier. Complaints are never sent by SMS.
+func (s *SMS) SendComplaint(Complaint) error { return nil }
+
+// EditStatus implements Notifier. Resolutions are never sent by SMS.
+func (s *SMS) EditStatus(Status) error { return nil }
+
+// SendAlert implements Notifier. Only critical alerts page; every recipient
+// is tried even if an earlier one fails.
+func (s *SMS) SendAlert(a Alert) error {
+	if !a.Critical() || !s.due(a.Title) {
+		return nil
+	}
+	text := "CMON CRITICAL: " + a.Title
+	if a.Message != "" {
+		text += " - " + a.Message
+	}
+	if r := []rune(text); len(r) > smsMaxLen {
+		text = string(r[:smsMaxLen-1]) + "…"
+	}
+
+	var failed []string
+	for _, to := range s.cfg.To {
+		var err error
+		switch s.cfg.Provider {
+		case SMSProviderTwilio:
+			err = s.sendTwilio(to, text)
+		case SMSProviderMSG91:
+			err = s.sendMSG91(to, a.Title, text)
+		default:
+			err = fmt.Errorf("unknown provider %q", s.cfg.Provider)
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", to, err))
+		}
+	}
+	if len(failed) == len(s.cfg.To) {
+		s.forget(a.Title) // nobody was paged; let the next alert retry
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to send SMS: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// due reports whether a page titled title is outside the cooldown, and if
+// so records it as sent now.
+func (s *SMS) due(title string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if last, ok := s.lastSent[title]; ok && s.cfg.Cooldown > 0 && now.Sub(last) < s.cfg.Cooldown {
+		return false
+	}
+	s.lastSent[title] = now
+	return true
+}
+
+func (s *SMS) forget(title string) {
+	s.mu.Lock()
+	delete(s.lastSent, title)
+	s.mu.Unlock()
+}
+
+func (s *SMS) sendTwilio(to, text string) error {
+	form := url.Values{"To": {to}, "From": {s.cfg.TwilioFrom}, "Body": {text}}
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", s.cfg.APIBase, url.PathEscape(s.cfg.TwilioAccountSID))
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(s.cfg.TwilioAccountSID, s.cfg.TwilioAuthToken)
+	_, err = s.do(req)
+	return err
+}
+
+func (s *SMS) sendMSG91(to, title, text string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"template_id": s.cfg.MSG91TemplateID,
+		"short_url":   "0",
+		"recipients": []map[string]string{{
+			"mobiles": strings.TrimPrefix(to, "+"), // MSG91 wants the country code without "+"
+			"title":   title,
+			"message": text,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.cfg.APIBase+"/api/v5/flow/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("authkey", s.cfg.MSG91AuthKey)
+	raw, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	// MSG91 reports some failures as 200 + {"type":"error","message":"…"}.
+	var result struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(raw, &result) == nil && result.Type == "error" {
+		return fmt.Errorf("MSG91: %s", result.Message)
+	}
+	return nil
+}
+
+// do sends req and returns the response body, failing on non-2xx.
+func (s *SMS) do(req *http.Request) ([]byte, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	return raw, nil
+}
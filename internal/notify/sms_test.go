@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSMSOnlyPagesCriticalAlerts(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits++ }))
+	defer srv.Close()
+
+	s := NewSMS(SMSConfig{Provider: SMSProviderTwilio, To: []string{"+919876543210"}, APIBase: srv.URL})
+	if err := s.SendComplaint(Complaint{Number: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SendAlert(Alert{Kind: AlertPortalDown, Title: "Portal down"}); err != nil {
+		t.Fatal(err)
+	}
+	if hits != 0 {
+		t.Errorf("non-critical traffic sent %d SMS", hits)
+	}
+}
+
+func TestSMSTwilioRequest(t *testing.T) {
+	var gotPath, gotUser, gotBody, gotTo string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotUser, _, _ = r.BasicAuth()
+		r.ParseForm()
+		gotBody, gotTo = r.PostForm.Get("Body"), r.PostForm.Get("To")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	s := NewSMS(SMSConfig{
+		Provider:         SMSProviderTwilio,
+		To:               []string{"+919876543210"},
+		TwilioAccountSID: "AC123",
+		TwilioAuthToken:  "tok",
+		TwilioFrom:       "+15005550006",
+		APIBase:          srv.URL,
+	})
+	if err := s.SendAlert(Alert{Kind: AlertCritical, Title: "Login failed", Message: strings.Repeat("x", 500)}); err != nil {
+		t.Fatalf("SendAlert: %v", err)
+	}
+	if gotPath != "/2010-04-01/Accounts/AC123/Messages.json" || gotUser != "AC123" || gotTo != "+919876543210" {
+		t.Errorf("request: path %q user %q to %q", gotPath, gotUser, gotTo)
+	}
+	if !strings.HasPrefix(gotBody, "CMON CRITICAL: Login failed") || len([]rune(gotBody)) != smsMaxLen {
+		t.Errorf("body (%d runes): %q", len([]rune(gotBody)), gotBody)
+	}
+}
+
+func TestSMSMSG91ErrorBody(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("authkey") != "key" {
+			t.Errorf("authkey header: %q", r.Header.Get("authkey"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		fmt.Fprint(w, `{"type":"error","message":"Invalid template"}`)
+	}))
+	defer srv.Close()
+
+	s := NewSMS(SMSConfig{Provider: SMSProviderMSG91, To: []string{"+919876543210"}, MSG91AuthKey: "key", MSG91TemplateID: "tpl", APIBase: srv.URL})
+	err := s.SendAlert(Alert{Kind: AlertCritical, Title: "Login failed"})
+	if err == nil || !strings.Contains(err.Error(), "Invalid template") {
+		t.Errorf("want MSG91 error surfaced, got %v", err)
+	}
+	recipients, _ := got["recipients"].([]interface{})
+	if len(recipients) != 1 || recipients[0].(map[string]interface{})["mobiles"] != "919876543210" {
+		t.Errorf("recipients: %v", got["recipients"])
+	}
+}
+
+func TestSMSCooldown(t *testing.T) {
+	hits := 0
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	clock := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	s := NewSMS(SMSConfig{Provider: SMSProviderTwilio, To: []string{"+919876543210"}, Cooldown: 15 * time.Minute, APIBase: srv.URL})
+	s.now = func() time.Time { return clock }
+	alert := Alert{Kind: AlertCritical, Title: "Login failed"}
+
+	s.SendAlert(alert)
+	clock = clock.Add(5 * time.Minute)
+	s.SendAlert(alert)
+	s.SendAlert(Alert{Kind: AlertCritical, Title: "Fetch failed"})
+	if hits != 2 {
+		t.Errorf("within cooldown: got %d SMS, want 2 (one per title)", hits)
+	}
+
+	clock = clock.Add(15 * time.Minute)
+	fail = true
+	if err := s.SendAlert(alert); err == nil {
+		t.Error("want error when the provider rejects the page")
+	}
+	fail = false
+	s.SendAlert(alert) // the failed page must not start a cooldown
+	if hits != 4 {
+		t.Errorf("after cooldown: got %d SMS, want 4", hits)
+	}
+}
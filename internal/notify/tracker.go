@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AlertTracker deduplicates critical alerts over one ongoing failure so an
+// overnight outage doesn't send an alert per fetch cycle. The first failure
+// alerts; later ones are suppressed except for a reminder every Reminder
+// (0 = never); the first success after an alert produces an AlertRecovered.
+type AlertTracker struct {
+	Reminder time.Duration
+
+	mu       sync.Mutex
+	title    string    // Title of the first alert of the current failure
+	since    time.Time // zero while nothing is failing
+	lastSent time.Time
+	failures int
+}
+
+// NewAlertTracker returns a tracker that reminds every reminder.
+func NewAlertTracker(reminder time.Duration) *AlertTracker {
+	return &AlertTracker{Reminder: reminder}
+}
+
+// Fail records a failure at a.Time (now when zero) and reports whether a
+// should be sent. Reminders are returned with the duration and failure
+// count prepended to the message.
+func (t *AlertTracker) Fail(a Alert) (Alert, bool) {
+	if a.Time.IsZero() {
+		a.Time = time.Now()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.failures++
+	if t.since.IsZero() {
+		t.since, t.title = a.Time, a.Title
+	}
+	switch {
+	case t.lastSent.IsZero():
+	case t.Reminder > 0 && a.Time.Sub(t.lastSent) >= t.Reminder:
+		a.Message = fmt.Sprintf("Still failing after %s (%d failed cycles). %s",
+			a.Time.Sub(t.since).Round(time.Minute), t.failures, a.Message)
+	default:
+		return Alert{}, false
+	}
+	t.lastSent = a.Time
+	return a, true
+}
+
+// Recover records a success at now and returns the recovery alert to send
+// when it ends a failure that was alerted. A failure that never alerted
+// ends silently.
+func (t *AlertTracker) Recover(now time.Time) (Alert, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	alerted := !t.lastSent.IsZero()
+	a := Alert{
+		Kind:    AlertRecovered,
+		Title:   t.title,
+		Message: fmt.Sprintf("Fetching works again after %s (%d failed cycles).", now.Sub(t.since).Round(time.Minute), t.failures),
+		Time:    now,
+	}
+	t.title, t.since, t.lastSent, t.failures = "", time.Time{}, time.Time{}, 0
+	return a, alerted
+}
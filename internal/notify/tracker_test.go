@@ -0,0 +1,54 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAlertTrackerDedupesAndReminds(t *testing.T) {
+	tr := NewAlertTracker(time.Hour)
+	start := time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC)
+	alert := func(at time.Time) Alert {
+		return Alert{Kind: AlertCritical, Title: "Fetch/Login Failure", Message: "timeout", Time: at}
+	}
+
+	if a, ok := tr.Fail(alert(start)); !ok || a.Message != "timeout" {
+		t.Fatalf("first failure: got %+v, %v", a, ok)
+	}
+	if _, ok := tr.Fail(alert(start.Add(15 * time.Minute))); ok {
+		t.Error("repeat inside the reminder interval must be suppressed")
+	}
+	a, ok := tr.Fail(alert(start.Add(time.Hour)))
+	if !ok || a.Message != "Still failing after 1h0m0s (3 failed cycles). timeout" {
+		t.Errorf("reminder: got %q, %v", a.Message, ok)
+	}
+	if _, ok := tr.Fail(alert(start.Add(90 * time.Minute))); ok {
+		t.Error("reminders are an hour after the last one, not the first")
+	}
+
+	rec, ok := tr.Recover(start.Add(2 * time.Hour))
+	if !ok || rec.Kind != AlertRecovered || rec.Title != "Fetch/Login Failure" || !strings.Contains(rec.Message, "after 2h0m0s (4 failed cycles)") {
+		t.Errorf("recovery: got %+v, %v", rec, ok)
+	}
+	if _, ok := tr.Recover(start.Add(3 * time.Hour)); ok {
+		t.Error("a second success must not announce recovery again")
+	}
+	if _, ok := tr.Fail(alert(start.Add(4 * time.Hour))); !ok {
+		t.Error("a new failure after recovery must alert again")
+	}
+}
+
+func TestAlertTrackerWithoutReminders(t *testing.T) {
+	tr := NewAlertTracker(0)
+	at := time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC)
+	tr.Fail(Alert{Title: "x", Time: at})
+	if _, ok := tr.Fail(Alert{Title: "x", Time: at.Add(24 * time.Hour)}); ok {
+		t.Error("Reminder 0 must never repeat the alert")
+	}
+
+	quiet := NewAlertTracker(time.Hour)
+	if _, ok := quiet.Recover(at); ok {
+		t.Error("recovery without a prior alert must stay silent")
+	}
+}
@@ -0,0 +1,263 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Webhook event names, sent as the "event" field and the X-Cmon-Event header.
+const (
+	EventComplaintNew       = "complaint.new"
+	EventComplaintReopened  = "complaint.reopened"
+	EventComplaintResolved  = "complaint.resolved"
+	EventFetchFailed        = "fetch.failed"
+	EventServiceCritical    = "service.critical"
+	EventPortalDown         = "portal.down"
+	EventPortalUp           = "portal.up"
+	EventFetchRecovered     = "fetch.recovered"
+	EventOutageDetected     = "outage.detected"
+	EventComplaintsImported = "complaints.imported"
+)
+
+// WebhookConfig configures NewWebhook.
+type WebhookConfig struct {
+	URLs []string
+	// Secret signs each body with HMAC-SHA256, sent as
+	// "X-Cmon-Signature: sha256=<hex>". Empty disables signing.
+	Secret string
+	// MaxRetries is how many times a failed delivery is retried (network
+	// errors, 429 and 5xx only) with exponential backoff from 1s.
+	MaxRetries int
+	Timeout    time.Duration
+}
+
+// Webhook POSTs every notification as a JSON event to each configured URL,
+// so cmon can feed n8n/Zapier flows or a ticketing system.
+type Webhook struct {
+	cfg    WebhookConfig
+	client *http.Client
+
+	// sleep waits between retries; swapped by tests.
+	sleep func(time.Duration)
+}
+
+// NewWebhook returns a webhook Notifier, or nil when no URL is configured.
+func NewWebhook(cfg WebhookConfig) *Webhook {
+	if len(cfg.URLs) == 0 {
+		return nil
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	log.Printf("✓ Webhook notifications enabled for %d URL(s)", len(cfg.URLs))
+	return &Webhook{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}, sleep: time.Sleep}
+}
+
+// WebhookEvent is the envelope of every delivery.
+type WebhookEvent struct {
+	ID    string      `json:"id"` // unique per event, the same across retries and URLs
+	Event string      `json:"event"`
+	Time  time.Time   `json:"time"`
+	Data  interface{} `json:"data"`
+}
+
+// webhookResolved is the data of a complaint.resolved event.
+type webhookResolved struct {
+	Number       string    `json:"complain_no"`
+	ConsumerName string    `json:"consumer_name,omitempty"`
+	Belt         string    `json:"belt,omitempty"`
+	Local        bool      `json:"local"`
+	ResolvedAt   time.Time `json:"resolved_at"`
+	ResolvedBy   string    `json:"resolved_by,omitempty"`
+	Remark       string    `json:"remark,omitempty"`
+}
+
+// webhookAlert is the data of alert events.
+type webhookAlert struct {
+	Title      string `json:"title"`
+	Message    string `json:"message,omitempty"`
+	RetryCount int    `json:"retry_count,omitempty"`
+	Area       string `json:"area,omitempty"`       // outage.detected only
+	Complaints int    `json:"complaints,omitempty"` // outage.detected only
+}
+
+// Name implements Notifier.
+func (w *Webhook) Name() string { return "webhook" }
+
+// SendComplaint implements Notifier.
+func (w *Webhook) SendComplaint(c Complaint) error {
+	return w.post(ComplaintEvent(c))
+}
+
+// EditStatus implements Notifier.
+func (w *Webhook) EditStatus(s Status) error {
+	return w.post(ResolvedEvent(s))
+}
+
+// SendAlert implements Notifier.
+func (w *Webhook) SendAlert(a Alert) error {
+	return w.post(AlertEvent(a))
+}
+
+// SendFetchFailure implements FetchFailureSender.
+func (w *Webhook) SendFetchFailure(err error, attempts int, at time.Time) error {
+	return w.post(FetchFailedEvent(err, attempts, at))
+}
+
+// ComplaintEvent is the complaint.new (or complaint.reopened) event for c.
+// It, ResolvedEvent and AlertEvent build the events the webhook sink
+// posts; the dashboard's live feed streams the same ones.
+func ComplaintEvent(c Complaint) WebhookEvent {
+	if c.Reopened != nil {
+		return newEvent(EventComplaintReopened, time.Now(), c)
+	}
+	return newEvent(EventComplaintNew, time.Now(), c)
+}
+
+// ResolvedEvent is the complaint.resolved event for s.
+func ResolvedEvent(s Status) WebhookEvent {
+	if s.Time.IsZero() {
+		s.Time = time.Now()
+	}
+	return newEvent(EventComplaintResolved, s.Time, webhookResolved{
+		Number:       s.ComplaintID,
+		ConsumerName: s.ConsumerName,
+		Belt:         s.Belt,
+		Local:        s.Local,
+		ResolvedAt:   s.Time,
+		ResolvedBy:   s.ResolvedBy,
+		Remark:       s.Remark,
+	})
+}
+
+// AlertEvent is the event for a: portal.down, outage.detected and so on,
+// by its Kind. Critical alerts (a failed login, a changed dashboard) are
+// service.critical; fetch.failed comes from FetchFailedEvent instead, for
+// every failed cycle rather than the first of an ongoing failure.
+func AlertEvent(a Alert) WebhookEvent {
+	if a.Time.IsZero() {
+		a.Time = time.Now()
+	}
+	data := webhookAlert{Title: a.Title, Message: a.Message, RetryCount: a.RetryCount}
+	event := EventServiceCritical
+	switch a.Kind {
+	case AlertPortalDown:
+		event = EventPortalDown
+	case AlertPortalUp:
+		event = EventPortalUp
+	case AlertRecovered:
+		event = EventFetchRecovered
+	case AlertOutage:
+		event = EventOutageDetected
+		if a.Outage != nil {
+			data.Area = a.Outage.Area
+			data.Complaints = len(a.Outage.Members)
+		}
+	case AlertImported:
+		event = EventComplaintsImported
+	}
+	return newEvent(event, a.Time, data)
+}
+
+// FetchFailedEvent is the fetch.failed event for a fetch cycle that gave
+// up after attempts with err.
+func FetchFailedEvent(err error, attempts int, at time.Time) WebhookEvent {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	data := webhookAlert{Title: "Fetch/Login Failure", RetryCount: attempts}
+	if err != nil {
+		data.Message = err.Error()
+	}
+	return newEvent(EventFetchFailed, at, data)
+}
+
+func newEvent(event string, at time.Time, data interface{}) WebhookEvent {
+	return WebhookEvent{ID: newEventID(), Event: event, Time: at.UTC(), Data: data}
+}
+
+// post delivers ev to every URL. A failing URL doesn't stop the rest.
+func (w *Webhook) post(ev WebhookEvent) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("encode webhook event: %w", err)
+	}
+	var errs []error
+	for _, url := range w.cfg.URLs {
+		if err := w.deliver(url, ev.Event, body); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", url, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver POSTs body to url, retrying transient failures.
+func (w *Webhook) deliver(url, event string, body []byte) error {
+	var err error
+	for attempt := 0; attempt <= w.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			w.sleep(time.Second << (attempt - 1))
+		}
+		var retry bool
+		retry, err = w.try(url, event, body)
+		if err == nil || !retry {
+			return err
+		}
+	}
+	return fmt.Errorf("giving up after %d attempts: %w", w.cfg.MaxRetries+1, err)
+}
+
+// try makes one delivery attempt and reports whether a failure is worth
+// retrying.
+func (w *Webhook) try(url, event string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "cmon-webhook/1.0")
+	req.Header.Set("X-Cmon-Event", event)
+	if w.cfg.Secret != "" {
+		req.Header.Set("X-Cmon-Signature", Sign(w.cfg.Secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("HTTP %d", resp.StatusCode)
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// Sign returns the X-Cmon-Signature value for body: "sha256=" followed by
+// the hex HMAC-SHA256 of body keyed with secret. Receivers should compute
+// the same over the raw request body and compare with hmac.Equal.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newEventID returns a random 128-bit hex ID so receivers can deduplicate
+// retried deliveries.
+func newEventID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
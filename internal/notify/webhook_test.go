@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewWebhookDisabled(t *testing.T) {
+	if w := NewWebhook(WebhookConfig{Secret: "s"}); w != nil {
+		t.Error("no URLs should disable the webhook")
+	}
+}
+
+func TestWebhookSignsAndEncodesEvents(t *testing.T) {
+	var gotBody []byte
+	var gotHeader http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotHeader = r.Header
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(WebhookConfig{URLs: []string{srv.URL}, Secret: "topsecret"})
+	at := time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)
+	if err := w.EditStatus(Status{ComplaintID: "12345", ConsumerName: "Ramesh", Belt: "Buhari", Time: at}); err != nil {
+		t.Fatalf("EditStatus: %v", err)
+	}
+
+	if got := gotHeader.Get("X-Cmon-Event"); got != EventComplaintResolved {
+		t.Errorf("X-Cmon-Event: got %q", got)
+	}
+	if got, want := gotHeader.Get("X-Cmon-Signature"), Sign("topsecret", gotBody); got != want {
+		t.Errorf("signature: got %q, want %q", got, want)
+	}
+	var ev struct {
+		ID    string          `json:"id"`
+		Event string          `json:"event"`
+		Time  time.Time       `json:"time"`
+		Data  webhookResolved `json:"data"`
+	}
+	if err := json.Unmarshal(gotBody, &ev); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if ev.ID == "" || ev.Event != EventComplaintResolved || !ev.Time.Equal(at) {
+		t.Errorf("envelope: %+v", ev)
+	}
+	if ev.Data.Number != "12345" || ev.Data.ConsumerName != "Ramesh" || !ev.Data.ResolvedAt.Equal(at) {
+		t.Errorf("data: %+v", ev.Data)
+	}
+}
+
+func TestWebhookAlertEvents(t *testing.T) {
+	var events []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		events = append(events, r.Header.Get("X-Cmon-Event"))
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(WebhookConfig{URLs: []string{srv.URL}})
+	for _, kind := range []AlertKind{AlertCritical, AlertPortalDown, AlertPortalUp, AlertOutage, AlertRecovered, AlertImported} {
+		if err := w.SendAlert(Alert{Kind: kind, Title: "t"}); err != nil {
+			t.Fatalf("SendAlert(%v): %v", kind, err)
+		}
+	}
+	want := []string{EventServiceCritical, EventPortalDown, EventPortalUp, EventOutageDetected, EventFetchRecovered, EventComplaintsImported}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Errorf("events: got %v, want %v", events, want)
+	}
+}
+
+func TestWebhookComplaintEvents(t *testing.T) {
+	var events []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		events = append(events, r.Header.Get("X-Cmon-Event"))
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(WebhookConfig{URLs: []string{srv.URL}})
+	reopened := Complaint{Number: "2", Reopened: &Reopen{ResolvedAt: time.Now()}}
+	for _, c := range []Complaint{{Number: "1"}, reopened} {
+		if err := w.SendComplaint(c); err != nil {
+			t.Fatalf("SendComplaint(%s): %v", c.Number, err)
+		}
+	}
+	want := []string{EventComplaintNew, EventComplaintReopened}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Errorf("events: got %v, want %v", events, want)
+	}
+	if got := reopened.Headline(); got != "♻️ RE-OPENED: Complaint 2" {
+		t.Errorf("Headline: got %q", got)
+	}
+}
+
+func TestWebhookRetriesTransientFailures(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(WebhookConfig{URLs: []string{srv.URL}, MaxRetries: 3})
+	var waits []time.Duration
+	w.sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	if err := w.SendComplaint(Complaint{Number: "1"}); err != nil {
+		t.Fatalf("SendComplaint: %v", err)
+	}
+	if hits != 3 {
+		t.Errorf("hits: got %d, want 3", hits)
+	}
+	if len(waits) != 2 || waits[0] != time.Second || waits[1] != 2*time.Second {
+		t.Errorf("backoff: got %v, want [1s 2s]", waits)
+	}
+}
+
+func TestWebhookDoesNotRetryClientErrors(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(WebhookConfig{URLs: []string{srv.URL}, MaxRetries: 3})
+	w.sleep = func(time.Duration) {}
+
+	err := w.SendComplaint(Complaint{Number: "1"})
+	if err == nil || !strings.Contains(err.Error(), "HTTP 400") {
+		t.Errorf("err: got %v, want HTTP 400", err)
+	}
+	if hits != 1 {
+		t.Errorf("hits: got %d, want 1", hits)
+	}
+}
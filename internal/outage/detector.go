@@ -0,0 +1,237 @@
+// Package outage groups new complaints by area to spot likely supply
+// outages. A burst of "no light" complaints from one village usually means a
+// feeder or transformer fault, and operators would rather see one
+// "⚡ Possible outage in X: 7 complaints" alert than seven separate messages.
+//
+// Complaints whose locality maps to a feeder (see package feeder) are
+// grouped by feeder instead, since one feeder fault reaches several
+// villages at once.
+//
+// The Detector keeps a sliding window of recent complaints per area in
+// memory, so cluster state carries across fetch cycles (but not restarts —
+// a restart simply starts a fresh window).
+package outage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Observation is one newly seen complaint fed to the Detector.
+type Observation struct {
+	ComplaintID  string
+	Area         string // village if resolved, otherwise the portal's area text
+	Belt         string
+	Feeder       string // empty when the locality is not mapped
+	ConsumerName string
+	Description  string
+}
+
+// Member is a complaint that belongs to a cluster.
+type Member struct {
+	ComplaintID  string
+	Area         string
+	ConsumerName string
+	Description  string
+	SeenAt       time.Time
+}
+
+// Cluster is an area or feeder that crossed the threshold within the
+// window. Area is the first member's area; Feeder is set when the cluster
+// is a feeder's.
+type Cluster struct {
+	Area    string
+	Belt    string
+	Feeder  string
+	Window  time.Duration
+	Members []Member // oldest first
+}
+
+// Result is what a single Observe call produced.
+//
+// Alerts lists clusters that crossed the threshold during this call — each
+// cluster alerts once until its window drains. Clustered holds every
+// complaint ID from this call that belongs to an alerted cluster (new or
+// already active), so callers can suppress individual notifications.
+type Result struct {
+	Alerts    []Cluster
+	Clustered map[string]bool
+}
+
+type areaState struct {
+	area    string
+	belt    string
+	feeder  string
+	members []Member
+	alerted bool
+}
+
+// Detector tracks per-area complaint windows. Safe for concurrent use.
+type Detector struct {
+	threshold int
+	window    time.Duration
+	now       func() time.Time
+
+	mu    sync.Mutex
+	areas map[string]*areaState
+}
+
+// NewDetector returns a Detector that alerts when threshold complaints from
+// one area arrive within window. Returns nil when threshold <= 0 (clustering
+// disabled); a nil Detector's Observe is a no-op.
+func NewDetector(threshold int, window time.Duration) *Detector {
+	if threshold <= 0 || window <= 0 {
+		return nil
+	}
+	return &Detector{
+		threshold: threshold,
+		window:    window,
+		now:       time.Now,
+		areas:     make(map[string]*areaState),
+	}
+}
+
+// Observe records a batch of new complaints and reports any clusters that
+// crossed the threshold. The whole batch is added before thresholds are
+// checked so one fetch cycle produces at most one alert per area, listing
+// every complaint in it.
+func (d *Detector) Observe(batch []Observation) Result {
+	res := Result{Clustered: make(map[string]bool)}
+	if d == nil || len(batch) == 0 {
+		return res
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	d.pruneLocked(now)
+
+	touched := make(map[string][]string) // key → complaint IDs from this batch
+	var order []string
+	for _, o := range batch {
+		area, feeder := strings.TrimSpace(o.Area), strings.TrimSpace(o.Feeder)
+		var key string
+		switch {
+		case feeder != "":
+			key = feederKey(feeder)
+		case area != "":
+			key = areaKey(o.Belt, area)
+		default:
+			continue
+		}
+		st, ok := d.areas[key]
+		if !ok {
+			st = &areaState{area: area, belt: o.Belt, feeder: feeder}
+			d.areas[key] = st
+		}
+		st.members = append(st.members, Member{
+			ComplaintID:  o.ComplaintID,
+			Area:         area,
+			ConsumerName: o.ConsumerName,
+			Description:  o.Description,
+			SeenAt:       now,
+		})
+		if _, seen := touched[key]; !seen {
+			order = append(order, key)
+		}
+		touched[key] = append(touched[key], o.ComplaintID)
+	}
+
+	for _, key := range order {
+		st := d.areas[key]
+		if !st.alerted && len(st.members) >= d.threshold {
+			st.alerted = true
+			res.Alerts = append(res.Alerts, Cluster{
+				Area:    st.area,
+				Belt:    st.belt,
+				Feeder:  st.feeder,
+				Window:  d.window,
+				Members: append([]Member(nil), st.members...),
+			})
+		}
+		if st.alerted {
+			for _, id := range touched[key] {
+				res.Clustered[id] = true
+			}
+		}
+	}
+
+	sort.SliceStable(res.Alerts, func(i, j int) bool {
+		return len(res.Alerts[i].Members) > len(res.Alerts[j].Members)
+	})
+	return res
+}
+
+// pruneLocked drops members older than the window. An area whose window
+// drains completely is forgotten, which also re-arms its alert.
+func (d *Detector) pruneLocked(now time.Time) {
+	cutoff := now.Add(-d.window)
+	for key, st := range d.areas {
+		kept := st.members[:0]
+		for _, m := range st.members {
+			if m.SeenAt.After(cutoff) {
+				kept = append(kept, m)
+			}
+		}
+		if len(kept) == 0 {
+			delete(d.areas, key)
+			continue
+		}
+		st.members = kept
+	}
+}
+
+// areaKey normalises belt + area so "Tokarva" and "TOKARVA " cluster
+// together, while same-named villages in different belts stay separate.
+func areaKey(belt, area string) string {
+	return strings.ToLower(strings.TrimSpace(belt)) + "|" + strings.ToLower(strings.Join(strings.Fields(area), " "))
+}
+
+// feederKey keys a feeder's cluster apart from every areaKey: belt names
+// never start with a NUL byte.
+func feederKey(feeder string) string {
+	return "\x00" + strings.ToLower(feeder)
+}
+
+// Title returns the alert headline, e.g. "⚡ Possible outage in Tokarva: 7
+// complaints", or "⚡ Possible outage on feeder Bajipura AG: 7 complaints".
+func (c Cluster) Title() string {
+	if c.Feeder != "" {
+		return fmt.Sprintf("⚡ Possible outage on feeder %s: %d complaints", c.Feeder, len(c.Members))
+	}
+	return fmt.Sprintf("⚡ Possible outage in %s: %d complaints", c.Area, len(c.Members))
+}
+
+// Spread counts the members per area, busiest first, e.g. "Tokarva 4,
+// Titva 2" — where a feeder cluster's complaints come from.
+func (c Cluster) Spread() string {
+	type areaCount struct {
+		area  string
+		count int
+	}
+	idx := make(map[string]int)
+	var counts []areaCount
+	for _, m := range c.Members {
+		area := m.Area
+		if area == "" {
+			area = "unknown area"
+		}
+		i, ok := idx[area]
+		if !ok {
+			i = len(counts)
+			idx[area] = i
+			counts = append(counts, areaCount{area: area})
+		}
+		counts[i].count++
+	}
+	sort.SliceStable(counts, func(i, j int) bool { return counts[i].count > counts[j].count })
+	parts := make([]string, len(counts))
+	for i, ac := range counts {
+		parts[i] = fmt.Sprintf("%s %d", ac.area, ac.count)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,102 @@
+package outage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func obs(id, area string) Observation {
+	return Observation{ComplaintID: id, Area: area, Belt: "bajipura"}
+}
+
+func TestNewDetectorDisabled(t *testing.T) {
+	if d := NewDetector(0, time.Hour); d != nil {
+		t.Error("threshold 0 should disable clustering")
+	}
+	var d *Detector
+	if res := d.Observe([]Observation{obs("1", "Tokarva")}); len(res.Alerts) != 0 || len(res.Clustered) != 0 {
+		t.Errorf("nil detector should be a no-op, got %+v", res)
+	}
+}
+
+func TestObserveAlertsOnceAcrossCycles(t *testing.T) {
+	d := NewDetector(3, time.Hour)
+	now := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+
+	// Cycle 1: two complaints — under threshold.
+	res := d.Observe([]Observation{obs("1", "Tokarva"), obs("2", "tokarva ")})
+	if len(res.Alerts) != 0 {
+		t.Fatalf("cycle 1: unexpected alert %+v", res.Alerts)
+	}
+
+	// Cycle 2: third complaint crosses the threshold; other areas unaffected.
+	now = now.Add(10 * time.Minute)
+	res = d.Observe([]Observation{obs("3", "TOKARVA"), obs("4", "Valod")})
+	if len(res.Alerts) != 1 {
+		t.Fatalf("cycle 2: want 1 alert, got %d", len(res.Alerts))
+	}
+	if got := len(res.Alerts[0].Members); got != 3 {
+		t.Errorf("cycle 2: alert members = %d, want 3", got)
+	}
+	if !res.Clustered["3"] || res.Clustered["4"] {
+		t.Errorf("cycle 2: clustered = %v, want only 3", res.Clustered)
+	}
+	if want := "⚡ Possible outage in Tokarva: 3 complaints"; res.Alerts[0].Title() != want {
+		t.Errorf("title = %q, want %q", res.Alerts[0].Title(), want)
+	}
+
+	// Cycle 3: another complaint joins the active cluster — no second alert.
+	now = now.Add(10 * time.Minute)
+	res = d.Observe([]Observation{obs("5", "Tokarva")})
+	if len(res.Alerts) != 0 || !res.Clustered["5"] {
+		t.Errorf("cycle 3: want no alert and 5 clustered, got %+v", res)
+	}
+
+	// After the window drains the area re-arms.
+	now = now.Add(2 * time.Hour)
+	var batch []Observation
+	for i := 6; i < 9; i++ {
+		batch = append(batch, obs(fmt.Sprint(i), "Tokarva"))
+	}
+	if res = d.Observe(batch); len(res.Alerts) != 1 {
+		t.Errorf("after window: want a fresh alert, got %d", len(res.Alerts))
+	}
+}
+
+func TestObserveSeparatesBelts(t *testing.T) {
+	d := NewDetector(2, time.Hour)
+	res := d.Observe([]Observation{
+		{ComplaintID: "1", Area: "Umarpada", Belt: "bajipura"},
+		{ComplaintID: "2", Area: "Umarpada", Belt: "valod"},
+		{ComplaintID: "3", Area: ""},
+		{ComplaintID: "4", Area: ""},
+	})
+	if len(res.Alerts) != 0 {
+		t.Errorf("same village name in different belts (or blank area) must not cluster: %+v", res.Alerts)
+	}
+}
+
+func TestObserveGroupsByFeeder(t *testing.T) {
+	d := NewDetector(3, time.Hour)
+	res := d.Observe([]Observation{
+		{ComplaintID: "1", Area: "Tokarva", Belt: "bajipura", Feeder: "Bajipura AG"},
+		{ComplaintID: "2", Area: "Titva", Belt: "bajipura", Feeder: "Bajipura AG"},
+		{ComplaintID: "3", Area: "Tokarva", Belt: "bajipura", Feeder: "bajipura ag"},
+		{ComplaintID: "4", Area: "Tokarva", Belt: "bajipura"},
+	})
+	if len(res.Alerts) != 1 {
+		t.Fatalf("want one feeder alert, got %+v", res.Alerts)
+	}
+	c := res.Alerts[0]
+	if want := "⚡ Possible outage on feeder Bajipura AG: 3 complaints"; c.Title() != want {
+		t.Errorf("title = %q, want %q", c.Title(), want)
+	}
+	if got := c.Spread(); got != "Tokarva 2, Titva 1" {
+		t.Errorf("Spread = %q, want \"Tokarva 2, Titva 1\"", got)
+	}
+	if res.Clustered["4"] {
+		t.Error("an unmapped complaint must not join the feeder's cluster")
+	}
+}
@@ -0,0 +1,399 @@
+// Package portaltest runs a fake DGVCL complaint portal for tests.
+//
+// It serves the pages and endpoints cmon talks to — the login page with
+// its arithmetic captcha and /api/login, the paginated complaint
+// dashboard, the complaint-record JSON API and the resolve endpoint — well
+// enough for the session client, fetcher and resolve flow to run
+// end-to-end without the real portal or credentials:
+//
+//	portal := portaltest.NewServer("user", "pass")
+//	defer portal.Close()
+//	portal.AddComplaint(portaltest.Complaint{Number: "C-1", APIID: "1", Name: "Asha"})
+//	api.SetRecordEndpoint(portal.RecordURL())
+//	sc.Login(portal.LoginURL(), "user", "pass")
+//	fetcher.FetchAll(portal.DashboardURL())
+//
+// Resolving a complaint removes it from the dashboard, as on the portal;
+// its record stays available with status "Resolved" and the remark.
+package portaltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Complaint is one complaint on the fake dashboard. Number is what the
+// dashboard shows; APIID is the ID the record and resolve APIs take.
+type Complaint struct {
+	Number      string
+	APIID       string
+	ConsumerNo  string
+	Name        string
+	MobileNo    string
+	Description string
+	Location    string
+	Area        string
+	Date        string // "02/01/2006 15:04:05"
+
+	// Status is the cStatus dashboard view listing the complaint, e.g.
+	// "2" for pending; "" lists it in every view.
+	Status string
+}
+
+// Resolution is one call to the resolve endpoint.
+type Resolution struct {
+	APIID  string
+	Remark string
+}
+
+// Server is a running fake portal.
+type Server struct {
+	*httptest.Server
+
+	// PageSize is the number of complaints per dashboard page (default 10).
+	PageSize int
+
+	username, password string
+
+	mu         sync.Mutex
+	complaints []Complaint
+	resolved   []Resolution
+	closed     map[string]Complaint // resolved complaints by API ID
+	captchas   map[string]int       // csrf token → expected captcha answer
+	tokens     map[string]bool
+	logins     int
+	down       bool
+	seq        int
+}
+
+// NewServer starts a fake portal accepting username and password. The
+// caller must Close it.
+func NewServer(username, password string) *Server {
+	s := &Server{
+		PageSize: 10,
+		username: username,
+		password: password,
+		captchas: make(map[string]int),
+		closed:   make(map[string]Complaint),
+		tokens:   make(map[string]bool),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.loginPage)
+	mux.HandleFunc("/api/login", s.apiLogin)
+	mux.HandleFunc("/dashboard_complaint_list", s.dashboard)
+	mux.HandleFunc("/api/complaint-record/", s.record)
+	mux.HandleFunc("/api/complaint-assign-process", s.resolve)
+	s.Server = httptest.NewServer(s.maintenance(mux))
+	return s
+}
+
+// LoginURL is the login page (LOGIN_URL).
+func (s *Server) LoginURL() string { return s.URL + "/" }
+
+// DashboardURL is the first dashboard page (COMPLAINT_URL).
+func (s *Server) DashboardURL() string { return s.URL + "/dashboard_complaint_list" }
+
+// RecordURL is the complaint-record API prefix (DGVCL_RECORD_URL).
+func (s *Server) RecordURL() string { return s.URL + "/api/complaint-record/" }
+
+// ResolveURL is the resolve endpoint (DGVCL_RESOLVE_URL).
+func (s *Server) ResolveURL() string { return s.URL + "/api/complaint-assign-process" }
+
+// AddComplaint puts c on the dashboard, after the ones already there.
+func (s *Server) AddComplaint(c Complaint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.complaints = append(s.complaints, c)
+}
+
+// SetStatus moves the complaint with apiID to the cStatus view status, as
+// assigning it on the portal would.
+func (s *Server) SetStatus(apiID, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.complaints {
+		if s.complaints[i].APIID == apiID {
+			s.complaints[i].Status = status
+		}
+	}
+}
+
+// Resolved returns the resolve calls received, in order.
+func (s *Server) Resolved() []Resolution {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Resolution(nil), s.resolved...)
+}
+
+// Logins returns the number of successful logins.
+func (s *Server) Logins() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins
+}
+
+// ExpireSessions invalidates every issued token, so the next dashboard
+// request gets the login form and API calls get 401.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = make(map[string]bool)
+}
+
+// SetDown makes every request get the portal's 503 maintenance page until
+// it is called again with false.
+func (s *Server) SetDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func (s *Server) maintenance(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		down := s.down
+		s.mu.Unlock()
+		if down {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `<html><head><title>Be right back.</title></head><body>Be right back.</body></html>`)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorized reports whether r carries a token from a successful login.
+func (s *Server) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return token != "" && s.tokens[token]
+}
+
+func (s *Server) loginPage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	s.mu.Lock()
+	s.seq++
+	a, b := s.seq%9+1, s.seq%7+2
+	csrf := fmt.Sprintf("csrf-%d", s.seq)
+	s.captchas[csrf] = a + b
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/html")
+	fmt.Fprint(w, loginHTML(csrf, fmt.Sprintf("%d + %d", a, b)))
+}
+
+func loginHTML(csrf, captcha string) string {
+	return fmt.Sprintf(`<!doctype html><html><head>
+<meta name="csrf-token" content="%s">
+<title>DGVCL Complaint Login</title>
+</head><body>
+<form id="loginForm">
+<input id="email_or_username" name="email_or_username">
+<input type="password" name="password">
+<ul><li class="captchaList"><span>%s</span></li></ul>
+<input name="captcha">
+</form>
+</body></html>`, csrf, captcha)
+}
+
+func (s *Server) apiLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var body struct {
+		Username string      `json:"email_or_username"`
+		Password string      `json:"password"`
+		Captcha  interface{} `json:"captcha"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "invalid request"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	csrf := r.Header.Get("X-CSRF-TOKEN")
+	want, ok := s.captchas[csrf]
+	if !ok {
+		writeJSON(w, 419, map[string]string{"message": "CSRF token mismatch."})
+		return
+	}
+	delete(s.captchas, csrf)
+	if fmt.Sprint(body.Captcha) != strconv.Itoa(want) {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "Invalid captcha."})
+		return
+	}
+	if body.Username != s.username || body.Password != s.password {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "These credentials do not match our records."})
+		return
+	}
+	s.logins++
+	token := fmt.Sprintf("token-%d", s.logins)
+	s.tokens[token] = true
+	writeJSON(w, http.StatusOK, map[string]string{"token": token})
+}
+
+// dashboard serves one page of the complaint list (?page=N, 1-based),
+// filtered to the ?cStatus= view when given, or the login form when the
+// request is not logged in.
+func (s *Server) dashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	if !s.authorized(r) {
+		fmt.Fprint(w, loginHTML("", "1 + 1"))
+		return
+	}
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	status := query.Get("cStatus")
+
+	s.mu.Lock()
+	size := s.PageSize
+	if size <= 0 {
+		size = 10
+	}
+	var listed []Complaint
+	for _, c := range s.complaints {
+		if status == "" || c.Status == "" || c.Status == status {
+			listed = append(listed, c)
+		}
+	}
+	s.mu.Unlock()
+	start := (page - 1) * size
+	end := start + size
+	var rows []Complaint
+	if start < len(listed) {
+		rows = listed[start:min(end, len(listed))]
+	}
+	more := end < len(listed)
+
+	var b strings.Builder
+	b.WriteString(`<html><head><title>Complaint List</title></head><body>
+<table id="dataTable"><thead><tr><th>Complaint No</th><th>Consumer</th><th>Date</th></tr></thead><tbody>
+`)
+	for _, c := range rows {
+		fmt.Fprintf(&b, "<tr><td><a href=\"javascript:void(0)\" onclick=\"openModelData(%s)\">%s</a></td><td>%s</td><td>%s</td></tr>\n",
+			html.EscapeString(c.APIID), html.EscapeString(c.Number), html.EscapeString(c.Name), html.EscapeString(c.Date))
+	}
+	b.WriteString("</tbody></table>\n<ul class=\"pagination\">\n")
+	if more {
+		query.Set("page", strconv.Itoa(page+1))
+		fmt.Fprintf(&b, "<li class=\"page-item\"><a class=\"page-link\" href=\"%s?%s\" rel=\"next\">›</a></li>\n", s.DashboardURL(), html.EscapeString(query.Encode()))
+	} else {
+		b.WriteString("<li class=\"page-item disabled\"><a class=\"page-link\">›</a></li>\n")
+	}
+	b.WriteString("</ul></body></html>")
+	fmt.Fprint(w, b.String())
+}
+
+func (s *Server) record(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "Unauthenticated."})
+		return
+	}
+	apiID := strings.TrimPrefix(r.URL.Path, "/api/complaint-record/")
+	c, status, ok := s.find(apiID)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Complaint not found."})
+		return
+	}
+	history := []map[string]interface{}{{"created_at": c.Date, "status": "Registered"}}
+	for _, res := range s.Resolved() {
+		if res.APIID == apiID {
+			history = append(history, map[string]interface{}{"status": "Resolved", "remark": res.Remark, "resolved_by": s.username})
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"complaintdetail": map[string]interface{}{
+			"complain_no":      c.Number,
+			"consumer_no":      c.ConsumerNo,
+			"complainant_name": c.Name,
+			"mobile_no":        c.MobileNo,
+			"description":      c.Description,
+			"complain_date":    c.Date,
+			"exact_location":   c.Location,
+			"area":             c.Area,
+			"complain_status":  status,
+		},
+		"complainthistory": history,
+	})
+}
+
+// resolve answers like the portal: plain text, with failures prefixed
+// "ERROR:". A "pending" assignment undoes a resolution.
+func (s *Server) resolve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "Unauthenticated."})
+		return
+	}
+	apiID := r.PostFormValue("complaint_id")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.PostFormValue("complaint_AsignType") {
+	case "resolved":
+	case "pending":
+		// Undoing a resolution puts the complaint back on the dashboard.
+		if c, ok := s.closed[apiID]; ok {
+			delete(s.closed, apiID)
+			s.complaints = append(s.complaints, c)
+			fmt.Fprint(w, "Complaint moved to pending")
+			return
+		}
+		fmt.Fprint(w, "ERROR: complaint not resolved")
+		return
+	default:
+		fmt.Fprint(w, "ERROR: unsupported assign type")
+		return
+	}
+	for i, c := range s.complaints {
+		if c.APIID == apiID {
+			s.complaints = append(s.complaints[:i], s.complaints[i+1:]...)
+			s.closed[apiID] = c
+			s.resolved = append(s.resolved, Resolution{APIID: apiID, Remark: r.PostFormValue("remark")})
+			fmt.Fprint(w, "Complaint resolved successfully")
+			return
+		}
+	}
+	fmt.Fprint(w, "ERROR: complaint not found")
+}
+
+// find looks apiID up on the dashboard, then among resolved complaints,
+// and returns it with its record status.
+func (s *Server) find(apiID string) (Complaint, string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.complaints {
+		if c.APIID == apiID {
+			return c, "Registered", true
+		}
+	}
+	if c, ok := s.closed[apiID]; ok {
+		return c, "Resolved", true
+	}
+	return Complaint{}, "", false
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,82 @@
+// Package proxy routes cmon's outbound traffic through one proxy.
+//
+// Every HTTP transport in cmon — the portal session, Telegram, Gemini,
+// WhatsApp and the notification channels — asks Func which proxy to use.
+// By default that is the standard HTTP_PROXY / HTTPS_PROXY / NO_PROXY
+// environment, as for any Go program; Setup replaces it with PROXY_URL
+// (http://, https:// or socks5://) and the PROXY_BYPASS hosts that go
+// direct. Requests to localhost are never proxied.
+package proxy
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+var (
+	mu      sync.RWMutex
+	current = httpproxy.FromEnvironment().ProxyFunc()
+)
+
+// Func is an http.Transport Proxy function using the configured proxy.
+func Func(req *http.Request) (*url.URL, error) {
+	mu.RLock()
+	f := current
+	mu.RUnlock()
+	return f(req.URL)
+}
+
+// Setup installs rawURL as the proxy for every destination except those
+// matching bypass (NO_PROXY syntax: "host", ".domain", "10.0.0.0/8",
+// "host:port" or "*"). An empty rawURL keeps the environment settings.
+// It also points http.DefaultTransport at Func, so clients built without a
+// transport of their own follow it. Call once at startup, before any
+// client is built.
+func Setup(rawURL string, bypass []string) error {
+	if p, ok := http.DefaultTransport.(*http.Transport); ok {
+		p.Proxy = Func
+	}
+	if rawURL == "" {
+		return nil
+	}
+	u, err := Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	f := (&httpproxy.Config{
+		HTTPProxy:  u.String(),
+		HTTPSProxy: u.String(),
+		NoProxy:    strings.Join(bypass, ","),
+	}).ProxyFunc()
+	mu.Lock()
+	current = f
+	mu.Unlock()
+
+	if len(bypass) > 0 {
+		log.Printf("🌐 Outbound traffic goes through %s (direct: %s)", u.Redacted(), strings.Join(bypass, ", "))
+	} else {
+		log.Printf("🌐 Outbound traffic goes through %s", u.Redacted())
+	}
+	return nil
+}
+
+// Parse checks a PROXY_URL value: an http, https or socks5 URL with a host.
+func Parse(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		// Not echoed: it may carry proxy credentials.
+		return nil, fmt.Errorf("invalid proxy URL (want scheme://[user:pass@]host:port)")
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return u, nil
+	}
+	return nil, fmt.Errorf("unsupported proxy scheme %q (want http, https or socks5)", u.Scheme)
+}
@@ -0,0 +1,59 @@
+package proxy
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSetupRoutesEverythingButBypassedHosts(t *testing.T) {
+	prev := current
+	prevDefault := http.DefaultTransport.(*http.Transport).Proxy
+	t.Cleanup(func() {
+		current = prev
+		http.DefaultTransport.(*http.Transport).Proxy = prevDefault
+	})
+
+	if err := Setup("socks5://user:pw@proxy.office:1080", []string{".internal.example", "10.0.0.0/8"}); err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+
+	cases := map[string]string{
+		"https://complaint.dgvcl.com/":        "socks5://user:pw@proxy.office:1080",
+		"https://api.telegram.org/botX/getMe": "socks5://user:pw@proxy.office:1080",
+		"http://hooks.internal.example/cmon":  "",
+		"http://10.1.2.3:8080/ping":           "",
+		"http://localhost:8080/health":        "",
+	}
+	for target, want := range cases {
+		req, _ := http.NewRequest(http.MethodGet, target, nil)
+		got, err := Func(req)
+		if err != nil {
+			t.Fatalf("Func(%s): %v", target, err)
+		}
+		gotStr := ""
+		if got != nil {
+			gotStr = got.String()
+		}
+		if gotStr != want {
+			t.Errorf("%s: proxy %q, want %q", target, gotStr, want)
+		}
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://generativelanguage.googleapis.com/", nil)
+	if got, _ := http.DefaultTransport.(*http.Transport).Proxy(req); got == nil || got.Host != "proxy.office:1080" {
+		t.Errorf("default transport not routed through the proxy: %v", got)
+	}
+}
+
+func TestParseRejectsUnsupportedSchemes(t *testing.T) {
+	for _, raw := range []string{"http://proxy:3128", "https://proxy:443", "socks5://proxy:1080"} {
+		if _, err := Parse(raw); err != nil {
+			t.Errorf("Parse(%q): %v", raw, err)
+		}
+	}
+	for _, raw := range []string{"ftp://proxy:21", "proxy:3128", "socks5://", ":::"} {
+		if _, err := Parse(raw); err == nil {
+			t.Errorf("Parse(%q) should fail", raw)
+		}
+	}
+}
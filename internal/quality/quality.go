@@ -0,0 +1,179 @@
+// Package quality flags complaints whose intake data is clearly unusable —
+// no mobile number to call back, no location to send a lineman to. Field
+// staff cannot fix these; the filing office can, so the checks feed both a
+// visual flag on each notification and a daily data-quality section in the
+// scheduled report.
+package quality
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Issue is a stable code for one intake problem. Codes are persisted (comma
+// joined) so they must not be renamed; add new ones instead.
+type Issue string
+
+const (
+	MissingMobile    Issue = "missing_mobile"
+	InvalidMobile    Issue = "invalid_mobile"
+	MissingLocation  Issue = "missing_location"
+	MissingConsumer  Issue = "missing_consumer_no"
+	EmptyDescription Issue = "empty_description"
+)
+
+// labels are the human-readable forms shown to operators.
+var labels = map[Issue]string{
+	MissingMobile:    "missing mobile number",
+	InvalidMobile:    "invalid mobile number",
+	MissingLocation:  "empty location",
+	MissingConsumer:  "missing consumer number",
+	EmptyDescription: "empty description",
+}
+
+// Label returns the operator-facing text for an issue code.
+func (i Issue) Label() string {
+	if l, ok := labels[i]; ok {
+		return l
+	}
+	return string(i)
+}
+
+// Input is the subset of complaint fields the checks look at. Values are
+// raw strings as returned by the portal.
+type Input struct {
+	ConsumerNo    string
+	MobileNo      string
+	ExactLocation string
+	Area          string
+	Description   string
+}
+
+// Check returns the issues found in in, in a fixed order. Nil means the
+// complaint looks fine.
+func Check(in Input) []Issue {
+	var issues []Issue
+
+	switch mobile := digitsOnly(in.MobileNo); {
+	case isBlank(in.MobileNo):
+		issues = append(issues, MissingMobile)
+	case !validIndianMobile(mobile):
+		issues = append(issues, InvalidMobile)
+	}
+	if isBlank(in.ExactLocation) && isBlank(in.Area) {
+		issues = append(issues, MissingLocation)
+	}
+	if isBlank(in.ConsumerNo) {
+		issues = append(issues, MissingConsumer)
+	}
+	if isBlank(in.Description) {
+		issues = append(issues, EmptyDescription)
+	}
+	return issues
+}
+
+// Summary joins issue labels for display: "missing mobile number, empty location".
+func Summary(issues []Issue) string {
+	parts := make([]string, len(issues))
+	for i, is := range issues {
+		parts[i] = is.Label()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Encode joins issue codes for storage.
+func Encode(issues []Issue) string {
+	parts := make([]string, len(issues))
+	for i, is := range issues {
+		parts[i] = string(is)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Decode splits a stored issue list back into codes.
+func Decode(s string) []Issue {
+	var out []Issue
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, Issue(p))
+		}
+	}
+	return out
+}
+
+// Flagged is one complaint listed in the daily data-quality section.
+type Flagged struct {
+	ComplaintID  string
+	ConsumerName string
+	Belt         string
+	Issues       []Issue
+}
+
+// ReportLines renders the data-quality section as plain text lines (header
+// first). Returns nil when nothing was flagged so callers can skip sending.
+// Counts per issue come first so the office sees the pattern, then the
+// individual complaints to correct.
+func ReportLines(flagged []Flagged) []string {
+	if len(flagged) == 0 {
+		return nil
+	}
+
+	counts := make(map[Issue]int)
+	var order []Issue
+	for _, f := range flagged {
+		for _, is := range f.Issues {
+			if counts[is] == 0 {
+				order = append(order, is)
+			}
+			counts[is]++
+		}
+	}
+
+	lines := []string{fmt.Sprintf("🧾 Data quality — %d complaint(s) with intake issues today", len(flagged))}
+	for _, is := range order {
+		lines = append(lines, fmt.Sprintf("• %s: %d", is.Label(), counts[is]))
+	}
+	lines = append(lines, "")
+	for _, f := range flagged {
+		line := f.ComplaintID
+		if f.ConsumerName != "" {
+			line += " — " + f.ConsumerName
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", line, Summary(f.Issues)))
+	}
+	return lines
+}
+
+func isBlank(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "" || s == "-" || strings.EqualFold(s, "null") || strings.EqualFold(s, "na") || strings.EqualFold(s, "n/a")
+}
+
+func digitsOnly(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// validIndianMobile accepts 10-digit numbers starting 6-9, optionally with a
+// leading 0 or 91 country code.
+func validIndianMobile(d string) bool {
+	switch {
+	case len(d) == 12 && strings.HasPrefix(d, "91"):
+		d = d[2:]
+	case len(d) == 11 && strings.HasPrefix(d, "0"):
+		d = d[1:]
+	}
+	if len(d) != 10 {
+		return false
+	}
+	if d[0] < '6' {
+		return false
+	}
+	return strings.Count(d, d[:1]) != 10 // 0000000000 / 9999999999 placeholders
+}
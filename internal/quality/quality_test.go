@@ -0,0 +1,67 @@
+package quality
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	good := Input{ConsumerNo: "12345", MobileNo: "9876543210", ExactLocation: "Near temple", Area: "Tokarva", Description: "No supply"}
+
+	cases := []struct {
+		name string
+		edit func(*Input)
+		want []Issue
+	}{
+		{"clean", func(*Input) {}, nil},
+		{"with country code", func(in *Input) { in.MobileNo = "+91 98765 43210" }, nil},
+		{"leading zero", func(in *Input) { in.MobileNo = "09876543210" }, nil},
+		{"missing mobile", func(in *Input) { in.MobileNo = "  " }, []Issue{MissingMobile}},
+		{"null mobile", func(in *Input) { in.MobileNo = "null" }, []Issue{MissingMobile}},
+		{"short mobile", func(in *Input) { in.MobileNo = "98765" }, []Issue{InvalidMobile}},
+		{"landline-like", func(in *Input) { in.MobileNo = "2612345678" }, []Issue{InvalidMobile}},
+		{"placeholder mobile", func(in *Input) { in.MobileNo = "9999999999" }, []Issue{InvalidMobile}},
+		{"area only is fine", func(in *Input) { in.ExactLocation = "" }, nil},
+		{"no location", func(in *Input) { in.ExactLocation, in.Area = "", "-" }, []Issue{MissingLocation}},
+		{"several", func(in *Input) { in.MobileNo, in.ConsumerNo, in.Description = "", "", "" }, []Issue{MissingMobile, MissingConsumer, EmptyDescription}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := good
+			tc.edit(&in)
+			if got := Check(in); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Check = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	issues := []Issue{MissingMobile, MissingLocation}
+	if got := Decode(Encode(issues)); !reflect.DeepEqual(got, issues) {
+		t.Errorf("round trip = %v, want %v", got, issues)
+	}
+	if got := Decode(""); got != nil {
+		t.Errorf("Decode(\"\") = %v, want nil", got)
+	}
+	if got := Summary(issues); got != "missing mobile number, empty location" {
+		t.Errorf("Summary = %q", got)
+	}
+}
+
+func TestReportLines(t *testing.T) {
+	if ReportLines(nil) != nil {
+		t.Error("empty report should be nil")
+	}
+	lines := ReportLines([]Flagged{
+		{ComplaintID: "1", ConsumerName: "Asha", Issues: []Issue{MissingMobile}},
+		{ComplaintID: "2", Issues: []Issue{MissingMobile, MissingLocation}},
+	})
+	text := strings.Join(lines, "\n")
+	for _, want := range []string{"2 complaint(s)", "missing mobile number: 2", "empty location: 1", "1 — Asha: missing mobile number"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report missing %q:\n%s", want, text)
+		}
+	}
+}
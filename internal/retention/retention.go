@@ -0,0 +1,302 @@
+// Package retention removes old resolved complaint history. Every cleanup
+// first writes the rows it is about to delete to a compressed archive (and
+// optionally uploads it), and only deletes once that archive is known good,
+// so retention never loses data.
+package retention
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cmon/internal/storage"
+)
+
+// historyFile is the JSONL member inside each archive: one Record per line.
+const historyFile = "history.jsonl"
+
+// Store is the subset of *storage.Storage cleanup needs.
+type Store interface {
+	GetResolvedHistoryBefore(cutoff time.Time) ([]storage.HistoryEntry, error)
+	DeleteHistory(complaintIDs []string) (int64, error)
+}
+
+// Uploader copies a finished archive to off-box backup storage.
+type Uploader interface {
+	Upload(ctx context.Context, name string, r io.Reader) error
+}
+
+// Config controls Run.
+type Config struct {
+	MaxAge    time.Duration // resolved history older than this is removed
+	ExportDir string        // where archives are written; created if missing
+	Upload    Uploader      // optional; when set, upload must succeed before deletion
+	// Seal, when set, encrypts consumer names before they are archived, so
+	// exports and uploads don't hold PII the database keeps sealed.
+	Seal func(string) string
+}
+
+// Result reports what one Run did. Archive is empty when nothing was due.
+type Result struct {
+	Archive  string
+	Exported int
+	Deleted  int64
+}
+
+// Record is the archived form of a history row.
+type Record struct {
+	ComplaintID  string    `json:"complaint_id"`
+	ConsumerNo   string    `json:"consumer_no,omitempty"`
+	ConsumerName string    `json:"consumer_name,omitempty"`
+	Village      string    `json:"village,omitempty"`
+	Belt         string    `json:"belt,omitempty"`
+	Category     string    `json:"category,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	ComplainDate string    `json:"complain_date,omitempty"`
+	DataIssues   string    `json:"data_issues,omitempty"`
+	FirstSeenAt  time.Time `json:"first_seen_at"`
+	ResolvedAt   time.Time `json:"resolved_at"`
+	// ResolutionSeconds is how long the complaint took to resolve.
+	ResolutionSeconds int64 `json:"resolution_seconds,omitempty"`
+	// VoiceNoteFileID and Attachments reference the resolution's evidence:
+	// a Telegram file ID and paths under the attachments directory, which
+	// retention leaves in place.
+	VoiceNoteFileID string   `json:"voice_note_file_id,omitempty"`
+	Attachments     []string `json:"attachments,omitempty"`
+	// Notes are the comments staff added while the complaint was open.
+	Notes []NoteRecord `json:"notes,omitempty"`
+}
+
+// NoteRecord is the archived form of a complaint note.
+type NoteRecord struct {
+	Author    string    `json:"author,omitempty"`
+	AuthorID  int64     `json:"author_id,omitempty"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Run exports resolved history older than cfg.MaxAge, then deletes exactly
+// the exported rows. Any failure before deletion leaves the database as it
+// was; a partial archive is removed rather than left looking complete.
+func Run(ctx context.Context, store Store, cfg Config, now time.Time) (Result, error) {
+	entries, err := store.GetResolvedHistoryBefore(now.Add(-cfg.MaxAge))
+	if err != nil {
+		return Result{}, fmt.Errorf("load expired history: %w", err)
+	}
+	if len(entries) == 0 {
+		return Result{}, nil
+	}
+
+	if err := os.MkdirAll(cfg.ExportDir, 0o755); err != nil {
+		return Result{}, fmt.Errorf("create export dir: %w", err)
+	}
+	name := fmt.Sprintf("cmon-history-%s.tar.gz", now.UTC().Format("20060102-150405"))
+	path := filepath.Join(cfg.ExportDir, name)
+	archived := entries
+	if cfg.Seal != nil {
+		archived = make([]storage.HistoryEntry, len(entries))
+		for i, e := range entries {
+			e.ConsumerName = cfg.Seal(e.ConsumerName)
+			archived[i] = e
+		}
+	}
+	if err := writeArchiveFile(path, archived, now); err != nil {
+		return Result{}, err
+	}
+	res := Result{Archive: path, Exported: len(entries)}
+
+	// Read the archive back so a truncated or corrupt file can never be the
+	// only copy of deleted rows.
+	if n, err := countArchived(path); err != nil || n != len(entries) {
+		if err == nil {
+			err = fmt.Errorf("archive holds %d records, want %d", n, len(entries))
+		}
+		return res, fmt.Errorf("verify %s: %w", path, err)
+	}
+
+	if cfg.Upload != nil {
+		f, err := os.Open(path)
+		if err != nil {
+			return res, err
+		}
+		err = cfg.Upload.Upload(ctx, name, f)
+		f.Close()
+		if err != nil {
+			return res, fmt.Errorf("upload %s: %w", name, err)
+		}
+	}
+
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ComplaintID
+	}
+	res.Deleted, err = store.DeleteHistory(ids)
+	if err != nil {
+		return res, fmt.Errorf("delete exported history: %w", err)
+	}
+	return res, nil
+}
+
+// writeArchiveFile writes the archive to a temp file next to path, syncs
+// it and renames it into place.
+func writeArchiveFile(path string, entries []storage.HistoryEntry, now time.Time) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".cmon-history-*.tmp")
+	if err != nil {
+		return fmt.Errorf("create archive: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+
+	if err := WriteArchive(tmp, entries, now); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write archive: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync archive: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// WriteArchive writes entries to w as a gzipped tar holding history.jsonl.
+func WriteArchive(w io.Writer, entries []storage.HistoryEntry, now time.Time) error {
+	var jsonl bytes.Buffer
+	enc := json.NewEncoder(&jsonl)
+	for _, e := range entries {
+		var notes []NoteRecord
+		for _, n := range e.Notes {
+			notes = append(notes, NoteRecord{Author: n.Author, AuthorID: n.AuthorID, Text: n.Text, CreatedAt: n.CreatedAt})
+		}
+		if err := enc.Encode(Record{
+			ComplaintID:       e.ComplaintID,
+			ConsumerNo:        e.ConsumerNo,
+			ConsumerName:      e.ConsumerName,
+			Village:           e.Village,
+			Belt:              e.Belt,
+			Category:          e.Category,
+			Description:       e.Description,
+			ComplainDate:      e.ComplainDate,
+			DataIssues:        e.DataIssues,
+			FirstSeenAt:       e.FirstSeenAt,
+			ResolvedAt:        e.ResolvedAt,
+			ResolutionSeconds: int64(e.ResolutionTime / time.Second),
+			VoiceNoteFileID:   e.VoiceNoteFileID,
+			Attachments:       e.Attachments,
+			Notes:             notes,
+		}); err != nil {
+			return err
+		}
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    historyFile,
+		Mode:    0o644,
+		Size:    int64(jsonl.Len()),
+		ModTime: now,
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(jsonl.Bytes()); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// countArchived returns how many records history.jsonl in the archive at
+// path decodes to.
+func countArchived(path string) (int, error) {
+	records, err := ReadArchive(path)
+	return len(records), err
+}
+
+// ReadArchive decodes the records of the archive at path, as written by
+// Run. On a decoding error it returns the records read so far.
+func ReadArchive(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s missing from archive", historyFile)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Name != historyFile {
+			continue
+		}
+		var out []Record
+		sc := bufio.NewScanner(tr)
+		sc.Buffer(make([]byte, 0, 64*1024), 16<<20)
+		for sc.Scan() {
+			var r Record
+			if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+				return out, fmt.Errorf("record %d: %w", len(out)+1, err)
+			}
+			out = append(out, r)
+		}
+		return out, sc.Err()
+	}
+}
+
+// HTTPUploader PUTs archives to URL + "/" + name, which suits WebDAV
+// servers and most object stores' pre-authorised upload endpoints.
+type HTTPUploader struct {
+	URL    string
+	Client *http.Client // nil uses a client with a 5 minute timeout
+}
+
+// Upload implements Uploader. Any non-2xx response is an error.
+func (u HTTPUploader) Upload(ctx context.Context, name string, r io.Reader) error {
+	client := u.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Minute}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, strings.TrimRight(u.URL, "/")+"/"+name, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+	// Object stores commonly refuse chunked PUTs, so send a length for files.
+	if f, ok := r.(interface{ Stat() (os.FileInfo, error) }); ok {
+		if fi, err := f.Stat(); err == nil {
+			req.ContentLength = fi.Size()
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
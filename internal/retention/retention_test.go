@@ -0,0 +1,200 @@
+package retention
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"cmon/internal/storage"
+)
+
+type fakeStore struct {
+	entries []storage.HistoryEntry
+	deleted []string
+	cutoff  time.Time
+}
+
+func (s *fakeStore) GetResolvedHistoryBefore(cutoff time.Time) ([]storage.HistoryEntry, error) {
+	s.cutoff = cutoff
+	return s.entries, nil
+}
+
+func (s *fakeStore) DeleteHistory(ids []string) (int64, error) {
+	s.deleted = append(s.deleted, ids...)
+	return int64(len(ids)), nil
+}
+
+type uploaderFunc func(ctx context.Context, name string, r io.Reader) error
+
+func (f uploaderFunc) Upload(ctx context.Context, name string, r io.Reader) error {
+	return f(ctx, name, r)
+}
+
+var now = time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+
+func sampleEntries() []storage.HistoryEntry {
+	return []storage.HistoryEntry{
+		{ComplaintID: "CMP-1", ConsumerNo: "C100", Description: "no supply", ResolvedAt: now.AddDate(0, -7, 0), Attachments: []string{"attachments/CMP-1/photo.jpg"},
+			Notes: []storage.Note{{Author: "Asha", Text: "Lineman dispatched", CreatedAt: now.AddDate(0, -7, -1)}}},
+		{ComplaintID: "CMP-2", DataIssues: "mobile_missing", ResolvedAt: now.AddDate(0, -6, 0)},
+	}
+}
+
+func readArchive(t *testing.T, path string) []Record {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != historyFile {
+		t.Fatalf("first member: %v, %v", hdr, err)
+	}
+	var out []Record
+	sc := bufio.NewScanner(tr)
+	for sc.Scan() {
+		var r Record
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func TestRunExportsThenDeletes(t *testing.T) {
+	store := &fakeStore{entries: sampleEntries()}
+	var uploaded string
+	cfg := Config{
+		MaxAge:    90 * 24 * time.Hour,
+		ExportDir: t.TempDir(),
+		Upload: uploaderFunc(func(_ context.Context, name string, _ io.Reader) error {
+			uploaded = name
+			return nil
+		}),
+	}
+
+	res, err := Run(context.Background(), store, cfg, now)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if want := now.Add(-cfg.MaxAge); !store.cutoff.Equal(want) {
+		t.Errorf("cutoff: got %v, want %v", store.cutoff, want)
+	}
+	if res.Exported != 2 || res.Deleted != 2 || len(store.deleted) != 2 {
+		t.Errorf("result %+v, deleted %v", res, store.deleted)
+	}
+	if uploaded != "cmon-history-20260310-090000.tar.gz" {
+		t.Errorf("uploaded name: got %q", uploaded)
+	}
+
+	got := readArchive(t, res.Archive)
+	if len(got) != 2 || got[0].ComplaintID != "CMP-1" || got[0].Description != "no supply" || got[1].DataIssues != "mobile_missing" ||
+		len(got[0].Attachments) != 1 || got[1].Attachments != nil ||
+		len(got[0].Notes) != 1 || got[0].Notes[0].Text != "Lineman dispatched" || got[1].Notes != nil {
+		t.Errorf("archived records: %+v", got)
+	}
+}
+
+func TestRunSealsConsumerNames(t *testing.T) {
+	entries := sampleEntries()
+	entries[0].ConsumerName = "Asha Patel"
+	store := &fakeStore{entries: entries}
+	cfg := Config{
+		MaxAge:    90 * 24 * time.Hour,
+		ExportDir: t.TempDir(),
+		Seal: func(v string) string {
+			if v == "" {
+				return v
+			}
+			return "sealed:" + v
+		},
+	}
+
+	res, err := Run(context.Background(), store, cfg, now)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	got := readArchive(t, res.Archive)
+	if len(got) != 2 || got[0].ConsumerName != "sealed:Asha Patel" || got[1].ConsumerName != "" || got[0].ConsumerNo != "C100" {
+		t.Errorf("archived records: %+v", got)
+	}
+	if entries[0].ConsumerName != "Asha Patel" {
+		t.Errorf("store entries modified: %q", entries[0].ConsumerName)
+	}
+}
+
+func TestRunKeepsHistoryWhenUploadFails(t *testing.T) {
+	store := &fakeStore{entries: sampleEntries()}
+	cfg := Config{
+		MaxAge:    24 * time.Hour,
+		ExportDir: t.TempDir(),
+		Upload: uploaderFunc(func(context.Context, string, io.Reader) error {
+			return errors.New("bucket unreachable")
+		}),
+	}
+
+	if _, err := Run(context.Background(), store, cfg, now); err == nil {
+		t.Fatal("want upload error")
+	}
+	if len(store.deleted) != 0 {
+		t.Errorf("history deleted despite failed upload: %v", store.deleted)
+	}
+}
+
+func TestRunNothingDue(t *testing.T) {
+	dir := t.TempDir()
+	res, err := Run(context.Background(), &fakeStore{}, Config{MaxAge: time.Hour, ExportDir: dir}, now)
+	if err != nil || res.Archive != "" {
+		t.Fatalf("got %+v, %v; want no archive", res, err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("empty run wrote files: %v", files)
+	}
+}
+
+func TestHTTPUploaderPuts(t *testing.T) {
+	var method, path string
+	var length int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, length = r.Method, r.URL.Path, r.ContentLength
+	}))
+	defer srv.Close()
+
+	f, err := os.CreateTemp(t.TempDir(), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("archive")
+	f.Seek(0, io.SeekStart)
+	defer f.Close()
+
+	if err := (HTTPUploader{URL: srv.URL + "/backups/"}).Upload(context.Background(), "x.tar.gz", f); err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if method != http.MethodPut || path != "/backups/x.tar.gz" || length != 7 {
+		t.Errorf("got %s %s (length %d)", method, path, length)
+	}
+
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "denied", http.StatusForbidden)
+	})
+	if err := (HTTPUploader{URL: srv.URL}).Upload(context.Background(), "x.tar.gz", f); err == nil {
+		t.Error("403 should be an error")
+	}
+}
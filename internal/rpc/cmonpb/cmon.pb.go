@@ -0,0 +1,1011 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: internal/rpc/cmonpb/cmon.proto
+
+package cmonpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Alert_Kind int32
+
+const (
+	Alert_KIND_UNSPECIFIED Alert_Kind = 0
+	Alert_KIND_CRITICAL    Alert_Kind = 1
+	Alert_KIND_PORTAL_DOWN Alert_Kind = 2
+	Alert_KIND_PORTAL_UP   Alert_Kind = 3
+	Alert_KIND_OUTAGE      Alert_Kind = 4
+	Alert_KIND_RECOVERED   Alert_Kind = 5
+	Alert_KIND_IMPORTED    Alert_Kind = 6
+)
+
+// Enum value maps for Alert_Kind.
+var (
+	Alert_Kind_name = map[int32]string{
+		0: "KIND_UNSPECIFIED",
+		1: "KIND_CRITICAL",
+		2: "KIND_PORTAL_DOWN",
+		3: "KIND_PORTAL_UP",
+		4: "KIND_OUTAGE",
+		5: "KIND_RECOVERED",
+		6: "KIND_IMPORTED",
+	}
+	Alert_Kind_value = map[string]int32{
+		"KIND_UNSPECIFIED": 0,
+		"KIND_CRITICAL":    1,
+		"KIND_PORTAL_DOWN": 2,
+		"KIND_PORTAL_UP":   3,
+		"KIND_OUTAGE":      4,
+		"KIND_RECOVERED":   5,
+		"KIND_IMPORTED":    6,
+	}
+)
+
+func (x Alert_Kind) Enum() *Alert_Kind {
+	p := new(Alert_Kind)
+	*p = x
+	return p
+}
+
+func (x Alert_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Alert_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_internal_rpc_cmonpb_cmon_proto_enumTypes[0].Descriptor()
+}
+
+func (Alert_Kind) Type() protoreflect.EnumType {
+	return &file_internal_rpc_cmonpb_cmon_proto_enumTypes[0]
+}
+
+func (x Alert_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Alert_Kind.Descriptor instead.
+func (Alert_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_internal_rpc_cmonpb_cmon_proto_rawDescGZIP(), []int{11, 0}
+}
+
+type Complaint struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ComplaintId  string                 `protobuf:"bytes,1,opt,name=complaint_id,json=complaintId,proto3" json:"complaint_id,omitempty"`
+	ApiId        string                 `protobuf:"bytes,2,opt,name=api_id,json=apiId,proto3" json:"api_id,omitempty"`
+	ConsumerName string                 `protobuf:"bytes,3,opt,name=consumer_name,json=consumerName,proto3" json:"consumer_name,omitempty"`
+	ConsumerNo   string                 `protobuf:"bytes,4,opt,name=consumer_no,json=consumerNo,proto3" json:"consumer_no,omitempty"`
+	MobileNo     string                 `protobuf:"bytes,5,opt,name=mobile_no,json=mobileNo,proto3" json:"mobile_no,omitempty"`
+	Village      string                 `protobuf:"bytes,6,opt,name=village,proto3" json:"village,omitempty"`
+	Belt         string                 `protobuf:"bytes,7,opt,name=belt,proto3" json:"belt,omitempty"`
+	Address      string                 `protobuf:"bytes,8,opt,name=address,proto3" json:"address,omitempty"`
+	Area         string                 `protobuf:"bytes,9,opt,name=area,proto3" json:"area,omitempty"`
+	Description  string                 `protobuf:"bytes,10,opt,name=description,proto3" json:"description,omitempty"`
+	// As shown on the portal, e.g. "01/03/2026 10:00:00".
+	ComplainDate string `protobuf:"bytes,11,opt,name=complain_date,json=complainDate,proto3" json:"complain_date,omitempty"`
+	// When cmon first saw the complaint; unset when unknown.
+	FirstSeenAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=first_seen_at,json=firstSeenAt,proto3" json:"first_seen_at,omitempty"`
+	// Google Maps link; empty when geocoding is off.
+	MapsUrl string `protobuf:"bytes,13,opt,name=maps_url,json=mapsUrl,proto3" json:"maps_url,omitempty"`
+	// Set when the complaint was resolved before and has reappeared.
+	Reopened      bool `protobuf:"varint,14,opt,name=reopened,proto3" json:"reopened,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Complaint) Reset() {
+	*x = Complaint{}
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Complaint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Complaint) ProtoMessage() {}
+
+func (x *Complaint) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Complaint.ProtoReflect.Descriptor instead.
+func (*Complaint) Descriptor() ([]byte, []int) {
+	return file_internal_rpc_cmonpb_cmon_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Complaint) GetComplaintId() string {
+	if x != nil {
+		return x.ComplaintId
+	}
+	return ""
+}
+
+func (x *Complaint) GetApiId() string {
+	if x != nil {
+		return x.ApiId
+	}
+	return ""
+}
+
+func (x *Complaint) GetConsumerName() string {
+	if x != nil {
+		return x.ConsumerName
+	}
+	return ""
+}
+
+func (x *Complaint) GetConsumerNo() string {
+	if x != nil {
+		return x.ConsumerNo
+	}
+	return ""
+}
+
+func (x *Complaint) GetMobileNo() string {
+	if x != nil {
+		return x.MobileNo
+	}
+	return ""
+}
+
+func (x *Complaint) GetVillage() string {
+	if x != nil {
+		return x.Village
+	}
+	return ""
+}
+
+func (x *Complaint) GetBelt() string {
+	if x != nil {
+		return x.Belt
+	}
+	return ""
+}
+
+func (x *Complaint) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Complaint) GetArea() string {
+	if x != nil {
+		return x.Area
+	}
+	return ""
+}
+
+func (x *Complaint) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Complaint) GetComplainDate() string {
+	if x != nil {
+		return x.ComplainDate
+	}
+	return ""
+}
+
+func (x *Complaint) GetFirstSeenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstSeenAt
+	}
+	return nil
+}
+
+func (x *Complaint) GetMapsUrl() string {
+	if x != nil {
+		return x.MapsUrl
+	}
+	return ""
+}
+
+func (x *Complaint) GetReopened() bool {
+	if x != nil {
+		return x.Reopened
+	}
+	return false
+}
+
+type ListPendingRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only complaints of this belt (case-insensitive); empty for all.
+	Belt          string `protobuf:"bytes,1,opt,name=belt,proto3" json:"belt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingRequest) Reset() {
+	*x = ListPendingRequest{}
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingRequest) ProtoMessage() {}
+
+func (x *ListPendingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingRequest.ProtoReflect.Descriptor instead.
+func (*ListPendingRequest) Descriptor() ([]byte, []int) {
+	return file_internal_rpc_cmonpb_cmon_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListPendingRequest) GetBelt() string {
+	if x != nil {
+		return x.Belt
+	}
+	return ""
+}
+
+type ListPendingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Complaints    []*Complaint           `protobuf:"bytes,1,rep,name=complaints,proto3" json:"complaints,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingResponse) Reset() {
+	*x = ListPendingResponse{}
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingResponse) ProtoMessage() {}
+
+func (x *ListPendingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingResponse.ProtoReflect.Descriptor instead.
+func (*ListPendingResponse) Descriptor() ([]byte, []int) {
+	return file_internal_rpc_cmonpb_cmon_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListPendingResponse) GetComplaints() []*Complaint {
+	if x != nil {
+		return x.Complaints
+	}
+	return nil
+}
+
+type ResolveComplaintRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ComplaintId string                 `protobuf:"bytes,1,opt,name=complaint_id,json=complaintId,proto3" json:"complaint_id,omitempty"`
+	// Resolution remark sent to the portal.
+	Remark        string `protobuf:"bytes,2,opt,name=remark,proto3" json:"remark,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveComplaintRequest) Reset() {
+	*x = ResolveComplaintRequest{}
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveComplaintRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveComplaintRequest) ProtoMessage() {}
+
+func (x *ResolveComplaintRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveComplaintRequest.ProtoReflect.Descriptor instead.
+func (*ResolveComplaintRequest) Descriptor() ([]byte, []int) {
+	return file_internal_rpc_cmonpb_cmon_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ResolveComplaintRequest) GetComplaintId() string {
+	if x != nil {
+		return x.ComplaintId
+	}
+	return ""
+}
+
+func (x *ResolveComplaintRequest) GetRemark() string {
+	if x != nil {
+		return x.Remark
+	}
+	return ""
+}
+
+type ResolveComplaintResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveComplaintResponse) Reset() {
+	*x = ResolveComplaintResponse{}
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveComplaintResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveComplaintResponse) ProtoMessage() {}
+
+func (x *ResolveComplaintResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveComplaintResponse.ProtoReflect.Descriptor instead.
+func (*ResolveComplaintResponse) Descriptor() ([]byte, []int) {
+	return file_internal_rpc_cmonpb_cmon_proto_rawDescGZIP(), []int{4}
+}
+
+type TriggerFetchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerFetchRequest) Reset() {
+	*x = TriggerFetchRequest{}
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerFetchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerFetchRequest) ProtoMessage() {}
+
+func (x *TriggerFetchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerFetchRequest.ProtoReflect.Descriptor instead.
+func (*TriggerFetchRequest) Descriptor() ([]byte, []int) {
+	return file_internal_rpc_cmonpb_cmon_proto_rawDescGZIP(), []int{5}
+}
+
+type TriggerFetchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerFetchResponse) Reset() {
+	*x = TriggerFetchResponse{}
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerFetchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerFetchResponse) ProtoMessage() {}
+
+func (x *TriggerFetchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerFetchResponse.ProtoReflect.Descriptor instead.
+func (*TriggerFetchResponse) Descriptor() ([]byte, []int) {
+	return file_internal_rpc_cmonpb_cmon_proto_rawDescGZIP(), []int{6}
+}
+
+type StreamEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Event topics to receive: "complaint.new", "complaint.resolved",
+	// "fetch.failed" and "alert". Empty for all of them.
+	Topics        []string `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamEventsRequest) Reset() {
+	*x = StreamEventsRequest{}
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsRequest) ProtoMessage() {}
+
+func (x *StreamEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsRequest.ProtoReflect.Descriptor instead.
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return file_internal_rpc_cmonpb_cmon_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StreamEventsRequest) GetTopics() []string {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Time  *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*Event_ComplaintNew
+	//	*Event_ComplaintResolved
+	//	*Event_FetchFailed
+	//	*Event_Alert
+	Event         isEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_internal_rpc_cmonpb_cmon_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Event) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Event) GetEvent() isEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *Event) GetComplaintNew() *Complaint {
+	if x != nil {
+		if x, ok := x.Event.(*Event_ComplaintNew); ok {
+			return x.ComplaintNew
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetComplaintResolved() *Resolution {
+	if x != nil {
+		if x, ok := x.Event.(*Event_ComplaintResolved); ok {
+			return x.ComplaintResolved
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetFetchFailed() *FetchFailure {
+	if x != nil {
+		if x, ok := x.Event.(*Event_FetchFailed); ok {
+			return x.FetchFailed
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetAlert() *Alert {
+	if x != nil {
+		if x, ok := x.Event.(*Event_Alert); ok {
+			return x.Alert
+		}
+	}
+	return nil
+}
+
+type isEvent_Event interface {
+	isEvent_Event()
+}
+
+type Event_ComplaintNew struct {
+	ComplaintNew *Complaint `protobuf:"bytes,2,opt,name=complaint_new,json=complaintNew,proto3,oneof"`
+}
+
+type Event_ComplaintResolved struct {
+	ComplaintResolved *Resolution `protobuf:"bytes,3,opt,name=complaint_resolved,json=complaintResolved,proto3,oneof"`
+}
+
+type Event_FetchFailed struct {
+	FetchFailed *FetchFailure `protobuf:"bytes,4,opt,name=fetch_failed,json=fetchFailed,proto3,oneof"`
+}
+
+type Event_Alert struct {
+	Alert *Alert `protobuf:"bytes,5,opt,name=alert,proto3,oneof"`
+}
+
+func (*Event_ComplaintNew) isEvent_Event() {}
+
+func (*Event_ComplaintResolved) isEvent_Event() {}
+
+func (*Event_FetchFailed) isEvent_Event() {}
+
+func (*Event_Alert) isEvent_Event() {}
+
+type Resolution struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ComplaintId  string                 `protobuf:"bytes,1,opt,name=complaint_id,json=complaintId,proto3" json:"complaint_id,omitempty"`
+	ConsumerName string                 `protobuf:"bytes,2,opt,name=consumer_name,json=consumerName,proto3" json:"consumer_name,omitempty"`
+	Belt         string                 `protobuf:"bytes,3,opt,name=belt,proto3" json:"belt,omitempty"`
+	// A locally registered complaint, resolved from the dashboard.
+	Local bool `protobuf:"varint,4,opt,name=local,proto3" json:"local,omitempty"`
+	// Who closed it on the portal and what they wrote, when known.
+	ResolvedBy    string `protobuf:"bytes,5,opt,name=resolved_by,json=resolvedBy,proto3" json:"resolved_by,omitempty"`
+	Remark        string `protobuf:"bytes,6,opt,name=remark,proto3" json:"remark,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Resolution) Reset() {
+	*x = Resolution{}
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Resolution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resolution) ProtoMessage() {}
+
+func (x *Resolution) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resolution.ProtoReflect.Descriptor instead.
+func (*Resolution) Descriptor() ([]byte, []int) {
+	return file_internal_rpc_cmonpb_cmon_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Resolution) GetComplaintId() string {
+	if x != nil {
+		return x.ComplaintId
+	}
+	return ""
+}
+
+func (x *Resolution) GetConsumerName() string {
+	if x != nil {
+		return x.ConsumerName
+	}
+	return ""
+}
+
+func (x *Resolution) GetBelt() string {
+	if x != nil {
+		return x.Belt
+	}
+	return ""
+}
+
+func (x *Resolution) GetLocal() bool {
+	if x != nil {
+		return x.Local
+	}
+	return false
+}
+
+func (x *Resolution) GetResolvedBy() string {
+	if x != nil {
+		return x.ResolvedBy
+	}
+	return ""
+}
+
+func (x *Resolution) GetRemark() string {
+	if x != nil {
+		return x.Remark
+	}
+	return ""
+}
+
+type FetchFailure struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Attempts      int32                  `protobuf:"varint,2,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchFailure) Reset() {
+	*x = FetchFailure{}
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchFailure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchFailure) ProtoMessage() {}
+
+func (x *FetchFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchFailure.ProtoReflect.Descriptor instead.
+func (*FetchFailure) Descriptor() ([]byte, []int) {
+	return file_internal_rpc_cmonpb_cmon_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *FetchFailure) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *FetchFailure) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+type Alert struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          Alert_Kind             `protobuf:"varint,1,opt,name=kind,proto3,enum=cmon.v1.Alert_Kind" json:"kind,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_rpc_cmonpb_cmon_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_internal_rpc_cmonpb_cmon_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Alert) GetKind() Alert_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return Alert_KIND_UNSPECIFIED
+}
+
+func (x *Alert) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Alert) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_internal_rpc_cmonpb_cmon_proto protoreflect.FileDescriptor
+
+const file_internal_rpc_cmonpb_cmon_proto_rawDesc = "" +
+	"\n" +
+	"\x1einternal/rpc/cmonpb/cmon.proto\x12\acmon.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc2\x03\n" +
+	"\tComplaint\x12!\n" +
+	"\fcomplaint_id\x18\x01 \x01(\tR\vcomplaintId\x12\x15\n" +
+	"\x06api_id\x18\x02 \x01(\tR\x05apiId\x12#\n" +
+	"\rconsumer_name\x18\x03 \x01(\tR\fconsumerName\x12\x1f\n" +
+	"\vconsumer_no\x18\x04 \x01(\tR\n" +
+	"consumerNo\x12\x1b\n" +
+	"\tmobile_no\x18\x05 \x01(\tR\bmobileNo\x12\x18\n" +
+	"\avillage\x18\x06 \x01(\tR\avillage\x12\x12\n" +
+	"\x04belt\x18\a \x01(\tR\x04belt\x12\x18\n" +
+	"\aaddress\x18\b \x01(\tR\aaddress\x12\x12\n" +
+	"\x04area\x18\t \x01(\tR\x04area\x12 \n" +
+	"\vdescription\x18\n" +
+	" \x01(\tR\vdescription\x12#\n" +
+	"\rcomplain_date\x18\v \x01(\tR\fcomplainDate\x12>\n" +
+	"\rfirst_seen_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\vfirstSeenAt\x12\x19\n" +
+	"\bmaps_url\x18\r \x01(\tR\amapsUrl\x12\x1a\n" +
+	"\breopened\x18\x0e \x01(\bR\breopened\"(\n" +
+	"\x12ListPendingRequest\x12\x12\n" +
+	"\x04belt\x18\x01 \x01(\tR\x04belt\"I\n" +
+	"\x13ListPendingResponse\x122\n" +
+	"\n" +
+	"complaints\x18\x01 \x03(\v2\x12.cmon.v1.ComplaintR\n" +
+	"complaints\"T\n" +
+	"\x17ResolveComplaintRequest\x12!\n" +
+	"\fcomplaint_id\x18\x01 \x01(\tR\vcomplaintId\x12\x16\n" +
+	"\x06remark\x18\x02 \x01(\tR\x06remark\"\x1a\n" +
+	"\x18ResolveComplaintResponse\"\x15\n" +
+	"\x13TriggerFetchRequest\"\x16\n" +
+	"\x14TriggerFetchResponse\"-\n" +
+	"\x13StreamEventsRequest\x12\x16\n" +
+	"\x06topics\x18\x01 \x03(\tR\x06topics\"\xa5\x02\n" +
+	"\x05Event\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x129\n" +
+	"\rcomplaint_new\x18\x02 \x01(\v2\x12.cmon.v1.ComplaintH\x00R\fcomplaintNew\x12D\n" +
+	"\x12complaint_resolved\x18\x03 \x01(\v2\x13.cmon.v1.ResolutionH\x00R\x11complaintResolved\x12:\n" +
+	"\ffetch_failed\x18\x04 \x01(\v2\x15.cmon.v1.FetchFailureH\x00R\vfetchFailed\x12&\n" +
+	"\x05alert\x18\x05 \x01(\v2\x0e.cmon.v1.AlertH\x00R\x05alertB\a\n" +
+	"\x05event\"\xb7\x01\n" +
+	"\n" +
+	"Resolution\x12!\n" +
+	"\fcomplaint_id\x18\x01 \x01(\tR\vcomplaintId\x12#\n" +
+	"\rconsumer_name\x18\x02 \x01(\tR\fconsumerName\x12\x12\n" +
+	"\x04belt\x18\x03 \x01(\tR\x04belt\x12\x14\n" +
+	"\x05local\x18\x04 \x01(\bR\x05local\x12\x1f\n" +
+	"\vresolved_by\x18\x05 \x01(\tR\n" +
+	"resolvedBy\x12\x16\n" +
+	"\x06remark\x18\x06 \x01(\tR\x06remark\"@\n" +
+	"\fFetchFailure\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\x12\x1a\n" +
+	"\battempts\x18\x02 \x01(\x05R\battempts\"\xf4\x01\n" +
+	"\x05Alert\x12'\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x13.cmon.v1.Alert.KindR\x04kind\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\x91\x01\n" +
+	"\x04Kind\x12\x14\n" +
+	"\x10KIND_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rKIND_CRITICAL\x10\x01\x12\x14\n" +
+	"\x10KIND_PORTAL_DOWN\x10\x02\x12\x12\n" +
+	"\x0eKIND_PORTAL_UP\x10\x03\x12\x0f\n" +
+	"\vKIND_OUTAGE\x10\x04\x12\x12\n" +
+	"\x0eKIND_RECOVERED\x10\x05\x12\x11\n" +
+	"\rKIND_IMPORTED\x10\x062\xbc\x02\n" +
+	"\n" +
+	"Complaints\x12H\n" +
+	"\vListPending\x12\x1b.cmon.v1.ListPendingRequest\x1a\x1c.cmon.v1.ListPendingResponse\x12W\n" +
+	"\x10ResolveComplaint\x12 .cmon.v1.ResolveComplaintRequest\x1a!.cmon.v1.ResolveComplaintResponse\x12K\n" +
+	"\fTriggerFetch\x12\x1c.cmon.v1.TriggerFetchRequest\x1a\x1d.cmon.v1.TriggerFetchResponse\x12>\n" +
+	"\fStreamEvents\x12\x1c.cmon.v1.StreamEventsRequest\x1a\x0e.cmon.v1.Event0\x01B\x1aZ\x18cmon/internal/rpc/cmonpbb\x06proto3"
+
+var (
+	file_internal_rpc_cmonpb_cmon_proto_rawDescOnce sync.Once
+	file_internal_rpc_cmonpb_cmon_proto_rawDescData []byte
+)
+
+func file_internal_rpc_cmonpb_cmon_proto_rawDescGZIP() []byte {
+	file_internal_rpc_cmonpb_cmon_proto_rawDescOnce.Do(func() {
+		file_internal_rpc_cmonpb_cmon_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_internal_rpc_cmonpb_cmon_proto_rawDesc), len(file_internal_rpc_cmonpb_cmon_proto_rawDesc)))
+	})
+	return file_internal_rpc_cmonpb_cmon_proto_rawDescData
+}
+
+var file_internal_rpc_cmonpb_cmon_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_internal_rpc_cmonpb_cmon_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_internal_rpc_cmonpb_cmon_proto_goTypes = []any{
+	(Alert_Kind)(0),                  // 0: cmon.v1.Alert.Kind
+	(*Complaint)(nil),                // 1: cmon.v1.Complaint
+	(*ListPendingRequest)(nil),       // 2: cmon.v1.ListPendingRequest
+	(*ListPendingResponse)(nil),      // 3: cmon.v1.ListPendingResponse
+	(*ResolveComplaintRequest)(nil),  // 4: cmon.v1.ResolveComplaintRequest
+	(*ResolveComplaintResponse)(nil), // 5: cmon.v1.ResolveComplaintResponse
+	(*TriggerFetchRequest)(nil),      // 6: cmon.v1.TriggerFetchRequest
+	(*TriggerFetchResponse)(nil),     // 7: cmon.v1.TriggerFetchResponse
+	(*StreamEventsRequest)(nil),      // 8: cmon.v1.StreamEventsRequest
+	(*Event)(nil),                    // 9: cmon.v1.Event
+	(*Resolution)(nil),               // 10: cmon.v1.Resolution
+	(*FetchFailure)(nil),             // 11: cmon.v1.FetchFailure
+	(*Alert)(nil),                    // 12: cmon.v1.Alert
+	(*timestamppb.Timestamp)(nil),    // 13: google.protobuf.Timestamp
+}
+var file_internal_rpc_cmonpb_cmon_proto_depIdxs = []int32{
+	13, // 0: cmon.v1.Complaint.first_seen_at:type_name -> google.protobuf.Timestamp
+	1,  // 1: cmon.v1.ListPendingResponse.complaints:type_name -> cmon.v1.Complaint
+	13, // 2: cmon.v1.Event.time:type_name -> google.protobuf.Timestamp
+	1,  // 3: cmon.v1.Event.complaint_new:type_name -> cmon.v1.Complaint
+	10, // 4: cmon.v1.Event.complaint_resolved:type_name -> cmon.v1.Resolution
+	11, // 5: cmon.v1.Event.fetch_failed:type_name -> cmon.v1.FetchFailure
+	12, // 6: cmon.v1.Event.alert:type_name -> cmon.v1.Alert
+	0,  // 7: cmon.v1.Alert.kind:type_name -> cmon.v1.Alert.Kind
+	2,  // 8: cmon.v1.Complaints.ListPending:input_type -> cmon.v1.ListPendingRequest
+	4,  // 9: cmon.v1.Complaints.ResolveComplaint:input_type -> cmon.v1.ResolveComplaintRequest
+	6,  // 10: cmon.v1.Complaints.TriggerFetch:input_type -> cmon.v1.TriggerFetchRequest
+	8,  // 11: cmon.v1.Complaints.StreamEvents:input_type -> cmon.v1.StreamEventsRequest
+	3,  // 12: cmon.v1.Complaints.ListPending:output_type -> cmon.v1.ListPendingResponse
+	5,  // 13: cmon.v1.Complaints.ResolveComplaint:output_type -> cmon.v1.ResolveComplaintResponse
+	7,  // 14: cmon.v1.Complaints.TriggerFetch:output_type -> cmon.v1.TriggerFetchResponse
+	9,  // 15: cmon.v1.Complaints.StreamEvents:output_type -> cmon.v1.Event
+	12, // [12:16] is the sub-list for method output_type
+	8,  // [8:12] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_internal_rpc_cmonpb_cmon_proto_init() }
+func file_internal_rpc_cmonpb_cmon_proto_init() {
+	if File_internal_rpc_cmonpb_cmon_proto != nil {
+		return
+	}
+	file_internal_rpc_cmonpb_cmon_proto_msgTypes[8].OneofWrappers = []any{
+		(*Event_ComplaintNew)(nil),
+		(*Event_ComplaintResolved)(nil),
+		(*Event_FetchFailed)(nil),
+		(*Event_Alert)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_rpc_cmonpb_cmon_proto_rawDesc), len(file_internal_rpc_cmonpb_cmon_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_internal_rpc_cmonpb_cmon_proto_goTypes,
+		DependencyIndexes: file_internal_rpc_cmonpb_cmon_proto_depIdxs,
+		EnumInfos:         file_internal_rpc_cmonpb_cmon_proto_enumTypes,
+		MessageInfos:      file_internal_rpc_cmonpb_cmon_proto_msgTypes,
+	}.Build()
+	File_internal_rpc_cmonpb_cmon_proto = out.File
+	file_internal_rpc_cmonpb_cmon_proto_goTypes = nil
+	file_internal_rpc_cmonpb_cmon_proto_depIdxs = nil
+}
@@ -0,0 +1,259 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: internal/rpc/cmonpb/cmon.proto
+
+package cmonpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Complaints_ListPending_FullMethodName      = "/cmon.v1.Complaints/ListPending"
+	Complaints_ResolveComplaint_FullMethodName = "/cmon.v1.Complaints/ResolveComplaint"
+	Complaints_TriggerFetch_FullMethodName     = "/cmon.v1.Complaints/TriggerFetch"
+	Complaints_StreamEvents_FullMethodName     = "/cmon.v1.Complaints/StreamEvents"
+)
+
+// ComplaintsClient is the client API for Complaints service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ComplaintsClient interface {
+	// ListPending returns the complaints currently open, oldest first.
+	ListPending(ctx context.Context, in *ListPendingRequest, opts ...grpc.CallOption) (*ListPendingResponse, error)
+	// ResolveComplaint resolves a complaint on the DGVCL portal (or, for a
+	// locally registered one, in cmon). A portal complaint leaves the pending
+	// list, and a complaint_resolved event is streamed, once the next fetch
+	// no longer sees it.
+	ResolveComplaint(ctx context.Context, in *ResolveComplaintRequest, opts ...grpc.CallOption) (*ResolveComplaintResponse, error)
+	// TriggerFetch runs a fetch cycle now and returns when it is done. It
+	// fails with UNAVAILABLE while another cycle is running.
+	TriggerFetch(ctx context.Context, in *TriggerFetchRequest, opts ...grpc.CallOption) (*TriggerFetchResponse, error)
+	// StreamEvents streams events as they happen. A client that falls too
+	// far behind has its stream closed with RESOURCE_EXHAUSTED; it should
+	// call ListPending and stream again.
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+}
+
+type complaintsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewComplaintsClient(cc grpc.ClientConnInterface) ComplaintsClient {
+	return &complaintsClient{cc}
+}
+
+func (c *complaintsClient) ListPending(ctx context.Context, in *ListPendingRequest, opts ...grpc.CallOption) (*ListPendingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPendingResponse)
+	err := c.cc.Invoke(ctx, Complaints_ListPending_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *complaintsClient) ResolveComplaint(ctx context.Context, in *ResolveComplaintRequest, opts ...grpc.CallOption) (*ResolveComplaintResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveComplaintResponse)
+	err := c.cc.Invoke(ctx, Complaints_ResolveComplaint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *complaintsClient) TriggerFetch(ctx context.Context, in *TriggerFetchRequest, opts ...grpc.CallOption) (*TriggerFetchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TriggerFetchResponse)
+	err := c.cc.Invoke(ctx, Complaints_TriggerFetch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *complaintsClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Complaints_ServiceDesc.Streams[0], Complaints_StreamEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamEventsRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Complaints_StreamEventsClient = grpc.ServerStreamingClient[Event]
+
+// ComplaintsServer is the server API for Complaints service.
+// All implementations must embed UnimplementedComplaintsServer
+// for forward compatibility.
+type ComplaintsServer interface {
+	// ListPending returns the complaints currently open, oldest first.
+	ListPending(context.Context, *ListPendingRequest) (*ListPendingResponse, error)
+	// ResolveComplaint resolves a complaint on the DGVCL portal (or, for a
+	// locally registered one, in cmon). A portal complaint leaves the pending
+	// list, and a complaint_resolved event is streamed, once the next fetch
+	// no longer sees it.
+	ResolveComplaint(context.Context, *ResolveComplaintRequest) (*ResolveComplaintResponse, error)
+	// TriggerFetch runs a fetch cycle now and returns when it is done. It
+	// fails with UNAVAILABLE while another cycle is running.
+	TriggerFetch(context.Context, *TriggerFetchRequest) (*TriggerFetchResponse, error)
+	// StreamEvents streams events as they happen. A client that falls too
+	// far behind has its stream closed with RESOURCE_EXHAUSTED; it should
+	// call ListPending and stream again.
+	StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[Event]) error
+	mustEmbedUnimplementedComplaintsServer()
+}
+
+// UnimplementedComplaintsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedComplaintsServer struct{}
+
+func (UnimplementedComplaintsServer) ListPending(context.Context, *ListPendingRequest) (*ListPendingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPending not implemented")
+}
+func (UnimplementedComplaintsServer) ResolveComplaint(context.Context, *ResolveComplaintRequest) (*ResolveComplaintResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveComplaint not implemented")
+}
+func (UnimplementedComplaintsServer) TriggerFetch(context.Context, *TriggerFetchRequest) (*TriggerFetchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerFetch not implemented")
+}
+func (UnimplementedComplaintsServer) StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedComplaintsServer) mustEmbedUnimplementedComplaintsServer() {}
+func (UnimplementedComplaintsServer) testEmbeddedByValue()                    {}
+
+// UnsafeComplaintsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ComplaintsServer will
+// result in compilation errors.
+type UnsafeComplaintsServer interface {
+	mustEmbedUnimplementedComplaintsServer()
+}
+
+func RegisterComplaintsServer(s grpc.ServiceRegistrar, srv ComplaintsServer) {
+	// If the following call pancis, it indicates UnimplementedComplaintsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Complaints_ServiceDesc, srv)
+}
+
+func _Complaints_ListPending_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ComplaintsServer).ListPending(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Complaints_ListPending_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ComplaintsServer).ListPending(ctx, req.(*ListPendingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Complaints_ResolveComplaint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveComplaintRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ComplaintsServer).ResolveComplaint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Complaints_ResolveComplaint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ComplaintsServer).ResolveComplaint(ctx, req.(*ResolveComplaintRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Complaints_TriggerFetch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerFetchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ComplaintsServer).TriggerFetch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Complaints_TriggerFetch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ComplaintsServer).TriggerFetch(ctx, req.(*TriggerFetchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Complaints_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ComplaintsServer).StreamEvents(m, &grpc.GenericServerStream[StreamEventsRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Complaints_StreamEventsServer = grpc.ServerStreamingServer[Event]
+
+// Complaints_ServiceDesc is the grpc.ServiceDesc for Complaints service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Complaints_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cmon.v1.Complaints",
+	HandlerType: (*ComplaintsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListPending",
+			Handler:    _Complaints_ListPending_Handler,
+		},
+		{
+			MethodName: "ResolveComplaint",
+			Handler:    _Complaints_ResolveComplaint_Handler,
+		},
+		{
+			MethodName: "TriggerFetch",
+			Handler:    _Complaints_TriggerFetch_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _Complaints_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "internal/rpc/cmonpb/cmon.proto",
+}
@@ -0,0 +1,376 @@
+// Package rpc serves the gRPC API described in cmonpb/cmon.proto, for
+// tools that would otherwise poll the dashboard or read Telegram: the
+// pending list, resolving, triggering a fetch and a stream of the events
+// published on the event bus.
+package rpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cmon/internal/eventbus"
+	"cmon/internal/notify"
+	"cmon/internal/rpc/cmonpb"
+	"cmon/internal/storage"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// streamBuffer is how many events a StreamEvents client may fall behind
+// before its stream is closed.
+const streamBuffer = 64
+
+// Server implements cmonpb.ComplaintsServer. Subscribe Handle to the event
+// bus to feed StreamEvents.
+type Server struct {
+	cmonpb.UnimplementedComplaintsServer
+
+	// Redact masks mobile and consumer numbers (REDACT_PII) in listed and
+	// streamed complaints.
+	Redact bool
+
+	stor    *storage.Storage
+	resolve func(apiID, remark string) error // the dashboard's resolve callback
+	fetch   func() error                     // the dashboard's refresh callback
+	token   string
+
+	mu      sync.Mutex
+	streams map[*stream]struct{}
+	closed  bool
+}
+
+// stream is one StreamEvents call.
+type stream struct {
+	topics map[string]bool // nil = all
+	events chan *cmonpb.Event
+	behind chan struct{} // closed when events overflowed
+	once   sync.Once
+}
+
+// New returns a Server over stor. resolve and fetch are the callbacks the
+// dashboard uses for the same actions. A non-empty token is required as a
+// bearer token on every call.
+func New(stor *storage.Storage, resolve func(apiID, remark string) error, fetch func() error, token string) *Server {
+	return &Server{
+		stor:    stor,
+		resolve: resolve,
+		fetch:   fetch,
+		token:   token,
+		streams: make(map[*stream]struct{}),
+	}
+}
+
+// Listen starts serving on addr and returns the gRPC server; stop it with
+// Shutdown.
+func (s *Server) Listen(addr string) (*grpc.Server, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("grpc listen on %s: %w", addr, err)
+	}
+	gs := s.NewGRPCServer()
+	go func() {
+		log.Printf("✓ gRPC API listening on %s", lis.Addr())
+		if err := gs.Serve(lis); err != nil {
+			log.Printf("⚠️  gRPC server error: %v", err)
+		}
+	}()
+	return gs, nil
+}
+
+// NewGRPCServer returns a grpc.Server with s registered and, when a token
+// is set, authentication on every call.
+func (s *Server) NewGRPCServer() *grpc.Server {
+	var opts []grpc.ServerOption
+	if s.token != "" {
+		opts = append(opts,
+			grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+				if err := s.authorize(ctx); err != nil {
+					return nil, err
+				}
+				return h(ctx, req)
+			}),
+			grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+				if err := s.authorize(ss.Context()); err != nil {
+					return err
+				}
+				return h(srv, ss)
+			}),
+		)
+	}
+	gs := grpc.NewServer(opts...)
+	cmonpb.RegisterComplaintsServer(gs, s)
+	return gs
+}
+
+// Shutdown ends every event stream, then stops gs gracefully, or at once
+// when ctx is done first.
+func (s *Server) Shutdown(ctx context.Context, gs *grpc.Server) {
+	s.mu.Lock()
+	s.closed = true
+	for st := range s.streams {
+		st.once.Do(func() { close(st.behind) })
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		gs.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		gs.Stop()
+	}
+}
+
+// authorize checks the bearer token in ctx's metadata.
+func (s *Server) authorize(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		if token, ok := strings.CutPrefix(v, "Bearer "); ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+}
+
+// ListPending implements cmonpb.ComplaintsServer.
+func (s *Server) ListPending(_ context.Context, req *cmonpb.ListPendingRequest) (*cmonpb.ListPendingResponse, error) {
+	firstSeen, err := s.stor.GetFirstSeen()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "read first-seen times: %v", err)
+	}
+	resp := &cmonpb.ListPendingResponse{}
+	for _, id := range s.stor.GetAllSeenComplaints() {
+		rec, ok := s.stor.GetRecord(id)
+		if !ok || (req.GetBelt() != "" && !strings.EqualFold(rec.Belt, req.GetBelt())) {
+			continue
+		}
+		consumerNo, mobileNo := rec.ConsumerNo, rec.MobileNo
+		if s.Redact {
+			consumerNo, mobileNo = notify.MaskNumber(consumerNo), notify.MaskNumber(mobileNo)
+		}
+		c := &cmonpb.Complaint{
+			ComplaintId:  rec.ComplaintID,
+			ApiId:        rec.APIID,
+			ConsumerName: rec.ConsumerName,
+			ConsumerNo:   consumerNo,
+			MobileNo:     mobileNo,
+			Village:      rec.Village,
+			Belt:         rec.Belt,
+			Address:      rec.Address,
+			Area:         rec.Area,
+			Description:  rec.Description,
+			ComplainDate: rec.ComplainDate,
+		}
+		if t, ok := firstSeen[id]; ok {
+			c.FirstSeenAt = timestamppb.New(t)
+		}
+		resp.Complaints = append(resp.Complaints, c)
+	}
+	sort.SliceStable(resp.Complaints, func(i, j int) bool {
+		a, b := resp.Complaints[i].GetFirstSeenAt().AsTime(), resp.Complaints[j].GetFirstSeenAt().AsTime()
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return resp.Complaints[i].ComplaintId < resp.Complaints[j].ComplaintId
+	})
+	return resp, nil
+}
+
+// ResolveComplaint implements cmonpb.ComplaintsServer.
+func (s *Server) ResolveComplaint(_ context.Context, req *cmonpb.ResolveComplaintRequest) (*cmonpb.ResolveComplaintResponse, error) {
+	if req.GetComplaintId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "complaint_id is required")
+	}
+	if !s.stor.Exists(req.GetComplaintId()) {
+		return nil, status.Errorf(codes.NotFound, "complaint %s is not pending", req.GetComplaintId())
+	}
+	apiID := s.stor.GetAPIID(req.GetComplaintId())
+	if err := s.resolve(apiID, req.GetRemark()); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "resolve complaint %s: %v", req.GetComplaintId(), err)
+	}
+	log.Printf("✅ Complaint %s resolved over gRPC", req.GetComplaintId())
+	return &cmonpb.ResolveComplaintResponse{}, nil
+}
+
+// TriggerFetch implements cmonpb.ComplaintsServer.
+func (s *Server) TriggerFetch(context.Context, *cmonpb.TriggerFetchRequest) (*cmonpb.TriggerFetchResponse, error) {
+	if err := s.fetch(); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "fetch: %v", err)
+	}
+	return &cmonpb.TriggerFetchResponse{}, nil
+}
+
+// StreamEvents implements cmonpb.ComplaintsServer.
+func (s *Server) StreamEvents(req *cmonpb.StreamEventsRequest, out grpc.ServerStreamingServer[cmonpb.Event]) error {
+	st := &stream{events: make(chan *cmonpb.Event, streamBuffer), behind: make(chan struct{})}
+	if len(req.GetTopics()) > 0 {
+		st.topics = make(map[string]bool)
+		for _, t := range req.GetTopics() {
+			st.topics[t] = true
+		}
+	}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return status.Error(codes.Unavailable, "shutting down")
+	}
+	s.streams[st] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.streams, st)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case e := <-st.events:
+			if err := out.Send(e); err != nil {
+				return err
+			}
+		case <-st.behind:
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return status.Error(codes.Unavailable, "shutting down")
+			}
+			return status.Errorf(codes.ResourceExhausted, "client fell more than %d events behind", streamBuffer)
+		case <-out.Context().Done():
+			return nil
+		}
+	}
+}
+
+// Handle is an eventbus.Handler that passes events on to every stream.
+// It never blocks: a stream whose buffer is full is closed instead.
+func (s *Server) Handle(e eventbus.Event) error {
+	events := toEvents(e, s.stor, s.Redact)
+	if len(events) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for st := range s.streams {
+		if st.topics != nil && !st.topics[e.Topic()] {
+			continue
+		}
+		for _, ev := range events {
+			select {
+			case st.events <- ev:
+			default:
+				st.once.Do(func() { close(st.behind) })
+			}
+		}
+	}
+	return nil
+}
+
+// toEvents converts a bus event to the events streamed for it; new
+// complaints published as a batch are streamed one by one, masked when
+// redact is set.
+func toEvents(e eventbus.Event, stor *storage.Storage, redact bool) []*cmonpb.Event {
+	now := timestamppb.New(time.Now())
+	switch e := e.(type) {
+	case eventbus.ComplaintNew:
+		return []*cmonpb.Event{{Time: now, Event: &cmonpb.Event_ComplaintNew{ComplaintNew: newComplaint(e.Complaint, stor, redact)}}}
+	case eventbus.ComplaintsNew:
+		out := make([]*cmonpb.Event, len(e.Complaints))
+		for i, c := range e.Complaints {
+			out[i] = &cmonpb.Event{Time: now, Event: &cmonpb.Event_ComplaintNew{ComplaintNew: newComplaint(c, stor, redact)}}
+		}
+		return out
+	case eventbus.ComplaintResolved:
+		return []*cmonpb.Event{{Time: stamp(e.Status.Time), Event: &cmonpb.Event_ComplaintResolved{ComplaintResolved: &cmonpb.Resolution{
+			ComplaintId:  e.Status.ComplaintID,
+			ConsumerName: e.Status.ConsumerName,
+			Belt:         e.Status.Belt,
+			Local:        e.Status.Local,
+			ResolvedBy:   e.Status.ResolvedBy,
+			Remark:       e.Status.Remark,
+		}}}}
+	case eventbus.FetchFailed:
+		msg := ""
+		if e.Err != nil {
+			msg = e.Err.Error()
+		}
+		return []*cmonpb.Event{{Time: stamp(e.Time), Event: &cmonpb.Event_FetchFailed{FetchFailed: &cmonpb.FetchFailure{
+			Error:    msg,
+			Attempts: int32(e.Attempts),
+		}}}}
+	case eventbus.AlertRaised:
+		return []*cmonpb.Event{{Time: stamp(e.Alert.Time), Event: &cmonpb.Event_Alert{Alert: &cmonpb.Alert{
+			Kind:    alertKind(e.Alert.Kind),
+			Title:   e.Alert.Title,
+			Message: e.Alert.Message,
+		}}}}
+	}
+	return nil
+}
+
+// newComplaint converts a new complaint; it is already saved, so the API
+// ID and first-seen time come from storage.
+func newComplaint(c notify.Complaint, stor *storage.Storage, redact bool) *cmonpb.Complaint {
+	if redact {
+		c = c.Redacted()
+	}
+	pc := &cmonpb.Complaint{
+		ComplaintId:  c.Number,
+		ApiId:        stor.GetAPIID(c.Number),
+		ConsumerName: c.ComplainantName,
+		ConsumerNo:   c.ConsumerNo,
+		MobileNo:     c.MobileNo,
+		Village:      c.Village,
+		Belt:         c.Belt,
+		Address:      c.ExactLocation,
+		Area:         c.Area,
+		Description:  c.Description,
+		ComplainDate: c.ComplainDate,
+		MapsUrl:      c.MapsURL,
+		Reopened:     c.Reopened != nil,
+	}
+	if t, ok := stor.GetFirstSeenAt(c.Number); ok {
+		pc.FirstSeenAt = timestamppb.New(t)
+	}
+	return pc
+}
+
+func alertKind(k notify.AlertKind) cmonpb.Alert_Kind {
+	switch k {
+	case notify.AlertCritical:
+		return cmonpb.Alert_KIND_CRITICAL
+	case notify.AlertPortalDown:
+		return cmonpb.Alert_KIND_PORTAL_DOWN
+	case notify.AlertPortalUp:
+		return cmonpb.Alert_KIND_PORTAL_UP
+	case notify.AlertOutage:
+		return cmonpb.Alert_KIND_OUTAGE
+	case notify.AlertRecovered:
+		return cmonpb.Alert_KIND_RECOVERED
+	case notify.AlertImported:
+		return cmonpb.Alert_KIND_IMPORTED
+	}
+	return cmonpb.Alert_KIND_UNSPECIFIED
+}
+
+// stamp converts t, using now for a zero time.
+func stamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,193 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"cmon/internal/eventbus"
+	"cmon/internal/notify"
+	"cmon/internal/rpc/cmonpb"
+	"cmon/internal/storage"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial serves srv over an in-memory listener and returns a client for it.
+func dial(t *testing.T, srv *Server) cmonpb.ComplaintsClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	gs := srv.NewGRPCServer()
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return cmonpb.NewComplaintsClient(conn)
+}
+
+func newStorage(t *testing.T) *storage.Storage {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { stor.Close() })
+	return stor
+}
+
+func TestListPendingAndResolve(t *testing.T) {
+	stor := newStorage(t)
+	if err := stor.SaveMultiple([]storage.Record{
+		{ComplaintID: "C-1", APIID: "101", ConsumerName: "Asha", Belt: "Tokarva"},
+		{ComplaintID: "C-2", APIID: "102", ConsumerName: "Ravi", Belt: "Mota"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var resolved []string
+	srv := New(stor, func(apiID, remark string) error {
+		resolved = append(resolved, apiID+": "+remark)
+		return nil
+	}, func() error { return errors.New("a scrape cycle is already in progress") }, "")
+	client := dial(t, srv)
+	ctx := context.Background()
+
+	all, err := client.ListPending(ctx, &cmonpb.ListPendingRequest{})
+	if err != nil || len(all.GetComplaints()) != 2 {
+		t.Fatalf("ListPending = %v, %v", all, err)
+	}
+	mota, err := client.ListPending(ctx, &cmonpb.ListPendingRequest{Belt: "mota"})
+	if err != nil || len(mota.GetComplaints()) != 1 || mota.GetComplaints()[0].GetApiId() != "102" {
+		t.Fatalf("ListPending(belt mota) = %v, %v", mota, err)
+	}
+
+	if _, err := client.ResolveComplaint(ctx, &cmonpb.ResolveComplaintRequest{ComplaintId: "C-1", Remark: "fuse replaced"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(resolved) != 1 || resolved[0] != "101: fuse replaced" {
+		t.Errorf("resolve callback got %v", resolved)
+	}
+	if _, err := client.ResolveComplaint(ctx, &cmonpb.ResolveComplaintRequest{ComplaintId: "C-9"}); status.Code(err) != codes.NotFound {
+		t.Errorf("resolving an unknown complaint = %v, want NotFound", err)
+	}
+
+	if _, err := client.TriggerFetch(ctx, &cmonpb.TriggerFetchRequest{}); status.Code(err) != codes.Unavailable {
+		t.Errorf("TriggerFetch while busy = %v, want Unavailable", err)
+	}
+}
+
+func TestTokenIsRequired(t *testing.T) {
+	stor := newStorage(t)
+	client := dial(t, New(stor, nil, nil, "s3cret"))
+
+	if _, err := client.ListPending(context.Background(), &cmonpb.ListPendingRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("call without a token = %v, want Unauthenticated", err)
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer s3cret")
+	if _, err := client.ListPending(ctx, &cmonpb.ListPendingRequest{}); err != nil {
+		t.Errorf("call with the token: %v", err)
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	stor := newStorage(t)
+	if err := stor.SaveMultiple([]storage.Record{{ComplaintID: "C-1", APIID: "101"}}); err != nil {
+		t.Fatal(err)
+	}
+	srv := New(stor, nil, nil, "")
+	bus := eventbus.New()
+	bus.Subscribe("grpc", srv.Handle)
+	client := dial(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, err := client.StreamEvents(ctx, &cmonpb.StreamEventsRequest{Topics: []string{eventbus.TopicComplaintNew, eventbus.TopicFetchFailed}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Wait until the stream is registered before publishing.
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		srv.mu.Lock()
+		n := len(srv.streams)
+		srv.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stream never registered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	bus.Publish(eventbus.AlertRaised{Alert: notify.Alert{Title: "not subscribed"}})
+	bus.Publish(eventbus.ComplaintsNew{Complaints: []notify.Complaint{{Number: "C-1", MapsURL: "https://maps.example/1"}}})
+	bus.Publish(eventbus.FetchFailed{Err: errors.New("login failed"), Attempts: 3})
+
+	e, err := events.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := e.GetComplaintNew(); c.GetComplaintId() != "C-1" || c.GetApiId() != "101" || c.GetMapsUrl() == "" || c.GetFirstSeenAt() == nil {
+		t.Errorf("first event = %v, want complaint C-1 with API ID, Maps link and first-seen time", e)
+	}
+	if e, err = events.Recv(); err != nil || e.GetFetchFailed().GetError() != "login failed" || e.GetFetchFailed().GetAttempts() != 3 {
+		t.Errorf("second event = %v, %v; want the fetch failure", e, err)
+	}
+}
+
+func TestSlowStreamIsCutOff(t *testing.T) {
+	srv := New(newStorage(t), nil, nil, "")
+	st := &stream{events: make(chan *cmonpb.Event, streamBuffer), behind: make(chan struct{})}
+	srv.streams[st] = struct{}{}
+
+	for range streamBuffer + 1 {
+		if err := srv.Handle(eventbus.FetchFailed{Err: errors.New("login failed")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case <-st.behind:
+	default:
+		t.Error("a stream that fell behind should be cut off")
+	}
+}
+
+func TestRedactMasksNumbers(t *testing.T) {
+	stor := newStorage(t)
+	if err := stor.SaveMultiple([]storage.Record{{ComplaintID: "C-1", APIID: "101", ConsumerNo: "123456789", MobileNo: "9876543210"}}); err != nil {
+		t.Fatal(err)
+	}
+	srv := New(stor, nil, nil, "")
+	srv.Redact = true
+
+	list, err := srv.ListPending(context.Background(), &cmonpb.ListPendingRequest{})
+	if err != nil || len(list.GetComplaints()) != 1 {
+		t.Fatalf("ListPending = %v, %v", list, err)
+	}
+	if c := list.GetComplaints()[0]; c.GetConsumerNo() != "•••••6789" || c.GetMobileNo() != "••••••3210" {
+		t.Errorf("listed numbers = %q, %q; want them masked", c.GetConsumerNo(), c.GetMobileNo())
+	}
+
+	st := &stream{events: make(chan *cmonpb.Event, streamBuffer), behind: make(chan struct{})}
+	srv.streams[st] = struct{}{}
+	if err := srv.Handle(eventbus.ComplaintNew{Complaint: notify.Complaint{Number: "C-1", ConsumerNo: "123456789", MobileNo: "9876543210"}}); err != nil {
+		t.Fatal(err)
+	}
+	if c := (<-st.events).GetComplaintNew(); c.GetConsumerNo() != "•••••6789" || c.GetMobileNo() != "••••••3210" {
+		t.Errorf("streamed numbers = %q, %q; want them masked", c.GetConsumerNo(), c.GetMobileNo())
+	}
+}
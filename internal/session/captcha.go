@@ -0,0 +1,112 @@
+package session
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Captcha is the challenge scraped from the login page.
+type Captcha struct {
+	// Text is the content of the captcha element, e.g. "5 + 7".
+	Text string
+}
+
+// Solver answers a login captcha. Login calls it once per attempt, so an
+// implementation may block (e.g. while waiting for a human) but should
+// enforce its own timeout.
+type Solver interface {
+	Solve(c Captcha) (string, error)
+}
+
+// SolverFunc adapts a plain function to Solver.
+type SolverFunc func(c Captcha) (string, error)
+
+// Solve implements Solver.
+func (f SolverFunc) Solve(c Captcha) (string, error) { return f(c) }
+
+// ArithmeticSolver solves the "a + b" style captcha the DGVCL portal
+// currently serves. It is the default Solver.
+type ArithmeticSolver struct{}
+
+// Solve implements Solver.
+func (ArithmeticSolver) Solve(c Captcha) (string, error) { return solveCaptcha(c.Text) }
+
+// Chain tries each Solver in order and returns the first answer. It lets a
+// cheap automatic solver run first with a slower fallback behind it.
+type Chain []Solver
+
+// Solve implements Solver. When every solver fails, the errors are joined.
+func (ch Chain) Solve(c Captcha) (string, error) {
+	var errs []string
+	for i, s := range ch {
+		answer, err := s.Solve(c)
+		if err == nil {
+			return answer, nil
+		}
+		if i < len(ch)-1 {
+			slog.Warn("captcha solver failed, trying next", "solver", i, "err", err)
+		}
+		errs = append(errs, err.Error())
+	}
+	if len(errs) == 0 {
+		return "", fmt.Errorf("no captcha solver configured")
+	}
+	return "", fmt.Errorf("all captcha solvers failed: %s", strings.Join(errs, "; "))
+}
+
+// Escalate answers with Primary until it has failed After times in a row,
+// then hands that and every later attempt to Fallback until Primary
+// succeeds again. It is meant for a slow or human fallback that should only
+// be bothered once the automatic solver is clearly broken (e.g. after a
+// portal change).
+//
+// Only Primary errors count: an answer Primary produces but the portal
+// rejects is not seen here.
+type Escalate struct {
+	Primary  Solver
+	Fallback Solver
+	After    int
+
+	mu       sync.Mutex
+	failures int
+}
+
+// Solve implements Solver.
+func (e *Escalate) Solve(c Captcha) (string, error) {
+	answer, err := e.Primary.Solve(c)
+	e.mu.Lock()
+	if err == nil {
+		e.failures = 0
+		e.mu.Unlock()
+		return answer, nil
+	}
+	e.failures++
+	failures := e.failures
+	e.mu.Unlock()
+
+	if failures < e.After {
+		return "", err
+	}
+	slog.Warn("captcha solver keeps failing, escalating", "failures", failures, "err", err)
+	return e.Fallback.Solve(c)
+}
+
+// SetCaptchaSolver replaces the solver used by Login. A nil s restores the
+// ArithmeticSolver default.
+func (c *Client) SetCaptchaSolver(s Solver) {
+	c.mu.Lock()
+	c.solver = s
+	c.mu.Unlock()
+}
+
+// captchaSolver returns the configured solver, defaulting to ArithmeticSolver.
+func (c *Client) captchaSolver() Solver {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.solver == nil {
+		return ArithmeticSolver{}
+	}
+	return c.solver
+}
@@ -7,7 +7,7 @@
 //
 // Key features:
 //   - Thread-safe cookie jar shared across all goroutines
-//   - Login with automatic arithmetic captcha solving
+//   - Login with pluggable captcha solving (arithmetic by default)
 //   - Session expiry detection via HTML presence check
 //   - Concurrency-safe Reset() for re-login / session recovery
 package session
@@ -31,6 +31,7 @@ import (
 	"time"
 
 	"cmon/internal/errors"
+	"cmon/internal/proxy"
 
 	"github.com/PuerkitoBio/goquery"
 	"golang.org/x/net/publicsuffix"
@@ -44,14 +45,23 @@ import (
 // be sent as `Authorization: Bearer <token>` on every subsequent request.
 type Client struct {
 	http        *http.Client
-	mu          sync.RWMutex // protects bearerToken and baseURL
+	mu          sync.RWMutex // protects bearerToken, loggedInAt, baseURL and solver
 	baseURL     string       // root host, used for session expiry checks
 	bearerToken string       // Sanctum Bearer token set after successful login
+	loggedInAt  time.Time    // when bearerToken was issued
+	solver      Solver       // captcha solver; nil means ArithmeticSolver
 
 	// limiter throttles outbound requests to stay under the DGVCL portal's
 	// rate limit. Shared across all goroutines using this client.
 	limiter       *rate.Limiter
 	maxRetries429 int
+
+	// tracer is set by EnableTrace when PORTAL_TRACE is on; nil otherwise.
+	tracer *tracer
+
+	// transport is the pooled transport under any tracer, kept so
+	// ConfigureTLS can reach it.
+	transport *http.Transport
 }
 
 // New creates a new session client with a fresh, empty cookie jar.
@@ -73,12 +83,11 @@ func New(rps float64, burst, maxRetries429 int) (*Client, error) {
 	}
 
 	transport := &http.Transport{
-		// The DGVCL portal uses a certificate signed by a private CA that is
-		// not in Go's system certificate store (Chrome had its own store and
-		// trusted it). Skipping verification restores the same behaviour.
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true, //nolint:gosec
-		},
+		// Certificates are verified against the system roots. A portal
+		// certificate from a private CA is trusted via ConfigureTLS
+		// (TLS_CA_FILE / TLS_PINNED_SHA256), not by skipping verification.
+		TLSClientConfig:     &tls.Config{MinVersion: tls.VersionTLS12},
+		Proxy:               proxy.Func,
 		MaxIdleConns:        50,
 		MaxIdleConnsPerHost: 10,
 		IdleConnTimeout:     90 * time.Second,
@@ -105,6 +114,7 @@ func New(rps float64, burst, maxRetries429 int) (*Client, error) {
 		},
 		limiter:       rate.NewLimiter(rate.Limit(rps), burst),
 		maxRetries429: maxRetries429,
+		transport:     transport,
 	}, nil
 }
 
@@ -112,6 +122,7 @@ func New(rps float64, burst, maxRetries429 int) (*Client, error) {
 func (c *Client) Reset() error {
 	c.mu.Lock()
 	c.bearerToken = ""
+	c.loggedInAt = time.Time{}
 	c.mu.Unlock()
 
 	jar, err := cookiejar.New(&cookiejar.Options{
@@ -132,7 +143,7 @@ func (c *Client) Reset() error {
 //
 // Flow:
 //  1. GET the login page → parse captcha + extract x-csrf-token from meta tag
-//  2. Solve arithmetic captcha
+//  2. Solve the captcha with the configured Solver (arithmetic by default)
 //  3. POST JSON credentials to /api/login with X-CSRF-Token header
 //  4. Verify session by checking dashboard is accessible (no login form)
 func (c *Client) Login(loginURL, username, password string) error {
@@ -163,7 +174,7 @@ func (c *Client) Login(loginURL, username, password string) error {
 	if captchaText == "" {
 		return errors.NewLoginFailedError("captcha text not found on login page", fmt.Errorf("selector li.captchaList span returned empty"))
 	}
-	captchaAnswer, err := solveCaptcha(captchaText)
+	captchaAnswer, err := c.captchaSolver().Solve(Captcha{Text: captchaText})
 	if err != nil {
 		return errors.NewLoginFailedError("captcha solution failed", err)
 	}
@@ -222,10 +233,31 @@ func (c *Client) Login(loginURL, username, password string) error {
 	}
 	c.mu.Lock()
 	c.bearerToken = loginResp.Token
+	c.loggedInAt = time.Now()
 	c.mu.Unlock()
 	return nil
 }
 
+// RenewAt returns when a session the portal expires ttl after login
+// should be renewed: margin before that expiry. Zero when not logged in.
+func (c *Client) RenewAt(ttl, margin time.Duration) time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.bearerToken == "" {
+		return time.Time{}
+	}
+	return c.loggedInAt.Add(ttl - margin)
+}
+
+// Authenticated reports whether a login has succeeded and its token has
+// not been cleared by Reset. It does not contact the portal; an expired
+// token is only noticed on the next request.
+func (c *Client) Authenticated() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.bearerToken != ""
+}
+
 // IsSessionExpired checks whether the current session is still valid by
 // fetching the dashboard root and checking if we get redirected to the
 // login page (i.e., if the login form is present in the response HTML).
@@ -275,7 +307,7 @@ func (c *Client) GetJSON(rawURL string) ([]byte, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GET %s returned HTTP %d", rawURL, resp.StatusCode)
+		return nil, statusError(rawURL, resp)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -337,8 +369,8 @@ func (c *Client) get(rawURL string) (*http.Response, error) {
 		return nil, fmt.Errorf("GET %s failed: %w", rawURL, err)
 	}
 	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
-		return nil, fmt.Errorf("GET %s returned HTTP %d", rawURL, resp.StatusCode)
+		defer resp.Body.Close()
+		return nil, statusError(rawURL, resp)
 	}
 	return resp, nil
 }
@@ -381,8 +413,10 @@ func (c *Client) do(req *http.Request) (*http.Response, error) {
 
 		resp, err := c.http.Do(req)
 		if err != nil {
+			slog.Debug("portal request failed", "method", req.Method, "url", req.URL.String(), "error", err)
 			return nil, err
 		}
+		slog.Debug("portal request", "method", req.Method, "url", req.URL.String(), "status", resp.StatusCode, "attempt", attempt+1)
 
 		if resp.StatusCode != http.StatusTooManyRequests {
 			return resp, nil
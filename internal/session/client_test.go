@@ -1,14 +1,30 @@
 package session
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"io"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"cmon/internal/errors"
+
+	"github.com/PuerkitoBio/goquery"
 )
 
 // TestGetJSONRetriesOn429 verifies that the session client transparently
@@ -281,6 +297,35 @@ func TestLoginHappyPathSendsCsrfAndStoresToken(t *testing.T) {
 	}
 }
 
+// TestRenewAt verifies the proactive re-login time follows the login and
+// is cleared with the token.
+func TestRenewAt(t *testing.T) {
+	f := newLoginFixture(t)
+	c, err := New(1000, 1000, 0)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if at := c.RenewAt(time.Hour, 5*time.Minute); !at.IsZero() {
+		t.Errorf("RenewAt before login = %v, want zero", at)
+	}
+
+	before := time.Now()
+	if err := c.Login(f.server.URL+"/login", f.wantUser, f.wantPass); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	at := c.RenewAt(time.Hour, 5*time.Minute)
+	if at.Before(before.Add(55*time.Minute)) || at.After(time.Now().Add(55*time.Minute)) {
+		t.Errorf("RenewAt = %v, want 55m after login at %v", at, before)
+	}
+
+	if err := c.Reset(); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if at := c.RenewAt(time.Hour, 5*time.Minute); !at.IsZero() {
+		t.Errorf("RenewAt after Reset = %v, want zero", at)
+	}
+}
+
 // TestLoginFailsOnUnsolvableCaptcha verifies that a captcha the solver
 // cannot parse aborts the flow before any credential POST.
 func TestLoginFailsOnUnsolvableCaptcha(t *testing.T) {
@@ -320,6 +365,91 @@ func TestLoginFailsOnMissingCaptcha(t *testing.T) {
 	}
 }
 
+// TestLoginUsesConfiguredSolver checks that a Solver set on the client
+// replaces the arithmetic default and sees the scraped captcha text.
+func TestLoginUsesConfiguredSolver(t *testing.T) {
+	f := newLoginFixture(t)
+	f.captchaText = "garbled" // arithmetic solver would fail
+
+	c, err := New(1000, 1000, 0)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var seen string
+	c.SetCaptchaSolver(Chain{
+		ArithmeticSolver{},
+		SolverFunc(func(ch Captcha) (string, error) {
+			seen = ch.Text
+			return "12", nil
+		}),
+	})
+
+	if err := c.Login(f.server.URL+"/login", f.wantUser, f.wantPass); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if seen != "garbled" {
+		t.Errorf("fallback solver saw %q, want garbled", seen)
+	}
+
+	c.SetCaptchaSolver(nil)
+	if _, ok := c.captchaSolver().(ArithmeticSolver); !ok {
+		t.Error("nil solver should restore the arithmetic default")
+	}
+}
+
+func TestChainReportsAllFailures(t *testing.T) {
+	fail := func(msg string) Solver {
+		return SolverFunc(func(Captcha) (string, error) { return "", fmt.Errorf("%s", msg) })
+	}
+	_, err := Chain{fail("ocr down"), fail("no reply")}.Solve(Captcha{Text: "?"})
+	if err == nil || !strings.Contains(err.Error(), "ocr down") || !strings.Contains(err.Error(), "no reply") {
+		t.Errorf("Chain error = %v, want both failures", err)
+	}
+	if _, err := (Chain{}).Solve(Captcha{}); err == nil {
+		t.Error("empty Chain should fail")
+	}
+}
+
+func TestEscalateFallsBackAfterRepeatedFailures(t *testing.T) {
+	primaryOK := false
+	var fallbackCalls int
+	e := &Escalate{
+		Primary: SolverFunc(func(Captcha) (string, error) {
+			if primaryOK {
+				return "auto", nil
+			}
+			return "", fmt.Errorf("unparseable")
+		}),
+		Fallback: SolverFunc(func(Captcha) (string, error) {
+			fallbackCalls++
+			return "human", nil
+		}),
+		After: 3,
+	}
+
+	for i := 1; i <= 2; i++ {
+		if _, err := e.Solve(Captcha{}); err == nil {
+			t.Fatalf("attempt %d: want primary error before escalation", i)
+		}
+	}
+	for i := 3; i <= 4; i++ {
+		if got, err := e.Solve(Captcha{}); err != nil || got != "human" {
+			t.Fatalf("attempt %d: got %q, %v; want fallback answer", i, got, err)
+		}
+	}
+	primaryOK = true
+	if got, _ := e.Solve(Captcha{}); got != "auto" {
+		t.Errorf("primary recovered: got %q, want auto", got)
+	}
+	primaryOK = false
+	if _, err := e.Solve(Captcha{}); err == nil {
+		t.Error("success should reset the failure count")
+	}
+	if fallbackCalls != 2 {
+		t.Errorf("fallback calls: got %d, want 2", fallbackCalls)
+	}
+}
+
 // TestLoginFailsOnBadCredentials surfaces the upstream HTTP error when the
 // portal rejects the credentials.
 func TestLoginFailsOnBadCredentials(t *testing.T) {
@@ -402,4 +532,259 @@ func TestLoginCaptchaSolverMatchesFixtureExpectation(t *testing.T) {
 	}
 }
 
+// TestGetDocClassifiesPortalErrorPages checks that known portal error and
+// maintenance responses surface as PortalUnavailableError (so the fetch loop
+// skips retries) while an unrecognised 500 stays a plain HTTP error.
+func TestGetDocClassifiesPortalErrorPages(t *testing.T) {
+	cases := []struct {
+		name       string
+		status     int
+		body       string
+		wantPortal bool
+	}{
+		{"laravel maintenance 503", http.StatusServiceUnavailable, `<title>Service Unavailable</title>`, true},
+		{"bare 503", http.StatusServiceUnavailable, ``, true},
+		{"laravel 500 page", http.StatusInternalServerError, `<html><title>Server Error</title></html>`, true},
+		{"proxy 502", http.StatusBadGateway, `<h1>502 Bad Gateway</h1>`, true},
+		{"unknown 500", http.StatusInternalServerError, `oops`, false},
+		{"404", http.StatusNotFound, `<title>Not Found</title>`, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			}))
+			defer server.Close()
+
+			c, err := New(1000, 1000, 0)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			_, err = c.GetDoc(server.URL)
+			if err == nil {
+				t.Fatal("expected error for non-200 response")
+			}
+			if got := errors.IsPortalUnavailable(err); got != tc.wantPortal {
+				t.Errorf("IsPortalUnavailable = %v, want %v (err: %v)", got, tc.wantPortal, err)
+			}
+		})
+	}
+}
+
+// TestPortalErrorSignatureIgnoresDashboardText makes sure a 200 maintenance
+// page is recognised by its title, but complaint text mentioning "server
+// error" inside the real dashboard is not.
+func TestPortalErrorSignatureIgnoresDashboardText(t *testing.T) {
+	maint, _ := goquery.NewDocumentFromReader(strings.NewReader(
+		`<html><head><title>Be right back.</title></head><body>Be right back.</body></html>`))
+	if sig := PortalErrorSignature(maint); sig == "" {
+		t.Error("maintenance page not recognised")
+	}
+
+	dash, _ := goquery.NewDocumentFromReader(strings.NewReader(
+		`<html><head><title>Dashboard</title></head><body><h1>Complaints</h1><table id="dataTable"><tr><td>meter server error</td></tr></table></body></html>`))
+	if sig := PortalErrorSignature(dash); sig != "" {
+		t.Errorf("dashboard misclassified as error page (%q)", sig)
+	}
+}
+
+// TestTraceWritesRedactedHAR checks that tracing records every request in a
+// HAR file and never persists credentials or auth headers.
+func TestTraceWritesRedactedHAR(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true,"token":"server-secret"}`)
+	}))
+	defer server.Close()
+
+	c, err := New(1000, 1000, 0)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	dir := t.TempDir()
+	if err := c.EnableTrace(dir); err != nil {
+		t.Fatalf("EnableTrace: %v", err)
+	}
+	c.bearerToken = "bearer-secret"
+
+	if _, err := c.GetJSON(server.URL + "/data?page=2"); err != nil {
+		t.Fatalf("GetJSON: %v", err)
+	}
+	if _, err := c.PostForm(server.URL+"/resolve", map[string][]string{"remark": {"done"}, "password": {"hunter2"}}); err != nil {
+		t.Fatalf("PostForm: %v", err)
+	}
+
+	path, err := c.FlushTrace("test")
+	if err != nil || path == "" {
+		t.Fatalf("FlushTrace: path=%q err=%v", path, err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read HAR: %v", err)
+	}
+	for _, secret := range []string{"bearer-secret", "hunter2", "server-secret"} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("HAR leaks %q", secret)
+		}
+	}
+
+	var har harFile
+	if err := json.Unmarshal(raw, &har); err != nil {
+		t.Fatalf("decode HAR: %v", err)
+	}
+	if len(har.Log.Entries) != 2 {
+		t.Fatalf("entries: got %d, want 2", len(har.Log.Entries))
+	}
+	if got := har.Log.Entries[1].Request.PostData; got == nil || !strings.Contains(got.Text, "remark=done") {
+		t.Errorf("POST body not captured: %+v", got)
+	}
+
+	// Buffer is cleared after a flush.
+	if path, _ := c.FlushTrace("empty"); path != "" {
+		t.Errorf("second flush should write nothing, wrote %s", path)
+	}
+}
+
+func TestConfigureTLSVerifiesPortalCertificate(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<html><body><table id="dataTable"></table></body></html>`)
+	}))
+	defer srv.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	pin := SPKIPin(srv.Certificate())
+	otherPin := "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
 
+	cases := []struct {
+		name string
+		opts *TLSOptions // nil: leave New's default
+		ok   bool
+	}{
+		{"default rejects an unknown CA", nil, false},
+		{"CA bundle", &TLSOptions{CAFile: caFile}, true},
+		{"matching pin without a bundle", &TLSOptions{Pins: []string{otherPin, pin}}, true},
+		{"wrong pin", &TLSOptions{Pins: []string{otherPin}}, false},
+		{"CA bundle and wrong pin", &TLSOptions{CAFile: caFile, Pins: []string{otherPin}}, false},
+		{"insecure", &TLSOptions{Insecure: true}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sc, err := New(1000, 1000, 0)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if tc.opts != nil {
+				if err := sc.ConfigureTLS(*tc.opts); err != nil {
+					t.Fatalf("ConfigureTLS: %v", err)
+				}
+			}
+			_, err = sc.GetDoc(srv.URL)
+			if tc.ok && err != nil {
+				t.Errorf("GetDoc failed: %v", err)
+			}
+			if !tc.ok && err == nil {
+				t.Error("GetDoc succeeded, want a certificate error")
+			}
+		})
+	}
+
+	sc, _ := New(1000, 1000, 0)
+	if err := sc.ConfigureTLS(TLSOptions{Pins: []string{"not-a-pin"}}); err == nil {
+		t.Error("malformed pin should be rejected")
+	}
+	if err := sc.ConfigureTLS(TLSOptions{CAFile: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("missing CA bundle should be rejected")
+	}
+}
+
+// TestPinIgnoresCertificatesTheServerOnlyAppends covers a MITM that
+// presents its own leaf with the real, public, pinned certificate appended:
+// the pin must match the leaf (or, with a CA bundle, the verified chain),
+// never a certificate merely sent along.
+func TestPinIgnoresCertificatesTheServerOnlyAppends(t *testing.T) {
+	ca, caKey := newTestCert(t, nil, nil, true)
+	leaf, leafKey := newTestCert(t, ca, caKey, false)
+	pinned, _ := newTestCert(t, nil, nil, true) // the portal's real certificate
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<html><body><table id="dataTable"></table></body></html>`)
+	}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{{
+		Certificate: [][]byte{leaf.Raw, pinned.Raw},
+		PrivateKey:  leafKey,
+	}}}
+	srv.StartTLS()
+	defer srv.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		opts TLSOptions
+		ok   bool
+	}{
+		{"appended pin without a bundle", TLSOptions{Pins: []string{SPKIPin(pinned)}}, false},
+		{"appended pin with a bundle", TLSOptions{CAFile: caFile, Pins: []string{SPKIPin(pinned)}}, false},
+		{"leaf pin without a bundle", TLSOptions{Pins: []string{SPKIPin(leaf)}}, true},
+		{"CA pin with a bundle", TLSOptions{CAFile: caFile, Pins: []string{SPKIPin(ca)}}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sc, err := New(1000, 1000, 0)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if err := sc.ConfigureTLS(tc.opts); err != nil {
+				t.Fatalf("ConfigureTLS: %v", err)
+			}
+			_, err = sc.GetDoc(srv.URL)
+			if tc.ok && err != nil {
+				t.Errorf("GetDoc failed: %v", err)
+			}
+			if !tc.ok && err == nil {
+				t.Error("GetDoc succeeded, want the handshake refused")
+			}
+		})
+	}
+}
+
+// newTestCert returns a certificate for 127.0.0.1 and its key, signed by
+// parent (self-signed when nil); isCA makes it a CA.
+func newTestCert(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, isCA bool) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "portal"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
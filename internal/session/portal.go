@@ -0,0 +1,91 @@
+package session
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"cmon/internal/errors"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// portalErrorBodyLimit caps how much of an error response body we read when
+// looking for a known signature. Laravel error pages are a few KB; anything
+// past this is not going to change the classification.
+const portalErrorBodyLimit = 64 << 10
+
+// portalErrorSignatures are lowercase fragments that only appear on the
+// portal's error / maintenance pages (Laravel defaults plus the IIS / nginx
+// pages the DGVCL front proxy serves when the app tier is down). Matched
+// against the page <title> for 200 responses and against the whole body for
+// 5xx responses. Ordered most-specific first so logs name the best match.
+var portalErrorSignatures = []string{
+	"be right back",
+	"under maintenance",
+	"site is under maintenance",
+	"service unavailable",
+	"server error",
+	"bad gateway",
+	"gateway timeout",
+	"whoops, looks like something went wrong",
+	"internal server error",
+}
+
+// matchPortalSignature returns the first signature contained in text, or "".
+// text is lowercased here so callers can pass raw HTML.
+func matchPortalSignature(text string) string {
+	text = strings.ToLower(text)
+	for _, sig := range portalErrorSignatures {
+		if strings.Contains(text, sig) {
+			return sig
+		}
+	}
+	return ""
+}
+
+// classifyErrorResponse inspects a non-200 response and returns a
+// PortalUnavailableError when it is one of the portal's known error pages.
+// HTTP 503 is always treated as unavailable (Laravel maintenance mode); other
+// 5xx statuses need a body signature so an unrelated upstream 500 is still
+// reported as a plain HTTP error.
+func classifyErrorResponse(rawURL string, status int, body []byte) *errors.PortalUnavailableError {
+	if status < 500 {
+		return nil
+	}
+	sig := matchPortalSignature(string(bytes.TrimSpace(body)))
+	if sig == "" && status == 503 {
+		sig = "http 503"
+	}
+	if sig == "" {
+		return nil
+	}
+	return errors.NewPortalUnavailableError("GET "+rawURL, status, sig)
+}
+
+// statusError builds the error returned for a non-200 GET. Known portal
+// error pages become a PortalUnavailableError so callers can skip retries;
+// everything else keeps the plain "returned HTTP n" message.
+func statusError(rawURL string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, portalErrorBodyLimit))
+	if perr := classifyErrorResponse(rawURL, resp.StatusCode, body); perr != nil {
+		return perr
+	}
+	return fmt.Errorf("GET %s returned HTTP %d", rawURL, resp.StatusCode)
+}
+
+// PortalErrorSignature reports whether doc is one of the portal's error or
+// maintenance pages served with HTTP 200. Only the <title> and the Laravel
+// error-page headline are checked — the dashboard itself may legitimately
+// contain words like "error" in complaint descriptions.
+func PortalErrorSignature(doc *goquery.Document) string {
+	if doc == nil {
+		return ""
+	}
+	if sig := matchPortalSignature(doc.Find("title").First().Text()); sig != "" {
+		return sig
+	}
+	return matchPortalSignature(doc.Find(".message, .title, h1").First().Text())
+}
@@ -0,0 +1,296 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// traceBodyLimit caps how much of each request/response body is kept in a
+// HAR entry. Dashboard pages are well under this; the cap only protects the
+// trace file from a pathological response.
+const traceBodyLimit = 512 << 10
+
+// redactedHeaders never reach the log or HAR file verbatim.
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+	"X-Csrf-Token":  true,
+	"X-Xsrf-Token":  true,
+}
+
+// Secret values inside bodies: JSON ("password":"…", "token":"…") and
+// URL-encoded forms (password=…&_token=…).
+var (
+	jsonSecretRe = regexp.MustCompile(`("(?:password|token|_token|access_token)"\s*:\s*)"[^"]*"`)
+	formSecretRe = regexp.MustCompile(`((?:^|&)(?:password|_token|token)=)[^&]*`)
+)
+
+// tracer records every portal request made through a Client. Each request is
+// logged; when harDir is set, entries are also collected and written as one
+// HAR 1.2 file per fetch cycle by FlushTrace, so portal behaviour changes can
+// be studied offline (e.g. in Chrome DevTools → Import HAR).
+type tracer struct {
+	base   http.RoundTripper
+	harDir string
+
+	mu      sync.Mutex
+	entries []harEntry
+}
+
+// EnableTrace turns on request/response logging for this client. harDir,
+// when non-empty, is where FlushTrace writes HAR files; it is created if
+// missing. Call once at startup before the client is shared.
+func (c *Client) EnableTrace(harDir string) error {
+	if harDir != "" {
+		if err := os.MkdirAll(harDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create HAR directory: %w", err)
+		}
+	}
+	base := c.http.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c.tracer = &tracer{base: base, harDir: harDir}
+	c.http.Transport = c.tracer
+	slog.Info("portal request tracing enabled", "har_dir", harDir)
+	return nil
+}
+
+// FlushTrace writes the requests recorded since the last flush to a HAR
+// file and clears the buffer. label is folded into the file name (e.g.
+// "cycle", "login"). Returns the path written, or "" when tracing/HAR is off
+// or nothing was recorded.
+func (c *Client) FlushTrace(label string) (string, error) {
+	if c.tracer == nil || c.tracer.harDir == "" {
+		return "", nil
+	}
+	return c.tracer.flush(label)
+}
+
+func (t *tracer) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		reqBody, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		slog.Info("portal request failed", "method", req.Method, "url", req.URL.String(), "duration", elapsed, "error", err)
+		return nil, err
+	}
+
+	var respBody []byte
+	if resp.Body != nil {
+		respBody, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slog.Info("portal request",
+		"method", req.Method,
+		"url", req.URL.String(),
+		"status", resp.StatusCode,
+		"duration", elapsed,
+		"req_bytes", len(reqBody),
+		"resp_bytes", len(respBody),
+	)
+
+	if t.harDir != "" {
+		entry := newHAREntry(req, reqBody, resp, respBody, start, elapsed)
+		t.mu.Lock()
+		t.entries = append(t.entries, entry)
+		t.mu.Unlock()
+	}
+	return resp, nil
+}
+
+func (t *tracer) flush(label string) (string, error) {
+	t.mu.Lock()
+	entries := t.entries
+	t.entries = nil
+	t.mu.Unlock()
+
+	if len(entries) == 0 {
+		return "", nil
+	}
+
+	doc := harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "cmon", Version: "1"},
+		Entries: entries,
+	}}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode HAR: %w", err)
+	}
+
+	name := fmt.Sprintf("portal-%s-%s.har", time.Now().Format("20060102-150405"), label)
+	path := filepath.Join(t.harDir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write HAR file: %w", err)
+	}
+	slog.Info("portal trace written", "path", path, "requests", len(entries))
+	return path, nil
+}
+
+// HAR 1.2 subset — only the fields DevTools and HAR viewers require.
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Headers     []harNV      `json:"headers"`
+	QueryString []harNV      `json:"queryString"`
+	Cookies     []harNV      `json:"cookies"`
+	PostData    *harPostData `json:"postData,omitempty"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int        `json:"status"`
+	StatusText  string     `json:"statusText"`
+	HTTPVersion string     `json:"httpVersion"`
+	Headers     []harNV    `json:"headers"`
+	Cookies     []harNV    `json:"cookies"`
+	Content     harContent `json:"content"`
+	RedirectURL string     `json:"redirectURL"`
+	HeadersSize int        `json:"headersSize"`
+	BodySize    int        `json:"bodySize"`
+}
+
+type harNV struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func newHAREntry(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, start time.Time, elapsed time.Duration) harEntry {
+	ms := float64(elapsed.Microseconds()) / 1000
+
+	e := harEntry{
+		StartedDateTime: start.Format(time.RFC3339Nano),
+		Time:            ms,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         req.URL.String(),
+			HTTPVersion: req.Proto,
+			Headers:     harHeaders(req.Header),
+			QueryString: []harNV{},
+			Cookies:     []harNV{},
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: harResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(resp.StatusCode),
+			HTTPVersion: resp.Proto,
+			Headers:     harHeaders(resp.Header),
+			Cookies:     []harNV{},
+			Content: harContent{
+				Size:     len(respBody),
+				MimeType: resp.Header.Get("Content-Type"),
+				Text:     redactBody(truncateBody(respBody)),
+			},
+			RedirectURL: resp.Header.Get("Location"),
+			HeadersSize: -1,
+			BodySize:    len(respBody),
+		},
+		Timings: harTimings{Wait: ms},
+	}
+	for k, vs := range req.URL.Query() {
+		for _, v := range vs {
+			e.Request.QueryString = append(e.Request.QueryString, harNV{Name: k, Value: v})
+		}
+	}
+	if len(reqBody) > 0 {
+		e.Request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     redactBody(truncateBody(reqBody)),
+		}
+	}
+	return e
+}
+
+func harHeaders(h http.Header) []harNV {
+	out := make([]harNV, 0, len(h))
+	for k, vs := range h {
+		for _, v := range vs {
+			if redactedHeaders[http.CanonicalHeaderKey(k)] {
+				v = "[redacted]"
+			}
+			out = append(out, harNV{Name: k, Value: v})
+		}
+	}
+	return out
+}
+
+func truncateBody(b []byte) string {
+	if len(b) > traceBodyLimit {
+		return string(b[:traceBodyLimit]) + "…[truncated]"
+	}
+	return string(b)
+}
+
+// redactBody masks credentials and tokens in JSON or form-encoded bodies.
+func redactBody(s string) string {
+	s = jsonSecretRe.ReplaceAllString(s, `$1"[redacted]"`)
+	return formSecretRe.ReplaceAllString(s, `${1}[redacted]`)
+}
@@ -0,0 +1,42 @@
+// Package shard splits complaint ownership between several cmon instances
+// watching the same portal account.
+//
+// Instances share no state: each hashes the complaint number and only
+// notifies for complaints whose hash falls in its slot. Ownership is a pure
+// function of the number, so it survives complaints moving between pages
+// as the portal list grows, and no complaint is ever announced twice.
+package shard
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// Shard is one slot out of Count. The zero value (and any Count <= 1) owns
+// every complaint, i.e. sharding is off.
+type Shard struct {
+	Index int // 0-based
+	Count int
+}
+
+// Enabled reports whether work is actually being split.
+func (s Shard) Enabled() bool { return s.Count > 1 }
+
+// Owns reports whether complaintNumber belongs to this shard.
+func (s Shard) Owns(complaintNumber string) bool {
+	if !s.Enabled() {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(strings.TrimSpace(complaintNumber)))
+	return int(h.Sum32()%uint32(s.Count)) == s.Index
+}
+
+// String formats the shard as "index/count" with a 1-based index for logs.
+func (s Shard) String() string {
+	if !s.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%d", s.Index+1, s.Count)
+}
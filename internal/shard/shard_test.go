@@ -0,0 +1,36 @@
+package shard
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestZeroShardOwnsEverything(t *testing.T) {
+	var s Shard
+	if s.Enabled() || !s.Owns("12345") {
+		t.Error("zero Shard should be disabled and own every complaint")
+	}
+}
+
+func TestShardsPartitionComplaints(t *testing.T) {
+	const count = 3
+	perShard := make([]int, count)
+	for n := 0; n < 3000; n++ {
+		id := fmt.Sprintf("2603%06d", n)
+		owners := 0
+		for i := 0; i < count; i++ {
+			if (Shard{Index: i, Count: count}).Owns(id) {
+				owners++
+				perShard[i]++
+			}
+		}
+		if owners != 1 {
+			t.Fatalf("complaint %s has %d owners, want exactly 1", id, owners)
+		}
+	}
+	for i, n := range perShard {
+		if n < 800 {
+			t.Errorf("shard %d owns only %d of 3000 complaints; distribution is badly skewed", i, n)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package sla
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cmon/internal/category"
+)
+
+// defaultTargets follow the supply-restoration standards of performance
+// for rural areas; set SLA_TARGETS to the regulator's figures for your
+// circle.
+var defaultTargets = map[string]time.Duration{
+	category.Transformer: 48 * time.Hour,
+	category.Line:        24 * time.Hour,
+	category.Voltage:     240 * time.Hour,
+	category.Meter:       168 * time.Hour,
+	category.Billing:     168 * time.Hour,
+	category.NoSupply:    12 * time.Hour,
+	category.Other:       72 * time.Hour,
+}
+
+// Targets are the resolution targets by category key.
+type Targets map[string]time.Duration
+
+// NewTargets returns the default targets with overrides applied (parsed
+// from SLA_TARGETS). An override for an unknown category or a
+// non-positive target is an error.
+func NewTargets(overrides map[string]time.Duration) (Targets, error) {
+	t := make(Targets, len(defaultTargets))
+	for k, d := range defaultTargets {
+		t[k] = d
+	}
+	keys := make([]string, 0, len(overrides))
+	for k := range overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !category.Known(k) {
+			return nil, fmt.Errorf("unknown SLA category %q (known: %s)", k, strings.Join(category.Keys(), ", "))
+		}
+		if overrides[k] <= 0 {
+			return nil, fmt.Errorf("SLA target for %s must be positive, got %s", k, overrides[k])
+		}
+		t[k] = overrides[k]
+	}
+	return t, nil
+}
@@ -0,0 +1,178 @@
+package sla
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"cmon/internal/category"
+	"cmon/internal/charts"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Resolution is one resolved complaint as a report counts it.
+type Resolution struct {
+	ComplaintID string
+	Category    string // category key; see category.Of
+	ResolvedAt  time.Time
+	Took        time.Duration
+}
+
+// Row is a report line: the resolutions of one category in the month.
+type Row struct {
+	Category string
+	Label    string
+	Target   time.Duration // 0 on the total row
+	Resolved int
+	Within   int // resolved within Target
+	Median   time.Duration
+	Max      time.Duration
+}
+
+// Compliance is the percentage of resolutions within target, or 0 when
+// there were none.
+func (r Row) Compliance() float64 {
+	if r.Resolved == 0 {
+		return 0
+	}
+	return 100 * float64(r.Within) / float64(r.Resolved)
+}
+
+// Report is the SLA compliance of one month.
+type Report struct {
+	Month time.Time // first instant of the month, in the report's zone
+	Rows  []Row     // one per category, in category.All order
+	Total Row
+}
+
+// BuildReport groups resolutions by category and measures each against
+// its target. Every category gets a row, so months line up in a
+// spreadsheet; the caller picks which resolutions belong to month.
+func BuildReport(month time.Time, resolutions []Resolution, targets Targets) Report {
+	took := make(map[string][]time.Duration)
+	var all []time.Duration
+	rep := Report{Month: month, Total: Row{Category: "total", Label: "All complaints"}}
+	for _, r := range resolutions {
+		key := r.Category
+		took[key] = append(took[key], r.Took)
+		all = append(all, r.Took)
+		if r.Took <= targets[key] {
+			rep.Total.Within++
+		}
+	}
+	for _, c := range category.All() {
+		row := Row{Category: c.Key, Label: c.Label, Target: targets[c.Key]}
+		for _, d := range took[c.Key] {
+			if d <= row.Target {
+				row.Within++
+			}
+		}
+		row.Resolved, row.Median, row.Max = spread(took[c.Key])
+		rep.Rows = append(rep.Rows, row)
+	}
+	rep.Total.Resolved, rep.Total.Median, rep.Total.Max = spread(all)
+	return rep
+}
+
+// spread returns the count, median and maximum of ds, sorting it.
+func spread(ds []time.Duration) (n int, median, max time.Duration) {
+	if len(ds) == 0 {
+		return 0, 0, 0
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+	return len(ds), ds[len(ds)/2], ds[len(ds)-1]
+}
+
+// lines are the category rows followed by the total.
+func (r Report) lines() []Row {
+	return append(append([]Row(nil), r.Rows...), r.Total)
+}
+
+// FileName is the export name for the report in format ("csv" or "pdf"):
+// cmon-sla-2026-09.csv.
+func (r Report) FileName(format string) string {
+	return fmt.Sprintf("cmon-sla-%s.%s", r.Month.Format("2006-01"), format)
+}
+
+// WriteCSV writes the report as CSV with durations in hours, one row per
+// category and a final total row.
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"month", "category", "target_hours", "resolved", "within_target", "compliance_percent", "median_hours", "max_hours"})
+	for _, row := range r.lines() {
+		target := ""
+		if row.Target > 0 {
+			target = hours(row.Target)
+		}
+		compliance := ""
+		if row.Resolved > 0 {
+			compliance = strconv.FormatFloat(row.Compliance(), 'f', 1, 64)
+		}
+		cw.Write([]string{
+			r.Month.Format("2006-01"), row.Category, target,
+			strconv.Itoa(row.Resolved), strconv.Itoa(row.Within), compliance,
+			hours(row.Median), hours(row.Max),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func hours(d time.Duration) string {
+	return strconv.FormatFloat(d.Hours(), 'f', 1, 64)
+}
+
+// WritePDF writes the report as a one-page A4 table.
+func (r Report) WritePDF(w io.Writer) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("SLA compliance "+r.Month.Format("January 2006"), true)
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, "SLA compliance report: "+r.Month.Format("January 2006"), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, "Complaints resolved in the month, measured from first seen to resolved.", "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	widths := []float64{50, 22, 22, 24, 24, 24, 24}
+	header := []string{"Category", "Target", "Resolved", "In target", "Compliance", "Median", "Longest"}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	for i, h := range header {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		pdf.CellFormat(widths[i], 8, h, "1", 0, align, true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 10)
+	for _, row := range r.lines() {
+		if row.Category == "total" {
+			pdf.SetFont("Helvetica", "B", 10)
+		}
+		target, compliance, median, longest := "", "-", "-", "-"
+		if row.Target > 0 {
+			target = charts.FormatDuration(row.Target)
+		}
+		if row.Resolved > 0 {
+			compliance = fmt.Sprintf("%.1f%%", row.Compliance())
+			median, longest = charts.FormatDuration(row.Median), charts.FormatDuration(row.Max)
+		}
+		cells := []string{row.Label, target, strconv.Itoa(row.Resolved), strconv.Itoa(row.Within), compliance, median, longest}
+		for i, s := range cells {
+			align := "R"
+			if i == 0 {
+				align = "L"
+			}
+			pdf.CellFormat(widths[i], 7, s, "1", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	return pdf.Output(w)
+}
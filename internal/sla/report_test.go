@@ -0,0 +1,167 @@
+package sla
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cmon/internal/category"
+	"cmon/internal/retention"
+	"cmon/internal/storage"
+)
+
+func TestNewTargets(t *testing.T) {
+	targets, err := NewTargets(map[string]time.Duration{"no_supply": 4 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if targets["no_supply"] != 4*time.Hour || targets["transformer"] != 48*time.Hour {
+		t.Errorf("targets = %v, want no_supply overridden and the rest default", targets)
+	}
+	if _, err := NewTargets(map[string]time.Duration{"sparks": time.Hour}); err == nil {
+		t.Error("an unknown category should be rejected")
+	}
+	if _, err := NewTargets(map[string]time.Duration{"meter": 0}); err == nil {
+		t.Error("a zero target should be rejected")
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	targets, _ := NewTargets(nil)
+	rep := BuildReport(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), []Resolution{
+		{Category: category.NoSupply, Took: 2 * time.Hour},
+		{Category: category.NoSupply, Took: 12 * time.Hour}, // exactly on target
+		{Category: category.NoSupply, Took: 30 * time.Hour},
+		{Category: category.Transformer, Took: 72 * time.Hour},
+	}, targets)
+
+	row := func(key string) Row {
+		for _, r := range rep.Rows {
+			if r.Category == key {
+				return r
+			}
+		}
+		t.Fatalf("no %s row", key)
+		return Row{}
+	}
+	if r := row("no_supply"); r.Resolved != 3 || r.Within != 2 || r.Median != 12*time.Hour || r.Max != 30*time.Hour {
+		t.Errorf("no_supply row = %+v", r)
+	}
+	if r := row("transformer"); r.Resolved != 1 || r.Within != 0 || r.Compliance() != 0 {
+		t.Errorf("transformer row = %+v", r)
+	}
+	if r := row("meter"); r.Resolved != 0 {
+		t.Errorf("meter row = %+v, want empty", r)
+	}
+	if len(rep.Rows) != len(category.All()) {
+		t.Errorf("%d rows, want one per category", len(rep.Rows))
+	}
+	if rep.Total.Resolved != 4 || rep.Total.Within != 2 || rep.Total.Compliance() != 50 {
+		t.Errorf("total = %+v, want 2 of 4 within target", rep.Total)
+	}
+
+	var csv bytes.Buffer
+	if err := rep.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"month,category,target_hours,resolved,within_target,compliance_percent,median_hours,max_hours\n",
+		"2026-09,no_supply,12.0,3,2,66.7,12.0,30.0\n",
+		"2026-09,meter,168.0,0,0,,0.0,0.0\n",
+		"2026-09,total,,4,2,50.0,",
+	} {
+		if !strings.Contains(csv.String(), want) {
+			t.Errorf("CSV missing %q:\n%s", want, csv.String())
+		}
+	}
+
+	var pdf bytes.Buffer
+	if err := rep.WritePDF(&pdf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(pdf.Bytes(), []byte("%PDF-")) {
+		t.Errorf("WritePDF output does not start with a PDF header")
+	}
+	if got := rep.FileName("pdf"); got != "cmon-sla-2026-09.pdf" {
+		t.Errorf("FileName = %q", got)
+	}
+}
+
+type fakeStore []storage.HistoryEntry
+
+func (s fakeStore) GetResolvedBetween(from, to time.Time) ([]storage.HistoryEntry, error) {
+	var out []storage.HistoryEntry
+	for _, e := range s {
+		if !e.ResolvedAt.Before(from) && e.ResolvedAt.Before(to) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func TestReporterMergesArchives(t *testing.T) {
+	sept := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	writeArchive := func(name string, entries []storage.HistoryEntry) {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := retention.WriteArchive(f, entries, sept); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeArchive("cmon-history-20261005-000000.tar.gz", []storage.HistoryEntry{
+		// Resolved in September, before resolution times were stored.
+		{ComplaintID: "A-1", Description: "no supply", FirstSeenAt: sept.Add(24 * time.Hour), ResolvedAt: sept.Add(26 * time.Hour)},
+		{ComplaintID: "DB-1", Description: "no supply", ResolvedAt: sept.Add(time.Hour), ResolutionTime: time.Hour},
+		{ComplaintID: "A-AUG", Description: "meter", ResolvedAt: sept.Add(-time.Hour)},
+	})
+	// Named before September: cannot hold its resolutions, so not read.
+	writeArchive("cmon-history-20260801-000000.tar.gz", []storage.HistoryEntry{
+		{ComplaintID: "A-STALE", Description: "no supply", ResolvedAt: sept.Add(time.Hour)},
+	})
+
+	r := &Reporter{
+		Store: fakeStore{
+			{ComplaintID: "DB-1", Category: category.NoSupply, Description: "meter sparking", ResolvedAt: sept.Add(time.Hour), ResolutionTime: 20 * time.Hour},
+			{ComplaintID: "DB-OCT", Description: "no supply", ResolvedAt: sept.AddDate(0, 1, 0)},
+		},
+		ArchiveDir: dir,
+		Targets:    Targets{"no_supply": 12 * time.Hour},
+		now:        func() time.Time { return time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC) },
+	}
+	month, err := r.Month("")
+	if err != nil || !month.Equal(sept) {
+		t.Fatalf("Month(\"\") = %v, %v; want September", month, err)
+	}
+	rep, err := r.Build(month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A-1 within target (2h); DB-1 from the database (20h) over it.
+	if rep.Total.Resolved != 2 || rep.Total.Within != 1 {
+		t.Errorf("total = %+v, want A-1 and DB-1 with one within target", rep.Total)
+	}
+
+	if _, err := r.Month("Sept"); err == nil {
+		t.Error("Month should reject a malformed month")
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/reports/sla?month=2026-09", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Header().Get("Content-Disposition"), "cmon-sla-2026-09.csv") {
+		t.Errorf("GET /reports/sla = %d, %q", rec.Code, rec.Header().Get("Content-Disposition"))
+	}
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/reports/sla?format=xls", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown format = %d, want 400", rec.Code)
+	}
+}
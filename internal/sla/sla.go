@@ -0,0 +1,87 @@
+// Package sla measures how long complaints take to resolve. A Tracker
+// subscribes to the event bus and, for every resolution, adds the time
+// since the complaint was first seen to the /metrics totals, counting it
+// as overdue past its category's target, or for an uncategorised one the
+// same AGE_OVERDUE_AFTER that tints summary rows red.
+//
+// Reports look back instead: a Reporter takes a month of resolutions from
+// the history table and retention archives, groups them by category (no
+// supply, transformer failure, ...) and measures each against its
+// regulatory target, exported as CSV or PDF.
+package sla
+
+import (
+	"log"
+	"time"
+
+	"cmon/internal/eventbus"
+	"cmon/internal/metrics"
+)
+
+// Tracker times resolutions against Target, or the target of the
+// complaint's category.
+type Tracker struct {
+	// Target is how long a complaint may stay open before its resolution
+	// counts as overdue; 0 counts none as overdue.
+	Target time.Duration
+
+	// Targets, with Category, measure a categorised complaint against its
+	// category's target instead (SLA_TARGETS); complaints stored without
+	// a category keep Target.
+	Targets Targets
+	// Category looks up a complaint's stored category (storage's
+	// GetCategory).
+	Category func(complaintID string) string
+
+	// FirstSeen looks up when a complaint was first seen (storage's
+	// GetFirstSeenAt). ComplaintResolved is published before the
+	// complaint leaves storage, so it is still there.
+	FirstSeen func(complaintID string) (time.Time, bool)
+
+	now func() time.Time
+}
+
+// Handle is the Tracker's eventbus.Handler.
+func (t *Tracker) Handle(e eventbus.Event) error {
+	r, ok := e.(eventbus.ComplaintResolved)
+	if !ok {
+		return nil
+	}
+	metrics.ComplaintsResolvedTotal.Inc()
+	first, ok := t.FirstSeen(r.Status.ComplaintID)
+	if !ok {
+		return nil
+	}
+	resolvedAt := r.Status.Time
+	if resolvedAt.IsZero() {
+		resolvedAt = t.clock()
+	}
+	took := resolvedAt.Sub(first)
+	if took < 0 {
+		return nil
+	}
+	metrics.ResolutionSecondsTotal.Add(uint64(took.Seconds()))
+	if target := t.target(r.Status.ComplaintID); target > 0 && took > target {
+		metrics.ComplaintsResolvedOverdueTotal.Inc()
+		log.Printf("⏰ Complaint %s resolved after %s, past the %s target", r.Status.ComplaintID, took.Round(time.Minute), target)
+	}
+	return nil
+}
+
+// target is the overdue threshold of complaintID.
+func (t *Tracker) target(complaintID string) time.Duration {
+	if t.Targets == nil || t.Category == nil {
+		return t.Target
+	}
+	if d, ok := t.Targets[t.Category(complaintID)]; ok {
+		return d
+	}
+	return t.Target
+}
+
+func (t *Tracker) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,78 @@
+package sla
+
+import (
+	"testing"
+	"time"
+
+	"cmon/internal/category"
+	"cmon/internal/eventbus"
+	"cmon/internal/metrics"
+	"cmon/internal/notify"
+)
+
+func TestTrackerTimesResolutions(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	seen := map[string]time.Time{
+		"C-1": now.Add(-2 * time.Hour),
+		"C-2": now.Add(-80 * time.Hour),
+	}
+	tr := &Tracker{
+		Target: 72 * time.Hour,
+		FirstSeen: func(id string) (time.Time, bool) {
+			at, ok := seen[id]
+			return at, ok
+		},
+		now: func() time.Time { return now },
+	}
+
+	resolved := metrics.ComplaintsResolvedTotal.Value()
+	overdue := metrics.ComplaintsResolvedOverdueTotal.Value()
+	seconds := metrics.ResolutionSecondsTotal.Value()
+
+	for _, e := range []eventbus.Event{
+		eventbus.ComplaintResolved{Status: notify.Status{ComplaintID: "C-1", Time: now}},
+		eventbus.ComplaintResolved{Status: notify.Status{ComplaintID: "C-2"}}, // zero Time: now
+		eventbus.ComplaintResolved{Status: notify.Status{ComplaintID: "C-3"}}, // never stored
+		eventbus.AlertRaised{},
+	} {
+		if err := tr.Handle(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := metrics.ComplaintsResolvedTotal.Value() - resolved; got != 3 {
+		t.Errorf("resolved += %d, want 3", got)
+	}
+	if got := metrics.ComplaintsResolvedOverdueTotal.Value() - overdue; got != 1 {
+		t.Errorf("overdue += %d, want 1 (C-2)", got)
+	}
+	if got, want := metrics.ResolutionSecondsTotal.Value()-seconds, uint64((82 * time.Hour).Seconds()); got != want {
+		t.Errorf("resolution seconds += %d, want %d", got, want)
+	}
+}
+
+func TestTrackerUsesCategoryTargets(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	tr := &Tracker{
+		Target:  72 * time.Hour,
+		Targets: Targets{category.NoSupply: 4 * time.Hour},
+		Category: func(id string) string {
+			if id == "C-1" {
+				return category.NoSupply
+			}
+			return ""
+		},
+		FirstSeen: func(string) (time.Time, bool) { return now.Add(-6 * time.Hour), true },
+		now:       func() time.Time { return now },
+	}
+
+	overdue := metrics.ComplaintsResolvedOverdueTotal.Value()
+	for _, id := range []string{"C-1", "C-2"} {
+		if err := tr.Handle(eventbus.ComplaintResolved{Status: notify.Status{ComplaintID: id}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := metrics.ComplaintsResolvedOverdueTotal.Value() - overdue; got != 1 {
+		t.Errorf("overdue += %d, want 1 (C-1 past its 4h no-supply target; C-2 uncategorised, within 72h)", got)
+	}
+}
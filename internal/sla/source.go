@@ -0,0 +1,167 @@
+package sla
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cmon/internal/category"
+	"cmon/internal/retention"
+	"cmon/internal/storage"
+)
+
+// Store is the subset of *storage.Storage reports read.
+type Store interface {
+	GetResolvedBetween(from, to time.Time) ([]storage.HistoryEntry, error)
+}
+
+// Reporter builds monthly reports from the history table and, for months
+// retention has already cleared from it, the archives in ArchiveDir.
+type Reporter struct {
+	Store      Store
+	ArchiveDir string // RETENTION_EXPORT_DIR; empty reads the database only
+	Targets    Targets
+	Location   *time.Location // months start at midnight here; nil is UTC
+
+	now func() time.Time
+}
+
+// Month returns the first instant of the month s ("2006-01") names, or of
+// last month when s is empty — the month a report is usually wanted for.
+func (r *Reporter) Month(s string) (time.Time, error) {
+	loc := r.location()
+	if s = strings.TrimSpace(s); s == "" {
+		now := r.clock().In(loc)
+		return time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, loc), nil
+	}
+	m, err := time.ParseInLocation("2006-01", s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("month %q: want YYYY-MM", s)
+	}
+	return m, nil
+}
+
+// Build reports on the complaints resolved in month.
+func (r *Reporter) Build(month time.Time) (Report, error) {
+	from := month
+	to := from.AddDate(0, 1, 0)
+	entries, err := r.Store.GetResolvedBetween(from, to)
+	if err != nil {
+		return Report{}, fmt.Errorf("load resolved history: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var res []Resolution
+	for _, e := range entries {
+		seen[e.ComplaintID] = true
+		res = append(res, resolution(e.ComplaintID, category.Of(e.Category, e.Description), e.FirstSeenAt, e.ResolvedAt, e.ResolutionTime))
+	}
+	for _, rec := range r.archived(from, to) {
+		if seen[rec.ComplaintID] {
+			continue
+		}
+		seen[rec.ComplaintID] = true
+		res = append(res, resolution(rec.ComplaintID, category.Of(rec.Category, rec.Description), rec.FirstSeenAt, rec.ResolvedAt, time.Duration(rec.ResolutionSeconds)*time.Second))
+	}
+	return BuildReport(month, res, r.Targets), nil
+}
+
+// resolution falls back to resolvedAt − firstSeen for rows resolved
+// before resolution times were stored.
+func resolution(id, cat string, firstSeen, resolvedAt time.Time, took time.Duration) Resolution {
+	if took == 0 && !firstSeen.IsZero() && resolvedAt.After(firstSeen) {
+		took = resolvedAt.Sub(firstSeen)
+	}
+	return Resolution{ComplaintID: id, Category: cat, ResolvedAt: resolvedAt, Took: took}
+}
+
+// archived returns the archived records resolved in [from, to). Archives
+// named for a time before from only hold older resolutions and are
+// skipped; one that cannot be read is logged and left out.
+func (r *Reporter) archived(from, to time.Time) []retention.Record {
+	if r.ArchiveDir == "" {
+		return nil
+	}
+	paths, _ := filepath.Glob(filepath.Join(r.ArchiveDir, "cmon-history-*.tar.gz"))
+	var out []retention.Record
+	for _, path := range paths {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "cmon-history-"), ".tar.gz")
+		if at, err := time.Parse("20060102-150405", stamp); err == nil && at.Before(from) {
+			continue
+		}
+		records, err := retention.ReadArchive(path)
+		if err != nil {
+			log.Printf("⚠️  SLA report: skipping archive %s: %v", path, err)
+			continue
+		}
+		for _, rec := range records {
+			if !rec.ResolvedAt.Before(from) && rec.ResolvedAt.Before(to) {
+				out = append(out, rec)
+			}
+		}
+	}
+	return out
+}
+
+// ServeHTTP serves /reports/sla?month=2026-09&format=pdf as a download;
+// month defaults to last month and format to csv.
+func (r *Reporter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	month, err := r.Month(req.URL.Query().Get("month"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	format := req.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "pdf" {
+		http.Error(w, "format must be csv or pdf", http.StatusBadRequest)
+		return
+	}
+
+	rep, err := r.Build(month)
+	if err != nil {
+		log.Printf("⚠️  SLA report: %v", err)
+		http.Error(w, "failed to build report", http.StatusInternalServerError)
+		return
+	}
+	var buf bytes.Buffer
+	contentType := "text/csv; charset=utf-8"
+	if format == "pdf" {
+		contentType = "application/pdf"
+		err = rep.WritePDF(&buf)
+	} else {
+		err = rep.WriteCSV(&buf)
+	}
+	if err != nil {
+		log.Printf("⚠️  SLA report: %v", err)
+		http.Error(w, "failed to write report", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", rep.FileName(format)))
+	w.Write(buf.Bytes())
+}
+
+func (r *Reporter) location() *time.Location {
+	if r.Location != nil {
+		return r.Location
+	}
+	return time.UTC
+}
+
+func (r *Reporter) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
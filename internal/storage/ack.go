@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Unseen is a notified complaint nobody has acknowledged yet.
+type Unseen struct {
+	ComplaintID  string
+	MessageID    string // Telegram message ID
+	ConsumerName string
+	Village      string
+	Belt         string
+	Category     string
+	NotifiedAt   time.Time
+}
+
+// markNotified stamps notified_at the first time a complaint gets a Telegram
+// message ID. Complaints notified before this column existed keep NULL and
+// are never picked up by the unseen reminder.
+func (s *Storage) markNotified(complaintID string) error {
+	_, err := s.db.Exec(`
+		UPDATE complaints SET notified_at = ?
+		WHERE complaint_id = ? AND notified_at IS NULL
+	`, historyNow().UTC().Format(historyTimeLayout), complaintID)
+	return err
+}
+
+// MarkAcknowledged records that by (a Telegram user's display name, byID
+// their user ID) has seen the complaint. Returns false when the complaint
+// is unknown or was already acknowledged — the first acknowledgment wins.
+func (s *Storage) MarkAcknowledged(complaintID, by string, byID int64) (bool, error) {
+	res, err := s.db.Exec(`
+		UPDATE complaints SET acknowledged_at = ?, acknowledged_by = ?, acknowledged_by_id = ?
+		WHERE complaint_id = ? AND acknowledged_at IS NULL
+	`, historyNow().UTC().Format(historyTimeLayout), by, byID, complaintID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// GetAcknowledgment returns who acknowledged complaintID. byID is 0 for
+// acknowledgments recorded before user IDs were kept. ok=false while
+// nobody has.
+func (s *Storage) GetAcknowledgment(complaintID string) (by string, byID int64, ok bool) {
+	var name sql.NullString
+	var id sql.NullInt64
+	err := s.db.QueryRow(`
+		SELECT acknowledged_by, acknowledged_by_id FROM complaints
+		WHERE complaint_id = ? AND acknowledged_at IS NOT NULL
+	`, complaintID).Scan(&name, &id)
+	if err != nil {
+		return "", 0, false
+	}
+	return name.String, id.Int64, true
+}
+
+// GetComplaintIDByMessageID does a reverse lookup from Telegram message ID to
+// complaint ID. Used for reaction updates, which only carry the message ID.
+func (s *Storage) GetComplaintIDByMessageID(messageID string) (string, bool) {
+	if messageID == "" {
+		return "", false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for id, mid := range s.messageIDs {
+		if mid == messageID {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// GetUnacknowledged returns complaints notified at or before notifiedBefore
+// that nobody has acknowledged and that have not been surfaced in an unseen
+// reminder yet, oldest first.
+func (s *Storage) GetUnacknowledged(notifiedBefore time.Time) ([]Unseen, error) {
+	rows, err := s.db.Query(`
+		SELECT complaint_id, tg_message_id, consumer_name, village, belt, category, notified_at
+		FROM complaints
+		WHERE notified_at IS NOT NULL AND notified_at <= ?
+			AND acknowledged_at IS NULL AND unseen_reminded_at IS NULL
+		ORDER BY notified_at, complaint_id
+	`, notifiedBefore.UTC().Format(historyTimeLayout))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Unseen
+	for rows.Next() {
+		var u Unseen
+		var messageID, name, village, belt, category, notified sql.NullString
+		if err := rows.Scan(&u.ComplaintID, &messageID, &name, &village, &belt, &category, &notified); err != nil {
+			return nil, err
+		}
+		u.MessageID = messageID.String
+		u.ConsumerName = s.reveal(name.String)
+		u.Village = village.String
+		u.Belt = belt.String
+		u.Category = category.String
+		u.NotifiedAt = parseHistoryTime(notified.String)
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// MarkUnseenReminded records that complaintIDs were included in an unseen
+// reminder, so each complaint is re-surfaced at most once.
+func (s *Storage) MarkUnseenReminded(complaintIDs []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	now := historyNow().UTC().Format(historyTimeLayout)
+	for _, id := range complaintIDs {
+		if _, err := tx.Exec(`UPDATE complaints SET unseen_reminded_at = ? WHERE complaint_id = ?`, now, id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
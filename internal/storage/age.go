@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Aging is a pending complaint whose Telegram message can carry a
+// "⏳ pending N days" footer.
+type Aging struct {
+	ComplaintID    string
+	MessageID      string // Telegram message ID
+	Text           string // message text as sent, without a footer
+	Belt           string
+	Category       string
+	AcknowledgedBy string
+	FirstSeenAt    time.Time
+	FooterDays     int // days the current footer shows; 0 = none
+}
+
+// GetFirstSeen returns when each stored complaint was first saved, keyed by
+// complaint ID.
+func (s *Storage) GetFirstSeen() (map[string]time.Time, error) {
+	rows, err := s.db.Query(`SELECT complaint_id, created_at FROM complaints WHERE created_at IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string]time.Time)
+	for rows.Next() {
+		var id string
+		var created sql.NullString
+		if err := rows.Scan(&id, &created); err != nil {
+			return nil, err
+		}
+		if t := parseHistoryTime(created.String); !t.IsZero() {
+			out[id] = t
+		}
+	}
+	return out, rows.Err()
+}
+
+// GetFirstSeenAt returns when complaintID was first saved, reporting false
+// for complaints not in storage.
+func (s *Storage) GetFirstSeenAt(complaintID string) (time.Time, bool) {
+	var created sql.NullString
+	if err := s.db.QueryRow(`SELECT created_at FROM complaints WHERE complaint_id = ?`, complaintID).Scan(&created); err != nil {
+		return time.Time{}, false
+	}
+	t := parseHistoryTime(created.String)
+	return t, !t.IsZero()
+}
+
+// SetMessageText keeps the text of a complaint's Telegram message, so the
+// age footer can be added by editing it later.
+func (s *Storage) SetMessageText(complaintID, text string) error {
+	_, err := s.db.Exec(`UPDATE complaints SET tg_message_text = ?, age_footer_days = 0 WHERE complaint_id = ?`, s.seal(text), complaintID)
+	return err
+}
+
+// GetMessageText returns the text SetMessageText kept for complaintID and
+// the days its age footer shows. ok=false when no text is stored.
+func (s *Storage) GetMessageText(complaintID string) (text string, footerDays int, ok bool) {
+	var stored sql.NullString
+	var days sql.NullInt64
+	err := s.db.QueryRow(`
+		SELECT tg_message_text, age_footer_days FROM complaints WHERE complaint_id = ?
+	`, complaintID).Scan(&stored, &days)
+	if err != nil || !stored.Valid {
+		return "", 0, false
+	}
+	return s.reveal(stored.String), int(days.Int64), true
+}
+
+// GetAging returns the complaints whose Telegram message text is known,
+// oldest first.
+func (s *Storage) GetAging() ([]Aging, error) {
+	rows, err := s.db.Query(`
+		SELECT complaint_id, tg_message_id, tg_message_text, belt, category, acknowledged_by, created_at, age_footer_days
+		FROM complaints
+		WHERE tg_message_id IS NOT NULL AND tg_message_id != '' AND tg_message_text IS NOT NULL
+		ORDER BY created_at, complaint_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Aging
+	for rows.Next() {
+		var a Aging
+		var text, belt, category, ackBy, created sql.NullString
+		var days sql.NullInt64
+		if err := rows.Scan(&a.ComplaintID, &a.MessageID, &text, &belt, &category, &ackBy, &created, &days); err != nil {
+			return nil, err
+		}
+		a.Text = s.reveal(text.String)
+		a.Belt = belt.String
+		a.Category = category.String
+		a.AcknowledgedBy = ackBy.String
+		a.FirstSeenAt = parseHistoryTime(created.String)
+		a.FooterDays = int(days.Int64)
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// SetAgeFooterDays records the day count the complaint's message footer
+// now shows.
+func (s *Storage) SetAgeFooterDays(complaintID string, days int) error {
+	_, err := s.db.Exec(`UPDATE complaints SET age_footer_days = ? WHERE complaint_id = ?`, days, complaintID)
+	return err
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"sync"
+	"time"
+)
+
+// Batcher is a write-behind buffer in front of SaveMultiple. Records are
+// held until size of them are buffered or the oldest has waited interval,
+// then written in one transaction. Each Add carries an afterSave callback
+// that runs once its records are durable, so side effects such as
+// notifications still never get ahead of the database.
+//
+// There is no background timer: the interval is checked on Add, and the
+// owner must Flush when it is done (the fetcher does at the end of every
+// cycle, so nothing stays buffered across cycles or past shutdown).
+type Batcher struct {
+	stor     *Storage
+	size     int
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending []Record
+	after   []func()
+	oldest  time.Time
+}
+
+// batchNow is swapped by tests to drive the flush interval.
+var batchNow = time.Now
+
+// NewBatcher returns a Batcher writing to s. size <= 1 writes every Add
+// straight through; interval <= 0 only flushes on size and Flush.
+func (s *Storage) NewBatcher(size int, interval time.Duration) *Batcher {
+	return &Batcher{stor: s, size: size, interval: interval}
+}
+
+// Add buffers records and flushes when a trigger fires. afterSave (may be
+// nil) runs after the flush that writes them, with the Batcher locked, so
+// it must not call back into it. On a failed flush the batch is dropped
+// and afterSave never runs; the caller sees the returned error.
+func (b *Batcher) Add(records []Record, afterSave func()) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.pending) == 0 {
+		b.oldest = batchNow()
+	}
+	b.pending = append(b.pending, records...)
+	if afterSave != nil {
+		b.after = append(b.after, afterSave)
+	}
+	if len(b.pending) >= b.size || (b.interval > 0 && batchNow().Sub(b.oldest) >= b.interval) {
+		return b.flushLocked()
+	}
+	return nil
+}
+
+// Buffered reports whether complaintID is waiting to be written.
+func (b *Batcher) Buffered(complaintID string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, r := range b.pending {
+		if r.ComplaintID == complaintID {
+			return true
+		}
+	}
+	return false
+}
+
+// Flush writes everything buffered and runs the waiting callbacks.
+func (b *Batcher) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.flushLocked()
+}
+
+func (b *Batcher) flushLocked() error {
+	records, after := b.pending, b.after
+	b.pending, b.after = nil, nil
+	if len(records) > 0 {
+		if err := b.stor.SaveMultiple(records); err != nil {
+			return err
+		}
+	}
+	for _, fn := range after {
+		fn()
+	}
+	return nil
+}
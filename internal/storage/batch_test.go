@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBatcherFlushesOnSizeIntervalAndFlush(t *testing.T) {
+	withTempCWD(t)
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	batchNow = func() time.Time { return now }
+	t.Cleanup(func() { batchNow = time.Now })
+
+	var announced []string
+	after := func(id string) func() {
+		return func() {
+			if !stor.Exists(id) {
+				t.Errorf("%s announced before it was saved", id)
+			}
+			announced = append(announced, id)
+		}
+	}
+	b := stor.NewBatcher(3, time.Minute)
+
+	// Below the size and inside the interval: buffered only.
+	b.Add([]Record{{ComplaintID: "A"}}, after("A"))
+	b.Add([]Record{{ComplaintID: "B"}}, after("B"))
+	if !stor.IsNew("A") || !b.Buffered("A") || len(announced) != 0 {
+		t.Fatalf("A should be buffered, not saved (announced %v)", announced)
+	}
+
+	// Size reached.
+	if err := b.Add([]Record{{ComplaintID: "C"}}, after("C")); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if len(announced) != 3 || b.Buffered("A") {
+		t.Fatalf("size flush: announced %v", announced)
+	}
+
+	// Interval elapsed since the oldest buffered record.
+	b.Add([]Record{{ComplaintID: "D"}}, after("D"))
+	now = now.Add(time.Minute)
+	b.Add([]Record{{ComplaintID: "E"}}, after("E"))
+	if len(announced) != 5 {
+		t.Fatalf("interval flush: announced %v", announced)
+	}
+
+	// Explicit flush of the remainder.
+	b.Add([]Record{{ComplaintID: "F"}}, after("F"))
+	if err := b.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if len(announced) != 6 || announced[5] != "F" {
+		t.Errorf("final flush: announced %v", announced)
+	}
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Conversation is one Telegram user's place in a multi-step flow, such as
+// resolving a complaint: which flow and state they are in, the complaint it
+// is about, and the bot prompt they are expected to answer. A user has at
+// most one conversation at a time.
+type Conversation struct {
+	Flow            string
+	State           string
+	ComplaintNumber string
+	MessageID       string // the complaint's Telegram message
+	OriginalText    string // its text when the flow started
+	PromptMessageID int
+	// Data holds flow-specific values between steps.
+	Data      map[string]string
+	UpdatedAt time.Time
+}
+
+// GetConversation returns userID's conversation, if any.
+func (s *Storage) GetConversation(userID int64) (Conversation, bool) {
+	var conv Conversation
+	var complaintID, messageID, originalText, data, updated sql.NullString
+	var promptID sql.NullInt64
+	err := s.db.QueryRow(`
+		SELECT flow, state, complaint_id, message_id, original_text, prompt_message_id, data, updated_at
+		FROM conversations
+		WHERE user_id = ?
+	`, userID).Scan(&conv.Flow, &conv.State, &complaintID, &messageID, &originalText, &promptID, &data, &updated)
+	if err == sql.ErrNoRows {
+		return conv, false
+	} else if err != nil {
+		log.Printf("⚠️  Failed to query conversation for user %d: %v", userID, err)
+		return conv, false
+	}
+	conv.ComplaintNumber = complaintID.String
+	conv.MessageID = messageID.String
+	conv.OriginalText = s.reveal(originalText.String)
+	conv.PromptMessageID = int(promptID.Int64)
+	conv.UpdatedAt = parseHistoryTime(updated.String)
+	if data.String != "" {
+		if err := json.Unmarshal([]byte(data.String), &conv.Data); err != nil {
+			log.Printf("⚠️  Ignoring unreadable conversation data for user %d: %v", userID, err)
+		}
+	}
+	return conv, true
+}
+
+// SaveConversation inserts or replaces userID's conversation. A zero
+// UpdatedAt is stored as now.
+func (s *Storage) SaveConversation(userID int64, conv Conversation) error {
+	if conv.UpdatedAt.IsZero() {
+		conv.UpdatedAt = time.Now()
+	}
+	var data any
+	if len(conv.Data) > 0 {
+		b, err := json.Marshal(conv.Data)
+		if err != nil {
+			return err
+		}
+		data = string(b)
+	}
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO conversations (user_id, flow, state, complaint_id, message_id, original_text, prompt_message_id, data, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, userID, conv.Flow, conv.State, conv.ComplaintNumber, conv.MessageID, s.seal(conv.OriginalText), conv.PromptMessageID, data,
+		conv.UpdatedAt.UTC().Format(historyTimeLayout))
+	if err != nil {
+		log.Printf("⚠️  Failed to save conversation for user %d: %v", userID, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteConversation ends userID's conversation.
+func (s *Storage) DeleteConversation(userID int64) {
+	if _, err := s.db.Exec(`DELETE FROM conversations WHERE user_id = ?`, userID); err != nil {
+		log.Printf("⚠️  Failed to delete conversation for user %d: %v", userID, err)
+	}
+}
+
+// GetIdleConversations returns the conversations last updated before
+// cutoff, by user ID.
+func (s *Storage) GetIdleConversations(cutoff time.Time) (map[int64]Conversation, error) {
+	rows, err := s.db.Query(`SELECT user_id FROM conversations WHERE updated_at < ?`, cutoff.UTC().Format(historyTimeLayout))
+	if err != nil {
+		return nil, err
+	}
+	var users []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		users = append(users, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make(map[int64]Conversation, len(users))
+	for _, id := range users {
+		if conv, ok := s.GetConversation(id); ok {
+			out[id] = conv
+		}
+	}
+	return out, nil
+}
+
+// migratePendingResolutions moves rows from the pending_resolutions table,
+// which conversations replaced, into conversations as resolve flows
+// awaiting a typed note, then drops it.
+func (s *Storage) migratePendingResolutions() error {
+	var n int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'pending_resolutions'`).Scan(&n); err != nil {
+		return fmt.Errorf("check pending_resolutions: %w", err)
+	}
+	if n == 0 {
+		return nil
+	}
+	if _, err := s.db.Exec(`
+		INSERT OR IGNORE INTO conversations (user_id, flow, state, complaint_id, message_id, original_text, prompt_message_id, updated_at)
+		SELECT user_id, 'resolve', 'note', complaint_id, message_id, original_text, prompt_message_id, COALESCE(created_at, CURRENT_TIMESTAMP)
+		FROM pending_resolutions
+	`); err != nil {
+		return fmt.Errorf("migrate pending_resolutions: %w", err)
+	}
+	if _, err := s.db.Exec(`DROP TABLE pending_resolutions`); err != nil {
+		return fmt.Errorf("drop pending_resolutions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Encryption at rest. With a key, the columns holding consumer PII —
+// consumer_name, mobile_no and address, the Telegram message text (also
+// kept as a conversation's original_text), plus the complaint JSON kept in
+// dead_letters, journal and deferred_sends payloads —
+// are sealed with AES-256-GCM before they reach SQLite and opened again on
+// read, so a copied cmon.db or backup
+// does not expose customer details. consumer_no stays in the clear: repeat
+// detection looks it up by value.
+//
+// Sealed values are "enc:v1:" + base64(nonce || ciphertext). Values without
+// the prefix are read as plaintext, so rows written before a key was set
+// keep working; encryptExisting seals them on the first start with a key.
+
+// EncryptionKeySize is the key length NewEncrypted requires (AES-256).
+const EncryptionKeySize = 32
+
+const (
+	sealedPrefix = "enc:v1:"
+
+	// encryptionCheckKey is the settings row holding a sealed known value,
+	// used to tell a wrong key (or a missing one) from a good one at startup.
+	encryptionCheckKey   = "encryption_check"
+	encryptionCheckValue = "cmon"
+)
+
+// ErrEncryptionKey is returned by NewEncrypted when the database was
+// encrypted with a different key, or is encrypted and no key was given.
+var ErrEncryptionKey = errors.New("storage encryption key does not match the database")
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != EncryptionKeySize {
+		return nil, fmt.Errorf("storage encryption key must be %d bytes, got %d", EncryptionKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts v for storage. Empty values stay empty so the "non-empty
+// wins" upserts keep working, and without a key v is returned unchanged.
+func (s *Storage) seal(v string) string {
+	if s.aead == nil || v == "" || strings.HasPrefix(v, sealedPrefix) {
+		return v
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		// crypto/rand does not fail on supported platforms; never fall
+		// back to writing plaintext.
+		panic(fmt.Sprintf("storage: read random nonce: %v", err))
+	}
+	return sealedPrefix + base64.StdEncoding.EncodeToString(s.aead.Seal(nonce, nonce, []byte(v), nil))
+}
+
+// Seal encrypts v as the PII columns are, for revealed values copied out
+// of the database such as retention archives. Without a key v is returned
+// unchanged.
+func (s *Storage) Seal(v string) string {
+	return s.seal(v)
+}
+
+// unseal decrypts a value written by seal; plaintext passes through.
+func (s *Storage) unseal(v string) (string, error) {
+	if !strings.HasPrefix(v, sealedPrefix) {
+		return v, nil
+	}
+	if s.aead == nil {
+		return "", ErrEncryptionKey
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(v, sealedPrefix))
+	if err != nil || len(raw) < s.aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+	n := s.aead.NonceSize()
+	plain, err := s.aead.Open(nil, raw[:n], raw[n:], nil)
+	if err != nil {
+		return "", ErrEncryptionKey
+	}
+	return string(plain), nil
+}
+
+// reveal is unseal for read paths that cannot fail: a value that does not
+// decrypt is logged and read as empty rather than shown as ciphertext.
+func (s *Storage) reveal(v string) string {
+	plain, err := s.unseal(v)
+	if err != nil {
+		log.Printf("⚠️  Failed to decrypt a stored value: %v", err)
+		return ""
+	}
+	return plain
+}
+
+// checkEncryptionKey compares the configured key with the one the database
+// was encrypted with, recording it on first use. A database that was never
+// encrypted opens with or without a key.
+func (s *Storage) checkEncryptionKey() error {
+	var check string
+	err := s.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, encryptionCheckKey).Scan(&check)
+	switch {
+	case err == sql.ErrNoRows:
+		if s.aead == nil {
+			return nil
+		}
+		_, err = s.db.Exec(`INSERT INTO settings (key, value) VALUES (?, ?)`, encryptionCheckKey, s.seal(encryptionCheckValue))
+		return err
+	case err != nil:
+		return err
+	}
+	if s.aead == nil {
+		return fmt.Errorf("%w: the database is encrypted; set STORAGE_ENCRYPTION_KEY", ErrEncryptionKey)
+	}
+	if plain, err := s.unseal(check); err != nil || plain != encryptionCheckValue {
+		return ErrEncryptionKey
+	}
+	return nil
+}
+
+// sealedColumns lists the PII columns encryptExisting seals, per table.
+var sealedColumns = []struct {
+	table, key string
+	columns    []string
+}{
+	{"complaints", "complaint_id", []string{"consumer_name", "mobile_no", "address", "tg_message_text"}},
+	{"complaint_history", "complaint_id", []string{"consumer_name"}},
+	{"dead_letters", "complaint_id", []string{"payload"}},
+	{"journal", "id", []string{"payload"}},
+	{"deferred_sends", "complaint_id", []string{"payload"}},
+	{"conversations", "user_id", []string{"original_text"}},
+}
+
+// encryptExisting seals PII still stored in the clear, e.g. rows written
+// before a key was configured. Already sealed values are skipped, so after
+// the first run this only reads.
+func (s *Storage) encryptExisting() error {
+	if s.aead == nil {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	total := 0
+	for _, t := range sealedColumns {
+		for _, col := range t.columns {
+			rows, err := tx.Query(fmt.Sprintf(`SELECT %s, %s FROM %s WHERE %s != '' AND %s NOT LIKE '%s%%'`,
+				t.key, col, t.table, col, col, sealedPrefix))
+			if err != nil {
+				return err
+			}
+			type row struct{ key, value string }
+			var plain []row
+			for rows.Next() {
+				var r row
+				if err := rows.Scan(&r.key, &r.value); err != nil {
+					rows.Close()
+					return err
+				}
+				plain = append(plain, r)
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			for _, r := range plain {
+				if _, err := tx.Exec(fmt.Sprintf(`UPDATE %s SET %s = ? WHERE %s = ?`, t.table, col, t.key), s.seal(r.value), r.key); err != nil {
+					return err
+				}
+			}
+			total += len(plain)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if total > 0 {
+		log.Printf("🔐 Encrypted %d stored values that were in the clear", total)
+	}
+	return nil
+}
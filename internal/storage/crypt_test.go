@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncryptionAtRest(t *testing.T) {
+	withTempCWD(t)
+	key := bytes.Repeat([]byte{7}, EncryptionKeySize)
+
+	// A row written before encryption was enabled.
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "CMP-1", ConsumerName: "Asha Patel", MobileNo: "9876543210", Address: "Near temple", ConsumerNo: "11223"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := stor.SaveConversation(1, Conversation{Flow: "resolve", State: "note", OriginalText: "Asha Patel, Near temple"}); err != nil {
+		t.Fatalf("SaveConversation: %v", err)
+	}
+	stor.Close()
+
+	stor, err = NewEncrypted(key)
+	if err != nil {
+		t.Fatalf("NewEncrypted: %v", err)
+	}
+	if err := stor.SaveConversation(2, Conversation{Flow: "resolve", State: "note", OriginalText: "Ravi Shah 9123456780"}); err != nil {
+		t.Fatalf("SaveConversation: %v", err)
+	}
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "CMP-2", ConsumerName: "Ravi Shah", MobileNo: "9123456780"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := stor.RecordFailure("CMP-2", "", StageNotify, "boom", `{"name":"Ravi Shah"}`); err != nil {
+		t.Fatalf("RecordFailure: %v", err)
+	}
+	stor.Close()
+
+	db, err := sql.Open("sqlite", dbFile)
+	if err != nil {
+		t.Fatalf("open raw: %v", err)
+	}
+	var raw strings.Builder
+	for _, q := range []string{
+		`SELECT consumer_name || mobile_no || address FROM complaints`,
+		`SELECT consumer_name FROM complaint_history`,
+		`SELECT payload FROM dead_letters`,
+		`SELECT original_text FROM conversations`,
+	} {
+		rows, err := db.Query(q)
+		if err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+		for rows.Next() {
+			var v sql.NullString
+			rows.Scan(&v)
+			raw.WriteString(v.String)
+		}
+		rows.Close()
+	}
+	var consumerNo string
+	db.QueryRow(`SELECT consumer_no FROM complaints WHERE complaint_id = 'CMP-1'`).Scan(&consumerNo)
+	db.Close()
+	for _, pii := range []string{"Asha", "9876543210", "temple", "Ravi", "9123456780"} {
+		if strings.Contains(raw.String(), pii) {
+			t.Errorf("%q stored in the clear", pii)
+		}
+	}
+	if consumerNo != "11223" {
+		t.Errorf("consumer_no should stay searchable, got %q", consumerNo)
+	}
+
+	stor, err = NewEncrypted(key)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got := stor.GetConsumerName("CMP-1"); got != "Asha Patel" {
+		t.Errorf("consumer name: got %q", got)
+	}
+	if got := stor.GetMobileNo("CMP-2"); got != "9123456780" {
+		t.Errorf("mobile: got %q", got)
+	}
+	if hist, _ := stor.GetConsumerHistory("11223", 5); len(hist) != 1 || hist[0].ConsumerName != "Asha Patel" {
+		t.Errorf("history: got %+v", hist)
+	}
+	if dls, _ := stor.GetDeadLetters(); len(dls) != 1 || dls[0].Payload != `{"name":"Ravi Shah"}` {
+		t.Errorf("dead letters: got %+v", dls)
+	}
+	for id, want := range map[int64]string{1: "Asha Patel, Near temple", 2: "Ravi Shah 9123456780"} {
+		if conv, ok := stor.GetConversation(id); !ok || conv.OriginalText != want {
+			t.Errorf("conversation %d: got %q, want %q", id, conv.OriginalText, want)
+		}
+	}
+	stor.Close()
+
+	if _, err := New(); !errors.Is(err, ErrEncryptionKey) {
+		t.Errorf("open without key: got %v, want ErrEncryptionKey", err)
+	}
+	if _, err := NewEncrypted(bytes.Repeat([]byte{8}, EncryptionKeySize)); !errors.Is(err, ErrEncryptionKey) {
+		t.Errorf("open with wrong key: got %v, want ErrEncryptionKey", err)
+	}
+}
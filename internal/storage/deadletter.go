@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Dead-letter stages: where processing of a complaint failed.
+const (
+	// StageFetch means the complaint's details could not be fetched or
+	// parsed, so it was never saved. It is retried when it is next seen on
+	// the dashboard.
+	StageFetch = "fetch"
+	// StageNotify means the complaint was saved but its Telegram message
+	// was not sent. Payload holds the notification to re-send.
+	StageNotify = "notify"
+)
+
+// Dead-letter retry backoff: the first retry waits deadLetterBaseDelay and
+// each further failure doubles it, up to deadLetterMaxDelay.
+const (
+	deadLetterBaseDelay = 5 * time.Minute
+	deadLetterMaxDelay  = 6 * time.Hour
+)
+
+// deadLetterNow is swapped by tests to control retry times.
+var deadLetterNow = time.Now
+
+// DeadLetter is a complaint whose processing failed, with enough state to
+// retry it on a later cycle and to show it in /failed.
+type DeadLetter struct {
+	ComplaintID   string
+	APIID         string
+	Stage         string // StageFetch or StageNotify
+	Attempts      int
+	LastError     string
+	Payload       string // StageNotify: complaint JSON
+	FirstFailedAt time.Time
+	LastFailedAt  time.Time
+	NextRetryAt   time.Time
+}
+
+// deadLetterBackoff is the wait before retrying after the nth failure.
+func deadLetterBackoff(attempts int) time.Duration {
+	d := deadLetterBaseDelay
+	for i := 1; i < attempts && d < deadLetterMaxDelay; i++ {
+		d *= 2
+	}
+	if d > deadLetterMaxDelay {
+		d = deadLetterMaxDelay
+	}
+	return d
+}
+
+// RecordFailure adds complaintID to the dead-letter list, or bumps its
+// attempt count, and schedules the next retry. A later failure at a
+// different stage replaces the stage and payload.
+func (s *Storage) RecordFailure(complaintID, apiID, stage, errMsg, payload string) (DeadLetter, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	now := deadLetterNow().UTC()
+	dl, found, err := s.getDeadLetter(complaintID)
+	if err != nil {
+		return DeadLetter{}, err
+	}
+	if !found {
+		dl = DeadLetter{ComplaintID: complaintID, FirstFailedAt: now}
+	}
+	if apiID != "" {
+		dl.APIID = apiID
+	}
+	dl.Stage = stage
+	dl.Attempts++
+	dl.LastError = errMsg
+	dl.Payload = payload
+	dl.LastFailedAt = now
+	dl.NextRetryAt = now.Add(deadLetterBackoff(dl.Attempts))
+
+	_, err = s.db.Exec(`
+		INSERT INTO dead_letters (complaint_id, api_id, stage, attempts, last_error, payload, first_failed_at, last_failed_at, next_retry_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(complaint_id) DO UPDATE SET
+			api_id = excluded.api_id,
+			stage = excluded.stage,
+			attempts = excluded.attempts,
+			last_error = excluded.last_error,
+			payload = excluded.payload,
+			last_failed_at = excluded.last_failed_at,
+			next_retry_at = excluded.next_retry_at
+	`, dl.ComplaintID, dl.APIID, dl.Stage, dl.Attempts, dl.LastError, s.seal(dl.Payload),
+		dl.FirstFailedAt.Format(historyTimeLayout), dl.LastFailedAt.Format(historyTimeLayout), dl.NextRetryAt.Format(historyTimeLayout))
+	if err != nil {
+		return DeadLetter{}, err
+	}
+	return dl, nil
+}
+
+// RetryDue reports whether complaintID may be processed now: true when it
+// is not dead-lettered or its backoff has elapsed.
+func (s *Storage) RetryDue(complaintID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	dl, found, err := s.getDeadLetter(complaintID)
+	if err != nil || !found {
+		return true
+	}
+	return !deadLetterNow().Before(dl.NextRetryAt)
+}
+
+// ClearDeadLetter drops complaintID from the dead-letter list once it has
+// been processed successfully. Clearing an unknown ID is a no-op.
+func (s *Storage) ClearDeadLetter(complaintID string) error {
+	_, err := s.db.Exec(`DELETE FROM dead_letters WHERE complaint_id = ?`, complaintID)
+	return err
+}
+
+// GetDeadLetters returns every dead-lettered complaint, oldest failure
+// first.
+func (s *Storage) GetDeadLetters() ([]DeadLetter, error) {
+	return s.queryDeadLetters(`ORDER BY first_failed_at, complaint_id`)
+}
+
+// GetDueDeadLetters returns the dead letters at stage whose backoff has
+// elapsed, oldest failure first.
+func (s *Storage) GetDueDeadLetters(stage string) ([]DeadLetter, error) {
+	return s.queryDeadLetters(`WHERE stage = ? AND next_retry_at <= ? ORDER BY first_failed_at, complaint_id`,
+		stage, deadLetterNow().UTC().Format(historyTimeLayout))
+}
+
+// PruneDeadLetters drops fetch-stage dead letters for complaints that are
+// no longer on the dashboard; there is nothing left to retry. Returns how
+// many were dropped.
+func (s *Storage) PruneDeadLetters(activeIDs []string) (int, error) {
+	active := make(map[string]bool, len(activeIDs))
+	for _, id := range activeIDs {
+		active[id] = true
+	}
+	letters, err := s.queryDeadLetters(`WHERE stage = ?`, StageFetch)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, dl := range letters {
+		if active[dl.ComplaintID] {
+			continue
+		}
+		if err := s.ClearDeadLetter(dl.ComplaintID); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+func (s *Storage) getDeadLetter(complaintID string) (DeadLetter, bool, error) {
+	letters, err := s.queryDeadLetters(`WHERE complaint_id = ?`, complaintID)
+	if err != nil || len(letters) == 0 {
+		return DeadLetter{}, false, err
+	}
+	return letters[0], true, nil
+}
+
+func (s *Storage) queryDeadLetters(where string, args ...interface{}) ([]DeadLetter, error) {
+	rows, err := s.db.Query(`
+		SELECT complaint_id, api_id, stage, attempts, last_error, payload, first_failed_at, last_failed_at, next_retry_at
+		FROM dead_letters `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []DeadLetter
+	for rows.Next() {
+		var dl DeadLetter
+		var apiID, lastErr, payload, first, last, next sql.NullString
+		if err := rows.Scan(&dl.ComplaintID, &apiID, &dl.Stage, &dl.Attempts, &lastErr, &payload, &first, &last, &next); err != nil {
+			return nil, err
+		}
+		dl.APIID = apiID.String
+		dl.LastError = lastErr.String
+		dl.Payload = s.reveal(payload.String)
+		dl.FirstFailedAt = parseHistoryTime(first.String)
+		dl.LastFailedAt = parseHistoryTime(last.String)
+		dl.NextRetryAt = parseHistoryTime(next.String)
+		out = append(out, dl)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeadLetterBackoff(t *testing.T) {
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 5 * time.Minute},
+		{2, 10 * time.Minute},
+		{4, 40 * time.Minute},
+		{8, 6 * time.Hour}, // 640m, capped
+		{50, 6 * time.Hour},
+	}
+	for _, tc := range cases {
+		if got := deadLetterBackoff(tc.attempts); got != tc.want {
+			t.Errorf("deadLetterBackoff(%d) = %v, want %v", tc.attempts, got, tc.want)
+		}
+	}
+}
+
+func TestDeadLetterLifecycle(t *testing.T) {
+	withTempCWD(t)
+
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	clock := base
+	deadLetterNow = func() time.Time { return clock }
+	t.Cleanup(func() { deadLetterNow = time.Now })
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	if !stor.RetryDue("CMP-1") {
+		t.Fatal("a complaint that never failed must be due")
+	}
+
+	if _, err := stor.RecordFailure("CMP-1", "901", StageFetch, "timeout", ""); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	clock = base.Add(time.Minute)
+	dl, err := stor.RecordFailure("CMP-1", "", StageFetch, "HTTP 500", "")
+	if err != nil {
+		t.Fatalf("record again: %v", err)
+	}
+	if dl.Attempts != 2 || dl.APIID != "901" || dl.LastError != "HTTP 500" || !dl.FirstFailedAt.Equal(base) {
+		t.Errorf("second failure: got %+v", dl)
+	}
+	if want := clock.Add(10 * time.Minute); !dl.NextRetryAt.Equal(want) {
+		t.Errorf("NextRetryAt: got %v, want %v", dl.NextRetryAt, want)
+	}
+	if stor.RetryDue("CMP-1") {
+		t.Error("must not be due inside the backoff window")
+	}
+	clock = dl.NextRetryAt
+	if !stor.RetryDue("CMP-1") {
+		t.Error("must be due once the backoff has elapsed")
+	}
+
+	if _, err := stor.RecordFailure("CMP-2", "902", StageNotify, "telegram down", `{"complain_no":"CMP-2"}`); err != nil {
+		t.Fatalf("record notify: %v", err)
+	}
+	due, err := stor.GetDueDeadLetters(StageNotify)
+	if err != nil || len(due) != 0 {
+		t.Fatalf("notify letter due immediately: %v %v", due, err)
+	}
+	clock = clock.Add(5 * time.Minute)
+	due, _ = stor.GetDueDeadLetters(StageNotify)
+	if len(due) != 1 || due[0].Payload != `{"complain_no":"CMP-2"}` {
+		t.Errorf("due notify letters: got %+v", due)
+	}
+
+	// CMP-1 left the dashboard; the notify letter is not a fetch failure
+	// and survives the prune.
+	if n, err := stor.PruneDeadLetters([]string{"CMP-9"}); err != nil || n != 1 {
+		t.Errorf("prune: got %d, %v; want 1", n, err)
+	}
+	all, _ := stor.GetDeadLetters()
+	if len(all) != 1 || all[0].ComplaintID != "CMP-2" {
+		t.Fatalf("after prune: got %+v", all)
+	}
+
+	if err := stor.ClearDeadLetter("CMP-2"); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if all, _ := stor.GetDeadLetters(); len(all) != 0 {
+		t.Errorf("after clear: got %+v", all)
+	}
+}
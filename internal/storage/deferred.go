@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// DeferredSend is a complaint message held back during quiet hours, to be
+// delivered when they end. Payload holds the complaint JSON.
+type DeferredSend struct {
+	ComplaintID string
+	Payload     string
+	QueuedAt    time.Time
+}
+
+// DeferSend queues complaintID's message until quiet hours end. Queueing
+// it again replaces the payload but keeps its place. Rows are dropped with
+// the complaint in Remove.
+func (s *Storage) DeferSend(complaintID, payload string) error {
+	_, err := s.db.Exec(`
+		INSERT INTO deferred_sends (complaint_id, payload, queued_at) VALUES (?, ?, ?)
+		ON CONFLICT(complaint_id) DO UPDATE SET payload = excluded.payload
+	`, complaintID, s.seal(payload), time.Now().UTC().Format(historyTimeLayout))
+	return err
+}
+
+// GetDeferredSends returns the queued messages, oldest first. Call
+// ClearDeferredSend for each once it is delivered or dead-lettered.
+func (s *Storage) GetDeferredSends() ([]DeferredSend, error) {
+	rows, err := s.db.Query(`SELECT complaint_id, payload, queued_at FROM deferred_sends ORDER BY queued_at, complaint_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []DeferredSend
+	for rows.Next() {
+		var d DeferredSend
+		var payload, queued sql.NullString
+		if err := rows.Scan(&d.ComplaintID, &payload, &queued); err != nil {
+			return nil, err
+		}
+		d.Payload = s.reveal(payload.String)
+		d.QueuedAt = parseHistoryTime(queued.String)
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// ClearDeferredSend removes complaintID from the quiet-hours queue.
+func (s *Storage) ClearDeferredSend(complaintID string) error {
+	_, err := s.db.Exec(`DELETE FROM deferred_sends WHERE complaint_id = ?`, complaintID)
+	return err
+}
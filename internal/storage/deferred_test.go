@@ -0,0 +1,47 @@
+package storage
+
+import "testing"
+
+func TestDeferredSends(t *testing.T) {
+	withTempCWD(t)
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "CMP-1"}, {ComplaintID: "CMP-2"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := stor.DeferSend("CMP-1", "first"); err != nil {
+		t.Fatalf("DeferSend: %v", err)
+	}
+	stor.DeferSend("CMP-2", "second")
+	stor.DeferSend("CMP-1", "first again")
+
+	// The queue survives a restart.
+	if err := stor.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	stor, err = New()
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	got, err := stor.GetDeferredSends()
+	if err != nil {
+		t.Fatalf("GetDeferredSends: %v", err)
+	}
+	if len(got) != 2 || got[0].ComplaintID != "CMP-1" || got[0].Payload != "first again" || got[1].Payload != "second" {
+		t.Fatalf("queue = %+v, want CMP-1 (requeued in place) then CMP-2", got)
+	}
+
+	// Resolving a complaint drops it from the queue; so does clearing it.
+	if err := stor.Remove("CMP-1"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	stor.ClearDeferredSend("CMP-2")
+	if got, _ := stor.GetDeferredSends(); len(got) != 0 {
+		t.Errorf("queue = %+v, want empty", got)
+	}
+}
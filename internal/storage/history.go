@@ -0,0 +1,541 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"cmon/internal/quality"
+)
+
+// historyTimeLayout matches SQLite's CURRENT_TIMESTAMP text format so rows
+// written by the backfill default and by upsertHistory compare correctly.
+const historyTimeLayout = "2006-01-02 15:04:05"
+
+// historyNow is swapped by tests to place history rows at fixed times.
+var historyNow = time.Now
+
+// HistoryEntry is one complaint in a consumer's history. Unlike the
+// complaints table, history rows survive resolution so repeat complainants
+// can be recognised after earlier complaints are closed.
+type HistoryEntry struct {
+	ComplaintID  string
+	ConsumerNo   string
+	ConsumerName string
+	Village      string
+	Belt         string
+	Category     string
+	Description  string
+	ComplainDate string
+	DataIssues   string // quality.Encode form
+	FirstSeenAt  time.Time
+	ResolvedAt   time.Time // zero while the complaint is still open
+	// ResolutionTime is ResolvedAt − FirstSeenAt, persisted when the
+	// complaint is resolved; zero while it is open.
+	ResolutionTime time.Duration
+
+	// TelegramMessageID is the complaint's Telegram message at the time it
+	// was resolved, so a re-opened complaint can point back at it.
+	TelegramMessageID string
+	// VoiceNoteFileID is the Telegram file ID of the voice note the
+	// complaint was resolved with, if any.
+	VoiceNoteFileID string
+	// Attachments are the files (repair photos) saved with the resolution,
+	// as paths under the attachments directory.
+	Attachments []string
+	// Notes are the comments staff added while it was open, oldest first.
+	// Only GetResolvedHistoryBefore fills them.
+	Notes []Note
+}
+
+// backfillHistory copies complaints that predate the history table into it.
+// INSERT OR IGNORE makes this a no-op once every row has a history entry.
+func (s *Storage) backfillHistory() {
+	res, err := s.db.Exec(`
+		INSERT OR IGNORE INTO complaint_history (complaint_id, consumer_no, consumer_name, village, belt, description, complain_date, first_seen_at)
+		SELECT complaint_id, consumer_no, consumer_name, village, belt, description, complain_date, COALESCE(created_at, CURRENT_TIMESTAMP)
+		FROM complaints
+	`)
+	if err != nil {
+		log.Printf("⚠️  Failed to backfill complaint history: %v", err)
+		return
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Printf("📚 Backfilled %d complaints into history", n)
+	}
+}
+
+// resolutionSecondsSQL computes resolution_seconds from first_seen_at and
+// the resolved_at it is compared with (the single ? parameter or column).
+const resolutionSecondsSQL = `MAX(0, CAST(ROUND((julianday(%s) - julianday(first_seen_at)) * 86400) AS INTEGER))`
+
+// backfillResolutionTimes fills resolution_seconds for rows resolved
+// before the column existed. A no-op once every resolved row has one.
+func (s *Storage) backfillResolutionTimes() {
+	res, err := s.db.Exec(`
+		UPDATE complaint_history SET resolution_seconds = ` + fmt.Sprintf(resolutionSecondsSQL, "resolved_at") + `
+		WHERE resolved_at IS NOT NULL AND resolution_seconds IS NULL AND first_seen_at IS NOT NULL
+	`)
+	if err != nil {
+		log.Printf("⚠️  Failed to backfill resolution times: %v", err)
+		return
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Printf("📚 Backfilled resolution times for %d complaints", n)
+	}
+}
+
+// upsertHistory records records in complaint_history inside the caller's
+// transaction. first_seen_at is only set on insert; other fields follow the
+// same "non-empty wins" rule as the complaints upsert. Saving a complaint
+// whose row was resolved clears resolved_at: it is open again.
+func (s *Storage) upsertHistory(tx *sql.Tx, records []Record) error {
+	stmt, err := tx.Prepare(`
+		INSERT INTO complaint_history (complaint_id, consumer_no, consumer_name, village, belt, category, description, complain_date, first_seen_at, data_issues)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(complaint_id) DO UPDATE SET
+			resolved_at = NULL,
+			resolution_seconds = NULL,
+			data_issues = CASE WHEN excluded.data_issues != '' THEN excluded.data_issues ELSE complaint_history.data_issues END,
+			consumer_no = CASE WHEN excluded.consumer_no != '' THEN excluded.consumer_no ELSE complaint_history.consumer_no END,
+			consumer_name = CASE WHEN excluded.consumer_name != '' THEN excluded.consumer_name ELSE complaint_history.consumer_name END,
+			village = CASE WHEN excluded.village != '' THEN excluded.village ELSE complaint_history.village END,
+			belt = CASE WHEN excluded.belt != '' THEN excluded.belt ELSE complaint_history.belt END,
+			category = CASE WHEN excluded.category != '' THEN excluded.category ELSE complaint_history.category END,
+			description = CASE WHEN excluded.description != '' THEN excluded.description ELSE complaint_history.description END,
+			complain_date = CASE WHEN excluded.complain_date != '' THEN excluded.complain_date ELSE complaint_history.complain_date END
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := historyNow().UTC().Format(historyTimeLayout)
+	for _, r := range records {
+		if _, err := stmt.Exec(r.ComplaintID, strings.TrimSpace(r.ConsumerNo), s.seal(r.ConsumerName), r.Village, r.Belt, r.Category, r.Description, r.ComplainDate, now, r.DataIssues); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markHistoryResolved stamps resolved_at and the time it took on a
+// complaint's history row and remembers its Telegram message. Rows already
+// resolved keep their original timestamp.
+func markHistoryResolved(tx *sql.Tx, complaintID, tgMessageID string) error {
+	now := historyNow().UTC().Format(historyTimeLayout)
+	_, err := tx.Exec(`
+		UPDATE complaint_history SET
+			resolved_at = ?,
+			resolution_seconds = `+fmt.Sprintf(resolutionSecondsSQL, "?")+`,
+			tg_message_id = CASE WHEN ? != '' THEN ? ELSE tg_message_id END
+		WHERE complaint_id = ? AND resolved_at IS NULL
+	`, now, now, tgMessageID, tgMessageID, complaintID)
+	return err
+}
+
+// GetResolvedHistory returns complaintID's history entry if it was
+// resolved, reporting false for complaints never seen or still open. A
+// complaint that shows up on the dashboard again after this returns true
+// has been re-opened on the portal.
+func (s *Storage) GetResolvedHistory(complaintID string) (HistoryEntry, bool, error) {
+	e := HistoryEntry{ComplaintID: complaintID}
+	var consumerNo, consumerName, village, belt, description, complainDate, issues, firstSeen, resolved, tgMessageID, voiceNote, attachments sql.NullString
+	err := s.db.QueryRow(`
+		SELECT consumer_no, consumer_name, village, belt, description, complain_date, data_issues, first_seen_at, resolved_at, tg_message_id, voice_note_file_id, attachments
+		FROM complaint_history
+		WHERE complaint_id = ? AND resolved_at IS NOT NULL
+	`, complaintID).Scan(&consumerNo, &consumerName, &village, &belt, &description, &complainDate, &issues, &firstSeen, &resolved, &tgMessageID, &voiceNote, &attachments)
+	if err == sql.ErrNoRows {
+		return HistoryEntry{}, false, nil
+	}
+	if err != nil {
+		return HistoryEntry{}, false, err
+	}
+	e.ConsumerNo = consumerNo.String
+	e.ConsumerName = s.reveal(consumerName.String)
+	e.Village = village.String
+	e.Belt = belt.String
+	e.Description = description.String
+	e.ComplainDate = complainDate.String
+	e.DataIssues = issues.String
+	e.FirstSeenAt = parseHistoryTime(firstSeen.String)
+	e.ResolvedAt = parseHistoryTime(resolved.String)
+	e.TelegramMessageID = tgMessageID.String
+	e.VoiceNoteFileID = voiceNote.String
+	e.Attachments = splitAttachments(attachments.String)
+	return e, true, nil
+}
+
+// SetHistoryVoiceNote keeps the Telegram file ID of the voice note a
+// complaint is being resolved with, so the audio can be fetched later.
+func (s *Storage) SetHistoryVoiceNote(complaintID, fileID string) error {
+	_, err := s.db.Exec(`UPDATE complaint_history SET voice_note_file_id = ? WHERE complaint_id = ?`, fileID, complaintID)
+	return err
+}
+
+// AddHistoryAttachment records a file saved with complaintID's resolution.
+func (s *Storage) AddHistoryAttachment(complaintID, path string) error {
+	_, err := s.db.Exec(`
+		UPDATE complaint_history SET attachments = CASE
+			WHEN attachments IS NULL OR attachments = '' THEN ?
+			ELSE attachments || char(10) || ?
+		END
+		WHERE complaint_id = ?
+	`, path, path, complaintID)
+	return err
+}
+
+// splitAttachments decodes the attachments column: one path per line.
+func splitAttachments(v string) []string {
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, "\n")
+}
+
+// CountConsumerComplaintsSince returns how many complaints from consumerNo
+// were first seen at or after since, not counting excludeID (the complaint
+// being annotated). Returns 0 for an empty consumer number.
+func (s *Storage) CountConsumerComplaintsSince(consumerNo string, since time.Time, excludeID string) (int, error) {
+	consumerNo = strings.TrimSpace(consumerNo)
+	if consumerNo == "" {
+		return 0, nil
+	}
+	var n int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM complaint_history
+		WHERE consumer_no = ? AND first_seen_at >= ? AND complaint_id != ?
+	`, consumerNo, since.UTC().Format(historyTimeLayout), excludeID).Scan(&n)
+	return n, err
+}
+
+// GetConsumerHistory returns up to limit history entries for consumerNo,
+// newest first.
+func (s *Storage) GetConsumerHistory(consumerNo string, limit int) ([]HistoryEntry, error) {
+	consumerNo = strings.TrimSpace(consumerNo)
+	if consumerNo == "" {
+		return nil, nil
+	}
+	rows, err := s.db.Query(`
+		SELECT complaint_id, consumer_no, consumer_name, village, belt, description, complain_date, data_issues, first_seen_at, resolved_at
+		FROM complaint_history
+		WHERE consumer_no = ?
+		ORDER BY first_seen_at DESC, complaint_id DESC
+		LIMIT ?
+	`, consumerNo, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []HistoryEntry
+	for rows.Next() {
+		var e HistoryEntry
+		var consumerName, village, belt, description, complainDate, issues, firstSeen, resolved sql.NullString
+		if err := rows.Scan(&e.ComplaintID, &e.ConsumerNo, &consumerName, &village, &belt, &description, &complainDate, &issues, &firstSeen, &resolved); err != nil {
+			return nil, err
+		}
+		e.ConsumerName = s.reveal(consumerName.String)
+		e.Village = village.String
+		e.Belt = belt.String
+		e.Description = description.String
+		e.ComplainDate = complainDate.String
+		e.DataIssues = issues.String
+		e.FirstSeenAt = parseHistoryTime(firstSeen.String)
+		e.ResolvedAt = parseHistoryTime(resolved.String)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// GetResolvedHistoryBefore returns history entries resolved before cutoff,
+// oldest first. These are what retention cleanup may delete; open
+// complaints are never returned however old they are.
+func (s *Storage) GetResolvedHistoryBefore(cutoff time.Time) ([]HistoryEntry, error) {
+	rows, err := s.db.Query(`
+		SELECT complaint_id, consumer_no, consumer_name, village, belt, category, description, complain_date, data_issues, first_seen_at, resolved_at, resolution_seconds, voice_note_file_id, attachments
+		FROM complaint_history
+		WHERE resolved_at IS NOT NULL AND resolved_at < ?
+		ORDER BY resolved_at, complaint_id
+	`, cutoff.UTC().Format(historyTimeLayout))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []HistoryEntry
+	for rows.Next() {
+		var e HistoryEntry
+		var consumerNo, consumerName, village, belt, category, description, complainDate, issues, firstSeen, resolved, voiceNote, attachments sql.NullString
+		var took sql.NullInt64
+		if err := rows.Scan(&e.ComplaintID, &consumerNo, &consumerName, &village, &belt, &category, &description, &complainDate, &issues, &firstSeen, &resolved, &took, &voiceNote, &attachments); err != nil {
+			return nil, err
+		}
+		e.ConsumerNo = consumerNo.String
+		e.ConsumerName = s.reveal(consumerName.String)
+		e.Village = village.String
+		e.Belt = belt.String
+		e.Category = category.String
+		e.Description = description.String
+		e.ComplainDate = complainDate.String
+		e.DataIssues = issues.String
+		e.FirstSeenAt = parseHistoryTime(firstSeen.String)
+		e.ResolvedAt = parseHistoryTime(resolved.String)
+		e.ResolutionTime = time.Duration(took.Int64) * time.Second
+		e.VoiceNoteFileID = voiceNote.String
+		e.Attachments = splitAttachments(attachments.String)
+		out = append(out, e)
+	}
+	// One connection: finish reading before the notes query.
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	notes, err := s.queryNotes(`WHERE complaint_id IN (
+		SELECT complaint_id FROM complaint_history WHERE resolved_at IS NOT NULL AND resolved_at < ?
+	)`, cutoff.UTC().Format(historyTimeLayout))
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].Notes = notes[out[i].ComplaintID]
+	}
+	return out, nil
+}
+
+// GetMessagesToCleanBefore returns complaints resolved before cutoff whose
+// Telegram message has not been cleaned up yet, oldest first. Only the
+// complaint ID, village, belt, resolution time and TelegramMessageID are
+// filled. A complaint re-opened and resolved again under a new message is
+// due again.
+func (s *Storage) GetMessagesToCleanBefore(cutoff time.Time) ([]HistoryEntry, error) {
+	rows, err := s.db.Query(`
+		SELECT complaint_id, village, belt, category, resolved_at, tg_message_id
+		FROM complaint_history
+		WHERE resolved_at IS NOT NULL AND resolved_at < ?
+			AND COALESCE(tg_message_id, '') != ''
+			AND tg_message_id != COALESCE(tg_cleaned_message_id, '')
+		ORDER BY resolved_at, complaint_id
+	`, cutoff.UTC().Format(historyTimeLayout))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []HistoryEntry
+	for rows.Next() {
+		var e HistoryEntry
+		var village, belt, category, resolved sql.NullString
+		if err := rows.Scan(&e.ComplaintID, &village, &belt, &category, &resolved, &e.TelegramMessageID); err != nil {
+			return nil, err
+		}
+		e.Village = village.String
+		e.Belt = belt.String
+		e.Category = category.String
+		e.ResolvedAt = parseHistoryTime(resolved.String)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// MarkMessageCleaned records that complaintID's resolved Telegram message
+// messageID has been cleaned up, so GetMessagesToCleanBefore skips it.
+func (s *Storage) MarkMessageCleaned(complaintID, messageID string) error {
+	_, err := s.db.Exec(`UPDATE complaint_history SET tg_cleaned_message_id = ? WHERE complaint_id = ?`, messageID, complaintID)
+	return err
+}
+
+// GetHistorySince returns history entries first seen or resolved at or
+// after since, oldest first seen first. Only the fields trend charts need
+// are filled: complaint ID, village, belt and the two timestamps.
+func (s *Storage) GetHistorySince(since time.Time) ([]HistoryEntry, error) {
+	cutoff := since.UTC().Format(historyTimeLayout)
+	rows, err := s.db.Query(`
+		SELECT complaint_id, village, belt, first_seen_at, resolved_at
+		FROM complaint_history
+		WHERE first_seen_at >= ? OR resolved_at >= ?
+		ORDER BY first_seen_at, complaint_id
+	`, cutoff, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []HistoryEntry
+	for rows.Next() {
+		var e HistoryEntry
+		var village, belt, firstSeen, resolved sql.NullString
+		if err := rows.Scan(&e.ComplaintID, &village, &belt, &firstSeen, &resolved); err != nil {
+			return nil, err
+		}
+		e.Village = village.String
+		e.Belt = belt.String
+		e.FirstSeenAt = parseHistoryTime(firstSeen.String)
+		e.ResolvedAt = parseHistoryTime(resolved.String)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// GetResolvedBetween returns complaints resolved at or after from and
+// before to, oldest resolution first, for SLA reporting. Only the complaint
+// ID, village, belt, category, description, timestamps and ResolutionTime
+// are filled.
+func (s *Storage) GetResolvedBetween(from, to time.Time) ([]HistoryEntry, error) {
+	rows, err := s.db.Query(`
+		SELECT complaint_id, village, belt, category, description, first_seen_at, resolved_at, resolution_seconds
+		FROM complaint_history
+		WHERE resolved_at >= ? AND resolved_at < ?
+		ORDER BY resolved_at, complaint_id
+	`, from.UTC().Format(historyTimeLayout), to.UTC().Format(historyTimeLayout))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []HistoryEntry
+	for rows.Next() {
+		var e HistoryEntry
+		var village, belt, category, description, firstSeen, resolved sql.NullString
+		var took sql.NullInt64
+		if err := rows.Scan(&e.ComplaintID, &village, &belt, &category, &description, &firstSeen, &resolved, &took); err != nil {
+			return nil, err
+		}
+		e.Village = village.String
+		e.Belt = belt.String
+		e.Category = category.String
+		e.Description = description.String
+		e.FirstSeenAt = parseHistoryTime(firstSeen.String)
+		e.ResolvedAt = parseHistoryTime(resolved.String)
+		e.ResolutionTime = time.Duration(took.Int64) * time.Second
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// CountResolvedBetween returns how many complaints were resolved at or
+// after from and before to.
+func (s *Storage) CountResolvedBetween(from, to time.Time) (int, error) {
+	var n int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM complaint_history
+		WHERE resolved_at >= ? AND resolved_at < ?
+	`, from.UTC().Format(historyTimeLayout), to.UTC().Format(historyTimeLayout)).Scan(&n)
+	return n, err
+}
+
+// GetOldestOpenHistory returns the open complaint first seen longest ago,
+// with its village and belt. ok=false when nothing is open.
+func (s *Storage) GetOldestOpenHistory() (HistoryEntry, bool, error) {
+	var e HistoryEntry
+	var village, belt, firstSeen sql.NullString
+	err := s.db.QueryRow(`
+		SELECT complaint_id, village, belt, first_seen_at
+		FROM complaint_history
+		WHERE resolved_at IS NULL
+		ORDER BY first_seen_at, complaint_id
+		LIMIT 1
+	`).Scan(&e.ComplaintID, &village, &belt, &firstSeen)
+	if err == sql.ErrNoRows {
+		return HistoryEntry{}, false, nil
+	}
+	if err != nil {
+		return HistoryEntry{}, false, err
+	}
+	e.Village = village.String
+	e.Belt = belt.String
+	e.FirstSeenAt = parseHistoryTime(firstSeen.String)
+	return e, true, nil
+}
+
+// DeleteHistory removes the given complaints' history rows, and their
+// notes, in one transaction and returns how many rows were deleted. Rows
+// of open complaints are left alone.
+func (s *Storage) DeleteHistory(complaintIDs []string) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`DELETE FROM complaint_history WHERE complaint_id = ? AND resolved_at IS NOT NULL`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	notesStmt, err := tx.Prepare(`DELETE FROM complaint_notes WHERE complaint_id = ?`)
+	if err != nil {
+		return 0, err
+	}
+	defer notesStmt.Close()
+
+	var total int64
+	for _, id := range complaintIDs {
+		res, err := stmt.Exec(id)
+		if err != nil {
+			return 0, err
+		}
+		n, _ := res.RowsAffected()
+		total += n
+		// Notes go with their history row, not with an open complaint's.
+		if n > 0 {
+			if _, err := notesStmt.Exec(id); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// parseHistoryTime parses a stored UTC timestamp. The sqlite driver may hand
+// back DATETIME columns in RFC 3339 form, so both layouts are accepted.
+func parseHistoryTime(v string) time.Time {
+	if v == "" {
+		return time.Time{}
+	}
+	for _, layout := range []string{historyTimeLayout, time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, v, time.UTC); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// GetFlaggedComplaintsSince returns complaints first seen at or after since
+// that were flagged with intake data issues, oldest first. Resolved ones are
+// included — the report is about intake quality, not open work.
+func (s *Storage) GetFlaggedComplaintsSince(since time.Time) ([]quality.Flagged, error) {
+	rows, err := s.db.Query(`
+		SELECT complaint_id, consumer_name, belt, data_issues
+		FROM complaint_history
+		WHERE first_seen_at >= ? AND data_issues IS NOT NULL AND data_issues != ''
+		ORDER BY first_seen_at, complaint_id
+	`, since.UTC().Format(historyTimeLayout))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []quality.Flagged
+	for rows.Next() {
+		var id string
+		var name, belt, issues sql.NullString
+		if err := rows.Scan(&id, &name, &belt, &issues); err != nil {
+			return nil, err
+		}
+		out = append(out, quality.Flagged{
+			ComplaintID:  id,
+			ConsumerName: s.reveal(name.String),
+			Belt:         belt.String,
+			Issues:       quality.Decode(issues.String),
+		})
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,457 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConsumerHistorySurvivesResolution(t *testing.T) {
+	withTempCWD(t)
+
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	clock := base
+	historyNow = func() time.Time { return clock }
+	t.Cleanup(func() { historyNow = time.Now })
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	// Last month's complaint, then two this month from the same consumer.
+	clock = base.AddDate(0, -1, 0)
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "CMP-OLD", ConsumerNo: "C100", Description: "no supply"}}); err != nil {
+		t.Fatalf("save old: %v", err)
+	}
+	clock = base
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "CMP-1", ConsumerNo: "C100"}}); err != nil {
+		t.Fatalf("save CMP-1: %v", err)
+	}
+	clock = base.Add(time.Hour)
+	if err := stor.Remove("CMP-1"); err != nil {
+		t.Fatalf("remove CMP-1: %v", err)
+	}
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "CMP-2", ConsumerNo: " C100 "}}); err != nil {
+		t.Fatalf("save CMP-2: %v", err)
+	}
+
+	monthStart := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	n, err := stor.CountConsumerComplaintsSince("C100", monthStart, "CMP-2")
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("prior complaints this month: got %d, want 1 (resolved CMP-1 only)", n)
+	}
+
+	entries, err := stor.GetConsumerHistory("C100", 10)
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("history entries: got %d, want 3", len(entries))
+	}
+	if entries[0].ComplaintID != "CMP-2" || entries[2].ComplaintID != "CMP-OLD" {
+		t.Errorf("order: got %s..%s, want newest first", entries[0].ComplaintID, entries[2].ComplaintID)
+	}
+	if entries[1].ResolvedAt.IsZero() {
+		t.Error("CMP-1 should be marked resolved after Remove")
+	}
+	if !entries[0].ResolvedAt.IsZero() {
+		t.Error("CMP-2 is still open and must not have resolved_at")
+	}
+	if entries[2].Description != "no supply" {
+		t.Errorf("description: got %q", entries[2].Description)
+	}
+
+	if n, _ := stor.CountConsumerComplaintsSince("", monthStart, ""); n != 0 {
+		t.Errorf("empty consumer number must count 0, got %d", n)
+	}
+}
+
+func TestGetFlaggedComplaintsSince(t *testing.T) {
+	withTempCWD(t)
+
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	clock := base
+	historyNow = func() time.Time { return clock }
+	t.Cleanup(func() { historyNow = time.Now })
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	clock = base.AddDate(0, 0, -1)
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "YESTERDAY", DataIssues: "missing_mobile"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	clock = base
+	if err := stor.SaveMultiple([]Record{
+		{ComplaintID: "CLEAN"},
+		{ComplaintID: "BAD", ConsumerName: "Asha", DataIssues: "missing_mobile,missing_location"},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	// Resolution must not drop the complaint from the day's report.
+	if err := stor.Remove("BAD"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	flagged, err := stor.GetFlaggedComplaintsSince(base.Truncate(24 * time.Hour))
+	if err != nil {
+		t.Fatalf("GetFlaggedComplaintsSince: %v", err)
+	}
+	if len(flagged) != 1 || flagged[0].ComplaintID != "BAD" {
+		t.Fatalf("flagged = %+v, want only BAD", flagged)
+	}
+	if len(flagged[0].Issues) != 2 || flagged[0].ConsumerName != "Asha" {
+		t.Errorf("flagged[0] = %+v", flagged[0])
+	}
+}
+
+func TestResolvedHistoryBeforeAndDelete(t *testing.T) {
+	withTempCWD(t)
+
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	clock := base.AddDate(0, -6, 0)
+	historyNow = func() time.Time { return clock }
+	t.Cleanup(func() { historyNow = time.Now })
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	if err := stor.SaveMultiple([]Record{
+		{ComplaintID: "CMP-OLD", ConsumerNo: "C100", DataIssues: "mobile_missing"},
+		{ComplaintID: "CMP-OPEN"}, // old but never resolved
+		{ComplaintID: "CMP-NEW"},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	for _, text := range []string{"Lineman dispatched 14:30", "Transformer fuse ordered"} {
+		if err := stor.AddNote("CMP-OLD", Note{Author: "Asha", AuthorID: 7, Text: text}); err != nil {
+			t.Fatalf("AddNote: %v", err)
+		}
+		clock = clock.Add(time.Minute)
+	}
+	if err := stor.AddNote("CMP-OPEN", Note{Author: "Ravi", Text: "Waiting on permit"}); err != nil {
+		t.Fatalf("AddNote: %v", err)
+	}
+	if n, ok := stor.GetLatestNote("CMP-OLD"); !ok || n.Text != "Transformer fuse ordered" || n.AuthorID != 7 {
+		t.Errorf("latest note = %+v, %v", n, ok)
+	}
+	if err := stor.Remove("CMP-OLD"); err != nil {
+		t.Fatalf("remove CMP-OLD: %v", err)
+	}
+	clock = base
+	if err := stor.Remove("CMP-NEW"); err != nil {
+		t.Fatalf("remove CMP-NEW: %v", err)
+	}
+
+	expired, err := stor.GetResolvedHistoryBefore(base.AddDate(0, -1, 0))
+	if err != nil {
+		t.Fatalf("GetResolvedHistoryBefore: %v", err)
+	}
+	if len(expired) != 1 || expired[0].ComplaintID != "CMP-OLD" || expired[0].DataIssues != "mobile_missing" || expired[0].ResolvedAt.IsZero() {
+		t.Fatalf("expired = %+v, want only CMP-OLD", expired)
+	}
+	if notes := expired[0].Notes; len(notes) != 2 || notes[0].Text != "Lineman dispatched 14:30" || notes[1].Author != "Asha" {
+		t.Errorf("expired notes = %+v, want both, oldest first", notes)
+	}
+
+	n, err := stor.DeleteHistory([]string{"CMP-OLD", "CMP-OPEN"})
+	if err != nil {
+		t.Fatalf("DeleteHistory: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("deleted %d rows, want 1 (open complaints are kept)", n)
+	}
+	if h, _ := stor.GetConsumerHistory("C100", 10); len(h) != 0 {
+		t.Errorf("CMP-OLD history still present: %+v", h)
+	}
+	if notes, _ := stor.GetNotes("CMP-OLD"); len(notes) != 0 {
+		t.Errorf("CMP-OLD notes still present: %+v", notes)
+	}
+	if notes, _ := stor.GetNotes("CMP-OPEN"); len(notes) != 1 {
+		t.Errorf("CMP-OPEN notes = %+v, want them kept", notes)
+	}
+}
+
+func TestResolvedHistoryDetectsReopen(t *testing.T) {
+	withTempCWD(t)
+
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	clock := base
+	historyNow = func() time.Time { return clock }
+	t.Cleanup(func() { historyNow = time.Now })
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	if _, ok, err := stor.GetResolvedHistory("CMP-1"); err != nil || ok {
+		t.Fatalf("unknown complaint: ok=%v err=%v, want false", ok, err)
+	}
+
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "CMP-1", MessageID: "42", ConsumerNo: "C100"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, ok, _ := stor.GetResolvedHistory("CMP-1"); ok {
+		t.Fatal("open complaint must not report a resolution")
+	}
+
+	clock = base.Add(2 * time.Hour)
+	if err := stor.SetHistoryVoiceNote("CMP-1", "voice-file-1"); err != nil {
+		t.Fatalf("SetHistoryVoiceNote: %v", err)
+	}
+	for _, path := range []string{"attachments/CMP-1/a.jpg", "attachments/CMP-1/b.jpg"} {
+		if err := stor.AddHistoryAttachment("CMP-1", path); err != nil {
+			t.Fatalf("AddHistoryAttachment: %v", err)
+		}
+	}
+	if err := stor.Remove("CMP-1"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	e, ok, err := stor.GetResolvedHistory("CMP-1")
+	if err != nil || !ok {
+		t.Fatalf("resolved complaint: ok=%v err=%v, want true", ok, err)
+	}
+	if !e.ResolvedAt.Equal(clock) {
+		t.Errorf("ResolvedAt: got %v, want %v", e.ResolvedAt, clock)
+	}
+	if e.TelegramMessageID != "42" || e.ConsumerNo != "C100" || e.VoiceNoteFileID != "voice-file-1" {
+		t.Errorf("entry: got %+v", e)
+	}
+	if len(e.Attachments) != 2 || e.Attachments[1] != "attachments/CMP-1/b.jpg" {
+		t.Errorf("attachments: got %q", e.Attachments)
+	}
+
+	// Seen again on the dashboard: saving re-opens the history row.
+	clock = base.Add(24 * time.Hour)
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "CMP-1", MessageID: "77"}}); err != nil {
+		t.Fatalf("re-save: %v", err)
+	}
+	if _, ok, _ := stor.GetResolvedHistory("CMP-1"); ok {
+		t.Error("re-opened complaint must not still report a resolution")
+	}
+	entries, err := stor.GetConsumerHistory("C100", 10)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("history: %v %v", entries, err)
+	}
+	if !entries[0].FirstSeenAt.Equal(base) {
+		t.Errorf("first_seen_at must survive re-open, got %v", entries[0].FirstSeenAt)
+	}
+}
+
+func TestMessagesToClean(t *testing.T) {
+	withTempCWD(t)
+
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	clock := base.AddDate(0, 0, -40)
+	historyNow = func() time.Time { return clock }
+	t.Cleanup(func() { historyNow = time.Now })
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	if err := stor.SaveMultiple([]Record{
+		{ComplaintID: "CMP-OLD", MessageID: "42", Village: "Limdi", Belt: "Dahod"},
+		{ComplaintID: "CMP-NOMSG"},
+		{ComplaintID: "CMP-NEW", MessageID: "43"},
+		{ComplaintID: "CMP-OPEN", MessageID: "44"},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	for _, id := range []string{"CMP-OLD", "CMP-NOMSG"} {
+		if err := stor.Remove(id); err != nil {
+			t.Fatalf("remove %s: %v", id, err)
+		}
+	}
+	clock = base
+	if err := stor.Remove("CMP-NEW"); err != nil {
+		t.Fatalf("remove CMP-NEW: %v", err)
+	}
+
+	cutoff := base.AddDate(0, 0, -30)
+	due, err := stor.GetMessagesToCleanBefore(cutoff)
+	if err != nil {
+		t.Fatalf("GetMessagesToCleanBefore: %v", err)
+	}
+	if len(due) != 1 || due[0].ComplaintID != "CMP-OLD" || due[0].TelegramMessageID != "42" || due[0].Village != "Limdi" || due[0].ResolvedAt.IsZero() {
+		t.Fatalf("due = %+v, want only CMP-OLD with message 42", due)
+	}
+
+	if err := stor.MarkMessageCleaned("CMP-OLD", "42"); err != nil {
+		t.Fatalf("MarkMessageCleaned: %v", err)
+	}
+	if due, _ := stor.GetMessagesToCleanBefore(cutoff); len(due) != 0 {
+		t.Errorf("cleaned message still due: %+v", due)
+	}
+
+	// Re-opened and resolved again under a new message: due once more.
+	clock = base.AddDate(0, 0, -35)
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "CMP-OLD", MessageID: "50"}}); err != nil {
+		t.Fatalf("re-save: %v", err)
+	}
+	if err := stor.Remove("CMP-OLD"); err != nil {
+		t.Fatalf("re-remove: %v", err)
+	}
+	if due, _ := stor.GetMessagesToCleanBefore(cutoff); len(due) != 1 || due[0].TelegramMessageID != "50" {
+		t.Errorf("re-resolved complaint: due = %+v, want message 50", due)
+	}
+}
+
+func TestGetHistorySince(t *testing.T) {
+	withTempCWD(t)
+
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	clock := base
+	historyNow = func() time.Time { return clock }
+	t.Cleanup(func() { historyNow = time.Now })
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	// Seen last week and resolved today, seen last week and still open,
+	// then a new complaint today.
+	clock = base.AddDate(0, 0, -7)
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "OLD-RESOLVED"}, {ComplaintID: "OLD-OPEN"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	clock = base
+	if err := stor.Remove("OLD-RESOLVED"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "NEW", Belt: "Dahod"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	got, err := stor.GetHistorySince(base.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetHistorySince: %v", err)
+	}
+	if len(got) != 2 || got[0].ComplaintID != "OLD-RESOLVED" || got[1].ComplaintID != "NEW" {
+		t.Fatalf("GetHistorySince = %+v, want OLD-RESOLVED then NEW", got)
+	}
+	if !got[0].ResolvedAt.Equal(base) || !got[0].FirstSeenAt.Equal(base.AddDate(0, 0, -7)) {
+		t.Errorf("OLD-RESOLVED times = %v / %v", got[0].FirstSeenAt, got[0].ResolvedAt)
+	}
+	if got[1].Belt != "Dahod" || !got[1].ResolvedAt.IsZero() {
+		t.Errorf("NEW = %+v, want open Dahod complaint", got[1])
+	}
+}
+
+func TestRollCallStats(t *testing.T) {
+	withTempCWD(t)
+
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	clock := base
+	historyNow = func() time.Time { return clock }
+	t.Cleanup(func() { historyNow = time.Now })
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	if _, ok, err := stor.GetOldestOpenHistory(); err != nil || ok {
+		t.Fatalf("GetOldestOpenHistory on empty history = %v, %v; want nothing", ok, err)
+	}
+
+	clock = base.AddDate(0, 0, -3)
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "A", Village: "Valod"}, {ComplaintID: "B"}, {ComplaintID: "C"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	clock = base.AddDate(0, 0, -2)
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "D", Village: "Buhari"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	// A and B resolved the day before base, C on base's day.
+	clock = base.AddDate(0, 0, -1)
+	for _, id := range []string{"A", "B"} {
+		if err := stor.Remove(id); err != nil {
+			t.Fatalf("remove %s: %v", id, err)
+		}
+	}
+	clock = base
+	if err := stor.Remove("C"); err != nil {
+		t.Fatalf("remove C: %v", err)
+	}
+
+	day := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	if n, err := stor.CountResolvedBetween(day, day.AddDate(0, 0, 1)); err != nil || n != 2 {
+		t.Errorf("CountResolvedBetween = %d, %v; want 2", n, err)
+	}
+	oldest, ok, err := stor.GetOldestOpenHistory()
+	if err != nil || !ok {
+		t.Fatalf("GetOldestOpenHistory = %v, %v", ok, err)
+	}
+	if oldest.ComplaintID != "D" || oldest.Village != "Buhari" || !oldest.FirstSeenAt.Equal(base.AddDate(0, 0, -2)) {
+		t.Errorf("oldest = %+v, want D from Buhari", oldest)
+	}
+}
+
+func TestGetResolvedBetween(t *testing.T) {
+	withTempCWD(t)
+
+	base := time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC)
+	clock := base
+	historyNow = func() time.Time { return clock }
+	t.Cleanup(func() { historyNow = time.Now })
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	if err := stor.SaveMultiple([]Record{
+		{ComplaintID: "CMP-FAST", Description: "No supply"},
+		{ComplaintID: "CMP-SLOW", Belt: "Tokarva"},
+		{ComplaintID: "CMP-OPEN"},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	clock = base.Add(90 * time.Minute)
+	if err := stor.Remove("CMP-FAST"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	clock = base.AddDate(0, 1, 0)
+	if err := stor.Remove("CMP-SLOW"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	got, err := stor.GetResolvedBetween(base, base.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("GetResolvedBetween: %v", err)
+	}
+	if len(got) != 1 || got[0].ComplaintID != "CMP-FAST" || got[0].Description != "No supply" || got[0].ResolutionTime != 90*time.Minute {
+		t.Fatalf("September = %+v, want CMP-FAST resolved in 1h30m", got)
+	}
+	got, _ = stor.GetResolvedBetween(base.AddDate(0, 1, 0), base.AddDate(0, 2, 0))
+	if len(got) != 1 || got[0].Belt != "Tokarva" || got[0].ResolutionTime != 30*24*time.Hour {
+		t.Errorf("October = %+v, want CMP-SLOW resolved in 30 days", got)
+	}
+
+	// Reopening clears the stored resolution time.
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "CMP-FAST"}}); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got, _ := stor.GetResolvedBetween(base, base.AddDate(0, 1, 0)); len(got) != 0 {
+		t.Errorf("reopened complaint still reported: %+v", got)
+	}
+}
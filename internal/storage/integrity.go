@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// compactFreeFraction is the share of free pages in the database file
+// above which startup compacts it with VACUUM. Retention and resolutions
+// delete rows but SQLite keeps their pages, so a long-running install
+// grows a file mostly made of holes.
+const compactFreeFraction = 0.25
+
+// checkIntegrity runs SQLite's quick_check and logs what it finds. A
+// damaged database is still loaded, as far as it reads, so complaints keep
+// flowing; restore a backup to repair it. Returns whether it is intact.
+func (s *Storage) checkIntegrity() bool {
+	rows, err := s.db.Query(`PRAGMA quick_check(10)`)
+	if err != nil {
+		log.Printf("⚠️  Database integrity check failed to run: %v", err)
+		return false
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			log.Printf("⚠️  Database integrity check failed to run: %v", err)
+			return false
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("⚠️  Database integrity check failed to run: %v", err)
+		return false
+	}
+	if len(problems) > 0 {
+		log.Printf("❌ %s failed its integrity check (restore a backup): %s", dbFile, strings.Join(problems, "; "))
+		return false
+	}
+	return true
+}
+
+// compact rewrites the database file without its free pages once they
+// pass compactFreeFraction, logging the space reclaimed.
+func (s *Storage) compact() {
+	var pages, free, pageSize int64
+	if err := s.db.QueryRow(`PRAGMA page_count`).Scan(&pages); err != nil {
+		log.Printf("⚠️  Failed to read database size: %v", err)
+		return
+	}
+	if err := s.db.QueryRow(`PRAGMA freelist_count`).Scan(&free); err != nil {
+		log.Printf("⚠️  Failed to read database free pages: %v", err)
+		return
+	}
+	if pages == 0 || float64(free) < compactFreeFraction*float64(pages) {
+		return
+	}
+	if err := s.db.QueryRow(`PRAGMA page_size`).Scan(&pageSize); err != nil {
+		log.Printf("⚠️  Failed to read database page size: %v", err)
+		return
+	}
+	if _, err := s.db.Exec(`VACUUM`); err != nil {
+		log.Printf("⚠️  Failed to compact %s: %v", dbFile, err)
+		return
+	}
+	log.Printf("🧹 Compacted %s: %d of %d pages were free, %.1f MB reclaimed", dbFile, free, pages, float64(free*pageSize)/(1<<20))
+}
+
+// legacyRow is one complaint of complaints.csv.
+type legacyRow struct {
+	complaintID  string
+	messageID    string
+	apiID        string
+	consumerName string
+}
+
+// legacyRepairs counts what readLegacyCSV fixed in complaints.csv.
+type legacyRepairs struct {
+	Duplicates int  // rows merged into an earlier row of the same complaint
+	Malformed  int  // rows without a complaint ID or unparseable, skipped
+	Wide       int  // rows with more than four columns; the rest was ignored
+	Truncated  bool // the last line was cut off mid-write and dropped
+}
+
+// String describes the repairs for the log, or "" when there were none.
+func (r legacyRepairs) String() string {
+	var parts []string
+	if r.Duplicates > 0 {
+		parts = append(parts, fmt.Sprintf("merged %d duplicate rows", r.Duplicates))
+	}
+	if r.Malformed > 0 {
+		parts = append(parts, fmt.Sprintf("skipped %d malformed rows", r.Malformed))
+	}
+	if r.Wide > 0 {
+		parts = append(parts, fmt.Sprintf("cut %d rows to four columns", r.Wide))
+	}
+	if r.Truncated {
+		parts = append(parts, "dropped a truncated last line")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// readLegacyCSV parses complaints.csv (complaint ID, Telegram message ID,
+// API ID, consumer name) as the old appender left it: one complaint per
+// ID, a later row filling in the fields an earlier one lacked, rows of
+// any width, and a last line a crash may have cut short. A last line
+// without its newline and with fewer than four columns is taken as cut
+// short.
+func readLegacyCSV(r io.Reader) ([]legacyRow, legacyRepairs, error) {
+	var rep legacyRepairs
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, rep, err
+	}
+	complete := len(data) == 0 || data[len(data)-1] == '\n'
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	var records [][]string
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			rep.Malformed++
+			continue
+		}
+		if err != nil {
+			return nil, rep, err
+		}
+		records = append(records, record)
+	}
+	if n := len(records); n > 0 && !complete && len(records[n-1]) < 4 {
+		records = records[:n-1]
+		rep.Truncated = true
+	}
+
+	var rows []legacyRow
+	index := make(map[string]int)
+	for i, record := range records {
+		if i == 0 && (record[0] == "ComplaintID" || record[0] == "complaint_id") {
+			continue // header
+		}
+		if len(record) > 4 {
+			rep.Wide++
+		}
+		record = append(record, "", "", "")[:4]
+		row := legacyRow{
+			complaintID:  strings.TrimSpace(record[0]),
+			messageID:    record[1],
+			apiID:        record[2],
+			consumerName: record[3],
+		}
+		if row.complaintID == "" {
+			rep.Malformed++
+			continue
+		}
+		j, dup := index[row.complaintID]
+		if !dup {
+			index[row.complaintID] = len(rows)
+			rows = append(rows, row)
+			continue
+		}
+		rep.Duplicates++
+		prev := &rows[j]
+		if row.messageID != "" {
+			prev.messageID = row.messageID
+		}
+		if row.apiID != "" {
+			prev.apiID = row.apiID
+		}
+		if row.consumerName != "" {
+			prev.consumerName = row.consumerName
+		}
+	}
+	return rows, rep, nil
+}
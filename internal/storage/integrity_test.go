@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadLegacyCSV(t *testing.T) {
+	in := "ComplaintID,MessageID,APIID,ConsumerName\n" +
+		"C-1,,API-1,Ramesh\n" +
+		"C-2,200,API-2,\"Patel, Mehul\"\n" +
+		"C-1,100,,\n" + // appended again after the message was sent
+		",300,API-3,Nobody\n" +
+		"C-3,400,API-4,Sita,extra\n" +
+		"C-4,5"
+	rows, rep, err := readLegacyCSV(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []legacyRow{
+		{"C-1", "100", "API-1", "Ramesh"},
+		{"C-2", "200", "API-2", "Patel, Mehul"},
+		{"C-3", "400", "API-4", "Sita"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %+v, want %+v", rows, want)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+	if rep != (legacyRepairs{Duplicates: 1, Malformed: 1, Wide: 1, Truncated: true}) {
+		t.Errorf("repairs = %+v", rep)
+	}
+
+	// A complete last row without its newline is kept.
+	rows, rep, _ = readLegacyCSV(strings.NewReader("C-1,1,A,Name"))
+	if len(rows) != 1 || rep.String() != "" {
+		t.Errorf("unterminated complete row: rows %+v, repairs %q", rows, rep)
+	}
+}
+
+func TestMigrateDamagedLegacyCSV(t *testing.T) {
+	withTempCWD(t)
+	if err := os.WriteFile(legacyCSVFile, []byte("C-1,,API-1,Ramesh\nC-1,100,,\nC-2,2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	if got := stor.GetAllSeenComplaints(); len(got) != 1 || got[0] != "C-1" {
+		t.Errorf("migrated complaints = %v, want only C-1", got)
+	}
+	if stor.GetMessageID("C-1") != "100" || stor.GetAPIID("C-1") != "API-1" {
+		t.Errorf("C-1 = message %q, API ID %q; want the duplicate rows merged", stor.GetMessageID("C-1"), stor.GetAPIID("C-1"))
+	}
+	if _, err := os.Stat(legacyCSVFile + ".bak"); err != nil {
+		t.Errorf("legacy CSV should be renamed after migration: %v", err)
+	}
+}
+
+func TestCompactReclaimsFreePages(t *testing.T) {
+	withTempCWD(t)
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if !stor.checkIntegrity() {
+		t.Fatal("a fresh database should pass the integrity check")
+	}
+
+	var records []Record
+	for i := range 500 {
+		records = append(records, Record{ComplaintID: fmt.Sprintf("C-%d", i), Description: strings.Repeat("d", 2000)})
+	}
+	if err := stor.SaveMultiple(records); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stor.db.Exec(`DELETE FROM complaints`); err != nil {
+		t.Fatal(err)
+	}
+	var free int
+	if err := stor.db.QueryRow(`PRAGMA freelist_count`).Scan(&free); err != nil || free == 0 {
+		t.Fatalf("freelist_count = %d, %v; want free pages after the delete", free, err)
+	}
+
+	stor.compact()
+	if err := stor.db.QueryRow(`PRAGMA freelist_count`).Scan(&free); err != nil || free != 0 {
+		t.Errorf("freelist_count after compact = %d, %v; want 0", free, err)
+	}
+}
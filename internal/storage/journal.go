@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Journal operations.
+const (
+	// JournalTelegramSend covers a complaint's Telegram message from just
+	// before it is sent until its message ID is persisted or the send is
+	// dead-lettered. Payload holds the complaint JSON.
+	JournalTelegramSend = "telegram.send"
+
+	// JournalAnnounce covers a new complaint from the transaction that
+	// saves it until it has been published to the notification channels.
+	// Payload holds the complaint JSON. See Record.Announce.
+	JournalAnnounce = "complaint.announce"
+)
+
+// JournalEntry is an operation that was started but not finished.
+type JournalEntry struct {
+	ID          int64
+	Op          string
+	ComplaintID string
+	Payload     string
+	CreatedAt   time.Time
+}
+
+// JournalAppend records that op on complaintID is about to happen. Call
+// JournalDone with the returned ID once its outcome is persisted; an entry
+// still present at startup means the process died in between, and
+// ReplayJournal hands it back.
+//
+// The entry is committed to the database before JournalAppend returns, so
+// it survives a crash at any later point.
+func (s *Storage) JournalAppend(op, complaintID, payload string) (int64, error) {
+	res, err := s.db.Exec(`INSERT INTO journal (op, complaint_id, payload, created_at) VALUES (?, ?, ?, ?)`,
+		op, complaintID, s.seal(payload), time.Now().UTC().Format(historyTimeLayout))
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// JournalDone removes a finished entry.
+func (s *Storage) JournalDone(id int64) error {
+	_, err := s.db.Exec(`DELETE FROM journal WHERE id = ?`, id)
+	return err
+}
+
+// JournalDoneFor removes every op entry for complaintID, for callers that
+// did not journal the operation themselves (SaveMultiple journals
+// announcements).
+func (s *Storage) JournalDoneFor(op, complaintID string) error {
+	_, err := s.db.Exec(`DELETE FROM journal WHERE op = ? AND complaint_id = ?`, op, complaintID)
+	return err
+}
+
+// journalAnnouncements journals the announcement of each record that
+// carries one, in tx.
+func (s *Storage) journalAnnouncements(tx *sql.Tx, records []Record) error {
+	now := time.Now().UTC().Format(historyTimeLayout)
+	for _, r := range records {
+		if r.Announce == "" {
+			continue
+		}
+		if _, err := tx.Exec(`INSERT INTO journal (op, complaint_id, payload, created_at) VALUES (?, ?, ?, ?)`,
+			JournalAnnounce, r.ComplaintID, s.seal(r.Announce), now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplayJournal passes every unfinished op entry, oldest first, to replay
+// and removes the ones it handles without error. Entries for complaints that
+// are no longer stored are dropped unseen. Call once at startup, before
+// anything appends new entries. Returns how many entries were replayed.
+func (s *Storage) ReplayJournal(op string, replay func(JournalEntry) error) (int, error) {
+	rows, err := s.db.Query(`SELECT id, op, complaint_id, payload, created_at FROM journal WHERE op = ? ORDER BY id`, op)
+	if err != nil {
+		return 0, err
+	}
+	var entries []JournalEntry
+	for rows.Next() {
+		var e JournalEntry
+		var payload, created sql.NullString
+		if err := rows.Scan(&e.ID, &e.Op, &e.ComplaintID, &payload, &created); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		e.Payload = s.reveal(payload.String)
+		e.CreatedAt = parseHistoryTime(created.String)
+		entries = append(entries, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, e := range entries {
+		if s.Exists(e.ComplaintID) {
+			if err := replay(e); err != nil {
+				continue // keep it for the next startup
+			}
+			n++
+		}
+		if err := s.JournalDone(e.ID); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJournalReplay(t *testing.T) {
+	withTempCWD(t)
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "CMP-1"}, {ComplaintID: "CMP-2"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	done, err := stor.JournalAppend(JournalTelegramSend, "CMP-1", "finished")
+	if err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := stor.JournalDone(done); err != nil {
+		t.Fatalf("done: %v", err)
+	}
+	stor.JournalAppend(JournalTelegramSend, "CMP-1", "interrupted")
+	stor.JournalAppend(JournalTelegramSend, "CMP-2", "replay fails")
+	stor.JournalAppend(JournalTelegramSend, "CMP-GONE", "complaint removed")
+
+	// Entries survive a restart.
+	if err := stor.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	stor, err = New()
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	var seen []string
+	n, err := stor.ReplayJournal(JournalTelegramSend, func(e JournalEntry) error {
+		seen = append(seen, e.ComplaintID+":"+e.Payload)
+		if e.ComplaintID == "CMP-2" {
+			return errors.New("telegram down")
+		}
+		return nil
+	})
+	if err != nil || n != 1 {
+		t.Fatalf("replay: got %d, %v; want 1", n, err)
+	}
+	if len(seen) != 2 || seen[0] != "CMP-1:interrupted" || seen[1] != "CMP-2:replay fails" {
+		t.Errorf("replayed: got %v", seen)
+	}
+
+	// Only the failed replay is left for next time.
+	seen = nil
+	stor.ReplayJournal(JournalTelegramSend, func(e JournalEntry) error {
+		seen = append(seen, e.ComplaintID)
+		return nil
+	})
+	if len(seen) != 1 || seen[0] != "CMP-2" {
+		t.Errorf("second replay: got %v", seen)
+	}
+}
+
+func TestSaveMultipleJournalsAnnouncements(t *testing.T) {
+	withTempCWD(t)
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	if err := stor.SaveMultiple([]Record{
+		{ComplaintID: "CMP-1", Announce: `{"complain_no":"CMP-1"}`},
+		{ComplaintID: "CMP-2", Announce: `{"complain_no":"CMP-2"}`},
+		{ComplaintID: "CMP-3"},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	// CMP-1 was announced before the "crash".
+	if err := stor.JournalDoneFor(JournalAnnounce, "CMP-1"); err != nil {
+		t.Fatalf("done: %v", err)
+	}
+
+	var seen []string
+	n, err := stor.ReplayJournal(JournalAnnounce, func(e JournalEntry) error {
+		seen = append(seen, e.ComplaintID+":"+e.Payload)
+		return nil
+	})
+	if err != nil || n != 1 || len(seen) != 1 || seen[0] != `CMP-2:{"complain_no":"CMP-2"}` {
+		t.Errorf("replay = %d, %v, %v; want only CMP-2", n, err, seen)
+	}
+
+	// Other operations' entries are left alone.
+	stor.JournalAppend(JournalTelegramSend, "CMP-3", "sending")
+	if n, _ := stor.ReplayJournal(JournalAnnounce, func(JournalEntry) error { return nil }); n != 0 {
+		t.Errorf("announcement replay took %d entries of another operation", n)
+	}
+	if n, _ := stor.ReplayJournal(JournalTelegramSend, func(JournalEntry) error { return nil }); n != 1 {
+		t.Errorf("send replay = %d, want the send entry still there", n)
+	}
+}
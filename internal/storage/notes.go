@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Note is an intermediate comment staff add to a complaint before it is
+// resolved, such as "Lineman dispatched 14:30". Notes outlive the
+// complaint's active row and go with its history into the archive.
+type Note struct {
+	Author    string
+	AuthorID  int64
+	Text      string
+	CreatedAt time.Time
+}
+
+// AddNote appends a note to complaintID. A zero CreatedAt is stored as now.
+func (s *Storage) AddNote(complaintID string, n Note) error {
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = historyNow()
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO complaint_notes (complaint_id, author, author_id, text, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, complaintID, n.Author, n.AuthorID, strings.TrimSpace(n.Text), n.CreatedAt.UTC().Format(historyTimeLayout))
+	return err
+}
+
+// GetNotes returns complaintID's notes, oldest first.
+func (s *Storage) GetNotes(complaintID string) ([]Note, error) {
+	notes, err := s.queryNotes(`WHERE complaint_id = ?`, complaintID)
+	return notes[complaintID], err
+}
+
+// GetLatestNote returns the most recent note on complaintID, if any.
+func (s *Storage) GetLatestNote(complaintID string) (Note, bool) {
+	notes, err := s.GetNotes(complaintID)
+	if err != nil || len(notes) == 0 {
+		return Note{}, false
+	}
+	return notes[len(notes)-1], true
+}
+
+// queryNotes loads the notes matching where, by complaint ID, oldest first.
+func (s *Storage) queryNotes(where string, args ...any) (map[string][]Note, error) {
+	rows, err := s.db.Query(`
+		SELECT complaint_id, author, author_id, text, created_at
+		FROM complaint_notes `+where+`
+		ORDER BY created_at, id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string][]Note)
+	for rows.Next() {
+		var id string
+		var n Note
+		var author, created sql.NullString
+		var authorID sql.NullInt64
+		if err := rows.Scan(&id, &author, &authorID, &n.Text, &created); err != nil {
+			return nil, err
+		}
+		n.Author = author.String
+		n.AuthorID = authorID.Int64
+		n.CreatedAt = parseHistoryTime(created.String)
+		out[id] = append(out[id], n)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// Schema versioning. The database records the schema it was last upgraded
+// to in SQLite's user_version; on open, migrate runs the migrations past
+// it, in order, and records each one as it completes. A new persisted
+// field is a migration appended to the list — never an edit to one that
+// has shipped.
+//
+// Databases from before versioning are at version 0 with any prefix of
+// the early migrations already applied, so those are written to be
+// idempotent (ensureColumn skips an existing column).
+
+// migration upgrades the schema by one version.
+type migration struct {
+	name string
+	up   func(*Storage) error
+}
+
+// migrations[i] upgrades a database at version i to i+1.
+var migrations = []migration{
+	{"complaint detail, acknowledgement and category columns", (*Storage).addComplaintColumns},
+	{"history detail, cleanup and resolution columns", (*Storage).addHistoryColumns},
+	{"pending resolutions into conversations", (*Storage).migratePendingResolutions},
+}
+
+// SchemaVersion is the schema version this build writes.
+var SchemaVersion = len(migrations)
+
+// ErrSchemaTooNew is returned by NewEncrypted for a database a newer build
+// has upgraded past SchemaVersion; opening it could lose what that build
+// stores.
+var ErrSchemaTooNew = errors.New("database schema is newer than this build")
+
+// migrate brings the database up to SchemaVersion.
+func (s *Storage) migrate() error {
+	var version int
+	if err := s.db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if version > SchemaVersion {
+		return fmt.Errorf("%w: version %d, this build knows up to %d; upgrade cmon", ErrSchemaTooNew, version, SchemaVersion)
+	}
+	for ; version < SchemaVersion; version++ {
+		m := migrations[version]
+		log.Printf("🗃️  Upgrading database schema to version %d: %s", version+1, m.name)
+		if err := m.up(s); err != nil {
+			return fmt.Errorf("schema migration %d (%s): %w", version+1, m.name, err)
+		}
+		// PRAGMA takes no parameters; version is an int.
+		if _, err := s.db.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, version+1)); err != nil {
+			return fmt.Errorf("record schema version %d: %w", version+1, err)
+		}
+	}
+	return nil
+}
+
+// addComplaintColumns adds the columns the complaints table grew after
+// its first release.
+func (s *Storage) addComplaintColumns() error {
+	for _, col := range []struct{ name, typ string }{
+		{"village", "TEXT"},
+		{"belt", "TEXT"},
+		{"consumer_no", "TEXT"},
+		{"mobile_no", "TEXT"},
+		{"address", "TEXT"},
+		{"area", "TEXT"},
+		{"description", "TEXT"},
+		{"complain_date", "TEXT"},
+		{"latitude", "REAL"},
+		{"longitude", "REAL"},
+		{"notified_at", "DATETIME"},
+		{"acknowledged_at", "DATETIME"},
+		{"acknowledged_by", "TEXT"},
+		{"acknowledged_by_id", "INTEGER"},
+		{"unseen_reminded_at", "DATETIME"},
+		{"tg_message_text", "TEXT"},
+		{"age_footer_days", "INTEGER"},
+		{"category", "TEXT"},
+	} {
+		if err := s.ensureComplaintColumn(col.name, col.typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addHistoryColumns adds the columns complaint_history grew after its
+// first release.
+func (s *Storage) addHistoryColumns() error {
+	for _, col := range []struct{ name, typ string }{
+		{"data_issues", "TEXT"},
+		{"tg_message_id", "TEXT"},
+		{"voice_note_file_id", "TEXT"},
+		{"attachments", "TEXT"},
+		{"tg_cleaned_message_id", "TEXT"},
+		{"resolution_seconds", "INTEGER"},
+		{"category", "TEXT"},
+	} {
+		if err := s.ensureColumn("complaint_history", col.name, col.typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestMigrateRecordsSchemaVersion(t *testing.T) {
+	withTempCWD(t)
+
+	// A database from before versioning: user_version 0, some columns
+	// already added by the old ensure-on-every-start code.
+	db, err := sql.Open("sqlite", dbFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`
+		CREATE TABLE complaints (complaint_id TEXT PRIMARY KEY, tg_message_id TEXT, wa_message_id TEXT, api_id TEXT, consumer_name TEXT, village TEXT, belt TEXT);
+		INSERT INTO complaints (complaint_id, api_id, belt) VALUES ('C-1', 'API-1', 'dahod');
+	`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New on an unversioned database: %v", err)
+	}
+	var version int
+	if err := stor.db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil || version != SchemaVersion {
+		t.Errorf("user_version = %d, %v; want %d", version, err, SchemaVersion)
+	}
+	if stor.GetBelt("C-1") != "dahod" {
+		t.Errorf("belt = %q, want the row kept through the upgrade", stor.GetBelt("C-1"))
+	}
+
+	// A newer build's database is refused rather than half-understood.
+	if _, err := stor.db.Exec(`PRAGMA user_version = 999`); err != nil {
+		t.Fatal(err)
+	}
+	stor.Close()
+	if _, err := New(); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("New on a newer schema = %v, want ErrSchemaTooNew", err)
+	}
+}
@@ -5,16 +5,24 @@
 //  2. In-memory cache for fast lookups (O(1) instead of O(n) DB queries)
 //
 // Thread-safety:
-//   - All operations are protected by a RWMutex
+//   - Lookups take a read lock on the in-memory maps only
+//   - Writers are serialized separately and lock the maps just long enough
+//     to update them after the database write, so lookups never wait on disk
 //   - Safe for concurrent access from multiple goroutines
 //
 // Migration:
-//   - On first run, it automatically migrates existing complaints.csv to SQLite
+//   - The schema is versioned in SQLite's user_version and upgraded on open
+//     by numbered migrations (see schema.go)
+//   - On first run, it automatically migrates existing complaints.csv to SQLite,
+//     merging duplicate rows and dropping a truncated last line
+//   - Every start runs SQLite's quick_check and compacts a file that is
+//     mostly free pages (see integrity.go)
 package storage
 
 import (
+	"context"
+	"crypto/cipher"
 	"database/sql"
-	"encoding/csv"
 	"fmt"
 	"log"
 	"os"
@@ -23,6 +31,8 @@ import (
 	"sync"
 	"time"
 
+	"cmon/internal/geocode"
+
 	_ "modernc.org/sqlite"
 )
 
@@ -40,6 +50,9 @@ type Record struct {
 	ConsumerName string
 	Village      string
 	Belt         string
+	// Category is the complaint type (category.NoSupply, ...) detected at
+	// intake; empty for complaints stored before detection existed.
+	Category string
 
 	// Cached complaint detail fields (sourced from the DGVCL detail API
 	// during scrape, used to render the dashboard without re-fetching).
@@ -49,34 +62,49 @@ type Record struct {
 	Area         string
 	Description  string
 	ComplainDate string
+
+	// Latitude/Longitude come from the optional geocoding step; 0,0 means
+	// not geocoded.
+	Latitude  float64
+	Longitude float64
+
+	// DataIssues is the comma-joined list of quality.Issue codes found at
+	// intake. Kept in complaint_history only, for the daily data-quality
+	// report; it does not need to outlive that.
+	DataIssues string
+
+	// Announce, when set, is the notification payload still to be sent
+	// for this complaint. SaveMultiple journals it (JournalAnnounce) in
+	// the same transaction as the record, so a crash between the save and
+	// the announcement cannot lose it.
+	Announce string
 }
 
 // Storage provides thread-safe storage for complaint data.
 type Storage struct {
+	// writeMu serializes writers, which hold it across their database
+	// work; mu guards the maps and is write-locked only while they change,
+	// so lookups never wait on disk I/O. Take writeMu before mu.
+	writeMu              sync.Mutex
 	mu                   sync.RWMutex
 	db                   *sql.DB
-	seen                 map[string]bool   // complaintID → exists
-	messageIDs           map[string]string // complaintID → Telegram message ID
-	waMessageIDs         map[string]string // complaintID → WhatsApp message ID
-	waMessageToComplaint map[string]string // waMessageID → complaintID (Reverse lookup)
-	apiIDs               map[string]string // complaintID → API ID
-	consumerNames        map[string]string // complaintID → Consumer name
-	villages             map[string]string // complaintID → village
-	belts                map[string]string // complaintID → belt
-	consumerNos          map[string]string // complaintID → consumer account number
-	mobileNos            map[string]string // complaintID → mobile number
-	addresses            map[string]string // complaintID → exact location
-	areas                map[string]string // complaintID → area
-	descriptions         map[string]string // complaintID → description
-	complainDates        map[string]string // complaintID → complain_date
-}
-
-// PendingResolution stores info about a complaint awaiting resolution note
-type PendingResolution struct {
-	ComplaintNumber string
-	MessageID       string
-	OriginalText    string
-	PromptMessageID int
+	aead                 cipher.AEAD              // seals PII columns; nil = stored in the clear
+	seen                 map[string]bool          // complaintID → exists
+	messageIDs           map[string]string        // complaintID → Telegram message ID
+	waMessageIDs         map[string]string        // complaintID → WhatsApp message ID
+	waMessageToComplaint map[string]string        // waMessageID → complaintID (Reverse lookup)
+	apiIDs               map[string]string        // complaintID → API ID
+	consumerNames        map[string]string        // complaintID → Consumer name
+	villages             map[string]string        // complaintID → village
+	belts                map[string]string        // complaintID → belt
+	categories           map[string]string        // complaintID → complaint category
+	consumerNos          map[string]string        // complaintID → consumer account number
+	mobileNos            map[string]string        // complaintID → mobile number
+	addresses            map[string]string        // complaintID → exact location
+	areas                map[string]string        // complaintID → area
+	descriptions         map[string]string        // complaintID → description
+	complainDates        map[string]string        // complaintID → complain_date
+	coordinates          map[string]geocode.Point // complaintID → geocoded location (absent if unknown)
 }
 
 // New creates a new Storage instance, connects to SQLite, and loads into memory.
@@ -87,6 +115,14 @@ type PendingResolution struct {
 // state. Recoverable schema-evolution failures (column-ensure) are returned as
 // an error so the caller can decide.
 func New() (*Storage, error) {
+	return NewEncrypted(nil)
+}
+
+// NewEncrypted is New with consumer PII encrypted at rest under key (see
+// crypt.go); a nil key stores it in the clear, like New. Returns
+// ErrEncryptionKey when the database was encrypted under another key or
+// is encrypted and key is nil.
+func NewEncrypted(key []byte) (*Storage, error) {
 	s := &Storage{
 		seen:                 make(map[string]bool),
 		messageIDs:           make(map[string]string),
@@ -96,12 +132,22 @@ func New() (*Storage, error) {
 		consumerNames:        make(map[string]string),
 		villages:             make(map[string]string),
 		belts:                make(map[string]string),
+		categories:           make(map[string]string),
 		consumerNos:          make(map[string]string),
 		mobileNos:            make(map[string]string),
 		addresses:            make(map[string]string),
 		areas:                make(map[string]string),
 		descriptions:         make(map[string]string),
 		complainDates:        make(map[string]string),
+		coordinates:          make(map[string]geocode.Point),
+	}
+
+	if key != nil {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		s.aead = aead
 	}
 
 	// Connect to SQLite
@@ -140,45 +186,121 @@ func New() (*Storage, error) {
 			consumer_name TEXT,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		);
-		CREATE TABLE IF NOT EXISTS pending_resolutions (
+		CREATE TABLE IF NOT EXISTS complaint_history (
+			complaint_id TEXT PRIMARY KEY,
+			consumer_no TEXT,
+			consumer_name TEXT,
+			village TEXT,
+			belt TEXT,
+			description TEXT,
+			complain_date TEXT,
+			first_seen_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			resolved_at DATETIME
+		);
+		CREATE INDEX IF NOT EXISTS idx_complaint_history_consumer ON complaint_history (consumer_no, first_seen_at);
+		CREATE TABLE IF NOT EXISTS geocode_cache (
+			query TEXT PRIMARY KEY,
+			latitude REAL,
+			longitude REAL,
+			found INTEGER NOT NULL DEFAULT 0,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE IF NOT EXISTS channel_messages (
+			channel TEXT NOT NULL,
+			complaint_id TEXT NOT NULL,
+			ref TEXT NOT NULL,
+			PRIMARY KEY (channel, complaint_id)
+		);
+		CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE IF NOT EXISTS dead_letters (
+			complaint_id TEXT PRIMARY KEY,
+			api_id TEXT,
+			stage TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			payload TEXT,
+			first_failed_at DATETIME,
+			last_failed_at DATETIME,
+			next_retry_at DATETIME
+		);
+		CREATE TABLE IF NOT EXISTS journal (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			op TEXT NOT NULL,
+			complaint_id TEXT NOT NULL,
+			payload TEXT,
+			created_at DATETIME
+		);
+		CREATE TABLE IF NOT EXISTS conversations (
 			user_id INTEGER PRIMARY KEY,
+			flow TEXT NOT NULL,
+			state TEXT NOT NULL,
 			complaint_id TEXT,
 			message_id TEXT,
 			original_text TEXT,
 			prompt_message_id INTEGER,
-			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			data TEXT,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE IF NOT EXISTS complaint_notes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			complaint_id TEXT NOT NULL,
+			author TEXT,
+			author_id INTEGER,
+			text TEXT NOT NULL,
+			created_at DATETIME
+		);
+		CREATE INDEX IF NOT EXISTS idx_complaint_notes_complaint ON complaint_notes (complaint_id);
+		CREATE TABLE IF NOT EXISTS deferred_sends (
+			complaint_id TEXT PRIMARY KEY,
+			payload TEXT,
+			queued_at DATETIME
 		);
 	`)
 	if err != nil {
 		log.Fatalf("❌ Failed to create tables: %v", err)
 	}
 
-	for _, col := range []struct{ name, typ string }{
-		{"village", "TEXT"},
-		{"belt", "TEXT"},
-		{"consumer_no", "TEXT"},
-		{"mobile_no", "TEXT"},
-		{"address", "TEXT"},
-		{"area", "TEXT"},
-		{"description", "TEXT"},
-		{"complain_date", "TEXT"},
-	} {
-		if err := s.ensureComplaintColumn(col.name, col.typ); err != nil {
-			return nil, err
-		}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := s.checkEncryptionKey(); err != nil {
+		db.Close()
+		return nil, err
 	}
 
 	// Run migration from old complaints.csv if needed
 	s.migrateFromCSV()
 
+	// Seed history with complaints that predate the history table so the
+	// first repeat after upgrade is still recognised.
+	s.backfillHistory()
+	s.backfillResolutionTimes()
+
+	if err := s.encryptExisting(); err != nil {
+		return nil, fmt.Errorf("encrypt existing data: %w", err)
+	}
+
+	// Startup integrity pass; a file left mostly empty by deletions is
+	// compacted, unless it is damaged and VACUUM could make it worse.
+	if s.checkIntegrity() {
+		s.compact()
+	}
+
 	// Load data from DB into memory maps
 	s.loadFromDB()
 
 	return s, nil
 }
 
-// migrateFromCSV parses the legacy complaints.csv file, inserts all records
-// into SQLite, and renames the CSV to .bak to prevent re-migration.
+// migrateFromCSV parses the legacy complaints.csv file, repairing what the
+// old appender left behind (see readLegacyCSV), inserts all records into
+// SQLite, and renames the CSV to .bak to prevent re-migration.
 func (s *Storage) migrateFromCSV() {
 	if _, err := os.Stat(legacyCSVFile); os.IsNotExist(err) {
 		return // No CSV file to migrate
@@ -193,12 +315,14 @@ func (s *Storage) migrateFromCSV() {
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+	rows, repairs, err := readLegacyCSV(file)
 	if err != nil {
 		log.Printf("⚠️  Failed to read CSV for migration: %v", err)
 		return
 	}
+	if r := repairs.String(); r != "" {
+		log.Printf("🧹 Repaired %s while migrating: %s", legacyCSVFile, r)
+	}
 
 	// Begin transaction
 	tx, err := s.db.Begin()
@@ -219,32 +343,10 @@ func (s *Storage) migrateFromCSV() {
 	defer stmt.Close()
 
 	migratedCount := 0
-	for i, record := range records {
-		if i == 0 && len(record) > 0 && (record[0] == "ComplaintID" || record[0] == "complaint_id") {
-			continue // Skip header
-		}
-		if len(record) < 1 {
-			continue
-		}
-
-		complaintID := record[0]
-		tgMessageID := ""
-		apiID := ""
-		consumerName := ""
-
-		if len(record) >= 2 {
-			tgMessageID = record[1]
-		}
-		if len(record) >= 3 {
-			apiID = record[2]
-		}
-		if len(record) >= 4 {
-			consumerName = record[3]
-		}
-
-		_, err := stmt.Exec(complaintID, tgMessageID, apiID, consumerName)
+	for _, row := range rows {
+		_, err := stmt.Exec(row.complaintID, row.messageID, row.apiID, s.seal(row.consumerName))
 		if err != nil {
-			log.Printf("⚠️  Failed to migrate record %s: %v", complaintID, err)
+			log.Printf("⚠️  Failed to migrate record %s: %v", row.complaintID, err)
 			continue
 		}
 		migratedCount++
@@ -269,7 +371,7 @@ func (s *Storage) migrateFromCSV() {
 
 // loadFromDB loads all complaint data from SQLite into the in-memory maps.
 func (s *Storage) loadFromDB() {
-	rows, err := s.db.Query(`SELECT complaint_id, tg_message_id, wa_message_id, api_id, consumer_name, village, belt, consumer_no, mobile_no, address, area, description, complain_date FROM complaints`)
+	rows, err := s.db.Query(`SELECT complaint_id, tg_message_id, wa_message_id, api_id, consumer_name, village, belt, category, consumer_no, mobile_no, address, area, description, complain_date, latitude, longitude FROM complaints`)
 	if err != nil {
 		log.Fatalf("❌ Failed to query database on load: %v", err)
 	}
@@ -277,9 +379,10 @@ func (s *Storage) loadFromDB() {
 
 	count := 0
 	for rows.Next() {
-		var complaintID, tgMessageID, waMessageID, apiID, consumerName, village, belt sql.NullString
+		var complaintID, tgMessageID, waMessageID, apiID, consumerName, village, belt, category sql.NullString
 		var consumerNo, mobileNo, address, area, description, complainDate sql.NullString
-		if err := rows.Scan(&complaintID, &tgMessageID, &waMessageID, &apiID, &consumerName, &village, &belt, &consumerNo, &mobileNo, &address, &area, &description, &complainDate); err != nil {
+		var lat, lon sql.NullFloat64
+		if err := rows.Scan(&complaintID, &tgMessageID, &waMessageID, &apiID, &consumerName, &village, &belt, &category, &consumerNo, &mobileNo, &address, &area, &description, &complainDate, &lat, &lon); err != nil {
 			log.Printf("⚠️  Failed to scan row on load: %v", err)
 			continue
 		}
@@ -297,7 +400,7 @@ func (s *Storage) loadFromDB() {
 				s.apiIDs[complaintID.String] = apiID.String
 			}
 			if consumerName.Valid {
-				s.consumerNames[complaintID.String] = consumerName.String
+				s.consumerNames[complaintID.String] = s.reveal(consumerName.String)
 			}
 			if village.Valid {
 				s.villages[complaintID.String] = village.String
@@ -305,14 +408,17 @@ func (s *Storage) loadFromDB() {
 			if belt.Valid {
 				s.belts[complaintID.String] = belt.String
 			}
+			if category.Valid && category.String != "" {
+				s.categories[complaintID.String] = category.String
+			}
 			if consumerNo.Valid {
 				s.consumerNos[complaintID.String] = consumerNo.String
 			}
 			if mobileNo.Valid {
-				s.mobileNos[complaintID.String] = mobileNo.String
+				s.mobileNos[complaintID.String] = s.reveal(mobileNo.String)
 			}
 			if address.Valid {
-				s.addresses[complaintID.String] = address.String
+				s.addresses[complaintID.String] = s.reveal(address.String)
 			}
 			if area.Valid {
 				s.areas[complaintID.String] = area.String
@@ -323,6 +429,9 @@ func (s *Storage) loadFromDB() {
 			if complainDate.Valid {
 				s.complainDates[complaintID.String] = complainDate.String
 			}
+			if p := (geocode.Point{Lat: lat.Float64, Lon: lon.Float64}); p.Valid() {
+				s.coordinates[complaintID.String] = p
+			}
 			count++
 		}
 	}
@@ -341,13 +450,6 @@ func (s *Storage) IsNew(complaintID string) bool {
 	return !s.seen[complaintID]
 }
 
-// MarkAsSeen marks a complaint as seen in memory only.
-func (s *Storage) MarkAsSeen(complaintID string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.seen[complaintID] = true
-}
-
 // GetMessageID retrieves the Telegram message ID for a complaint.
 func (s *Storage) GetMessageID(complaintID string) string {
 	s.mu.RLock()
@@ -364,10 +466,10 @@ func (s *Storage) GetWAMessageID(complaintID string) string {
 
 // SetMessageID updates both memory and DB with a new Telegram message ID.
 func (s *Storage) SetMessageID(complaintID, messageID string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 
-	if !s.seen[complaintID] {
+	if !s.Exists(complaintID) {
 		return fmt.Errorf("complaint %s not found in storage", complaintID)
 	}
 
@@ -375,19 +477,26 @@ func (s *Storage) SetMessageID(complaintID, messageID string) error {
 		log.Printf("⚠️  Failed to persist Telegram message ID for %s: %v", complaintID, err)
 		return err
 	}
+	if messageID != "" {
+		if err := s.markNotified(complaintID); err != nil {
+			log.Printf("⚠️  Failed to record notification time for %s: %v", complaintID, err)
+		}
+	}
 
+	s.mu.Lock()
 	s.messageIDs[complaintID] = messageID
+	s.mu.Unlock()
 	return nil
 }
 
 // SetWAMessageID updates both memory and DB with a new WhatsApp Message ID.
 // This is called asynchronously when a WA message is successfully sent.
 func (s *Storage) SetWAMessageID(complaintID, waMessageID string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 
 	// Need existence check so we don't save WA message ID if complaint is bad or deleted
-	if !s.seen[complaintID] {
+	if !s.Exists(complaintID) {
 		return fmt.Errorf("complaint %s not found in storage", complaintID)
 	}
 
@@ -398,6 +507,8 @@ func (s *Storage) SetWAMessageID(complaintID, waMessageID string) error {
 		return err
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if oldWAMessageID := s.waMessageIDs[complaintID]; oldWAMessageID != "" && oldWAMessageID != waMessageID {
 		delete(s.waMessageToComplaint, oldWAMessageID)
 	}
@@ -426,10 +537,12 @@ func (s *Storage) GetComplaintIDByWAMessageID(waMessageID string) (string, bool)
 		return "", false
 	}
 	// Opportunistic cache fill
+	s.writeMu.Lock()
 	s.mu.Lock()
 	s.waMessageIDs[complaintID] = waMessageID
 	s.waMessageToComplaint[waMessageID] = complaintID
 	s.mu.Unlock()
+	s.writeMu.Unlock()
 
 	return complaintID, true
 }
@@ -462,6 +575,14 @@ func (s *Storage) GetBelt(complaintID string) string {
 	return s.belts[complaintID]
 }
 
+// GetCategory retrieves the stored category for a complaint; "" when it
+// was stored before categories were detected.
+func (s *Storage) GetCategory(complaintID string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.categories[complaintID]
+}
+
 // GetConsumerNo retrieves the cached consumer account number for a complaint.
 func (s *Storage) GetConsumerNo(complaintID string) string {
 	s.mu.RLock()
@@ -504,6 +625,14 @@ func (s *Storage) GetComplainDate(complaintID string) string {
 	return s.complainDates[complaintID]
 }
 
+// GetCoordinates returns the geocoded location for a complaint, if any.
+func (s *Storage) GetCoordinates(complaintID string) (geocode.Point, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.coordinates[complaintID]
+	return p, ok
+}
+
 // SetDetails persists the cached complaint detail fields for a known complaint.
 //
 // Used by the dashboard layer to lazy-backfill rows that pre-date the schema
@@ -511,10 +640,10 @@ func (s *Storage) GetComplainDate(complaintID string) string {
 // All fields are written atomically; the in-memory cache is only updated
 // after the DB write succeeds so memory never gets ahead of disk.
 func (s *Storage) SetDetails(complaintID, consumerNo, mobileNo, address, area, description, complainDate string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 
-	if !s.seen[complaintID] {
+	if !s.Exists(complaintID) {
 		return fmt.Errorf("complaint %s not found in storage", complaintID)
 	}
 
@@ -522,10 +651,22 @@ func (s *Storage) SetDetails(complaintID, consumerNo, mobileNo, address, area, d
 		UPDATE complaints
 		SET consumer_no = ?, mobile_no = ?, address = ?, area = ?, description = ?, complain_date = ?
 		WHERE complaint_id = ?
-	`, consumerNo, mobileNo, address, area, description, complainDate, complaintID); err != nil {
+	`, consumerNo, s.seal(mobileNo), s.seal(address), area, description, complainDate, complaintID); err != nil {
 		return err
 	}
 
+	// Keep the history row in step so /consumer and repeat detection see
+	// backfilled consumer numbers. Best-effort: history is advisory.
+	if _, err := s.db.Exec(`
+		UPDATE complaint_history
+		SET consumer_no = ?, description = ?, complain_date = ?
+		WHERE complaint_id = ?
+	`, consumerNo, description, complainDate, complaintID); err != nil {
+		log.Printf("⚠️  Failed to update history for %s: %v", complaintID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.consumerNos[complaintID] = consumerNo
 	s.mobileNos[complaintID] = mobileNo
 	s.addresses[complaintID] = address
@@ -537,10 +678,10 @@ func (s *Storage) SetDetails(complaintID, consumerNo, mobileNo, address, area, d
 
 // UpdateBelt persists a belt reassignment for an existing complaint.
 func (s *Storage) UpdateBelt(complaintID, belt string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 
-	if !s.seen[complaintID] {
+	if !s.Exists(complaintID) {
 		return fmt.Errorf("complaint %s not found in storage", complaintID)
 	}
 
@@ -548,10 +689,41 @@ func (s *Storage) UpdateBelt(complaintID, belt string) error {
 		return err
 	}
 
+	s.mu.Lock()
 	s.belts[complaintID] = belt
+	s.mu.Unlock()
 	return nil
 }
 
+// GetRecord returns the stored fields of an active complaint, as they would
+// be passed to SaveMultiple, so a removal can be undone.
+func (s *Storage) GetRecord(complaintID string) (Record, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.seen[complaintID] {
+		return Record{}, false
+	}
+	p := s.coordinates[complaintID]
+	return Record{
+		ComplaintID:  complaintID,
+		MessageID:    s.messageIDs[complaintID],
+		WAMessageID:  s.waMessageIDs[complaintID],
+		APIID:        s.apiIDs[complaintID],
+		ConsumerName: s.consumerNames[complaintID],
+		Village:      s.villages[complaintID],
+		Belt:         s.belts[complaintID],
+		Category:     s.categories[complaintID],
+		ConsumerNo:   s.consumerNos[complaintID],
+		MobileNo:     s.mobileNos[complaintID],
+		Address:      s.addresses[complaintID],
+		Area:         s.areas[complaintID],
+		Description:  s.descriptions[complaintID],
+		ComplainDate: s.complainDates[complaintID],
+		Latitude:     p.Lat,
+		Longitude:    p.Lon,
+	}, true
+}
+
 // Exists checks if a complaint exists in memory.
 func (s *Storage) Exists(complaintID string) bool {
 	s.mu.RLock()
@@ -585,6 +757,20 @@ func (s *Storage) GetPendingCountsByBelt() map[string]int {
 	return counts
 }
 
+// GetPendingCountsByFeeder returns the current active complaint count per
+// feeder, as feederOf names it from the complaint's village, area and
+// address (feeder.Map.Lookup); unmapped complaints count under "".
+func (s *Storage) GetPendingCountsByFeeder(feederOf func(places ...string) string) map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for complaintID := range s.seen {
+		counts[feederOf(s.villages[complaintID], s.areas[complaintID], s.addresses[complaintID])]++
+	}
+	return counts
+}
+
 // GetVillageCountsByBelt returns village -> open complaint count for the
 // given belt. The belt argument is matched case-insensitively against the
 // raw canonical belt key stored on each complaint; callers that hold a
@@ -633,8 +819,8 @@ func (s *Storage) GetPendingComplaints() map[string][]string {
 // Existing records are left untouched in the DB (INSERT OR IGNORE) to preserve
 // wa_message_id and other previously saved values.
 func (s *Storage) SaveMultiple(records []Record) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -642,8 +828,8 @@ func (s *Storage) SaveMultiple(records []Record) error {
 	}
 
 	stmt, err := tx.Prepare(`
-		INSERT INTO complaints (complaint_id, tg_message_id, wa_message_id, api_id, consumer_name, village, belt, consumer_no, mobile_no, address, area, description, complain_date)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO complaints (complaint_id, tg_message_id, wa_message_id, api_id, consumer_name, village, belt, category, consumer_no, mobile_no, address, area, description, complain_date, latitude, longitude)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(complaint_id) DO UPDATE SET
 			tg_message_id = CASE
 				WHEN excluded.tg_message_id != '' THEN excluded.tg_message_id
@@ -669,6 +855,10 @@ func (s *Storage) SaveMultiple(records []Record) error {
 				WHEN excluded.belt != '' THEN excluded.belt
 				ELSE complaints.belt
 			END,
+			category = CASE
+				WHEN excluded.category != '' THEN excluded.category
+				ELSE complaints.category
+			END,
 			consumer_no = CASE
 				WHEN excluded.consumer_no != '' THEN excluded.consumer_no
 				ELSE complaints.consumer_no
@@ -692,6 +882,14 @@ func (s *Storage) SaveMultiple(records []Record) error {
 			complain_date = CASE
 				WHEN excluded.complain_date != '' THEN excluded.complain_date
 				ELSE complaints.complain_date
+			END,
+			latitude = CASE
+				WHEN excluded.latitude != 0 OR excluded.longitude != 0 THEN excluded.latitude
+				ELSE complaints.latitude
+			END,
+			longitude = CASE
+				WHEN excluded.latitude != 0 OR excluded.longitude != 0 THEN excluded.longitude
+				ELSE complaints.longitude
 			END
 	`)
 	if err != nil {
@@ -701,18 +899,30 @@ func (s *Storage) SaveMultiple(records []Record) error {
 	defer stmt.Close()
 
 	for _, r := range records {
-		if _, err := stmt.Exec(r.ComplaintID, r.MessageID, r.WAMessageID, r.APIID, r.ConsumerName, r.Village, r.Belt, r.ConsumerNo, r.MobileNo, r.Address, r.Area, r.Description, r.ComplainDate); err != nil {
+		if _, err := stmt.Exec(r.ComplaintID, r.MessageID, r.WAMessageID, r.APIID, s.seal(r.ConsumerName), r.Village, r.Belt, r.Category, r.ConsumerNo, s.seal(r.MobileNo), s.seal(r.Address), r.Area, r.Description, r.ComplainDate, r.Latitude, r.Longitude); err != nil {
 			tx.Rollback()
 			return err
 		}
 	}
 
+	if err := s.upsertHistory(tx, records); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := s.journalAnnouncements(tx, records); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return err
 	}
 
 	// Update memory maps (safe to overwrite — same data for new records;
 	// for duplicates we still want the latest in-memory state).
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	for _, r := range records {
 		s.seen[r.ComplaintID] = true
 		// Only set tg_message_id in memory if we have one (don't blank existing)
@@ -738,6 +948,9 @@ func (s *Storage) SaveMultiple(records []Record) error {
 		if r.Belt != "" {
 			s.belts[r.ComplaintID] = r.Belt
 		}
+		if r.Category != "" {
+			s.categories[r.ComplaintID] = r.Category
+		}
 		if r.ConsumerNo != "" {
 			s.consumerNos[r.ComplaintID] = r.ConsumerNo
 		}
@@ -756,6 +969,9 @@ func (s *Storage) SaveMultiple(records []Record) error {
 		if r.ComplainDate != "" {
 			s.complainDates[r.ComplaintID] = r.ComplainDate
 		}
+		if p := (geocode.Point{Lat: r.Latitude, Lon: r.Longitude}); p.Valid() {
+			s.coordinates[r.ComplaintID] = p
+		}
 	}
 
 	return nil
@@ -763,15 +979,15 @@ func (s *Storage) SaveMultiple(records []Record) error {
 
 // Remove permanently deletes a complaint from SQLite and memory.
 func (s *Storage) Remove(complaintID string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
 
-	if _, err := tx.Exec(`DELETE FROM pending_resolutions WHERE complaint_id = ?`, complaintID); err != nil {
+	if _, err := tx.Exec(`DELETE FROM conversations WHERE complaint_id = ?`, complaintID); err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -781,10 +997,32 @@ func (s *Storage) Remove(complaintID string) error {
 		return err
 	}
 
+	if _, err := tx.Exec(`DELETE FROM channel_messages WHERE complaint_id = ?`, complaintID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM dead_letters WHERE complaint_id = ?`, complaintID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM deferred_sends WHERE complaint_id = ?`, complaintID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := markHistoryResolved(tx, complaintID, s.GetMessageID(complaintID)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return err
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	// Remove WA message ID from reverse index
 	if waMsgID, ok := s.waMessageIDs[complaintID]; ok && waMsgID != "" {
 		delete(s.waMessageToComplaint, waMsgID)
@@ -797,11 +1035,13 @@ func (s *Storage) Remove(complaintID string) error {
 	delete(s.consumerNames, complaintID)
 	delete(s.villages, complaintID)
 	delete(s.belts, complaintID)
+	delete(s.categories, complaintID)
 	delete(s.mobileNos, complaintID)
 	delete(s.addresses, complaintID)
 	delete(s.areas, complaintID)
 	delete(s.descriptions, complaintID)
 	delete(s.complainDates, complaintID)
+	delete(s.coordinates, complaintID)
 
 	return nil
 }
@@ -809,10 +1049,10 @@ func (s *Storage) Remove(complaintID string) error {
 // RemoveIfExists conditionally deletes a complaint from SQLite and memory.
 // Returns true if deleted, false if it didn't exist.
 func (s *Storage) RemoveIfExists(complaintID string) (bool, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 
-	if !s.seen[complaintID] {
+	if !s.Exists(complaintID) {
 		return false, nil
 	}
 
@@ -821,7 +1061,7 @@ func (s *Storage) RemoveIfExists(complaintID string) (bool, error) {
 		return false, err
 	}
 
-	if _, err := tx.Exec(`DELETE FROM pending_resolutions WHERE complaint_id = ?`, complaintID); err != nil {
+	if _, err := tx.Exec(`DELETE FROM conversations WHERE complaint_id = ?`, complaintID); err != nil {
 		tx.Rollback()
 		return false, err
 	}
@@ -831,10 +1071,32 @@ func (s *Storage) RemoveIfExists(complaintID string) (bool, error) {
 		return false, err
 	}
 
+	if _, err := tx.Exec(`DELETE FROM channel_messages WHERE complaint_id = ?`, complaintID); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM dead_letters WHERE complaint_id = ?`, complaintID); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM deferred_sends WHERE complaint_id = ?`, complaintID); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := markHistoryResolved(tx, complaintID, s.GetMessageID(complaintID)); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return false, err
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	// Remove WA message ID from reverse index
 	if waMsgID, ok := s.waMessageIDs[complaintID]; ok && waMsgID != "" {
 		delete(s.waMessageToComplaint, waMsgID)
@@ -847,61 +1109,45 @@ func (s *Storage) RemoveIfExists(complaintID string) (bool, error) {
 	delete(s.consumerNames, complaintID)
 	delete(s.villages, complaintID)
 	delete(s.belts, complaintID)
+	delete(s.categories, complaintID)
 	delete(s.mobileNos, complaintID)
 	delete(s.addresses, complaintID)
 	delete(s.areas, complaintID)
 	delete(s.descriptions, complaintID)
 	delete(s.complainDates, complaintID)
+	delete(s.coordinates, complaintID)
 
 	return true, nil
 }
 
-// GetPendingResolution retrieves a pending resolution from SQLite.
-func (s *Storage) GetPendingResolution(userID int64) (PendingResolution, bool) {
-	var pr PendingResolution
-	err := s.db.QueryRow(`
-		SELECT complaint_id, message_id, original_text, prompt_message_id
-		FROM pending_resolutions
-		WHERE user_id = ?
-	`, userID).Scan(&pr.ComplaintNumber, &pr.MessageID, &pr.OriginalText, &pr.PromptMessageID)
-	if err == sql.ErrNoRows {
-		return pr, false
-	} else if err != nil {
-		log.Printf("⚠️  Failed to query pending resolution for user %d: %v", userID, err)
-		return pr, false
-	}
-	return pr, true
-}
-
-// AddPendingResolution inserts or replaces a pending resolution in SQLite.
-func (s *Storage) AddPendingResolution(userID int64, pr PendingResolution) error {
-	_, err := s.db.Exec(`
-		INSERT OR REPLACE INTO pending_resolutions (user_id, complaint_id, message_id, original_text, prompt_message_id) 
-		VALUES (?, ?, ?, ?, ?)
-	`, userID, pr.ComplaintNumber, pr.MessageID, pr.OriginalText, pr.PromptMessageID)
-	if err != nil {
-		log.Printf("⚠️  Failed to save pending resolution for user %d: %v", userID, err)
-		return err
+// Close gracefully closes the SQLite database connection.
+func (s *Storage) Close() error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if s.db != nil {
+		return s.db.Close()
 	}
 	return nil
 }
 
-// RemovePendingResolution deletes a pending resolution from SQLite.
-func (s *Storage) RemovePendingResolution(userID int64) {
-	_, err := s.db.Exec(`DELETE FROM pending_resolutions WHERE user_id = ?`, userID)
+// Ping checks that the database is reachable and writable by taking the
+// write lock with a throwaway write that is rolled back.
+func (s *Storage) Ping(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		log.Printf("⚠️  Failed to delete pending resolution for user %d: %v", userID, err)
+		return err
 	}
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, `INSERT OR REPLACE INTO settings (key, value) VALUES ('health.ping', '1')`)
+	return err
 }
 
-// Close gracefully closes the SQLite database connection.
-func (s *Storage) Close() error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	if s.db != nil {
-		return s.db.Close()
-	}
-	return nil
+// Backup writes a consistent snapshot of the whole database to path, which
+// must not exist yet. It uses VACUUM INTO, so writers are not blocked for
+// longer than the copy and the snapshot needs no WAL file next to it.
+func (s *Storage) Backup(ctx context.Context, path string) error {
+	_, err := s.db.ExecContext(ctx, `VACUUM INTO ?`, path)
+	return err
 }
 
 // getStorageStats (diagnostic) returns the total rows directly from DB count.
@@ -912,33 +1158,34 @@ func (s *Storage) getStorageStats() (int, error) {
 }
 
 func (s *Storage) ensureComplaintColumn(name, typ string) error {
-	if _, err := s.db.Exec(fmt.Sprintf(`ALTER TABLE complaints ADD COLUMN %s %s`, name, typ)); err != nil {
+	return s.ensureColumn("complaints", name, typ)
+}
+
+// ensureColumn adds a column to table if it is not already present.
+func (s *Storage) ensureColumn(table, name, typ string) error {
+	if _, err := s.db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, name, typ)); err != nil {
 		// Ignore "duplicate column" style errors across SQLite variants.
 		if err.Error() != "SQL logic error: duplicate column name: "+name+" (1)" &&
 			err.Error() != "duplicate column name: "+name {
-			return fmt.Errorf("ensure complaints.%s column: %w", name, err)
+			return fmt.Errorf("ensure %s.%s column: %w", table, name, err)
 		}
 	}
 	return nil
 }
 
-// GenerateLocalComplaintID generates a local complaint ID in format VLDYYYYMMDDSR.
-// SR starts at 01 each day and increments. Thread-safe via s.mu write lock.
-func (s *Storage) GenerateLocalComplaintID() (string, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// GenerateLocalComplaintID generates a local complaint ID in format VLDYYYYMMDDSR,
+// dated with now's day in now's location. SR starts at 01 each day and
+// increments. Thread-safe via s.writeMu.
+func (s *Storage) GenerateLocalComplaintID(now time.Time) (string, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
 
-	// Indian Standard Time (IST) timezone
-	ist, err := time.LoadLocation("Asia/Kolkata")
-	if err != nil {
-		ist = time.Local
-	}
-	dateStr := time.Now().In(ist).Format("20060102")
+	dateStr := now.Format("20060102")
 	prefix := "VLD" + dateStr
 
 	var lastID string
 	query := `SELECT complaint_id FROM complaints WHERE complaint_id LIKE ? ORDER BY complaint_id DESC LIMIT 1`
-	err = s.db.QueryRow(query, prefix+"%").Scan(&lastID)
+	err := s.db.QueryRow(query, prefix+"%").Scan(&lastID)
 
 	seq := 1
 	if err == nil {
@@ -955,3 +1202,83 @@ func (s *Storage) GenerateLocalComplaintID() (string, error) {
 	return fmt.Sprintf("%s%02d", prefix, seq), nil
 }
 
+// GetGeocode implements geocode.Cache. ok=false when query was never looked up.
+func (s *Storage) GetGeocode(query string) (geocode.Point, bool, bool) {
+	var lat, lon sql.NullFloat64
+	var found int
+	err := s.db.QueryRow(`SELECT latitude, longitude, found FROM geocode_cache WHERE query = ?`, query).Scan(&lat, &lon, &found)
+	if err != nil {
+		return geocode.Point{}, false, false
+	}
+	return geocode.Point{Lat: lat.Float64, Lon: lon.Float64}, found == 1, true
+}
+
+// PutGeocode implements geocode.Cache.
+func (s *Storage) PutGeocode(query string, p geocode.Point, found bool) error {
+	f := 0
+	if found {
+		f = 1
+	}
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO geocode_cache (query, latitude, longitude, found, updated_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`, query, p.Lat, p.Lon, f)
+	return err
+}
+
+// GetMessageRef implements notify.MessageRefs: the reference (Slack ts,
+// Discord message ID, …) channel stored for complaintID, or "".
+func (s *Storage) GetMessageRef(channel, complaintID string) string {
+	var ref string
+	if err := s.db.QueryRow(`SELECT ref FROM channel_messages WHERE channel = ? AND complaint_id = ?`, channel, complaintID).Scan(&ref); err != nil {
+		return ""
+	}
+	return ref
+}
+
+// SetMessageRef implements notify.MessageRefs. Rows are dropped with the
+// complaint in Remove.
+func (s *Storage) SetMessageRef(channel, complaintID, ref string) error {
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO channel_messages (channel, complaint_id, ref)
+		VALUES (?, ?, ?)
+	`, channel, complaintID, ref)
+	return err
+}
+
+// GetComplaintIDsByMessageRef returns the stored complaints whose channel
+// ref is ref, such as the members of one Telegram digest, sorted.
+func (s *Storage) GetComplaintIDsByMessageRef(channel, ref string) []string {
+	rows, err := s.db.Query(`SELECT complaint_id FROM channel_messages WHERE channel = ? AND ref = ? ORDER BY complaint_id`, channel, ref)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if rows.Scan(&id) == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// GetSetting returns a runtime setting saved with SetSetting. ok=false when
+// the key was never set.
+func (s *Storage) GetSetting(key string) (string, bool) {
+	var value string
+	if err := s.db.QueryRow(`SELECT value FROM settings WHERE key = ?`, key).Scan(&value); err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// SetSetting persists a runtime setting (admin toggles) across restarts.
+func (s *Storage) SetSetting(key, value string) error {
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO settings (key, value, updated_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+	`, key, value)
+	return err
+}
@@ -1,9 +1,12 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
@@ -230,6 +233,8 @@ func TestUpgradeFromLegacySchema(t *testing.T) {
 		);
 		INSERT INTO complaints (complaint_id, tg_message_id, api_id, consumer_name, village, belt)
 		VALUES ('CMP-LEGACY', '42', 'API-LEGACY', 'Bob', 'Tokarva', 'Bajipura');
+		INSERT INTO pending_resolutions (user_id, complaint_id, message_id, original_text, prompt_message_id)
+		VALUES (5, 'CMP-LEGACY', '42', 'original', 99);
 	`); err != nil {
 		t.Fatalf("seed legacy db: %v", err)
 	}
@@ -263,6 +268,12 @@ func TestUpgradeFromLegacySchema(t *testing.T) {
 		t.Errorf("new MobileNo on legacy row: got %q, want empty", got)
 	}
 
+	// A resolution awaiting remarks carries over as a conversation.
+	if conv, ok := s.GetConversation(5); !ok || conv.Flow != "resolve" || conv.State != "note" ||
+		conv.ComplaintNumber != "CMP-LEGACY" || conv.PromptMessageID != 99 || conv.OriginalText != "original" {
+		t.Errorf("migrated conversation: got %+v, %v", conv, ok)
+	}
+
 	// And the new SetDetails path must work on the legacy row (this is what
 	// the lazy-backfill code path in summary/fetcher.go calls).
 	if err := s.SetDetails("CMP-LEGACY", "CONS-L", "777", "addr", "area", "desc", "2026-05-09"); err != nil {
@@ -276,7 +287,7 @@ func TestUpgradeFromLegacySchema(t *testing.T) {
 	}
 }
 
-func TestRemoveDeletesPendingResolutions(t *testing.T) {
+func TestRemoveDeletesConversations(t *testing.T) {
 	withTempCWD(t)
 
 	stor, err := New()
@@ -294,21 +305,68 @@ func TestRemoveDeletesPendingResolutions(t *testing.T) {
 		t.Fatalf("save complaint: %v", err)
 	}
 
-	if err := stor.AddPendingResolution(7, PendingResolution{
+	if err := stor.SaveConversation(7, Conversation{
+		Flow:            "resolve",
+		State:           "note",
 		ComplaintNumber: "CMP-1",
 		MessageID:       "12345",
 		OriginalText:    "original",
 		PromptMessageID: 99,
 	}); err != nil {
-		t.Fatalf("add pending resolution: %v", err)
+		t.Fatalf("save conversation: %v", err)
 	}
 
 	if err := stor.Remove("CMP-1"); err != nil {
 		t.Fatalf("remove complaint: %v", err)
 	}
 
-	if _, exists := stor.GetPendingResolution(7); exists {
-		t.Fatal("pending resolution should be deleted when complaint is removed")
+	if _, exists := stor.GetConversation(7); exists {
+		t.Fatal("conversation should be deleted when complaint is removed")
+	}
+}
+
+func TestConversationRoundTrip(t *testing.T) {
+	withTempCWD(t)
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = stor.Close()
+	})
+
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	if err := stor.SaveConversation(7, Conversation{
+		Flow:            "resolve",
+		State:           "remarks",
+		ComplaintNumber: "CMP-1",
+		PromptMessageID: 99,
+		Data:            map[string]string{"remark": "Fuse replaced"},
+		UpdatedAt:       base,
+	}); err != nil {
+		t.Fatalf("save conversation: %v", err)
+	}
+	if err := stor.SaveConversation(8, Conversation{Flow: "resolve", State: "note", UpdatedAt: base.Add(time.Hour)}); err != nil {
+		t.Fatalf("save conversation: %v", err)
+	}
+
+	conv, ok := stor.GetConversation(7)
+	if !ok || conv.State != "remarks" || conv.Data["remark"] != "Fuse replaced" || !conv.UpdatedAt.Equal(base) {
+		t.Fatalf("GetConversation: got %+v, %v", conv, ok)
+	}
+
+	idle, err := stor.GetIdleConversations(base.Add(30 * time.Minute))
+	if err != nil {
+		t.Fatalf("GetIdleConversations: %v", err)
+	}
+	if len(idle) != 1 || idle[7].ComplaintNumber != "CMP-1" {
+		t.Errorf("idle conversations: got %+v, want only user 7's", idle)
+	}
+
+	stor.DeleteConversation(7)
+	if _, ok := stor.GetConversation(7); ok {
+		t.Error("conversation should be gone after DeleteConversation")
 	}
 }
 
@@ -323,7 +381,8 @@ func TestGenerateLocalComplaintID(t *testing.T) {
 		_ = stor.Close()
 	})
 
-	id1, err := stor.GenerateLocalComplaintID()
+	now := time.Date(2026, 3, 10, 23, 30, 0, 0, time.UTC).In(time.FixedZone("IST", 5*3600+1800))
+	id1, err := stor.GenerateLocalComplaintID(now)
 	if err != nil {
 		t.Fatalf("GenerateLocalComplaintID 1: %v", err)
 	}
@@ -332,6 +391,9 @@ func TestGenerateLocalComplaintID(t *testing.T) {
 	if len(id1) < 13 || id1[:3] != "VLD" {
 		t.Errorf("expected VLDYYYYMMDD01 format, got %q", id1)
 	}
+	if id1 != "VLD2026031101" {
+		t.Errorf("expected the first ID of 11 Mar in now's zone, got %q", id1)
+	}
 
 	// Save a record with that ID to DB
 	if err := stor.SaveMultiple([]Record{{
@@ -342,7 +404,7 @@ func TestGenerateLocalComplaintID(t *testing.T) {
 	}
 
 	// Generate next
-	id2, err := stor.GenerateLocalComplaintID()
+	id2, err := stor.GenerateLocalComplaintID(now)
 	if err != nil {
 		t.Fatalf("GenerateLocalComplaintID 2: %v", err)
 	}
@@ -361,3 +423,234 @@ func TestGenerateLocalComplaintID(t *testing.T) {
 }
 
 
+
+func TestUnseenReminderLifecycle(t *testing.T) {
+	withTempCWD(t)
+
+	base := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	clock := base
+	historyNow = func() time.Time { return clock }
+	t.Cleanup(func() { historyNow = time.Now })
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	if err := stor.SaveMultiple([]Record{
+		{ComplaintID: "CMP-1", ConsumerName: "Ramesh", Belt: "dahod"},
+		{ComplaintID: "CMP-2"},
+		{ComplaintID: "CMP-3"}, // never notified: no Telegram message ID
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := stor.SetMessageID("CMP-1", "101"); err != nil {
+		t.Fatalf("SetMessageID: %v", err)
+	}
+	clock = base.Add(30 * time.Minute)
+	if err := stor.SetMessageID("CMP-2", "102"); err != nil {
+		t.Fatalf("SetMessageID: %v", err)
+	}
+
+	// Only CMP-1 was notified before the cutoff.
+	unseen, err := stor.GetUnacknowledged(base.Add(10 * time.Minute))
+	if err != nil {
+		t.Fatalf("GetUnacknowledged: %v", err)
+	}
+	if len(unseen) != 1 || unseen[0].ComplaintID != "CMP-1" || unseen[0].MessageID != "101" || unseen[0].ConsumerName != "Ramesh" {
+		t.Fatalf("unseen before cutoff: got %+v", unseen)
+	}
+	if !unseen[0].NotifiedAt.Equal(base) {
+		t.Errorf("NotifiedAt: got %v, want %v", unseen[0].NotifiedAt, base)
+	}
+
+	if id, ok := stor.GetComplaintIDByMessageID("102"); !ok || id != "CMP-2" {
+		t.Errorf("reverse lookup: got %q, %v", id, ok)
+	}
+
+	// First acknowledgment wins.
+	if _, _, ok := stor.GetAcknowledgment("CMP-2"); ok {
+		t.Error("GetAcknowledgment before any acknowledgment should report false")
+	}
+	if ok, err := stor.MarkAcknowledged("CMP-2", "@asha", 7); err != nil || !ok {
+		t.Fatalf("MarkAcknowledged: %v, %v", ok, err)
+	}
+	if ok, _ := stor.MarkAcknowledged("CMP-2", "@vijay", 8); ok {
+		t.Error("second acknowledgment should report false")
+	}
+	if by, byID, ok := stor.GetAcknowledgment("CMP-2"); !ok || by != "@asha" || byID != 7 {
+		t.Errorf("GetAcknowledgment = %q, %d, %v; want @asha, 7", by, byID, ok)
+	}
+
+	clock = base.Add(2 * time.Hour)
+	unseen, err = stor.GetUnacknowledged(clock)
+	if err != nil {
+		t.Fatalf("GetUnacknowledged: %v", err)
+	}
+	if len(unseen) != 1 || unseen[0].ComplaintID != "CMP-1" {
+		t.Fatalf("unseen after ack: got %+v", unseen)
+	}
+
+	// Reminded complaints are not re-surfaced.
+	if err := stor.MarkUnseenReminded([]string{"CMP-1"}); err != nil {
+		t.Fatalf("MarkUnseenReminded: %v", err)
+	}
+	if unseen, _ := stor.GetUnacknowledged(clock); len(unseen) != 0 {
+		t.Errorf("unseen after reminder: got %+v", unseen)
+	}
+}
+
+func TestSettingsRoundTrip(t *testing.T) {
+	withTempCWD(t)
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	if _, ok := stor.GetSetting("flag.translation"); ok {
+		t.Fatal("unset key should report ok=false")
+	}
+	for _, v := range []string{"off", "on"} {
+		if err := stor.SetSetting("flag.translation", v); err != nil {
+			t.Fatalf("SetSetting: %v", err)
+		}
+	}
+	if got, ok := stor.GetSetting("flag.translation"); !ok || got != "on" {
+		t.Errorf("GetSetting = %q, %v; want on, true", got, ok)
+	}
+}
+
+func TestMessageRefsClearedOnRemove(t *testing.T) {
+	withTempCWD(t)
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "CMP-1"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := stor.SetMessageRef("slack", "CMP-1", "1700000000.000100"); err != nil {
+		t.Fatalf("SetMessageRef: %v", err)
+	}
+	if err := stor.SetMessageRef("discord", "CMP-1", "555"); err != nil {
+		t.Fatalf("SetMessageRef: %v", err)
+	}
+	if got := stor.GetMessageRef("slack", "CMP-1"); got != "1700000000.000100" {
+		t.Errorf("slack ref = %q", got)
+	}
+	if got := stor.GetMessageRef("discord", "CMP-1"); got != "555" {
+		t.Errorf("discord ref = %q", got)
+	}
+
+	if err := stor.Remove("CMP-1"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if got := stor.GetMessageRef("slack", "CMP-1"); got != "" {
+		t.Errorf("slack ref after Remove = %q, want empty", got)
+	}
+}
+
+func TestBackupSnapshotsDatabase(t *testing.T) {
+	withTempCWD(t)
+
+	stor, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]Record{{ComplaintID: "CMP-1", ConsumerName: "Asha"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	if err := stor.Backup(context.Background(), "snapshot.db"); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	db, err := sql.Open("sqlite", "snapshot.db")
+	if err != nil {
+		t.Fatalf("open snapshot: %v", err)
+	}
+	defer db.Close()
+	var name string
+	if err := db.QueryRow(`SELECT consumer_name FROM complaints WHERE complaint_id = 'CMP-1'`).Scan(&name); err != nil || name != "Asha" {
+		t.Errorf("snapshot row: got %q, %v", name, err)
+	}
+}
+
+// benchmarkStorage returns a Storage holding n complaints.
+func benchmarkStorage(b *testing.B, n int) *Storage {
+	b.Helper()
+	cwd, _ := os.Getwd()
+	if err := os.Chdir(b.TempDir()); err != nil {
+		b.Fatalf("chdir: %v", err)
+	}
+	b.Cleanup(func() { os.Chdir(cwd) })
+
+	stor, err := New()
+	if err != nil {
+		b.Fatalf("New: %v", err)
+	}
+	b.Cleanup(func() { stor.Close() })
+	records := make([]Record, n)
+	for i := range records {
+		records[i] = Record{ComplaintID: fmt.Sprintf("CMP-%06d", i), MessageID: fmt.Sprint(i)}
+	}
+	if err := stor.SaveMultiple(records); err != nil {
+		b.Fatalf("save: %v", err)
+	}
+	return stor
+}
+
+// BenchmarkLookups measures IsNew/GetMessageID from parallel workers
+// against 50k seen complaints, idle and while another goroutine saves a
+// complaint every millisecond. Lookups only wait for the in-memory update,
+// not for SQLite, so they should stay close to the idle figure.
+func BenchmarkLookups(b *testing.B) {
+	const seen = 50000
+	lookups := func(b *testing.B, stor *Storage) {
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				id := fmt.Sprintf("CMP-%06d", i%seen)
+				if stor.IsNew(id) || stor.GetMessageID(id) == "" {
+					b.Errorf("lookup of %s failed", id)
+				}
+				i++
+			}
+		})
+	}
+
+	b.Run("idle", func(b *testing.B) {
+		stor := benchmarkStorage(b, seen)
+		b.ResetTimer()
+		lookups(b, stor)
+	})
+
+	b.Run("while saving", func(b *testing.B) {
+		stor := benchmarkStorage(b, seen)
+		stop, done := make(chan struct{}), make(chan struct{})
+		go func() {
+			defer close(done)
+			tick := time.NewTicker(time.Millisecond)
+			defer tick.Stop()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				case <-tick.C:
+				}
+				stor.SaveMultiple([]Record{{ComplaintID: fmt.Sprintf("NEW-%06d", i)}})
+			}
+		}()
+		b.ResetTimer()
+		lookups(b, stor)
+		b.StopTimer()
+		close(stop)
+		<-done
+	})
+}
@@ -0,0 +1,190 @@
+package summary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cmon/internal/feeder"
+
+	"github.com/fogleman/gg"
+)
+
+// groupBy is how RenderTable groups: "belt", "area" or "feeder"; see
+// SetGroupBy. Mutated only at boot and from package tests.
+var groupBy = "belt"
+
+// SetGroupBy switches the combined summary between belt groups (the
+// default) and area or feeder groups, each closed by a subtotal row, under
+// a bar chart of complaints per area or feeder. Belt-wise images are
+// unaffected.
+func SetGroupBy(mode string) {
+	groupBy = mode
+}
+
+// feeders maps complaints to their feeder; see SetFeeders. nil maps none.
+var feeders *feeder.Map
+
+// SetFeeders sets the locality → feeder map (FEEDER_MAP_FILE) used to tag
+// complaints and for the feeder grouping.
+func SetFeeders(m *feeder.Map) {
+	feeders = m
+}
+
+// Area chart layout (scaled)
+const (
+	subtotalH      = 50 * renderScale
+	chartBarH      = 40 * renderScale
+	chartBottomGap = 30 * renderScale
+	chartLabelW    = 340.0 * renderScale
+	chartMaxBars   = 8
+)
+
+// areaCount is one bar of the area chart.
+type areaCount struct {
+	area  string
+	count int
+}
+
+func getArea(c Complaint) string {
+	a := strings.TrimSpace(c.Area)
+	if a == "" {
+		return "Unknown Area"
+	}
+	return a
+}
+
+// getFeeder is c's feeder, looked up when the complaint was not tagged.
+func getFeeder(c Complaint) string {
+	if c.Feeder != "" {
+		return c.Feeder
+	}
+	if f := feeders.Lookup(c.Village, c.Area, c.Address); f != "" {
+		return f
+	}
+	return "Unmapped"
+}
+
+// groupComplaintsByArea groups complaints by area, the area with most
+// complaints first (ties alphabetical), rows ordered as in belt groups.
+func groupComplaintsByArea(complaints []Complaint) []complaintGroup {
+	return groupComplaintsBy(complaints, getArea)
+}
+
+// groupComplaintsByFeeder is groupComplaintsByArea for feeders.
+func groupComplaintsByFeeder(complaints []Complaint) []complaintGroup {
+	return groupComplaintsBy(complaints, getFeeder)
+}
+
+func groupComplaintsBy(complaints []Complaint, key func(Complaint) string) []complaintGroup {
+	grouped := make(map[string][]Complaint)
+	for _, c := range complaints {
+		area := key(c)
+		grouped[area] = append(grouped[area], c)
+	}
+
+	groups := make([]complaintGroup, 0, len(grouped))
+	for area, items := range grouped {
+		vCounts := villageCounts(items)
+		sortGroupRows(items, vCounts)
+		groups = append(groups, complaintGroup{area: area, complaints: items, villages: vCounts})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if len(groups[i].complaints) != len(groups[j].complaints) {
+			return len(groups[i].complaints) > len(groups[j].complaints)
+		}
+		return groups[i].area < groups[j].area
+	})
+	return groups
+}
+
+// areaChart returns the chart bars for groups (already worst first): one
+// per area or feeder, the tail past chartMaxBars folded into "Other areas"
+// ("Other feeders").
+func areaChart(groups []complaintGroup) []areaCount {
+	other := "Other areas"
+	if groupBy == "feeder" {
+		other = "Other feeders"
+	}
+	bars := make([]areaCount, 0, min(len(groups), chartMaxBars))
+	for i, g := range groups {
+		if i == chartMaxBars-1 && len(groups) > chartMaxBars {
+			rest := 0
+			for _, o := range groups[i:] {
+				rest += len(o.complaints)
+			}
+			return append(bars, areaCount{area: fmt.Sprintf("%s (%d)", other, len(groups)-i), count: rest})
+		}
+		bars = append(bars, areaCount{area: g.area, count: len(g.complaints)})
+	}
+	return bars
+}
+
+// chartHeight is the space the area chart takes above the table.
+func chartHeight(bars int) float64 {
+	if bars == 0 {
+		return 0
+	}
+	return float64(bars)*chartBarH + chartBottomGap
+}
+
+// drawAreaChart draws one horizontal bar per area, scaled to the largest.
+func drawAreaChart(dc *gg.Context, boldFont, regularFont string, x, y, width float64, bars []areaCount) {
+	maxCount := 1
+	for _, b := range bars {
+		maxCount = max(maxCount, b.count)
+	}
+	countW := float64(90 * renderScale)
+	barMaxW := width - chartLabelW - countW
+
+	for i, b := range bars {
+		rowY := y + float64(i)*chartBarH
+
+		dc.LoadFontFace(boldFont, fontSize)
+		dc.SetColor(theme.Text)
+		label := b.area
+		if lines := wrapText(dc, label, chartLabelW-cellPaddingX); len(lines) > 0 {
+			label = lines[0]
+		}
+		dc.DrawStringAnchored(label, x+chartLabelW-cellPaddingX, rowY+chartBarH/2, 1, 0.5)
+
+		barW := barMaxW * float64(b.count) / float64(maxCount)
+		dc.SetColor(theme.HeaderBg)
+		dc.DrawRoundedRectangle(x+chartLabelW, rowY+6*renderScale, barW, chartBarH-12*renderScale, 6*renderScale)
+		dc.Fill()
+
+		dc.LoadFontFace(regularFont, fontSize)
+		dc.SetColor(theme.Text)
+		dc.DrawStringAnchored(fmt.Sprintf("%d", b.count), x+chartLabelW+barW+cellPaddingX, rowY+chartBarH/2, 0, 0.5)
+	}
+}
+
+// drawAreaHeader is the area counterpart of drawGroupHeader.
+func drawAreaHeader(dc *gg.Context, boldFont string, x, y, width float64, area string, count int) {
+	dc.SetColor(theme.HeaderBg)
+	dc.DrawRectangle(x, y, width, float64(groupHeaderH))
+	dc.Fill()
+
+	dc.LoadFontFace(boldFont, headerFontSz-2*renderScale)
+	dc.SetColor(theme.HeaderText)
+	label := fmt.Sprintf("%s  •  %d complaints", area, count)
+	dc.DrawStringAnchored(label, x+cellPaddingX, y+float64(groupHeaderH)/2, 0, 0.5)
+}
+
+// drawSubtotal closes an area group with its complaint count.
+func drawSubtotal(dc *gg.Context, boldFont string, x, y, width float64, area string, count int) {
+	dc.SetColor(theme.VillageHeaderBg)
+	dc.DrawRectangle(x, y, width, float64(subtotalH))
+	dc.Fill()
+
+	dc.SetColor(theme.Border)
+	dc.SetLineWidth(0.5 * renderScale)
+	dc.DrawLine(x, y+float64(subtotalH), x+width, y+float64(subtotalH))
+	dc.Stroke()
+
+	dc.LoadFontFace(boldFont, fontSize)
+	dc.SetColor(theme.VillageHeaderText)
+	text := fmt.Sprintf("Subtotal %s: %d", area, count)
+	dc.DrawStringAnchored(text, x+width-cellPaddingX, y+float64(subtotalH)/2, 1, 0.5)
+}
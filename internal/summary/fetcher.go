@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"cmon/internal/api"
 	"cmon/internal/session"
 	"cmon/internal/storage"
 )
@@ -18,6 +19,7 @@ import (
 type pendingComplaint struct {
 	complaintID string
 	apiID       string
+	firstSeen   time.Time
 }
 
 // FetchAllPendingDetails returns one Complaint per active complaint in storage.
@@ -44,6 +46,11 @@ func FetchAllPendingDetails(sc *session.Client, stor *storage.Storage) ([]Compla
 	complaints := make([]Complaint, 0, len(complaintIDs))
 	var needsBackfill []pendingComplaint
 
+	firstSeen, err := stor.GetFirstSeen()
+	if err != nil {
+		log.Printf("  ⚠️  Failed to read first-seen times: %v", err)
+	}
+
 	for _, id := range complaintIDs {
 		apiID := stor.GetAPIID(id)
 		if apiID == "" {
@@ -51,9 +58,9 @@ func FetchAllPendingDetails(sc *session.Client, stor *storage.Storage) ([]Compla
 			continue
 		}
 
-		c := buildFromStorage(stor, id, apiID)
+		c := buildFromStorage(stor, id, apiID, firstSeen[id])
 		if needsRefetch(c) {
-			needsBackfill = append(needsBackfill, pendingComplaint{id, apiID})
+			needsBackfill = append(needsBackfill, pendingComplaint{id, apiID, firstSeen[id]})
 			continue
 		}
 		complaints = append(complaints, c)
@@ -68,14 +75,18 @@ func FetchAllPendingDetails(sc *session.Client, stor *storage.Storage) ([]Compla
 	if len(complaints) == 0 {
 		return nil, fmt.Errorf("no complaints with valid API IDs")
 	}
+	for i, c := range complaints {
+		complaints[i].Feeder = feeders.Lookup(c.Village, c.Area, c.Address)
+	}
 
 	log.Printf("📊 Returning %d complaint records (storage-backed)", len(complaints))
 	return complaints, nil
 }
 
 // buildFromStorage assembles a Complaint entirely from cached storage values.
-func buildFromStorage(stor *storage.Storage, complaintID, apiID string) Complaint {
+func buildFromStorage(stor *storage.Storage, complaintID, apiID string, firstSeen time.Time) Complaint {
 	date := stor.GetComplainDate(complaintID)
+	point, _ := stor.GetCoordinates(complaintID)
 	return Complaint{
 		ComplainNo:        complaintID,
 		Name:              stor.GetConsumerName(complaintID),
@@ -90,10 +101,26 @@ func buildFromStorage(stor *storage.Storage, complaintID, apiID string) Complain
 		TelegramMessageID: stor.GetMessageID(complaintID),
 		WhatsAppMessageID: stor.GetWAMessageID(complaintID),
 		APIID:             apiID,
-		AgeMinutes:        computeAgeMinutes(date, time.Now()),
+		AgeMinutes:        computeAgeMinutes(date, firstSeen, time.Now()),
+		FirstSeenAt:       firstSeen,
+		Latitude:          point.Lat,
+		Longitude:         point.Lon,
+		LatestNote:        latestNote(stor, complaintID),
 	}
 }
 
+// latestNote renders complaintID's most recent note for the summary.
+func latestNote(stor *storage.Storage, complaintID string) string {
+	n, ok := stor.GetLatestNote(complaintID)
+	if !ok {
+		return ""
+	}
+	if n.Author == "" {
+		return n.Text
+	}
+	return n.Author + ": " + n.Text
+}
+
 // needsRefetch returns true when a Complaint built from storage is missing the
 // detail fields that scrape would normally cache. Used to decide which rows to
 // backfill from the API. ConsumerName alone is not enough — it has been stored
@@ -127,17 +154,17 @@ func backfillDetails(sc *session.Client, stor *storage.Storage, pending []pendin
 	var wg sync.WaitGroup
 	for i, p := range pending {
 		wg.Add(1)
-		go func(idx int, complaintID, apiID string) {
+		go func(idx int, complaintID, apiID string, firstSeen time.Time) {
 			defer wg.Done()
-			c, err := fetchAndPersistDetail(sc, stor, complaintID, apiID)
+			c, err := fetchAndPersistDetail(sc, stor, complaintID, apiID, firstSeen)
 			if err != nil {
 				log.Printf("  ⚠️  Backfill failed for %s: %v. Falling back to storage values.", complaintID, err)
-				fallback := buildFromStorage(stor, complaintID, apiID)
+				fallback := buildFromStorage(stor, complaintID, apiID, firstSeen)
 				results[idx] = result{c: &fallback, ok: true}
 				return
 			}
 			results[idx] = result{c: c, ok: true}
-		}(i, p.complaintID, p.apiID)
+		}(i, p.complaintID, p.apiID, p.firstSeen)
 	}
 	wg.Wait()
 
@@ -153,10 +180,8 @@ func backfillDetails(sc *session.Client, stor *storage.Storage, pending []pendin
 // fetchAndPersistDetail hits the DGVCL detail API for a single complaint,
 // writes the result into storage so future reads bypass the API, and returns
 // the populated Complaint for immediate dashboard rendering.
-func fetchAndPersistDetail(sc *session.Client, stor *storage.Storage, complaintID, apiID string) (*Complaint, error) {
-	apiURL := fmt.Sprintf("https://complaint.dgvcl.com/api/complaint-record/%s", apiID)
-
-	body, err := sc.GetJSON(apiURL)
+func fetchAndPersistDetail(sc *session.Client, stor *storage.Storage, complaintID, apiID string, firstSeen time.Time) (*Complaint, error) {
+	body, err := api.GetRecordJSON(sc, apiID)
 	if err != nil {
 		return nil, fmt.Errorf("fetch failed: %w", err)
 	}
@@ -198,7 +223,9 @@ func fetchAndPersistDetail(sc *session.Client, stor *storage.Storage, complaintI
 		TelegramMessageID: stor.GetMessageID(complaintID),
 		WhatsAppMessageID: stor.GetWAMessageID(complaintID),
 		APIID:             apiID,
-		AgeMinutes:        computeAgeMinutes(date, time.Now()),
+		AgeMinutes:        computeAgeMinutes(date, firstSeen, time.Now()),
+		FirstSeenAt:       firstSeen,
+		LatestNote:        latestNote(stor, complaintID),
 	}, nil
 }
 
@@ -0,0 +1,225 @@
+package summary
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"cmon/internal/belt"
+)
+
+// Filter selects complaints for a narrowed summary. It is a list of terms
+// that must all hold, written "field op value" with no spaces around the
+// operator, e.g.
+//
+//	area=Valod age>24h description~"no supply"
+//
+// Text fields (area, village, belt, description) take = (equal), != (not
+// equal) and ~ (contains), all ignoring case; belt also matches the belt's
+// display label. age takes >, >=, < and <= against a duration such as 90m,
+// 24h or 3d. Values with spaces are double-quoted.
+type Filter struct {
+	expr  string
+	terms []filterTerm
+}
+
+type filterTerm struct {
+	field string
+	op    string
+	value string
+	age   time.Duration
+}
+
+// filterFields maps each field name to the operators it accepts.
+var filterFields = map[string][]string{
+	"area":        {"=", "!=", "~"},
+	"village":     {"=", "!=", "~"},
+	"belt":        {"=", "!=", "~"},
+	"description": {"=", "!=", "~"},
+	"age":         {">", ">=", "<", "<="},
+}
+
+const filterLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// filterOps is every operator, two-character ones first so ">=" is not
+// read as ">".
+var filterOps = []string{">=", "<=", "!=", "=", "~", ">", "<"}
+
+// ParseFilter parses a filter expression. An empty expression returns a
+// nil Filter, which matches everything.
+func ParseFilter(expr string) (*Filter, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, nil
+	}
+
+	f := &Filter{expr: expr}
+	rest := expr
+	for rest != "" {
+		term, next, err := parseFilterTerm(rest)
+		if err != nil {
+			return nil, err
+		}
+		f.terms = append(f.terms, term)
+		rest = strings.TrimSpace(next)
+	}
+	return f, nil
+}
+
+// parseFilterTerm reads one term off the front of s and returns the rest.
+func parseFilterTerm(s string) (filterTerm, string, error) {
+	end := 0
+	for end < len(s) && strings.IndexByte(filterLetters, s[end]) >= 0 {
+		end++
+	}
+	field := strings.ToLower(s[:end])
+	ops, ok := filterFields[field]
+	if !ok {
+		word := s
+		if i := strings.IndexAny(word, " \t"); i >= 0 {
+			word = word[:i]
+		}
+		return filterTerm{}, "", fmt.Errorf("unknown filter %q (want area, village, belt, description or age)", word)
+	}
+
+	s = s[end:]
+	op := ""
+	for _, candidate := range filterOps {
+		if strings.HasPrefix(s, candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" || !containsOp(ops, op) {
+		return filterTerm{}, "", fmt.Errorf("%s takes %s", field, strings.Join(ops, " "))
+	}
+	s = s[len(op):]
+
+	var value string
+	if strings.HasPrefix(s, `"`) {
+		closing := strings.Index(s[1:], `"`)
+		if closing < 0 {
+			return filterTerm{}, "", fmt.Errorf("unterminated quote in %s filter", field)
+		}
+		value, s = s[1:closing+1], s[closing+2:]
+	} else {
+		i := strings.IndexAny(s, " \t")
+		if i < 0 {
+			i = len(s)
+		}
+		value, s = s[:i], s[i:]
+	}
+	if value == "" {
+		return filterTerm{}, "", fmt.Errorf("%s%s needs a value", field, op)
+	}
+
+	t := filterTerm{field: field, op: op, value: value}
+	if field == "age" {
+		d, err := parseAge(value)
+		if err != nil {
+			return filterTerm{}, "", err
+		}
+		t.age = d
+	}
+	return t, s, nil
+}
+
+func containsOp(ops []string, op string) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAge parses a Go duration, plus whole days written "3d".
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (want e.g. 90m, 24h or 3d)", s)
+	}
+	return d, nil
+}
+
+// String returns the expression f was parsed from.
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.expr
+}
+
+// Match reports whether c satisfies every term. A nil Filter matches all.
+func (f *Filter) Match(c *Complaint) bool {
+	if f == nil {
+		return true
+	}
+	for _, t := range f.terms {
+		if !t.match(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the complaints f matches, in their original order.
+func (f *Filter) Apply(complaints []Complaint) []Complaint {
+	if f == nil {
+		return complaints
+	}
+	var out []Complaint
+	for i := range complaints {
+		if f.Match(&complaints[i]) {
+			out = append(out, complaints[i])
+		}
+	}
+	return out
+}
+
+func (t filterTerm) match(c *Complaint) bool {
+	if t.field == "age" {
+		age := time.Duration(c.AgeMinutes) * time.Minute
+		switch t.op {
+		case ">":
+			return age > t.age
+		case ">=":
+			return age >= t.age
+		case "<":
+			return age < t.age
+		}
+		return age <= t.age
+	}
+
+	var values []string
+	switch t.field {
+	case "area":
+		values = []string{c.Area}
+	case "village":
+		values = []string{c.Village}
+	case "belt":
+		values = []string{c.Belt, belt.StyleFor(c.Belt).Label}
+	case "description":
+		values = []string{c.Description}
+	}
+
+	want := strings.ToLower(strings.TrimSpace(t.value))
+	hit := false
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if t.op == "~" && strings.Contains(v, want) || t.op != "~" && v == want {
+			hit = true
+			break
+		}
+	}
+	if t.op == "!=" {
+		return !hit
+	}
+	return hit
+}
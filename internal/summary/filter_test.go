@@ -0,0 +1,57 @@
+package summary
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterMatches(t *testing.T) {
+	complaints := []Complaint{
+		{ComplainNo: "1", Area: "Valod", Village: "Buhari", Description: "No supply since morning", AgeMinutes: 30 * 60},
+		{ComplainNo: "2", Area: "valod", Village: "Valod", Description: "Meter burnt", AgeMinutes: 2 * 60},
+		{ComplainNo: "3", Area: "Bajipura", Village: "Bajipura", Description: "No supply", AgeMinutes: 4 * 24 * 60},
+	}
+
+	for _, tc := range []struct {
+		expr string
+		want string
+	}{
+		{"", "1 2 3"},
+		{"area=Valod", "1 2"},
+		{"area=Valod age>24h", "1"},
+		{"area!=valod", "3"},
+		{`description~"no supply"`, "1 3"},
+		{"age>=3d", "3"},
+		{"age<90m", ""},
+		{"village~pura AGE<=5d", "3"},
+	} {
+		f, err := ParseFilter(tc.expr)
+		if err != nil {
+			t.Fatalf("ParseFilter(%q): %v", tc.expr, err)
+		}
+		var got []string
+		for _, c := range f.Apply(complaints) {
+			got = append(got, c.ComplainNo)
+		}
+		if strings.Join(got, " ") != tc.want {
+			t.Errorf("%q matched %v, want %s", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestParseFilterRejectsBadTerms(t *testing.T) {
+	for expr, want := range map[string]string{
+		"feeder=North": "unknown filter",
+		"age=24h":      "age takes",
+		"area>Valod":   "area takes",
+		"age>soon":     "invalid age",
+		`area="Valod`:  "unterminated quote",
+		"area=":        "needs a value",
+		"area = Valod": "area takes",
+	} {
+		_, err := ParseFilter(expr)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseFilter(%q) error = %v, want it to mention %q", expr, err, want)
+		}
+	}
+}
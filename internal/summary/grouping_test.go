@@ -1,8 +1,15 @@
 package summary
 
 import (
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"cmon/internal/feeder"
 )
 
 // TestParseComplaintDate covers every accepted layout plus the rejection
@@ -229,6 +236,20 @@ func TestParseComplaintDateUsesLocalLocation(t *testing.T) {
 	}
 }
 
+func TestParseComplaintDateUsesSetLocation(t *testing.T) {
+	ist := time.FixedZone("IST", 5*3600+1800)
+	SetLocation(ist)
+	t.Cleanup(func() { SetLocation(time.Local) })
+
+	got, ok := parseComplaintDate("2026-03-04 10:11:12")
+	if !ok {
+		t.Fatal("expected parse to succeed")
+	}
+	if want := time.Date(2026, 3, 4, 4, 41, 12, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
 func beltsOf(groups []complaintGroup) []string {
 	out := make([]string, 0, len(groups))
 	for _, g := range groups {
@@ -268,28 +289,233 @@ func TestComputeAgeMinutes(t *testing.T) {
 	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.Local)
 
 	t.Run("two hours ago", func(t *testing.T) {
-		got := computeAgeMinutes("2026-05-10 10:00:00", now)
+		got := computeAgeMinutes("2026-05-10 10:00:00", time.Time{}, now)
 		if got != 120 {
 			t.Errorf("got %d, want 120", got)
 		}
 	})
 
 	t.Run("future date returns zero", func(t *testing.T) {
-		got := computeAgeMinutes("2026-05-11 12:00:00", now)
+		got := computeAgeMinutes("2026-05-11 12:00:00", time.Time{}, now)
 		if got != 0 {
 			t.Errorf("future date should yield 0, got %d", got)
 		}
 	})
 
 	t.Run("unparseable returns zero", func(t *testing.T) {
-		if got := computeAgeMinutes("not a date", now); got != 0 {
+		if got := computeAgeMinutes("not a date", time.Time{}, now); got != 0 {
 			t.Errorf("unparseable date should yield 0, got %d", got)
 		}
 	})
 
 	t.Run("empty returns zero", func(t *testing.T) {
-		if got := computeAgeMinutes("", now); got != 0 {
+		if got := computeAgeMinutes("", time.Time{}, now); got != 0 {
 			t.Errorf("empty should yield 0, got %d", got)
 		}
 	})
+	t.Run("falls back to first seen", func(t *testing.T) {
+		if got := computeAgeMinutes("", now.Add(-3*time.Hour), now); got != 180 {
+			t.Errorf("first seen 3h ago should yield 180, got %d", got)
+		}
+		if got := computeAgeMinutes("2026-05-10 10:00:00", now.Add(-3*time.Hour), now); got != 120 {
+			t.Errorf("complaint date should win over first seen, got %d", got)
+		}
+	})
+}
+
+func TestRowColorMarksOldComplaints(t *testing.T) {
+	defer SetAgeThresholds(agingAfter, overdueAfter)
+	SetAgeThresholds(24*time.Hour, 72*time.Hour)
+
+	for _, tc := range []struct {
+		ageMinutes int64
+		row        int
+		want       color.Color
+	}{
+		{60, 0, theme.RowEven},
+		{60, 1, theme.RowOdd},
+		{60 * 24, 1, theme.RowAging},
+		{60 * 72, 0, theme.RowOverdue},
+	} {
+		if got := rowColor(&Complaint{AgeMinutes: tc.ageMinutes}, tc.row); got != tc.want {
+			t.Errorf("rowColor(age %dm, row %d) = %v, want %v", tc.ageMinutes, tc.row, got, tc.want)
+		}
+	}
+
+	SetAgeThresholds(0, 0)
+	if got := rowColor(&Complaint{AgeMinutes: 60 * 24 * 30}, 0); got != theme.RowEven {
+		t.Errorf("thresholds off: rowColor = %v, want zebra stripe", got)
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	for _, spec := range []string{"", "light", "Dark"} {
+		want := LightTheme
+		if spec == "Dark" {
+			want = DarkTheme
+		}
+		if got, err := LoadTheme(spec); err != nil || got != want {
+			t.Errorf("LoadTheme(%q) = %v, %v; want built-in theme", spec, got, err)
+		}
+	}
+
+	dir := t.TempDir()
+	write := func(name, body string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	got, err := LoadTheme(write("custom.json", `{"base": "dark", "header_bg": "#0f766e", "title_text": "Bardoli SDn", "branding": "DGVCL"}`))
+	if err != nil {
+		t.Fatalf("LoadTheme(file): %v", err)
+	}
+	want := DarkTheme
+	want.HeaderBg = color.RGBA{R: 0x0f, G: 0x76, B: 0x6e, A: 255}
+	want.TitleText, want.Branding = "Bardoli SDn", "DGVCL"
+	if got != want {
+		t.Errorf("LoadTheme(file) = %+v, want %+v", got, want)
+	}
+
+	for name, body := range map[string]string{
+		"bad-colour.json": `{"text": "teal"}`,
+		"bad-base.json":   `{"base": "sepia"}`,
+		"bad-json.json":   `{`,
+	} {
+		if _, err := LoadTheme(write(name, body)); err == nil {
+			t.Errorf("LoadTheme(%s) succeeded, want error", name)
+		}
+	}
+	if _, err := LoadTheme(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("LoadTheme(missing file) succeeded, want error")
+	}
+}
+
+func TestFooterTextAppendsBranding(t *testing.T) {
+	defer SetTheme(theme)
+
+	SetTheme(LightTheme)
+	if got := footerText("Total: 3 pending complaints"); got != "Total: 3 pending complaints" {
+		t.Errorf("no branding: footerText = %q", got)
+	}
+	branded := LightTheme
+	branded.Branding = "DGVCL Valod"
+	SetTheme(branded)
+	if got := footerText("Total: 3 pending complaints"); got != "Total: 3 pending complaints  ·  DGVCL Valod" {
+		t.Errorf("branded: footerText = %q", got)
+	}
+}
+
+// TestPaginateSplitsGroupsAcrossPages checks that pages hold at most the
+// page size, keep belt order, and that a belt split across pages still
+// reports its full count in the group header.
+func TestPaginateSplitsGroupsAcrossPages(t *testing.T) {
+	var in []Complaint
+	for i := 0; i < 4; i++ {
+		in = append(in, Complaint{ComplainNo: fmt.Sprintf("A%d", i), ComplainDate: "2026-03-01", Belt: "Alpha", Village: "Valod"})
+	}
+	for i := 0; i < 3; i++ {
+		in = append(in, Complaint{ComplainNo: fmt.Sprintf("B%d", i), ComplainDate: "2026-03-02", Belt: "Bravo", Village: "Buhari"})
+	}
+	groups := groupComplaints(in)
+
+	pages := paginate(groups, 3)
+	var got [][]string
+	for _, page := range pages {
+		var rows []string
+		for _, g := range page {
+			if g.total() != map[string]int{"Alpha": 4, "Bravo": 3}[g.belt] {
+				t.Errorf("%s on a page: total() = %d, want whole belt", g.belt, g.total())
+			}
+			for _, c := range g.complaints {
+				rows = append(rows, c.ComplainNo)
+			}
+		}
+		got = append(got, rows)
+	}
+	want := [][]string{{"A0", "A1", "A2"}, {"A3", "B0", "B1"}, {"B2"}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("pages = %v, want %v", got, want)
+	}
+
+	if pages := paginate(groups, 0); len(pages) != 1 || len(pages[0]) != 2 {
+		t.Errorf("paginate(max 0) = %d pages, want everything on one", len(pages))
+	}
+}
+
+// TestGroupComplaintsByAreaWorstFirst checks area groups come busiest
+// first and that the chart folds areas past chartMaxBars into one bar.
+func TestGroupComplaintsByAreaWorstFirst(t *testing.T) {
+	in := []Complaint{
+		{ComplainNo: "1", Area: "Feeder B"},
+		{ComplainNo: "2", Area: "Feeder A"},
+		{ComplainNo: "3", Area: "Feeder B"},
+		{ComplainNo: "4", Area: ""},
+	}
+	groups := groupComplaintsByArea(in)
+	var got []string
+	for _, g := range groups {
+		got = append(got, fmt.Sprintf("%s=%d", g.area, g.total()))
+	}
+	if want := "[Feeder B=2 Feeder A=1 Unknown Area=1]"; fmt.Sprint(got) != want {
+		t.Errorf("area groups = %v, want %s", got, want)
+	}
+
+	in = nil
+	for i := 0; i < chartMaxBars+2; i++ {
+		for j := 0; j <= i; j++ {
+			in = append(in, Complaint{ComplainNo: fmt.Sprintf("%d-%d", i, j), Area: fmt.Sprintf("Area %02d", i)})
+		}
+	}
+	bars := areaChart(groupComplaintsByArea(in))
+	if len(bars) != chartMaxBars {
+		t.Fatalf("chart has %d bars, want %d", len(bars), chartMaxBars)
+	}
+	if bars[0].area != "Area 09" || bars[0].count != 10 {
+		t.Errorf("first bar = %+v, want the busiest area", bars[0])
+	}
+	if last := bars[len(bars)-1]; last.area != "Other areas (3)" || last.count != 3+2+1 {
+		t.Errorf("last bar = %+v, want the three quietest areas folded", last)
+	}
+}
+
+// TestPaginateMarksContinuedAreas checks only the part that finishes an
+// area is left unmarked, so its subtotal row is drawn once.
+func TestPaginateMarksContinuedAreas(t *testing.T) {
+	groups := groupComplaintsByArea([]Complaint{
+		{ComplainNo: "1", Area: "Feeder A"},
+		{ComplainNo: "2", Area: "Feeder A"},
+		{ComplainNo: "3", Area: "Feeder A"},
+	})
+	pages := paginate(groups, 2)
+	if len(pages) != 2 || !pages[0][0].continues || pages[1][0].continues {
+		t.Errorf("continues flags = %v/%v, want only the first part continued", pages[0][0].continues, pages[len(pages)-1][0].continues)
+	}
+}
+
+// TestGroupComplaintsByFeeder checks untagged complaints are looked up in
+// the feeder map and the rest fall under "Unmapped".
+func TestGroupComplaintsByFeeder(t *testing.T) {
+	m, err := feeder.Parse(strings.NewReader("Tokarva = Bajipura AG\nTitva = Bajipura AG"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetFeeders(m)
+	defer SetFeeders(nil)
+
+	groups := groupComplaintsByFeeder([]Complaint{
+		{ComplainNo: "1", Village: "Tokarva"},
+		{ComplainNo: "2", Area: "Titva"},
+		{ComplainNo: "3", Area: "Valod"},
+		{ComplainNo: "4", Feeder: "Valod Town"},
+	})
+	var got []string
+	for _, g := range groups {
+		got = append(got, fmt.Sprintf("%s=%d", g.area, g.total()))
+	}
+	if want := "[Bajipura AG=2 Unmapped=1 Valod Town=1]"; fmt.Sprint(got) != want {
+		t.Errorf("feeder groups = %v, want %s", got, want)
+	}
 }
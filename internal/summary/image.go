@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"cmon/internal/belt"
+	"cmon/internal/notify"
 
 	"github.com/fogleman/gg"
 )
@@ -28,6 +29,7 @@ type Complaint struct {
 	Area              string `json:"area"`
 	Village           string `json:"village"`
 	Belt              string `json:"belt"`
+	Feeder            string `json:"feeder,omitempty"` // see SetFeeders
 	Description       string `json:"description"`
 	ComplainDate      string `json:"complain_date"`
 	TelegramMessageID string `json:"telegram_message_id"`
@@ -37,10 +39,36 @@ type Complaint struct {
 	APIID string `json:"api_id"`
 
 	// AgeMinutes is now() − ComplainDate at the moment the complaint was
-	// fetched. Zero when ComplainDate is empty or unparseable. Surfaces as a
-	// human-readable "3d 4h" cell in the dashboard + summary image so the ops
-	// team can triage by how long a ticket has been pending.
+	// fetched, or now() − FirstSeenAt when ComplainDate is empty or
+	// unparseable; zero when neither is known. Surfaces as a human-readable
+	// "3d 4h" cell in the dashboard + summary image so the ops team can
+	// triage by how long a ticket has been pending.
 	AgeMinutes int64 `json:"age_minutes"`
+
+	// FirstSeenAt is when cmon first saved the complaint; zero if unknown.
+	FirstSeenAt time.Time `json:"first_seen_at,omitempty"`
+
+	// Latitude/Longitude are set when the complaint was geocoded; both zero
+	// otherwise. Used by RenderMap.
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+
+	// LatestNote is the most recent staff note, as "Author: text"; empty
+	// when the complaint has none.
+	LatestNote string `json:"latest_note,omitempty"`
+}
+
+// Redact returns a copy of complaints with mobile and consumer numbers
+// masked to their last four digits, for images posted to wide chats
+// (REDACT_PII).
+func Redact(complaints []Complaint) []Complaint {
+	out := make([]Complaint, len(complaints))
+	for i, c := range complaints {
+		c.MobileNo = notify.MaskNumber(c.MobileNo)
+		c.ConsumerNo = notify.MaskNumber(c.ConsumerNo)
+		out[i] = c
+	}
+	return out
 }
 
 // AgeString renders an AgeMinutes value as a compact human-readable string
@@ -74,13 +102,17 @@ func formatAge(minutes int64) string {
 	}
 }
 
-// computeAgeMinutes returns the age in minutes for a complaint date string.
-// Returns 0 when the date is empty or unparseable so callers can store the
-// raw zero value without special-casing.
-func computeAgeMinutes(complainDate string, now time.Time) int64 {
+// computeAgeMinutes returns the age in minutes for a complaint date string,
+// falling back to firstSeen when the date is empty or unparseable. Returns
+// 0 when neither is known so callers can store the raw zero value without
+// special-casing.
+func computeAgeMinutes(complainDate string, firstSeen, now time.Time) int64 {
 	t, ok := parseComplaintDate(complainDate)
 	if !ok {
-		return 0
+		if firstSeen.IsZero() {
+			return 0
+		}
+		t = firstSeen
 	}
 	delta := now.Sub(t)
 	if delta < 0 {
@@ -95,6 +127,8 @@ type BeltImage struct {
 	Belt       string
 	Label      string
 	Count      int
+	Page       int // 1-based; a belt past the page size spans several images
+	Pages      int
 	PNG        []byte
 	Complaints []Complaint
 }
@@ -125,24 +159,84 @@ const (
 	maxDescWidth  = 440.0 * renderScale
 )
 
-// Light theme colors
+// Age thresholds for row colouring; see SetAgeThresholds. Mutated only at
+// boot and from package tests.
 var (
-	bgColor                = color.RGBA{R: 245, G: 247, B: 250, A: 255} // Light gray bg
-	titleColor             = color.RGBA{R: 30, G: 41, B: 59, A: 255}    // Dark slate
-	headerBgColor          = color.RGBA{R: 37, G: 99, B: 235, A: 255}   // Blue
-	headerTextColor        = color.RGBA{R: 255, G: 255, B: 255, A: 255} // White
-	villageHeaderBgColor   = color.RGBA{R: 226, G: 232, B: 240, A: 255} // Slate-200
-	villageHeaderTextColor = color.RGBA{R: 71, G: 85, B: 105, A: 255}   // Slate-600
-	rowEvenColor           = color.RGBA{R: 255, G: 255, B: 255, A: 255} // White
-	rowOddColor            = color.RGBA{R: 241, G: 245, B: 249, A: 255} // Subtle blue-gray
-	textColor              = color.RGBA{R: 30, G: 41, B: 59, A: 255}    // Dark slate
-	borderColor            = color.RGBA{R: 203, G: 213, B: 225, A: 255} // Slate border
-	footerColor            = color.RGBA{R: 100, G: 116, B: 139, A: 255} // Muted slate
+	agingAfter   = 24 * time.Hour
+	overdueAfter = 72 * time.Hour
 )
 
+// SetAgeThresholds sets the ages past which a row is tinted amber (aging)
+// and red (overdue). Zero turns that tint off.
+func SetAgeThresholds(aging, overdue time.Duration) {
+	agingAfter, overdueAfter = aging, overdue
+}
+
+// location is the zone complaint dates are read in and image timestamps
+// shown in; see SetLocation. Mutated only at boot and from package tests.
+var location = time.Local
+
+// SetLocation sets the zone of portal complaint dates and of the "generated
+// at" stamp on images (TZ_OVERRIDE).
+func SetLocation(loc *time.Location) {
+	location = loc
+}
+
+// maxRowsPerImage is the page size of a rendered table; see
+// SetMaxRowsPerImage. Mutated only at boot and from package tests.
+var maxRowsPerImage = 25
+
+// SetMaxRowsPerImage caps the complaints drawn in one image; longer tables
+// are split into pages. Telegram rejects photos over 10MB and shrinks very
+// tall ones past reading. 0 never splits.
+func SetMaxRowsPerImage(n int) {
+	maxRowsPerImage = n
+}
+
+// rowColor is the background of table row rowIdx for c: red or amber past
+// the age thresholds, else the usual zebra stripe.
+func rowColor(c *Complaint, rowIdx int) color.Color {
+	age := time.Duration(c.AgeMinutes) * time.Minute
+	switch {
+	case overdueAfter > 0 && age >= overdueAfter:
+		return theme.RowOverdue
+	case agingAfter > 0 && age >= agingAfter:
+		return theme.RowAging
+	case rowIdx%2 == 0:
+		return theme.RowEven
+	}
+	return theme.RowOdd
+}
+
+// complaintGroup is one belt's complaints, or one area's (or feeder's)
+// when area is set.
+// On a page it may hold only part of the group; villages still counts the
+// whole group, so headers show the full totals, and continues marks a part
+// whose group goes on over the next page.
 type complaintGroup struct {
 	belt       string
+	area       string
 	complaints []Complaint
+	villages   map[string]int
+	continues  bool
+}
+
+// total is the number of complaints in the whole belt.
+func (g complaintGroup) total() int {
+	n := 0
+	for _, count := range g.villages {
+		n += count
+	}
+	return n
+}
+
+// villageCounts counts complaints per village.
+func villageCounts(complaints []Complaint) map[string]int {
+	counts := make(map[string]int)
+	for _, c := range complaints {
+		counts[getVillage(c)]++
+	}
+	return counts
 }
 
 // column definition for the table.
@@ -163,6 +257,13 @@ var columns = []column{
 	{"Description", func(c *Complaint) string { return c.Description }, maxDescWidth},
 	{"Date", func(c *Complaint) string { return c.ComplainDate }, 0},
 	{"Age", func(c *Complaint) string { return c.AgeString() }, 0},
+	{"Latest Note", func(c *Complaint) string { return c.LatestNote }, maxDescWidth},
+}
+
+// FontFile returns the font file the summary images are drawn in, bold or
+// regular, for other renderers to match.
+func FontFile(bold bool) (string, error) {
+	return findFont(bold)
 }
 
 // findFont locates a font file across Linux and Windows paths.
@@ -284,16 +385,173 @@ func computeRowHeights(dc *gg.Context, complaints []Complaint, colWidths []float
 	return heights
 }
 
-// RenderTable renders all pending complaints as a single combined image,
-// grouped by belt with a colored group-header row separating each belt's
-// complaints.
-func RenderTable(complaints []Complaint) ([]byte, error) {
+// RenderTable renders all pending complaints as combined images, grouped by
+// belt with a colored group-header row separating each belt's complaints,
+// or by area or feeder with subtotals and a bar chart on top (see
+// SetGroupBy).
+// Backlogs longer than the page size (see SetMaxRowsPerImage) are split
+// into several images headed "Page i/n"; every page shares the same column
+// widths.
+func RenderTable(complaints []Complaint) ([][]byte, error) {
 	if len(complaints) == 0 {
 		return nil, fmt.Errorf("no complaints to render")
 	}
 
 	groups := groupComplaints(complaints)
+	var chart []areaCount
+	switch groupBy {
+	case "area":
+		groups = groupComplaintsByArea(complaints)
+		chart = areaChart(groups)
+	case "feeder":
+		groups = groupComplaintsByFeeder(complaints)
+		chart = areaChart(groups)
+	}
+	t, err := newTableLayout(complaints)
+	if err != nil {
+		return nil, err
+	}
+
+	stamp := time.Now().In(location).Format("02 Jan 2006, 03:04 PM")
+	footer := footerText(fmt.Sprintf("Total: %d pending complaints", len(complaints)))
+	pages := paginate(groups, maxRowsPerImage)
+	out := make([][]byte, 0, len(pages))
+	for i, page := range pages {
+		title := fmt.Sprintf("%s  —  %s", theme.TitleText, stamp) + pageSuffix(i, len(pages))
+		var pageChart []areaCount
+		if i == 0 {
+			pageChart = chart
+		}
+		png, err := t.render(title, footer, page, true, pageChart)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, png)
+	}
+	return out, nil
+}
+
+// RenderTablesByBelt groups complaints by belt and renders one image per belt,
+// or several per belt past the page size. Each image has the belt name in
+// the title so callers can send them as independent photos. The returned
+// slice follows the same belt ordering as GroupComplaints (oldest complaint
+// first, then alphabetical tie-break).
+func RenderTablesByBelt(complaints []Complaint) ([]BeltImage, error) {
+	if len(complaints) == 0 {
+		return nil, fmt.Errorf("no complaints to render")
+	}
+
+	groups := groupComplaints(complaints)
+	out := make([]BeltImage, 0, len(groups))
+	for _, g := range groups {
+		style := belt.StyleFor(g.belt)
+		images, err := renderBeltTable(style.Label, g)
+		if err != nil {
+			return nil, fmt.Errorf("render %s belt: %w", style.Label, err)
+		}
+		for i, png := range images {
+			out = append(out, BeltImage{
+				Belt:       g.belt,
+				Label:      style.Label,
+				Count:      len(g.complaints),
+				Page:       i + 1,
+				Pages:      len(images),
+				PNG:        png,
+				Complaints: g.complaints,
+			})
+		}
+	}
+	return out, nil
+}
+
+// RenderBeltTable renders a single belt's complaints as table images, one
+// per page. beltLabel is shown in the title and footer; complaints should
+// already be filtered to that belt and sorted by the caller.
+func RenderBeltTable(beltLabel string, complaints []Complaint) ([][]byte, error) {
+	if len(complaints) == 0 {
+		return nil, fmt.Errorf("no complaints to render for belt %q", beltLabel)
+	}
+	return renderBeltTable(beltLabel, complaintGroup{
+		belt:       beltLabel,
+		complaints: complaints,
+		villages:   villageCounts(complaints),
+	})
+}
 
+func renderBeltTable(beltLabel string, g complaintGroup) ([][]byte, error) {
+	t, err := newTableLayout(g.complaints)
+	if err != nil {
+		return nil, err
+	}
+
+	stamp := time.Now().In(location).Format("02 Jan 2006, 03:04 PM")
+	footer := footerText(fmt.Sprintf("%s Belt — %d pending complaints", beltLabel, len(g.complaints)))
+	pages := paginate([]complaintGroup{g}, maxRowsPerImage)
+	out := make([][]byte, 0, len(pages))
+	for i, page := range pages {
+		title := fmt.Sprintf("Pending Complaints — %s Belt — %s", beltLabel, stamp) + pageSuffix(i, len(pages))
+		png, err := t.render(title, footer, page, false, nil)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, png)
+	}
+	return out, nil
+}
+
+// pageSuffix labels page i (0-based) of n in an image title; nothing when
+// everything fits on one page.
+func pageSuffix(i, n int) string {
+	if n <= 1 {
+		return ""
+	}
+	return fmt.Sprintf("  —  Page %d/%d", i+1, n)
+}
+
+// paginate splits groups into pages of at most maxRows complaints, keeping
+// their order. A group that does not fit continues on the next page, its
+// header repeated. maxRows <= 0 puts everything on one page.
+func paginate(groups []complaintGroup, maxRows int) [][]complaintGroup {
+	if maxRows <= 0 {
+		return [][]complaintGroup{groups}
+	}
+	var pages [][]complaintGroup
+	var page []complaintGroup
+	rows := 0
+	for _, g := range groups {
+		rest := g.complaints
+		for len(rest) > 0 {
+			n := min(maxRows-rows, len(rest))
+			part := g
+			part.complaints = rest[:n]
+			rest = rest[n:]
+			part.continues = len(rest) > 0
+			page = append(page, part)
+			rows += n
+			if rows == maxRows {
+				pages = append(pages, page)
+				page, rows = nil, 0
+			}
+		}
+	}
+	if len(page) > 0 {
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+// tableLayout holds the fonts and column widths shared by every page of
+// one render.
+type tableLayout struct {
+	boldFont    string
+	regularFont string
+	colWidths   []float64
+	totalWidth  float64
+	measure     *gg.Context
+}
+
+// newTableLayout loads the fonts and sizes the columns to fit complaints.
+func newTableLayout(complaints []Complaint) (*tableLayout, error) {
 	boldFont, err := findFont(true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load bold font: %w", err)
@@ -303,7 +561,7 @@ func RenderTable(complaints []Complaint) ([]byte, error) {
 		return nil, fmt.Errorf("failed to load regular font: %w", err)
 	}
 
-	// ---- Step 1: Measure column widths ----
+	// ---- Measure column widths ----
 	tmpDC := gg.NewContext(1, 1)
 	if err := tmpDC.LoadFontFace(boldFont, headerFontSz); err != nil {
 		return nil, fmt.Errorf("failed to load bold font: %w", err)
@@ -322,33 +580,51 @@ func RenderTable(complaints []Complaint) ([]byte, error) {
 	if err := tmpDC.LoadFontFace(regularFont, fontSize); err != nil {
 		return nil, fmt.Errorf("failed to load regular font: %w", err)
 	}
-	for _, group := range groups {
-		for _, c := range group.complaints {
-			c := c
-			for i, col := range columns {
-				w, _ := tmpDC.MeasureString(col.field(&c))
-				needed := w + cellPaddingX*2 + 4*renderScale
-				if needed > colWidths[i] {
-					colWidths[i] = needed
-				}
+	for _, c := range complaints {
+		c := c
+		for i, col := range columns {
+			w, _ := tmpDC.MeasureString(col.field(&c))
+			needed := w + cellPaddingX*2 + 4*renderScale
+			if needed > colWidths[i] {
+				colWidths[i] = needed
 			}
 		}
 	}
 
 	// Apply max width caps
+	var totalWidth float64
 	for i, col := range columns {
 		if col.maxWidth > 0 && colWidths[i] > col.maxWidth {
 			colWidths[i] = col.maxWidth
 		}
+		totalWidth += colWidths[i]
 	}
 
+	return &tableLayout{
+		boldFont:    boldFont,
+		regularFont: regularFont,
+		colWidths:   colWidths,
+		totalWidth:  totalWidth,
+		measure:     tmpDC,
+	}, nil
+}
+
+// render draws one page: the title, an optional per-area bar chart, the
+// column header row, then each group's complaints under their village
+// headers. groupHeaders adds the belt (or area) row above each group, as in
+// the combined table; area groups also close with a subtotal row.
+func (t *tableLayout) render(title, footer string, page []complaintGroup, groupHeaders bool, chart []areaCount) ([]byte, error) {
+	colWidths, totalWidth := t.colWidths, t.totalWidth
+
 	// Compute row heights (for text wrapping)
-	rowHeightsByGroup := make([][]float64, len(groups))
+	rowHeightsByGroup := make([][]float64, len(page))
 	var totalRowHeight float64
-	for i, group := range groups {
-		rowHeightsByGroup[i] = computeRowHeights(tmpDC, group.complaints, colWidths)
-		totalRowHeight += float64(groupHeaderH)
-		
+	for i, group := range page {
+		rowHeightsByGroup[i] = computeRowHeights(t.measure, group.complaints, colWidths)
+		if groupHeaders {
+			totalRowHeight += float64(groupHeaderH)
+		}
+
 		var lastVillage string
 		for j, h := range rowHeightsByGroup[i] {
 			v := getVillage(group.complaints[j])
@@ -358,44 +634,51 @@ func RenderTable(complaints []Complaint) ([]byte, error) {
 			}
 			totalRowHeight += h
 		}
+		if group.area != "" && !group.continues {
+			totalRowHeight += float64(subtotalH)
+		}
 	}
 
-	// ---- Step 2: Calculate canvas size ----
-	var totalWidth float64
-	for _, w := range colWidths {
-		totalWidth += w
-	}
-
+	// ---- Calculate canvas size ----
+	chartH := chartHeight(len(chart))
 	canvasWidth := totalWidth + float64(40*renderScale*2) // 40px logical margin each side
+	t.measure.LoadFontFace(t.boldFont, titleFontSz)
+	if titleW, _ := t.measure.MeasureString(title); titleW+float64(40*renderScale*2) > canvasWidth {
+		canvasWidth = titleW + float64(40*renderScale*2) // keep a long title on the canvas
+	}
+	t.measure.LoadFontFace(t.regularFont, fontSize)
 	canvasHeight := float64(titlePadding) +
+		chartH +
 		float64(headerHeight) +
 		totalRowHeight +
 		float64(footerPadding)
 
-	// ---- Step 3: Draw ----
+	// ---- Draw ----
 	dc := gg.NewContext(int(canvasWidth), int(canvasHeight))
 
 	// Background
-	dc.SetColor(bgColor)
+	dc.SetColor(theme.Background)
 	dc.Clear()
 
 	// Title
-	dc.LoadFontFace(boldFont, titleFontSz)
-	dc.SetColor(titleColor)
-	title := fmt.Sprintf("Pending Complaints Summary Valod SDn  —  %s", time.Now().Format("02 Jan 2006, 03:04 PM"))
+	dc.LoadFontFace(t.boldFont, titleFontSz)
+	dc.SetColor(theme.Title)
 	dc.DrawStringAnchored(title, canvasWidth/2, float64(titlePadding)/2+float64(2*renderScale), 0.5, 0.5)
 
-	tableX := float64(40 * renderScale)
-	tableY := float64(titlePadding)
+	tableX := (canvasWidth - totalWidth) / 2
+	if len(chart) > 0 {
+		drawAreaChart(dc, t.boldFont, t.regularFont, tableX, float64(titlePadding), totalWidth, chart)
+	}
+	tableY := float64(titlePadding) + chartH
 
 	// Header row background (rounded top corners)
-	dc.SetColor(headerBgColor)
+	dc.SetColor(theme.HeaderBg)
 	dc.DrawRoundedRectangle(tableX, tableY, totalWidth, float64(headerHeight), float64(16*renderScale))
 	dc.Fill()
 
 	// Header text
-	dc.LoadFontFace(boldFont, headerFontSz)
-	dc.SetColor(headerTextColor)
+	dc.LoadFontFace(t.boldFont, headerFontSz)
+	dc.SetColor(theme.HeaderText)
 	x := tableX
 	for i, col := range columns {
 		tx := x + colWidths[i]/2
@@ -405,19 +688,20 @@ func RenderTable(complaints []Complaint) ([]byte, error) {
 	}
 
 	// Data rows
-	dc.LoadFontFace(regularFont, fontSize)
+	dc.LoadFontFace(t.regularFont, fontSize)
 	_, lineH := dc.MeasureString("Ay")
 	lineSpacing := lineH + float64(4*renderScale)
 	curY := tableY + float64(headerHeight)
 
 	rowIdx := 0
-	for groupIdx, group := range groups {
-		drawGroupHeader(dc, boldFont, tableX, curY, totalWidth, group.belt, len(group.complaints))
-		curY += float64(groupHeaderH)
-
-		vCounts := make(map[string]int)
-		for _, c := range group.complaints {
-			vCounts[getVillage(c)]++
+	for groupIdx, group := range page {
+		if groupHeaders {
+			if group.area != "" {
+				drawAreaHeader(dc, t.boldFont, tableX, curY, totalWidth, group.area, group.total())
+			} else {
+				drawGroupHeader(dc, t.boldFont, tableX, curY, totalWidth, group.belt, group.total())
+			}
+			curY += float64(groupHeaderH)
 		}
 
 		var lastVillage string
@@ -425,28 +709,24 @@ func RenderTable(complaints []Complaint) ([]byte, error) {
 			c := c
 			v := getVillage(c)
 			if complaintIdx == 0 || v != lastVillage {
-				drawVillageHeader(dc, boldFont, tableX, curY, totalWidth, v, vCounts[v])
+				drawVillageHeader(dc, t.boldFont, tableX, curY, totalWidth, v, group.villages[v])
 				curY += float64(villageHeaderH)
 				lastVillage = v
 			}
 
 			rh := rowHeightsByGroup[groupIdx][complaintIdx]
 
-			if rowIdx%2 == 0 {
-				dc.SetColor(rowEvenColor)
-			} else {
-				dc.SetColor(rowOddColor)
-			}
+			dc.SetColor(rowColor(&c, rowIdx))
 			dc.DrawRectangle(tableX, curY, totalWidth, rh)
 			dc.Fill()
 
-			dc.SetColor(borderColor)
+			dc.SetColor(theme.Border)
 			dc.SetLineWidth(0.5 * renderScale)
 			dc.DrawLine(tableX, curY+rh, tableX+totalWidth, curY+rh)
 			dc.Stroke()
 
-			dc.LoadFontFace(regularFont, fontSize)
-			dc.SetColor(textColor)
+			dc.LoadFontFace(t.regularFont, fontSize)
+			dc.SetColor(theme.Text)
 			x := tableX
 			for i, col := range columns {
 				text := col.field(&c)
@@ -466,10 +746,15 @@ func RenderTable(complaints []Complaint) ([]byte, error) {
 			curY += rh
 			rowIdx++
 		}
+
+		if group.area != "" && !group.continues {
+			drawSubtotal(dc, t.boldFont, tableX, curY, totalWidth, group.area, group.total())
+			curY += float64(subtotalH)
+		}
 	}
 
 	// Outer table border
-	dc.SetColor(borderColor)
+	dc.SetColor(theme.Border)
 	dc.SetLineWidth(1 * renderScale)
 	totalTableH := float64(headerHeight) + totalRowHeight
 	dc.DrawRoundedRectangle(tableX, tableY, totalWidth, totalTableH, float64(16*renderScale))
@@ -485,220 +770,11 @@ func RenderTable(complaints []Complaint) ([]byte, error) {
 	}
 
 	// Footer
-	dc.LoadFontFace(regularFont, 24*renderScale)
-	dc.SetColor(footerColor)
-	footer := fmt.Sprintf("Total: %d pending complaints", len(complaints))
-	dc.DrawStringAnchored(footer, canvasWidth/2, canvasHeight-float64(30*renderScale), 0.5, 0.5)
-
-	// ---- Step 4: Encode to PNG ----
-	return encodeImage(dc.Image())
-}
-
-// RenderTablesByBelt groups complaints by belt and renders one image per belt.
-// Each image has the belt name in the title so callers can send them as
-// independent photos. The returned slice follows the same belt ordering as
-// GroupComplaints (oldest complaint first, then alphabetical tie-break).
-func RenderTablesByBelt(complaints []Complaint) ([]BeltImage, error) {
-	if len(complaints) == 0 {
-		return nil, fmt.Errorf("no complaints to render")
-	}
-
-	groups := groupComplaints(complaints)
-	out := make([]BeltImage, 0, len(groups))
-	for _, g := range groups {
-		style := belt.StyleFor(g.belt)
-		png, err := RenderBeltTable(style.Label, g.complaints)
-		if err != nil {
-			return nil, fmt.Errorf("render %s belt: %w", style.Label, err)
-		}
-		out = append(out, BeltImage{
-			Belt:       g.belt,
-			Label:      style.Label,
-			Count:      len(g.complaints),
-			PNG:        png,
-			Complaints: g.complaints,
-		})
-	}
-	return out, nil
-}
-
-// RenderBeltTable renders a single belt's complaints as a table image.
-// beltLabel is shown in the title and footer; complaints should already be
-// filtered to that belt and sorted by the caller.
-func RenderBeltTable(beltLabel string, complaints []Complaint) ([]byte, error) {
-	if len(complaints) == 0 {
-		return nil, fmt.Errorf("no complaints to render for belt %q", beltLabel)
-	}
-
-	boldFont, err := findFont(true)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load bold font: %w", err)
-	}
-	regularFont, err := findFont(false)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load regular font: %w", err)
-	}
-
-	tmpDC := gg.NewContext(1, 1)
-	if err := tmpDC.LoadFontFace(boldFont, headerFontSz); err != nil {
-		return nil, fmt.Errorf("failed to load bold font: %w", err)
-	}
-
-	colWidths := make([]float64, len(columns))
-	for i, col := range columns {
-		w, _ := tmpDC.MeasureString(col.header)
-		colWidths[i] = w + cellPaddingX*2 + 4*renderScale
-		if colWidths[i] < float64(minColWidth) {
-			colWidths[i] = float64(minColWidth)
-		}
-	}
-
-	if err := tmpDC.LoadFontFace(regularFont, fontSize); err != nil {
-		return nil, fmt.Errorf("failed to load regular font: %w", err)
-	}
-	for _, c := range complaints {
-		c := c
-		for i, col := range columns {
-			w, _ := tmpDC.MeasureString(col.field(&c))
-			needed := w + cellPaddingX*2 + 4*renderScale
-			if needed > colWidths[i] {
-				colWidths[i] = needed
-			}
-		}
-	}
-
-	for i, col := range columns {
-		if col.maxWidth > 0 && colWidths[i] > col.maxWidth {
-			colWidths[i] = col.maxWidth
-		}
-	}
-
-	rowHeights := computeRowHeights(tmpDC, complaints, colWidths)
-	var totalRowHeight float64
-	var lastVillage string
-	for j, h := range rowHeights {
-		v := getVillage(complaints[j])
-		if j == 0 || v != lastVillage {
-			totalRowHeight += float64(villageHeaderH)
-			lastVillage = v
-		}
-		totalRowHeight += h
-	}
-
-	var totalWidth float64
-	for _, w := range colWidths {
-		totalWidth += w
-	}
-
-	canvasWidth := totalWidth + float64(40*renderScale*2)
-	canvasHeight := float64(titlePadding) +
-		float64(headerHeight) +
-		totalRowHeight +
-		float64(footerPadding)
-
-	dc := gg.NewContext(int(canvasWidth), int(canvasHeight))
-
-	dc.SetColor(bgColor)
-	dc.Clear()
-
-	dc.LoadFontFace(boldFont, titleFontSz)
-	dc.SetColor(titleColor)
-	title := fmt.Sprintf("Pending Complaints — %s Belt — %s",
-		beltLabel, time.Now().Format("02 Jan 2006, 03:04 PM"))
-	dc.DrawStringAnchored(title, canvasWidth/2, float64(titlePadding)/2+float64(2*renderScale), 0.5, 0.5)
-
-	tableX := float64(40 * renderScale)
-	tableY := float64(titlePadding)
-
-	dc.SetColor(headerBgColor)
-	dc.DrawRoundedRectangle(tableX, tableY, totalWidth, float64(headerHeight), float64(16*renderScale))
-	dc.Fill()
-
-	dc.LoadFontFace(boldFont, headerFontSz)
-	dc.SetColor(headerTextColor)
-	x := tableX
-	for i, col := range columns {
-		tx := x + colWidths[i]/2
-		ty := tableY + float64(headerHeight)/2
-		dc.DrawStringAnchored(col.header, tx, ty, 0.5, 0.5)
-		x += colWidths[i]
-	}
-
-	dc.LoadFontFace(regularFont, fontSize)
-	_, lineH := dc.MeasureString("Ay")
-	lineSpacing := lineH + float64(4*renderScale)
-	curY := tableY + float64(headerHeight)
-
-	vCounts := make(map[string]int)
-	for _, c := range complaints {
-		vCounts[getVillage(c)]++
-	}
-
-	lastVillage = ""
-	for rowIdx, c := range complaints {
-		c := c
-		v := getVillage(c)
-		if rowIdx == 0 || v != lastVillage {
-			drawVillageHeader(dc, boldFont, tableX, curY, totalWidth, v, vCounts[v])
-			curY += float64(villageHeaderH)
-			lastVillage = v
-		}
-
-		rh := rowHeights[rowIdx]
-
-		if rowIdx%2 == 0 {
-			dc.SetColor(rowEvenColor)
-		} else {
-			dc.SetColor(rowOddColor)
-		}
-		dc.DrawRectangle(tableX, curY, totalWidth, rh)
-		dc.Fill()
-
-		dc.SetColor(borderColor)
-		dc.SetLineWidth(0.5 * renderScale)
-		dc.DrawLine(tableX, curY+rh, tableX+totalWidth, curY+rh)
-		dc.Stroke()
-
-		dc.LoadFontFace(regularFont, fontSize)
-		dc.SetColor(textColor)
-		x := tableX
-		for i, col := range columns {
-			text := col.field(&c)
-			innerWidth := colWidths[i] - cellPaddingX*2
-			wrapped := wrapText(dc, text, innerWidth)
-
-			totalTextH := float64(len(wrapped)) * lineSpacing
-			startY := curY + (rh-totalTextH)/2 + lineH
-
-			for lineIdx, line := range wrapped {
-				ly := startY + float64(lineIdx)*lineSpacing
-				dc.DrawString(line, x+cellPaddingX, ly)
-			}
-			x += colWidths[i]
-		}
-
-		curY += rh
-	}
-
-	dc.SetColor(borderColor)
-	dc.SetLineWidth(1 * renderScale)
-	totalTableH := float64(headerHeight) + totalRowHeight
-	dc.DrawRoundedRectangle(tableX, tableY, totalWidth, totalTableH, float64(16*renderScale))
-	dc.Stroke()
-
-	dc.SetLineWidth(0.5 * renderScale)
-	x = tableX
-	for i := 0; i < len(columns)-1; i++ {
-		x += colWidths[i]
-		dc.DrawLine(x, tableY+float64(headerHeight), x, tableY+totalTableH)
-		dc.Stroke()
-	}
-
-	dc.LoadFontFace(regularFont, 24*renderScale)
-	dc.SetColor(footerColor)
-	footer := fmt.Sprintf("%s Belt — %d pending complaints", beltLabel, len(complaints))
+	dc.LoadFontFace(t.regularFont, 24*renderScale)
+	dc.SetColor(theme.Footer)
 	dc.DrawStringAnchored(footer, canvasWidth/2, canvasHeight-float64(30*renderScale), 0.5, 0.5)
 
+	// ---- Encode to PNG ----
 	return encodeImage(dc.Image())
 }
 
@@ -723,23 +799,9 @@ func groupComplaints(complaints []Complaint) []complaintGroup {
 
 	groups := make([]complaintGroup, 0, len(grouped))
 	for belt, items := range grouped {
-		vCounts := make(map[string]int)
-		for _, c := range items {
-			vCounts[getVillage(c)]++
-		}
-
-		sort.Slice(items, func(i, j int) bool {
-			vi := getVillage(items[i])
-			vj := getVillage(items[j])
-			if vi != vj {
-				if vCounts[vi] != vCounts[vj] {
-					return vCounts[vi] > vCounts[vj]
-				}
-				return vi < vj
-			}
-			return complaintDateLess(items[i], items[j])
-		})
-		groups = append(groups, complaintGroup{belt: belt, complaints: items})
+		vCounts := villageCounts(items)
+		sortGroupRows(items, vCounts)
+		groups = append(groups, complaintGroup{belt: belt, complaints: items, villages: vCounts})
 	}
 
 	sort.Slice(groups, func(i, j int) bool {
@@ -760,6 +822,22 @@ func groupComplaints(complaints []Complaint) []complaintGroup {
 	return groups
 }
 
+// sortGroupRows orders one group's complaints village by village, the
+// village with most complaints first, and oldest first within a village.
+func sortGroupRows(items []Complaint, vCounts map[string]int) {
+	sort.Slice(items, func(i, j int) bool {
+		vi := getVillage(items[i])
+		vj := getVillage(items[j])
+		if vi != vj {
+			if vCounts[vi] != vCounts[vj] {
+				return vCounts[vi] > vCounts[vj]
+			}
+			return vi < vj
+		}
+		return complaintDateLess(items[i], items[j])
+	})
+}
+
 func complaintDateLess(a, b Complaint) bool {
 	at, aok := parseComplaintDate(a.ComplainDate)
 	bt, bok := parseComplaintDate(b.ComplainDate)
@@ -796,7 +874,7 @@ func parseComplaintDate(value string) (time.Time, bool) {
 		"02/01/2006",
 	}
 	for _, layout := range layouts {
-		if ts, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+		if ts, err := time.ParseInLocation(layout, value, location); err == nil {
 			return ts, true
 		}
 	}
@@ -809,7 +887,7 @@ func drawGroupHeader(dc *gg.Context, boldFont string, x, y, width float64, beltN
 	dc.DrawRectangle(x, y, width, float64(groupHeaderH))
 	dc.Fill()
 
-	dc.SetColor(borderColor)
+	dc.SetColor(theme.Border)
 	dc.SetLineWidth(0.5 * renderScale)
 	dc.DrawLine(x, y+float64(groupHeaderH), x+width, y+float64(groupHeaderH))
 	dc.Stroke()
@@ -828,17 +906,17 @@ func drawGroupHeader(dc *gg.Context, boldFont string, x, y, width float64, beltN
 }
 
 func drawVillageHeader(dc *gg.Context, font string, x, y, width float64, village string, count int) {
-	dc.SetColor(villageHeaderBgColor)
+	dc.SetColor(theme.VillageHeaderBg)
 	dc.DrawRectangle(x, y, width, float64(villageHeaderH))
 	dc.Fill()
 
-	dc.SetColor(borderColor)
+	dc.SetColor(theme.Border)
 	dc.SetLineWidth(0.5 * renderScale)
 	dc.DrawLine(x, y+float64(villageHeaderH), x+width, y+float64(villageHeaderH))
 	dc.Stroke()
 
 	dc.LoadFontFace(font, fontSize)
-	dc.SetColor(villageHeaderTextColor)
+	dc.SetColor(theme.VillageHeaderText)
 	text := fmt.Sprintf("%s (%d)", village, count)
 	dc.DrawStringAnchored(text, x+float64(cellPaddingX), y+float64(villageHeaderH)/2, 0, 0.5)
 }
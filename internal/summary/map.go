@@ -0,0 +1,162 @@
+package summary
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"cmon/internal/belt"
+
+	"github.com/fogleman/gg"
+)
+
+// Map layout (before renderScale).
+const (
+	mapWidth      = 1200
+	mapHeight     = 900
+	mapMargin     = 70
+	mapLegendW    = 260
+	mapDotRadius  = 9
+	mapMinSpanDeg = 0.05 // ~5 km; keeps a single cluster from filling the canvas
+)
+
+// RenderMap plots geocoded pending complaints as belt-coloured dots on a
+// lat/lon grid. There is no base-map tile layer — the point is to see where
+// complaints bunch up relative to each other, and pulling tiles would add a
+// network dependency (and a tile-usage policy) to the summary path.
+//
+// Complaints without coordinates are skipped and counted in the footer.
+// Returns an error when no complaint has coordinates.
+func RenderMap(complaints []Complaint) ([]byte, error) {
+	var located []Complaint
+	for _, c := range complaints {
+		if c.Latitude != 0 || c.Longitude != 0 {
+			located = append(located, c)
+		}
+	}
+	if len(located) == 0 {
+		return nil, fmt.Errorf("no geocoded complaints to plot")
+	}
+
+	boldFont, err := findFont(true)
+	if err != nil {
+		return nil, err
+	}
+	regularFont, err := findFont(false)
+	if err != nil {
+		return nil, err
+	}
+
+	minLat, maxLat, minLon, maxLon := mapBounds(located)
+
+	s := float64(renderScale)
+	w, h := mapWidth*s, mapHeight*s
+	margin := mapMargin * s
+	plotW := w - 2*margin - mapLegendW*s
+	plotH := h - 2*margin
+
+	// Keep degrees square-ish: a degree of longitude is shorter than one of
+	// latitude by cos(lat), so scale both axes by the same pixels-per-km.
+	cosLat := math.Cos((minLat + maxLat) / 2 * math.Pi / 180)
+	spanX := (maxLon - minLon) * cosLat
+	spanY := maxLat - minLat
+	scale := math.Min(plotW/spanX, plotH/spanY)
+	offX := margin + (plotW-spanX*scale)/2
+	offY := margin + (plotH-spanY*scale)/2
+
+	project := func(lat, lon float64) (float64, float64) {
+		return offX + (lon-minLon)*cosLat*scale, offY + (maxLat-lat)*scale
+	}
+
+	dc := gg.NewContext(int(w), int(h))
+	dc.SetColor(theme.Background)
+	dc.Clear()
+
+	// Plot frame + light grid
+	dc.SetColor(theme.Border)
+	dc.SetLineWidth(1 * s)
+	for i := 0; i <= 4; i++ {
+		x := margin + plotW*float64(i)/4
+		y := margin + plotH*float64(i)/4
+		dc.DrawLine(x, margin, x, margin+plotH)
+		dc.DrawLine(margin, y, margin+plotW, y)
+	}
+	dc.Stroke()
+
+	if err := dc.LoadFontFace(boldFont, 26*s); err != nil {
+		return nil, err
+	}
+	dc.SetColor(theme.Title)
+	dc.DrawStringAnchored(fmt.Sprintf("Pending complaint locations (%d)", len(located)), w/2, margin/2, 0.5, 0.5)
+
+	// Dots, drawn per belt so the legend order matches
+	counts := make(map[string]int)
+	var belts []string
+	for _, c := range located {
+		key := belt.DisplayName(c.Belt)
+		if counts[key] == 0 {
+			belts = append(belts, key)
+		}
+		counts[key]++
+	}
+	sort.Strings(belts)
+
+	for _, c := range located {
+		x, y := project(c.Latitude, c.Longitude)
+		style := belt.StyleFor(c.Belt)
+		dc.DrawCircle(x, y, mapDotRadius*s)
+		dc.SetColor(style.Text)
+		dc.FillPreserve()
+		dc.SetColor(theme.Background)
+		dc.SetLineWidth(2 * s)
+		dc.Stroke()
+	}
+
+	// Legend
+	if err := dc.LoadFontFace(regularFont, 18*s); err != nil {
+		return nil, err
+	}
+	lx := w - margin - mapLegendW*s + 30*s
+	ly := margin + 20*s
+	for _, name := range belts {
+		dc.DrawCircle(lx, ly, mapDotRadius*s)
+		dc.SetColor(belt.StyleFor(name).Text)
+		dc.Fill()
+		dc.SetColor(theme.Text)
+		dc.DrawStringAnchored(fmt.Sprintf("%s (%d)", name, counts[name]), lx+20*s, ly, 0, 0.35)
+		ly += 34 * s
+	}
+
+	// Footer
+	footer := fmt.Sprintf("Lat %.3f–%.3f, Lon %.3f–%.3f", minLat, maxLat, minLon, maxLon)
+	if missing := len(complaints) - len(located); missing > 0 {
+		footer += fmt.Sprintf(" · %d complaint(s) without coordinates not shown", missing)
+	}
+	dc.SetColor(theme.Footer)
+	dc.DrawStringAnchored(footerText(footer), w/2, h-margin/2, 0.5, 0.5)
+
+	return encodeImage(dc.Image())
+}
+
+// mapBounds returns the bounding box of located complaints, padded so dots
+// at the edge aren't clipped and widened to mapMinSpanDeg on each axis.
+func mapBounds(located []Complaint) (minLat, maxLat, minLon, maxLon float64) {
+	minLat, maxLat = located[0].Latitude, located[0].Latitude
+	minLon, maxLon = located[0].Longitude, located[0].Longitude
+	for _, c := range located[1:] {
+		minLat = math.Min(minLat, c.Latitude)
+		maxLat = math.Max(maxLat, c.Latitude)
+		minLon = math.Min(minLon, c.Longitude)
+		maxLon = math.Max(maxLon, c.Longitude)
+	}
+	widen := func(lo, hi float64) (float64, float64) {
+		pad := (hi - lo) * 0.08
+		if hi-lo+2*pad < mapMinSpanDeg {
+			pad = (mapMinSpanDeg - (hi - lo)) / 2
+		}
+		return lo - pad, hi + pad
+	}
+	minLat, maxLat = widen(minLat, maxLat)
+	minLon, maxLon = widen(minLon, maxLon)
+	return
+}
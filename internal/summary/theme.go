@@ -0,0 +1,190 @@
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"os"
+	"strings"
+)
+
+// Theme is the palette and wording of the rendered summary images. Belt
+// group headers keep their belt colours in every theme.
+type Theme struct {
+	Background        color.RGBA
+	Title             color.RGBA
+	HeaderBg          color.RGBA
+	HeaderText        color.RGBA
+	VillageHeaderBg   color.RGBA
+	VillageHeaderText color.RGBA
+	RowEven           color.RGBA
+	RowOdd            color.RGBA
+	Text              color.RGBA
+	Border            color.RGBA
+	Footer            color.RGBA
+	RowAging          color.RGBA
+	RowOverdue        color.RGBA
+
+	// TitleText heads the all-belts table, before the timestamp.
+	TitleText string
+	// Branding is appended to every image footer when set.
+	Branding string
+}
+
+// LightTheme is the default: dark text on a light gray background.
+var LightTheme = Theme{
+	Background:        color.RGBA{R: 245, G: 247, B: 250, A: 255}, // Light gray bg
+	Title:             color.RGBA{R: 30, G: 41, B: 59, A: 255},    // Dark slate
+	HeaderBg:          color.RGBA{R: 37, G: 99, B: 235, A: 255},   // Blue
+	HeaderText:        color.RGBA{R: 255, G: 255, B: 255, A: 255}, // White
+	VillageHeaderBg:   color.RGBA{R: 226, G: 232, B: 240, A: 255}, // Slate-200
+	VillageHeaderText: color.RGBA{R: 71, G: 85, B: 105, A: 255},   // Slate-600
+	RowEven:           color.RGBA{R: 255, G: 255, B: 255, A: 255}, // White
+	RowOdd:            color.RGBA{R: 241, G: 245, B: 249, A: 255}, // Subtle blue-gray
+	Text:              color.RGBA{R: 30, G: 41, B: 59, A: 255},    // Dark slate
+	Border:            color.RGBA{R: 203, G: 213, B: 225, A: 255}, // Slate border
+	Footer:            color.RGBA{R: 100, G: 116, B: 139, A: 255}, // Muted slate
+	RowAging:          color.RGBA{R: 254, G: 243, B: 199, A: 255}, // Amber-100
+	RowOverdue:        color.RGBA{R: 254, G: 202, B: 202, A: 255}, // Red-200
+	TitleText:         "Pending Complaints Summary Valod SDn",
+}
+
+// DarkTheme suits Telegram's night mode: light text on slate.
+var DarkTheme = Theme{
+	Background:        color.RGBA{R: 15, G: 23, B: 42, A: 255},    // Slate-900
+	Title:             color.RGBA{R: 226, G: 232, B: 240, A: 255}, // Slate-200
+	HeaderBg:          color.RGBA{R: 30, G: 64, B: 175, A: 255},   // Blue-800
+	HeaderText:        color.RGBA{R: 241, G: 245, B: 249, A: 255}, // Slate-100
+	VillageHeaderBg:   color.RGBA{R: 51, G: 65, B: 85, A: 255},    // Slate-700
+	VillageHeaderText: color.RGBA{R: 203, G: 213, B: 225, A: 255}, // Slate-300
+	RowEven:           color.RGBA{R: 30, G: 41, B: 59, A: 255},    // Slate-800
+	RowOdd:            color.RGBA{R: 39, G: 52, B: 72, A: 255},    // Between slate-800 and 700
+	Text:              color.RGBA{R: 226, G: 232, B: 240, A: 255}, // Slate-200
+	Border:            color.RGBA{R: 71, G: 85, B: 105, A: 255},   // Slate-600
+	Footer:            color.RGBA{R: 148, G: 163, B: 184, A: 255}, // Slate-400
+	RowAging:          color.RGBA{R: 113, G: 63, B: 18, A: 255},   // Amber-900
+	RowOverdue:        color.RGBA{R: 127, G: 29, B: 29, A: 255},   // Red-900
+	TitleText:         LightTheme.TitleText,
+}
+
+// theme is the palette every render uses; see SetTheme. Mutated only at
+// boot and from package tests.
+var theme = LightTheme
+
+// SetTheme makes t the theme of every later summary image.
+func SetTheme(t Theme) {
+	theme = t
+}
+
+// CurrentTheme returns the theme set by SetTheme, so other renderers (see
+// package charts) can match the summary images.
+func CurrentTheme() Theme {
+	return theme
+}
+
+// themeFile is the JSON form of a theme file: "base" names the built-in
+// theme to start from ("light" by default) and every other key overrides
+// one colour ("#rrggbb") or text of it.
+type themeFile struct {
+	Base              string `json:"base"`
+	Background        string `json:"background"`
+	Title             string `json:"title"`
+	HeaderBg          string `json:"header_bg"`
+	HeaderText        string `json:"header_text"`
+	VillageHeaderBg   string `json:"village_header_bg"`
+	VillageHeaderText string `json:"village_header_text"`
+	RowEven           string `json:"row_even"`
+	RowOdd            string `json:"row_odd"`
+	Text              string `json:"text"`
+	Border            string `json:"border"`
+	Footer            string `json:"footer"`
+	RowAging          string `json:"row_aging"`
+	RowOverdue        string `json:"row_overdue"`
+	TitleText         string `json:"title_text"`
+	Branding          string `json:"branding"`
+}
+
+// LoadTheme returns the theme named by spec: "light" (or empty), "dark",
+// or the path of a JSON theme file.
+func LoadTheme(spec string) (Theme, error) {
+	switch strings.ToLower(strings.TrimSpace(spec)) {
+	case "", "light":
+		return LightTheme, nil
+	case "dark":
+		return DarkTheme, nil
+	}
+
+	data, err := os.ReadFile(spec)
+	if err != nil {
+		return Theme{}, fmt.Errorf("failed to read theme file: %w", err)
+	}
+	var f themeFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return Theme{}, fmt.Errorf("failed to parse theme file %s: %w", spec, err)
+	}
+	var t Theme
+	switch strings.ToLower(strings.TrimSpace(f.Base)) {
+	case "", "light":
+		t = LightTheme
+	case "dark":
+		t = DarkTheme
+	default:
+		return Theme{}, fmt.Errorf("theme file %s: base must be light or dark, got %q", spec, f.Base)
+	}
+
+	for _, o := range []struct {
+		hex string
+		dst *color.RGBA
+	}{
+		{f.Background, &t.Background},
+		{f.Title, &t.Title},
+		{f.HeaderBg, &t.HeaderBg},
+		{f.HeaderText, &t.HeaderText},
+		{f.VillageHeaderBg, &t.VillageHeaderBg},
+		{f.VillageHeaderText, &t.VillageHeaderText},
+		{f.RowEven, &t.RowEven},
+		{f.RowOdd, &t.RowOdd},
+		{f.Text, &t.Text},
+		{f.Border, &t.Border},
+		{f.Footer, &t.Footer},
+		{f.RowAging, &t.RowAging},
+		{f.RowOverdue, &t.RowOverdue},
+	} {
+		if o.hex == "" {
+			continue
+		}
+		c, err := parseHexColor(o.hex)
+		if err != nil {
+			return Theme{}, fmt.Errorf("theme file %s: %w", spec, err)
+		}
+		*o.dst = c
+	}
+	if f.TitleText != "" {
+		t.TitleText = f.TitleText
+	}
+	if f.Branding != "" {
+		t.Branding = f.Branding
+	}
+	return t, nil
+}
+
+// parseHexColor parses "#rrggbb" (the "#" is optional).
+func parseHexColor(s string) (color.RGBA, error) {
+	h := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	var r, g, b uint8
+	if len(h) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid colour %q (want #rrggbb)", s)
+	}
+	if _, err := fmt.Sscanf(h, "%02x%02x%02x", &r, &g, &b); err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid colour %q (want #rrggbb)", s)
+	}
+	return color.RGBA{R: r, G: g, B: b, A: 255}, nil
+}
+
+// footerText appends the theme's branding, if any, to an image footer.
+func footerText(footer string) string {
+	if theme.Branding == "" {
+		return footer
+	}
+	return footer + "  ·  " + theme.Branding
+}
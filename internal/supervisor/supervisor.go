@@ -0,0 +1,141 @@
+// Package supervisor owns cmon's long-running goroutines — the Telegram
+// and WhatsApp handlers, the session keep-alive, the schedulers. Each runs
+// as a task watched through its done channel: a task that panics is
+// reported (see package crash) and started again after a backoff, and the
+// state of every task is available for /health.
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"cmon/internal/crash"
+)
+
+// Task states reported by Status.
+const (
+	StateRunning    = "running"
+	StateRestarting = "restarting" // crashed; waiting out the backoff
+	StateStopped    = "stopped"    // returned, or its context ended
+)
+
+// TaskStatus is the liveness of one supervised task.
+type TaskStatus struct {
+	Name      string    `json:"name"`
+	State     string    `json:"state"`
+	Restarts  int       `json:"restarts"`
+	LastCrash string    `json:"last_crash,omitempty"`
+	CrashedAt time.Time `json:"crashed_at,omitzero"`
+}
+
+// Supervisor starts and restarts tasks. Create one with New.
+type Supervisor struct {
+	// MinBackoff is the delay before the first restart; it doubles with
+	// each further crash up to MaxBackoff. A task that stays up for
+	// MaxBackoff starts again from MinBackoff next time it crashes.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	wg    *sync.WaitGroup
+	mu    sync.Mutex
+	tasks []*TaskStatus
+}
+
+// New returns a Supervisor that adds each task to wg, so shutdown can
+// wait for all of them.
+func New(wg *sync.WaitGroup) *Supervisor {
+	return &Supervisor{MinBackoff: time.Second, MaxBackoff: time.Minute, wg: wg}
+}
+
+// Go runs fn as the task name until fn returns or ctx is done, restarting
+// it whenever it panics.
+func (s *Supervisor) Go(ctx context.Context, name string, fn func(ctx context.Context)) {
+	st := &TaskStatus{Name: name, State: StateRunning}
+	s.mu.Lock()
+	s.tasks = append(s.tasks, st)
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.supervise(ctx, st, fn)
+	}()
+}
+
+// supervise is the task's lifetime: run, wait on done, and either stop or
+// back off and run again.
+func (s *Supervisor) supervise(ctx context.Context, st *TaskStatus, fn func(ctx context.Context)) {
+	backoff := s.MinBackoff
+	for {
+		started := time.Now()
+		crashed := make(chan any, 1)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			defer func() {
+				if rec := recover(); rec != nil {
+					crash.Report(st.Name, rec, debug.Stack())
+					crashed <- rec
+				}
+			}()
+			fn(ctx)
+		}()
+		<-done
+
+		var rec any
+		select {
+		case rec = <-crashed:
+		default:
+		}
+		if rec == nil || ctx.Err() != nil {
+			s.update(st, func() { st.State = StateStopped })
+			return
+		}
+
+		if time.Since(started) >= s.MaxBackoff {
+			backoff = s.MinBackoff
+		}
+		s.update(st, func() {
+			st.State = StateRestarting
+			st.LastCrash = fmt.Sprint(rec)
+			st.CrashedAt = time.Now()
+		})
+		log.Printf("🔁 Restarting %s in %v", st.Name, backoff)
+		select {
+		case <-ctx.Done():
+			s.update(st, func() { st.State = StateStopped })
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, s.MaxBackoff)
+		s.update(st, func() {
+			st.State = StateRunning
+			st.Restarts++
+		})
+	}
+}
+
+func (s *Supervisor) update(st *TaskStatus, fn func()) {
+	s.mu.Lock()
+	fn()
+	s.mu.Unlock()
+}
+
+// Status returns every task's state, in the order they were started. A
+// nil Supervisor has no tasks.
+func (s *Supervisor) Status() []TaskStatus {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]TaskStatus, len(s.tasks))
+	for i, st := range s.tasks {
+		out[i] = *st
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+package supervisor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"cmon/internal/crash"
+)
+
+func TestGoRestartsAfterPanic(t *testing.T) {
+	var reports []string
+	crash.SetReporter(func(component string, value any, stack []byte) {
+		reports = append(reports, component+": "+value.(string))
+	})
+	t.Cleanup(func() { crash.SetReporter(nil) })
+
+	var wg sync.WaitGroup
+	s := New(&wg)
+	s.MinBackoff, s.MaxBackoff = time.Millisecond, 4*time.Millisecond
+	runs := 0
+	s.Go(context.Background(), "keep-alive", func(ctx context.Context) {
+		runs++
+		if runs < 3 {
+			panic("boom")
+		}
+	})
+	wg.Wait()
+
+	if runs != 3 {
+		t.Errorf("runs = %d, want 3 (two panics, then a clean return)", runs)
+	}
+	if len(reports) != 2 || reports[0] != "keep-alive: boom" {
+		t.Errorf("reports = %v", reports)
+	}
+	st := s.Status()
+	if len(st) != 1 || st[0].State != StateStopped || st[0].Restarts != 2 || st[0].LastCrash != "boom" || st[0].CrashedAt.IsZero() {
+		t.Errorf("status = %+v", st)
+	}
+}
+
+func TestStatusWhileRestarting(t *testing.T) {
+	var wg sync.WaitGroup
+	s := New(&wg)
+	s.MinBackoff = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Go(ctx, "Telegram handler", func(context.Context) { panic("boom") })
+	blocked := make(chan struct{})
+	s.Go(ctx, "summary scheduler", func(ctx context.Context) {
+		close(blocked)
+		<-ctx.Done()
+	})
+	<-blocked
+
+	deadline := time.Now().Add(time.Second)
+	for s.Status()[0].State != StateRestarting {
+		if time.Now().After(deadline) {
+			t.Fatalf("crashed task never reported restarting: %+v", s.Status())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if st := s.Status()[1]; st.State != StateRunning || st.Restarts != 0 {
+		t.Errorf("healthy task = %+v", st)
+	}
+
+	// Shutdown must not wait out the backoff.
+	cancel()
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("tasks should stop once ctx is done")
+	}
+	for _, st := range s.Status() {
+		if st.State != StateStopped {
+			t.Errorf("after shutdown: %+v", st)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cmon/internal/belt"
+	"cmon/internal/i18n"
+	"cmon/internal/storage"
+)
+
+// MessageReactionUpdated is a change of a user's reaction on a message.
+// Telegram only delivers these when the bot is a chat administrator and
+// message_reaction is listed in allowed_updates.
+type MessageReactionUpdated struct {
+	Chat        Chat           `json:"chat"`
+	MessageID   int            `json:"message_id"`
+	User        *User          `json:"user,omitempty"`
+	NewReaction []ReactionType `json:"new_reaction"`
+}
+
+// ReactionType is an emoji or custom-emoji reaction.
+type ReactionType struct {
+	Type  string `json:"type"`
+	Emoji string `json:"emoji,omitempty"`
+}
+
+// complaintKeyboard builds the inline keyboard under a complaint message.
+// With AckButton set, a "👀 Seen" button ("👀 Acknowledge" with AckClaims)
+// sits next to "Mark as Resolved"; once acknowledged it shows who saw it so
+// the rest of the group knows.
+// DetailsButton adds a "🔍 Details" row for the live portal status.
+func (c *Client) complaintKeyboard(complaintNumber, seenBy string) *InlineKeyboardMarkup {
+	row := []InlineKeyboardButton{
+		{
+			Text:         i18n.T("button.resolve"),
+			CallbackData: fmt.Sprintf("resolve:%s", complaintNumber),
+		},
+	}
+	if c.AckButton {
+		text := i18n.T("button.seen")
+		if c.AckClaims {
+			text = i18n.T("button.acknowledge")
+		}
+		if seenBy != "" {
+			text = "👀 " + truncateRunes(seenBy, 20)
+		}
+		row = append(row, InlineKeyboardButton{
+			Text:         text,
+			CallbackData: fmt.Sprintf("ack:%s", complaintNumber),
+		})
+	}
+	rows := [][]InlineKeyboardButton{row}
+	if c.DetailsButton {
+		rows = append(rows, []InlineKeyboardButton{{
+			Text:         i18n.T("button.details"),
+			CallbackData: fmt.Sprintf("details:%s", complaintNumber),
+		}})
+	}
+	return &InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// handleAckCallback processes a click on the "👀 Seen" (or "👀 Acknowledge")
+// button.
+func (c *Client) handleAckCallback(query *CallbackQuery, complaintNumber string, stor *storage.Storage) {
+	by := userDisplayName(query.From)
+	ok, err := stor.MarkAcknowledged(complaintNumber, by, query.From.ID)
+	if err != nil {
+		log.Printf("⚠️  Failed to record acknowledgment for %s: %v\n", complaintNumber, err)
+		c.answerCallbackQuery(query.ID, "Error saving acknowledgment")
+		return
+	}
+	if !ok {
+		if current, _, found := stor.GetAcknowledgment(complaintNumber); found && c.AckClaims {
+			c.answerCallbackQuery(query.ID, "Already being handled by "+current)
+			return
+		}
+		c.answerCallbackQuery(query.ID, "Already acknowledged")
+		return
+	}
+
+	log.Printf("👀 Complaint %s acknowledged by %s\n", complaintNumber, by)
+	if c.AckClaims {
+		c.answerCallbackQuery(query.ID, "You are handling this complaint")
+	} else {
+		c.answerCallbackQuery(query.ID, "Marked as seen")
+	}
+	if query.Message != nil && query.Message.Chat != nil {
+		c.showAcknowledgment(fmt.Sprintf("%d", query.Message.Chat.ID), query.Message.MessageID, complaintNumber, by, stor)
+	}
+}
+
+// handleReaction treats any reaction on a complaint message as an
+// acknowledgment. Removing a reaction does not un-acknowledge. With
+// AckClaims only the button claims a complaint, so reactions are ignored.
+func (c *Client) handleReaction(r *MessageReactionUpdated, stor *storage.Storage) {
+	if r.User == nil || len(r.NewReaction) == 0 || c.AckClaims {
+		return
+	}
+	complaintNumber, found := stor.GetComplaintIDByMessageID(fmt.Sprintf("%d", r.MessageID))
+	if !found || inDigest(stor, complaintNumber) {
+		return
+	}
+	by := userDisplayName(*r.User)
+	ok, err := stor.MarkAcknowledged(complaintNumber, by, r.User.ID)
+	if err != nil {
+		log.Printf("⚠️  Failed to record reaction acknowledgment for %s: %v\n", complaintNumber, err)
+		return
+	}
+	if ok {
+		log.Printf("👀 Complaint %s acknowledged by %s (reaction)\n", complaintNumber, by)
+		c.editComplaintKeyboard(fmt.Sprintf("%d", r.Chat.ID), r.MessageID, complaintNumber, by)
+	}
+}
+
+// showAcknowledgment updates a complaint message once it is acknowledged.
+// With AckClaims the stored text gains a "Being handled by" line; otherwise,
+// or when the text is not stored, only the keyboard changes.
+func (c *Client) showAcknowledgment(chatID string, messageID int, complaintNumber, by string, stor *storage.Storage) {
+	if inDigest(stor, complaintNumber) {
+		return // the digest's buttons are shared; leave them be
+	}
+	if c.AckClaims {
+		if text, footerDays, ok := stor.GetMessageText(complaintNumber); ok {
+			req := EditMessageRequest{
+				ChatID:      chatID,
+				MessageID:   strconv.Itoa(messageID),
+				Text:        c.complaintText(text, by, footerDays, c.complaintNotes(complaintNumber, stor)),
+				ParseMode:   "HTML",
+				ReplyMarkup: c.complaintKeyboard(complaintNumber, by),
+			}
+			err := c.edit("editMessageText", req)
+			if err == nil {
+				return
+			}
+			log.Printf("⚠️  Failed to show handler of %s: %v\n", complaintNumber, err)
+		}
+	}
+	c.editComplaintKeyboard(chatID, messageID, complaintNumber, by)
+}
+
+// complaintText is a complaint message as sent plus the lines added later:
+// who is handling it (with AckClaims), staff notes and its age footer.
+func (c *Client) complaintText(text, ackBy string, footerDays int, notes []storage.Note) string {
+	if c.AckClaims && ackBy != "" {
+		text += "\n\n🛠️ Being handled by " + htmlEscape(ackBy)
+	}
+	if len(notes) > 0 {
+		text += "\n\n" + notesBlock(notes, c.location())
+	}
+	if footerDays > 0 {
+		text += "\n\n" + ageFooter(footerDays)
+	}
+	return text
+}
+
+// mayResolve reports whether u may resolve a complaint acknowledged by by
+// (user ID byID, 0 when unknown): the acknowledger or one of Admins.
+func (c *Client) mayResolve(u User, by string, byID int64) bool {
+	if byID != 0 && byID == u.ID || byID == 0 && by == userDisplayName(u) {
+		return true
+	}
+	return c.isAdmin(u)
+}
+
+// isAdmin reports whether u is one of Admins.
+func (c *Client) isAdmin(u User) bool {
+	id := strconv.FormatInt(u.ID, 10)
+	for _, admin := range c.Admins {
+		if admin == id || u.Username != "" && admin == "@"+strings.ToLower(u.Username) {
+			return true
+		}
+	}
+	return false
+}
+
+// editComplaintKeyboard swaps the keyboard under a complaint message for one
+// showing who acknowledged it.
+func (c *Client) editComplaintKeyboard(chatID string, messageID int, complaintNumber, seenBy string) {
+	req := struct {
+		ChatID      string                `json:"chat_id"`
+		MessageID   int                   `json:"message_id"`
+		ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup"`
+	}{
+		ChatID:      chatID,
+		MessageID:   messageID,
+		ReplyMarkup: c.complaintKeyboard(complaintNumber, seenBy),
+	}
+	if err := c.edit("editMessageReplyMarkup", req); err != nil {
+		log.Printf("⚠️  Failed to update keyboard for %s: %v\n", complaintNumber, err)
+	}
+}
+
+// SendUnseenReminder posts the "nobody has looked at these" reminder. Items
+// are grouped by the chat their complaint was routed to, so each belt chat
+// only sees its own complaints.
+func (c *Client) SendUnseenReminder(items []storage.Unseen) error {
+	if c == nil || len(items) == 0 {
+		return nil
+	}
+
+	byChat := make(map[string][]storage.Unseen)
+	for _, u := range items {
+		chatID := c.ChatIDFor(u.Belt, u.Category)
+		byChat[chatID] = append(byChat[chatID], u)
+	}
+	chats := make([]string, 0, len(byChat))
+	for chatID := range byChat {
+		chats = append(chats, chatID)
+	}
+	sort.Strings(chats)
+
+	now := time.Now()
+	var firstErr error
+	for _, chatID := range chats {
+		msg := Message{
+			ChatID:                chatID,
+			Text:                  formatUnseenReminder(chatID, byChat[chatID], now, c.AckClaims),
+			ParseMode:             "HTML",
+			DisableWebPagePreview: true,
+		}
+		if err := c.send("sendMessage", msg); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to send unseen reminder: %w", err)
+		}
+	}
+	return firstErr
+}
+
+// formatUnseenReminder renders one chat's reminder. Complaint numbers link
+// to the original message when the chat is a supergroup. claims words the
+// hint for AckClaims, where the items are complaints nobody has claimed.
+func formatUnseenReminder(chatID string, items []storage.Unseen, now time.Time, claims bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "👀 <b>Nobody has looked at these yet (%d)</b>\n", len(items))
+	for _, u := range items {
+		id := htmlEscape(u.ComplaintID)
+		if link := messageLink(chatID, u.MessageID); link != "" {
+			id = fmt.Sprintf("<a href=\"%s\">%s</a>", link, id)
+		}
+		line := "\n• " + id
+		if name := strings.TrimSpace(u.ConsumerName); name != "" {
+			line += " — " + htmlEscape(name)
+		}
+		if village := strings.TrimSpace(u.Village); village != "" {
+			line += ", " + htmlEscape(village)
+		}
+		line += fmt.Sprintf(" (%s %s)", belt.StyleFor(u.Belt).Emoji, htmlEscape(belt.DisplayName(u.Belt)))
+		if !u.NotifiedAt.IsZero() {
+			line += " · " + formatWaiting(now.Sub(u.NotifiedAt))
+		}
+		b.WriteString(line)
+	}
+	if claims {
+		b.WriteString("\n\nTap 👀 Acknowledge on a complaint to take it on.")
+	} else {
+		b.WriteString("\n\nTap 👀 Seen or react to a complaint to acknowledge it.")
+	}
+	return b.String()
+}
+
+// messageLink returns a t.me link to a message in a supergroup/channel
+// ("-100…" chat IDs). Basic groups and private chats have no public message
+// links, so "" is returned.
+func messageLink(chatID, messageID string) string {
+	if messageID == "" || !strings.HasPrefix(chatID, "-100") {
+		return ""
+	}
+	return fmt.Sprintf("https://t.me/c/%s/%s", strings.TrimPrefix(chatID, "-100"), messageID)
+}
+
+// formatWaiting renders how long a complaint has gone unseen, e.g. "2h 05m".
+func formatWaiting(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// userDisplayName prefers @username, falling back to the first name.
+func userDisplayName(u User) string {
+	if u.Username != "" {
+		return "@" + u.Username
+	}
+	return u.FirstName
+}
@@ -0,0 +1,185 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"cmon/internal/backup"
+	"cmon/internal/flags"
+	"cmon/internal/logging"
+	"cmon/internal/storage"
+)
+
+// adminCommands are only honoured in AdminChatID.
+var adminCommands = map[string]bool{
+	"/debug":    true,
+	"/loglevel": true,
+	"/flag":     true,
+	"/flags":    true,
+}
+
+// isAdminCommand reports whether text starts with one of adminCommands.
+func isAdminCommand(text string) bool {
+	fields := strings.Fields(strings.TrimSpace(text))
+	return len(fields) > 0 && adminCommands[fields[0]]
+}
+
+// adminAllowed reports whether message may run an admin command. It must
+// come from AdminChatID, and when that chat also receives complaints (as
+// it does by default, TELEGRAM_ADMIN_CHAT_ID unset) its sender must be one
+// of Admins: otherwise every member of the broadcast group could flip
+// flags or download the database.
+func (c *Client) adminAllowed(message *IncomingMessage) bool {
+	if c.AdminChatID == "" || message.Chat == nil || strconv.FormatInt(message.Chat.ID, 10) != c.AdminChatID {
+		return false
+	}
+	if !c.broadcastChat(c.AdminChatID) {
+		return true
+	}
+	return message.From != nil && c.isAdmin(*message.From)
+}
+
+// broadcastChat reports whether chatID receives complaint messages: the
+// main chat or a belt or category route.
+func (c *Client) broadcastChat(chatID string) bool {
+	if chatID == c.ChatID {
+		return true
+	}
+	for _, routes := range []map[string]string{c.BeltRoutes, c.CategoryRoutes} {
+		for _, dest := range routes {
+			if chat, _, _ := strings.Cut(dest, ":"); chat == chatID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// handleAdminCommand runs a runtime-toggle command. Commands failing
+// adminAllowed are ignored so group members can't flip flags.
+func (c *Client) handleAdminCommand(message *IncomingMessage) {
+	if c.Flags == nil || !c.adminAllowed(message) {
+		log.Printf("⚠️  Ignoring admin command %q outside the admin chat or from a non-admin\n", strings.Fields(message.Text)[0])
+		return
+	}
+	reply := runAdminCommand(c.Flags, message.Text)
+	c.send("sendMessage", Message{
+		ChatID:           c.AdminChatID,
+		Text:             reply,
+		ParseMode:        "HTML",
+		ReplyToMessageID: message.MessageID,
+	})
+}
+
+// maxDocumentSize is the largest file the Bot API accepts via sendDocument.
+const maxDocumentSize = 50 << 20
+
+// handleBackupCommand answers /backup in AdminChatID with a fresh snapshot
+// of the database as a document, for keeping a copy off the machine. It is
+// gated by adminAllowed: the database holds every consumer's details.
+func (c *Client) handleBackupCommand(ctx context.Context, message *IncomingMessage, stor *storage.Storage) {
+	if !c.adminAllowed(message) {
+		log.Printf("⚠️  Ignoring /backup outside the admin chat or from a non-admin\n")
+		return
+	}
+	reply := func(text string) {
+		c.send("sendMessage", Message{ChatID: c.AdminChatID, Text: text, ParseMode: "HTML", ReplyToMessageID: message.MessageID})
+	}
+
+	dir, err := os.MkdirTemp("", "cmon-backup-")
+	if err != nil {
+		log.Printf("⚠️  /backup: %v\n", err)
+		reply("❌ Backup failed: " + htmlEscape(err.Error()))
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	name := backup.FileName(now)
+	path := filepath.Join(dir, name)
+	if err := stor.Backup(ctx, path); err != nil {
+		log.Printf("⚠️  /backup: snapshot failed: %v\n", err)
+		reply("❌ Backup failed: " + htmlEscape(err.Error()))
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("⚠️  /backup: %v\n", err)
+		reply("❌ Backup failed: " + htmlEscape(err.Error()))
+		return
+	}
+	if len(data) > maxDocumentSize {
+		reply(fmt.Sprintf("❌ The backup is %d MB, over Telegram's 50 MB limit. Copy it from the server instead.", len(data)>>20))
+		return
+	}
+
+	caption := fmt.Sprintf("💾 Database backup, %s", now.In(c.location()).Format("02 Jan 2006 15:04"))
+	if err := c.SendDocument(c.AdminChatID, name, data, caption); err != nil {
+		log.Printf("⚠️  /backup: upload failed: %v\n", err)
+		reply("❌ Backup upload failed: " + htmlEscape(err.Error()))
+		return
+	}
+	log.Printf("💾 Sent a %d KB database backup to the admin chat\n", len(data)>>10)
+}
+
+// runAdminCommand applies text to f and returns the HTML reply.
+func runAdminCommand(f *flags.Flags, text string) string {
+	args := strings.Fields(strings.ToLower(strings.TrimSpace(text)))
+	switch args[0] {
+	case "/debug":
+		if len(args) != 2 || (args[1] != "on" && args[1] != "off") {
+			return "Usage: <code>/debug on|off</code>"
+		}
+		lvl := "info"
+		if args[1] == "on" {
+			lvl = "debug"
+		}
+		if err := f.SetLogLevel(lvl); err != nil {
+			return "❌ " + htmlEscape(err.Error())
+		}
+		if lvl == "debug" {
+			return "🐛 Debug logging <b>on</b> — portal requests are logged too. Send <code>/debug off</code> when done; it stays on across restarts."
+		}
+		return "✅ Debug logging <b>off</b>."
+
+	case "/loglevel":
+		if len(args) != 2 {
+			return fmt.Sprintf("Log level: <b>%s</b>\nUsage: <code>/loglevel debug|info|warn|error</code>", logging.Level())
+		}
+		if err := f.SetLogLevel(args[1]); err != nil {
+			return "❌ " + htmlEscape(err.Error())
+		}
+		return fmt.Sprintf("✅ Log level set to <b>%s</b>.", logging.Level())
+
+	case "/flag":
+		var name flags.Name
+		ok := len(args) == 3 && (args[2] == "on" || args[2] == "off")
+		if ok {
+			name, ok = flags.Parse(args[1])
+		}
+		if !ok {
+			return fmt.Sprintf("Usage: <code>/flag name on|off</code>\nFlags: <code>%s</code>", flagNames())
+		}
+		if err := f.Set(name, args[2] == "on"); err != nil {
+			return "❌ " + htmlEscape(err.Error())
+		}
+		return fmt.Sprintf("✅ <b>%s</b> switched <b>%s</b>.", name, args[2])
+
+	default: // /flags
+		return "<b>Runtime settings</b>\n<pre>" + htmlEscape(f.Status()) + "</pre>"
+	}
+}
+
+func flagNames() string {
+	names := make([]string, len(flags.All))
+	for i, n := range flags.All {
+		names[i] = string(n)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,63 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"cmon/internal/storage"
+)
+
+// RefreshAgeFooters adds a "⏳ pending N days" footer to the message of
+// every complaint pending a day or more, or updates it when the day count
+// has moved on. The message text is the one stored at send time, so a
+// message is edited at most once a day; its keyboard (and, with AckClaims,
+// the "Being handled by" line) is sent again because editing the text
+// would otherwise drop it.
+func (c *Client) RefreshAgeFooters(stor *storage.Storage, now time.Time) error {
+	if c == nil {
+		return nil
+	}
+	items, err := stor.GetAging()
+	if err != nil {
+		return fmt.Errorf("failed to list pending complaints: %w", err)
+	}
+
+	edited := 0
+	for _, a := range items {
+		if a.FirstSeenAt.IsZero() {
+			continue
+		}
+		days := int(now.Sub(a.FirstSeenAt) / (24 * time.Hour))
+		if days < 1 || days == a.FooterDays {
+			continue
+		}
+		req := EditMessageRequest{
+			ChatID:      c.ChatIDFor(a.Belt, a.Category),
+			MessageID:   a.MessageID,
+			Text:        c.complaintText(a.Text, a.AcknowledgedBy, days, c.complaintNotes(a.ComplaintID, stor)),
+			ParseMode:   "HTML",
+			ReplyMarkup: c.complaintKeyboard(a.ComplaintID, a.AcknowledgedBy),
+		}
+		if err := c.edit("editMessageText", req); err != nil {
+			log.Printf("⚠️  Failed to update age footer for complaint %s: %v", a.ComplaintID, err)
+			continue
+		}
+		if err := stor.SetAgeFooterDays(a.ComplaintID, days); err != nil {
+			log.Printf("⚠️  Failed to record age footer for complaint %s: %v", a.ComplaintID, err)
+		}
+		edited++
+	}
+	if edited > 0 {
+		log.Printf("⏳ Updated the age footer on %d complaint messages", edited)
+	}
+	return nil
+}
+
+// ageFooter renders the footer line for a complaint pending days days.
+func ageFooter(days int) string {
+	if days == 1 {
+		return "⏳ pending 1 day"
+	}
+	return fmt.Sprintf("⏳ pending %d days", days)
+}
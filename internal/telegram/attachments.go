@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PhotoSize is one size of a photo; Telegram sends several, smallest first.
+type PhotoSize struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	FileSize     int64  `json:"file_size,omitempty"`
+}
+
+// photoRemark is the portal remark for a photo sent without a caption.
+const photoRemark = "[photo attached]"
+
+// handlePhotoRemark resolves a complaint with a photo (repair evidence)
+// sent in reply to its remarks prompt. The caption, or photoRemark, goes
+// to the portal; the largest size is saved under AttachmentsDir and its
+// path recorded in the complaint's history. A photo that cannot be saved
+// does not hold up the resolution.
+func (c *Client) handlePhotoRemark(t *Turn) {
+	complaintNumber, message := t.Conv.ComplaintNumber, t.Message
+	log.Printf("📷 Received photo resolution note from %s for complaint %s\n", t.From.FirstName, complaintNumber)
+
+	path, err := c.saveAttachment(complaintNumber, message.Photo[len(message.Photo)-1])
+	switch {
+	case err != nil:
+		log.Printf("⚠️  Failed to save photo for complaint %s: %v\n", complaintNumber, err)
+	case path != "":
+		if err := t.Store.AddHistoryAttachment(complaintNumber, path); err != nil {
+			log.Printf("⚠️  Failed to record photo for complaint %s: %v\n", complaintNumber, err)
+		}
+		log.Printf("📎 Complaint %s: photo from %s saved to %s\n", complaintNumber, t.From.FirstName, path)
+	}
+
+	note := strings.TrimSpace(message.Caption)
+	if note == "" {
+		note = photoRemark
+	}
+	c.resolveWithNote(t.Session, t.Store, *t.Conv, note, t.From)
+}
+
+// saveAttachment downloads p into complaintNumber's directory under
+// AttachmentsDir and returns the file's path, or "" when AttachmentsDir is
+// unset.
+func (c *Client) saveAttachment(complaintNumber string, p PhotoSize) (string, error) {
+	if c.AttachmentsDir == "" {
+		return "", nil
+	}
+	data, err := c.DownloadFile(p.FileID)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(c.AttachmentsDir, safeFileName(complaintNumber))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create attachments dir: %w", err)
+	}
+	name := p.FileUniqueID
+	if name == "" {
+		name = p.FileID
+	}
+	// Bot API photos are always JPEG.
+	path := filepath.Join(dir, safeFileName(name)+".jpg")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("write attachment: %w", err)
+	}
+	return path, nil
+}
+
+// safeFileName replaces anything but letters, digits, '-' and '_' in s so
+// portal complaint numbers and file IDs can't escape AttachmentsDir.
+func safeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+}
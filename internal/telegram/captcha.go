@@ -0,0 +1,169 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"cmon/internal/i18n"
+	"cmon/internal/session"
+
+	"github.com/fogleman/gg"
+)
+
+// captchaPrompt is a login captcha waiting for a human answer.
+type captchaPrompt struct {
+	chatID    string
+	messageID int
+	answer    chan string
+}
+
+// CaptchaSolver returns a session.Solver that posts the captcha to chatID
+// and waits up to timeout for someone to reply to it with the answer.
+// HandleUpdates must be running for replies to arrive.
+//
+// Only one prompt is outstanding at a time; a second login attempt while
+// one is pending fails straight away rather than spamming the chat.
+func (c *Client) CaptchaSolver(chatID string, timeout time.Duration) session.Solver {
+	return session.SolverFunc(func(captcha session.Captcha) (string, error) {
+		return c.askCaptcha(chatID, captcha, timeout)
+	})
+}
+
+func (c *Client) askCaptcha(chatID string, captcha session.Captcha, timeout time.Duration) (string, error) {
+	if c == nil {
+		return "", fmt.Errorf("telegram not configured")
+	}
+
+	c.captchaMu.Lock()
+	if c.captcha != nil {
+		c.captchaMu.Unlock()
+		return "", fmt.Errorf("a captcha prompt is already waiting for an answer")
+	}
+	prompt := &captchaPrompt{chatID: chatID, answer: make(chan string, 1)}
+	c.captcha = prompt
+	c.captchaMu.Unlock()
+	defer func() {
+		c.captchaMu.Lock()
+		c.captcha = nil
+		c.captchaMu.Unlock()
+	}()
+
+	messageID, err := c.sendCaptchaPrompt(chatID, captcha, timeout)
+	if err != nil {
+		return "", fmt.Errorf("failed to send captcha prompt: %w", err)
+	}
+	c.captchaMu.Lock()
+	prompt.messageID = messageID
+	c.captchaMu.Unlock()
+	log.Printf("🧩 Captcha sent to chat %s; waiting up to %v for an answer\n", chatID, timeout)
+
+	select {
+	case answer := <-prompt.answer:
+		log.Println("🧩 Captcha answer received from Telegram")
+		return answer, nil
+	case <-time.After(timeout):
+		c.send("sendMessage", Message{
+			ChatID:           chatID,
+			Text:             i18n.T("captcha.timeout"),
+			ParseMode:        "HTML",
+			ReplyToMessageID: messageID,
+		})
+		return "", fmt.Errorf("no captcha answer within %v", timeout)
+	}
+}
+
+// sendCaptchaPrompt posts the captcha as an image with a force-reply
+// prompt, falling back to plain text when rendering fails. Returns the
+// message ID replies must point at.
+func (c *Client) sendCaptchaPrompt(chatID string, captcha session.Captcha, timeout time.Duration) (int, error) {
+	caption := i18n.T("captcha.prompt", formatWaiting(timeout))
+	markup := ForceReply{ForceReply: true, InputFieldPlaceholder: i18n.T("captcha.placeholder")}
+
+	var result SendMessageResult
+	img, err := renderCaptcha(captcha.Text)
+	if err == nil {
+		result, err = c.uploadPhoto(chatID, img, caption, markup)
+	} else {
+		log.Printf("⚠️  Failed to render captcha image, sending text: %v\n", err)
+		result, err = doRequest[SendMessageResult](context.Background(), c, "sendMessage", Message{
+			ChatID:      chatID,
+			Text:        fmt.Sprintf("%s\n\n<code>%s</code>", htmlEscape(caption), htmlEscape(captcha.Text)),
+			ParseMode:   "HTML",
+			ReplyMarkup: markup,
+		})
+	}
+	if err != nil {
+		return 0, err
+	}
+	if result.MessageID == 0 {
+		return 0, fmt.Errorf("captcha prompt returned no message ID")
+	}
+	return result.MessageID, nil
+}
+
+// deliverCaptchaAnswer hands a reply to the outstanding captcha prompt to
+// the waiting login. Reports whether message was consumed.
+func (c *Client) deliverCaptchaAnswer(message *IncomingMessage) bool {
+	c.captchaMu.Lock()
+	prompt := c.captcha
+	c.captchaMu.Unlock()
+	if prompt == nil || !isCaptchaReply(prompt, message) {
+		return false
+	}
+
+	answer := strings.TrimSpace(message.Text)
+	select {
+	case prompt.answer <- answer:
+		log.Printf("🧩 Captcha answer from %s\n", message.From.FirstName)
+		c.send("sendMessage", Message{
+			ChatID:           prompt.chatID,
+			Text:             i18n.T("captcha.got_it"),
+			ParseMode:        "HTML",
+			ReplyToMessageID: message.MessageID,
+		})
+	default:
+		// Someone already answered this prompt.
+	}
+	return true
+}
+
+// isCaptchaReply reports whether message replies to prompt in its chat.
+func isCaptchaReply(prompt *captchaPrompt, message *IncomingMessage) bool {
+	if prompt.messageID == 0 || message.ReplyToMessage == nil || message.Chat == nil {
+		return false
+	}
+	return message.ReplyToMessage.MessageID == prompt.messageID &&
+		strconv.FormatInt(message.Chat.ID, 10) == prompt.chatID
+}
+
+// renderCaptcha draws the captcha text as a PNG so the prompt looks like
+// what the portal shows. It uses gg's built-in bitmap face, scaled up, so
+// no font files are needed on the host.
+func renderCaptcha(text string) ([]byte, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("empty captcha text")
+	}
+	const scale, pad = 4, 12
+	dc := gg.NewContext(1, 1)
+	tw, th := dc.MeasureString(text)
+	w, h := int((tw+2*pad)*scale), int((th+2*pad)*scale)
+
+	dc = gg.NewContext(w, h)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+	dc.Scale(scale, scale)
+	dc.SetRGB(0.1, 0.1, 0.1)
+	dc.DrawStringAnchored(text, float64(w)/scale/2, float64(h)/scale/2, 0.5, 0.35)
+
+	var buf bytes.Buffer
+	if err := dc.EncodePNG(&buf); err != nil {
+		return nil, fmt.Errorf("encode captcha image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
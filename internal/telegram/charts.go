@@ -0,0 +1,109 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"cmon/internal/charts"
+	"cmon/internal/i18n"
+	"cmon/internal/storage"
+)
+
+// Default and longest /chart periods, in days.
+const (
+	defaultChartDays = 30
+	maxChartDays     = 365
+)
+
+func isChartCommand(text string) bool {
+	fields := strings.Fields(strings.TrimSpace(text))
+	return len(fields) > 0 && fields[0] == "/chart"
+}
+
+// handleChartCommand processes /chart [volume|resolution] [days]: the daily
+// complaint volume and/or the resolution-time distribution over the last
+// days (30 by default), both without a kind.
+func (c *Client) handleChartCommand(message *IncomingMessage, stor *storage.Storage) {
+	args := strings.Fields(strings.TrimSpace(message.Text))[1:]
+	kind, days := "", defaultChartDays
+	for _, arg := range args {
+		switch arg = strings.ToLower(arg); arg {
+		case "volume", "resolution":
+			kind = arg
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(arg, "d"))
+		if err != nil || n < 1 || n > maxChartDays {
+			c.sendTextMessage(i18n.T("command.chart.usage", maxChartDays, defaultChartDays), "HTML")
+			return
+		}
+		days = n
+	}
+
+	now := time.Now().In(c.location())
+	from := now.AddDate(0, 0, -days+1)
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	if err := c.sendCharts(stor, kind, from, now, i18n.T("command.chart.last_days", days)); err != nil {
+		log.Printf("⚠️  /chart failed: %v", err)
+		c.sendTextMessage(i18n.T("command.chart.failed", htmlEscape(err.Error())), "HTML")
+	}
+}
+
+// sendCharts posts the volume and/or resolution chart (kind "" for both)
+// for history between from and to; period names it in the titles.
+func (c *Client) sendCharts(stor *storage.Storage, kind string, from, to time.Time, period string) error {
+	entries, err := stor.GetHistorySince(from)
+	if err != nil {
+		return fmt.Errorf("load history: %w", err)
+	}
+
+	if kind == "" || kind == "volume" {
+		png, err := charts.RenderVolume(i18n.T("chart.volume.title", period), charts.DailyVolume(entries, from, to))
+		if err != nil {
+			return err
+		}
+		if err := c.SendPhoto(c.ChatID, png, i18n.T("chart.volume.caption", period)); err != nil {
+			return err
+		}
+	}
+	if kind == "" || kind == "resolution" {
+		png, err := charts.RenderResolution(i18n.T("chart.resolution.title", period), charts.ResolutionTimes(entries, from, to))
+		if err != nil {
+			return err
+		}
+		if err := c.SendPhoto(c.ChatID, png, i18n.T("chart.resolution.caption", period)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PostWeeklyReport sends the week ending now: a short text summary, then
+// the volume and resolution charts. Exposed for the scheduler in main.go.
+func (c *Client) PostWeeklyReport(stor *storage.Storage, now time.Time) error {
+	from := now.AddDate(0, 0, -6)
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	entries, err := stor.GetHistorySince(from)
+	if err != nil {
+		return fmt.Errorf("load history: %w", err)
+	}
+
+	newCount := 0
+	for _, e := range entries {
+		if !e.FirstSeenAt.Before(from) {
+			newCount++
+		}
+	}
+	res := charts.ResolutionTimes(entries, from, now)
+	text := i18n.T("weekly.title", from.Format("02 Jan"), now.Format("02 Jan"), newCount, res.Resolved)
+	if res.Resolved > 0 {
+		text += i18n.T("weekly.median", charts.FormatDuration(res.Median))
+	}
+	text += i18n.T("weekly.pending", len(stor.GetAllSeenComplaints()))
+	c.sendTextMessage(text, "HTML")
+
+	return c.sendCharts(stor, "", from, now, i18n.T("weekly.period"))
+}
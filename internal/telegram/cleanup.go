@@ -0,0 +1,146 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"cmon/internal/i18n"
+	"cmon/internal/storage"
+)
+
+// maxArchiveText keeps each archive message under Telegram's 4096
+// character limit; longer archives are split.
+const maxArchiveText = 3500
+
+// CleanupOptions controls CleanupResolvedMessages.
+type CleanupOptions struct {
+	MaxAge  time.Duration // messages of complaints resolved longer ago are cleaned up
+	Unpin   bool          // unpin the messages instead of deleting them
+	Archive bool          // first post an archive message listing them
+}
+
+// CleanupResolvedMessages removes the messages of complaints resolved more
+// than opts.MaxAge before now from their chats, so old resolved cards don't
+// clutter the group forever. With opts.Archive each chat first gets a
+// message listing the complaints being cleaned up; if that fails, nothing
+// in the chat is touched until the next run.
+//
+// A digest message is shared by several complaints and is only removed
+// once none of them is open. A message Telegram refuses to delete or unpin
+// (already gone, or too old for a bot that isn't a group admin) is logged
+// and not tried again.
+func (c *Client) CleanupResolvedMessages(stor *storage.Storage, opts CleanupOptions, now time.Time) error {
+	if c == nil {
+		return nil
+	}
+	due, err := stor.GetMessagesToCleanBefore(now.Add(-opts.MaxAge))
+	if err != nil {
+		return fmt.Errorf("failed to list resolved messages: %w", err)
+	}
+
+	byChat := make(map[string][]storage.HistoryEntry)
+	for _, e := range due {
+		if id, open := stor.GetComplaintIDByMessageID(e.TelegramMessageID); open {
+			log.Printf("🧹 Keeping message %s of complaint %s: it is shared with open complaint %s", e.TelegramMessageID, e.ComplaintID, id)
+			continue
+		}
+		chat := c.ChatIDFor(e.Belt, e.Category)
+		byChat[chat] = append(byChat[chat], e)
+	}
+	chats := make([]string, 0, len(byChat))
+	for chat := range byChat {
+		chats = append(chats, chat)
+	}
+	sort.Strings(chats)
+
+	var errs []error
+	cleaned := 0
+	for _, chat := range chats {
+		entries := byChat[chat]
+		if opts.Archive {
+			if err := c.postArchive(chat, entries); err != nil {
+				errs = append(errs, fmt.Errorf("archive for chat %s: %w", chat, err))
+				continue
+			}
+		}
+		done := make(map[string]error) // message ID → outcome, for digests
+		for _, e := range entries {
+			err, seen := done[e.TelegramMessageID]
+			if !seen {
+				err = c.cleanupMessage(chat, e.TelegramMessageID, opts.Unpin)
+				done[e.TelegramMessageID] = err
+			}
+			var apiErr *APIError
+			if err != nil && !(errors.As(err, &apiErr) && apiErr.Code == 400) {
+				if !seen {
+					errs = append(errs, fmt.Errorf("complaint %s: %w", e.ComplaintID, err))
+				}
+				continue // transient: try again next run
+			}
+			if err != nil && !seen {
+				log.Printf("⚠️  Telegram refused to clean up message %s of complaint %s, skipping it: %v", e.TelegramMessageID, e.ComplaintID, err)
+			}
+			if err := stor.MarkMessageCleaned(e.ComplaintID, e.TelegramMessageID); err != nil {
+				errs = append(errs, fmt.Errorf("complaint %s: %w", e.ComplaintID, err))
+				continue
+			}
+			cleaned++
+		}
+	}
+	if cleaned > 0 {
+		log.Printf("🧹 Cleaned up the messages of %d resolved complaints", cleaned)
+	}
+	return errors.Join(errs...)
+}
+
+// cleanupMessage deletes or unpins one message in chat.
+func (c *Client) cleanupMessage(chat, messageID string, unpin bool) error {
+	method := "deleteMessage"
+	if unpin {
+		method = "unpinChatMessage"
+	}
+	return c.send(method, map[string]interface{}{
+		"chat_id":    chat,
+		"message_id": messageID,
+	})
+}
+
+// postArchive sends chat the list of entries about to be cleaned up, split
+// over as many messages as it takes.
+func (c *Client) postArchive(chat string, entries []storage.HistoryEntry) error {
+	title := "🗄️ " + i18n.T("archive.title", len(entries))
+	var b strings.Builder
+	b.WriteString(title)
+	for _, e := range entries {
+		line := "\n" + archiveLine(e, c.location())
+		if b.Len()+len(line) > maxArchiveText {
+			if err := c.send("sendMessage", Message{ChatID: chat, Text: b.String(), ParseMode: "HTML"}); err != nil {
+				return err
+			}
+			b.Reset()
+			b.WriteString(title)
+		}
+		b.WriteString(line)
+	}
+	return c.send("sendMessage", Message{ChatID: chat, Text: b.String(), ParseMode: "HTML"})
+}
+
+// archiveLine is one complaint in an archive message: number, place and
+// when it was resolved.
+func archiveLine(e storage.HistoryEntry, loc *time.Location) string {
+	place := e.Village
+	if e.Belt != "" {
+		if place != "" {
+			place += ", "
+		}
+		place += e.Belt
+	}
+	if place != "" {
+		place = " (" + htmlEscape(place) + ")"
+	}
+	return i18n.T("archive.line", htmlEscape(e.ComplaintID), place, e.ResolvedAt.In(loc).Format("02 Jan 15:04"))
+}
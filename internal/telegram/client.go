@@ -32,8 +32,17 @@ import (
 
 	"cmon/internal/api"
 	"cmon/internal/belt"
+	"cmon/internal/buildinfo"
+	"cmon/internal/crash"
+	"cmon/internal/flags"
+	"cmon/internal/health"
+	"cmon/internal/i18n"
 	"cmon/internal/metrics"
+	"cmon/internal/msgtmpl"
+	"cmon/internal/notify"
+	"cmon/internal/outage"
 	"cmon/internal/session"
+	"cmon/internal/sla"
 	"cmon/internal/storage"
 )
 
@@ -57,26 +66,6 @@ const (
 	defaultRateInterval = 35 * time.Millisecond
 )
 
-// PendingResolution stores information about a complaint awaiting resolution note.
-//
-// When a user clicks "Mark as Resolved" button:
-//  1. Store complaint info in pendingResolutions map
-//  2. Send prompt message asking for resolution note
-//  3. Wait for user's reply
-//  4. Process reply and mark complaint as resolved
-//
-// Fields:
-//   - ComplaintNumber: Complaint ID being resolved
-//   - MessageID: Telegram message ID to edit after resolution
-//   - OriginalText: Original message text (to extract consumer name)
-//   - PromptMessageID: ID of prompt message (to delete after reply)
-type PendingResolution struct {
-	ComplaintNumber string
-	MessageID       string
-	OriginalText    string
-	PromptMessageID int
-}
-
 // Client represents a Telegram bot client.
 //
 // Thread-safety:
@@ -110,7 +99,110 @@ type Client struct {
 	// routed chat will see the resolution prompt land in the default chat.
 	// Tracked for a follow-up; not gating on this for the routing rollout.
 	BeltRoutes map[string]string
+	// CategoryRoutes maps a complaint category to a chat ID, or
+	// "chatID:topicID" for a forum topic, taking precedence over
+	// BeltRoutes (TELEGRAM_CATEGORY_ROUTES). Edits follow it like belt
+	// routes, with the same caveat for interactive flows.
+	CategoryRoutes map[string]string
+	// SummaryMap, when true, makes the scheduled summary follow the table
+	// image with a map of geocoded pending complaints (SUMMARY_MAP_ENABLED).
+	SummaryMap bool
+	// AckButton adds a "👀 Seen" button to complaint messages and listens
+	// for reactions, feeding the unseen-complaint reminder
+	// (UNSEEN_REMINDER_DELAY > 0).
+	AckButton bool
+	// AckClaims makes the button "👀 Acknowledge": the first tap claims the
+	// complaint, the message shows who is handling it, and only they or
+	// Admins may resolve it (TELEGRAM_ACK_CLAIMS).
+	AckClaims bool
+	// Admins are lower-cased "@username" or numeric user IDs allowed to
+	// resolve complaints claimed by someone else, and to use the admin
+	// commands in an admin chat that also receives complaints
+	// (TELEGRAM_ADMINS).
+	Admins []string
+	// DigestThreshold sends a batch of more than this many new complaints
+	// for one chat as digest messages with a resolve button per complaint
+	// (TELEGRAM_DIGEST_THRESHOLD). 0 sends a message per complaint.
+	DigestThreshold int
+	// QuietStart and QuietEnd (HH:MM, in Location) bound the quiet hours
+	// (QUIET_HOURS): routine new complaints are queued in storage and sent
+	// as a digest once they end. Empty disables.
+	QuietStart, QuietEnd string
+	// UrgentKeywords mark complaints that are sent even in quiet hours
+	// (QUIET_HOURS_URGENT); see notify.Complaint.Urgent.
+	UrgentKeywords []string
+	// Location is the zone for displayed times and quiet hours
+	// (TZ_OVERRIDE). Nil uses time.Local.
+	Location *time.Location
+	// QuickRemarks are canned resolution notes offered as buttons after
+	// "Mark as Resolved", next to "✏️ Custom…" for the typed-note prompt
+	// (RESOLUTION_REMARKS). Empty goes straight to the typed note.
+	QuickRemarks []string
+	// Transcriber turns voice-note remarks into text for the portal
+	// (VOICE_TRANSCRIBE); nil submits "[voice note attached]" instead.
+	Transcriber Transcriber
+	// UndoWindow keeps an "↩️ Undo" button on a resolved complaint this
+	// long, to reopen it on the portal and restore it (RESOLVE_UNDO_WINDOW).
+	// 0 resolves for good straight away.
+	UndoWindow time.Duration
+	// ConversationTimeout ends a conversation (a remarks prompt) left
+	// unanswered this long and deletes its prompt
+	// (CONVERSATION_TIMEOUT). 0 keeps it until answered.
+	ConversationTimeout time.Duration
+	// AttachmentsDir is where photos sent as resolution remarks are saved,
+	// one directory per complaint (DATA_DIR/attachments). Empty keeps only
+	// the caption.
+	AttachmentsDir string
+	// DetailsButton adds a "🔍 Details" button that replies with the
+	// complaint's live portal status (TELEGRAM_DETAILS_BUTTON).
+	DetailsButton bool
+	// AdminChatID is the only chat whose runtime-toggle commands (/debug,
+	// /loglevel, /flag) and /backup are honoured; Flags is what the toggles
+	// change. When it also receives complaints, only Admins may use them.
+	// Admin commands are ignored while Flags is nil.
+	AdminChatID string
+	Flags       *flags.Flags
+	// Resolved is told of each complaint resolved from the chat (its
+	// button, /resolveall) before it leaves storage, so the rest of cmon
+	// hears of it; main publishes it on the event bus. Nil skips it.
+	Resolved func(notify.Status) error
+	// Reopened is told of each complaint whose resolution was undone, once
+	// it is back in storage; main publishes it on the event bus as a new
+	// complaint so the other channels show it pending again. Nil skips it.
+	Reopened func(notify.Complaint) error
+	// Health answers /status with the health state and recent fetch
+	// cycles; /status is ignored while it is nil.
+	Health *health.Monitor
+	// SLAReports answers /slareport with a month's SLA compliance report;
+	// /slareport is ignored while it is nil.
+	SLAReports *sla.Reporter
+	// Templates lays out complaint messages (MESSAGE_TEMPLATE); nil uses
+	// the built-in layout.
+	Templates *msgtmpl.Set
+	// RedactPII masks mobile and consumer numbers to their last four
+	// digits in complaint messages and summary images (REDACT_PII).
+	// Storage keeps them in full.
+	RedactPII   bool
 	lastReqTime time.Time
+	// limiter enforces the Bot API's global and per-chat limits across
+	// every goroutine using this client; created lazily by rateLimiter.
+	limiterOnce sync.Once
+	limiter     *rateLimiter
+	// apiBase overrides the Bot API root (TELEGRAM_API_URL, tests).
+	apiBase string
+	// captcha is the outstanding human captcha prompt, if any; guarded by
+	// captchaMu rather than mu so a waiting login never blocks sends.
+	captchaMu sync.Mutex
+	captcha   *captchaPrompt
+	// undos are the resolutions whose Undo button is still live, by
+	// complaint number; guarded by undoMu.
+	undoMu sync.Mutex
+	undos  map[string]*undoable
+	// digestGu is the Gujarati line of each translated digest member, by
+	// complaint number, for redrawDigest; guarded by digestMu. It is not
+	// persisted: after a restart a digest redraws in English.
+	digestMu sync.Mutex
+	digestGu map[string]string
 	// httpClient is a persistent client reused across all API calls for
 	// connection pooling — creating a new client per call defeats TCP reuse.
 	httpClient *http.Client
@@ -126,6 +218,11 @@ type Message struct {
 	DisableWebPagePreview bool        `json:"disable_web_page_preview"`
 	ReplyMarkup           interface{} `json:"reply_markup,omitempty"`
 	ReplyToMessageID      int         `json:"reply_to_message_id,omitempty"`
+	// MessageThreadID posts into a forum topic; 0 posts to the chat.
+	MessageThreadID int `json:"message_thread_id,omitempty"`
+	// AllowSendingWithoutReply sends the message anyway when the one it
+	// replies to has been deleted.
+	AllowSendingWithoutReply bool `json:"allow_sending_without_reply,omitempty"`
 }
 
 // InlineKeyboardMarkup represents an inline keyboard.
@@ -148,9 +245,10 @@ type ForceReply struct {
 
 // Update represents a Telegram update from getUpdates.
 type Update struct {
-	UpdateID      int              `json:"update_id"`
-	Message       *IncomingMessage `json:"message,omitempty"`
-	CallbackQuery *CallbackQuery   `json:"callback_query,omitempty"`
+	UpdateID        int                     `json:"update_id"`
+	Message         *IncomingMessage        `json:"message,omitempty"`
+	CallbackQuery   *CallbackQuery          `json:"callback_query,omitempty"`
+	MessageReaction *MessageReactionUpdated `json:"message_reaction,omitempty"`
 }
 
 // IncomingMessage represents a received Telegram message.
@@ -159,6 +257,9 @@ type IncomingMessage struct {
 	From           *User            `json:"from,omitempty"`
 	Chat           *Chat            `json:"chat,omitempty"`
 	Text           string           `json:"text"`
+	Voice          *Voice           `json:"voice,omitempty"`
+	Photo          []PhotoSize      `json:"photo,omitempty"`
+	Caption        string           `json:"caption,omitempty"`
 	ReplyToMessage *IncomingMessage `json:"reply_to_message,omitempty"`
 }
 
@@ -198,6 +299,8 @@ type EditMessageRequest struct {
 //   - TELEGRAM_BOT_TOKEN: Bot API token from @BotFather
 //   - TELEGRAM_CHAT_ID: Target chat ID for notifications
 //   - DEBUG_MODE: If "true", skip actual API calls
+//   - TELEGRAM_API_URL: Bot API root, for a self-hosted Bot API server or
+//     the telegramtest fake (default https://api.telegram.org)
 //
 // Returns:
 //   - *Client: Configured Telegram client, or nil if not configured
@@ -232,6 +335,7 @@ func NewClient() *Client {
 		ChatID:       chatID,
 		DebugMode:    debugMode,
 		rateInterval: parseRateInterval(os.Getenv("TELEGRAM_RATE_INTERVAL_MS")),
+		apiBase:      strings.TrimSuffix(os.Getenv("TELEGRAM_API_URL"), "/"),
 		// httpClientTimeout > longPollSeconds so the long-poll cycle never
 		// trips the HTTP timeout before the API replies on its own clock.
 		httpClient: &http.Client{
@@ -264,95 +368,183 @@ func (c *Client) effectiveRateInterval() time.Duration {
 	return defaultRateInterval
 }
 
-// ChatIDForBelt returns the chat ID a complaint of the given canonical belt
-// should be sent to. Falls back to c.ChatID when no override exists. Public
-// so callers that edit a previously-sent message (the resolve flow) can
-// target the same chat the original message went to.
+// ChatIDForBelt returns the chat ID a message about the given canonical
+// belt (an outage alert) should be sent to. Falls back to c.ChatID when no
+// override exists.
 func (c *Client) ChatIDForBelt(canonicalBelt string) string {
+	return c.ChatIDFor(canonicalBelt, "")
+}
+
+// ChatIDFor returns the chat ID a complaint of the given canonical belt and
+// category is sent to: the category's route, else the belt's, else
+// c.ChatID. Public so callers that edit a previously-sent message (the
+// resolve flow) can target the same chat the original message went to.
+func (c *Client) ChatIDFor(canonicalBelt, category string) string {
+	return c.destinationFor(canonicalBelt, category).chatID
+}
+
+// destination is where a complaint message goes: a chat and, in a forum,
+// the topic (message thread) within it; 0 is the chat itself.
+type destination struct {
+	chatID string
+	thread int
+}
+
+// destinationFor resolves CategoryRoutes, then BeltRoutes. A category
+// route may name a topic as "chatID:topicID".
+func (c *Client) destinationFor(canonicalBelt, category string) destination {
 	if c == nil {
-		return ""
+		return destination{}
+	}
+	if dest, ok := c.CategoryRoutes[category]; ok && dest != "" {
+		chat, topic, _ := strings.Cut(dest, ":")
+		thread, _ := strconv.Atoi(topic)
+		return destination{chatID: chat, thread: thread}
 	}
 	if len(c.BeltRoutes) == 0 || canonicalBelt == "" {
-		return c.ChatID
+		return destination{chatID: c.ChatID}
 	}
 	if dest, ok := c.BeltRoutes[strings.ToLower(strings.TrimSpace(canonicalBelt))]; ok && dest != "" {
-		return dest
+		return destination{chatID: dest}
 	}
-	return c.ChatID
+	return destination{chatID: c.ChatID}
 }
 
-// doRequest handles the common logic for sending requests to Telegram API.
-//
-// Features:
-//   - JSON marshaling
-//   - HTTP POST with proper headers
-//   - Error response parsing
-//   - Long timeout for long polling (60s)
-//
-// Parameters:
-//   - method: Telegram API method name (e.g., "sendMessage")
-//   - payload: Request payload (will be JSON marshaled)
-//
-// Returns:
-//   - map[string]interface{}: Parsed response
-//   - error: Request or API error
-func (c *Client) doRequest(method string, payload interface{}) (map[string]interface{}, error) {
-	result, err := c.doRequestRaw(method, payload)
-	// Only count outbound message-sending methods toward send metrics; skip
-	// long-polling getUpdates and similar control-plane calls.
-	if isOutboundSendMethod(method) {
-		if err != nil {
-			metrics.TelegramSendFailuresTotal.Inc()
-		} else {
-			metrics.TelegramSendsTotal.Inc()
-		}
-	}
-	return result, err
-}
-
-func (c *Client) doRequestRaw(method string, payload interface{}) (map[string]interface{}, error) {
+// postJSON marshals payload and posts it to a Bot API method, returning
+// the raw response body for doRequest to decode.
+func (c *Client) postJSON(ctx context.Context, method string, payload interface{}) ([]byte, error) {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	// Rate limiting for Telegram API. The interval comes from
-	// effectiveRateInterval so a client built with TELEGRAM_RATE_INTERVAL_MS
-	// can pace differently while still defaulting to the safe fallback.
-	rate := c.effectiveRateInterval()
-	c.mu.Lock()
-	if elapsed := time.Since(c.lastReqTime); elapsed < rate {
-		time.Sleep(rate - elapsed)
-	}
-	c.lastReqTime = time.Now()
-	c.mu.Unlock()
+	return c.call(ctx, method, payloadChatID(jsonData), func(ctx context.Context, apiURL string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(jsonData))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req, err
+	})
+}
 
-	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/%s", c.BotToken, method)
+// call sends one Bot API request built by newReq. Every attempt is paced by
+// the fixed interval and the shared token buckets, and a 429 is waited out
+// (for everyone, via the limiter) and retried up to maxRateLimitRetries
+// times. The body is returned undecoded, ok or not, for decodeResponse.
+// newReq must build the request with ctx, so cancelling it aborts the call
+// in flight; a cancelled ctx also stops further attempts.
+func (c *Client) call(ctx context.Context, method, chatID string, newReq func(ctx context.Context, apiURL string) (*http.Request, error)) ([]byte, error) {
+	if !perChatLimited(method) {
+		chatID = ""
+	}
+	limiter := c.rateLimiter()
+	apiURL := fmt.Sprintf("%s/bot%s/%s", c.apiBaseURL(), c.BotToken, method)
+
+	for attempt := 0; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := limiter.wait(ctx, chatID); err != nil {
+			return nil, err
+		}
 
-	// Use the persistent httpClient (shared connection pool, not re-created per call)
-	resp, err := c.httpClient.Post(apiURL, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
+		// Rate limiting for Telegram API. The interval comes from
+		// effectiveRateInterval so a client built with TELEGRAM_RATE_INTERVAL_MS
+		// can pace differently while still defaulting to the safe fallback.
+		// The slot is reserved under c.mu and waited for outside it, so
+		// callers queue in order without holding the lock while asleep.
+		rate := c.effectiveRateInterval()
+		c.mu.Lock()
+		slot := time.Now()
+		if next := c.lastReqTime.Add(rate); next.After(slot) {
+			slot = next
+		}
+		c.lastReqTime = slot
+		c.mu.Unlock()
+		if err := sleepCtx(ctx, time.Until(slot)); err != nil {
+			return nil, err
+		}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		req, err := newReq(ctx, apiURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		// Use the persistent httpClient (shared connection pool, not re-created per call)
+		start := time.Now()
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			metrics.TelegramRequestErrorsTotal.Inc(method)
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		metrics.TelegramRequestSeconds.ObserveLabel(method, time.Since(start).Seconds())
+		if err != nil {
+			metrics.TelegramRequestErrorsTotal.Inc(method)
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		observeResponse(method, body)
+
+		wait := retryAfter(body)
+		if wait == 0 || attempt == maxRateLimitRetries {
+			return body, nil
+		}
+		log.Printf("⏳ Telegram rate limit on %s; retrying in %v (attempt %d/%d)", method, wait, attempt+1, maxRateLimitRetries)
+		limiter.pause(wait)
 	}
+}
 
-	var result map[string]interface{}
-	err = json.Unmarshal(body, &result)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+// observeResponse counts a failed or rate-limited Bot API response in the
+// per-method metrics.
+func observeResponse(method string, body []byte) {
+	var status struct {
+		OK        bool `json:"ok"`
+		ErrorCode int  `json:"error_code"`
+	}
+	switch {
+	case json.Unmarshal(body, &status) == nil && status.OK:
+	case status.ErrorCode == 429:
+		metrics.TelegramRateLimitedTotal.Inc(method)
+	default:
+		metrics.TelegramRequestErrorsTotal.Inc(method)
 	}
+}
 
-	// Check if API call succeeded
-	if ok, exists := result["ok"].(bool); !exists || !ok {
-		return nil, fmt.Errorf("Telegram API error: %v", result)
+// rateLimiter returns the client's shared limiter, creating it on first use
+// so Clients built as literals (tests) are limited too.
+func (c *Client) rateLimiter() *rateLimiter {
+	c.limiterOnce.Do(func() {
+		if c.limiter == nil {
+			c.limiter = newRateLimiter()
+		}
+	})
+	return c.limiter
+}
+
+// apiBaseURL is the Bot API root; apiBase overrides it.
+func (c *Client) apiBaseURL() string {
+	if c.apiBase != "" {
+		return c.apiBase
 	}
+	return "https://api.telegram.org"
+}
 
-	return result, nil
+// payloadChatID pulls chat_id out of an encoded request for per-chat
+// limiting. Telegram accepts it as a string or a number.
+func payloadChatID(jsonData []byte) string {
+	var p struct {
+		ChatID interface{} `json:"chat_id"`
+	}
+	if json.Unmarshal(jsonData, &p) != nil || p.ChatID == nil {
+		return ""
+	}
+	switch v := p.ChatID.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatInt(int64(v), 10)
+	}
+	return ""
 }
 
 // isOutboundSendMethod reports whether a Telegram API method represents an
@@ -379,6 +571,8 @@ func isOutboundSendMethod(method string) bool {
 //	[Complaint description]
 //	📍 Location, Area
 //
+// The layout comes from c.Templates (see package msgtmpl).
+//
 // Features:
 //   - HTML formatting for better readability
 //   - Inline keyboard with "Mark as Resolved" button
@@ -391,97 +585,66 @@ func isOutboundSendMethod(method string) bool {
 // Returns:
 //   - string: Telegram message ID
 //   - error: Send error
-func (c *Client) SendComplaintMessage(complaintJSON string, complaintNumber string, gujaratiText string) (string, error) {
+func (c *Client) SendComplaintMessage(complaintJSON string, complaintNumber string) (string, error) {
 	if c == nil {
 		log.Println("   ⚠️  Telegram not configured, skipping message send")
 		return "", nil
 	}
 
-	log.Println("   📨 Sending complaint to Telegram...")
-
 	// Parse JSON to extract fields
-	var complaint map[string]interface{}
-	err := json.Unmarshal([]byte(complaintJSON), &complaint)
-	if err != nil {
+	var complaint notify.Complaint
+	if err := json.Unmarshal([]byte(complaintJSON), &complaint); err != nil {
 		return "", fmt.Errorf("failed to parse complaint JSON: %w", err)
 	}
+	messageID, _, err := c.sendComplaint(complaint, complaintNumber)
+	return messageID, err
+}
 
-	// Helper function to safely extract values (handles null)
-	getValue := func(key string) string {
-		val := complaint[key]
-		if val == nil {
-			return ""
-		}
-		return fmt.Sprintf("%v", val)
-	}
-
-	// Format message with emojis and structure
-	message := fmt.Sprintf(
-		"📋 Complaint : %s\n\n"+
-			"%s Belt: %s\n"+
-			"👤 %s\n"+
-			"📞 %s\n"+
-			"🆔 Consumer: %s\n"+
-			"📅 %s\n\n"+
-			"💬 <b>Details:</b>\n%s\n"+
-			"📍 %s, %s",
-		getValue("complain_no"),
-		belt.StyleFor(getValue("belt")).Emoji,
-		belt.DisplayName(getValue("belt")),
-		getValue("complainant_name"),
-		getValue("mobile_no"),
-		getValue("consumer_no"),
-		getValue("complain_date"),
-		getValue("description"),
-		getValue("exact_location"),
-		getValue("area"),
-	)
-
-	// Append Gujarati translation if available
-	if gujaratiText != "" {
-		message += "\n\n" + strings.Repeat("─", 10) + "\n" +
-			gujaratiText
-	}
-
-	// Create inline keyboard with "Mark as Resolved" button
-	// Callback data format: "resolve:COMPLAINT_NUMBER"
-	keyboard := &InlineKeyboardMarkup{
-		InlineKeyboard: [][]InlineKeyboardButton{
-			{
-				{
-					Text:         "✅ Mark as Resolved",
-					CallbackData: fmt.Sprintf("resolve:%s", complaintNumber),
-				},
-			},
-		},
+// sendComplaint is SendComplaintMessage for a decoded complaint. It also
+// returns the message text sent, for the age footer (see RefreshAgeFooters).
+func (c *Client) sendComplaint(complaint notify.Complaint, complaintNumber string) (string, string, error) {
+	log.Println("   📨 Sending complaint to Telegram...")
+
+	if c.RedactPII {
+		complaint = complaint.Redacted()
+	}
+
+	message, err := c.Templates.Render(msgtmpl.Telegram, complaint)
+	if err != nil {
+		return "", "", err
 	}
 
+	// Inline keyboard: "Mark as Resolved" (callback "resolve:COMPLAINT_NUMBER")
+	// plus "👀 Seen" (callback "ack:COMPLAINT_NUMBER") when AckButton is set
+	keyboard := c.complaintKeyboard(complaintNumber, "")
+
+	dest := c.destinationFor(complaint.Belt, complaint.Category)
 	telegramMsg := Message{
-		ChatID:                c.ChatIDForBelt(getValue("belt")),
+		ChatID:                dest.chatID,
+		MessageThreadID:       dest.thread,
 		Text:                  message,
 		ParseMode:             "HTML",
 		DisableWebPagePreview: true,
 		ReplyMarkup:           keyboard,
 	}
+	// A re-opened complaint replies to its original (resolved) message so
+	// the chat can see the earlier thread.
+	if r := complaint.Reopened; r != nil {
+		if id, err := strconv.Atoi(r.TelegramMessageID); err == nil {
+			telegramMsg.ReplyToMessageID = id
+			telegramMsg.AllowSendingWithoutReply = true
+		}
+	}
 
-	result, err := c.doRequest("sendMessage", telegramMsg)
+	result, err := doRequest[SendMessageResult](context.Background(), c, "sendMessage", telegramMsg)
 	if err != nil {
-		return "", fmt.Errorf("failed to send Telegram message: %w", err)
+		return "", "", fmt.Errorf("failed to send Telegram message: %w", err)
 	}
 
-	messageID := extractMessageID(result)
+	messageID := strconv.Itoa(result.MessageID)
 
 	log.Println("   ✓ Complaint successfully sent to Telegram")
-	return messageID, nil
-}
-
-func extractMessageID(result map[string]interface{}) string {
-	if msgResult, ok := result["result"].(map[string]interface{}); ok {
-		if msgID, ok := msgResult["message_id"].(float64); ok {
-			return fmt.Sprintf("%.0f", msgID)
-		}
-	}
-	return ""
+	return messageID, message, nil
 }
 
 func defaultIfEmpty(value, fallback string) string {
@@ -519,18 +682,15 @@ func (c *Client) SendCriticalAlert(errorType, errorMsg string, retryCount int) e
 
 	log.Println("   🚨 Sending critical alert to Telegram...")
 
-	message := fmt.Sprintf(
-		"🚨 <b>CRITICAL ALERT - CMON SERVICE</b>\n\n"+
-			"<b>Error Type:</b> %s\n"+
-			"<b>Error Message:</b> %s\n"+
-			"<b>Retry Attempts:</b> %d\n"+
-			"<b>Timestamp:</b> %s\n\n"+
-			"⚠️ <b>Action Required:</b> Please check the service immediately.",
-		errorType,
-		errorMsg,
-		retryCount,
-		time.Now().Format("2006-01-02 15:04:05"),
-	)
+	message, err := renderHTML(criticalAlertTmpl, criticalAlertView{
+		ErrorType:  errorType,
+		ErrorMsg:   errorMsg,
+		RetryCount: retryCount,
+		Time:       time.Now().In(c.location()),
+	})
+	if err != nil {
+		return err
+	}
 
 	telegramMsg := Message{
 		ChatID:                c.ChatID,
@@ -539,7 +699,7 @@ func (c *Client) SendCriticalAlert(errorType, errorMsg string, retryCount int) e
 		DisableWebPagePreview: true,
 	}
 
-	_, err := c.doRequest("sendMessage", telegramMsg)
+	err = c.send("sendMessage", telegramMsg)
 	if err != nil {
 		return fmt.Errorf("failed to send Telegram alert: %w", err)
 	}
@@ -548,6 +708,214 @@ func (c *Client) SendCriticalAlert(errorType, errorMsg string, retryCount int) e
 	return nil
 }
 
+// SendRecoveryAlert tells the main chat that the failure behind an earlier
+// SendCriticalAlert has cleared.
+//
+// Parameters:
+//   - errorType: Type of error that recovered, as in the critical alert
+//   - detail: How long it lasted, etc.; may be empty
+func (c *Client) SendRecoveryAlert(errorType, detail string) error {
+	if c == nil {
+		return nil
+	}
+
+	message, err := renderHTML(recoveryAlertTmpl, criticalAlertView{
+		ErrorType: errorType,
+		ErrorMsg:  detail,
+		Time:      time.Now().In(c.location()),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = c.send("sendMessage", Message{
+		ChatID:                c.ChatID,
+		Text:                  message,
+		ParseMode:             "HTML",
+		DisableWebPagePreview: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send recovery alert: %w", err)
+	}
+	log.Println("   ✓ Recovery alert sent to Telegram")
+	return nil
+}
+
+// SendImportDigest posts the one message that stands in for the complaints
+// the initial import stored without a message each.
+func (c *Client) SendImportDigest(title, detail string) error {
+	if c == nil {
+		return nil
+	}
+	err := c.send("sendMessage", Message{
+		ChatID:    c.ChatID,
+		Text:      fmt.Sprintf("📥 <b>%s</b>\n\n%s", htmlEscape(title), htmlEscape(detail)),
+		ParseMode: "HTML",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send import digest: %w", err)
+	}
+	log.Println("   ✓ Import digest sent to Telegram")
+	return nil
+}
+
+// SendStartupNotice tells AdminChatID that cmon has started, with the
+// build it is running and the pending complaints it picked up again.
+// Does nothing without an admin chat.
+func (c *Client) SendStartupNotice(pending int) error {
+	if c == nil || c.AdminChatID == "" {
+		return nil
+	}
+	info := buildinfo.Get()
+	title := i18n.T("notice.started", htmlEscape(info.Version), pending)
+	if err := c.send("sendMessage", Message{ChatID: c.AdminChatID, Text: formatBuildInfo(title, info), ParseMode: "HTML"}); err != nil {
+		return fmt.Errorf("failed to send startup notice: %w", err)
+	}
+	log.Println("   ✓ Startup notice sent to Telegram")
+	return nil
+}
+
+// SendShutdownNotice tells AdminChatID that cmon is shutting down
+// gracefully and why, e.g. "signal: SIGTERM". Does nothing without an
+// admin chat.
+func (c *Client) SendShutdownNotice(reason string) error {
+	if c == nil || c.AdminChatID == "" {
+		return nil
+	}
+	text := i18n.T("notice.shutdown", htmlEscape(reason))
+	if err := c.send("sendMessage", Message{ChatID: c.AdminChatID, Text: text, ParseMode: "HTML"}); err != nil {
+		return fmt.Errorf("failed to send shutdown notice: %w", err)
+	}
+	log.Println("   ✓ Shutdown notice sent to Telegram")
+	return nil
+}
+
+// maxCrashStack caps the stack trace in a crash report; Telegram rejects
+// messages over 4096 characters.
+const maxCrashStack = 3000
+
+// SendCrashReport tells AdminChatID that component panicked with value and
+// has been recovered, with the start of the stack. Does nothing without an
+// admin chat.
+func (c *Client) SendCrashReport(component string, value any, stack []byte) error {
+	if c == nil || c.AdminChatID == "" {
+		return nil
+	}
+	trace := string(stack)
+	if len(trace) > maxCrashStack {
+		trace = strings.ToValidUTF8(trace[:maxCrashStack], "") + "\n…"
+	}
+	text := i18n.T("notice.crash", htmlEscape(component)) +
+		fmt.Sprintf("\n<code>%s</code>\n<pre>%s</pre>", htmlEscape(fmt.Sprint(value)), htmlEscape(trace))
+	if err := c.send("sendMessage", Message{ChatID: c.AdminChatID, Text: text, ParseMode: "HTML"}); err != nil {
+		return fmt.Errorf("failed to send crash report: %w", err)
+	}
+	return nil
+}
+
+// SendUpdateNotice tells AdminChatID that release latest (notes at url)
+// is newer than the running version current; installed says it has been
+// put in place and cmon is restarting into it. Does nothing without an
+// admin chat.
+func (c *Client) SendUpdateNotice(current, latest, url string, installed bool) error {
+	if c == nil || c.AdminChatID == "" {
+		return nil
+	}
+	text := i18n.T("notice.update", htmlEscape(latest), htmlEscape(current))
+	if installed {
+		text = i18n.T("notice.updating", htmlEscape(latest), htmlEscape(current))
+	}
+	if url != "" {
+		text += i18n.T("notice.release_notes", htmlEscape(url))
+	}
+	if err := c.send("sendMessage", Message{ChatID: c.AdminChatID, Text: text, ParseMode: "HTML", DisableWebPagePreview: true}); err != nil {
+		return fmt.Errorf("failed to send update notice: %w", err)
+	}
+	log.Println("   ✓ Update notice sent to Telegram")
+	return nil
+}
+
+// SendPortalStatusAlert notifies the main chat that the DGVCL portal went
+// down (error / maintenance page) or came back. Unlike SendCriticalAlert this
+// is informational: the outage is on the portal side and cmon will resume on
+// its own, so the message does not ask anyone to check the service.
+//
+// Parameters:
+//   - down: true when an outage starts, false when it ends
+//   - detail: Matched error-page signature or error text (ignored on recovery)
+func (c *Client) SendPortalStatusAlert(down bool, detail string) error {
+	if c == nil {
+		return nil
+	}
+
+	ts := time.Now().In(c.location()).Format("2006-01-02 15:04:05")
+	var message string
+	if down {
+		message = fmt.Sprintf(
+			"⚠️ <b>%s</b>\n\n"+
+				"<b>%s</b> %s\n"+
+				"<b>%s</b> %s\n\n"+
+				"%s",
+			i18n.T("alert.portal.down"),
+			i18n.T("alert.portal.detail"), htmlEscape(detail),
+			i18n.T("alert.portal.since"), ts,
+			i18n.T("alert.portal.down.body"),
+		)
+	} else {
+		message = fmt.Sprintf("✅ <b>%s</b>\n\n<b>%s</b> %s", i18n.T("alert.portal.up"), i18n.T("alert.portal.recovered"), ts)
+	}
+
+	err := c.send("sendMessage", Message{
+		ChatID:                c.ChatID,
+		Text:                  message,
+		ParseMode:             "HTML",
+		DisableWebPagePreview: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send portal status alert: %w", err)
+	}
+	return nil
+}
+
+// SendOutageAlert posts one aggregated "possible outage" message for a
+// cluster of complaints from the same area, routed like a complaint from
+// that belt.
+func (c *Client) SendOutageAlert(cluster outage.Cluster) error {
+	if c == nil {
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<b>%s</b>\n", htmlEscape(cluster.Title()))
+	if cluster.Belt != "" {
+		fmt.Fprintf(&b, "%s\n", i18n.T("alert.outage.belt", belt.StyleFor(cluster.Belt).Emoji, htmlEscape(belt.DisplayName(cluster.Belt))))
+	}
+	if cluster.Feeder != "" {
+		fmt.Fprintf(&b, "%s\n", i18n.T("alert.outage.feeder", htmlEscape(cluster.Feeder), htmlEscape(cluster.Spread())))
+	}
+	fmt.Fprintf(&b, "%s\n", i18n.T("alert.outage.window", cluster.Window))
+	for _, m := range cluster.Members {
+		fmt.Fprintf(&b, "\n• <b>%s</b>", htmlEscape(m.ComplaintID))
+		if m.ConsumerName != "" {
+			fmt.Fprintf(&b, " — %s", htmlEscape(m.ConsumerName))
+		}
+		if m.Description != "" {
+			fmt.Fprintf(&b, ": %s", htmlEscape(truncateRunes(m.Description, 80)))
+		}
+	}
+
+	err := c.send("sendMessage", Message{
+		ChatID:                c.ChatIDForBelt(cluster.Belt),
+		Text:                  b.String(),
+		ParseMode:             "HTML",
+		DisableWebPagePreview: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send outage alert: %w", err)
+	}
+	return nil
+}
+
 // EditMessageText edits an existing Telegram message.
 //
 // Use cases:
@@ -581,7 +949,7 @@ func (c *Client) EditMessageText(chatID, messageID, newText string) error {
 		ParseMode: "HTML",
 	}
 
-	_, err := c.doRequest("editMessageText", req)
+	err := c.edit("editMessageText", req)
 	if err != nil {
 		return fmt.Errorf("failed to edit Telegram message: %w", err)
 	}
@@ -590,6 +958,49 @@ func (c *Client) EditMessageText(chatID, messageID, newText string) error {
 	return nil
 }
 
+// PinChatMessage pins messageID in chatID without notifying members. The
+// bot needs the "pin messages" admin right in groups.
+func (c *Client) PinChatMessage(chatID, messageID string) error {
+	if c == nil {
+		return nil
+	}
+
+	payload := map[string]interface{}{
+		"chat_id":              chatID,
+		"message_id":           messageID,
+		"disable_notification": true,
+	}
+	if err := c.send("pinChatMessage", payload); err != nil {
+		return fmt.Errorf("failed to pin Telegram message: %w", err)
+	}
+	return nil
+}
+
+// UnpinChatMessage unpins messageID in chatID, leaving other pins alone.
+func (c *Client) UnpinChatMessage(chatID, messageID string) error {
+	if c == nil {
+		return nil
+	}
+
+	payload := map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": messageID,
+	}
+	if err := c.send("unpinChatMessage", payload); err != nil {
+		return fmt.Errorf("failed to unpin Telegram message: %w", err)
+	}
+	return nil
+}
+
+// Ping checks that the Bot API is reachable and the token is accepted
+// (getMe). Used by the health checks.
+func (c *Client) Ping() error {
+	if c == nil {
+		return nil
+	}
+	return c.send("getMe", struct{}{})
+}
+
 // SendPhoto sends a photo (PNG bytes) to a Telegram chat.
 //
 // Uses multipart/form-data as required by Telegram's sendPhoto API.
@@ -601,12 +1012,36 @@ func (c *Client) EditMessageText(chatID, messageID, newText string) error {
 //
 // Returns:
 //   - error: Upload or API error
-func (c *Client) SendPhoto(chatID string, photoBytes []byte, caption string) (err error) {
+func (c *Client) SendPhoto(chatID string, photoBytes []byte, caption string) error {
 	if c == nil {
 		log.Println("   ⚠️  Telegram not configured, skipping photo send")
 		return nil
 	}
 
+	log.Println("   📸 Sending photo to Telegram...")
+	if _, err := c.uploadPhoto(chatID, photoBytes, caption, nil); err != nil {
+		return err
+	}
+	log.Println("   ✓ Photo successfully sent to Telegram")
+	return nil
+}
+
+// maxPhotoBytes is the largest file Telegram accepts through sendPhoto.
+const maxPhotoBytes = 10 << 20
+
+// SendImage sends a rendered PNG as a photo, or as a document named
+// filename when it is over Telegram's photo size limit.
+func (c *Client) SendImage(chatID string, png []byte, caption, filename string) error {
+	if c == nil || len(png) <= maxPhotoBytes {
+		return c.SendPhoto(chatID, png, caption)
+	}
+	log.Printf("   📎 Image is %d bytes, over the photo limit; sending as a document", len(png))
+	return c.SendDocument(chatID, filename, png, caption)
+}
+
+// uploadPhoto posts a sendPhoto multipart request and returns the sent
+// message. replyMarkup, when non-nil, is JSON-encoded into the form.
+func (c *Client) uploadPhoto(chatID string, photoBytes []byte, caption string, replyMarkup interface{}) (result SendMessageResult, err error) {
 	defer func() {
 		if err != nil {
 			metrics.TelegramSendFailuresTotal.Inc()
@@ -615,8 +1050,6 @@ func (c *Client) SendPhoto(chatID string, photoBytes []byte, caption string) (er
 		}
 	}()
 
-	log.Println("   📸 Sending photo to Telegram...")
-
 	// Build multipart form
 	var body bytes.Buffer
 	writer := multipart.NewWriter(&body)
@@ -628,44 +1061,73 @@ func (c *Client) SendPhoto(chatID string, photoBytes []byte, caption string) (er
 	if caption != "" {
 		writer.WriteField("caption", caption)
 	}
+	if replyMarkup != nil {
+		markup, err := json.Marshal(replyMarkup)
+		if err != nil {
+			return result, fmt.Errorf("failed to encode reply markup: %w", err)
+		}
+		writer.WriteField("reply_markup", string(markup))
+	}
 
 	// Add photo file
 	part, err := writer.CreateFormFile("photo", "summary.png")
 	if err != nil {
-		return fmt.Errorf("failed to create form file: %w", err)
+		return result, fmt.Errorf("failed to create form file: %w", err)
 	}
 	part.Write(photoBytes)
 	writer.Close()
 
-	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/sendPhoto", c.BotToken)
-
-	req, err := http.NewRequest("POST", apiURL, &body)
+	form := body.Bytes()
+	respBody, err := c.call(context.Background(), "sendPhoto", chatID, func(ctx context.Context, apiURL string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(form))
+		if err == nil {
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+		}
+		return req, err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return result, fmt.Errorf("failed to send photo: %w", err)
 	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return decodeResponse[SendMessageResult]("sendPhoto", respBody)
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send photo: %w", err)
-	}
-	defer resp.Body.Close()
+// SendDocument uploads data to chatID as a file named filename.
+func (c *Client) SendDocument(chatID, filename string, data []byte, caption string) (err error) {
+	defer func() {
+		if err != nil {
+			metrics.TelegramSendFailuresTotal.Inc()
+		} else {
+			metrics.TelegramSendsTotal.Inc()
+		}
+	}()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read sendPhoto response body: %w", err)
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("chat_id", chatID)
+	if caption != "" {
+		writer.WriteField("caption", caption)
+		writer.WriteField("parse_mode", "HTML")
 	}
-	var result map[string]interface{}
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return fmt.Errorf("failed to unmarshal sendPhoto response (status %d, body %q): %w", resp.StatusCode, string(respBody), err)
+	part, err := writer.CreateFormFile("document", filename)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
 	}
+	part.Write(data)
+	writer.Close()
 
-	if ok, exists := result["ok"].(bool); !exists || !ok {
-		return fmt.Errorf("Telegram sendPhoto error: %v", result)
+	form := body.Bytes()
+	respBody, err := c.call(context.Background(), "sendDocument", chatID, func(ctx context.Context, apiURL string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(form))
+		if err == nil {
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+		}
+		return req, err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send document: %w", err)
 	}
-
-	log.Println("   ✓ Photo successfully sent to Telegram")
-	return nil
+	_, err = decodeResponse[SendMessageResult]("sendDocument", respBody)
+	return err
 }
 
 // getUpdates fetches new updates from Telegram using long polling.
@@ -681,7 +1143,7 @@ func (c *Client) SendPhoto(chatID string, photoBytes []byte, caption string) (er
 // Returns:
 //   - []Update: List of updates
 //   - error: Request error
-func (c *Client) getUpdates(offset int) ([]Update, error) {
+func (c *Client) getUpdates(ctx context.Context, offset int) ([]Update, error) {
 	if c == nil {
 		return nil, nil
 	}
@@ -690,24 +1152,13 @@ func (c *Client) getUpdates(offset int) ([]Update, error) {
 		"offset":  offset,
 		"timeout": longPollSeconds,
 	}
-
-	result, err := c.doRequest("getUpdates", payload)
-	if err != nil {
-		return nil, err
+	if c.AckButton {
+		// Reactions are opt-in on Telegram's side; listing them replaces
+		// the default set, so message and callback_query must be repeated.
+		payload["allowed_updates"] = []string{"message", "callback_query", "message_reaction"}
 	}
 
-	var updates []Update
-	if resultArray, ok := result["result"].([]interface{}); ok {
-		for _, item := range resultArray {
-			jsonData, _ := json.Marshal(item)
-			var update Update
-			if err := json.Unmarshal(jsonData, &update); err == nil {
-				updates = append(updates, update)
-			}
-		}
-	}
-
-	return updates, nil
+	return doRequest[[]Update](ctx, c, "getUpdates", payload)
 }
 
 // answerCallbackQuery sends a response to a callback query.
@@ -731,8 +1182,7 @@ func (c *Client) answerCallbackQuery(callbackQueryID string, text string) error
 		"show_alert":        false,
 	}
 
-	_, err := c.doRequest("answerCallbackQuery", payload)
-	return err
+	return c.send("answerCallbackQuery", payload)
 }
 
 // HandleUpdates listens for incoming updates and processes them.
@@ -766,42 +1216,80 @@ func (c *Client) HandleUpdates(ctx context.Context, sc *session.Client, stor *st
 			log.Println("🛑 Telegram callback handler stopped")
 			return
 		default:
-			updates, err := c.getUpdates(offset)
+			updates, err := c.getUpdates(ctx, offset)
 			if err != nil {
+				if ctx.Err() != nil {
+					continue // cancelled mid-poll; the next pass stops
+				}
 				log.Printf("⚠️  Error getting Telegram updates: %v\n", err)
-				time.Sleep(5 * time.Second)
+				select {
+				case <-ctx.Done():
+				case <-time.After(5 * time.Second):
+				}
 				continue
 			}
 
 			for _, update := range updates {
-				if update.CallbackQuery != nil {
-					c.handleCallbackQuery(ctx, update.CallbackQuery, stor)
-				} else if update.Message != nil {
-					c.handleMessage(ctx, sc, update.Message, stor)
-				}
+				c.handleUpdate(ctx, sc, update, stor)
 				offset = update.UpdateID + 1
 			}
+			c.expireConversations(stor, time.Now())
 		}
 	}
 }
 
+// handleUpdate dispatches one update. A panic while handling it is
+// reported and the update skipped, so one bad update can neither stop the
+// handler nor be redelivered forever.
+func (c *Client) handleUpdate(ctx context.Context, sc *session.Client, update Update, stor *storage.Storage) {
+	defer crash.Recover("Telegram update handler")
+	if update.CallbackQuery != nil {
+		c.handleCallbackQuery(ctx, sc, update.CallbackQuery, stor)
+	} else if update.MessageReaction != nil {
+		c.handleReaction(update.MessageReaction, stor)
+	} else if update.Message != nil {
+		c.handleMessage(ctx, sc, update.Message, stor)
+	}
+}
+
 // handleCallbackQuery processes a callback query from an inline button.
 //
 // Flow when user clicks "Mark as Resolved":
 //  1. Parse callback data to get complaint number
-//  2. Store pending resolution with complaint details
-//  3. Send prompt message asking for resolution note
-//  4. Wait for user's text message reply
+//  2. Start the resolve conversation (see resolveFlow), which sends a
+//     prompt asking for the resolution note
+//  3. Wait for the user's answer to the prompt
 //
 // Parameters:
 //   - ctx: Context for cancellation
+//   - sc: Portal session for the "🔍 Details" lookup
 //   - query: Callback query to process
 //   - stor: Storage for complaint data
-func (c *Client) handleCallbackQuery(ctx context.Context, query *CallbackQuery, stor *storage.Storage) {
+func (c *Client) handleCallbackQuery(ctx context.Context, sc *session.Client, query *CallbackQuery, stor *storage.Storage) {
 	log.Printf("📞 Received callback query: %s from %s\n", query.Data, query.From.FirstName)
 
-	// Parse callback data (format: "resolve:COMPLAINT_NUMBER")
+	// Parse callback data (format: "resolve:", "ack:", "details:",
+	// "undo:" + COMPLAINT_NUMBER, or a conversation flow's prefix such as
+	// "remark:")
 	parts := strings.SplitN(query.Data, ":", 2)
+	for _, flow := range flows {
+		if len(parts) == 2 && parts[0] == flow.CallbackPrefix {
+			c.handleConversationCallback(ctx, sc, query, flow, parts[1], stor)
+			return
+		}
+	}
+	if len(parts) == 2 && parts[0] == "ack" {
+		c.handleAckCallback(query, parts[1], stor)
+		return
+	}
+	if len(parts) == 2 && parts[0] == "undo" {
+		c.handleUndoCallback(sc, query, parts[1], stor)
+		return
+	}
+	if len(parts) == 2 && parts[0] == "details" {
+		c.handleDetailsCallback(sc, query, parts[1], stor)
+		return
+	}
 	if len(parts) != 2 || parts[0] != "resolve" {
 		log.Println("⚠️  Invalid callback data format")
 		c.answerCallbackQuery(query.ID, "Invalid action")
@@ -810,6 +1298,21 @@ func (c *Client) handleCallbackQuery(ctx context.Context, query *CallbackQuery,
 
 	complaintNumber := parts[1]
 
+	// Starting a resolution counts as having seen the complaint
+	claimed, err := stor.MarkAcknowledged(complaintNumber, userDisplayName(query.From), query.From.ID)
+	if err != nil {
+		log.Printf("⚠️  Failed to record acknowledgment for %s: %v\n", complaintNumber, err)
+	}
+	if c.AckClaims {
+		if by, byID, ok := stor.GetAcknowledgment(complaintNumber); ok && !c.mayResolve(query.From, by, byID) {
+			c.answerCallbackQuery(query.ID, i18n.T("ack.claimed", by))
+			return
+		}
+		if claimed && query.Message != nil && query.Message.Chat != nil {
+			c.showAcknowledgment(fmt.Sprintf("%d", query.Message.Chat.ID), query.Message.MessageID, complaintNumber, userDisplayName(query.From), stor)
+		}
+	}
+
 	// Get message ID for this complaint
 	messageID := stor.GetMessageID(complaintNumber)
 	if messageID == "" && query.Message != nil {
@@ -827,122 +1330,32 @@ func (c *Client) handleCallbackQuery(ctx context.Context, query *CallbackQuery,
 		originalText = query.Message.Text
 	}
 
-	// Check if resolution is already pending for this user and complaint (Toggle logic)
-	// We use the storage for DB-backed state
-	pending, exists := stor.GetPendingResolution(query.From.ID)
-	if exists && pending.ComplaintNumber == complaintNumber {
-		// User clicked button again -> CANCEL action
-		stor.RemovePendingResolution(query.From.ID)
-
-		// Delete the previous prompt message
-		if pending.PromptMessageID > 0 {
-			deleteReq := struct {
-				ChatID    string `json:"chat_id"`
-				MessageID int    `json:"message_id"`
-			}{
-				ChatID:    c.ChatID,
-				MessageID: pending.PromptMessageID,
-			}
-			c.doRequest("deleteMessage", deleteReq)
-		}
-
-		c.answerCallbackQuery(query.ID, "Resolution cancelled")
+	// Pressing the button again while being asked for remarks cancels
+	if conv, exists := c.activeConversation(stor, query.From.ID); exists && conv.Flow == resolveFlow.Name && conv.ComplaintNumber == complaintNumber {
+		c.endConversation(stor, query.From.ID, conv)
+		c.answerCallbackQuery(query.ID, i18n.T("resolve.cancelled"))
 		log.Printf("❌ Resolution cancelled by toggle for user %s\n", query.From.FirstName)
 		return
 	}
 
-	if exists {
-		stor.RemovePendingResolution(query.From.ID)
-		if pending.PromptMessageID > 0 {
-			deleteReq := struct {
-				ChatID    string `json:"chat_id"`
-				MessageID int    `json:"message_id"`
-			}{
-				ChatID:    c.ChatID,
-				MessageID: pending.PromptMessageID,
-			}
-			c.doRequest("deleteMessage", deleteReq)
-		}
-	}
-
-	log.Printf("📝 Requesting resolution note for complaint %s from %s\n", complaintNumber, query.From.FirstName)
-
-	// Extract consumer name from original text
-	consumerName := "Unknown"
-	if idx := strings.Index(originalText, "👤 "); idx != -1 {
-		nameStart := idx + len("👤 ")
-		if newlineIdx := strings.Index(originalText[nameStart:], "\n"); newlineIdx != -1 {
-			consumerName = originalText[nameStart : nameStart+newlineIdx]
-		}
-	}
-
-	// Build mention text: use @username if available, otherwise use HTML mention with user ID
-	// This is needed for Selective ForceReply to target only this user in a group
-	mentionText := ""
-	if query.From.Username != "" {
-		mentionText = "@" + query.From.Username
-	} else {
-		mentionText = fmt.Sprintf("<a href=\"tg://user?id=%d\">%s</a>", query.From.ID, query.From.FirstName)
-	}
-
-	// Send prompt message asking for resolution note
-	// Selective: true + @mention ensures only the button-clicker sees the force-reply prompt
-	promptMsg := Message{
-		ChatID:    c.ChatID,
-		Text:      fmt.Sprintf("📝 %s, enter remarks for complaint <b>%s</b>\n👤 %s:", mentionText, complaintNumber, consumerName),
-		ParseMode: "HTML",
-		ReplyMarkup: &ForceReply{
-			ForceReply:            true,
-			Selective:             true,
-			InputFieldPlaceholder: "Enter resolution details...",
+	c.startResolve(&Turn{
+		Ctx:      ctx,
+		Session:  sc,
+		Store:    stor,
+		From:     query.From,
+		Callback: query,
+		Conv: &storage.Conversation{
+			ComplaintNumber: complaintNumber,
+			MessageID:       messageID,
+			OriginalText:    originalText,
 		},
-	}
-
-	result, err := c.doRequest("sendMessage", promptMsg)
-	if err != nil {
-		log.Printf("⚠️  Failed to send prompt message: %v\n", err)
-		c.answerCallbackQuery(query.ID, "Error sending prompt")
-		return
-	}
-
-	// Extract prompt message ID for later deletion
-	var promptMsgID int
-	if msgResult, ok := result["result"].(map[string]interface{}); ok {
-		if msgID, ok := msgResult["message_id"].(float64); ok {
-			promptMsgID = int(msgID)
-		}
-	}
-
-	pr := storage.PendingResolution{
-		ComplaintNumber: complaintNumber,
-		MessageID:       messageID,
-		OriginalText:    originalText,
-		PromptMessageID: promptMsgID,
-	}
-	if err := stor.AddPendingResolution(query.From.ID, pr); err != nil {
-		if promptMsgID > 0 {
-			deleteReq := struct {
-				ChatID    string `json:"chat_id"`
-				MessageID int    `json:"message_id"`
-			}{
-				ChatID:    c.ChatID,
-				MessageID: promptMsgID,
-			}
-			c.doRequest("deleteMessage", deleteReq)
-		}
-		c.answerCallbackQuery(query.ID, "Error saving pending resolution")
-		log.Printf("⚠️  Failed to persist pending resolution for %s: %v\n", query.From.FirstName, err)
-		return
-	}
-
-	c.answerCallbackQuery(query.ID, "Please send your remarks")
-	log.Printf("✓ Prompted %s for remarks\n", query.From.FirstName)
+	})
 }
 
 // handleMessage processes regular text messages (for resolution notes).
 //
 // Flow when user sends resolution note:
-//  1. Check if user has pending resolution
+//  1. Check it answers the user's conversation prompt
 //  2. Delete prompt message (keep chat clean)
 //  3. Call API to mark complaint as resolved on website
 //  4. Edit original Telegram message to show "RESOLVED"
@@ -954,7 +1367,28 @@ func (c *Client) handleCallbackQuery(ctx context.Context, query *CallbackQuery,
 //   - message: Incoming message
 //   - stor: Storage for complaint data
 func (c *Client) handleMessage(ctx context.Context, sc *session.Client, message *IncomingMessage, stor *storage.Storage) {
-	if message.From == nil || message.Text == "" {
+	if message.From == nil {
+		return
+	}
+	if message.Voice != nil || len(message.Photo) > 0 {
+		c.handleConversationReply(ctx, sc, message, stor)
+		return
+	}
+	if message.Text == "" {
+		return
+	}
+
+	if c.deliverCaptchaAnswer(message) {
+		return
+	}
+
+	if strings.TrimSpace(message.Text) == "/backup" {
+		c.handleBackupCommand(ctx, message, stor)
+		return
+	}
+
+	if isAdminCommand(message.Text) {
+		c.handleAdminCommand(message)
 		return
 	}
 
@@ -963,159 +1397,181 @@ func (c *Client) handleMessage(ctx context.Context, sc *session.Client, message
 		return
 	}
 
-	// Handle /summarybelt command (per-belt images)
-	if strings.TrimSpace(message.Text) == "/summarybelt" {
-		c.handleSummaryBeltCommand(ctx, sc, stor)
+	if isResolveAllCommand(message.Text) {
+		c.handleResolveAllCommand(&Turn{Ctx: ctx, Session: sc, Store: stor, From: *message.From, Message: message})
 		return
 	}
 
-	// Handle /summary command
-	if strings.TrimSpace(message.Text) == "/summary" {
-		c.handleSummaryCommand(ctx, sc, stor)
+	if isConsumerCommand(message.Text) {
+		c.handleConsumerCommand(message, stor)
 		return
 	}
 
-	// Only process text messages from users with pending resolutions
-	pending, exists := stor.GetPendingResolution(message.From.ID)
-	if !exists {
-		return // No pending resolution for this user
+	if isChartCommand(message.Text) {
+		c.handleChartCommand(message, stor)
+		return
 	}
 
-	// Verify this is a reply to the bot's prompt message (not a random message)
-	// If ReplyToMessage is nil (user typed without replying) or points to a different message, ignore it
-	if message.ReplyToMessage == nil || message.ReplyToMessage.MessageID != pending.PromptMessageID {
+	if isSLAReportCommand(message.Text) {
+		c.handleSLAReportCommand(message)
 		return
 	}
 
-	promptMsgID := pending.PromptMessageID
-	stor.RemovePendingResolution(message.From.ID)
+	if strings.TrimSpace(message.Text) == "/status" {
+		c.handleStatusCommand()
+		return
+	}
 
-	// Delete prompt message to keep chat clean
-	if promptMsgID > 0 {
-		deleteReq := struct {
-			ChatID    string `json:"chat_id"`
-			MessageID int    `json:"message_id"`
-		}{
-			ChatID:    c.ChatID,
-			MessageID: promptMsgID,
-		}
-		c.doRequest("deleteMessage", deleteReq)
+	if strings.TrimSpace(message.Text) == "/version" {
+		c.sendTextMessage(formatBuildInfo("ℹ️ <b>CMON version</b>", buildinfo.Get()), "HTML")
+		return
 	}
 
-	// Check for "cancel" keyword (Case-insensitive)
-	if strings.EqualFold(strings.TrimSpace(message.Text), "cancel") {
-		log.Printf("❌ Resolution cancelled by keyword for user %s\n", message.From.FirstName)
-		msg := Message{
-			ChatID:    c.ChatID,
-			Text:      "❌ Resolution cancelled.",
-			ParseMode: "HTML",
-		}
-		c.doRequest("sendMessage", msg)
+	if strings.TrimSpace(message.Text) == "/failed" {
+		c.handleFailedCommand(stor)
 		return
 	}
 
-	log.Printf("📝 Received resolution note from %s for complaint %s\n", message.From.FirstName, pending.ComplaintNumber)
+	// Handle /summary and /summarybelt (per-belt images), optionally
+	// followed by a filter such as "area=Valod age>24h"
+	if cmd, expr, ok := summaryCommand(message.Text); ok {
+		c.handleSummaryRequest(ctx, sc, stor, cmd, expr)
+		return
+	}
+
+	// Otherwise only replies count: to a conversation's prompt, or to a
+	// complaint message as a note
+	if c.handleConversationReply(ctx, sc, message, stor) {
+		return
+	}
+	c.handleNoteReply(message, stor)
+}
+
+// resolveWithNote marks conv's complaint resolved on the portal with
+// note as the remarks, then edits its message and drops it from storage.
+// Failures are reported in the chat.
+func (c *Client) resolveWithNote(sc *session.Client, stor *storage.Storage, conv storage.Conversation, note string, by User) {
 
 	// Check if complaint still exists
-	if !stor.Exists(pending.ComplaintNumber) {
-		log.Printf("⚠️  Complaint %s was already resolved\n", pending.ComplaintNumber)
+	if !stor.Exists(conv.ComplaintNumber) {
+		log.Printf("⚠️  Complaint %s was already resolved\n", conv.ComplaintNumber)
 		errorMsg := Message{
 			ChatID:    c.ChatID,
-			Text:      fmt.Sprintf("ℹ️ Complaint <b>%s</b> was already resolved.", pending.ComplaintNumber),
+			Text:      i18n.T("resolve.already", htmlEscape(conv.ComplaintNumber)),
 			ParseMode: "HTML",
 		}
-		c.doRequest("sendMessage", errorMsg)
+		c.send("sendMessage", errorMsg)
 		return
 	}
 
 	// Get API ID for resolution call
-	apiID := stor.GetAPIID(pending.ComplaintNumber)
+	apiID := stor.GetAPIID(conv.ComplaintNumber)
 	if apiID == "" {
-		log.Printf("⚠️  No API ID found for complaint %s\n", pending.ComplaintNumber)
+		log.Printf("⚠️  No API ID found for complaint %s\n", conv.ComplaintNumber)
 		errorMsg := Message{
 			ChatID:    c.ChatID,
-			Text:      fmt.Sprintf("❌ Error: Cannot resolve complaint %s (API ID not found).", pending.ComplaintNumber),
+			Text:      i18n.T("resolve.no_api_id", htmlEscape(conv.ComplaintNumber)),
 			ParseMode: "HTML",
 		}
-		c.doRequest("sendMessage", errorMsg)
+		c.send("sendMessage", errorMsg)
 		return
 	}
 
 	// Call API to mark complaint as resolved
-	log.Printf("🌐 Calling DGVCL API to mark complaint %s as resolved...\n", pending.ComplaintNumber)
+	log.Printf("🌐 Calling DGVCL API to mark complaint %s as resolved...\n", conv.ComplaintNumber)
 
-	err := api.ResolveComplaint(sc, apiID, message.Text, c.DebugMode)
+	err := api.ResolveComplaint(sc, apiID, note, c.DebugMode)
 	if err != nil {
 		log.Printf("⚠️  Failed to mark complaint on website: %v\n", err)
 		errorMsg := Message{
 			ChatID:    c.ChatID,
-			Text:      fmt.Sprintf("❌ Failed to mark complaint %s as resolved on website: %v\nPlease try again or contact support.", pending.ComplaintNumber, err),
+			Text:      i18n.T("resolve.failed", htmlEscape(conv.ComplaintNumber), htmlEscape(err.Error())),
 			ParseMode: "HTML",
 		}
-		c.doRequest("sendMessage", errorMsg)
+		c.send("sendMessage", errorMsg)
 		return
 	}
 
-	log.Printf("✅ Successfully marked complaint %s as resolved on website\n", pending.ComplaintNumber)
+	log.Printf("✅ Successfully marked complaint %s as resolved on website\n", conv.ComplaintNumber)
 
-	// Extract consumer name from original text
-	consumerName := "Unknown"
-	if idx := strings.Index(pending.OriginalText, "👤 "); idx != -1 {
-		nameStart := idx + len("👤 ")
-		if newlineIdx := strings.Index(pending.OriginalText[nameStart:], "\n"); newlineIdx != -1 {
-			consumerName = pending.OriginalText[nameStart : nameStart+newlineIdx]
-		}
-	}
+	// A digest member shares its message with the rest of the digest, so
+	// the digest is redrawn without it rather than replaced.
+	digest := inDigest(stor, conv.ComplaintNumber)
+	digestChat := c.ChatIDFor(stor.GetBelt(conv.ComplaintNumber), stor.GetCategory(conv.ComplaintNumber))
+
+	// Keep what an undo restores before the complaint leaves storage
+	undo := c.snapshotForUndo(stor, conv, by)
 
 	// Create resolved message
-	resolvedMessage := fmt.Sprintf(
-		"✅ <b>RESOLVED</b>\n\n"+
-			"Complaint #%s\n"+
-			"👤 %s\n"+
-			"🕐 %s",
-		pending.ComplaintNumber,
-		consumerName,
-		time.Now().Format("02 Jan 2006, 03:04 PM"),
-	)
-
-	var editErr error
-	if pending.MessageID == "" {
+	resolvedMessage, editErr := resolvedText(notify.Status{
+		ComplaintID:  conv.ComplaintNumber,
+		ConsumerName: consumerNameFromText(conv.OriginalText),
+		Time:         time.Now().In(c.location()),
+	})
+	if editErr != nil {
+		log.Printf("⚠️  %v\n", editErr)
+	} else if conv.MessageID == "" {
 		editErr = fmt.Errorf("telegram message ID missing")
+	} else if digest {
+		editErr = c.AsNotifier(stor).redrawDigest(digestChat, conv.MessageID, conv.ComplaintNumber)
+		if editErr != nil {
+			log.Printf("⚠️  Failed to redraw digest: %v\n", editErr)
+		}
 	} else {
 		req := EditMessageRequest{
 			ChatID:      c.ChatID,
-			MessageID:   pending.MessageID,
+			MessageID:   conv.MessageID,
 			Text:        resolvedMessage,
 			ParseMode:   "HTML",
 			ReplyMarkup: &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{}},
 		}
+		if undo != nil {
+			req.ReplyMarkup = c.undoKeyboard(conv.ComplaintNumber)
+		}
 
-		_, editErr = c.doRequest("editMessageText", req)
+		editErr = c.edit("editMessageText", req)
 		if editErr != nil {
 			log.Printf("⚠️  Failed to edit message: %v\n", editErr)
 		}
 	}
 
+	if c.Resolved != nil {
+		if err := c.Resolved(notify.Status{
+			ComplaintID:  conv.ComplaintNumber,
+			ConsumerName: defaultIfEmpty(stor.GetConsumerName(conv.ComplaintNumber), "Unknown"),
+			Belt:         stor.GetBelt(conv.ComplaintNumber),
+			Category:     stor.GetCategory(conv.ComplaintNumber),
+			Time:         time.Now(),
+			Remark:       note,
+			Channel:      "telegram",
+		}); err != nil {
+			log.Printf("⚠️  Failed to publish the resolution of %s: %v\n", conv.ComplaintNumber, err)
+		}
+	}
+
 	// Remove from storage even if Telegram editing failed. The website resolve
 	// already succeeded, so local state should not continue to advertise it as pending.
-	removed, err := stor.RemoveIfExists(pending.ComplaintNumber)
+	removed, err := stor.RemoveIfExists(conv.ComplaintNumber)
 	if err != nil {
 		log.Printf("⚠️  Failed to remove from storage: %v\n", err)
 	} else if !removed {
-		log.Printf("ℹ️  Complaint %s was already removed from storage\n", pending.ComplaintNumber)
+		log.Printf("ℹ️  Complaint %s was already removed from storage\n", conv.ComplaintNumber)
 	}
 
 	if editErr != nil {
 		errorMsg := Message{
 			ChatID:    c.ChatID,
-			Text:      fmt.Sprintf("❌ Complaint %s was marked as resolved on the website, but I could not update the original Telegram message.", pending.ComplaintNumber),
+			Text:      i18n.T("resolve.edit_failed", htmlEscape(conv.ComplaintNumber)),
 			ParseMode: "HTML",
 		}
-		c.doRequest("sendMessage", errorMsg)
+		c.send("sendMessage", errorMsg)
 		return
 	}
 
-	log.Printf("✓ Successfully resolved complaint %s with note\n", pending.ComplaintNumber)
+	if undo != nil {
+		c.armUndo(undo)
+	}
+	log.Printf("✓ Successfully resolved complaint %s with note\n", conv.ComplaintNumber)
 }
 
 // Per-command handlers (handleSummaryCommand, handleSummaryBeltCommand,
@@ -1,8 +1,32 @@
 package telegram
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"cmon/internal/api"
+	"cmon/internal/buildinfo"
+	"cmon/internal/crash"
+	"cmon/internal/flags"
+	"cmon/internal/health"
+	"cmon/internal/i18n"
+	"cmon/internal/logging"
+	"cmon/internal/metrics"
+	"cmon/internal/notify"
+	"cmon/internal/storage"
 )
 
 func TestParseRateInterval(t *testing.T) {
@@ -88,6 +112,34 @@ func TestChatIDForBeltOnNilClientReturnsEmpty(t *testing.T) {
 	}
 }
 
+func TestDestinationForCategory(t *testing.T) {
+	c := &Client{
+		ChatID:         "default-chat",
+		BeltRoutes:     map[string]string{"dahod": "-1001234"},
+		CategoryRoutes: map[string]string{"billing": "-1009999", "transformer": "-1008888:42"},
+	}
+
+	cases := []struct {
+		name, belt, category string
+		want                 destination
+	}{
+		{"category route wins over belt route", "dahod", "billing", destination{chatID: "-1009999"}},
+		{"category route with a forum topic", "songadh", "transformer", destination{chatID: "-1008888", thread: 42}},
+		{"unrouted category falls back to belt", "dahod", "meter", destination{chatID: "-1001234"}},
+		{"no category falls back to default", "songadh", "", destination{chatID: "default-chat"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := c.destinationFor(tc.belt, tc.category); got != tc.want {
+				t.Errorf("destinationFor(%q, %q) = %+v, want %+v", tc.belt, tc.category, got, tc.want)
+			}
+		})
+	}
+	if got := c.ChatIDForBelt("songadh"); got != "default-chat" {
+		t.Errorf("ChatIDForBelt ignores category routes; got %q", got)
+	}
+}
+
 func TestIsMoveCommand(t *testing.T) {
 	tests := []struct {
 		name string
@@ -111,3 +163,1918 @@ func TestIsMoveCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestComplaintKeyboard(t *testing.T) {
+	off := &Client{}
+	kb := off.complaintKeyboard("123", "")
+	if len(kb.InlineKeyboard) != 1 || len(kb.InlineKeyboard[0]) != 1 {
+		t.Fatalf("AckButton off: want only the resolve button, got %+v", kb)
+	}
+
+	on := &Client{AckButton: true}
+	kb = on.complaintKeyboard("123", "")
+	if got := kb.InlineKeyboard[0]; len(got) != 2 || got[1].Text != "👀 Seen" || got[1].CallbackData != "ack:123" {
+		t.Errorf("AckButton on: got %+v", got)
+	}
+	kb = on.complaintKeyboard("123", "@asha")
+	if got := kb.InlineKeyboard[0][1].Text; got != "👀 @asha" {
+		t.Errorf("acknowledged button text: got %q", got)
+	}
+
+	details := &Client{DetailsButton: true}
+	kb = details.complaintKeyboard("123", "")
+	if len(kb.InlineKeyboard) != 2 || kb.InlineKeyboard[1][0].CallbackData != "details:123" {
+		t.Errorf("DetailsButton on: got %+v", kb)
+	}
+}
+
+func TestFormatLiveDetails(t *testing.T) {
+	rec := &api.Record{
+		Detail: map[string]interface{}{
+			"complain_no":      "123",
+			"complainant_name": "Asha",
+			"complain_status":  "Assigned",
+			"assign_to":        "JE <Tokarva>",
+			"remark":           nil,
+		},
+	}
+	for i := 1; i <= 12; i++ {
+		rec.History = append(rec.History, map[string]interface{}{
+			"id":         float64(i),
+			"created_at": fmt.Sprintf("2026-03-01 %02d:00", i),
+			"status":     fmt.Sprintf("step %d", i),
+		})
+	}
+	got := formatLiveDetails("123", rec, time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC))
+	for _, want := range []string{
+		"🔍 <b>Live status — complaint 123</b>\n<i>Fetched 01 Mar 2026, 02:05 PM</i>\n",
+		"\n• Assign to: JE &lt;Tokarva&gt;\n• Complain status: Assigned",
+		"<b>History</b> (latest 10 of 12)",
+		"\n• 2026-03-01 03:00 · step 3",
+		"\n• 2026-03-01 12:00 · step 12",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("details missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Asha") || strings.Contains(got, "step 2\n") || strings.Contains(got, "Remark") {
+		t.Errorf("details include non-status, old or empty fields:\n%s", got)
+	}
+}
+
+func TestMessageLink(t *testing.T) {
+	cases := []struct {
+		chatID, messageID, want string
+	}{
+		{"-1001234567", "42", "https://t.me/c/1234567/42"},
+		{"-4567", "42", ""}, // basic group: no message links
+		{"-1001234567", "", ""},
+	}
+	for _, tc := range cases {
+		if got := messageLink(tc.chatID, tc.messageID); got != tc.want {
+			t.Errorf("messageLink(%q, %q) = %q, want %q", tc.chatID, tc.messageID, got, tc.want)
+		}
+	}
+}
+
+func TestFormatUnseenReminder(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	items := []storage.Unseen{
+		{ComplaintID: "123", MessageID: "42", ConsumerName: "Ramesh <Patel>", Village: "Tokarva", Belt: "dahod", NotifiedAt: now.Add(-2*time.Hour - 5*time.Minute)},
+		{ComplaintID: "124", NotifiedAt: now.Add(-45 * time.Minute)},
+	}
+	got := formatUnseenReminder("-1001234567", items, now, false)
+
+	for _, want := range []string{
+		"Nobody has looked at these yet (2)",
+		`<a href="https://t.me/c/1234567/42">123</a> — Ramesh &lt;Patel&gt;, Tokarva`,
+		"· 2h 05m",
+		"\n• 124 (",
+		"· 45m",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("reminder missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestResolvedText(t *testing.T) {
+	at := time.Date(2026, 3, 10, 15, 4, 0, 0, time.UTC)
+	got, err := resolvedText(notify.Status{ComplaintID: "123", Time: at})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "✅ <b>RESOLVED</b>\n\nComplaint #123\n👤 Unknown\n🕐 10 Mar 2026, 03:04 PM"
+	if got != want {
+		t.Errorf("resolvedText = %q, want %q", got, want)
+	}
+	if got, _ := resolvedText(notify.Status{ComplaintID: "VLD1", ConsumerName: "Asha", Local: true, Time: at}); !strings.HasPrefix(got, "✅ <b>RESOLVED (LOCAL)</b>") || !strings.Contains(got, "👤 Asha") {
+		t.Errorf("local resolvedText = %q", got)
+	}
+	if got, _ := resolvedText(notify.Status{ComplaintID: "124", ConsumerName: "A & B <Ltd>", Time: at}); !strings.Contains(got, "👤 A &amp; B &lt;Ltd&gt;") {
+		t.Errorf("consumer name not escaped: %q", got)
+	}
+	got, _ = resolvedText(notify.Status{ComplaintID: "125", ConsumerName: "Asha", ResolvedBy: "JE Tokarva", Remark: "fuse <replaced>", Time: at})
+	if !strings.Contains(got, "👤 Asha\n📝 Resolved on portal by JE Tokarva: fuse &lt;replaced&gt;\n🕐") {
+		t.Errorf("resolution remark missing: %q", got)
+	}
+}
+
+func TestBotTextsFollowLanguage(t *testing.T) {
+	i18n.SetLanguage(i18n.Gujarati)
+	t.Cleanup(func() { i18n.SetLanguage(i18n.English) })
+
+	got, err := resolvedText(notify.Status{ComplaintID: "123", ConsumerName: "A & B", Time: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got, "✅ <b>નિકાલ થયો</b>\n\nફરિયાદ #123\n👤 A &amp; B") {
+		t.Errorf("Gujarati resolvedText = %q", got)
+	}
+	if kb := (&Client{}).complaintKeyboard("123", ""); kb.InlineKeyboard[0][0].Text != "✅ નિકાલ થયો" {
+		t.Errorf("resolve button = %q", kb.InlineKeyboard[0][0].Text)
+	}
+	alert, _ := renderHTML(criticalAlertTmpl, criticalAlertView{ErrorType: "Login", Time: time.Now()})
+	if !strings.Contains(alert, "<b>ભૂલનો પ્રકાર:</b> Login") {
+		t.Errorf("Gujarati critical alert = %q", alert)
+	}
+	if got := pageCaption(2, 3); got != " (પાનું 2/3)" {
+		t.Errorf("page caption = %q", got)
+	}
+}
+
+func TestSendComplaintMessageEscapesContent(t *testing.T) {
+	var sent Message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":9}}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client()}
+
+	complaint := `{"complain_no":"123","complainant_name":"R&D <Works>","description":"Wire down <b>near</b> pole & transformer","exact_location":"Main Rd","area":"Tokarva","maps_url":"javascript:alert(1)","repeat_note":"2 earlier <complaints>","address_gu":"ગામ <નવું>"}`
+	msgID, err := c.SendComplaintMessage(complaint, "123")
+	if err != nil {
+		t.Fatalf("SendComplaintMessage: %v", err)
+	}
+	if msgID != "9" {
+		t.Errorf("message ID = %q, want 9", msgID)
+	}
+	for _, want := range []string{
+		"📋 Complaint : 123\n",
+		"👤 R&amp;D &lt;Works&gt;\n",
+		"💬 <b>Details:</b>\nWire down &lt;b&gt;near&lt;/b&gt; pole &amp; transformer\n",
+		"</i>\n🗺️ <a href=\"#ZgotmplZ\">",
+		"\n\n<b>2 earlier &lt;complaints&gt;</b>",
+		"📍 Main Rd, Tokarva\n     <i>ગામ &lt;નવું&gt;</i>\n",
+	} {
+		if !strings.Contains(sent.Text, want) {
+			t.Errorf("message missing %q:\n%s", want, sent.Text)
+		}
+	}
+	if strings.Contains(sent.Text, "<b>near</b>") || strings.Contains(sent.Text, "javascript:") {
+		t.Errorf("unescaped content in message:\n%s", sent.Text)
+	}
+}
+
+func TestSendComplaintMessageRepliesToReopenedOriginal(t *testing.T) {
+	var sent Message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":10}}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client()}
+
+	complaint := `{"complain_no":"123","reopened":{"resolved_at":"2026-03-09T08:35:00Z","tg_message_id":"9"}}`
+	if _, err := c.SendComplaintMessage(complaint, "123"); err != nil {
+		t.Fatalf("SendComplaintMessage: %v", err)
+	}
+	if sent.ReplyToMessageID != 9 || !sent.AllowSendingWithoutReply {
+		t.Errorf("reply: got %d (allow without reply %v), want 9", sent.ReplyToMessageID, sent.AllowSendingWithoutReply)
+	}
+	if !strings.HasPrefix(sent.Text, "♻️ <b>RE-OPENED</b>") {
+		t.Errorf("message should start with the re-opened header:\n%s", sent.Text)
+	}
+}
+
+func TestSendComplaintMessageRedactsPII(t *testing.T) {
+	var sent Message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":9}}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client(), RedactPII: true}
+
+	complaint := `{"complain_no":"123","complainant_name":"Asha","mobile_no":"9876543210","consumer_no":"55667788"}`
+	if _, err := c.SendComplaintMessage(complaint, "123"); err != nil {
+		t.Fatalf("SendComplaintMessage: %v", err)
+	}
+	if strings.Contains(sent.Text, "9876543210") || strings.Contains(sent.Text, "55667788") {
+		t.Errorf("full numbers in redacted message:\n%s", sent.Text)
+	}
+	if !strings.Contains(sent.Text, "••••••3210") || !strings.Contains(sent.Text, "••••7788") {
+		t.Errorf("masked numbers missing:\n%s", sent.Text)
+	}
+}
+
+func TestSendDocumentUploadsFile(t *testing.T) {
+	var method, chatID, filename, content string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		chatID = r.FormValue("chat_id")
+		if f, hdr, err := r.FormFile("document"); err == nil {
+			filename = hdr.Filename
+			b := new(bytes.Buffer)
+			b.ReadFrom(f)
+			content = b.String()
+		}
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":11}}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", apiBase: srv.URL, httpClient: srv.Client()}
+
+	if err := c.SendDocument("-200", "cmon-20260310-091500.db", []byte("SQLite format 3"), "backup"); err != nil {
+		t.Fatalf("SendDocument: %v", err)
+	}
+	if method != "sendDocument" || chatID != "-200" || filename != "cmon-20260310-091500.db" || content != "SQLite format 3" {
+		t.Errorf("got method %q chat %q file %q content %q", method, chatID, filename, content)
+	}
+}
+
+func TestIsCaptchaReply(t *testing.T) {
+	prompt := &captchaPrompt{chatID: "-1001234", messageID: 42}
+	reply := func(chatID int64, to int) *IncomingMessage {
+		return &IncomingMessage{Chat: &Chat{ID: chatID}, Text: "12", ReplyToMessage: &IncomingMessage{MessageID: to}}
+	}
+	if !isCaptchaReply(prompt, reply(-1001234, 42)) {
+		t.Error("reply to the prompt in its chat should match")
+	}
+	if isCaptchaReply(prompt, reply(-1001234, 41)) {
+		t.Error("reply to another message should not match")
+	}
+	if isCaptchaReply(prompt, reply(-1009999, 42)) {
+		t.Error("reply in another chat should not match")
+	}
+	if isCaptchaReply(prompt, &IncomingMessage{Chat: &Chat{ID: -1001234}, Text: "12"}) {
+		t.Error("plain message should not match")
+	}
+	if isCaptchaReply(&captchaPrompt{chatID: "-1001234"}, reply(-1001234, 0)) {
+		t.Error("prompt without a message ID yet should not match")
+	}
+}
+
+func TestRenderCaptcha(t *testing.T) {
+	img, err := renderCaptcha("5 + 7")
+	if err != nil {
+		t.Fatalf("renderCaptcha: %v", err)
+	}
+	decoded, err := png.Decode(bytes.NewReader(img))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if b := decoded.Bounds(); b.Dx() <= b.Dy() {
+		t.Errorf("captcha image should be wider than tall, got %v", b)
+	}
+	if _, err := renderCaptcha("  "); err == nil {
+		t.Error("empty captcha text should error")
+	}
+}
+
+type memSettings map[string]string
+
+func (m memSettings) GetSetting(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+func (m memSettings) SetSetting(key, value string) error {
+	m[key] = value
+	return nil
+}
+
+func TestRunAdminCommand(t *testing.T) {
+	t.Cleanup(func() { logging.SetLevel("info") })
+	f := flags.New(memSettings{})
+
+	cases := []struct {
+		text, want string
+	}{
+		{"/flag translation off", "<b>translation</b> switched <b>off</b>"},
+		{"/flag weather off", "Usage: <code>/flag name on|off</code>"},
+		{"/flag summaries", "Usage:"},
+		{"/debug on", "Debug logging <b>on</b>"},
+		{"/loglevel", "Log level: <b>debug</b>"},
+		{"/loglevel chatty", "unknown log level"},
+		{"/loglevel WARN", "Log level set to <b>warn</b>"},
+		{"/debug maybe", "Usage: <code>/debug on|off</code>"},
+		{"/flags", "translation: off\nsummaries: on\nreminders: on\nlog level: warn"},
+	}
+	for _, tc := range cases {
+		if got := runAdminCommand(f, tc.text); !strings.Contains(got, tc.want) {
+			t.Errorf("%q: got %q, want it to contain %q", tc.text, got, tc.want)
+		}
+	}
+	if f.Enabled(flags.Translation) {
+		t.Error("translation should be off")
+	}
+}
+
+func TestIsAdminCommand(t *testing.T) {
+	for text, want := range map[string]bool{
+		"/debug on": true,
+		" /flags":   true,
+		"/flagged":  false,
+		"/summary":  false,
+		"debug on":  false,
+		"":          false,
+	} {
+		if got := isAdminCommand(text); got != want {
+			t.Errorf("isAdminCommand(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
+
+func TestAdminAllowed(t *testing.T) {
+	admin := &User{ID: 7, Username: "Boss"}
+	member := &User{ID: 8, Username: "member"}
+	for _, tc := range []struct {
+		name      string
+		adminChat string
+		chat      int64
+		from      *User
+		want      bool
+	}{
+		{"separate admin chat", "-200", -200, member, true},
+		{"other chat", "-200", -100, admin, false},
+		{"broadcast chat non-admin", "-100", -100, member, false},
+		{"broadcast chat admin", "-100", -100, admin, true},
+		{"belt route chat non-admin", "-300", -300, member, false},
+		{"category route chat non-admin", "-400", -400, member, false},
+		{"broadcast chat no sender", "-100", -100, nil, false},
+	} {
+		c := &Client{
+			ChatID:         "-100",
+			AdminChatID:    tc.adminChat,
+			Admins:         []string{"@boss"},
+			BeltRoutes:     map[string]string{"north": "-300"},
+			CategoryRoutes: map[string]string{"meter": "-400:12"},
+		}
+		message := &IncomingMessage{Chat: &Chat{ID: tc.chat}, From: tc.from, Text: "/backup"}
+		if got := c.adminAllowed(message); got != tc.want {
+			t.Errorf("%s: adminAllowed = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSummaryCommand(t *testing.T) {
+	for text, want := range map[string][3]string{
+		"/summary":                      {"/summary", "", "true"},
+		" /summary area=Valod age>24h ": {"/summary", "area=Valod age>24h", "true"},
+		"/summarybelt belt=Dahod":       {"/summarybelt", "belt=Dahod", "true"},
+		"/summaryx":                     {"", "", "false"},
+		"please /summary":               {"", "", "false"},
+	} {
+		cmd, filter, ok := summaryCommand(text)
+		if got := [3]string{cmd, filter, fmt.Sprint(ok)}; got != want {
+			t.Errorf("summaryCommand(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestPerChatLimitedMethods(t *testing.T) {
+	for method, want := range map[string]bool{
+		"sendMessage":            true,
+		"sendPhoto":              true,
+		"sendDocument":           true,
+		"editMessageText":        true,
+		"editMessageReplyMarkup": true,
+		"answerCallbackQuery":    false,
+		"deleteMessage":          false,
+		"getUpdates":             false,
+	} {
+		if got := perChatLimited(method); got != want {
+			t.Errorf("perChatLimited(%s) = %v, want %v", method, got, want)
+		}
+	}
+}
+
+func TestRateLimiterPerChatAndGlobal(t *testing.T) {
+	clock := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	l := newRateLimiter()
+	l.now = func() time.Time { return clock }
+
+	// A chat's 20-message burst goes straight out; the 21st waits for a
+	// refill (3s at 20/min) while another chat is unaffected.
+	for i := 0; i < int(perChatBurst); i++ {
+		if d := l.delay("-100"); d != 0 {
+			t.Fatalf("message %d delayed %v inside the burst", i+1, d)
+		}
+	}
+	if d := l.delay("-100"); d < 2900*time.Millisecond || d > 3100*time.Millisecond {
+		t.Errorf("21st message to one chat: delay %v, want ~3s", d)
+	}
+	clock = clock.Add(time.Second) // refill the global bucket
+	if d := l.delay("-200"); d != 0 {
+		t.Errorf("other chat delayed %v", d)
+	}
+
+	// A 429 pause holds everyone, including calls with no chat.
+	l.pause(5 * time.Second)
+	if d := l.delay(""); d < 5*time.Second {
+		t.Errorf("delay during pause = %v, want >= 5s", d)
+	}
+}
+
+func TestDoRequestHonoursRetryAfter(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 7","parameters":{"retry_after":7}}`)
+			return
+		}
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+	}))
+	defer srv.Close()
+
+	var slept []time.Duration
+	c := &Client{BotToken: "t", apiBase: srv.URL, httpClient: srv.Client()}
+	c.rateLimiter().sleep = func(_ context.Context, d time.Duration) error { slept = append(slept, d); return nil }
+
+	if err := c.send("sendMessage", Message{ChatID: "-100", Text: "hi"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("API calls = %d, want 2 (one retry)", calls)
+	}
+	if len(slept) != 1 || slept[0] < 6*time.Second || slept[0] > 7*time.Second {
+		t.Errorf("slept %v, want one ~7s wait", slept)
+	}
+}
+
+func TestDoRequestGivesUpAfterRepeated429(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"ok":false,"error_code":429,"parameters":{"retry_after":1}}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{BotToken: "t", apiBase: srv.URL, httpClient: srv.Client()}
+	c.rateLimiter().sleep = func(context.Context, time.Duration) error { return nil }
+
+	if err := c.send("sendMessage", Message{ChatID: "-100"}); !IsRateLimited(err) {
+		t.Fatalf("err = %v, want a 429 APIError once retries are exhausted", err)
+	}
+	if calls != maxRateLimitRetries+1 {
+		t.Errorf("API calls = %d, want %d", calls, maxRateLimitRetries+1)
+	}
+}
+
+func TestRetryAfterPauseStopsWithContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"ok":false,"error_code":429,"parameters":{"retry_after":30}}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{BotToken: "t", apiBase: srv.URL, httpClient: srv.Client()}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := doRequest[SendMessageResult](ctx, c, "sendMessage", Message{ChatID: "-100"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want the context's deadline", err)
+	}
+	if waited := time.Since(start); waited > 5*time.Second {
+		t.Errorf("call returned after %v, want it to stop with the context, not wait out retry_after", waited)
+	}
+}
+
+func TestCallRecordsRequestMetrics(t *testing.T) {
+	replies := []string{
+		`{"ok":false,"error_code":429,"parameters":{"retry_after":1}}`,
+		`{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, replies[0])
+		replies = replies[1:]
+	}))
+	defer srv.Close()
+
+	c := &Client{BotToken: "t", apiBase: srv.URL, httpClient: srv.Client()}
+	c.rateLimiter().sleep = func(context.Context, time.Duration) error { return nil }
+
+	requests := metrics.TelegramRequestSeconds.Count("sendMessage")
+	limited := metrics.TelegramRateLimitedTotal.Value("sendMessage")
+	failed := metrics.TelegramRequestErrorsTotal.Value("sendMessage")
+	if err := c.send("sendMessage", Message{ChatID: "-100"}); err == nil {
+		t.Fatal("want the 400 returned after the 429 retry")
+	}
+	if got := metrics.TelegramRequestSeconds.Count("sendMessage") - requests; got != 2 {
+		t.Errorf("observed requests = %d, want 2", got)
+	}
+	if got := metrics.TelegramRateLimitedTotal.Value("sendMessage") - limited; got != 1 {
+		t.Errorf("rate limited = %d, want 1", got)
+	}
+	if got := metrics.TelegramRequestErrorsTotal.Value("sendMessage") - failed; got != 1 {
+		t.Errorf("errors = %d, want 1 (the 429 is counted apart)", got)
+	}
+}
+
+func TestDoRequestDecodesTypedResult(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":42,"date":1700000000,"chat":{"id":-100123}}}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{BotToken: "t", apiBase: srv.URL, httpClient: srv.Client()}
+	got, err := doRequest[SendMessageResult](context.Background(), c, "sendMessage", Message{ChatID: "-100123", Text: "hi"})
+	if err != nil {
+		t.Fatalf("doRequest: %v", err)
+	}
+	if got.MessageID != 42 || got.Chat.ID != -100123 || got.Date != 1700000000 {
+		t.Errorf("result = %+v", got)
+	}
+}
+
+func TestAPIErrorDistinguishesNotModified(t *testing.T) {
+	var status int
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", apiBase: srv.URL, httpClient: srv.Client()}
+	req := EditMessageRequest{ChatID: "-100", MessageID: "7", Text: "same"}
+
+	status, body = http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: message is not modified: specified new message content and reply markup are exactly the same"}`
+	err := c.send("editMessageText", req)
+	if !IsNotModified(err) {
+		t.Fatalf("send err = %v, want not-modified", err)
+	}
+	if err := c.edit("editMessageText", req); err != nil {
+		t.Errorf("edit of unchanged message = %v, want nil", err)
+	}
+
+	status, body = http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: message to edit not found"}`
+	err = c.edit("editMessageText", req)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 400 || IsNotModified(err) {
+		t.Fatalf("edit err = %v, want a 400 APIError that is not not-modified", err)
+	}
+	if apiErr.Method != "editMessageText" || !strings.Contains(apiErr.Description, "not found") {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+}
+
+func TestFormatDeadLetters(t *testing.T) {
+	now := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC).In(time.FixedZone("IST", 5*3600+1800))
+	got := formatDeadLetters([]storage.DeadLetter{
+		{ComplaintID: "123", Stage: storage.StageFetch, Attempts: 3, LastError: "HTTP 500 <html>", NextRetryAt: now.Add(20 * time.Minute)},
+		{ComplaintID: "124", Stage: storage.StageNotify, Attempts: 1, NextRetryAt: now.Add(-time.Minute)},
+	}, now)
+	for _, want := range []string{
+		"📮 <b>Failed complaints (2)</b>",
+		"<b>123</b> · details fetch · 3 attempt(s)\n❗ HTTP 500 &lt;html&gt;\n⏳ Next retry 10 Mar 13:50",
+		"<b>124</b> · Telegram send · 1 attempt(s)\n⏳ Retrying on the next cycle",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("reply missing %q:\n%s", want, got)
+		}
+	}
+	if got := formatDeadLetters(nil, now); !strings.HasPrefix(got, "✅") {
+		t.Errorf("empty list: %q", got)
+	}
+}
+
+func TestFormatStatus(t *testing.T) {
+	started := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	got := formatStatus(health.Status{
+		Status:            health.StateDegraded,
+		Uptime:            "3h0m0s",
+		Portal:            "ok",
+		ConsecutiveErrors: 1,
+		Components: map[string]health.ComponentStatus{
+			"fetch":    {Status: health.StateHealthy},
+			"telegram": {Status: health.StateDegraded, Detail: "getMe: 401"},
+		},
+	}, []health.FetchCycle{
+		{StartedAt: started.Add(15 * time.Minute), Duration: 42 * time.Second, Errors: 2, Status: "error: <timeout>"},
+		{StartedAt: started, Duration: 12340 * time.Millisecond, Pages: 3, NewComplaints: 2, Status: "success"},
+	}, time.FixedZone("IST", 5*3600+1800))
+	for _, want := range []string{
+		"🩺 <b>Status: degraded</b> ⚠️",
+		"Up 3h0m0s · portal ok · 1 failed fetch(es) in a row",
+		"⚠️ telegram: getMe: 401",
+		"❌ 10 Mar 13:45 · 42s · 0 page(s) · 0 new · 2 error(s)\n   ❗ error: &lt;timeout&gt;",
+		"✅ 10 Mar 13:30 · 12.3s · 3 page(s) · 2 new\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("reply missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "fetch:") {
+		t.Errorf("healthy components should not be listed:\n%s", got)
+	}
+	if got := formatStatus(health.Status{Status: health.StateStarting}, nil, time.UTC); !strings.Contains(got, "No fetch cycles yet.") {
+		t.Errorf("empty history: %q", got)
+	}
+}
+
+func TestFormatBuildInfo(t *testing.T) {
+	got := formatBuildInfo("ℹ️ <b>CMON version</b>", buildinfo.Info{Version: "v1.2.3", Commit: "a1b2c3d4e5f6", BuildTime: "2026-10-17T08:00:00Z", GoVersion: "go1.25.3"})
+	want := "ℹ️ <b>CMON version</b>\n🏷️ Version: <code>v1.2.3</code>\n🔖 Commit: <code>a1b2c3d</code>\n🛠️ Built: 2026-10-17T08:00:00Z\n🐹 Go: go1.25.3"
+	if got != want {
+		t.Errorf("formatBuildInfo = %q, want %q", got, want)
+	}
+	if got := formatBuildInfo("t", buildinfo.Info{Version: "dev", GoVersion: "go1.25.3"}); strings.Contains(got, "Commit") || strings.Contains(got, "Built") {
+		t.Errorf("unknown commit and build time should be left out: %q", got)
+	}
+}
+
+func TestLifecycleNoticesGoToAdminChat(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{BotToken: "t", ChatID: "-100", AdminChatID: "-200", apiBase: srv.URL, httpClient: srv.Client()}
+	if err := c.SendStartupNotice(12); err != nil {
+		t.Fatalf("SendStartupNotice: %v", err)
+	}
+	if err := c.SendShutdownNotice("signal: SIGTERM"); err != nil {
+		t.Fatalf("SendShutdownNotice: %v", err)
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("API calls = %d, want 2", len(bodies))
+	}
+	for _, b := range bodies {
+		if !strings.Contains(b, `"chat_id":"-200"`) {
+			t.Errorf("notice not sent to the admin chat: %s", b)
+		}
+	}
+	if !strings.Contains(bodies[0], "CMON started") || !strings.Contains(bodies[0], "resumed 12 pending complaints") {
+		t.Errorf("startup notice = %s", bodies[0])
+	}
+	if !strings.Contains(bodies[1], "CMON shutting down") || !strings.Contains(bodies[1], "(signal: SIGTERM)") {
+		t.Errorf("shutdown notice = %s", bodies[1])
+	}
+
+	c.AdminChatID = ""
+	if err := c.SendShutdownNotice("signal: SIGINT"); err != nil || len(bodies) != 2 {
+		t.Errorf("without an admin chat nothing should be sent; err=%v, calls=%d", err, len(bodies))
+	}
+}
+
+func TestHandleUpdateRecoversAndReportsPanic(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{BotToken: "t", ChatID: "-100", AdminChatID: "-200", apiBase: srv.URL, httpClient: srv.Client()}
+	crash.SetReporter(func(component string, value any, stack []byte) {
+		if err := c.SendCrashReport(component, value, stack); err != nil {
+			t.Errorf("SendCrashReport: %v", err)
+		}
+	})
+	t.Cleanup(func() { crash.SetReporter(nil) })
+
+	// A reaction with no storage behind it dereferences nil.
+	reaction := &MessageReactionUpdated{MessageID: 7, User: &User{ID: 1, FirstName: "A"}, NewReaction: []ReactionType{{Type: "emoji", Emoji: "👍"}}}
+	c.handleUpdate(context.Background(), nil, Update{UpdateID: 1, MessageReaction: reaction}, nil)
+
+	if len(bodies) != 1 {
+		t.Fatalf("API calls = %d, want 1 crash report", len(bodies))
+	}
+	if !strings.Contains(bodies[0], `"chat_id":"-200"`) || !strings.Contains(bodies[0], "Panic in Telegram update handler") ||
+		!strings.Contains(bodies[0], "nil pointer dereference") || !strings.Contains(bodies[0], "handleReaction") {
+		t.Errorf("crash report = %s", bodies[0])
+	}
+}
+
+func TestSendCrashReportTruncatesStack(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{BotToken: "t", ChatID: "-100", AdminChatID: "-200", apiBase: srv.URL, httpClient: srv.Client()}
+	stack := []byte(strings.Repeat("goroutine frame\n", 1000))
+	if err := c.SendCrashReport("fetch loop", "boom", stack); err != nil {
+		t.Fatalf("SendCrashReport: %v", err)
+	}
+	var msg Message
+	if err := json.Unmarshal([]byte(body), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if n := len([]rune(msg.Text)); n > 4096 {
+		t.Errorf("report is %d characters, over Telegram's limit", n)
+	}
+	if !strings.Contains(msg.Text, "…</pre>") {
+		t.Errorf("truncated stack should be marked: %q", msg.Text[len(msg.Text)-40:])
+	}
+}
+
+func TestHandleUpdatesStopsMidPoll(t *testing.T) {
+	polling := make(chan struct{}, 1)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case polling <- struct{}{}:
+		default:
+		}
+		// Hold the long poll open, as Telegram does when there is nothing new.
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+		fmt.Fprint(w, `{"ok":true,"result":[]}`)
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client()}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.HandleUpdates(ctx, nil, nil)
+		close(done)
+	}()
+
+	<-polling
+	start := time.Now()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("HandleUpdates still blocked in getUpdates 2s after cancellation")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("shutdown took %v", d)
+	}
+}
+
+func TestDoRequestHonoursCancelledContext(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client()}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := doRequest[SendMessageResult](ctx, c, "sendMessage", Message{ChatID: "-100", Text: "hi"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if calls != 0 {
+		t.Errorf("a cancelled call reached the API %d times", calls)
+	}
+}
+
+// deadLetterStore is an in-memory messageStore.
+type deadLetterStore struct {
+	ids     map[string]string
+	letters map[string]storage.DeadLetter
+	journal []storage.JournalEntry
+}
+
+func (s *deadLetterStore) GetMessageID(id string) string { return s.ids[id] }
+func (s *deadLetterStore) SetMessageID(id, msgID string) error {
+	s.ids[id] = msgID
+	return nil
+}
+func (s *deadLetterStore) SetMessageText(id, text string) error { return nil }
+func (s *deadLetterStore) GetMessageText(id string) (string, int, bool) {
+	return "", 0, false
+}
+func (s *deadLetterStore) GetAcknowledgment(id string) (string, int64, bool) {
+	return "", 0, false
+}
+func (s *deadLetterStore) GetNotes(id string) ([]storage.Note, error) { return nil, nil }
+func (s *deadLetterStore) GetMessageRef(channel, id string) string    { return "" }
+func (s *deadLetterStore) SetMessageRef(channel, id, ref string) error {
+	return nil
+}
+func (s *deadLetterStore) GetComplaintIDsByMessageRef(channel, ref string) []string { return nil }
+func (s *deadLetterStore) GetRecord(id string) (storage.Record, bool) {
+	return storage.Record{}, false
+}
+func (s *deadLetterStore) DeferSend(id, payload string) error { return nil }
+func (s *deadLetterStore) GetDeferredSends() ([]storage.DeferredSend, error) {
+	return nil, nil
+}
+func (s *deadLetterStore) ClearDeferredSend(id string) error { return nil }
+func (s *deadLetterStore) RecordFailure(id, apiID, stage, errMsg, payload string) (storage.DeadLetter, error) {
+	dl := s.letters[id]
+	dl.ComplaintID, dl.Stage, dl.LastError, dl.Payload = id, stage, errMsg, payload
+	dl.Attempts++
+	s.letters[id] = dl
+	return dl, nil
+}
+func (s *deadLetterStore) GetDueDeadLetters(stage string) ([]storage.DeadLetter, error) {
+	var out []storage.DeadLetter
+	for _, dl := range s.letters {
+		if dl.Stage == stage {
+			out = append(out, dl)
+		}
+	}
+	return out, nil
+}
+func (s *deadLetterStore) ClearDeadLetter(id string) error {
+	delete(s.letters, id)
+	return nil
+}
+func (s *deadLetterStore) JournalAppend(op, id, payload string) (int64, error) {
+	e := storage.JournalEntry{ID: int64(len(s.journal) + 1), Op: op, ComplaintID: id, Payload: payload}
+	s.journal = append(s.journal, e)
+	return e.ID, nil
+}
+func (s *deadLetterStore) JournalDone(id int64) error {
+	for i, e := range s.journal {
+		if e.ID == id {
+			s.journal = append(s.journal[:i], s.journal[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+func (s *deadLetterStore) ReplayJournal(op string, replay func(storage.JournalEntry) error) (int, error) {
+	var entries []storage.JournalEntry
+	kept := s.journal[:0]
+	for _, e := range s.journal {
+		if e.Op == op {
+			entries = append(entries, e)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	s.journal = kept
+	for _, e := range entries {
+		if err := replay(e); err != nil {
+			return 0, err
+		}
+	}
+	return len(entries), nil
+}
+
+func TestNotifierDeadLettersFailedSends(t *testing.T) {
+	fail := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			fmt.Fprint(w, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":55}}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client()}
+	stor := &deadLetterStore{ids: map[string]string{}, letters: map[string]storage.DeadLetter{}}
+	n := c.AsNotifier(stor)
+
+	if err := n.SendComplaint(notify.Complaint{Number: "123", ComplainantName: "Asha"}); err == nil {
+		t.Fatal("SendComplaint should fail")
+	}
+	dl, ok := stor.letters["123"]
+	if !ok || dl.Stage != storage.StageNotify || dl.Attempts != 1 || !strings.Contains(dl.Payload, `"complainant_name":"Asha"`) {
+		t.Fatalf("dead letter: %+v", dl)
+	}
+
+	n.RetryFailedSends()
+	if stor.letters["123"].Attempts != 2 {
+		t.Errorf("failed retry should reschedule, got %+v", stor.letters["123"])
+	}
+
+	fail = false
+	n.RetryFailedSends()
+	if _, ok := stor.letters["123"]; ok {
+		t.Error("successful retry should clear the dead letter")
+	}
+	if stor.ids["123"] != "55" {
+		t.Errorf("message ID: got %q, want 55", stor.ids["123"])
+	}
+}
+
+func TestNotifierRecoversInterruptedSends(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":77}}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client()}
+	stor := &deadLetterStore{ids: map[string]string{}, letters: map[string]storage.DeadLetter{}}
+	n := c.AsNotifier(stor)
+
+	if err := n.SendComplaint(notify.Complaint{Number: "200"}); err != nil {
+		t.Fatalf("SendComplaint: %v", err)
+	}
+	if len(stor.journal) != 0 {
+		t.Fatalf("a finished send must leave no journal entry, got %+v", stor.journal)
+	}
+
+	// A crash mid-send leaves entries behind: one whose message ID was
+	// stored, one that never got that far.
+	stor.ids["201"] = "90"
+	stor.JournalAppend(storage.JournalTelegramSend, "201", `{"complain_no":"201"}`)
+	stor.JournalAppend(storage.JournalTelegramSend, "202", `{"complain_no":"202"}`)
+
+	n.RecoverInterruptedSends()
+	if _, ok := stor.letters["201"]; ok {
+		t.Error("a send with a stored message ID must not be retried")
+	}
+	dl, ok := stor.letters["202"]
+	if !ok || dl.Stage != storage.StageNotify || dl.Payload != `{"complain_no":"202"}` {
+		t.Errorf("interrupted send should be dead-lettered for retry, got %+v", dl)
+	}
+}
+
+func TestNotifierDoesNotResendSentComplaints(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":88}}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client()}
+	stor := &deadLetterStore{ids: map[string]string{}, letters: map[string]storage.DeadLetter{}}
+	n := c.AsNotifier(stor)
+
+	// 300 was sent before a crash that left a retry queued for it.
+	stor.ids["300"] = "70"
+	stor.RecordFailure("300", "", storage.StageNotify, "interrupted", `{"complain_no":"300"}`)
+
+	if err := n.SendComplaints([]notify.Complaint{{Number: "300"}, {Number: "301"}}); err != nil {
+		t.Fatalf("SendComplaints: %v", err)
+	}
+	if calls != 1 || stor.ids["300"] != "70" || stor.ids["301"] != "88" {
+		t.Errorf("API calls = %d, message IDs %v; want only 301 sent", calls, stor.ids)
+	}
+	if _, ok := stor.letters["300"]; ok {
+		t.Error("the stale retry for a sent complaint should be cleared")
+	}
+}
+
+func TestRefreshAgeFooters(t *testing.T) {
+	t.Chdir(t.TempDir())
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]storage.Record{{ComplaintID: "CMP-1"}, {ComplaintID: "CMP-2"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	// CMP-2 has no stored text (sent before the footer existed) and is left alone.
+	stor.SetMessageID("CMP-1", "9")
+	stor.SetMessageID("CMP-2", "10")
+	stor.SetMessageText("CMP-1", "📋 Complaint : CMP-1")
+	stor.MarkAcknowledged("CMP-1", "Asha", 1)
+
+	var edits []EditMessageRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req EditMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		edits = append(edits, req)
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client(), AckButton: true}
+
+	now := time.Now()
+	for _, at := range []time.Time{now, now.Add(50 * time.Hour), now.Add(60 * time.Hour), now.Add(75 * time.Hour)} {
+		if err := c.RefreshAgeFooters(stor, at); err != nil {
+			t.Fatalf("RefreshAgeFooters: %v", err)
+		}
+	}
+	if len(edits) != 2 {
+		t.Fatalf("edits = %d, want one on day 2 and one on day 3: %+v", len(edits), edits)
+	}
+	if edits[0].MessageID != "9" || edits[0].Text != "📋 Complaint : CMP-1\n\n⏳ pending 2 days" {
+		t.Errorf("day 2 edit = %+v", edits[0])
+	}
+	if edits[1].Text != "📋 Complaint : CMP-1\n\n⏳ pending 3 days" {
+		t.Errorf("day 3 edit = %q", edits[1].Text)
+	}
+	if kb := edits[1].ReplyMarkup; kb == nil || len(kb.InlineKeyboard[0]) != 2 || kb.InlineKeyboard[0][1].Text != "👀 Asha" {
+		t.Errorf("keyboard not kept: %+v", kb)
+	}
+}
+
+func TestCleanupResolvedMessages(t *testing.T) {
+	t.Chdir(t.TempDir())
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	// CMP-1 and CMP-2 shared a digest message; CMP-4 is in a digest with
+	// open CMP-5, so its message stays.
+	if err := stor.SaveMultiple([]storage.Record{
+		{ComplaintID: "CMP-1", MessageID: "11", Village: "Valod", Belt: "Dahod"},
+		{ComplaintID: "CMP-2", MessageID: "11"},
+		{ComplaintID: "CMP-3", MessageID: "12"},
+		{ComplaintID: "CMP-4", MessageID: "13"},
+		{ComplaintID: "CMP-5", MessageID: "13"},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	for _, id := range []string{"CMP-1", "CMP-2", "CMP-3", "CMP-4"} {
+		if err := stor.Remove(id); err != nil {
+			t.Fatalf("remove %s: %v", id, err)
+		}
+	}
+
+	type call struct {
+		method string
+		params map[string]interface{}
+	}
+	var calls []call
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&params)
+		calls = append(calls, call{path.Base(r.URL.Path), params})
+		if params["message_id"] == "12" {
+			fmt.Fprint(w, `{"ok":false,"error_code":400,"description":"Bad Request: message can't be deleted"}`)
+			return
+		}
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client(), Location: time.UTC}
+	opts := CleanupOptions{MaxAge: 30 * 24 * time.Hour, Archive: true}
+
+	// Nothing is old enough yet.
+	if err := c.CleanupResolvedMessages(stor, opts, time.Now()); err != nil || len(calls) != 0 {
+		t.Fatalf("fresh resolutions: err=%v calls=%d", err, len(calls))
+	}
+
+	later := time.Now().Add(31 * 24 * time.Hour)
+	if err := c.CleanupResolvedMessages(stor, opts, later); err != nil {
+		t.Fatalf("CleanupResolvedMessages: %v", err)
+	}
+	var methods []string
+	for _, c := range calls {
+		methods = append(methods, c.method)
+	}
+	if strings.Join(methods, " ") != "sendMessage deleteMessage deleteMessage" {
+		t.Fatalf("calls = %v, want the archive then one delete per message", methods)
+	}
+	archive := calls[0].params["text"].(string)
+	if !strings.Contains(archive, "3 resolved complaints") || !strings.Contains(archive, "<code>CMP-1</code> (Valod, Dahod) — resolved ") ||
+		strings.Contains(archive, "CMP-4") {
+		t.Errorf("archive = %q", archive)
+	}
+	if calls[1].params["message_id"] != "11" || calls[2].params["message_id"] != "12" {
+		t.Errorf("deleted %v and %v, want 11 and 12", calls[1].params["message_id"], calls[2].params["message_id"])
+	}
+
+	// Done, including the message Telegram refused: nothing left to do.
+	calls = nil
+	if err := c.CleanupResolvedMessages(stor, opts, later); err != nil || len(calls) != 0 {
+		t.Errorf("second run: err=%v calls=%v", err, calls)
+	}
+}
+
+func TestPostRollCallReplacesPreviousPin(t *testing.T) {
+	t.Chdir(t.TempDir())
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]storage.Record{{ComplaintID: "CMP-1", Village: "Valod"}, {ComplaintID: "CMP-2"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	type call struct {
+		method string
+		params map[string]interface{}
+	}
+	var calls []call
+	nextID := 40
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&params)
+		calls = append(calls, call{path.Base(r.URL.Path), params})
+		if path.Base(r.URL.Path) == "sendMessage" {
+			nextID++
+			fmt.Fprintf(w, `{"ok":true,"result":{"message_id":%d}}`, nextID)
+			return
+		}
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client()}
+
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := c.PostRollCall(stor, now.AddDate(0, 0, i)); err != nil {
+			t.Fatalf("PostRollCall: %v", err)
+		}
+	}
+
+	var methods []string
+	for _, c := range calls {
+		methods = append(methods, c.method)
+	}
+	want := []string{"sendMessage", "pinChatMessage", "sendMessage", "pinChatMessage", "unpinChatMessage"}
+	if strings.Join(methods, " ") != strings.Join(want, " ") {
+		t.Fatalf("calls = %v, want %v", methods, want)
+	}
+	if text := calls[0].params["text"].(string); !strings.Contains(text, "📋 2 pending complaints") ||
+		!strings.Contains(text, "<code>CMP-1</code> (Valod)") || !strings.Contains(text, "✅ 0 resolved yesterday") {
+		t.Errorf("roll-call text = %q", text)
+	}
+	if got := calls[3].params["message_id"]; got != "42" {
+		t.Errorf("pinned %v, want the new message 42", got)
+	}
+	if got := calls[4].params["message_id"]; got != "41" {
+		t.Errorf("unpinned %v, want yesterday's message 41", got)
+	}
+}
+
+func TestAckClaimsRestrictResolving(t *testing.T) {
+	t.Chdir(t.TempDir())
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]storage.Record{{ComplaintID: "CMP-1"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	stor.SetMessageID("CMP-1", "9")
+	stor.SetMessageText("CMP-1", "📋 Complaint : CMP-1")
+
+	var methods []string
+	var params []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&p)
+		methods = append(methods, path.Base(r.URL.Path))
+		params = append(params, p)
+		if path.Base(r.URL.Path) == "sendMessage" {
+			fmt.Fprint(w, `{"ok":true,"result":{"message_id":50}}`)
+			return
+		}
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client(),
+		AckButton: true, AckClaims: true, Admins: []string{"@boss"}}
+	msg := &IncomingMessage{MessageID: 9, Chat: &Chat{ID: -100}, Text: "📋 Complaint : CMP-1"}
+	last := func() (string, map[string]interface{}) { return methods[len(methods)-1], params[len(params)-1] }
+
+	c.handleAckCallback(&CallbackQuery{ID: "q1", From: User{ID: 1, Username: "asha"}, Message: msg, Data: "ack:CMP-1"}, "CMP-1", stor)
+	if len(methods) != 2 || methods[0] != "answerCallbackQuery" || methods[1] != "editMessageText" {
+		t.Fatalf("claim calls = %v, want an answer then a text edit", methods)
+	}
+	if text := params[1]["text"]; text != "📋 Complaint : CMP-1\n\n🛠️ Being handled by @asha" {
+		t.Errorf("claimed text = %q", text)
+	}
+
+	c.handleCallbackQuery(context.Background(), nil, &CallbackQuery{ID: "q2", From: User{ID: 2, Username: "vijay"}, Message: msg, Data: "resolve:CMP-1"}, stor)
+	if m, p := last(); m != "answerCallbackQuery" || !strings.Contains(p["text"].(string), "Being handled by @asha") {
+		t.Fatalf("resolve by someone else: last call %s %v, want a refusal", m, p)
+	}
+
+	c.handleCallbackQuery(context.Background(), nil, &CallbackQuery{ID: "q3", From: User{ID: 3, Username: "Boss"}, Message: msg, Data: "resolve:CMP-1"}, stor)
+	if m, p := last(); m != "answerCallbackQuery" || p["text"] != "Please send your remarks" {
+		t.Errorf("resolve by an admin: last call %s %v, want the remarks prompt", m, p)
+	}
+	if _, ok := stor.GetConversation(3); !ok {
+		t.Error("admin should have a pending resolution")
+	}
+}
+
+func TestQuickRemarkResolvesComplaint(t *testing.T) {
+	t.Chdir(t.TempDir())
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]storage.Record{{ComplaintID: "CMP-1", APIID: "777"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	stor.SetMessageID("CMP-1", "9")
+
+	var methods []string
+	var params []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&p)
+		methods = append(methods, path.Base(r.URL.Path))
+		params = append(params, p)
+		if path.Base(r.URL.Path) == "sendMessage" {
+			fmt.Fprint(w, `{"ok":true,"result":{"message_id":50}}`)
+			return
+		}
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client(), DebugMode: true,
+		QuickRemarks: []string{"Fuse replaced", "DO replaced", "Line fault repaired"}}
+	user := User{ID: 1, FirstName: "Asha", Username: "asha"}
+	original := &IncomingMessage{MessageID: 9, Chat: &Chat{ID: -100}, Text: "📋 Complaint : CMP-1\n👤 Ramesh\n"}
+
+	c.handleCallbackQuery(context.Background(), nil, &CallbackQuery{ID: "q1", From: user, Message: original, Data: "resolve:CMP-1"}, stor)
+	if methods[0] != "sendMessage" {
+		t.Fatalf("calls = %v, want the remarks prompt first", methods)
+	}
+	kb, _ := json.Marshal(params[0]["reply_markup"])
+	for _, want := range []string{`"Fuse replaced"`, `"remark:CMP-1:1"`, `"remark:CMP-1:custom"`, `"remark:CMP-1:cancel"`} {
+		if !strings.Contains(string(kb), want) {
+			t.Errorf("keyboard missing %s: %s", want, kb)
+		}
+	}
+
+	// Someone else cannot answer the prompt.
+	prompt := &IncomingMessage{MessageID: 50, Chat: &Chat{ID: -100}}
+	c.handleCallbackQuery(context.Background(), nil, &CallbackQuery{ID: "q2", From: User{ID: 2}, Message: prompt, Data: "remark:CMP-1:0"}, stor)
+	if !stor.Exists("CMP-1") {
+		t.Fatal("complaint resolved by a user without the prompt")
+	}
+
+	methods, params = nil, nil
+	c.handleCallbackQuery(context.Background(), nil, &CallbackQuery{ID: "q3", From: user, Message: prompt, Data: "remark:CMP-1:1"}, stor)
+	if strings.Join(methods, " ") != "deleteMessage answerCallbackQuery editMessageText" {
+		t.Fatalf("calls = %v, want the prompt deleted, an answer and the resolved edit", methods)
+	}
+	if text := params[2]["text"].(string); !strings.Contains(text, "CMP-1") || !strings.Contains(text, "Ramesh") {
+		t.Errorf("resolved text = %q", text)
+	}
+	if stor.Exists("CMP-1") {
+		t.Error("complaint should be removed once resolved")
+	}
+	if _, ok := stor.GetConversation(1); ok {
+		t.Error("pending resolution should be cleared")
+	}
+}
+
+type fakeTranscriber struct {
+	audio    []byte
+	mimeType string
+}
+
+func (f *fakeTranscriber) Transcribe(_ context.Context, audio []byte, mimeType string) (string, error) {
+	f.audio, f.mimeType = audio, mimeType
+	return " Fuse replaced at the DP ", nil
+}
+
+func TestVoiceRemarkResolvesComplaint(t *testing.T) {
+	t.Chdir(t.TempDir())
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]storage.Record{{ComplaintID: "CMP-1", APIID: "777"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	stor.SetMessageID("CMP-1", "9")
+
+	var methods []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/file/bott/voice/file_1.oga" {
+			w.Write([]byte("OggS-audio"))
+			return
+		}
+		methods = append(methods, path.Base(r.URL.Path))
+		switch path.Base(r.URL.Path) {
+		case "sendMessage":
+			fmt.Fprint(w, `{"ok":true,"result":{"message_id":50}}`)
+		case "getFile":
+			fmt.Fprint(w, `{"ok":true,"result":{"file_id":"F1","file_path":"voice/file_1.oga"}}`)
+		default:
+			fmt.Fprint(w, `{"ok":true,"result":true}`)
+		}
+	}))
+	defer srv.Close()
+	transcriber := &fakeTranscriber{}
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client(), DebugMode: true, Transcriber: transcriber}
+	user := User{ID: 1, FirstName: "Asha"}
+
+	c.handleCallbackQuery(context.Background(), nil, &CallbackQuery{ID: "q1", From: user, Message: &IncomingMessage{MessageID: 9, Chat: &Chat{ID: -100}}, Data: "resolve:CMP-1"}, stor)
+
+	// A voice note that is not a reply to the prompt is ignored.
+	c.handleMessage(context.Background(), nil, &IncomingMessage{MessageID: 60, From: &user, Voice: &Voice{FileID: "F0"}}, stor)
+	if !stor.Exists("CMP-1") {
+		t.Fatal("voice note outside the prompt resolved the complaint")
+	}
+
+	c.handleMessage(context.Background(), nil, &IncomingMessage{
+		MessageID:      61,
+		From:           &user,
+		Voice:          &Voice{FileID: "F1", Duration: 4},
+		ReplyToMessage: &IncomingMessage{MessageID: 50},
+	}, stor)
+	if stor.Exists("CMP-1") {
+		t.Fatalf("complaint not resolved; calls = %v", methods)
+	}
+	if string(transcriber.audio) != "OggS-audio" || transcriber.mimeType != "audio/ogg" {
+		t.Errorf("transcriber got %q (%s)", transcriber.audio, transcriber.mimeType)
+	}
+	e, ok, err := stor.GetResolvedHistory("CMP-1")
+	if err != nil || !ok || e.VoiceNoteFileID != "F1" {
+		t.Errorf("history = %+v, %v, %v; want voice note F1", e, ok, err)
+	}
+
+	c.Transcriber = nil
+	if got := c.transcribeVoice(context.Background(), &Voice{FileID: "F1"}); got != voiceNoteRemark {
+		t.Errorf("without a transcriber got %q, want %q", got, voiceNoteRemark)
+	}
+}
+
+func TestPhotoRemarkSavesAttachment(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]storage.Record{{ComplaintID: "CMP/1", APIID: "777"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	stor.SetMessageID("CMP/1", "9")
+
+	var fileIDs []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/file/bott/photos/file_2.jpg" {
+			w.Write([]byte("JPEG-large"))
+			return
+		}
+		switch path.Base(r.URL.Path) {
+		case "sendMessage":
+			fmt.Fprint(w, `{"ok":true,"result":{"message_id":50}}`)
+		case "getFile":
+			var req struct {
+				FileID string `json:"file_id"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			fileIDs = append(fileIDs, req.FileID)
+			fmt.Fprint(w, `{"ok":true,"result":{"file_path":"photos/file_2.jpg"}}`)
+		default:
+			fmt.Fprint(w, `{"ok":true,"result":true}`)
+		}
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client(), DebugMode: true, AttachmentsDir: "attachments"}
+	user := User{ID: 1, FirstName: "Asha"}
+
+	c.handleCallbackQuery(context.Background(), nil, &CallbackQuery{ID: "q1", From: user, Message: &IncomingMessage{MessageID: 9, Chat: &Chat{ID: -100}}, Data: "resolve:CMP/1"}, stor)
+	c.handleMessage(context.Background(), nil, &IncomingMessage{
+		MessageID:      61,
+		From:           &user,
+		Photo:          []PhotoSize{{FileID: "P-small", FileUniqueID: "u1"}, {FileID: "P-large", FileUniqueID: "u2"}},
+		Caption:        "Fuse replaced",
+		ReplyToMessage: &IncomingMessage{MessageID: 50},
+	}, stor)
+	if stor.Exists("CMP/1") {
+		t.Fatal("complaint not resolved")
+	}
+	if len(fileIDs) != 1 || fileIDs[0] != "P-large" {
+		t.Errorf("downloaded %v, want the largest size only", fileIDs)
+	}
+
+	want := filepath.Join("attachments", "CMP_1", "u2.jpg")
+	e, ok, err := stor.GetResolvedHistory("CMP/1")
+	if err != nil || !ok || len(e.Attachments) != 1 || e.Attachments[0] != want {
+		t.Fatalf("history = %+v, %v, %v; want attachment %s", e, ok, err, want)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, want)); err != nil || string(data) != "JPEG-large" {
+		t.Errorf("saved photo = %q, %v", data, err)
+	}
+}
+
+func TestResolveConversationStepsAndExpires(t *testing.T) {
+	t.Chdir(t.TempDir())
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]storage.Record{{ComplaintID: "CMP-1", APIID: "777"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	stor.SetMessageID("CMP-1", "9")
+
+	var methods []string
+	var params []map[string]interface{}
+	nextID := 50
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&p)
+		methods = append(methods, path.Base(r.URL.Path))
+		params = append(params, p)
+		if path.Base(r.URL.Path) == "sendMessage" {
+			fmt.Fprintf(w, `{"ok":true,"result":{"message_id":%d}}`, nextID)
+			nextID++
+			return
+		}
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client(), DebugMode: true,
+		QuickRemarks: []string{"Fuse replaced"}, ConversationTimeout: time.Hour}
+	user := User{ID: 1, FirstName: "Asha"}
+	original := &IncomingMessage{MessageID: 9, Chat: &Chat{ID: -100}, Text: "📋 Complaint : CMP-1\n"}
+
+	c.handleCallbackQuery(context.Background(), nil, &CallbackQuery{ID: "q1", From: user, Message: original, Data: "resolve:CMP-1"}, stor)
+	if conv, ok := stor.GetConversation(1); !ok || conv.Flow != "resolve" || conv.State != resolveRemarks || conv.PromptMessageID != 50 {
+		t.Fatalf("after resolve: conversation %+v, %v", conv, ok)
+	}
+
+	// Custom… swaps the keyboard for the typed-note prompt.
+	methods, params = nil, nil
+	c.handleCallbackQuery(context.Background(), nil, &CallbackQuery{ID: "q2", From: user, Message: &IncomingMessage{MessageID: 50}, Data: "remark:CMP-1:custom"}, stor)
+	if strings.Join(methods, " ") != "deleteMessage sendMessage answerCallbackQuery" {
+		t.Fatalf("custom calls = %v", methods)
+	}
+	if _, ok := params[1]["reply_markup"].(map[string]interface{})["force_reply"]; !ok {
+		t.Errorf("note prompt should force a reply: %v", params[1])
+	}
+	conv, ok := stor.GetConversation(1)
+	if !ok || conv.State != resolveNote || conv.PromptMessageID != 51 {
+		t.Fatalf("after custom: conversation %+v, %v", conv, ok)
+	}
+
+	// Left unanswered past the timeout, the reply no longer resolves.
+	conv.UpdatedAt = time.Now().Add(-2 * time.Hour)
+	if err := stor.SaveConversation(1, conv); err != nil {
+		t.Fatalf("age conversation: %v", err)
+	}
+	methods, params = nil, nil
+	c.handleMessage(context.Background(), nil, &IncomingMessage{MessageID: 70, From: &user, Text: "Fuse replaced", ReplyToMessage: &IncomingMessage{MessageID: 51}}, stor)
+	if !stor.Exists("CMP-1") {
+		t.Fatal("an expired prompt resolved the complaint")
+	}
+	if _, ok := stor.GetConversation(1); ok || len(methods) != 1 || methods[0] != "deleteMessage" {
+		t.Errorf("expired conversation: still saved=%v, calls %v; want it dropped and its prompt deleted", ok, methods)
+	}
+
+	// The update loop's sweep drops idle prompts without waiting for a reply.
+	c.handleCallbackQuery(context.Background(), nil, &CallbackQuery{ID: "q3", From: user, Message: original, Data: "resolve:CMP-1"}, stor)
+	methods, params = nil, nil
+	c.expireConversations(stor, time.Now().Add(2*time.Hour))
+	if _, ok := stor.GetConversation(1); ok || len(methods) != 1 || methods[0] != "deleteMessage" {
+		t.Errorf("after sweep: still saved=%v, calls %v", ok, methods)
+	}
+}
+
+func TestUndoRestoresResolvedComplaint(t *testing.T) {
+	t.Chdir(t.TempDir())
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]storage.Record{{ComplaintID: "CMP-1", APIID: "777", Village: "Valod"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	stor.SetMessageID("CMP-1", "9")
+	stor.SetMessageText("CMP-1", "📋 Complaint : <b>CMP-1</b>")
+
+	calls := make(chan [2]string, 32)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls <- [2]string{path.Base(r.URL.Path), string(body)}
+		if path.Base(r.URL.Path) == "sendMessage" {
+			fmt.Fprint(w, `{"ok":true,"result":{"message_id":50}}`)
+			return
+		}
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client(), DebugMode: true, UndoWindow: time.Minute}
+	var reopened []notify.Complaint
+	c.Reopened = func(complaint notify.Complaint) error {
+		reopened = append(reopened, complaint)
+		return nil
+	}
+	user := User{ID: 1, FirstName: "Asha"}
+	drain := func() (out [][2]string) {
+		for {
+			select {
+			case call := <-calls:
+				out = append(out, call)
+			default:
+				return out
+			}
+		}
+	}
+	resolve := func() {
+		c.handleCallbackQuery(context.Background(), nil, &CallbackQuery{ID: "q1", From: user, Message: &IncomingMessage{MessageID: 9, Text: "📋 Complaint : CMP-1"}, Data: "resolve:CMP-1"}, stor)
+		c.handleMessage(context.Background(), nil, &IncomingMessage{MessageID: 61, From: &user, Text: "Fuse replaced", ReplyToMessage: &IncomingMessage{MessageID: 50}}, stor)
+	}
+
+	resolve()
+	got := drain()
+	if last := got[len(got)-1]; last[0] != "editMessageText" || !strings.Contains(last[1], `"undo:CMP-1"`) || !strings.Contains(last[1], "Undo (1m)") {
+		t.Fatalf("resolved edit = %v, want an Undo button", last)
+	}
+	if stor.Exists("CMP-1") {
+		t.Fatal("complaint should be removed once resolved")
+	}
+
+	// Only the resolver (or an admin) may undo.
+	c.handleCallbackQuery(context.Background(), nil, &CallbackQuery{ID: "q2", From: User{ID: 2, FirstName: "Ravi"}, Message: &IncomingMessage{MessageID: 9}, Data: "undo:CMP-1"}, stor)
+	if got = drain(); len(got) != 1 || !strings.Contains(got[0][1], "Only Asha or an admin") || stor.Exists("CMP-1") || len(reopened) != 0 {
+		t.Fatalf("undo by another user = %v, want it refused", got)
+	}
+
+	c.handleCallbackQuery(context.Background(), nil, &CallbackQuery{ID: "q2", From: user, Message: &IncomingMessage{MessageID: 9}, Data: "undo:CMP-1"}, stor)
+	if len(reopened) != 1 || reopened[0].Number != "CMP-1" || reopened[0].Village != "Valod" {
+		t.Errorf("reopened = %+v, want CMP-1 published again", reopened)
+	}
+	if !stor.Exists("CMP-1") || stor.GetAPIID("CMP-1") != "777" || stor.GetVillage("CMP-1") != "Valod" || stor.GetMessageID("CMP-1") != "9" {
+		t.Fatal("undo should restore the complaint to storage")
+	}
+	if _, ok, _ := stor.GetResolvedHistory("CMP-1"); ok {
+		t.Error("undone complaint should be open again in history")
+	}
+	got = drain()
+	if len(got) != 2 || got[0][0] != "editMessageText" || !strings.Contains(got[0][1], `\u003cb\u003eCMP-1`) || !strings.Contains(got[0][1], `"resolve:CMP-1"`) {
+		t.Fatalf("undo calls = %v, want the active message restored", got)
+	}
+
+	c.handleCallbackQuery(context.Background(), nil, &CallbackQuery{ID: "q3", From: user, Message: &IncomingMessage{MessageID: 9}, Data: "undo:CMP-1"}, stor)
+	if got = drain(); !strings.Contains(got[0][1], "Too late to undo") {
+		t.Errorf("second undo = %v, want it refused", got)
+	}
+
+	// Once the window passes the button goes and the resolution stands.
+	c.UndoWindow = 10 * time.Millisecond
+	resolve()
+	drain()
+	select {
+	case call := <-calls:
+		if call[0] != "editMessageReplyMarkup" {
+			t.Errorf("after the window got %v, want the Undo button removed", call)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Undo button was not removed after the window")
+	}
+	if stor.Exists("CMP-1") {
+		t.Error("expired undo should leave the complaint resolved")
+	}
+}
+
+func TestResolveAllConfirmsThenResolves(t *testing.T) {
+	t.Chdir(t.TempDir())
+	defer func(d time.Duration) { bulkResolvePause = d }(bulkResolvePause)
+	bulkResolvePause = 0
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]storage.Record{
+		{ComplaintID: "CMP-1", APIID: "1", Area: "Valod"},
+		{ComplaintID: "CMP-2", APIID: "2", Area: "VALOD "},
+		{ComplaintID: "CMP-3", APIID: "3", Area: "Bardoli"},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	calls := make(chan [2]string, 64)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls <- [2]string{path.Base(r.URL.Path), string(body)}
+		if path.Base(r.URL.Path) == "sendMessage" {
+			fmt.Fprint(w, `{"ok":true,"result":{"message_id":70}}`)
+			return
+		}
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client(), DebugMode: true, Admins: []string{"1"}}
+	var published []string
+	c.Resolved = func(s notify.Status) error {
+		// Published while the complaint is still stored, for subscribers
+		// that look it up.
+		if stor.Exists(s.ComplaintID) && s.Channel == "telegram" {
+			published = append(published, s.ComplaintID+": "+s.Remark)
+		}
+		return nil
+	}
+	drain := func() (out [][2]string) {
+		for {
+			select {
+			case call := <-calls:
+				out = append(out, call)
+			default:
+				return out
+			}
+		}
+	}
+	admin := User{ID: 1, FirstName: "Asha"}
+
+	c.handleMessage(context.Background(), nil, &IncomingMessage{MessageID: 5, From: &User{ID: 2, FirstName: "Ravi"}, Text: "/resolveall valod"}, stor)
+	if got := drain(); len(got) != 1 || !strings.Contains(got[0][1], "Only admins") {
+		t.Fatalf("non-admin /resolveall = %v, want it refused", got)
+	}
+
+	c.handleMessage(context.Background(), nil, &IncomingMessage{MessageID: 6, From: &admin, Text: "/resolveall valod | Feeder restored"}, stor)
+	got := drain()
+	if len(got) != 1 || !strings.Contains(got[0][1], `"resolveall::yes"`) || !strings.Contains(got[0][1], "CMP-1, CMP-2") || strings.Contains(got[0][1], "CMP-3") {
+		t.Fatalf("confirmation prompt = %v, want CMP-1 and CMP-2 with a Resolve button", got)
+	}
+	if !stor.Exists("CMP-1") {
+		t.Fatal("nothing should be resolved before confirming")
+	}
+
+	c.handleCallbackQuery(context.Background(), nil, &CallbackQuery{ID: "q1", From: admin, Message: &IncomingMessage{MessageID: 70}, Data: "resolveall::yes"}, stor)
+	if stor.Exists("CMP-1") || stor.Exists("CMP-2") || !stor.Exists("CMP-3") {
+		t.Fatal("only the Valod complaints should be resolved")
+	}
+	if _, ok, _ := stor.GetResolvedHistory("CMP-2"); !ok {
+		t.Error("CMP-2 should be resolved in history")
+	}
+	if len(published) != 2 || published[0] != "CMP-1: Feeder restored" || published[1] != "CMP-2: Feeder restored" {
+		t.Errorf("published resolutions = %v, want CMP-1 and CMP-2 before removal", published)
+	}
+	got = drain()
+	if last := got[len(got)-1]; last[0] != "editMessageText" || !strings.Contains(last[1], "Resolved 2/2 complaints in valod") {
+		t.Errorf("final progress = %v, want 2/2 resolved", last)
+	}
+	if _, ok := stor.GetConversation(admin.ID); ok {
+		t.Error("conversation should end after the bulk resolve")
+	}
+}
+
+func TestReplyToComplaintAddsNote(t *testing.T) {
+	t.Chdir(t.TempDir())
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]storage.Record{{ComplaintID: "CMP-1", APIID: "1"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	stor.SetMessageID("CMP-1", "9")
+	stor.SetMessageText("CMP-1", "📋 Complaint : <b>CMP-1</b>")
+
+	calls := make(chan [2]string, 16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls <- [2]string{path.Base(r.URL.Path), string(body)}
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client(), DebugMode: true}
+	user := User{ID: 1, FirstName: "Asha"}
+
+	c.handleMessage(context.Background(), nil, &IncomingMessage{MessageID: 20, From: &user, Text: "Lineman dispatched 14:30", ReplyToMessage: &IncomingMessage{MessageID: 9}}, stor)
+	notes, _ := stor.GetNotes("CMP-1")
+	if len(notes) != 1 || notes[0].Author != "Asha" || notes[0].Text != "Lineman dispatched 14:30" {
+		t.Fatalf("notes = %+v, want the reply", notes)
+	}
+	select {
+	case call := <-calls:
+		if call[0] != "editMessageText" || !strings.Contains(call[1], "Lineman dispatched 14:30") || !strings.Contains(call[1], `"resolve:CMP-1"`) {
+			t.Errorf("edit = %v, want the note under the complaint with its buttons", call)
+		}
+	default:
+		t.Fatal("complaint message was not updated")
+	}
+
+	// Replies to other messages are not notes.
+	c.handleMessage(context.Background(), nil, &IncomingMessage{MessageID: 21, From: &user, Text: "ok", ReplyToMessage: &IncomingMessage{MessageID: 10}}, stor)
+	if notes, _ := stor.GetNotes("CMP-1"); len(notes) != 1 {
+		t.Errorf("notes = %+v, want the unrelated reply ignored", notes)
+	}
+}
+
+func TestNotesBlockShowsLatest(t *testing.T) {
+	var notes []storage.Note
+	for i := 1; i <= maxNotesShown+2; i++ {
+		notes = append(notes, storage.Note{Author: "A<b>", Text: fmt.Sprintf("step %d", i)})
+	}
+	got := notesBlock(notes, time.UTC)
+	if !strings.Contains(got, "(2 earlier not shown)") || strings.Count(got, "•") != maxNotesShown || !strings.Contains(got, ": step 3\n") {
+		t.Errorf("notesBlock = %q", got)
+	}
+	if !strings.Contains(got, "A&lt;b&gt;") {
+		t.Errorf("notesBlock = %q, want authors escaped", got)
+	}
+}
+
+func TestDigestBundlesLargeBatch(t *testing.T) {
+	t.Chdir(t.TempDir())
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]storage.Record{
+		{ComplaintID: "CMP-1", ConsumerName: "Ramesh"},
+		{ComplaintID: "CMP-2", ConsumerName: "Suresh"},
+		{ComplaintID: "CMP-3", ConsumerName: "Mahesh"},
+	}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	calls := make(chan [2]string, 16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls <- [2]string{path.Base(r.URL.Path), string(body)}
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":50}}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client(), DebugMode: true, DigestThreshold: 2}
+	n := c.AsNotifier(stor)
+
+	err = n.SendComplaints([]notify.Complaint{
+		{Number: "CMP-1", ComplainantName: "Ramesh", Village: "Valod"},
+		{Number: "CMP-2", ComplainantName: "Suresh <b>"},
+		{Number: "CMP-3", ComplainantName: "Mahesh"},
+	})
+	if err != nil {
+		t.Fatalf("SendComplaints: %v", err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("sent %d messages, want one digest", len(calls))
+	}
+	call := <-calls
+	if call[0] != "sendMessage" || !strings.Contains(call[1], "3 new complaints") || !strings.Contains(call[1], `"resolve:CMP-3"`) || !strings.Contains(call[1], "Suresh \\u0026lt;b\\u0026gt;") {
+		t.Errorf("digest = %v", call)
+	}
+	for _, id := range []string{"CMP-1", "CMP-2", "CMP-3"} {
+		if got := stor.GetMessageID(id); got != "50" {
+			t.Errorf("message ID of %s = %q, want the digest's", id, got)
+		}
+	}
+
+	// Resolving a member redraws the digest without it.
+	if err := n.EditStatus(notify.Status{ComplaintID: "CMP-1"}); err != nil {
+		t.Fatalf("EditStatus: %v", err)
+	}
+	call = <-calls
+	if call[0] != "editMessageText" || !strings.Contains(call[1], "2 complaints still pending") || strings.Contains(call[1], "CMP-1") {
+		t.Errorf("redraw = %v, want the digest without CMP-1", call)
+	}
+
+	stor.Remove("CMP-1")
+	stor.Remove("CMP-2")
+	n.EditStatus(notify.Status{ComplaintID: "CMP-3"})
+	if call = <-calls; !strings.Contains(call[1], "Every complaint in this digest is resolved") || strings.Contains(call[1], "resolve:") {
+		t.Errorf("last redraw = %v, want the digest closed", call)
+	}
+
+	// A batch at the threshold gets a message each.
+	n.SendComplaints([]notify.Complaint{{Number: "CMP-4"}, {Number: "CMP-5"}})
+	if len(calls) != 2 {
+		t.Errorf("sent %d messages for a small batch, want 2", len(calls))
+	}
+}
+
+func TestInQuietHours(t *testing.T) {
+	at := func(hhmm string) time.Time {
+		tm, _ := time.Parse("15:04", hhmm)
+		return tm
+	}
+	overnight := &Client{QuietStart: "22:00", QuietEnd: "07:00", Location: time.UTC}
+	for hhmm, want := range map[string]bool{"23:00": true, "06:59": true, "07:00": false, "12:00": false, "22:00": true} {
+		if got := overnight.InQuietHours(at(hhmm)); got != want {
+			t.Errorf("22:00-07:00 at %s = %v, want %v", hhmm, got, want)
+		}
+	}
+	daytime := &Client{QuietStart: "13:00", QuietEnd: "14:00", Location: time.UTC}
+	if !daytime.InQuietHours(at("13:30")) || daytime.InQuietHours(at("14:30")) {
+		t.Error("13:00-14:00 should cover 13:30 only")
+	}
+	ist := &Client{QuietStart: "22:00", QuietEnd: "07:00", Location: time.FixedZone("IST", 5*3600+1800)}
+	if !ist.InQuietHours(at("17:00")) || ist.InQuietHours(at("02:00")) {
+		t.Error("quiet hours should be read in Location: 17:00 UTC is 22:30 IST, 02:00 UTC is 07:30 IST")
+	}
+	if (&Client{}).InQuietHours(at("03:00")) {
+		t.Error("no quiet hours configured should never be quiet")
+	}
+}
+
+func TestQuietHoursHoldRoutineComplaints(t *testing.T) {
+	t.Chdir(t.TempDir())
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]storage.Record{{ComplaintID: "CMP-1"}, {ComplaintID: "CMP-2"}, {ComplaintID: "CMP-3"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	calls := make(chan [2]string, 16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls <- [2]string{path.Base(r.URL.Path), string(body)}
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":60}}`)
+	}))
+	defer srv.Close()
+	now := time.Now()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client(), DebugMode: true,
+		QuietStart: now.Add(-time.Hour).Format("15:04"), QuietEnd: now.Add(time.Hour).Format("15:04"), UrgentKeywords: []string{"wire down"}}
+	n := c.AsNotifier(stor)
+
+	err = n.SendComplaints([]notify.Complaint{
+		{Number: "CMP-1", Description: "No supply"},
+		{Number: "CMP-2", Description: "Low voltage"},
+		{Number: "CMP-3", Description: "Wire down near school"},
+	})
+	if err != nil {
+		t.Fatalf("SendComplaints: %v", err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("sent %d messages in quiet hours, want only the urgent one", len(calls))
+	}
+	if call := <-calls; !strings.Contains(call[1], "CMP-3") {
+		t.Errorf("sent %v, want the urgent complaint", call)
+	}
+	if held, _ := stor.GetDeferredSends(); len(held) != 2 {
+		t.Fatalf("held %d complaints, want 2", len(held))
+	}
+
+	// Nothing goes out while quiet hours last.
+	n.FlushDeferred()
+	if len(calls) != 0 {
+		t.Fatalf("flushed during quiet hours")
+	}
+
+	c.QuietStart, c.QuietEnd = "", ""
+	n.FlushDeferred()
+	if len(calls) != 1 {
+		t.Fatalf("sent %d messages after quiet hours, want one digest", len(calls))
+	}
+	if call := <-calls; !strings.Contains(call[1], "2 new complaints") {
+		t.Errorf("morning message = %v, want a digest of both", call)
+	}
+	if stor.GetMessageID("CMP-1") != "60" || stor.GetMessageID("CMP-2") != "60" {
+		t.Error("held complaints should point at the digest")
+	}
+	if held, _ := stor.GetDeferredSends(); len(held) != 0 {
+		t.Errorf("queue still holds %+v", held)
+	}
+}
+
+func TestNotifierEditTranslation(t *testing.T) {
+	t.Chdir(t.TempDir())
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]storage.Record{{ComplaintID: "CMP-1"}, {ComplaintID: "CMP-2"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	stor.SetMessageID("CMP-1", "9")
+	stor.SetMessageText("CMP-1", "📋 Complaint CMP-1")
+	stor.MarkAcknowledged("CMP-1", "Asha", 1)
+
+	var edits []EditMessageRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req EditMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		edits = append(edits, req)
+		fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+	c := &Client{BotToken: "t", ChatID: "-100", apiBase: srv.URL, httpClient: srv.Client(), AckButton: true}
+	n := c.AsNotifier(stor)
+
+	tr := notify.Complaint{Number: "CMP-1", ComplainantName: "Ramesh", NameGu: "રમેશ", Translation: "👤 રમેશ"}
+	if err := n.EditTranslation(tr); err != nil {
+		t.Fatalf("EditTranslation: %v", err)
+	}
+	// CMP-2 has no message (its send failed, say): nothing to edit.
+	if err := n.EditTranslation(notify.Complaint{Number: "CMP-2", NameGu: "નામ"}); err != nil {
+		t.Fatalf("EditTranslation without a message: %v", err)
+	}
+	if len(edits) != 1 {
+		t.Fatalf("edits = %d, want 1", len(edits))
+	}
+	e := edits[0]
+	if e.MessageID != "9" || !strings.Contains(e.Text, "રમેશ") {
+		t.Errorf("edit = %+v, want message 9 with the Gujarati name", e)
+	}
+	if e.ReplyMarkup == nil || e.ReplyMarkup.InlineKeyboard[0][1].Text != "👀 Asha" {
+		t.Errorf("keyboard = %+v, want it kept with the acknowledgment", e.ReplyMarkup)
+	}
+	if text, _, _ := stor.GetMessageText("CMP-1"); !strings.Contains(text, "રમેશ") {
+		t.Errorf("stored text = %q, want the translated message kept for later edits", text)
+	}
+}
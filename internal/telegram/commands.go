@@ -4,10 +4,16 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"time"
 
 	"cmon/internal/belt"
+	"cmon/internal/buildinfo"
 	"cmon/internal/complaintid"
+	"cmon/internal/health"
+	"cmon/internal/i18n"
+	"cmon/internal/quality"
 	"cmon/internal/session"
 	"cmon/internal/storage"
 	"cmon/internal/summary"
@@ -22,20 +28,66 @@ import (
 // in the chat. Exposed for the scheduler in main.go; never call it from a
 // user-message handler (those go through the existing dispatch).
 func (c *Client) PostScheduledSummary(ctx context.Context, sc *session.Client, stor *storage.Storage) {
-	c.handleSummaryCommand(ctx, sc, stor)
+	complaints := c.handleSummaryCommand(ctx, sc, stor, nil)
+	if c.SummaryMap {
+		c.sendSummaryMap(complaints)
+	}
+	c.sendDataQualityReport(stor)
+}
+
+// sendSummaryMap follows the scheduled summary with a map of geocoded
+// pending complaints. Skipped quietly when nothing has coordinates.
+func (c *Client) sendSummaryMap(complaints []summary.Complaint) {
+	if len(complaints) == 0 {
+		return
+	}
+	imgBytes, err := summary.RenderMap(complaints)
+	if err != nil {
+		log.Printf("ℹ️  Summary map skipped: %v\n", err)
+		return
+	}
+	if err := c.SendPhoto(c.ChatID, imgBytes, "🗺️ Pending complaint locations"); err != nil {
+		log.Printf("⚠️  Failed to send summary map: %v\n", err)
+	}
+}
+
+// sendDataQualityReport appends the daily data-quality section to the
+// scheduled report: today's complaints (in Location) whose intake data was flagged.
+// Sends nothing when the day is clean.
+func (c *Client) sendDataQualityReport(stor *storage.Storage) {
+	now := time.Now().In(c.location())
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	flagged, err := stor.GetFlaggedComplaintsSince(dayStart)
+	if err != nil {
+		log.Printf("⚠️  Failed to load data-quality report: %v\n", err)
+		return
+	}
+	lines := quality.ReportLines(flagged)
+	if len(lines) == 0 {
+		return
+	}
+	for i := range lines {
+		lines[i] = htmlEscape(lines[i])
+	}
+	lines[0] = "<b>" + lines[0] + "</b>"
+	c.sendTextMessage(strings.Join(lines, "\n"), "HTML")
 }
 
 // handleSummaryCommand processes the /summary command — fetches all pending
-// complaints and sends a single combined PNG summary back to the chat.
-func (c *Client) handleSummaryCommand(ctx context.Context, sc *session.Client, stor *storage.Storage) {
+// complaints, keeps those filter matches (all when nil), and sends the
+// combined PNG summary back to the chat.
+// Returns the complaints that were rendered (nil on failure) so the
+// scheduled summary can reuse them.
+func (c *Client) handleSummaryCommand(ctx context.Context, sc *session.Client, stor *storage.Storage, filter *summary.Filter) []summary.Complaint {
 	log.Println("📊 /summary command received")
 
 	processingMsg := Message{
 		ChatID:    c.ChatID,
-		Text:      "📊 <b>Generating summary...</b>\nFetching details for all pending complaints.",
+		Text:      i18n.T("summary.generating"),
 		ParseMode: "HTML",
 	}
-	c.doRequest("sendMessage", processingMsg)
+	c.send("sendMessage", processingMsg)
 
 	// Fetch all pending complaint details
 	complaints, err := summary.FetchAllPendingDetails(sc, stor)
@@ -43,87 +95,99 @@ func (c *Client) handleSummaryCommand(ctx context.Context, sc *session.Client, s
 		log.Printf("⚠️  Summary fetch failed: %v\n", err)
 		noDataMsg := Message{
 			ChatID:    c.ChatID,
-			Text:      "ℹ️ No pending complaints found.",
+			Text:      i18n.T("summary.none"),
 			ParseMode: "HTML",
 		}
-		c.doRequest("sendMessage", noDataMsg)
-		return
+		c.send("sendMessage", noDataMsg)
+		return nil
+	}
+	if complaints = filter.Apply(complaints); len(complaints) == 0 {
+		c.sendTextMessage(i18n.T("summary.none_match", htmlEscape(filter.String())), "HTML")
+		return nil
 	}
 
-	// Render combined table image
-	imgBytes, err := summary.RenderTable(complaints)
+	// Render combined table images, one per page
+	pages, err := summary.RenderTable(c.summaryRows(complaints))
 	if err != nil {
 		log.Printf("⚠️  Summary render failed: %v\n", err)
 		errorMsg := Message{
 			ChatID:    c.ChatID,
-			Text:      fmt.Sprintf("❌ Failed to render summary image: %v", err),
+			Text:      i18n.T("summary.render_failed", htmlEscape(err.Error())),
 			ParseMode: "HTML",
 		}
-		c.doRequest("sendMessage", errorMsg)
-		return
+		c.send("sendMessage", errorMsg)
+		return nil
 	}
 
-	caption := fmt.Sprintf("📋 %d Pending Complaints", len(complaints))
-	if err := c.SendPhoto(c.ChatID, imgBytes, caption); err != nil {
-		log.Printf("⚠️  Failed to send summary photo: %v\n", err)
-		errorMsg := Message{
-			ChatID:    c.ChatID,
-			Text:      fmt.Sprintf("❌ Failed to send summary image: %v", err),
-			ParseMode: "HTML",
+	for i, imgBytes := range pages {
+		caption := i18n.T("summary.caption", len(complaints)) + filterCaption(filter) + pageCaption(i+1, len(pages))
+		if err := c.SendImage(c.ChatID, imgBytes, caption, fmt.Sprintf("summary-%d.png", i+1)); err != nil {
+			log.Printf("⚠️  Failed to send summary photo: %v\n", err)
+			errorMsg := Message{
+				ChatID:    c.ChatID,
+				Text:      i18n.T("summary.send_failed", htmlEscape(err.Error())),
+				ParseMode: "HTML",
+			}
+			c.send("sendMessage", errorMsg)
+			return nil
 		}
-		c.doRequest("sendMessage", errorMsg)
-		return
 	}
 
 	log.Println("✓ Summary image sent successfully")
+	return complaints
 }
 
 // handleSummaryBeltCommand processes the /summarybelt command, sending one
-// image per belt instead of a single combined image.
-func (c *Client) handleSummaryBeltCommand(ctx context.Context, sc *session.Client, stor *storage.Storage) {
+// image per belt instead of a single combined image. filter narrows it as
+// for /summary.
+func (c *Client) handleSummaryBeltCommand(ctx context.Context, sc *session.Client, stor *storage.Storage, filter *summary.Filter) {
 	log.Println("📊 /summarybelt command received")
 
 	processingMsg := Message{
 		ChatID:    c.ChatID,
-		Text:      "📊 <b>Generating belt-wise summary...</b>\nRendering one image per belt.",
+		Text:      i18n.T("summary.generating_belt"),
 		ParseMode: "HTML",
 	}
-	c.doRequest("sendMessage", processingMsg)
+	c.send("sendMessage", processingMsg)
 
 	complaints, err := summary.FetchAllPendingDetails(sc, stor)
 	if err != nil {
 		log.Printf("⚠️  Belt summary fetch failed: %v\n", err)
 		noDataMsg := Message{
 			ChatID:    c.ChatID,
-			Text:      "ℹ️ No pending complaints found.",
+			Text:      i18n.T("summary.none"),
 			ParseMode: "HTML",
 		}
-		c.doRequest("sendMessage", noDataMsg)
+		c.send("sendMessage", noDataMsg)
+		return
+	}
+	if complaints = filter.Apply(complaints); len(complaints) == 0 {
+		c.sendTextMessage(i18n.T("summary.none_match", htmlEscape(filter.String())), "HTML")
 		return
 	}
 
-	beltImages, err := summary.RenderTablesByBelt(complaints)
+	beltImages, err := summary.RenderTablesByBelt(c.summaryRows(complaints))
 	if err != nil {
 		log.Printf("⚠️  Belt summary render failed: %v\n", err)
 		errorMsg := Message{
 			ChatID:    c.ChatID,
-			Text:      fmt.Sprintf("❌ Failed to render belt summary images: %v", err),
+			Text:      i18n.T("summary.render_belt_failed", htmlEscape(err.Error())),
 			ParseMode: "HTML",
 		}
-		c.doRequest("sendMessage", errorMsg)
+		c.send("sendMessage", errorMsg)
 		return
 	}
 
 	for _, bi := range beltImages {
-		caption := fmt.Sprintf("📋 %s Belt — %d Pending Complaints", bi.Label, bi.Count)
-		if err := c.SendPhoto(c.ChatID, bi.PNG, caption); err != nil {
+		caption := i18n.T("summary.caption_belt", bi.Label, bi.Count) + filterCaption(filter) + pageCaption(bi.Page, bi.Pages)
+		if err := c.SendImage(c.ChatID, bi.PNG, caption, fmt.Sprintf("summary-%s-%d.png", bi.Belt, bi.Page)); err != nil {
 			log.Printf("⚠️  Failed to send %s belt summary photo: %v\n", bi.Label, err)
 			errorMsg := Message{
 				ChatID:    c.ChatID,
-				Text:      fmt.Sprintf("❌ Failed to send %s belt summary image: %v", bi.Label, err),
+				Text:      i18n.T("summary.send_belt_failed", htmlEscape(bi.Label), htmlEscape(err.Error())),
 				ParseMode: "HTML",
 			}
-			c.doRequest("sendMessage", errorMsg)
+			c.send("sendMessage", errorMsg)
 			continue
 		}
 	}
@@ -132,6 +196,63 @@ func (c *Client) handleSummaryBeltCommand(ctx context.Context, sc *session.Clien
 		len(beltImages), len(complaints))
 }
 
+// filterCaption names the filter a summary was narrowed by, if any.
+func filterCaption(filter *summary.Filter) string {
+	if filter == nil {
+		return ""
+	}
+	return i18n.T("summary.matching", htmlEscape(filter.String()))
+}
+
+// summaryCommand splits a /summary or /summarybelt message into the
+// command and the filter expression after it.
+func summaryCommand(text string) (cmd, filter string, ok bool) {
+	text = strings.TrimSpace(text)
+	cmd, filter, _ = strings.Cut(text, " ")
+	if cmd != "/summary" && cmd != "/summarybelt" {
+		return "", "", false
+	}
+	return cmd, strings.TrimSpace(filter), true
+}
+
+// handleSummaryRequest parses a /summary or /summarybelt filter and runs
+// the command, or explains the filter syntax when it does not parse.
+func (c *Client) handleSummaryRequest(ctx context.Context, sc *session.Client, stor *storage.Storage, cmd, expr string) {
+	filter, err := summary.ParseFilter(expr)
+	if err != nil {
+		c.sendSummaryFilterUsage(err)
+		return
+	}
+	if cmd == "/summarybelt" {
+		c.handleSummaryBeltCommand(ctx, sc, stor, filter)
+		return
+	}
+	c.handleSummaryCommand(ctx, sc, stor, filter)
+}
+
+// sendSummaryFilterUsage answers a /summary filter that did not parse.
+func (c *Client) sendSummaryFilterUsage(err error) {
+	c.sendTextMessage(
+		fmt.Sprintf(
+			"❌ %s\n\n"+
+				"Usage: <code>/summary [filter ...]</code>, e.g. <code>/summary area=Valod age&gt;24h</code>\n"+
+				"Filters: <code>area</code>, <code>village</code>, <code>belt</code>, <code>description</code> with = != ~ (contains); "+
+				"<code>age</code> with &gt; &gt;= &lt; &lt;= and a duration like 90m, 24h or 3d. Quote values with spaces.",
+			htmlEscape(err.Error()),
+		),
+		"HTML",
+	)
+}
+
+// pageCaption labels page of pages in a summary caption; nothing for a
+// single image.
+func pageCaption(page, pages int) string {
+	if pages <= 1 {
+		return ""
+	}
+	return i18n.T("summary.page", page, pages)
+}
+
 // handleMoveCommand processes the /move command. Two invocation forms:
 //   - Reply to a complaint message:   /move <belt-name>
 //   - With explicit complaint ID:     /move <complaint_id> <belt-name>
@@ -172,29 +293,29 @@ func (c *Client) handleMoveCommand(message *IncomingMessage, stor *storage.Stora
 
 	newBelt, ok := belt.Canonicalize(beltInput)
 	if !ok {
-		c.sendTextMessage(fmt.Sprintf("❌ Unknown belt <b>%s</b>.\nValid belts: <code>%s</code>", htmlEscape(strings.TrimSpace(beltInput)), strings.Join(belt.All(), ", ")), "HTML")
+		c.sendTextMessage(i18n.T("command.move.unknown_belt", htmlEscape(strings.TrimSpace(beltInput)), strings.Join(belt.All(), ", ")), "HTML")
 		return
 	}
 
 	if !stor.Exists(complaintID) {
-		c.sendTextMessage(fmt.Sprintf("❌ Complaint <b>%s</b> is not in active storage.", htmlEscape(complaintID)), "HTML")
+		c.sendTextMessage(i18n.T("command.move.not_stored", htmlEscape(complaintID)), "HTML")
 		return
 	}
 
 	oldBelt := belt.DisplayName(stor.GetBelt(complaintID))
 	if err := stor.UpdateBelt(complaintID, newBelt); err != nil {
 		log.Printf("⚠️  Failed to move complaint %s to %s: %v\n", complaintID, newBelt, err)
-		c.sendTextMessage(fmt.Sprintf("❌ Failed to update complaint <b>%s</b>.", htmlEscape(complaintID)), "HTML")
+		c.sendTextMessage(i18n.T("command.move.failed", htmlEscape(complaintID)), "HTML")
 		return
 	}
 
 	if message.ReplyToMessage != nil && message.ReplyToMessage.Text != "" {
 		updatedText, changed := rewriteComplaintBeltLine(message.ReplyToMessage.Text, newBelt)
 		if changed {
-			_, err := c.doRequest("editMessageText", EditMessageRequest{
+			err := c.edit("editMessageText", EditMessageRequest{
 				ChatID:      c.ChatID,
 				MessageID:   fmt.Sprintf("%d", message.ReplyToMessage.MessageID),
-				Text:        updatedText,
+				Text:        htmlEscape(updatedText),
 				ParseMode:   "HTML",
 				ReplyMarkup: nil,
 			})
@@ -204,7 +325,7 @@ func (c *Client) handleMoveCommand(message *IncomingMessage, stor *storage.Stora
 		}
 	}
 
-	c.sendTextMessage(fmt.Sprintf("✅ Complaint <b>%s</b> moved from <b>%s</b> to <b>%s</b>.", htmlEscape(complaintID), htmlEscape(oldBelt), htmlEscape(newBelt)), "HTML")
+	c.sendTextMessage(i18n.T("command.move.done", htmlEscape(complaintID), htmlEscape(oldBelt), htmlEscape(newBelt)), "HTML")
 }
 
 func (c *Client) sendMoveUsage() {
@@ -228,6 +349,229 @@ func (c *Client) sendMoveUsage() {
 	)
 }
 
+// consumerHistoryLimit caps how many past complaints /consumer lists so the
+// reply stays within one Telegram message.
+const consumerHistoryLimit = 15
+
+// handleConsumerCommand processes /consumer <number>, listing the consumer's
+// recent complaints (open and resolved) from the history table.
+func (c *Client) handleConsumerCommand(message *IncomingMessage, stor *storage.Storage) {
+	args := strings.Fields(strings.TrimSpace(message.Text))
+	if len(args) < 2 {
+		c.sendTextMessage(i18n.T("command.consumer.usage"), "HTML")
+		return
+	}
+	consumerNo := args[1]
+
+	entries, err := stor.GetConsumerHistory(consumerNo, consumerHistoryLimit)
+	if err != nil {
+		log.Printf("⚠️  Failed to load history for consumer %s: %v\n", consumerNo, err)
+		c.sendTextMessage(i18n.T("command.consumer.failed", htmlEscape(consumerNo)), "HTML")
+		return
+	}
+	c.sendTextMessage(formatConsumerHistory(consumerNo, entries, c.location()), "HTML")
+}
+
+// formatConsumerHistory renders history entries (newest first) as the
+// /consumer reply.
+func formatConsumerHistory(consumerNo string, entries []storage.HistoryEntry, loc *time.Location) string {
+	if len(entries) == 0 {
+		return i18n.T("command.consumer.none", htmlEscape(consumerNo))
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.T("command.consumer.title", htmlEscape(consumerNo)))
+	if name := entries[0].ConsumerName; name != "" {
+		fmt.Fprintf(&b, " — %s", htmlEscape(name))
+	}
+	b.WriteString(i18n.T("command.consumer.count", len(entries)))
+	if len(entries) == consumerHistoryLimit {
+		b.WriteString(i18n.T("command.consumer.latest"))
+	}
+	b.WriteString("\n")
+
+	for _, e := range entries {
+		status := i18n.T("command.consumer.open")
+		if !e.ResolvedAt.IsZero() {
+			status = i18n.T("command.consumer.resolved", e.ResolvedAt.In(loc).Format("02 Jan"))
+		}
+		date := e.ComplainDate
+		if date == "" && !e.FirstSeenAt.IsZero() {
+			date = e.FirstSeenAt.In(loc).Format("2006-01-02")
+		}
+		fmt.Fprintf(&b, "\n<b>%s</b> · %s · %s\n", htmlEscape(e.ComplaintID), htmlEscape(date), status)
+		if e.Belt != "" {
+			fmt.Fprintf(&b, "%s %s", belt.StyleFor(e.Belt).Emoji, htmlEscape(belt.DisplayName(e.Belt)))
+			if e.Village != "" {
+				fmt.Fprintf(&b, " / %s", htmlEscape(e.Village))
+			}
+			b.WriteString("\n")
+		}
+		if e.Description != "" {
+			fmt.Fprintf(&b, "💬 %s\n", htmlEscape(truncateRunes(e.Description, 120)))
+		}
+	}
+	return b.String()
+}
+
+// handleFailedCommand processes /failed, listing complaints on the
+// dead-letter list with their attempts and next retry.
+func (c *Client) handleFailedCommand(stor *storage.Storage) {
+	letters, err := stor.GetDeadLetters()
+	if err != nil {
+		log.Printf("⚠️  Failed to load dead letters: %v\n", err)
+		c.sendTextMessage(i18n.T("command.deadletters.failed"), "HTML")
+		return
+	}
+	c.sendTextMessage(formatDeadLetters(letters, time.Now().In(c.location())), "HTML")
+}
+
+// formatDeadLetters renders the /failed reply.
+func formatDeadLetters(letters []storage.DeadLetter, now time.Time) string {
+	if len(letters) == 0 {
+		return "✅ No failed complaints — everything was processed."
+	}
+
+	stageLabels := map[string]string{
+		storage.StageFetch:  "details fetch",
+		storage.StageNotify: "Telegram send",
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "📮 <b>Failed complaints (%d)</b>\n", len(letters))
+	for _, dl := range letters {
+		stage := stageLabels[dl.Stage]
+		if stage == "" {
+			stage = dl.Stage
+		}
+		fmt.Fprintf(&b, "\n<b>%s</b> · %s · %d attempt(s)\n", htmlEscape(dl.ComplaintID), htmlEscape(stage), dl.Attempts)
+		if dl.LastError != "" {
+			fmt.Fprintf(&b, "❗ %s\n", htmlEscape(truncateRunes(dl.LastError, 120)))
+		}
+		if dl.NextRetryAt.After(now) {
+			fmt.Fprintf(&b, "⏳ Next retry %s\n", dl.NextRetryAt.In(now.Location()).Format("02 Jan 15:04"))
+		} else {
+			b.WriteString("⏳ Retrying on the next cycle\n")
+		}
+	}
+	return b.String()
+}
+
+// formatBuildInfo renders info under title, for /version and the startup
+// notice.
+func formatBuildInfo(title string, info buildinfo.Info) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n🏷️ Version: <code>%s</code>", title, htmlEscape(info.Version))
+	if c := info.ShortCommit(); c != "" {
+		fmt.Fprintf(&b, "\n🔖 Commit: <code>%s</code>", htmlEscape(c))
+	}
+	if info.BuildTime != "" {
+		fmt.Fprintf(&b, "\n🛠️ Built: %s", htmlEscape(info.BuildTime))
+	}
+	fmt.Fprintf(&b, "\n🐹 Go: %s", htmlEscape(info.GoVersion))
+	return b.String()
+}
+
+// statusCycles is how many fetch cycles /status lists.
+const statusCycles = 10
+
+// handleStatusCommand replies to /status with the health state and the
+// recent fetch cycles. Ignored while Health is nil.
+func (c *Client) handleStatusCommand() {
+	if c.Health == nil {
+		return
+	}
+	c.sendTextMessage(formatStatus(c.Health.GetStatus(), c.Health.FetchHistory(), c.location()), "HTML")
+}
+
+// formatStatus renders the /status reply; history is newest first.
+func formatStatus(st health.Status, history []health.FetchCycle, loc *time.Location) string {
+	stateIcons := map[string]string{
+		health.StateHealthy:   "✅",
+		health.StateDegraded:  "⚠️",
+		health.StateUnhealthy: "❌",
+		health.StateStarting:  "⏳",
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "🩺 <b>Status: %s</b> %s\n", htmlEscape(st.Status), stateIcons[st.Status])
+	fmt.Fprintf(&b, "Up %s · portal %s", htmlEscape(st.Uptime), htmlEscape(st.Portal))
+	if st.ConsecutiveErrors > 0 {
+		fmt.Fprintf(&b, " · %d failed fetch(es) in a row", st.ConsecutiveErrors)
+	}
+	b.WriteString("\n")
+
+	names := make([]string, 0, len(st.Components))
+	for name := range st.Components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		comp := st.Components[name]
+		if comp.Status == health.StateHealthy {
+			continue
+		}
+		fmt.Fprintf(&b, "%s %s: %s\n", stateIcons[comp.Status], htmlEscape(name), htmlEscape(truncateRunes(comp.Detail, 120)))
+	}
+
+	if len(history) == 0 {
+		b.WriteString("\nNo fetch cycles yet.")
+		return b.String()
+	}
+	if len(history) > statusCycles {
+		history = history[:statusCycles]
+	}
+	b.WriteString("\n<b>Recent fetch cycles</b>\n")
+	for _, cy := range history {
+		icon := "✅"
+		if cy.Status != "success" {
+			icon = "❌"
+		}
+		fmt.Fprintf(&b, "%s %s · %s · %d page(s) · %d new",
+			icon, cy.StartedAt.In(loc).Format("02 Jan 15:04"),
+			cy.Duration.Round(100*time.Millisecond), cy.Pages, cy.NewComplaints)
+		if cy.Errors > 0 {
+			fmt.Fprintf(&b, " · %d error(s)", cy.Errors)
+		}
+		b.WriteString("\n")
+		if cy.Status != "success" {
+			fmt.Fprintf(&b, "   ❗ %s\n", htmlEscape(truncateRunes(cy.Status, 120)))
+		}
+	}
+	return b.String()
+}
+
+// location is the zone times are shown in: Location, or time.Local when
+// unset.
+func (c *Client) location() *time.Location {
+	if c.Location != nil {
+		return c.Location
+	}
+	return time.Local
+}
+
+// truncateRunes shortens s to at most n runes, adding an ellipsis.
+func truncateRunes(s string, n int) string {
+	r := []rune(strings.TrimSpace(s))
+	if len(r) <= n {
+		return string(r)
+	}
+	return string(r[:n]) + "…"
+}
+
+// isConsumerCommand reports whether text is a /consumer command.
+func isConsumerCommand(text string) bool {
+	fields := strings.Fields(strings.TrimSpace(text))
+	return len(fields) > 0 && fields[0] == "/consumer"
+}
+
+// summaryRows is complaints as the summary images show them: redacted
+// when RedactPII is set.
+func (c *Client) summaryRows(complaints []summary.Complaint) []summary.Complaint {
+	if c.RedactPII {
+		return summary.Redact(complaints)
+	}
+	return complaints
+}
+
 // sendTextMessage is a thin convenience for the command handlers that need
 // to push a plain text reply without crafting a full Message struct.
 func (c *Client) sendTextMessage(text, parseMode string) {
@@ -236,7 +580,7 @@ func (c *Client) sendTextMessage(text, parseMode string) {
 		Text:      text,
 		ParseMode: parseMode,
 	}
-	c.doRequest("sendMessage", msg)
+	c.send("sendMessage", msg)
 }
 
 // isMoveCommand reports whether the first whitespace-delimited token of text
@@ -271,15 +615,3 @@ func rewriteComplaintBeltLine(text, beltName string) (string, bool) {
 
 	return text, false
 }
-
-// htmlEscape escapes the three characters Telegram's HTML parse mode treats
-// specially. Used by command handlers when interpolating untrusted input
-// (complaint IDs from user replies, belt names, etc.) into messages.
-func htmlEscape(value string) string {
-	replacer := strings.NewReplacer(
-		"&", "&amp;",
-		"<", "&lt;",
-		">", "&gt;",
-	)
-	return replacer.Replace(value)
-}
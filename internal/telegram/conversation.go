@@ -0,0 +1,200 @@
+package telegram
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"cmon/internal/i18n"
+	"cmon/internal/session"
+	"cmon/internal/storage"
+)
+
+// Conversations are multi-step exchanges with one user, such as resolving
+// a complaint: "Mark as Resolved" → pick or type remarks → done. A Flow
+// names its states; each state sends a prompt when entered and handles the
+// user's answer to it — a reply to the prompt or a press of one of its
+// buttons — by naming the state to move to. Progress is kept in storage
+// (storage.Conversation) so a restart does not lose it, and a user has at
+// most one conversation at a time.
+
+// stateDone is the state a handler returns to end its conversation.
+const stateDone = ""
+
+// Flow is a multi-step conversation.
+type Flow struct {
+	Name string
+	// CallbackPrefix marks presses of buttons on the flow's prompts; their
+	// data is "PREFIX:COMPLAINT_NUMBER:CHOICE".
+	CallbackPrefix string
+	States         map[string]State
+}
+
+// State is one step of a Flow.
+type State struct {
+	// Enter sends the state's prompt and sets t.Conv.PromptMessageID.
+	Enter func(c *Client, t *Turn) error
+	// Handle processes the user's answer to the prompt, which has already
+	// been removed, and returns the next state or stateDone. Returning the
+	// current state prompts again.
+	Handle func(c *Client, t *Turn) string
+	// Ack answers the button press that entered the state, unless Handle
+	// already answered it; an i18n key.
+	Ack string
+}
+
+// flows are the conversations the bot runs, by Flow.Name.
+var flows = map[string]*Flow{
+	resolveFlow.Name:    resolveFlow,
+	resolveAllFlow.Name: resolveAllFlow,
+}
+
+// Turn is one input to a conversation.
+type Turn struct {
+	Ctx     context.Context
+	Session *session.Client
+	Store   *storage.Storage
+	From    User
+	Conv    *storage.Conversation
+
+	Message  *IncomingMessage // a reply to the prompt
+	Callback *CallbackQuery   // or a press of one of its buttons,
+	Choice   string           // whose data ends in this choice
+
+	answered bool
+}
+
+// answer answers the turn's button press, if it was one.
+func (t *Turn) answer(c *Client, text string) {
+	if t.Callback == nil || t.answered {
+		return
+	}
+	t.answered = true
+	c.answerCallbackQuery(t.Callback.ID, text)
+}
+
+// startConversation begins flow at state for t.From, ending any
+// conversation they were in. Reports whether the first prompt was sent.
+func (c *Client) startConversation(t *Turn, flow *Flow, state string) bool {
+	if old, ok := t.Store.GetConversation(t.From.ID); ok {
+		c.endConversation(t.Store, t.From.ID, old)
+	}
+	t.Conv.Flow = flow.Name
+	return c.enterState(t, flow, state)
+}
+
+// enterState sends state's prompt and saves the conversation there.
+func (c *Client) enterState(t *Turn, flow *Flow, state string) bool {
+	st, ok := flow.States[state]
+	if !ok {
+		log.Printf("⚠️  Flow %s has no state %q\n", flow.Name, state)
+		t.answer(c, "Invalid action")
+		return false
+	}
+	t.Conv.State = state
+	if err := st.Enter(c, t); err != nil {
+		log.Printf("⚠️  Failed to send prompt message: %v\n", err)
+		t.answer(c, "Error sending prompt")
+		return false
+	}
+	t.Conv.UpdatedAt = time.Now()
+	if err := t.Store.SaveConversation(t.From.ID, *t.Conv); err != nil {
+		c.deleteMessage(t.Conv.PromptMessageID)
+		t.answer(c, "Error saving conversation")
+		log.Printf("⚠️  Failed to persist %s conversation for %s: %v\n", flow.Name, t.From.FirstName, err)
+		return false
+	}
+	t.answer(c, i18n.T(st.Ack))
+	return true
+}
+
+// advance removes the conversation's prompt, hands t to its current
+// state, and moves to the state that returns.
+func (c *Client) advance(t *Turn, flow *Flow) {
+	c.endConversation(t.Store, t.From.ID, *t.Conv)
+	next := stateDone
+	if st, ok := flow.States[t.Conv.State]; ok {
+		next = st.Handle(c, t)
+	} else {
+		log.Printf("⚠️  Dropping %s conversation in unknown state %q\n", flow.Name, t.Conv.State)
+		t.answer(c, "This prompt has expired")
+	}
+	if next != stateDone {
+		c.enterState(t, flow, next)
+	}
+}
+
+// endConversation forgets userID's conversation and deletes its prompt.
+func (c *Client) endConversation(stor *storage.Storage, userID int64, conv storage.Conversation) {
+	stor.DeleteConversation(userID)
+	c.deleteMessage(conv.PromptMessageID)
+}
+
+// activeConversation returns userID's conversation unless it has been
+// idle longer than ConversationTimeout, in which case it is ended.
+func (c *Client) activeConversation(stor *storage.Storage, userID int64) (storage.Conversation, bool) {
+	conv, ok := stor.GetConversation(userID)
+	if !ok {
+		return conv, false
+	}
+	if c.ConversationTimeout > 0 && time.Since(conv.UpdatedAt) > c.ConversationTimeout {
+		log.Printf("⌛ %s conversation for complaint %s expired\n", conv.Flow, conv.ComplaintNumber)
+		c.endConversation(stor, userID, conv)
+		return conv, false
+	}
+	return conv, true
+}
+
+// handleConversationReply feeds message to its sender's conversation when
+// it replies to the conversation's prompt, and reports whether it did.
+func (c *Client) handleConversationReply(ctx context.Context, sc *session.Client, message *IncomingMessage, stor *storage.Storage) bool {
+	conv, ok := c.activeConversation(stor, message.From.ID)
+	// Only a reply to the bot's prompt counts, not a random message
+	if !ok || message.ReplyToMessage == nil || message.ReplyToMessage.MessageID != conv.PromptMessageID {
+		return false
+	}
+	flow := flows[conv.Flow]
+	if flow == nil {
+		c.endConversation(stor, message.From.ID, conv)
+		return false
+	}
+	c.advance(&Turn{Ctx: ctx, Session: sc, Store: stor, From: *message.From, Conv: &conv, Message: message}, flow)
+	return true
+}
+
+// handleConversationCallback processes a press of a button on flow's
+// prompts; data is the callback data after the flow's prefix. Only the
+// user the prompt was sent to can answer it.
+func (c *Client) handleConversationCallback(ctx context.Context, sc *session.Client, query *CallbackQuery, flow *Flow, data string, stor *storage.Storage) {
+	i := strings.LastIndex(data, ":")
+	if i < 0 {
+		c.answerCallbackQuery(query.ID, "Invalid action")
+		return
+	}
+	complaintNumber, choice := data[:i], data[i+1:]
+
+	conv, ok := c.activeConversation(stor, query.From.ID)
+	if !ok || conv.Flow != flow.Name || conv.ComplaintNumber != complaintNumber || query.Message == nil || query.Message.MessageID != conv.PromptMessageID {
+		c.answerCallbackQuery(query.ID, "This prompt is not for you")
+		return
+	}
+	c.advance(&Turn{Ctx: ctx, Session: sc, Store: stor, From: query.From, Conv: &conv, Callback: query, Choice: choice}, flow)
+}
+
+// expireConversations ends conversations idle longer than
+// ConversationTimeout, deleting their prompts.
+func (c *Client) expireConversations(stor *storage.Storage, now time.Time) {
+	if c.ConversationTimeout <= 0 {
+		return
+	}
+	idle, err := stor.GetIdleConversations(now.Add(-c.ConversationTimeout))
+	if err != nil {
+		log.Printf("⚠️  Failed to look up idle conversations: %v\n", err)
+		return
+	}
+	for userID, conv := range idle {
+		log.Printf("⌛ %s conversation for complaint %s expired\n", conv.Flow, conv.ComplaintNumber)
+		c.endConversation(stor, userID, conv)
+	}
+}
@@ -0,0 +1,152 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"cmon/internal/api"
+	"cmon/internal/i18n"
+	"cmon/internal/session"
+	"cmon/internal/storage"
+)
+
+// maxDetailHistory caps the history rows shown in a details reply.
+const maxDetailHistory = 10
+
+// statusKeyHints pick the assignment/status fields out of a complaint
+// record; the rest (name, mobile, description) is already in the message.
+// History rows also keep their timestamps.
+var (
+	statusKeyHints  = []string{"status", "assign", "attend", "remark", "resol", "close", "update", "staff", "lineman"}
+	historyKeyHints = append([]string{"date", "time", "_at"}, statusKeyHints...)
+)
+
+// handleDetailsCallback answers a "🔍 Details" click with the complaint's
+// live portal record, as a reply to the complaint message.
+func (c *Client) handleDetailsCallback(sc *session.Client, query *CallbackQuery, complaintNumber string, stor *storage.Storage) {
+	apiID := stor.GetAPIID(complaintNumber)
+	switch {
+	case apiID == "":
+		c.answerCallbackQuery(query.ID, i18n.T("details.untracked"))
+		return
+	case api.IsLocalID(apiID):
+		c.answerCallbackQuery(query.ID, i18n.T("details.local"))
+		return
+	case sc == nil:
+		c.answerCallbackQuery(query.ID, i18n.T("details.no_session"))
+		return
+	}
+	c.answerCallbackQuery(query.ID, i18n.T("details.fetching"))
+
+	var text string
+	rec, err := api.FetchComplaintRecord(sc, apiID)
+	if err != nil {
+		log.Printf("⚠️  Failed to fetch live details for %s: %v\n", complaintNumber, err)
+		text = i18n.T("details.failed", htmlEscape(complaintNumber), htmlEscape(err.Error()))
+	} else {
+		log.Printf("🔍 Live details for %s requested by %s\n", complaintNumber, userDisplayName(query.From))
+		text = formatLiveDetails(complaintNumber, rec, time.Now().In(c.location()))
+	}
+
+	msg := Message{ChatID: c.ChatID, Text: text, ParseMode: "HTML", DisableWebPagePreview: true}
+	if query.Message != nil && query.Message.Chat != nil {
+		msg.ChatID = fmt.Sprintf("%d", query.Message.Chat.ID)
+		msg.ReplyToMessageID = query.Message.MessageID
+	}
+	if err := c.send("sendMessage", msg); err != nil {
+		log.Printf("⚠️  Failed to send live details for %s: %v\n", complaintNumber, err)
+	}
+}
+
+// formatLiveDetails renders the status fields and latest history rows of
+// a complaint record.
+func formatLiveDetails(complaintNumber string, rec *api.Record, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔍 <b>Live status — complaint %s</b>\n<i>Fetched %s</i>\n", htmlEscape(complaintNumber), now.Format("02 Jan 2006, 03:04 PM"))
+
+	fields := recordFields(rec.Detail, statusKeyHints)
+	if len(fields) == 0 {
+		b.WriteString("\nNo status fields in the portal record.")
+	}
+	for _, f := range fields {
+		fmt.Fprintf(&b, "\n• %s: %s", htmlEscape(f[0]), htmlEscape(truncateRunes(f[1], 200)))
+	}
+
+	if len(rec.History) > 0 {
+		rows := rec.History
+		if len(rows) > maxDetailHistory {
+			fmt.Fprintf(&b, "\n\n<b>History</b> (latest %d of %d)", maxDetailHistory, len(rows))
+			rows = rows[len(rows)-maxDetailHistory:]
+		} else {
+			b.WriteString("\n\n<b>History</b>")
+		}
+		for _, row := range rows {
+			fields := recordFields(row, historyKeyHints)
+			if len(fields) == 0 {
+				fields = recordFields(row, nil)
+			}
+			values := make([]string, 0, len(fields))
+			for _, f := range fields {
+				values = append(values, htmlEscape(truncateRunes(f[1], 80)))
+			}
+			if len(values) > 0 {
+				fmt.Fprintf(&b, "\n• %s", strings.Join(values, " · "))
+			}
+		}
+	}
+	return b.String()
+}
+
+// recordFields returns the non-empty scalar fields of m as label/value
+// pairs in key order. With hints, only keys containing one of them are
+// kept; without, everything but IDs.
+func recordFields(m map[string]interface{}, hints []string) [][2]string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var out [][2]string
+	for _, k := range keys {
+		v := m[k]
+		switch v.(type) {
+		case nil, map[string]interface{}, []interface{}:
+			continue
+		}
+		value := strings.TrimSpace(fmt.Sprintf("%v", v))
+		if value == "" {
+			continue
+		}
+		lower := strings.ToLower(k)
+		if hints != nil && !containsAny(lower, hints) {
+			continue
+		}
+		if hints == nil && (lower == "id" || strings.HasSuffix(lower, "_id")) {
+			continue
+		}
+		out = append(out, [2]string{fieldLabel(k), value})
+	}
+	return out
+}
+
+func containsAny(key string, hints []string) bool {
+	for _, hint := range hints {
+		if strings.Contains(key, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldLabel turns an API key like "complain_status" into "Complain status".
+func fieldLabel(key string) string {
+	label := strings.TrimSpace(strings.ReplaceAll(key, "_", " "))
+	if label == "" {
+		return key
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
@@ -0,0 +1,216 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"cmon/internal/i18n"
+	"cmon/internal/notify"
+)
+
+// Digests: when a fetch cycle brings more than DigestThreshold new
+// complaints for one chat, they are listed in one message with a grid of
+// "✅ NUMBER" buttons, each starting the usual resolve flow. Every member
+// stores the digest's message ID as its own, and a digestRef marks it as a
+// member, so resolving one redraws the digest without it rather than
+// replacing the whole message.
+
+const (
+	// digestRef is the MessageRefs channel marking digest members; the ref
+	// is the digest's message ID.
+	digestRef = "telegram-digest"
+	// maxDigestSize caps the complaints in one digest so it stays under
+	// Telegram's length limit; a bigger batch gets several.
+	maxDigestSize = 20
+	// digestColumns is how many resolve buttons share a keyboard row.
+	digestColumns = 3
+)
+
+// inDigest reports whether complaintNumber was announced in a digest.
+func inDigest(refs notify.MessageRefs, complaintNumber string) bool {
+	return refs.GetMessageRef(digestRef, complaintNumber) != ""
+}
+
+// SendComplaints implements notify.BatchSender. Per chat, a batch of more
+// than DigestThreshold complaints goes out as digests; smaller ones, or all
+// of them with DigestThreshold 0, get a message each. During quiet hours
+// the routine ones are queued instead, as in SendComplaint.
+func (n *Notifier) SendComplaints(cs []notify.Complaint) error {
+	var ready []notify.Complaint
+	var errs []error
+	now := time.Now()
+	for _, c := range cs {
+		if n.client.holdUntilMorning(c, now) {
+			errs = append(errs, n.deferSend(c))
+		} else {
+			ready = append(ready, c)
+		}
+	}
+	return errors.Join(append(errs, n.sendBatch(ready, n.client.DigestThreshold))...)
+}
+
+// sendBatch sends cs, as digests for each chat or topic with more than
+// threshold of them (none when threshold is 0). A digest that fails to
+// send falls back to a message per complaint.
+func (n *Notifier) sendBatch(cs []notify.Complaint, threshold int) error {
+	var chats []destination
+	byChat := make(map[destination][]notify.Complaint)
+	for _, c := range cs {
+		if n.sent(c) {
+			continue
+		}
+		chat := n.client.destinationFor(c.Belt, c.Category)
+		if _, ok := byChat[chat]; !ok {
+			chats = append(chats, chat)
+		}
+		byChat[chat] = append(byChat[chat], c)
+	}
+
+	var errs []error
+	for _, chat := range chats {
+		group := byChat[chat]
+		if threshold <= 0 || len(group) <= threshold {
+			errs = append(errs, n.sendEach(group))
+			continue
+		}
+		for len(group) > 0 {
+			batch := group[:min(len(group), maxDigestSize)]
+			group = group[len(batch):]
+			if err := n.sendDigest(chat, batch); err != nil {
+				log.Printf("⚠️  Failed to send digest of %d complaints, sending them one by one: %v", len(batch), err)
+				errs = append(errs, n.sendEach(batch))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendEach sends cs a message each.
+func (n *Notifier) sendEach(cs []notify.Complaint) error {
+	var errs []error
+	for _, c := range cs {
+		if err := n.send(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendDigest sends cs to dest as one digest and records it as each
+// one's message. Only a failed send is returned; once the digest is out,
+// falling back would announce the complaints twice.
+func (n *Notifier) sendDigest(dest destination, cs []notify.Complaint) error {
+	result, err := doRequest[SendMessageResult](context.Background(), n.client, "sendMessage", Message{
+		ChatID:                dest.chatID,
+		MessageThreadID:       dest.thread,
+		Text:                  digestText(i18n.T("digest.new", len(cs)), cs),
+		ParseMode:             "HTML",
+		DisableWebPagePreview: true,
+		ReplyMarkup:           digestKeyboard(cs),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send Telegram digest: %w", err)
+	}
+	msgID := strconv.Itoa(result.MessageID)
+	log.Printf("📦 Sent %d complaints to Telegram as one digest", len(cs))
+
+	for _, c := range cs {
+		observeNotified(c)
+		if err := n.stor.ClearDeadLetter(c.Number); err != nil {
+			log.Printf("⚠️  Failed to clear dead letter for complaint %s: %v", c.Number, err)
+		}
+		if err := n.stor.SetMessageID(c.Number, msgID); err != nil {
+			log.Printf("⚠️  Failed to persist Telegram message ID for complaint %s: %v", c.Number, err)
+			continue
+		}
+		if err := n.stor.SetMessageRef(digestRef, c.Number, msgID); err != nil {
+			log.Printf("⚠️  Failed to mark complaint %s as a digest member: %v", c.Number, err)
+		}
+	}
+	return nil
+}
+
+// redrawDigest re-renders digest messageID from its members still in
+// storage, leaving out except (the one being resolved), with the Gujarati
+// lines of the ones translated so far. Once none are left it says so and
+// loses its buttons.
+func (n *Notifier) redrawDigest(chatID, messageID, except string) error {
+	var cs []notify.Complaint
+	for _, id := range n.stor.GetComplaintIDsByMessageRef(digestRef, messageID) {
+		if id == except {
+			n.client.setDigestGujarati(id, "")
+			continue
+		}
+		if rec, ok := n.stor.GetRecord(id); ok {
+			cs = append(cs, notify.Complaint{
+				Number:          id,
+				ComplainantName: rec.ConsumerName,
+				Description:     rec.Description,
+				Area:            rec.Area,
+				Village:         rec.Village,
+				Translation:     n.client.digestGujaratiFor(id),
+			})
+		}
+	}
+	text := i18n.T("digest.done")
+	if len(cs) > 0 {
+		text = digestText(i18n.T("digest.pending", len(cs)), cs)
+	}
+	return n.client.edit("editMessageText", EditMessageRequest{
+		ChatID:      chatID,
+		MessageID:   messageID,
+		Text:        text,
+		ParseMode:   "HTML",
+		ReplyMarkup: digestKeyboard(cs),
+	})
+}
+
+// digestText lists cs under title, one short entry each; a member's
+// Translation is its Gujarati line (see digestGujarati). Phone and
+// consumer numbers are left out, so REDACT_PII has nothing to mask.
+func digestText(title string, cs []notify.Complaint) string {
+	var b strings.Builder
+	b.WriteString(title)
+	fmt.Fprintf(&b, "\n<i>%s</i>\n", i18n.T("digest.hint"))
+	for _, c := range cs {
+		mark := "•"
+		if c.Reopened != nil {
+			mark = "♻️"
+		}
+		place := c.Village
+		if place == "" {
+			place = c.Area
+		}
+		fmt.Fprintf(&b, "\n%s <b>%s</b> — %s", mark, htmlEscape(c.Number), htmlEscape(defaultIfEmpty(c.ComplainantName, i18n.T("resolved.unknown"))))
+		if place != "" {
+			fmt.Fprintf(&b, " · %s", htmlEscape(place))
+		}
+		if d := strings.TrimSpace(c.Description); d != "" {
+			fmt.Fprintf(&b, "\n   <i>%s</i>", htmlEscape(truncateRunes(d, 80)))
+		}
+		if c.Translation != "" {
+			fmt.Fprintf(&b, "\n   %s", htmlEscape(truncateRunes(c.Translation, 80)))
+		}
+	}
+	return b.String()
+}
+
+// digestKeyboard is the grid of "✅ NUMBER" resolve buttons under a digest.
+func digestKeyboard(cs []notify.Complaint) *InlineKeyboardMarkup {
+	rows := [][]InlineKeyboardButton{}
+	for i, c := range cs {
+		if i%digestColumns == 0 {
+			rows = append(rows, nil)
+		}
+		rows[len(rows)-1] = append(rows[len(rows)-1], InlineKeyboardButton{
+			Text:         "✅ " + c.Number,
+			CallbackData: "resolve:" + c.Number,
+		})
+	}
+	return &InlineKeyboardMarkup{InlineKeyboard: rows}
+}
@@ -0,0 +1,91 @@
+package telegram
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"cmon/internal/i18n"
+)
+
+// Messages sent with ParseMode "HTML" carry portal and user text (complaint
+// descriptions, consumer names, error pages) that may contain <, > or &.
+// Unescaped, Telegram either rejects the message ("can't parse entities") or
+// renders it mangled. Fixed-layout messages are therefore built from
+// html/template, which escapes every interpolated value (complaints via
+// msgtmpl, so the layout can be customised); ad-hoc messages go through
+// htmlEscape.
+
+// textFuncs gives the message templates {{t "key"}} for the bot texts in
+// BOT_LANGUAGE (see package i18n).
+var textFuncs = template.FuncMap{"t": i18n.T}
+
+// resolvedTmpl renders the card that replaces a resolved complaint message.
+var resolvedTmpl = template.Must(template.New("resolved").Funcs(textFuncs).Parse(
+	`✅ <b>{{.Title}}</b>
+
+{{t "resolved.complaint" .ComplaintID}}
+👤 {{.ConsumerName}}{{if .Resolution}}
+📝 {{.Resolution}}{{end}}
+🕐 {{.Time.Format "02 Jan 2006, 03:04 PM"}}`))
+
+type resolvedView struct {
+	Title        string
+	ComplaintID  string
+	ConsumerName string
+	Resolution   string
+	Time         time.Time
+}
+
+// criticalAlertTmpl renders SendCriticalAlert. Error messages often embed
+// portal HTML, so they must not be trusted as markup.
+var criticalAlertTmpl = template.Must(template.New("critical").Funcs(textFuncs).Parse(
+	`🚨 <b>{{t "alert.critical.title"}}</b>
+
+<b>{{t "alert.error_type"}}</b> {{.ErrorType}}
+<b>{{t "alert.error_message"}}</b> {{.ErrorMsg}}
+<b>{{t "alert.retries"}}</b> {{.RetryCount}}
+<b>{{t "alert.timestamp"}}</b> {{.Time.Format "2006-01-02 15:04:05"}}
+
+⚠️ <b>{{t "alert.action"}}</b> {{t "alert.action.check"}}`))
+
+// recoveryAlertTmpl renders SendRecoveryAlert, the all-clear for an
+// earlier critical alert.
+var recoveryAlertTmpl = template.Must(template.New("recovery").Funcs(textFuncs).Parse(
+	`✅ <b>{{t "alert.recovered.title"}}</b>
+
+<b>{{t "alert.error_type"}}</b> {{.ErrorType}}
+{{- if .ErrorMsg}}
+{{.ErrorMsg}}
+{{- end}}
+<b>{{t "alert.timestamp"}}</b> {{.Time.Format "2006-01-02 15:04:05"}}`))
+
+type criticalAlertView struct {
+	ErrorType  string
+	ErrorMsg   string
+	RetryCount int
+	Time       time.Time
+}
+
+// renderHTML executes one of the message templates.
+func renderHTML(t *template.Template, data interface{}) (string, error) {
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render %s message: %w", t.Name(), err)
+	}
+	return b.String(), nil
+}
+
+// htmlEscape escapes the three characters Telegram's HTML parse mode treats
+// specially. Used by command handlers when interpolating untrusted input
+// (complaint IDs from user replies, belt names, etc.) into messages.
+func htmlEscape(value string) string {
+	replacer := strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+	)
+	return replacer.Replace(value)
+}
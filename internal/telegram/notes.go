@@ -0,0 +1,101 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"cmon/internal/i18n"
+	"cmon/internal/storage"
+)
+
+// maxNotesShown caps the notes listed under a complaint message, newest
+// kept, so a long thread doesn't push it past Telegram's length limit.
+const maxNotesShown = 5
+
+// handleNoteReply records a text reply to a complaint message as a note on
+// that complaint ("Lineman dispatched 14:30") and shows it under the
+// message. Reports whether message was such a reply.
+func (c *Client) handleNoteReply(message *IncomingMessage, stor *storage.Storage) bool {
+	if message.ReplyToMessage == nil || strings.HasPrefix(message.Text, "/") {
+		return false
+	}
+	messageID := strconv.Itoa(message.ReplyToMessage.MessageID)
+	complaintNumber, found := stor.GetComplaintIDByMessageID(messageID)
+	if !found || inDigest(stor, complaintNumber) {
+		return false // a digest reply can't say which complaint it means
+	}
+
+	by := userDisplayName(*message.From)
+	if err := stor.AddNote(complaintNumber, storage.Note{Author: by, AuthorID: message.From.ID, Text: message.Text}); err != nil {
+		log.Printf("⚠️  Failed to save note on complaint %s: %v\n", complaintNumber, err)
+		c.sendTextMessage(i18n.T("note.failed", htmlEscape(complaintNumber)), "HTML")
+		return true
+	}
+	log.Printf("📝 %s added a note to complaint %s\n", by, complaintNumber)
+
+	chatID := c.ChatID
+	if message.Chat != nil {
+		chatID = strconv.FormatInt(message.Chat.ID, 10)
+	}
+	if c.refreshComplaintMessage(chatID, messageID, complaintNumber, stor) {
+		return true
+	}
+	// Without the stored text the message can't be rebuilt; confirm in
+	// the thread instead.
+	c.send("sendMessage", Message{
+		ChatID:           chatID,
+		Text:             i18n.T("note.added", htmlEscape(complaintNumber)),
+		ParseMode:        "HTML",
+		ReplyToMessageID: message.MessageID,
+	})
+	return true
+}
+
+// refreshComplaintMessage redraws a complaint message from its stored text
+// with the current handler, notes and age footer. Reports false when the
+// text is not stored or the edit failed.
+func (c *Client) refreshComplaintMessage(chatID, messageID, complaintNumber string, stor *storage.Storage) bool {
+	text, footerDays, ok := stor.GetMessageText(complaintNumber)
+	if !ok {
+		return false
+	}
+	by, _, _ := stor.GetAcknowledgment(complaintNumber)
+	err := c.edit("editMessageText", EditMessageRequest{
+		ChatID:      chatID,
+		MessageID:   messageID,
+		Text:        c.complaintText(text, by, footerDays, c.complaintNotes(complaintNumber, stor)),
+		ParseMode:   "HTML",
+		ReplyMarkup: c.complaintKeyboard(complaintNumber, by),
+	})
+	if err != nil {
+		log.Printf("⚠️  Failed to update message of complaint %s: %v\n", complaintNumber, err)
+		return false
+	}
+	return true
+}
+
+// complaintNotes returns complaintNumber's notes, logging a failed lookup.
+func (c *Client) complaintNotes(complaintNumber string, stor *storage.Storage) []storage.Note {
+	notes, err := stor.GetNotes(complaintNumber)
+	if err != nil {
+		log.Printf("⚠️  Failed to load notes of complaint %s: %v\n", complaintNumber, err)
+	}
+	return notes
+}
+
+// notesBlock renders notes as the "📝 Notes" section of a complaint message.
+func notesBlock(notes []storage.Note, loc *time.Location) string {
+	var b strings.Builder
+	b.WriteString("📝 <b>Notes</b>")
+	if len(notes) > maxNotesShown {
+		fmt.Fprintf(&b, " <i>(%d earlier not shown)</i>", len(notes)-maxNotesShown)
+		notes = notes[len(notes)-maxNotesShown:]
+	}
+	for _, n := range notes {
+		fmt.Fprintf(&b, "\n• <i>%s</i> %s: %s", n.CreatedAt.In(loc).Format("02 Jan 15:04"), htmlEscape(n.Author), htmlEscape(n.Text))
+	}
+	return b.String()
+}
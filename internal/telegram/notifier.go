@@ -0,0 +1,260 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"cmon/internal/i18n"
+	"cmon/internal/metrics"
+	"cmon/internal/notify"
+	"cmon/internal/storage"
+)
+
+// messageStore is the slice of storage the notifier needs: Telegram message
+// IDs are persisted on send and looked up again to edit on resolution, and
+// complaints that could not be sent are dead-lettered for a later retry.
+// Each send is journaled so one interrupted by a crash is not lost, and
+// digest members are tracked so the digest can be redrawn. The message
+// text, acknowledgment and notes are read back to add a late translation.
+type messageStore interface {
+	GetMessageID(complaintID string) string
+	SetMessageID(complaintID, messageID string) error
+	SetMessageText(complaintID, text string) error
+	GetMessageText(complaintID string) (text string, footerDays int, ok bool)
+	GetAcknowledgment(complaintID string) (by string, byID int64, ok bool)
+	GetNotes(complaintID string) ([]storage.Note, error)
+	GetMessageRef(channel, complaintID string) string
+	SetMessageRef(channel, complaintID, ref string) error
+	GetComplaintIDsByMessageRef(channel, ref string) []string
+	GetRecord(complaintID string) (storage.Record, bool)
+	DeferSend(complaintID, payload string) error
+	GetDeferredSends() ([]storage.DeferredSend, error)
+	ClearDeferredSend(complaintID string) error
+	RecordFailure(complaintID, apiID, stage, errMsg, payload string) (storage.DeadLetter, error)
+	GetDueDeadLetters(stage string) ([]storage.DeadLetter, error)
+	ClearDeadLetter(complaintID string) error
+	JournalAppend(op, complaintID, payload string) (int64, error)
+	JournalDone(id int64) error
+	ReplayJournal(op string, replay func(storage.JournalEntry) error) (int, error)
+}
+
+// Notifier adapts a Client to notify.Notifier.
+type Notifier struct {
+	client *Client
+	stor   messageStore
+}
+
+// AsNotifier wraps c for use in a notify.Multi. Returns nil when Telegram is
+// not configured so callers can skip the channel.
+func (c *Client) AsNotifier(stor messageStore) *Notifier {
+	if c == nil {
+		return nil
+	}
+	return &Notifier{client: c, stor: stor}
+}
+
+// Name implements notify.Notifier.
+func (n *Notifier) Name() string { return "telegram" }
+
+// SendComplaint implements notify.Notifier. The message ID is persisted so
+// the complaint can be edited on resolution and acknowledged by reaction.
+//
+// The send is journaled until its message ID or dead letter is stored; if
+// the process dies in between, RecoverInterruptedSends re-queues it.
+// During quiet hours a routine complaint is queued instead (see
+// FlushDeferred).
+func (n *Notifier) SendComplaint(c notify.Complaint) error {
+	if n.client.holdUntilMorning(c, time.Now()) {
+		return n.deferSend(c)
+	}
+	return n.send(c)
+}
+
+// send is SendComplaint without the quiet-hours check. The complaint
+// number is the idempotency key: a complaint that already has a message
+// is not sent again, whichever retry or recovery path brings it back.
+func (n *Notifier) send(c notify.Complaint) error {
+	if n.sent(c) {
+		return nil
+	}
+	if payload, err := json.Marshal(c); err == nil {
+		if id, err := n.stor.JournalAppend(storage.JournalTelegramSend, c.Number, string(payload)); err != nil {
+			log.Printf("⚠️  Failed to journal Telegram send for complaint %s: %v", c.Number, err)
+		} else {
+			defer n.stor.JournalDone(id)
+		}
+	}
+	msgID, text, err := n.client.sendComplaint(c, c.Number)
+	if err != nil {
+		n.deadLetter(c, err)
+		return err
+	}
+	observeNotified(c)
+	// Sent: drop any earlier failure before anything else can go wrong, so
+	// a retry never sends the message twice.
+	if err := n.stor.ClearDeadLetter(c.Number); err != nil {
+		log.Printf("⚠️  Failed to clear dead letter for complaint %s: %v", c.Number, err)
+	}
+	if msgID == "" {
+		log.Printf("⚠️  Telegram sent complaint %s but returned no message ID", c.Number)
+		return nil
+	}
+	if err := n.stor.SetMessageID(c.Number, msgID); err != nil {
+		return fmt.Errorf("failed to persist Telegram message ID: %w", err)
+	}
+	if err := n.stor.SetMessageText(c.Number, text); err != nil {
+		log.Printf("⚠️  Failed to keep Telegram message text for complaint %s: %v", c.Number, err)
+	}
+	return nil
+}
+
+// sent reports whether c already has a Telegram message, clearing any
+// retry still queued for it.
+func (n *Notifier) sent(c notify.Complaint) bool {
+	if n.stor.GetMessageID(c.Number) == "" {
+		return false
+	}
+	log.Printf("↩️  Complaint %s already has a Telegram message; not sending it again", c.Number)
+	if err := n.stor.ClearDeadLetter(c.Number); err != nil {
+		log.Printf("⚠️  Failed to clear dead letter for complaint %s: %v", c.Number, err)
+	}
+	return true
+}
+
+// observeNotified records how long c took from the dashboard to Telegram.
+func observeNotified(c notify.Complaint) {
+	if !c.SeenAt.IsZero() {
+		metrics.TelegramNotificationSeconds.Observe(time.Since(c.SeenAt).Seconds())
+	}
+}
+
+// deadLetter keeps a complaint whose message failed to send so
+// RetryFailedSends can deliver it on a later cycle; it is already saved and
+// would otherwise never be sent.
+func (n *Notifier) deadLetter(c notify.Complaint, cause error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		log.Printf("⚠️  Failed to encode complaint %s for retry: %v", c.Number, err)
+		return
+	}
+	dl, err := n.stor.RecordFailure(c.Number, "", storage.StageNotify, cause.Error(), string(payload))
+	if err != nil {
+		log.Printf("⚠️  Failed to record dead letter for complaint %s: %v", c.Number, err)
+		return
+	}
+	metrics.DeadLettersTotal.Inc()
+	log.Printf("📮 Complaint %s queued for Telegram retry (attempt %d, next at %s)", c.Number, dl.Attempts, dl.NextRetryAt.Local().Format("15:04"))
+}
+
+// RecoverInterruptedSends dead-letters the Telegram sends a crash or kill
+// interrupted, so RetryFailedSends delivers them. A send that got as far
+// as storing its message ID is dropped instead. Call once at startup.
+//
+// A message that reached Telegram just before the crash is sent again:
+// a duplicate beats a complaint nobody hears about.
+func (n *Notifier) RecoverInterruptedSends() {
+	if n == nil {
+		return
+	}
+	_, err := n.stor.ReplayJournal(storage.JournalTelegramSend, func(e storage.JournalEntry) error {
+		if n.stor.GetMessageID(e.ComplaintID) != "" {
+			return nil
+		}
+		log.Printf("📮 Telegram message for complaint %s was interrupted at %s; queueing it for retry", e.ComplaintID, e.CreatedAt.Local().Format("02 Jan 15:04"))
+		if _, err := n.stor.RecordFailure(e.ComplaintID, "", storage.StageNotify, "interrupted: cmon stopped while sending", e.Payload); err != nil {
+			return err
+		}
+		metrics.DeadLettersTotal.Inc()
+		return nil
+	})
+	if err != nil {
+		log.Printf("⚠️  Failed to replay the storage journal: %v", err)
+	}
+}
+
+// RetryFailedSends re-sends complaints whose Telegram message failed and
+// whose backoff has elapsed. SendComplaint clears each one that goes
+// through and reschedules the rest with a longer backoff.
+func (n *Notifier) RetryFailedSends() {
+	if n == nil {
+		return
+	}
+	due, err := n.stor.GetDueDeadLetters(storage.StageNotify)
+	if err != nil {
+		log.Printf("⚠️  Failed to load dead letters: %v", err)
+		return
+	}
+	for _, dl := range due {
+		var c notify.Complaint
+		if err := json.Unmarshal([]byte(dl.Payload), &c); err != nil {
+			log.Printf("⚠️  Dropping unreadable dead letter for complaint %s: %v", dl.ComplaintID, err)
+			n.stor.ClearDeadLetter(dl.ComplaintID)
+			continue
+		}
+		log.Printf("📮 Retrying Telegram message for complaint %s (attempt %d)", dl.ComplaintID, dl.Attempts+1)
+		if err := n.SendComplaint(c); err != nil {
+			log.Printf("⚠️  Telegram retry for complaint %s failed: %v", dl.ComplaintID, err)
+		}
+	}
+}
+
+// SendAlert implements notify.Notifier.
+func (n *Notifier) SendAlert(a notify.Alert) error {
+	switch a.Kind {
+	case notify.AlertPortalDown:
+		return n.client.SendPortalStatusAlert(true, a.Message)
+	case notify.AlertPortalUp:
+		return n.client.SendPortalStatusAlert(false, "")
+	case notify.AlertRecovered:
+		return n.client.SendRecoveryAlert(a.Title, a.Message)
+	case notify.AlertImported:
+		return n.client.SendImportDigest(a.Title, a.Message)
+	case notify.AlertOutage:
+		if a.Outage == nil {
+			return nil
+		}
+		return n.client.SendOutageAlert(*a.Outage)
+	default:
+		return n.client.SendCriticalAlert(a.Title, a.Message, a.RetryCount)
+	}
+}
+
+// EditStatus implements notify.Notifier: the original complaint message is
+// replaced with a short "RESOLVED" card, or a digest is redrawn without
+// the complaint.
+func (n *Notifier) EditStatus(s notify.Status) error {
+	if s.Channel == n.Name() {
+		return nil // resolved from the chat, which edited the message itself
+	}
+	messageID := n.stor.GetMessageID(s.ComplaintID)
+	if messageID == "" {
+		log.Printf("⚠️  Complaint %s has no Telegram message ID; nothing to edit", s.ComplaintID)
+		return nil
+	}
+	if inDigest(n.stor, s.ComplaintID) {
+		return n.redrawDigest(n.client.ChatIDFor(s.Belt, s.Category), messageID, s.ComplaintID)
+	}
+	s.Time = s.Time.In(n.client.location())
+	text, err := resolvedText(s)
+	if err != nil {
+		return err
+	}
+	return n.client.EditMessageText(n.client.ChatIDFor(s.Belt, s.Category), messageID, text)
+}
+
+// resolvedText renders the card that replaces a resolved complaint message.
+func resolvedText(s notify.Status) (string, error) {
+	title := i18n.T("resolved.title")
+	if s.Local {
+		title = i18n.T("resolved.title.local")
+	}
+	return renderHTML(resolvedTmpl, resolvedView{
+		Title:        title,
+		ComplaintID:  s.ComplaintID,
+		ConsumerName: defaultIfEmpty(s.ConsumerName, i18n.T("resolved.unknown")),
+		Resolution:   s.Resolution(),
+		Time:         s.Time,
+	})
+}
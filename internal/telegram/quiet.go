@@ -0,0 +1,93 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"cmon/internal/notify"
+)
+
+// InQuietHours reports whether now falls in the quiet hours from
+// QuietStart to QuietEnd in Location, which may span midnight. Always
+// false when they are not set.
+func (c *Client) InQuietHours(now time.Time) bool {
+	start, okStart := minuteOfDay(c.QuietStart)
+	end, okEnd := minuteOfDay(c.QuietEnd)
+	if !okStart || !okEnd || start == end {
+		return false
+	}
+	now = now.In(c.location())
+	m := now.Hour()*60 + now.Minute()
+	if start < end {
+		return m >= start && m < end
+	}
+	return m >= start || m < end
+}
+
+// minuteOfDay parses HH:MM into minutes since midnight.
+func minuteOfDay(hhmm string) (int, bool) {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// holdUntilMorning reports whether complaint should wait for the end of
+// quiet hours: it is routine and now is within them.
+func (c *Client) holdUntilMorning(complaint notify.Complaint, now time.Time) bool {
+	return c.InQuietHours(now) && !complaint.Urgent(c.UrgentKeywords)
+}
+
+// deferSend queues c until quiet hours end.
+func (n *Notifier) deferSend(c notify.Complaint) error {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode complaint %s for later: %w", c.Number, err)
+	}
+	if err := n.stor.DeferSend(c.Number, string(payload)); err != nil {
+		return fmt.Errorf("failed to queue complaint %s for after quiet hours: %w", c.Number, err)
+	}
+	log.Printf("🌙 Quiet hours: complaint %s held until %s", c.Number, n.client.QuietEnd)
+	return nil
+}
+
+// FlushDeferred sends the complaints held back during quiet hours, as a
+// digest per chat when there are several. Each leaves the queue once it is
+// sent or dead-lettered. Does nothing while quiet hours last, so it can be
+// called after every fetch.
+func (n *Notifier) FlushDeferred() {
+	if n == nil || n.client.InQuietHours(time.Now()) {
+		return
+	}
+	queued, err := n.stor.GetDeferredSends()
+	if err != nil {
+		log.Printf("⚠️  Failed to load complaints held for quiet hours: %v", err)
+		return
+	}
+	if len(queued) == 0 {
+		return
+	}
+
+	var cs []notify.Complaint
+	for _, d := range queued {
+		var c notify.Complaint
+		if err := json.Unmarshal([]byte(d.Payload), &c); err != nil {
+			log.Printf("⚠️  Dropping unreadable held complaint %s: %v", d.ComplaintID, err)
+			n.stor.ClearDeferredSend(d.ComplaintID)
+			continue
+		}
+		cs = append(cs, c)
+	}
+	log.Printf("🌅 Quiet hours over: sending %d held complaints", len(cs))
+	if err := n.sendBatch(cs, 1); err != nil {
+		log.Printf("⚠️  Some held complaints failed to send and were queued for retry: %v", err)
+	}
+	for _, c := range cs {
+		if err := n.stor.ClearDeferredSend(c.Number); err != nil {
+			log.Printf("⚠️  Failed to clear held complaint %s: %v", c.Number, err)
+		}
+	}
+}
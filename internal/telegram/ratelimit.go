@@ -0,0 +1,150 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bot API limits. Telegram allows about 30 messages per second across all
+// chats and 20 per minute into a single group; past that it answers 429.
+const (
+	globalRatePerSec  = 30.0
+	globalBurst       = 30.0
+	perChatRatePerMin = 20.0
+	perChatBurst      = 20.0
+
+	// maxRateLimitRetries is how many 429 responses one call will wait out
+	// before giving up and returning the error.
+	maxRateLimitRetries = 3
+)
+
+// tokenBucket refills at rate tokens/second up to burst. reserve takes a
+// token immediately and reports how long the caller must wait before using
+// it; tokens may go negative so concurrent callers queue in order.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate, burst float64) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst}
+}
+
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// rateLimiter is shared by every call a Client makes: one global bucket,
+// one bucket per destination chat, and a pause window set when Telegram
+// answers 429 so all senders back off together, not just the one that
+// was refused.
+type rateLimiter struct {
+	mu          sync.Mutex
+	global      *tokenBucket
+	chats       map[string]*tokenBucket
+	pausedUntil time.Time
+
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		global: newTokenBucket(globalRatePerSec, globalBurst),
+		chats:  map[string]*tokenBucket{},
+		now:    time.Now,
+		sleep:  sleepCtx,
+	}
+}
+
+// delay reserves a slot for one request to chatID ("" = no per-chat limit)
+// and returns how long to wait before sending it.
+func (l *rateLimiter) delay(chatID string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	d := l.global.reserve(now)
+	if chatID != "" {
+		b, ok := l.chats[chatID]
+		if !ok {
+			b = newTokenBucket(perChatRatePerMin/60, perChatBurst)
+			l.chats[chatID] = b
+		}
+		if cd := b.reserve(now); cd > d {
+			d = cd
+		}
+	}
+	if p := l.pausedUntil.Sub(now); p > d {
+		d = p
+	}
+	return d
+}
+
+// wait blocks until a request to chatID may be sent, or until ctx is
+// done, returning its error.
+func (l *rateLimiter) wait(ctx context.Context, chatID string) error {
+	if d := l.delay(chatID); d > 0 {
+		return l.sleep(ctx, d)
+	}
+	return nil
+}
+
+// sleepCtx sleeps for d, returning early with ctx's error when it is done
+// first.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// pause holds every sender for d, as instructed by a 429's retry_after.
+func (l *rateLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := l.now().Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// perChatLimited reports whether method posts into a chat — a send* or an
+// edit* method — and so counts against that chat's per-minute allowance;
+// Telegram counts edits in a group as messages too. Callback answers,
+// deletions and polling only count globally.
+func perChatLimited(method string) bool {
+	return strings.HasPrefix(method, "send") || strings.HasPrefix(method, "edit")
+}
+
+// retryAfter returns the back-off a Bot API error response asks for, or 0
+// when the response is not a 429.
+func retryAfter(body []byte) time.Duration {
+	var resp apiResponse[json.RawMessage]
+	if json.Unmarshal(body, &resp) != nil || resp.ErrorCode != 429 {
+		return 0
+	}
+	if resp.Parameters == nil || resp.Parameters.RetryAfter <= 0 {
+		return time.Second
+	}
+	return time.Duration(resp.Parameters.RetryAfter) * time.Second
+}
@@ -0,0 +1,204 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"cmon/internal/i18n"
+)
+
+// resolveFlow is "Mark as Resolved": with QuickRemarks the user picks a
+// canned remark (resolveRemarks) or asks to type one; otherwise, or then,
+// they reply with a typed, voice or photo note (resolveNote). Either way the
+// complaint is resolved with it.
+var resolveFlow = &Flow{
+	Name:           "resolve",
+	CallbackPrefix: "remark",
+	States: map[string]State{
+		resolveRemarks: {
+			Enter:  func(c *Client, t *Turn) error { return c.enterRemarksPrompt(t, true) },
+			Handle: (*Client).handleRemarkChoice,
+			Ack:    "resolve.ack",
+		},
+		resolveNote: {
+			Enter:  func(c *Client, t *Turn) error { return c.enterRemarksPrompt(t, false) },
+			Handle: (*Client).handleResolutionNote,
+			Ack:    "resolve.ack",
+		},
+	},
+}
+
+// States of resolveFlow.
+const (
+	resolveRemarks = "remarks"
+	resolveNote    = "note"
+)
+
+// Choices on the quick-remarks keyboard besides a remark's index.
+const (
+	remarkCustom = "custom"
+	remarkCancel = "cancel"
+)
+
+// startResolve begins resolveFlow for the user who pressed "Mark as
+// Resolved" on a complaint's message.
+func (c *Client) startResolve(t *Turn) {
+	log.Printf("📝 Requesting resolution note for complaint %s from %s\n", t.Conv.ComplaintNumber, t.From.FirstName)
+	state := resolveNote
+	if len(c.QuickRemarks) > 0 {
+		state = resolveRemarks
+	}
+	if c.startConversation(t, resolveFlow, state) {
+		log.Printf("✓ Prompted %s for remarks\n", t.From.FirstName)
+	}
+}
+
+// enterRemarksPrompt sends a resolveFlow prompt for t's complaint.
+func (c *Client) enterRemarksPrompt(t *Turn, quick bool) error {
+	id, err := c.sendRemarksPrompt(t.From, t.Conv.ComplaintNumber, t.Conv.OriginalText, quick)
+	t.Conv.PromptMessageID = id
+	return err
+}
+
+// sendRemarksPrompt asks u for the remarks to resolve complaintNumber with
+// and returns the prompt's message ID. With quick it offers QuickRemarks as
+// buttons; otherwise it is a ForceReply for a typed or voice note.
+func (c *Client) sendRemarksPrompt(u User, complaintNumber, originalText string, quick bool) (int, error) {
+	mention := userMention(u)
+	consumerName := htmlEscape(consumerNameFromText(originalText))
+
+	msg := Message{ChatID: c.ChatID, ParseMode: "HTML"}
+	if quick {
+		msg.Text = i18n.T("resolve.prompt.quick", mention, htmlEscape(complaintNumber), consumerName)
+		msg.ReplyMarkup = c.remarksKeyboard(complaintNumber)
+	} else {
+		// Selective: true + @mention ensures only the button-clicker sees the force-reply prompt
+		msg.Text = i18n.T("resolve.prompt.note", mention, htmlEscape(complaintNumber), consumerName)
+		msg.ReplyMarkup = &ForceReply{
+			ForceReply:            true,
+			Selective:             true,
+			InputFieldPlaceholder: i18n.T("resolve.placeholder"),
+		}
+	}
+
+	prompt, err := doRequest[SendMessageResult](context.Background(), c, "sendMessage", msg)
+	if err != nil {
+		return 0, err
+	}
+	return prompt.MessageID, nil
+}
+
+// remarksKeyboard lays out QuickRemarks two to a row, then "✏️ Custom…"
+// and "❌ Cancel". Callbacks are "remark:COMPLAINT_NUMBER:CHOICE", the
+// choice being the remark's index so long remarks fit Telegram's 64-byte
+// callback data.
+func (c *Client) remarksKeyboard(complaintNumber string) *InlineKeyboardMarkup {
+	var rows [][]InlineKeyboardButton
+	for i, remark := range c.QuickRemarks {
+		button := InlineKeyboardButton{
+			Text:         remark,
+			CallbackData: fmt.Sprintf("remark:%s:%d", complaintNumber, i),
+		}
+		if i%2 == 0 {
+			rows = append(rows, []InlineKeyboardButton{button})
+		} else {
+			rows[len(rows)-1] = append(rows[len(rows)-1], button)
+		}
+	}
+	rows = append(rows, []InlineKeyboardButton{
+		{Text: i18n.T("resolve.custom"), CallbackData: fmt.Sprintf("remark:%s:%s", complaintNumber, remarkCustom)},
+		{Text: i18n.T("resolve.cancel"), CallbackData: fmt.Sprintf("remark:%s:%s", complaintNumber, remarkCancel)},
+	})
+	return &InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// handleRemarkChoice handles the quick-remarks keyboard: a remark resolves
+// the complaint with it, Custom moves to the typed-note prompt. A typed
+// reply to the keyboard prompt is taken as the note.
+func (c *Client) handleRemarkChoice(t *Turn) string {
+	if t.Callback == nil {
+		return c.handleResolutionNote(t)
+	}
+	switch t.Choice {
+	case remarkCancel:
+		t.answer(c, i18n.T("resolve.cancelled"))
+		log.Printf("❌ Resolution cancelled by %s\n", t.From.FirstName)
+		return stateDone
+	case remarkCustom:
+		return resolveNote
+	}
+	n, err := strconv.Atoi(t.Choice)
+	if err != nil || n < 0 || n >= len(c.QuickRemarks) {
+		t.answer(c, i18n.T("resolve.unknown"))
+		return resolveRemarks
+	}
+	note := c.QuickRemarks[n]
+	t.answer(c, i18n.T("resolve.resolving"))
+	log.Printf("📝 %s picked remark %q for complaint %s\n", t.From.FirstName, note, t.Conv.ComplaintNumber)
+	c.resolveWithNote(t.Session, t.Store, *t.Conv, note, t.From)
+	return stateDone
+}
+
+// handleResolutionNote resolves the complaint with the user's reply: a
+// voice note, a photo, or typed remarks ("cancel" gives up).
+func (c *Client) handleResolutionNote(t *Turn) string {
+	m := t.Message
+	switch {
+	case m == nil:
+		t.answer(c, i18n.T("resolve.reply_please"))
+	case m.Voice != nil:
+		c.handleVoiceRemark(t)
+	case len(m.Photo) > 0:
+		c.handlePhotoRemark(t)
+	case strings.EqualFold(strings.TrimSpace(m.Text), "cancel"):
+		log.Printf("❌ Resolution cancelled by keyword for user %s\n", t.From.FirstName)
+		c.send("sendMessage", Message{
+			ChatID:    c.ChatID,
+			Text:      i18n.T("command.cancelled"),
+			ParseMode: "HTML",
+		})
+	default:
+		log.Printf("📝 Received resolution note from %s for complaint %s\n", t.From.FirstName, t.Conv.ComplaintNumber)
+		c.resolveWithNote(t.Session, t.Store, *t.Conv, m.Text, t.From)
+	}
+	return stateDone
+}
+
+// deleteMessage removes a bot message (a remarks prompt) from ChatID.
+func (c *Client) deleteMessage(messageID int) {
+	if messageID <= 0 {
+		return
+	}
+	req := struct {
+		ChatID    string `json:"chat_id"`
+		MessageID int    `json:"message_id"`
+	}{
+		ChatID:    c.ChatID,
+		MessageID: messageID,
+	}
+	c.send("deleteMessage", req)
+}
+
+// userMention is @username if available, otherwise an HTML mention by user
+// ID. Selective ForceReply needs one to target only this user in a group.
+func userMention(u User) string {
+	if u.Username != "" {
+		return "@" + u.Username
+	}
+	return fmt.Sprintf("<a href=\"tg://user?id=%d\">%s</a>", u.ID, htmlEscape(u.FirstName))
+}
+
+// consumerNameFromText pulls the consumer name off the "👤 " line of a
+// complaint message, or "Unknown".
+func consumerNameFromText(text string) string {
+	if idx := strings.Index(text, "👤 "); idx != -1 {
+		nameStart := idx + len("👤 ")
+		if newlineIdx := strings.Index(text[nameStart:], "\n"); newlineIdx != -1 {
+			return text[nameStart : nameStart+newlineIdx]
+		}
+	}
+	return "Unknown"
+}
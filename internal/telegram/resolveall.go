@@ -0,0 +1,209 @@
+package telegram
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cmon/internal/i18n"
+	"cmon/internal/storage"
+)
+
+// bulkRemark is the portal remark /resolveall uses when none is given.
+const bulkRemark = "Supply restored"
+
+// bulkResolvePause spaces out the portal calls of a /resolveall so a
+// feeder's worth of resolutions doesn't hammer the website.
+var bulkResolvePause = time.Second
+
+// resolveAllFlow is /resolveall: the matched complaints are listed with
+// Resolve and Cancel buttons, and resolved one by one on confirmation.
+var resolveAllFlow = &Flow{
+	Name:           "resolveall",
+	CallbackPrefix: "resolveall",
+	States: map[string]State{
+		resolveAllConfirm: {
+			Enter:  (*Client).enterResolveAllConfirm,
+			Handle: (*Client).handleResolveAllConfirm,
+		},
+	},
+}
+
+// States of resolveAllFlow, and the keys of its Conversation.Data.
+const (
+	resolveAllConfirm = "confirm"
+
+	bulkIDsKey    = "ids"
+	bulkRemarkKey = "remark"
+	bulkLabelKey  = "label"
+)
+
+// isResolveAllCommand reports whether text is a /resolveall command.
+func isResolveAllCommand(text string) bool {
+	fields := strings.Fields(strings.TrimSpace(text))
+	return len(fields) > 0 && fields[0] == "/resolveall"
+}
+
+// handleResolveAllCommand processes "/resolveall <area or IDs> [| remark]"
+// for when a feeder comes back and many complaints resolve at once. An area
+// matches complaints whose area or village is that name; otherwise the
+// arguments are complaint numbers. Nothing is resolved until the sender
+// confirms. With Admins set, only they may use it.
+func (c *Client) handleResolveAllCommand(t *Turn) {
+	if len(c.Admins) > 0 && !c.isAdmin(t.From) {
+		c.sendTextMessage(i18n.T("resolveall.admins_only"), "HTML")
+		return
+	}
+	args := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(t.Message.Text), "/resolveall"))
+	remark := bulkRemark
+	if i := strings.Index(args, "|"); i >= 0 {
+		if r := strings.TrimSpace(args[i+1:]); r != "" {
+			remark = r
+		}
+		args = strings.TrimSpace(args[:i])
+	}
+	if args == "" {
+		c.sendTextMessage(i18n.T("resolveall.usage"), "HTML")
+		return
+	}
+
+	ids, label, unknown := matchBulkComplaints(t.Store, args)
+	if len(unknown) > 0 {
+		c.sendTextMessage(i18n.T("resolveall.unknown", htmlEscape(strings.Join(unknown, ", "))), "HTML")
+		return
+	}
+	if len(ids) == 0 {
+		c.sendTextMessage(i18n.T("resolveall.none", htmlEscape(args)), "HTML")
+		return
+	}
+
+	t.Conv = &storage.Conversation{Data: map[string]string{
+		bulkIDsKey:    strings.Join(ids, ","),
+		bulkRemarkKey: remark,
+		bulkLabelKey:  label,
+	}}
+	c.startConversation(t, resolveAllFlow, resolveAllConfirm)
+}
+
+// matchBulkComplaints resolves /resolveall's argument to complaint numbers.
+// When its first word is a stored complaint every word must be one, and
+// the ones that aren't come back as unknown. Otherwise it is an area or
+// village name, matched case-insensitively. label describes the selection.
+func matchBulkComplaints(stor *storage.Storage, args string) (ids []string, label string, unknown []string) {
+	fields := strings.Fields(strings.ReplaceAll(args, ",", " "))
+	if stor.Exists(fields[0]) {
+		for _, id := range fields {
+			if stor.Exists(id) {
+				ids = append(ids, id)
+			} else {
+				unknown = append(unknown, id)
+			}
+		}
+		return ids, i18n.T("resolveall.listed"), unknown
+	}
+
+	for _, id := range stor.GetAllSeenComplaints() {
+		if strings.EqualFold(strings.TrimSpace(stor.GetArea(id)), args) || strings.EqualFold(strings.TrimSpace(stor.GetVillage(id)), args) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, i18n.T("resolveall.in_area", args), nil
+}
+
+// enterResolveAllConfirm asks the sender to confirm the bulk resolution.
+func (c *Client) enterResolveAllConfirm(t *Turn) error {
+	ids := strings.Split(t.Conv.Data[bulkIDsKey], ",")
+	text := i18n.T("resolveall.confirm",
+		len(ids), htmlEscape(t.Conv.Data[bulkLabelKey]), htmlEscape(t.Conv.Data[bulkRemarkKey]), htmlEscape(strings.Join(ids, ", ")))
+	msg := Message{
+		ChatID:    c.ChatID,
+		Text:      text,
+		ParseMode: "HTML",
+		ReplyMarkup: &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{
+			{Text: i18n.T("resolveall.button", len(ids)), CallbackData: "resolveall::yes"},
+			{Text: i18n.T("resolve.cancel"), CallbackData: "resolveall::no"},
+		}}},
+	}
+	if t.Message != nil {
+		msg.ReplyToMessageID = t.Message.MessageID
+	}
+	prompt, err := doRequest[SendMessageResult](t.Ctx, c, "sendMessage", msg)
+	if err != nil {
+		return err
+	}
+	t.Conv.PromptMessageID = prompt.MessageID
+	return nil
+}
+
+// handleResolveAllConfirm acts on the confirmation buttons.
+func (c *Client) handleResolveAllConfirm(t *Turn) string {
+	switch {
+	case t.Callback == nil:
+		return resolveAllConfirm // ask again: only the buttons answer
+	case t.Choice != "yes":
+		t.answer(c, i18n.T("resolveall.cancelled"))
+		log.Printf("❌ /resolveall cancelled by %s\n", t.From.FirstName)
+		return stateDone
+	}
+	t.answer(c, i18n.T("resolve.resolving"))
+	c.runResolveAll(t, strings.Split(t.Conv.Data[bulkIDsKey], ","), t.Conv.Data[bulkRemarkKey], t.Conv.Data[bulkLabelKey])
+	return stateDone
+}
+
+// runResolveAll resolves ids one after another with remark, pausing
+// bulkResolvePause between portal calls, and keeps a progress message up
+// to date. Each failure is reported in the chat by resolveWithNote.
+func (c *Client) runResolveAll(t *Turn, ids []string, remark, label string) {
+	log.Printf("📦 %s is resolving %d complaints %s\n", t.From.FirstName, len(ids), label)
+	progress, err := doRequest[SendMessageResult](t.Ctx, c, "sendMessage", Message{
+		ChatID:    c.ChatID,
+		Text:      i18n.T("resolveall.progress", len(ids), htmlEscape(label), 0, len(ids)),
+		ParseMode: "HTML",
+	})
+	if err != nil {
+		log.Printf("⚠️  Failed to send /resolveall progress: %v\n", err)
+	}
+	update := func(text string) {
+		if progress.MessageID == 0 {
+			return
+		}
+		c.edit("editMessageText", EditMessageRequest{
+			ChatID:    c.ChatID,
+			MessageID: strconv.Itoa(progress.MessageID),
+			Text:      text,
+			ParseMode: "HTML",
+		})
+	}
+
+	resolved := 0
+	for i, id := range ids {
+		if i > 0 && bulkResolvePause > 0 {
+			select {
+			case <-t.Ctx.Done():
+				update(i18n.T("resolveall.stopped", i, len(ids), htmlEscape(label)))
+				return
+			case <-time.After(bulkResolvePause):
+			}
+		}
+		existed := t.Store.Exists(id)
+		c.resolveWithNote(t.Session, t.Store, storage.Conversation{
+			ComplaintNumber: id,
+			MessageID:       t.Store.GetMessageID(id),
+			OriginalText:    "👤 " + t.Store.GetConsumerName(id) + "\n",
+		}, remark, t.From)
+		if existed && !t.Store.Exists(id) {
+			resolved++
+		}
+		update(i18n.T("resolveall.progress", len(ids), htmlEscape(label), i+1, len(ids)))
+	}
+
+	summary := i18n.T("resolveall.done", resolved, len(ids), htmlEscape(label))
+	if resolved < len(ids) {
+		summary += i18n.T("resolveall.not_done", len(ids)-resolved)
+	}
+	update(summary)
+	log.Printf("📦 /resolveall done: %d/%d resolved\n", resolved, len(ids))
+}
@@ -0,0 +1,115 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cmon/internal/metrics"
+)
+
+// apiResponse is the envelope every Bot API method answers with. Result is
+// only meaningful when OK is true; otherwise ErrorCode/Description say why.
+type apiResponse[T any] struct {
+	OK          bool                `json:"ok"`
+	Result      T                   `json:"result"`
+	ErrorCode   int                 `json:"error_code"`
+	Description string              `json:"description"`
+	Parameters  *responseParameters `json:"parameters"`
+}
+
+type responseParameters struct {
+	RetryAfter      int   `json:"retry_after"`
+	MigrateToChatID int64 `json:"migrate_to_chat_id"`
+}
+
+// SendMessageResult is the part of the Message object that sendMessage and
+// sendPhoto return which we use.
+type SendMessageResult struct {
+	MessageID int   `json:"message_id"`
+	Date      int64 `json:"date"`
+	Chat      struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+}
+
+// APIError is a Bot API call that Telegram answered with ok=false.
+type APIError struct {
+	Method      string
+	Code        int // error_code: 400, 403, 429, …
+	Description string
+	RetryAfter  time.Duration // set on 429
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Telegram API error: %s: %d %s", e.Method, e.Code, e.Description)
+}
+
+// IsNotModified reports whether err is Telegram refusing an edit because
+// the message already has that content — harmless for idempotent edits.
+func IsNotModified(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == 400 && strings.Contains(apiErr.Description, "message is not modified")
+}
+
+// IsRateLimited reports whether err is a 429 that survived the client's
+// own retries.
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == 429
+}
+
+// decodeResponse unmarshals a Bot API body into T, turning ok=false into
+// an *APIError.
+func decodeResponse[T any](method string, body []byte) (T, error) {
+	var resp apiResponse[T]
+	if err := json.Unmarshal(body, &resp); err != nil {
+		var zero T
+		return zero, fmt.Errorf("failed to parse %s response (body %q): %w", method, string(body), err)
+	}
+	if !resp.OK {
+		apiErr := &APIError{Method: method, Code: resp.ErrorCode, Description: resp.Description}
+		if resp.Parameters != nil && resp.Parameters.RetryAfter > 0 {
+			apiErr.RetryAfter = time.Duration(resp.Parameters.RetryAfter) * time.Second
+		}
+		return resp.Result, apiErr
+	}
+	return resp.Result, nil
+}
+
+// doRequest calls a JSON Bot API method and decodes its result into T;
+// cancelling ctx abandons the call. Outbound message methods are counted in
+// the send metrics; long polling and other control-plane calls are not.
+func doRequest[T any](ctx context.Context, c *Client, method string, payload interface{}) (T, error) {
+	var result T
+	body, err := c.postJSON(ctx, method, payload)
+	if err == nil {
+		result, err = decodeResponse[T](method, body)
+	}
+	if isOutboundSendMethod(method) {
+		if err != nil {
+			metrics.TelegramSendFailuresTotal.Inc()
+		} else {
+			metrics.TelegramSendsTotal.Inc()
+		}
+	}
+	return result, err
+}
+
+// send calls a method whose result the caller doesn't need.
+func (c *Client) send(method string, payload interface{}) error {
+	_, err := doRequest[json.RawMessage](context.Background(), c, method, payload)
+	return err
+}
+
+// edit is send for editMessage* calls: an edit that changes nothing is
+// treated as success, since the message already shows what we wanted.
+func (c *Client) edit(method string, payload interface{}) error {
+	if err := c.send(method, payload); err != nil && !IsNotModified(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"cmon/internal/charts"
+	"cmon/internal/i18n"
+	"cmon/internal/storage"
+)
+
+// rollCallPinKey is the setting holding the pinned roll-call message ID,
+// per chat, so the next one can unpin it even after a restart.
+const rollCallPinKey = "rollcall_pinned_message:"
+
+// rollCallStats is what the morning roll-call reports.
+type rollCallStats struct {
+	Pending           int
+	Oldest            storage.HistoryEntry // zero ComplaintID when nothing is pending
+	ResolvedYesterday int
+}
+
+// PostRollCall sends the morning status message for now's day and pins it
+// in place of the previous one. Exposed for the scheduler in main.go.
+func (c *Client) PostRollCall(stor *storage.Storage, now time.Time) error {
+	if c == nil {
+		return nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	stats := rollCallStats{Pending: len(stor.GetAllSeenComplaints())}
+	var err error
+	if stats.ResolvedYesterday, err = stor.CountResolvedBetween(today.AddDate(0, 0, -1), today); err != nil {
+		return fmt.Errorf("count resolved: %w", err)
+	}
+	if stats.Oldest, _, err = stor.GetOldestOpenHistory(); err != nil {
+		return fmt.Errorf("find oldest complaint: %w", err)
+	}
+
+	result, err := doRequest[SendMessageResult](context.Background(), c, "sendMessage", Message{
+		ChatID:    c.ChatID,
+		Text:      formatRollCall(stats, now),
+		ParseMode: "HTML",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send roll-call: %w", err)
+	}
+	messageID := strconv.Itoa(result.MessageID)
+
+	if err := c.PinChatMessage(c.ChatID, messageID); err != nil {
+		return err
+	}
+	key := rollCallPinKey + c.ChatID
+	if previous, ok := stor.GetSetting(key); ok && previous != "" && previous != messageID {
+		// Yesterday's message may have been unpinned or deleted by hand.
+		if err := c.UnpinChatMessage(c.ChatID, previous); err != nil {
+			log.Printf("⚠️  Could not unpin the previous roll-call: %v", err)
+		}
+	}
+	if err := stor.SetSetting(key, messageID); err != nil {
+		log.Printf("⚠️  Failed to record the pinned roll-call: %v", err)
+	}
+	log.Printf("📌 Posted and pinned the morning roll-call (%d pending)", stats.Pending)
+	return nil
+}
+
+// formatRollCall renders the roll-call message.
+func formatRollCall(s rollCallStats, now time.Time) string {
+	text := i18n.T("rollcall.title", now.Format("Mon 02 Jan"), s.Pending)
+	if s.Oldest.ComplaintID != "" {
+		text += i18n.T("rollcall.oldest", htmlEscape(s.Oldest.ComplaintID))
+		if s.Oldest.Village != "" {
+			text += " (" + htmlEscape(s.Oldest.Village) + ")"
+		}
+		if !s.Oldest.FirstSeenAt.IsZero() {
+			text += i18n.T("rollcall.pending_for", charts.FormatDuration(now.Sub(s.Oldest.FirstSeenAt)))
+		}
+	}
+	text += i18n.T("rollcall.resolved_yesterday", s.ResolvedYesterday)
+	return text
+}
@@ -0,0 +1,65 @@
+package telegram
+
+import (
+	"bytes"
+	"log"
+	"strings"
+
+	"cmon/internal/i18n"
+)
+
+func isSLAReportCommand(text string) bool {
+	fields := strings.Fields(strings.TrimSpace(text))
+	return len(fields) > 0 && fields[0] == "/slareport"
+}
+
+// handleSLAReportCommand processes /slareport [YYYY-MM]: a short summary
+// of the month's SLA compliance (last month by default), followed by the
+// report as CSV and PDF documents.
+func (c *Client) handleSLAReportCommand(message *IncomingMessage) {
+	if c.SLAReports == nil {
+		return
+	}
+	args := strings.Fields(strings.TrimSpace(message.Text))[1:]
+	month, err := c.SLAReports.Month(strings.Join(args, " "))
+	if err != nil {
+		c.sendTextMessage(i18n.T("slareport.usage"), "HTML")
+		return
+	}
+	rep, err := c.SLAReports.Build(month)
+	if err != nil {
+		log.Printf("⚠️  /slareport failed: %v", err)
+		c.sendTextMessage(i18n.T("slareport.failed", htmlEscape(err.Error())), "HTML")
+		return
+	}
+
+	text := i18n.T("slareport.title", month.Format("January 2006"))
+	if rep.Total.Resolved == 0 {
+		c.sendTextMessage(text+i18n.T("slareport.none"), "HTML")
+		return
+	}
+	text += i18n.T("slareport.total", rep.Total.Within, rep.Total.Resolved, rep.Total.Compliance())
+	for _, row := range rep.Rows {
+		if row.Resolved > 0 {
+			text += i18n.T("slareport.row", htmlEscape(row.Label), row.Compliance(), row.Resolved)
+		}
+	}
+	c.sendTextMessage(text, "HTML")
+
+	for _, format := range []string{"csv", "pdf"} {
+		var buf bytes.Buffer
+		if format == "csv" {
+			err = rep.WriteCSV(&buf)
+		} else {
+			err = rep.WritePDF(&buf)
+		}
+		if err == nil {
+			err = c.SendDocument(c.ChatID, rep.FileName(format), buf.Bytes(), i18n.T("slareport.caption", month.Format("January 2006")))
+		}
+		if err != nil {
+			log.Printf("⚠️  /slareport: %s: %v", format, err)
+			c.sendTextMessage(i18n.T("slareport.send_failed", strings.ToUpper(format), htmlEscape(err.Error())), "HTML")
+			return
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Package telegramtest provides a fake Telegram Bot API for tests.
+//
+// The fake answers every method the telegram.Client uses: sends and edits
+// get a message back with a fresh message_id, getUpdates delivers whatever
+// the test queued, and everything else returns true. Each call is recorded
+// so a test can assert on what the bot would have said. Point a client at
+// it with TELEGRAM_API_URL=srv.URL.
+package telegramtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"cmon/internal/telegram"
+)
+
+// Call is one Bot API request received by the fake. Params holds the JSON
+// body, or the form fields of a multipart upload with each file recorded
+// under its field name as the uploaded file name.
+type Call struct {
+	Method string
+	Params map[string]interface{}
+}
+
+// Text returns the call's text or caption, whichever it carried.
+func (c Call) Text() string {
+	if s, ok := c.Params["text"].(string); ok {
+		return s
+	}
+	s, _ := c.Params["caption"].(string)
+	return s
+}
+
+// Server is a running fake Bot API.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	calls      []Call
+	nextMsgID  int
+	nextUpdate int
+	updates    []telegram.Update
+}
+
+// NewServer starts a fake Bot API. The caller must Close it.
+func NewServer() *Server {
+	s := &Server{nextMsgID: 100, nextUpdate: 1}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Calls returns the recorded calls to method, or every call when method is
+// empty, in arrival order.
+func (s *Server) Calls(method string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Call
+	for _, c := range s.calls {
+		if method == "" || c.Method == method {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// QueueUpdate makes u the next update getUpdates delivers. A zero
+// UpdateID is assigned the next one in sequence.
+func (s *Server) QueueUpdate(u telegram.Update) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u.UpdateID == 0 {
+		u.UpdateID = s.nextUpdate
+	}
+	if u.UpdateID >= s.nextUpdate {
+		s.nextUpdate = u.UpdateID + 1
+	}
+	s.updates = append(s.updates, u)
+}
+
+// handle serves /bot<token>/<method>.
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	if !strings.HasPrefix(path, "bot") || !strings.Contains(path, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	method := path[strings.LastIndex(path, "/")+1:]
+
+	params, err := readParams(r)
+	if err != nil {
+		reply(w, http.StatusBadRequest, map[string]interface{}{"ok": false, "error_code": 400, "description": err.Error()})
+		return
+	}
+
+	if method == "getUpdates" {
+		s.record(method, params)
+		reply(w, http.StatusOK, map[string]interface{}{"ok": true, "result": s.pendingUpdates(r, params)})
+		return
+	}
+
+	var result interface{} = true
+	switch {
+	case method == "getMe":
+		result = map[string]interface{}{"id": 1, "is_bot": true, "first_name": "cmon", "username": "cmon_test_bot"}
+	case strings.HasPrefix(method, "send"), strings.HasPrefix(method, "edit"):
+		result = s.message(params)
+	}
+	s.record(method, params)
+	reply(w, http.StatusOK, map[string]interface{}{"ok": true, "result": result})
+}
+
+func (s *Server) record(method string, params map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, Call{Method: method, Params: params})
+}
+
+// message builds the Message a send or edit returns. Edits keep the
+// message_id they were given.
+func (s *Server) message(params map[string]interface{}) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := params["message_id"]
+	if !ok {
+		s.nextMsgID++
+		id = s.nextMsgID
+	}
+	return map[string]interface{}{
+		"message_id": id,
+		"date":       time.Now().Unix(),
+		"chat":       map[string]interface{}{"id": chatID(params["chat_id"])},
+	}
+}
+
+// pendingUpdates returns the queued updates at or after the request's
+// offset, dropping the ones it acknowledges. With none queued it waits a
+// moment, as a long poll would, so a polling loop does not spin.
+func (s *Server) pendingUpdates(r *http.Request, params map[string]interface{}) []telegram.Update {
+	offset := 0
+	if v, ok := params["offset"].(float64); ok {
+		offset = int(v)
+	}
+	deadline := time.Now().Add(100 * time.Millisecond)
+	for {
+		s.mu.Lock()
+		kept := s.updates[:0]
+		for _, u := range s.updates {
+			if u.UpdateID >= offset {
+				kept = append(kept, u)
+			}
+		}
+		s.updates = kept
+		out := append([]telegram.Update{}, kept...)
+		s.mu.Unlock()
+
+		if len(out) > 0 || time.Now().After(deadline) {
+			return out
+		}
+		select {
+		case <-r.Context().Done():
+			return out
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func readParams(r *http.Request) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			return nil, fmt.Errorf("decode JSON body: %w", err)
+		}
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return nil, fmt.Errorf("parse multipart body: %w", err)
+		}
+		for k, v := range r.MultipartForm.Value {
+			params[k] = v[0]
+		}
+		for k, files := range r.MultipartForm.File {
+			params[k] = files[0].Filename
+		}
+	default:
+		if err := r.ParseForm(); err != nil {
+			return nil, fmt.Errorf("parse form body: %w", err)
+		}
+		for k, v := range r.Form {
+			params[k] = v[0]
+		}
+	}
+	return params, nil
+}
+
+// chatID echoes chat_id back as a number when it is one.
+func chatID(v interface{}) interface{} {
+	if s, ok := v.(string); ok {
+		var n int64
+		if _, err := fmt.Sscan(s, &n); err == nil {
+			return n
+		}
+	}
+	return v
+}
+
+func reply(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
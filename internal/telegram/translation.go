@@ -0,0 +1,111 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"cmon/internal/msgtmpl"
+	"cmon/internal/notify"
+)
+
+// EditTranslation implements notify.TranslationEditor: complaints go out
+// in English, and once c's Gujarati translation is ready its message is
+// rendered again with it, keeping who is handling it, the notes, the age
+// footer and the keyboard. A digest gets c's Gujarati name and details
+// under its entry. A complaint still held for quiet hours is re-queued
+// with the translation instead; one without a message (resolved
+// meanwhile, say) is left alone.
+func (n *Notifier) EditTranslation(c notify.Complaint) error {
+	messageID := n.stor.GetMessageID(c.Number)
+	if messageID == "" {
+		if n.held(c.Number) {
+			return n.deferSend(c)
+		}
+		return nil
+	}
+	if inDigest(n.stor, c.Number) {
+		line := digestGujarati(c)
+		if line == "" {
+			return nil
+		}
+		n.client.setDigestGujarati(c.Number, line)
+		return n.redrawDigest(n.client.ChatIDFor(c.Belt, c.Category), messageID, "")
+	}
+	if n.client.RedactPII {
+		c = c.Redacted()
+	}
+	text, err := n.client.Templates.Render(msgtmpl.Telegram, c)
+	if err != nil {
+		return err
+	}
+	by, _, _ := n.stor.GetAcknowledgment(c.Number)
+	notes, err := n.stor.GetNotes(c.Number)
+	if err != nil {
+		log.Printf("⚠️  Failed to load notes of complaint %s: %v\n", c.Number, err)
+	}
+	_, footerDays, _ := n.stor.GetMessageText(c.Number)
+	err = n.client.edit("editMessageText", EditMessageRequest{
+		ChatID:      n.client.ChatIDFor(c.Belt, c.Category),
+		MessageID:   messageID,
+		Text:        n.client.complaintText(text, by, footerDays, notes),
+		ParseMode:   "HTML",
+		ReplyMarkup: n.client.complaintKeyboard(c.Number, by),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add the translation to complaint %s: %w", c.Number, err)
+	}
+	if err := n.stor.SetMessageText(c.Number, text); err != nil {
+		log.Printf("⚠️  Failed to keep Telegram message text for complaint %s: %v", c.Number, err)
+	}
+	return nil
+}
+
+// held reports whether complaintID is queued until quiet hours end.
+func (n *Notifier) held(complaintID string) bool {
+	queued, err := n.stor.GetDeferredSends()
+	if err != nil {
+		log.Printf("⚠️  Failed to load complaints held for quiet hours: %v", err)
+		return false
+	}
+	for _, d := range queued {
+		if d.ComplaintID == complaintID {
+			return true
+		}
+	}
+	return false
+}
+
+// digestGujarati is c's entry line in a digest once translated: its
+// Gujarati name and details, or "" when neither was translated.
+func digestGujarati(c notify.Complaint) string {
+	var parts []string
+	for _, p := range []string{c.NameGu, c.DescriptionGu} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " — ")
+}
+
+// setDigestGujarati keeps line for complaintNumber's digest entry; an
+// empty line forgets it.
+func (c *Client) setDigestGujarati(complaintNumber, line string) {
+	c.digestMu.Lock()
+	defer c.digestMu.Unlock()
+	if line == "" {
+		delete(c.digestGu, complaintNumber)
+		return
+	}
+	if c.digestGu == nil {
+		c.digestGu = make(map[string]string)
+	}
+	c.digestGu[complaintNumber] = line
+}
+
+// digestGujaratiFor returns the line setDigestGujarati kept, or "".
+func (c *Client) digestGujaratiFor(complaintNumber string) string {
+	c.digestMu.Lock()
+	defer c.digestMu.Unlock()
+	return c.digestGu[complaintNumber]
+}
@@ -0,0 +1,213 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"cmon/internal/api"
+	"cmon/internal/i18n"
+	"cmon/internal/notify"
+	"cmon/internal/session"
+	"cmon/internal/storage"
+)
+
+// undoRemark is the portal remark for reopening an undone resolution.
+const undoRemark = "Resolution undone"
+
+// undoable is a resolution whose Undo button is still live: what it takes
+// to put the complaint back as it was, and who resolved it.
+type undoable struct {
+	record       storage.Record
+	messageID    string
+	text         string // complaint message text as sent; "" if not stored
+	ackBy        string
+	ackByID      int64
+	resolvedBy   string
+	resolvedByID int64
+	timer        *time.Timer
+}
+
+// snapshotForUndo captures conv's complaint before by resolves it, or
+// returns nil when UndoWindow is off, the complaint is not stored, or it
+// was announced in a digest.
+func (c *Client) snapshotForUndo(stor *storage.Storage, conv storage.Conversation, by User) *undoable {
+	if c.UndoWindow <= 0 || inDigest(stor, conv.ComplaintNumber) {
+		return nil
+	}
+	rec, ok := stor.GetRecord(conv.ComplaintNumber)
+	if !ok {
+		return nil
+	}
+	u := &undoable{record: rec, messageID: conv.MessageID, resolvedBy: userDisplayName(by), resolvedByID: by.ID}
+	if text, _, ok := stor.GetMessageText(conv.ComplaintNumber); ok {
+		u.text = text
+	} else if conv.OriginalText != "" {
+		u.text = htmlEscape(conv.OriginalText)
+	}
+	u.ackBy, u.ackByID, _ = stor.GetAcknowledgment(conv.ComplaintNumber)
+	return u
+}
+
+// undoKeyboard is the single "↩️ Undo (60s)" button under a resolved
+// complaint.
+func (c *Client) undoKeyboard(complaintNumber string) *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{{
+		Text:         i18n.T("undo.button", undoWindowLabel(c.UndoWindow)),
+		CallbackData: "undo:" + complaintNumber,
+	}}}}
+}
+
+// undoWindowLabel renders the window as "60s" or "5m".
+func undoWindowLabel(d time.Duration) string {
+	if d >= time.Minute && d%time.Minute == 0 {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%ds", int(d.Round(time.Second).Seconds()))
+}
+
+// armUndo keeps u for UndoWindow, then drops it and its button.
+func (c *Client) armUndo(u *undoable) {
+	id := u.record.ComplaintID
+	c.undoMu.Lock()
+	defer c.undoMu.Unlock()
+	if c.undos == nil {
+		c.undos = make(map[string]*undoable)
+	}
+	if old := c.undos[id]; old != nil {
+		old.timer.Stop()
+	}
+	c.undos[id] = u
+	u.timer = time.AfterFunc(c.UndoWindow, func() {
+		if c.takeUndo(id) == u {
+			c.clearKeyboard(u.messageID)
+		}
+	})
+}
+
+// takeUndo removes and returns complaintNumber's live undo, if any.
+func (c *Client) takeUndo(complaintNumber string) *undoable {
+	c.undoMu.Lock()
+	defer c.undoMu.Unlock()
+	u := c.undos[complaintNumber]
+	if u != nil {
+		delete(c.undos, complaintNumber)
+		u.timer.Stop()
+	}
+	return u
+}
+
+// claimUndo is takeUndo for a click by who: the undo is only taken when
+// who resolved the complaint or is one of Admins. allowed is false when it
+// was refused, leaving it live; u is nil when there is nothing to undo.
+func (c *Client) claimUndo(complaintNumber string, who User) (u *undoable, allowed bool) {
+	c.undoMu.Lock()
+	defer c.undoMu.Unlock()
+	u = c.undos[complaintNumber]
+	if u == nil {
+		return nil, true
+	}
+	if !c.mayResolve(who, u.resolvedBy, u.resolvedByID) {
+		return u, false
+	}
+	delete(c.undos, complaintNumber)
+	u.timer.Stop()
+	return u, true
+}
+
+// reopenedComplaint is rec as the other channels announce it once its
+// resolution has been undone.
+func reopenedComplaint(rec storage.Record) notify.Complaint {
+	return notify.Complaint{
+		Number:          rec.ComplaintID,
+		Belt:            rec.Belt,
+		ComplainantName: rec.ConsumerName,
+		MobileNo:        rec.MobileNo,
+		ConsumerNo:      rec.ConsumerNo,
+		ComplainDate:    rec.ComplainDate,
+		Description:     rec.Description,
+		ExactLocation:   rec.Address,
+		Area:            rec.Area,
+		Village:         rec.Village,
+		Category:        rec.Category,
+	}
+}
+
+// handleUndoCallback processes a click on "↩️ Undo": within the window,
+// and only for the resolver or an admin, it moves the complaint back to
+// pending on the portal, restores it to storage, turns its message back
+// into the active complaint and tells Reopened.
+func (c *Client) handleUndoCallback(sc *session.Client, query *CallbackQuery, complaintNumber string, stor *storage.Storage) {
+	u, allowed := c.claimUndo(complaintNumber, query.From)
+	if !allowed {
+		c.answerCallbackQuery(query.ID, i18n.T("undo.not_allowed", u.resolvedBy))
+		return
+	}
+	if u == nil {
+		c.answerCallbackQuery(query.ID, i18n.T("undo.too_late"))
+		if query.Message != nil {
+			c.clearKeyboard(fmt.Sprintf("%d", query.Message.MessageID))
+		}
+		return
+	}
+
+	if err := api.ReopenComplaint(sc, u.record.APIID, undoRemark, c.DebugMode); err != nil {
+		log.Printf("⚠️  Failed to undo resolution of %s: %v\n", complaintNumber, err)
+		c.answerCallbackQuery(query.ID, i18n.T("undo.failed"))
+		c.clearKeyboard(u.messageID)
+		return
+	}
+
+	if err := stor.SaveMultiple([]storage.Record{u.record}); err != nil {
+		log.Printf("⚠️  Failed to restore complaint %s: %v\n", complaintNumber, err)
+	}
+	if u.text != "" {
+		if err := stor.SetMessageText(complaintNumber, u.text); err != nil {
+			log.Printf("⚠️  Failed to restore message text of %s: %v\n", complaintNumber, err)
+		}
+	}
+	if u.ackBy != "" {
+		if _, err := stor.MarkAcknowledged(complaintNumber, u.ackBy, u.ackByID); err != nil {
+			log.Printf("⚠️  Failed to restore acknowledgment of %s: %v\n", complaintNumber, err)
+		}
+	}
+
+	req := EditMessageRequest{
+		ChatID:      c.ChatID,
+		MessageID:   u.messageID,
+		Text:        c.complaintText(u.text, u.ackBy, 0, c.complaintNotes(complaintNumber, stor)),
+		ParseMode:   "HTML",
+		ReplyMarkup: c.complaintKeyboard(complaintNumber, u.ackBy),
+	}
+	if u.text == "" {
+		req.Text = fmt.Sprintf("📋 Complaint : <b>%s</b>", htmlEscape(complaintNumber))
+	}
+	if err := c.edit("editMessageText", req); err != nil {
+		log.Printf("⚠️  Failed to restore message of %s: %v\n", complaintNumber, err)
+	}
+
+	if c.Reopened != nil {
+		if err := c.Reopened(reopenedComplaint(u.record)); err != nil {
+			log.Printf("⚠️  Failed to publish the reopening of %s: %v\n", complaintNumber, err)
+		}
+	}
+
+	c.answerCallbackQuery(query.ID, i18n.T("undo.done"))
+	log.Printf("↩️  %s undid the resolution of complaint %s\n", query.From.FirstName, complaintNumber)
+}
+
+// clearKeyboard removes the buttons under a message in ChatID.
+func (c *Client) clearKeyboard(messageID string) {
+	req := struct {
+		ChatID      string                `json:"chat_id"`
+		MessageID   string                `json:"message_id"`
+		ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup"`
+	}{
+		ChatID:      c.ChatID,
+		MessageID:   messageID,
+		ReplyMarkup: &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{}},
+	}
+	if err := c.edit("editMessageReplyMarkup", req); err != nil {
+		log.Printf("⚠️  Failed to remove buttons from message %s: %v\n", messageID, err)
+	}
+}
@@ -0,0 +1,102 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Voice is the audio of a voice message.
+type Voice struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	Duration     int    `json:"duration"`
+	MimeType     string `json:"mime_type,omitempty"`
+	FileSize     int64  `json:"file_size,omitempty"`
+}
+
+// Transcriber turns a voice note into text; *translate.Translator is one.
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio []byte, mimeType string) (string, error)
+}
+
+// voiceNoteRemark is the portal remark for a voice note that could not be
+// transcribed.
+const voiceNoteRemark = "[voice note attached]"
+
+// maxDownloadBytes is the largest file the Bot API serves through getFile.
+const maxDownloadBytes = 20 << 20
+
+// DownloadFile fetches a file the bot has received, by file ID.
+func (c *Client) DownloadFile(fileID string) ([]byte, error) {
+	file, err := doRequest[struct {
+		FilePath string `json:"file_path"`
+	}](context.Background(), c, "getFile", map[string]string{"file_id": fileID})
+	if err != nil {
+		return nil, fmt.Errorf("getFile: %w", err)
+	}
+	if file.FilePath == "" {
+		return nil, fmt.Errorf("getFile returned no path")
+	}
+
+	resp, err := c.httpClient.Get(fmt.Sprintf("%s/file/bot%s/%s", c.apiBaseURL(), c.BotToken, file.FilePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download file: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(data) > maxDownloadBytes {
+		return nil, fmt.Errorf("file is over %d bytes", maxDownloadBytes)
+	}
+	return data, nil
+}
+
+// handleVoiceRemark resolves a complaint with a voice note sent in reply
+// to its remarks prompt. The note is transcribed when a Transcriber is set;
+// otherwise, or if that fails, the portal gets voiceNoteRemark. Either way
+// the file ID is kept in the complaint's history.
+func (c *Client) handleVoiceRemark(t *Turn) {
+	complaintNumber, voice := t.Conv.ComplaintNumber, t.Message.Voice
+	log.Printf("🎤 Received voice resolution note from %s for complaint %s\n", t.From.FirstName, complaintNumber)
+
+	note := c.transcribeVoice(t.Ctx, voice)
+	if err := t.Store.SetHistoryVoiceNote(complaintNumber, voice.FileID); err != nil {
+		log.Printf("⚠️  Failed to keep voice note for complaint %s: %v\n", complaintNumber, err)
+	}
+	c.resolveWithNote(t.Session, t.Store, *t.Conv, note, t.From)
+}
+
+// transcribeVoice returns v's transcription, or voiceNoteRemark.
+func (c *Client) transcribeVoice(ctx context.Context, v *Voice) string {
+	if c.Transcriber == nil {
+		return voiceNoteRemark
+	}
+	audio, err := c.DownloadFile(v.FileID)
+	if err != nil {
+		log.Printf("⚠️  Failed to download voice note: %v\n", err)
+		return voiceNoteRemark
+	}
+	mimeType := v.MimeType
+	if mimeType == "" {
+		mimeType = "audio/ogg"
+	}
+	text, err := c.Transcriber.Transcribe(ctx, audio, mimeType)
+	if err != nil {
+		log.Printf("⚠️  Failed to transcribe voice note: %v\n", err)
+		return voiceNoteRemark
+	}
+	if text = strings.TrimSpace(text); text == "" {
+		return voiceNoteRemark
+	}
+	log.Printf("🎤 Transcribed voice note: %q\n", text)
+	return text
+}
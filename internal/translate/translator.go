@@ -1,4 +1,5 @@
-// Package translate provides Gemini AI translation for CMON.
+// Package translate provides Gemini AI translation (and voice-note
+// transcription and category fallback) for CMON.
 //
 // Translates complaint fields from English-script Gujarati (transliteration)
 // to proper Gujarati script using the Gemini API. For example:
@@ -6,20 +7,24 @@
 //   - "LITE NATHI" → "લાઇટ નથી" (no electricity)
 //
 // Graceful degradation: if API key is not set, translation is disabled.
-// On 429 rate limit errors, returns empty string so only English is sent.
+// On 429 rate limit errors, returns ErrRateLimited so callers can back off:
+// the complaint translation queue waits and tries again.
 package translate
 
 import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"strings"
 
+	"cmon/internal/category"
 	"cmon/internal/config"
+	"cmon/internal/proxy"
 )
 
 const systemPrompt = `You are a translator for an Indian electricity complaint system.
@@ -36,6 +41,10 @@ Rules:
 - If a field is already in English (like a proper name), transliterate it phonetically to Gujarati script
 - Output ONLY the translated fields in the exact same format, nothing else`
 
+// ErrRateLimited is returned when Gemini answers 429; callers back off or
+// go without.
+var ErrRateLimited = errors.New("rate limited")
+
 // Translator wraps the Gemini API client for transliteration.
 type Translator struct {
 	apiKey string
@@ -60,6 +69,7 @@ func NewTranslator(_ context.Context, apiKey string, cfg *config.Config) (*Trans
 		client: &http.Client{
 			Timeout: cfg.HTTPTimeout,
 			Transport: &http.Transport{
+				Proxy:               proxy.Func,
 				MaxIdleConns:        cfg.HTTPMaxConns,
 				MaxIdleConnsPerHost: cfg.HTTPMaxConns / 10,
 			},
@@ -78,7 +88,14 @@ type content struct {
 }
 
 type part struct {
-	Text string `json:"text"`
+	Text       string      `json:"text,omitempty"`
+	InlineData *inlineData `json:"inline_data,omitempty"`
+}
+
+// inlineData carries a file (audio for Transcribe) inside the request.
+type inlineData struct {
+	MimeType string `json:"mime_type"`
+	Data     []byte `json:"data"` // base64 in JSON, as the API expects
 }
 
 type geminiResponse struct {
@@ -98,7 +115,7 @@ type geminiResponse struct {
 // BatchTranslateToGujarati translates multiple fields in a single Gemini API call.
 //
 // Sends all fields as a structured prompt and parses the response.
-// Returns empty strings on 429 rate limit (caller sends English-only).
+// Returns ErrRateLimited on 429 (the caller retries later).
 func (t *Translator) BatchTranslateToGujarati(ctx context.Context, texts []string) ([]string, error) {
 	if t == nil || len(texts) == 0 {
 		return texts, nil
@@ -124,9 +141,75 @@ func (t *Translator) BatchTranslateToGujarati(ctx context.Context, texts []strin
 		},
 	}
 
+	responseText, err := t.generate(ctx, reqBody)
+	if errors.Is(err, ErrRateLimited) {
+		log.Println("  ⚠️  Gemini 429 rate limit on translation")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the structured response
+	return parseTranslationResponse(responseText, texts), nil
+}
+
+const transcribePrompt = `Transcribe this voice note from an electricity lineman about the repair done on a complaint.
+It may be in Gujarati, Hindi or English. Write it in the language spoken, in its own script.
+Output ONLY the transcription, nothing else. If nothing intelligible is said, output nothing.`
+
+// Transcribe turns a voice note (e.g. Telegram's audio/ogg) into text.
+// Returns ErrRateLimited on 429, and "" when nothing intelligible was said.
+func (t *Translator) Transcribe(ctx context.Context, audio []byte, mimeType string) (string, error) {
+	if t == nil {
+		return "", fmt.Errorf("transcription not configured")
+	}
+	reqBody := geminiRequest{
+		Contents: []content{{Parts: []part{
+			{Text: transcribePrompt},
+			{InlineData: &inlineData{MimeType: mimeType, Data: audio}},
+		}}},
+	}
+	text, err := t.generate(ctx, reqBody)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(text), nil
+}
+
+const classifyPrompt = `Classify this electricity complaint from rural Gujarat. The description may be
+Gujarati written in English letters. Answer with ONLY one key from the list below, nothing else.
+If none fits, answer "other".
+
+%s
+Complaint: %s`
+
+// Classify picks the category of a complaint description no keyword rule
+// matched; it is the category.Classifier Fallback. Returns ErrRateLimited
+// on 429.
+func (t *Translator) Classify(ctx context.Context, description string, categories []category.Category) (string, error) {
+	if t == nil {
+		return "", fmt.Errorf("classification not configured")
+	}
+	var list strings.Builder
+	for _, c := range categories {
+		fmt.Fprintf(&list, "- %s: %s\n", c.Key, c.Label)
+	}
+	reqBody := geminiRequest{
+		Contents: []content{{Parts: []part{{Text: fmt.Sprintf(classifyPrompt, list.String(), description)}}}},
+	}
+	text, err := t.generate(ctx, reqBody)
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(strings.TrimSpace(text), `"'.`), nil
+}
+
+// generate posts reqBody to the model and returns the first candidate's
+// text. A 429 is returned as ErrRateLimited.
+func (t *Translator) generate(ctx context.Context, reqBody geminiRequest) (string, error) {
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return "", fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	apiURL := fmt.Sprintf(
@@ -135,51 +218,47 @@ func (t *Translator) BatchTranslateToGujarati(ctx context.Context, texts []strin
 
 	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := t.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("API request failed: %w", err)
+		return "", fmt.Errorf("API request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return "", fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Handle 429 rate limit — return empty so caller sends English-only
+	// Handle 429 rate limit so the caller can degrade gracefully
 	if resp.StatusCode == 429 {
-		log.Println("  ⚠️  Gemini 429 rate limit — skipping translation")
-		return nil, fmt.Errorf("rate limited")
+		return "", ErrRateLimited
 	}
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return "", fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
 	}
 
 	var geminiResp geminiResponse
 	if err := json.Unmarshal(body, &geminiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return "", fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if geminiResp.Error != nil {
 		if geminiResp.Error.Code == 429 {
-			log.Println("  ⚠️  Gemini 429 rate limit — skipping translation")
-			return nil, fmt.Errorf("rate limited")
+			return "", ErrRateLimited
 		}
-		return nil, fmt.Errorf("API error: %s", geminiResp.Error.Message)
+		return "", fmt.Errorf("API error: %s", geminiResp.Error.Message)
 	}
 
 	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("empty response from Gemini")
+		return "", fmt.Errorf("empty response from Gemini")
 	}
 
-	// Parse the structured response
-	responseText := geminiResp.Candidates[0].Content.Parts[0].Text
-	return parseTranslationResponse(responseText, texts), nil
+	return geminiResp.Candidates[0].Content.Parts[0].Text, nil
 }
 
 // parseTranslationResponse extracts translated fields from Gemini's response.
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package update
+
+import (
+	"os"
+	"syscall"
+)
+
+// Restart replaces the process with exe, keeping its PID, arguments and
+// environment, so a service manager sees the same process carry on. It
+// only returns on failure.
+func Restart(exe string) error {
+	return syscall.Exec(exe, os.Args, os.Environ())
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package update
+
+import (
+	"os"
+	"os/exec"
+)
+
+// Restart starts exe with the same arguments, environment and console.
+// Windows has no exec, so the caller must exit once it returns nil.
+func Restart(exe string) error {
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Start()
+}
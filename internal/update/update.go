@@ -0,0 +1,284 @@
+// Package update checks GitHub for a newer cmon release and, when asked,
+// swaps the running binary for it. The running version is embedded at
+// build time (buildinfo.Version, as the release workflow sets it); a build
+// without one is "dev" and never counts as outdated.
+package update
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAPIBase = "https://api.github.com"
+	// checkTimeout bounds the release lookup; downloadTimeout the binary.
+	checkTimeout    = 30 * time.Second
+	downloadTimeout = 10 * time.Minute
+	// maxBinarySize refuses downloads that cannot be a cmon build.
+	maxBinarySize = 256 << 20
+	// maxChecksumsSize bounds the SHA256SUMS download.
+	maxChecksumsSize = 64 << 10
+)
+
+// ChecksumsName is the release file listing each build's SHA-256, in
+// sha256sum's format. Apply refuses a release without it.
+const ChecksumsName = "SHA256SUMS"
+
+// Release is the part of a GitHub release the checker uses.
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	URL    string  `json:"html_url"`
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is one file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Asset returns the release file called name.
+func (r Release) Asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// AssetName is the release file built for goos/goarch, as named by the
+// release workflow: cmon-linux-amd64, cmon-windows-amd64.exe, …
+func AssetName(goos, goarch string) string {
+	name := "cmon-" + goos + "-" + goarch
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Checker looks up the latest release of Repo and compares it with
+// Current. A nil *Checker is valid and never finds an update.
+type Checker struct {
+	Repo    string // "owner/name"
+	Current string // running version, e.g. "v1.4.0"
+	// APIBase overrides https://api.github.com for tests.
+	APIBase string
+
+	client *http.Client
+}
+
+// New returns a Checker for repo, or nil when repo is empty.
+func New(repo, current string) *Checker {
+	if repo == "" {
+		return nil
+	}
+	return &Checker{Repo: repo, Current: current, client: &http.Client{}}
+}
+
+// Latest fetches the newest published release.
+func (c *Checker) Latest(ctx context.Context) (Release, error) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	base := c.APIBase
+	if base == "" {
+		base = defaultAPIBase
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/repos/"+c.Repo+"/releases/latest", nil)
+	if err != nil {
+		return Release{}, fmt.Errorf("release request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "cmon-update")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Release{}, fmt.Errorf("release lookup: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+		return Release{}, fmt.Errorf("release lookup: HTTP %d", resp.StatusCode)
+	}
+	var rel Release
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&rel); err != nil {
+		return Release{}, fmt.Errorf("release lookup: %w", err)
+	}
+	if rel.Tag == "" {
+		return Release{}, errors.New("release lookup: no tag in the latest release")
+	}
+	return rel, nil
+}
+
+// Check reports the latest release and whether it is newer than Current.
+func (c *Checker) Check(ctx context.Context) (Release, bool, error) {
+	if c == nil {
+		return Release{}, false, nil
+	}
+	rel, err := c.Latest(ctx)
+	if err != nil {
+		return Release{}, false, err
+	}
+	return rel, Newer(c.Current, rel.Tag), nil
+}
+
+// Apply downloads rel's build for this platform and puts it in place of
+// exe, the running binary; it takes effect on the next start (see
+// Restart). The download goes to a temporary file next to exe first, so a
+// failed or partial download leaves exe untouched, and is only installed
+// when its SHA-256 matches the release's SHA256SUMS.
+func (c *Checker) Apply(ctx context.Context, rel Release, exe string) error {
+	name := AssetName(runtime.GOOS, runtime.GOARCH)
+	asset, ok := rel.Asset(name)
+	if !ok {
+		return fmt.Errorf("release %s has no %s build", rel.Tag, name)
+	}
+	sums, ok := rel.Asset(ChecksumsName)
+	if !ok {
+		return fmt.Errorf("release %s has no %s to verify %s against", rel.Tag, ChecksumsName, name)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, downloadTimeout)
+	defer cancel()
+	want, err := c.checksum(ctx, sums, name)
+	if err != nil {
+		return err
+	}
+	body, err := c.download(ctx, asset, maxBinarySize)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".cmon-update-*")
+	if err != nil {
+		return fmt.Errorf("download %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed into place
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, h), body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("download %s: %w", name, err)
+	}
+	if n == 0 || n > maxBinarySize {
+		return fmt.Errorf("download %s: unexpected size %d bytes", name, n)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		return fmt.Errorf("download %s: SHA-256 %s does not match %s (%s)", name, got, ChecksumsName, want)
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return fmt.Errorf("install %s: %w", name, err)
+	}
+
+	// Windows cannot replace a running executable, but can rename it.
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return fmt.Errorf("install %s: %w", name, err)
+		}
+	}
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		return fmt.Errorf("install %s: %w", name, err)
+	}
+	return nil
+}
+
+// download starts fetching asset, returning its body limited to one byte
+// more than limit so oversized files can be told apart.
+func (c *Checker) download(ctx context.Context, asset Asset, limit int64) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, asset.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("download request: %w", err)
+	}
+	req.Header.Set("User-Agent", "cmon-update")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", asset.Name, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("download %s: HTTP %d", asset.Name, resp.StatusCode)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(resp.Body, limit+1), resp.Body}, nil
+}
+
+// checksum downloads sums, a SHA256SUMS file, and returns the hex SHA-256
+// it lists for name.
+func (c *Checker) checksum(ctx context.Context, sums Asset, name string) (string, error) {
+	body, err := c.download(ctx, sums, maxChecksumsSize)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	sc := bufio.NewScanner(body)
+	for sc.Scan() {
+		// "<hex>  <name>", or "<hex> *<name>" for sha256sum -b.
+		sum, file, ok := strings.Cut(strings.TrimSpace(sc.Text()), " ")
+		if ok && strings.TrimPrefix(strings.TrimSpace(file), "*") == name && len(sum) == sha256.Size*2 {
+			return strings.ToLower(sum), nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return "", fmt.Errorf("download %s: %w", sums.Name, err)
+	}
+	return "", fmt.Errorf("%s lists no checksum for %s", sums.Name, name)
+}
+
+// Newer reports whether latest is a higher version than current. Versions
+// are "v1.2.3" with optional "v" and fewer parts; anything after "-" or
+// "+" is ignored. Unparseable versions, like "dev", are never newer or
+// outdated.
+func Newer(current, latest string) bool {
+	cur, ok1 := parseVersion(current)
+	lat, ok2 := parseVersion(latest)
+	if !ok1 || !ok2 {
+		return false
+	}
+	for i := range cur {
+		if lat[i] != cur[i] {
+			return lat[i] > cur[i]
+		}
+	}
+	return false
+}
+
+// parseVersion splits "v1.2.3" into its major, minor and patch numbers.
+func parseVersion(v string) ([3]int, bool) {
+	var out [3]int
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) > 3 {
+		return out, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return out, false
+		}
+		out[i] = n
+	}
+	return out, true
+}
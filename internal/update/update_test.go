@@ -0,0 +1,137 @@
+package update
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestNewer(t *testing.T) {
+	for _, tc := range []struct {
+		current, latest string
+		want            bool
+	}{
+		{"v1.2.3", "v1.2.4", true},
+		{"v1.2.3", "v1.3.0", true},
+		{"v1.9.0", "v2.0.0", true},
+		{"1.2.3", "v1.10.0", true},
+		{"v1.2", "v1.2.1", true},
+		{"v1.2.3", "v1.2.3", false},
+		{"v1.2.4", "v1.2.3", false},
+		{"v1.2.3-rc1", "v1.2.3", false},
+		{"dev", "v9.9.9", false},
+		{"v1.2.3", "nightly", false},
+		{"v1.2.3.4", "v1.2.4", false},
+	} {
+		if got := Newer(tc.current, tc.latest); got != tc.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tc.current, tc.latest, got, tc.want)
+		}
+	}
+}
+
+func TestAssetName(t *testing.T) {
+	if got := AssetName("linux", "arm64"); got != "cmon-linux-arm64" {
+		t.Errorf("linux/arm64: %q", got)
+	}
+	if got := AssetName("windows", "amd64"); got != "cmon-windows-amd64.exe" {
+		t.Errorf("windows/amd64: %q", got)
+	}
+}
+
+func TestNilCheckerFindsNothing(t *testing.T) {
+	c := New("", "v1.0.0")
+	if c != nil {
+		t.Fatal("empty repo should disable the checker")
+	}
+	if _, newer, err := c.Check(context.Background()); newer || err != nil {
+		t.Errorf("nil Check = %v, %v", newer, err)
+	}
+}
+
+func TestCheckAndApply(t *testing.T) {
+	binary := "#!/bin/sh\necho new\n"
+	sum := sha256.Sum256([]byte(binary))
+	sums := hex.EncodeToString(sum[:]) + "  " + AssetName(runtime.GOOS, runtime.GOARCH) + "\n"
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/cmon/releases/latest":
+			w.Write([]byte(`{"tag_name":"v1.3.0","html_url":"https://example.com/v1.3.0","assets":[` +
+				`{"name":"` + AssetName(runtime.GOOS, runtime.GOARCH) + `","browser_download_url":"` + srv.URL + `/download"},` +
+				`{"name":"SHA256SUMS","browser_download_url":"` + srv.URL + `/sums"}]}`))
+		case "/download":
+			w.Write([]byte(binary))
+		case "/sums":
+			w.Write([]byte(sums))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := New("owner/cmon", "v1.2.0")
+	c.APIBase = srv.URL
+	rel, newer, err := c.Check(context.Background())
+	if err != nil || !newer || rel.Tag != "v1.3.0" || rel.URL != "https://example.com/v1.3.0" {
+		t.Fatalf("Check = %+v, %v, %v", rel, newer, err)
+	}
+
+	exe := filepath.Join(t.TempDir(), "cmon")
+	if err := os.WriteFile(exe, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Apply(context.Background(), rel, exe); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if got, _ := os.ReadFile(exe); string(got) != binary {
+		t.Errorf("binary after Apply = %q", got)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(exe)); len(entries) > 2 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+
+	// A build that does not match SHA256SUMS is not installed.
+	if err := os.WriteFile(exe, []byte("old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	binary = "#!/bin/sh\necho tampered\n"
+	if err := c.Apply(context.Background(), rel, exe); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("tampered build should error; got %v", err)
+	}
+	if got, _ := os.ReadFile(exe); string(got) != "old" {
+		t.Errorf("tampered build installed: %q", got)
+	}
+
+	sums = "0000  other-build\n"
+	if err := c.Apply(context.Background(), rel, exe); err == nil || !strings.Contains(err.Error(), "no checksum") {
+		t.Errorf("unlisted build should error; got %v", err)
+	}
+
+	rel.Assets = rel.Assets[:1]
+	if err := c.Apply(context.Background(), rel, exe); err == nil || !strings.Contains(err.Error(), "no SHA256SUMS") {
+		t.Errorf("missing SHA256SUMS should error; got %v", err)
+	}
+
+	rel.Assets = nil
+	if err := c.Apply(context.Background(), rel, exe); err == nil || !strings.Contains(err.Error(), "no "+AssetName(runtime.GOOS, runtime.GOARCH)) {
+		t.Errorf("missing build should error; got %v", err)
+	}
+}
+
+func TestLatestHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	c := New("owner/cmon", "v1.0.0")
+	c.APIBase = srv.URL
+	if _, _, err := c.Check(context.Background()); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("non-200 should error with the status; got %v", err)
+	}
+}
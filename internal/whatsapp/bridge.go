@@ -36,11 +36,17 @@ func fetchSummaryComplaints(sc *session.Client, storI summaryStorage) ([]summary
 	return complaints, nil
 }
 
-// renderTable calls summary.RenderTable (combined image with belt group headers).
-func renderTable(complaints []summaryComplaint) ([]byte, error) {
+// renderTable calls summary.RenderTable (combined images with belt group
+// headers, one per page).
+func renderTable(complaints []summaryComplaint) ([][]byte, error) {
 	return summary.RenderTable(complaints)
 }
 
+// redactSummary calls summary.Redact.
+func redactSummary(complaints []summaryComplaint) []summaryComplaint {
+	return summary.Redact(complaints)
+}
+
 // renderTablesByBelt calls summary.RenderTablesByBelt (one image per belt).
 func renderTablesByBelt(complaints []summaryComplaint) ([]summaryBeltImage, error) {
 	return summary.RenderTablesByBelt(complaints)
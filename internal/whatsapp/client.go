@@ -11,6 +11,7 @@
 //   - NewClient(): Reads env, opens SQLite device store, connects (prints QR if needed)
 //   - SendComplaintMessage(): Sends + tracks message ID for resolve-by-reply
 //   - SendMessage(): Sends a plain-text message (non-tracked)
+//   - Subscriber(): Event-bus handler for new/resolved complaints and outages
 //   - HandleEvents(): Background goroutine listening for incoming messages
 //   - Disconnect(): Graceful shutdown
 //
@@ -33,11 +34,14 @@ import (
 	"cmon/internal/belt"
 	"cmon/internal/complaintid"
 	"cmon/internal/metrics"
+	"cmon/internal/msgtmpl"
+	"cmon/internal/notify"
+	"cmon/internal/proxy"
+	"cmon/internal/quality"
 
 	_ "modernc.org/sqlite"
 
 	"cmon/internal/session"
-	"cmon/internal/telegram"
 
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/proto/waCommon"
@@ -63,6 +67,45 @@ const requestTimeout = 30 * time.Second
 type Client struct {
 	wm           *whatsmeow.Client
 	recipientJID types.JID
+	// Templates lays out complaint messages (MESSAGE_TEMPLATE); nil uses
+	// the built-in layout.
+	Templates *msgtmpl.Set
+	// RedactPII masks mobile and consumer numbers to their last four
+	// digits in complaint messages and summary images (REDACT_PII).
+	RedactPII bool
+	// Location is the zone for displayed times and "today" (TZ_OVERRIDE).
+	// Nil uses time.Local.
+	Location *time.Location
+	// Resolved is told of each complaint resolved by reply before it
+	// leaves storage; main publishes it on the event bus, which also edits
+	// the Telegram message. Nil skips it.
+	Resolved func(notify.Status) error
+}
+
+// location is Location, or time.Local when unset.
+func (c *Client) location() *time.Location {
+	if c.Location != nil {
+		return c.Location
+	}
+	return time.Local
+}
+
+// complaintText renders complaint's WhatsApp message, masked when
+// RedactPII is set.
+func (c *Client) complaintText(complaint notify.Complaint) (string, error) {
+	if c.RedactPII {
+		complaint = complaint.Redacted()
+	}
+	return c.Templates.Render(msgtmpl.WhatsApp, complaint)
+}
+
+// summaryRows returns complaints as the summary images show them, masked
+// when RedactPII is set.
+func (c *Client) summaryRows(complaints []summaryComplaint) []summaryComplaint {
+	if c.RedactPII {
+		return redactSummary(complaints)
+	}
+	return complaints
 }
 
 // NewClient creates a new WhatsApp client from environment variables.
@@ -114,6 +157,8 @@ func NewClient() *Client {
 	}
 
 	wmClient := whatsmeow.NewClient(deviceStore, waLog.Stdout("Client", "WARN", true))
+	// Websocket and media traffic follow PROXY_URL like everything else.
+	wmClient.SetProxy(proxy.Func)
 
 	c := &Client{
 		wm:           wmClient,
@@ -317,7 +362,7 @@ func (c *Client) sendImage(ctx context.Context, imgBytes []byte, caption string)
 //   - browserCtxHolder: Provides browser context for API calls and summary fetch
 //   - stor:           Storage for complaint data
 //   - resolveEnabled: Whether reply-to-resolve is active
-func (c *Client) HandleEvents(ctx context.Context, sc *session.Client, stor interface{}, tg *telegram.Client, resolveEnabled bool, debugMode bool) {
+func (c *Client) HandleEvents(ctx context.Context, sc *session.Client, stor interface{}, resolveEnabled bool, debugMode bool) {
 	if c == nil {
 		return
 	}
@@ -413,7 +458,7 @@ func (c *Client) HandleEvents(ctx context.Context, sc *session.Client, stor inte
 			}
 
 			log.Printf("📝 WhatsApp resolve request for complaint %s (remark: %s)", complaintNumber, remark)
-			go c.handleResolve(ctx, sc, storRslv, tg, complaintNumber, quotedID, remark, debugMode)
+			go c.handleResolve(ctx, sc, storRslv, complaintNumber, quotedID, remark, debugMode)
 		}
 	})
 
@@ -427,6 +472,33 @@ func (c *Client) HandleEvents(ctx context.Context, sc *session.Client, stor inte
 // in the chat. Exposed for the scheduler in main.go.
 func (c *Client) PostScheduledSummary(ctx context.Context, sc *session.Client, stor interface{}) {
 	c.handleSummaryCommand(ctx, sc, stor)
+	if ctx.Err() != nil {
+		return
+	}
+	if qs, ok := stor.(qualityStorage); ok {
+		c.sendDataQualityReport(qs)
+	}
+}
+
+// sendDataQualityReport mirrors the Telegram data-quality section: today's
+// complaints with flagged intake data. Silent on a clean day.
+func (c *Client) sendDataQualityReport(stor qualityStorage) {
+	now := time.Now().In(c.location())
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	flagged, err := stor.GetFlaggedComplaintsSince(dayStart)
+	if err != nil {
+		log.Printf("⚠️  Failed to load data-quality report: %v", err)
+		return
+	}
+	lines := quality.ReportLines(flagged)
+	if len(lines) == 0 {
+		return
+	}
+	lines[0] = "*" + lines[0] + "*"
+	if err := c.SendMessage(strings.Join(lines, "\n")); err != nil {
+		log.Printf("⚠️  Failed to send data-quality report: %v", err)
+	}
 }
 
 // handleSummaryCommand fetches all pending complaints and sends a summary image.
@@ -455,23 +527,29 @@ func (c *Client) handleSummaryCommand(ctx context.Context, sc *session.Client, s
 	if ctx.Err() != nil {
 		return
 	}
+	complaints = c.summaryRows(complaints)
 
-	// Render combined table as PNG
-	imgBytes, err := renderSummaryImage(complaints)
+	// Render combined table as PNGs, one per page
+	pages, err := renderSummaryImage(complaints)
 	if err != nil {
 		log.Printf("⚠️  WhatsApp summary render failed: %v", err)
 		c.SendMessage(fmt.Sprintf("❌ Failed to render summary image: %v", err))
 		return
 	}
-	if ctx.Err() != nil {
-		return
-	}
 
-	caption := fmt.Sprintf("📋 %d Pending Complaints", len(complaints))
-	if err := c.sendImage(ctx, imgBytes, caption); err != nil {
-		log.Printf("⚠️  WhatsApp summary image send failed: %v", err)
-		c.SendMessage(buildTextSummary(complaints))
-		return
+	for i, imgBytes := range pages {
+		if ctx.Err() != nil {
+			return
+		}
+		caption := fmt.Sprintf("📋 %d Pending Complaints", len(complaints))
+		if len(pages) > 1 {
+			caption += fmt.Sprintf(" (page %d/%d)", i+1, len(pages))
+		}
+		if err := c.sendImage(ctx, imgBytes, caption); err != nil {
+			log.Printf("⚠️  WhatsApp summary image send failed: %v", err)
+			c.SendMessage(buildTextSummary(complaints))
+			return
+		}
 	}
 }
 
@@ -499,6 +577,7 @@ func (c *Client) handleSummaryBeltCommand(ctx context.Context, sc *session.Clien
 	if ctx.Err() != nil {
 		return
 	}
+	complaints = c.summaryRows(complaints)
 
 	beltImages, err := renderSummaryImages(complaints)
 	if err != nil {
@@ -513,6 +592,9 @@ func (c *Client) handleSummaryBeltCommand(ctx context.Context, sc *session.Clien
 			return
 		}
 		caption := fmt.Sprintf("📋 %s Belt — %d Pending Complaints", bi.Label, bi.Count)
+		if bi.Pages > 1 {
+			caption += fmt.Sprintf(" (page %d/%d)", bi.Page, bi.Pages)
+		}
 		if err := c.sendImage(ctx, bi.PNG, caption); err != nil {
 			log.Printf("⚠️  WhatsApp belt summary image send failed for %s: %v", bi.Label, err)
 			sendErrs++
@@ -528,7 +610,7 @@ func (c *Client) handleSummaryBeltCommand(ctx context.Context, sc *session.Clien
 // handleResolve resolves a complaint via the DGVCL API and updates tracking.
 // ctx is the parent HandleEvents context; checked at major boundaries so a
 // shutdown skips further work after the API call returns.
-func (c *Client) handleResolve(ctx context.Context, sc *session.Client, stor resolveStorage, tg *telegram.Client, complaintNumber, waMessageID, remark string, debugMode bool) {
+func (c *Client) handleResolve(ctx context.Context, sc *session.Client, stor resolveStorage, complaintNumber, waMessageID, remark string, debugMode bool) {
 	if ctx.Err() != nil {
 		return
 	}
@@ -552,31 +634,24 @@ func (c *Client) handleResolve(ctx context.Context, sc *session.Client, stor res
 		return
 	}
 
-	messageID := stor.GetMessageID(complaintNumber)
-	telegramEditFailed := false
-	if messageID != "" && tg != nil {
+	var publishErr error
+	if c.Resolved != nil {
 		consumerName := stor.GetConsumerName(complaintNumber)
 		if consumerName == "" {
 			consumerName = "Unknown"
 		}
-
-		resolvedMessage := fmt.Sprintf(
-			"✅ <b>RESOLVE</b>\n\n"+
-				"Complaint #%s\n"+
-				"👤 %s\n"+
-				"🕐 %s",
-			complaintNumber,
-			consumerName,
-			time.Now().Format("02 Jan 2006, 03:04 PM"),
-		)
-
-		if err := tg.EditMessageText(tg.ChatIDForBelt(stor.GetBelt(complaintNumber)), messageID, resolvedMessage); err != nil {
-			log.Printf("⚠️  WhatsApp resolved %s on website but failed to edit Telegram message: %v", complaintNumber, err)
-			telegramEditFailed = true
+		publishErr = c.Resolved(notify.Status{
+			ComplaintID:  complaintNumber,
+			ConsumerName: consumerName,
+			Belt:         stor.GetBelt(complaintNumber),
+			Category:     stor.GetCategory(complaintNumber),
+			Time:         time.Now(),
+			Remark:       remark,
+			Channel:      "whatsapp",
+		})
+		if publishErr != nil {
+			log.Printf("⚠️  WhatsApp resolved %s on website but failed to update the other channels: %v", complaintNumber, publishErr)
 		}
-	} else if tg != nil {
-		log.Printf("⚠️  WhatsApp resolved %s on website but no Telegram message ID was stored", complaintNumber)
-		telegramEditFailed = true
 	}
 
 	// Remove from storage after cross-channel notifications are updated so the
@@ -586,8 +661,8 @@ func (c *Client) handleResolve(ctx context.Context, sc *session.Client, stor res
 		log.Printf("⚠️  Resolved on website but failed to remove %s from storage: %v", complaintNumber, err)
 	}
 
-	if telegramEditFailed {
-		c.SendMessage(fmt.Sprintf("⚠️ Complaint #%s was resolved on the website, but Telegram could not be updated.", complaintNumber))
+	if publishErr != nil {
+		c.SendMessage(fmt.Sprintf("⚠️ Complaint #%s was resolved on the website, but the other channels could not all be updated.", complaintNumber))
 	}
 	c.SendMessage(fmt.Sprintf("✅ RESOLVED\n\nComplaint #%s\n💬 %s", complaintNumber, remark))
 	log.Printf("✅ WhatsApp: resolved complaint %s", complaintNumber)
@@ -647,10 +722,17 @@ type resolveStorage interface {
 	GetConsumerName(complaintNumber string) string
 	GetMessageID(complaintNumber string) string
 	GetBelt(complaintNumber string) string
+	GetCategory(complaintNumber string) string
 	Exists(complaintNumber string) bool
 	Remove(complaintNumber string) error
 }
 
+// qualityStorage is the subset of storage.Storage needed for the daily
+// data-quality section.
+type qualityStorage interface {
+	GetFlaggedComplaintsSince(since time.Time) ([]quality.Flagged, error)
+}
+
 type storageSetter interface {
 	SetWAMessageID(complaintID, waMessageID string) error
 }
@@ -669,7 +751,7 @@ func defaultFetchPendingSummary(sc *session.Client, stor summaryStorage) ([]summ
 	return fetchSummaryComplaints(sc, stor)
 }
 
-func defaultRenderSummaryImage(complaints []summaryComplaint) ([]byte, error) {
+func defaultRenderSummaryImage(complaints []summaryComplaint) ([][]byte, error) {
 	return renderTable(complaints)
 }
 
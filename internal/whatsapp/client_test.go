@@ -0,0 +1,35 @@
+package whatsapp
+
+import (
+	"strings"
+	"testing"
+
+	"cmon/internal/notify"
+)
+
+func TestRedactPII(t *testing.T) {
+	complaint := notify.Complaint{Number: "CMP-1", ComplainantName: "Asha Patel", MobileNo: "9876543210", ConsumerNo: "112233445"}
+	rows := []summaryComplaint{{ComplainNo: "CMP-1", Name: "Asha Patel", MobileNo: "9876543210", ConsumerNo: "112233445"}}
+
+	for _, redact := range []bool{false, true} {
+		c := &Client{RedactPII: redact}
+		text, err := c.complaintText(complaint)
+		if err != nil {
+			t.Fatalf("complaintText: %v", err)
+		}
+		if got := strings.Contains(text, "9876543210"); got == redact {
+			t.Errorf("RedactPII=%v: full mobile number shown = %v in %q", redact, got, text)
+		}
+		if redact && !strings.Contains(text, "3210") {
+			t.Errorf("redacted message lost the last four digits: %q", text)
+		}
+
+		got := c.summaryRows(rows)
+		if redact != (got[0].MobileNo != "9876543210" || got[0].ConsumerNo != "112233445") {
+			t.Errorf("RedactPII=%v: summary row %+v", redact, got[0])
+		}
+	}
+	if rows[0].MobileNo != "9876543210" {
+		t.Errorf("summaryRows modified its input: %+v", rows[0])
+	}
+}
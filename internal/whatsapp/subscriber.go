@@ -0,0 +1,103 @@
+package whatsapp
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"cmon/internal/eventbus"
+	"cmon/internal/i18n"
+	"cmon/internal/notify"
+)
+
+// complaintSendGap spaces complaint messages. whatsmeow prefetches
+// encryption sessions from its SQLite store on every send, and sends fired
+// back to back cause SQLITE_BUSY contention.
+const complaintSendGap = time.Second
+
+// Subscriber returns the event-bus handler that mirrors complaints (once
+// translated, when translation is on), resolutions and outage alerts to
+// the WhatsApp recipient. stor records complaint message IDs for
+// resolve-by-reply.
+func (c *Client) Subscriber(stor interface{}) eventbus.Handler {
+	var mu sync.Mutex
+	var lastComplaint time.Time
+
+	// send must be called with mu held. A complaint that already has a
+	// WhatsApp message (a recovered announcement, say) is not sent again,
+	// and one whose translation is still being made waits for
+	// ComplaintTranslated: WhatsApp messages are not edited afterwards.
+	send := func(complaint notify.Complaint) error {
+		if complaint.TranslationPending {
+			return nil
+		}
+		if s, ok := stor.(interface{ GetWAMessageID(string) string }); ok && s.GetWAMessageID(complaint.Number) != "" {
+			return nil
+		}
+		if wait := complaintSendGap - time.Since(lastComplaint); wait > 0 {
+			time.Sleep(wait)
+		}
+		lastComplaint = time.Now()
+		text, err := c.complaintText(complaint)
+		if err != nil {
+			return err
+		}
+		return c.SendComplaintMessage(text, complaint.Number, stor)
+	}
+
+	return func(e eventbus.Event) error {
+		switch e := e.(type) {
+		case eventbus.ComplaintNew:
+			mu.Lock()
+			defer mu.Unlock()
+			return send(e.Complaint)
+
+		case eventbus.ComplaintTranslated:
+			mu.Lock()
+			defer mu.Unlock()
+			return send(e.Complaint)
+
+		case eventbus.ComplaintsNew:
+			mu.Lock()
+			defer mu.Unlock()
+			var errs []error
+			for _, complaint := range e.Complaints {
+				if err := send(complaint); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			return errors.Join(errs...)
+
+		case eventbus.ComplaintResolved:
+			if e.Status.Channel == "whatsapp" {
+				return nil // handleResolve has replied already
+			}
+			e.Status.Time = e.Status.Time.In(c.location())
+			return c.SendMessage(ResolvedText(e.Status))
+
+		case eventbus.AlertRaised:
+			// Only outages go to WhatsApp; operational alerts stay with
+			// the on-call channels.
+			if e.Alert.Kind == notify.AlertOutage {
+				return c.SendMessage(e.Alert.Title + "\n" + e.Alert.Message)
+			}
+		}
+		return nil
+	}
+}
+
+// ResolvedText is the WhatsApp notice for a resolved complaint.
+func ResolvedText(s notify.Status) string {
+	title := "✅ " + i18n.T("resolved.title")
+	if s.Local {
+		title = "✅ " + i18n.T("resolved.title.local")
+	}
+	return fmt.Sprintf(
+		"%s\n\n%s\n👤 %s\n🕐 %s",
+		title,
+		i18n.T("resolved.complaint", s.ComplaintID),
+		s.ConsumerName,
+		s.Time.Format("02 Jan 2006, 03:04 PM"),
+	)
+}
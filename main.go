@@ -17,6 +17,9 @@
 //  5. Handle errors with retry logic and session reset
 //  6. Graceful shutdown on SIGTERM/SIGINT
 //
+// "cmon fetch-once --diff" instead reads the dashboard once and prints the
+// complaints a fetch would add and resolve, changing nothing.
+//
 // Error recovery strategy:
 //   - Session expired → Re-login
 //   - Re-login failed → Reset session (new cookie jar) and re-login
@@ -26,31 +29,65 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 	_ "time/tzdata"
 
 	"cmon/internal/api"
 	"cmon/internal/auth"
+	"cmon/internal/backup"
 	"cmon/internal/belt"
+	"cmon/internal/buildinfo"
+	"cmon/internal/category"
 	"cmon/internal/complaint"
 	"cmon/internal/config"
+	"cmon/internal/crash"
 	"cmon/internal/errors"
+	"cmon/internal/eventbus"
+	"cmon/internal/feeder"
+	"cmon/internal/filter"
+	"cmon/internal/flags"
+	"cmon/internal/geocode"
 	"cmon/internal/health"
+	"cmon/internal/i18n"
+	"cmon/internal/leader"
+	"cmon/internal/heartbeat"
+	"cmon/internal/hooks"
 	"cmon/internal/logging"
 	"cmon/internal/metrics"
+	"cmon/internal/mqtt"
+	"cmon/internal/msgtmpl"
+	"cmon/internal/notify"
+	"cmon/internal/outage"
+	"cmon/internal/proxy"
+	"cmon/internal/quality"
+	"cmon/internal/retention"
+	"cmon/internal/rpc"
 	"cmon/internal/session"
+	"cmon/internal/shard"
+	"cmon/internal/sla"
 	"cmon/internal/storage"
+	"cmon/internal/summary"
+	"cmon/internal/supervisor"
 	"cmon/internal/telegram"
 	"cmon/internal/translate"
+	"cmon/internal/update"
 	"cmon/internal/whatsapp"
+
+	"google.golang.org/grpc"
 )
 
 // fetchMu prevents concurrent scrape cycles (ticker vs dashboard refresh).
@@ -66,64 +103,336 @@ type daemonDeps struct {
 	stor          *storage.Storage
 	tg            *telegram.Client
 	wa            *whatsapp.Client
-	translator    *translate.Translator
+	translations  *complaint.TranslationQueue
 	healthMonitor *health.Monitor
+	outage        *outage.Detector
+	feeders       *feeder.Map
+	categories    *category.Classifier
+	filter        *filter.Rules // ignore rules; nil announces everything
+	geocoder      *geocode.Geocoder
+	bus           *eventbus.Bus // complaint/alert events; channels and WhatsApp subscribe
+	flags         *flags.Flags  // runtime toggles from the admin chat
+
+	// tgNotifier is the Telegram channel when enabled; it re-sends
+	// dead-lettered complaint messages after each successful fetch.
+	tgNotifier *telegram.Notifier
+
+	// heartbeat pings the dead-man's-switch after each successful fetch;
+	// nil when HEARTBEAT_URL is unset.
+	heartbeat *heartbeat.Pinger
+
+	// alerts suppresses repeats of a critical alert while the failure
+	// lasts and announces its recovery.
+	alerts *notify.AlertTracker
+
+	// absences counts the complete fetches each stored complaint has been
+	// missing from; see confirmResolved.
+	absences *complaint.Absences
+
+	// complaintStatus is the dashboard view (cStatus) each complaint was
+	// last found in when several are fetched; guarded by fetchMu.
+	complaintStatus map[string]string
 }
 
 func main() {
-	// Force Indian Standard Time (IST) for all time operations
-	ist, err := time.LoadLocation("Asia/Kolkata")
-	if err != nil {
-		log.Fatal("❌ Failed to load IST timezone:", err)
+	if len(os.Args) > 1 && os.Args[1] == "fetch-once" {
+		os.Exit(runFetchOnce(os.Args[2:]))
 	}
-	time.Local = ist
-
-	log.Println("🚀 Starting CMON...")
+	log.Printf("🚀 Starting CMON %s...", buildinfo.Get())
 
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		log.Fatal("❌ Configuration error:", err)
 	}
+	// Times are shown and scheduled in TZ_OVERRIDE's zone; it is passed
+	// along explicitly rather than replacing time.Local.
+	loc := cfg.Location()
+	log.Printf("🕐 Timezone: %s", loc)
 
 	// Install slog as the application-wide structured logger and reroute the
 	// stdlib log package through it. Done as soon as config is parsed so every
 	// subsequent log line is in the configured format.
-	logging.Setup(cfg.LogFormat)
+	// LOG_FILE adds a rotated file next to the console output.
+	if cfg.LogFile != "" {
+		logFile, err := logging.OpenRotating(cfg.LogFile, int64(cfg.LogMaxSizeMB)<<20, cfg.LogMaxBackups, time.Duration(cfg.LogMaxAgeDays)*24*time.Hour)
+		if err != nil {
+			log.Fatal("❌ Failed to open log file:", err)
+		}
+		defer logFile.Close()
+		logging.Setup(cfg.LogFormat, logFile)
+	} else {
+		logging.Setup(cfg.LogFormat)
+	}
 
-	// Point the DGVCL resolve client at the configured endpoint. Default
-	// matches production; override via DGVCL_RESOLVE_URL for staging.
+	// Route outbound traffic through PROXY_URL before any client is built.
+	if err := proxy.Setup(cfg.ProxyURL, cfg.ProxyBypass); err != nil {
+		log.Fatal("❌ Invalid proxy settings:", err)
+	}
+
+	// Point the DGVCL API clients at the configured endpoints. Defaults
+	// match production; override via DGVCL_RESOLVE_URL / DGVCL_RECORD_URL
+	// for staging.
 	api.SetResolveEndpoint(cfg.ResolveURL)
+	api.SetRecordEndpoint(cfg.RecordURL)
+	api.SetRecordCacheTTL(cfg.RecordCacheTTL)
+
+	// With LEADER_ELECTION a standby replica waits here until it holds the
+	// lease — before storage is loaded, so it takes over with the state the
+	// leader left behind. Meanwhile a probe server answers /livez and
+	// reports /readyz as standby; it hands the port over to the dashboard
+	// server in Step 6.
+	var lease *leader.Lease
+	var standbyServer *http.Server
+	if cfg.LeaderElection {
+		standbyServer = health.StartStandbyServer(cfg.HealthCheckPort)
+		lease, err = leader.Open(cfg.LeaderLeaseFile, cfg.InstanceID, cfg.LeaderLeaseTTL)
+		if err != nil {
+			log.Fatalf("❌ Leader election: %v", err)
+		}
+		log.Printf("🗳️  Leader election enabled as %s (lease %s)", cfg.InstanceID, cfg.LeaderLeaseTTL)
+		standbyCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		err = lease.Acquire(standbyCtx)
+		stop()
+		if err != nil {
+			log.Println("🛑 Stopped while standing by")
+			shutdownStandbyServer(standbyServer)
+			lease.Close()
+			return
+		}
+		log.Printf("👑 %s is the leader", cfg.InstanceID)
+	}
 
 	// Initialize storage. Closed at the very end of the graceful shutdown
 	// sequence — never via defer — so it cannot run while a goroutine is
 	// still mid-write. See the explicit shutdown block at the bottom of main.
-	stor, err := storage.New()
+	encKey, err := cfg.EncryptionKey()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	stor, err := storage.NewEncrypted(encKey)
 	if err != nil {
 		log.Fatalf("❌ Failed to initialize storage: %v", err)
 	}
+	if encKey != nil {
+		log.Println("🔐 Consumer details are encrypted at rest")
+	}
 
 	// Live gauge: cmon_open_complaints{belt=...}. Read from storage at scrape
 	// time so the value can never drift from the source of truth.
 	metrics.RegisterOpenComplaintsByBelt(stor.GetPendingCountsByBelt)
 
+	// Feeder map (optional): tags complaints with their feeder, groups
+	// outage clusters by it and adds cmon_open_complaints_by_feeder.
+	var feeders *feeder.Map
+	if cfg.FeederMapFile != "" {
+		if feeders, err = feeder.Load(cfg.FeederMapFile); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		metrics.RegisterOpenComplaintsByFeeder(func() map[string]int {
+			return stor.GetPendingCountsByFeeder(feeders.Lookup)
+		})
+		log.Printf("✓ Feeder map loaded: %d localities", feeders.Len())
+	}
+
+	// Monthly SLA compliance reports (/slareport, /reports/sla), reading
+	// months that retention has cleared from its archives.
+	slaOverrides, _ := cfg.SLATargetOverrides() // syntax checked by Validate
+	slaTargets, err := sla.NewTargets(slaOverrides)
+	if err != nil {
+		log.Fatalf("❌ SLA_TARGETS: %v", err)
+	}
+	slaReports := &sla.Reporter{Store: stor, ArchiveDir: cfg.RetentionExportDir, Targets: slaTargets, Location: loc}
+
+	// Runtime toggles (feature flags, log level) persisted by admin commands.
+	runtimeFlags := flags.New(stor)
+
+	// Complaint message layout shared by Telegram and WhatsApp, with the
+	// translation interleaved per BILINGUAL_FIELDS; a broken template fails
+	// here rather than on the first complaint.
+	templates, err := msgtmpl.Load(cfg.MessageTemplate, cfg.BilingualFields)
+	if err != nil {
+		log.Fatal("❌ Failed to load message template:", err)
+	}
+	if cfg.MessageTemplate != "" {
+		log.Printf("✓ Complaint messages use template %s", cfg.MessageTemplate)
+	}
+
 	// Step 3: Initialize Telegram client (optional)
 	tg := telegram.NewClient()
 	if tg != nil && len(cfg.TelegramBeltRoutes) > 0 {
 		tg.BeltRoutes = cfg.TelegramBeltRoutes
 		log.Printf("✓ Telegram per-belt routing enabled for %d belt(s)", len(cfg.TelegramBeltRoutes))
 	}
+	if tg != nil && len(cfg.TelegramCategoryRoutes) > 0 {
+		tg.CategoryRoutes = cfg.TelegramCategoryRoutes
+		log.Printf("✓ Telegram per-category routing enabled for %d categor(ies)", len(cfg.TelegramCategoryRoutes))
+	}
+	if tg != nil {
+		tg.SummaryMap = cfg.SummaryMapEnabled
+		tg.AckButton = cfg.UnseenReminderDelay > 0 || cfg.TelegramAckClaims
+		tg.AckClaims = cfg.TelegramAckClaims
+		tg.Admins = cfg.TelegramAdmins
+		tg.DigestThreshold = cfg.TelegramDigestThreshold
+		if start, end, ok := cfg.QuietHoursRange(); ok {
+			tg.QuietStart, tg.QuietEnd = start, end
+			tg.UrgentKeywords = cfg.UrgentKeywords
+			log.Printf("🌙 Quiet hours %s–%s: routine complaints are held for a morning digest", start, end)
+		}
+		tg.QuickRemarks = cfg.ResolutionRemarks
+		tg.ConversationTimeout = cfg.ConversationTimeout
+		tg.UndoWindow = cfg.ResolveUndoWindow
+		tg.AttachmentsDir = filepath.Join(cfg.DataDir, "attachments")
+		tg.DetailsButton = cfg.TelegramDetailsButton
+		tg.RedactPII = cfg.RedactPII
+		tg.AdminChatID = cfg.TelegramAdminChatID
+		tg.Flags = runtimeFlags
+		tg.Templates = templates
+		tg.SLAReports = slaReports
+		tg.Location = loc
+		if cfg.TelegramLifecycleNotices {
+			if err := tg.SendStartupNotice(len(stor.GetAllSeenComplaints())); err != nil {
+				log.Printf("⚠️  %v", err)
+			}
+		}
+		crash.SetReporter(func(component string, value any, stack []byte) {
+			if err := tg.SendCrashReport(component, value, stack); err != nil {
+				log.Printf("⚠️  %v", err)
+			}
+		})
+	}
+	i18n.SetLanguage(cfg.BotLanguage)
+	summary.SetAgeThresholds(cfg.AgingAfter, cfg.OverdueAfter)
+	theme, err := summary.LoadTheme(cfg.SummaryTheme)
+	if err != nil {
+		log.Fatal("❌ Failed to load summary theme:", err)
+	}
+	if cfg.SummaryTitle != "" {
+		theme.TitleText = cfg.SummaryTitle
+	}
+	if cfg.SummaryBranding != "" {
+		theme.Branding = cfg.SummaryBranding
+	}
+	summary.SetTheme(theme)
+	summary.SetMaxRowsPerImage(cfg.SummaryMaxRows)
+	summary.SetGroupBy(cfg.SummaryGroupBy)
+	summary.SetFeeders(feeders)
+	summary.SetLocation(loc)
+	health.SetLocation(loc)
 
 	// Step 3a: Initialize WhatsApp client (optional)
 	wa := whatsapp.NewClient()
+	if wa != nil {
+		wa.Templates = templates
+		wa.Location = loc
+		wa.RedactPII = cfg.RedactPII
+	}
+
+	// Step 3a2: Event bus. Every enabled notification channel subscribes
+	// through one fan-out, then WhatsApp and the SLA tracker. Webhooks get
+	// their own queue: their retries must not hold up the fetch cycle.
+	notifier := buildNotifier(cfg, tg, stor)
+	bus := eventbus.New()
+	bus.Subscribe("notify", eventbus.Notify(notifier))
+	if wa != nil {
+		bus.Subscribe("whatsapp", wa.Subscriber(stor))
+	}
+	// Resolutions made from a chat are published like the ones the fetch
+	// detects, before the chat removes the complaint from storage.
+	publishResolved := func(s notify.Status) error {
+		return bus.Publish(eventbus.ComplaintResolved{Status: s})
+	}
+	if tg != nil {
+		tg.Resolved = publishResolved
+		// An undone resolution is announced again, as pending.
+		tg.Reopened = func(complaint notify.Complaint) error {
+			return bus.Publish(eventbus.ComplaintNew{Complaint: complaint})
+		}
+	}
+	if wa != nil {
+		wa.Resolved = publishResolved
+	}
+	bus.Subscribe("sla", (&sla.Tracker{Target: cfg.OverdueAfter, FirstSeen: stor.GetFirstSeenAt, Targets: slaTargets, Category: stor.GetCategory}).Handle)
+	var stopQueues []func()
+	if webhook := buildWebhook(cfg); webhook != nil {
+		stopQueues = append(stopQueues, bus.SubscribeAsync("webhook", webhookQueueSize, eventbus.Notify(webhook)))
+		log.Println("✓ Webhook events are delivered in the background")
+	}
+
+	// Step 3a3: Site-specific hooks, compiled in or loaded from plugins.
+	// Each gets its own queue like the webhook.
+	hookList := hooks.Registered()
+	for _, path := range cfg.HookPlugins {
+		r, err := hooks.LoadPlugin(path)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		hookList = append(hookList, r)
+	}
+	for _, r := range hookList {
+		stopQueues = append(stopQueues, bus.SubscribeAsync("hook "+r.Name, webhookQueueSize, hooks.Handler(r)))
+		log.Printf("✓ Hook %s enabled", r.Name)
+	}
+
+	// Step 3a4: MQTT for SCADA/IoT dashboards (optional). The publisher
+	// reads the open counts as each event arrives and queues the messages
+	// itself, so it subscribes synchronously.
+	var mqttPub *mqtt.Publisher
+	if cfg.MQTTBrokerURL != "" {
+		mqttPub = mqtt.New(mqtt.Config{
+			BrokerURL:   cfg.MQTTBrokerURL,
+			ClientID:    cfg.MQTTClientID,
+			Username:    cfg.MQTTUsername,
+			Password:    cfg.MQTTPassword,
+			TopicPrefix: cfg.MQTTTopicPrefix,
+			QoS:         byte(cfg.MQTTQoS),
+			Redact:      cfg.RedactPII,
+		}, stor.GetPendingCountsByBelt)
+		bus.Subscribe("mqtt", mqttPub.Handle)
+		log.Printf("✓ MQTT publishing to %s under %s/", cfg.MQTTBrokerURL, cfg.MQTTTopicPrefix)
+	}
+	if sh := (shard.Shard{Index: cfg.ShardIndex, Count: cfg.ShardCount}); sh.Enabled() {
+		log.Printf("🧩 Sharding enabled: this instance owns shard %s", sh)
+	}
 
 	// Step 3b: Initialize Gemini Translator (optional)
 	translator, err := translate.NewTranslator(context.Background(), cfg.GeminiAPIKey, cfg)
 	if err != nil {
 		log.Printf("⚠️  Translator init failed (translation disabled): %v", err)
 	}
+	if tg != nil && cfg.VoiceTranscribe && translator != nil {
+		tg.Transcriber = translator
+		log.Println("✓ Voice-note remarks are transcribed with Gemini")
+	}
+	// Complaints go out in English; their Gujarati translations follow from
+	// this queue, paced to Gemini's quota, and edit the messages (channels
+	// that cannot edit send them only then). The announcement is journaled
+	// until the translation is delivered.
+	translations := complaint.NewTranslationQueue(translator, cfg.GeminiTranslateRPM, func(c notify.Complaint) {
+		if err := bus.Publish(eventbus.ComplaintTranslated{Complaint: c}); err != nil {
+			log.Printf("⚠️  Failed to add the translation to complaint %s: %v", c.Number, err)
+		}
+		if err := stor.JournalDoneFor(storage.JournalAnnounce, c.Number); err != nil {
+			log.Printf("⚠️  Failed to clear the announcement journal for complaint %s: %v", c.Number, err)
+		}
+	})
+	categories := &category.Classifier{}
+	if cfg.CategoryGeminiFallback && translator != nil {
+		categories.Fallback = translator.Classify
+		log.Println("✓ Complaints no keyword rule matches are categorized with Gemini")
+	}
+	ignoreRules, err := filter.New(cfg.IgnoreConsumers, cfg.IgnoreDescription, cfg.NotifyAreas)
+	if err != nil {
+		log.Fatalf("❌ Invalid ignore rules: %v", err)
+	}
+	if ignoreRules != nil {
+		log.Println("✓ Complaint ignore rules are active")
+	}
 
 	// Step 4: Initialize health monitor
 	healthMonitor := health.NewMonitor()
+	if tg != nil {
+		tg.Health = healthMonitor
+	}
 
 	// Step 5: Create authenticated session client (replaces browser context)
 	sc, err := session.New(cfg.APIRateLimitRPS, cfg.APIRateLimitBurst, cfg.APIMaxRetries429)
@@ -131,6 +440,40 @@ func main() {
 		log.Fatal("❌ Failed to create session client:", err)
 	}
 	log.Println("✓ Session client created")
+	if err := sc.ConfigureTLS(session.TLSOptions{
+		CAFile:   cfg.TLSCAFile,
+		Pins:     cfg.TLSPins,
+		Insecure: cfg.InsecureTLS,
+	}); err != nil {
+		log.Fatal("❌ Invalid portal TLS settings:", err)
+	}
+	if cfg.PortalTrace {
+		if err := sc.EnableTrace(cfg.PortalTraceHARDir); err != nil {
+			log.Fatal("❌ Failed to enable portal tracing:", err)
+		}
+	}
+	if tg != nil && cfg.CaptchaHumanAfter > 0 {
+		sc.SetCaptchaSolver(&session.Escalate{
+			Primary:  session.ArithmeticSolver{},
+			Fallback: tg.CaptchaSolver(cfg.TelegramAdminChatID, cfg.CaptchaHumanTimeout),
+			After:    cfg.CaptchaHumanAfter,
+		})
+		log.Printf("✓ Captcha fallback: Telegram admin chat after %d failed attempt(s)", cfg.CaptchaHumanAfter)
+	}
+
+	// Step 5a: Component checks behind /health and /readyz. Only storage
+	// failing makes the service unhealthy; the others degrade it.
+	healthMonitor.MaxFetchAge = cfg.HealthMaxFetchAge
+	healthMonitor.AddCheck("storage", true, stor.Ping)
+	healthMonitor.AddCheck("session", false, func(context.Context) error {
+		if !sc.Authenticated() {
+			return fmt.Errorf("not logged in to the DGVCL portal")
+		}
+		return nil
+	})
+	if tg != nil {
+		healthMonitor.AddCheck("telegram", false, func(context.Context) error { return tg.Ping() })
+	}
 
 	// Bundle the long-lived state so helpers don't take 13 positional args.
 	deps := &daemonDeps{
@@ -139,8 +482,51 @@ func main() {
 		stor:          stor,
 		tg:            tg,
 		wa:            wa,
-		translator:    translator,
+		translations:  translations,
 		healthMonitor: healthMonitor,
+		outage:        outage.NewDetector(cfg.OutageClusterThreshold, cfg.OutageClusterWindow),
+		feeders:       feeders,
+		categories:    categories,
+		filter:        ignoreRules,
+		geocoder: geocode.New(geocode.Options{
+			Provider:  cfg.GeocodeProvider,
+			Endpoint:  cfg.GeocodeURL,
+			UserAgent: cfg.GeocodeUserAgent,
+			Country:   cfg.GeocodeCountry,
+			Cache:     stor,
+		}),
+		bus:        bus,
+		flags:      runtimeFlags,
+		tgNotifier: telegramNotifier(notifier),
+		heartbeat:  heartbeat.New(cfg.HeartbeatURL),
+		alerts:     notify.NewAlertTracker(cfg.AlertReminderInterval),
+		absences:   complaint.NewAbsences(),
+	}
+	if deps.heartbeat != nil {
+		log.Println("✓ Heartbeat pings enabled after each successful fetch")
+	}
+	bus.Subscribe("alerts", func(e eventbus.Event) error {
+		if f, ok := e.(eventbus.FetchFailed); ok && !f.Silent {
+			sendCriticalAlert(deps,
+				"Fetch/Login Failure",
+				fmt.Sprintf("Unable to fetch complaints after %d attempts. Last error: %v", f.Attempts, f.Err),
+				f.Attempts,
+			)
+		}
+		return nil
+	})
+
+	// Telegram sends cut short by a crash or kill are re-queued before
+	// anything new is sent.
+	deps.tgNotifier.RecoverInterruptedSends()
+	// Then complaints saved but never announced; channels that already
+	// have one skip it.
+	recovered := translations
+	if !runtimeFlags.Enabled(flags.Translation) {
+		recovered = nil
+	}
+	if n := complaint.RecoverAnnouncements(stor, bus, recovered); n > 0 {
+		log.Printf("📮 Announced %d complaints interrupted by the last shutdown", n)
 	}
 
 	// Build the refresh function that the dashboard can call to trigger a scrape.
@@ -159,38 +545,20 @@ func main() {
 		if strings.HasPrefix(lowerAPIID, "local") || strings.HasPrefix(lowerAPIID, "l-") || strings.HasPrefix(lowerAPIID, "vld") {
 			log.Printf("✅ Resolving local complaint %s...", apiID)
 
-			messageID := stor.GetMessageID(apiID)
 			consumerName := stor.GetConsumerName(apiID)
 			if consumerName == "" {
 				consumerName = "Unknown"
 			}
 
-			resolvedMessage := fmt.Sprintf(
-				"✅ <b>RESOLVED (LOCAL)</b>\n\n"+
-					"Complaint #%s\n"+
-					"👤 %s\n"+
-					"🕐 %s",
-				apiID,
-				consumerName,
-				time.Now().Format("02 Jan 2006, 03:04 PM"),
-			)
-
-			if tg != nil && messageID != "" {
-				if err := tg.EditMessageText(tg.ChatIDForBelt(stor.GetBelt(apiID)), messageID, resolvedMessage); err != nil {
-					log.Printf("⚠️  Failed to edit Telegram message for local complaint %s: %v", apiID, err)
-				}
-			}
-
-			if wa != nil {
-				waResolvedMsg := fmt.Sprintf(
-					"✅ RESOLVED (LOCAL)\n\nComplaint #%s\n👤 %s\n🕐 %s",
-					apiID,
-					consumerName,
-					time.Now().Format("02 Jan 2006, 03:04 PM"),
-				)
-				if waErr := wa.SendMessage(waResolvedMsg); waErr != nil {
-					log.Printf("⚠️  Failed to send WhatsApp resolved notice: %v", waErr)
-				}
+			if err := bus.Publish(eventbus.ComplaintResolved{Status: notify.Status{
+				ComplaintID:  apiID,
+				ConsumerName: consumerName,
+				Belt:         stor.GetBelt(apiID),
+				Category:     stor.GetCategory(apiID),
+				Local:        true,
+				Time:         time.Now(),
+			}}); err != nil {
+				log.Printf("⚠️  Failed to update notifications for local complaint %s: %v", apiID, err)
 			}
 
 			if err := stor.Remove(apiID); err != nil {
@@ -204,11 +572,12 @@ func main() {
 
 	registerLocalFn := func(complainantName, mobileNo, consumerNo, village, beltName, address, area, description string) (string, error) {
 		// Generate custom VLDYYYYMMDDSR ID
-		complaintID, err := stor.GenerateLocalComplaintID()
+		now := time.Now().In(loc)
+		complaintID, err := stor.GenerateLocalComplaintID(now)
 		if err != nil {
 			return "", fmt.Errorf("failed to generate complaint ID: %w", err)
 		}
-		complainDate := time.Now().Format("02/01/2006 15:04:05")
+		complainDate := now.Format("02/01/2006 15:04:05")
 
 		// Handle Auto Assign belt
 		var canonicalBelt string
@@ -236,31 +605,31 @@ func main() {
 			Village:      village,
 			Belt:         canonicalBelt,
 			ConsumerNo:   consumerNo,
+			Category:     categories.Classify(context.Background(), description),
 			MobileNo:     mobileNo,
 			Address:      address,
 			Area:         area,
 			Description:  description,
 			ComplainDate: complainDate,
 		}
-
-		// Translate details
-		translatedName := record.ConsumerName
-		translatedDesc := record.Description
-		translatedAddr := fmt.Sprintf("%s, %s", record.Address, record.Area)
-
-		if translator != nil {
-			texts := []string{translatedName, translatedDesc, translatedAddr}
-			out, err := translator.BatchTranslateToGujarati(context.Background(), texts)
-			if err == nil {
-				translatedName = out[0]
-				translatedDesc = out[1]
-				translatedAddr = out[2]
-			}
-		}
-
-		gujaratiText := ""
-		if translatedName != "" || translatedDesc != "" || translatedAddr != "" {
-			gujaratiText = fmt.Sprintf("👤 %s\n💬 %s\n📍 %s", translatedName, translatedDesc, translatedAddr)
+		intakeIssues := quality.Check(quality.Input{
+			ConsumerNo:    consumerNo,
+			MobileNo:      mobileNo,
+			ExactLocation: address,
+			Area:          area,
+			Description:   description,
+		})
+		record.DataIssues = quality.Encode(intakeIssues)
+
+		// Geocode like scraped complaints so local ones get a Maps link too
+		mapsURL := ""
+		if deps.geocoder != nil {
+			query := geocode.Query(address, area, village, "Gujarat")
+			geoCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			point, _ := deps.geocoder.Lookup(geoCtx, query)
+			cancel()
+			record.Latitude, record.Longitude = point.Lat, point.Lon
+			mapsURL = geocode.MapsURL(point, query)
 		}
 
 		// Persist to DB
@@ -281,20 +650,30 @@ func main() {
 			Area:            record.Area,
 			Village:         record.Village,
 			Belt:            record.Belt,
+			Feeder:          feeders.Lookup(record.Village, record.Area, record.Address),
+			Category:        record.Category,
+			DataIssues:      quality.Summary(intakeIssues),
+			MapsURL:         mapsURL,
 		}
-		prettyJSON, _ := json.MarshalIndent(details, "  ", "  ")
-
-		if tg != nil {
-			msgID, err := tg.SendComplaintMessage(string(prettyJSON), record.ComplaintID, gujaratiText)
-			if err == nil && msgID != "" {
-				_ = stor.SetMessageID(record.ComplaintID, msgID)
+		if prior, err := stor.CountConsumerComplaintsSince(record.ConsumerNo, complaint.StartOfMonth(now), record.ComplaintID); err == nil {
+			details.RepeatNote = complaint.RepeatNote(prior + 1)
+		}
+		nc := complaint.NotifyComplaint(details, complaint.Translation{})
+		nc.SeenAt = now
+		// A complaint awaiting its translation stays journaled until the
+		// channels holding it back have it (see RecoverAnnouncements).
+		translating := translations != nil && runtimeFlags.Enabled(flags.Translation)
+		if translating {
+			nc.TranslationPending = true
+			if payload, err := json.Marshal(nc); err == nil {
+				stor.JournalAppend(storage.JournalAnnounce, nc.Number, string(payload))
 			}
 		}
-
-		// Send WhatsApp notification
-		if wa != nil {
-			waText := complaint.BuildWhatsAppMessage(details, gujaratiText)
-			_ = wa.SendComplaintMessage(waText, record.ComplaintID, stor)
+		if err := bus.Publish(eventbus.ComplaintNew{Complaint: nc}); err != nil {
+			log.Printf("⚠️  Failed to send notification for %s: %v", record.ComplaintID, err)
+		}
+		if translating {
+			translations.Add(nc)
 		}
 
 		// Refresh Dashboard WebSockets
@@ -305,18 +684,88 @@ func main() {
 		return complaintID, nil
 	}
 
+	// resolveFromChat backs the Slack/Discord resolve buttons. Like the
+	// dashboard, portal complaints are only resolved upstream here; the next
+	// fetch notices they are gone and edits every channel's message.
+	resolveFromChat := func(complaintID, by, via string) error {
+		if !stor.Exists(complaintID) {
+			return notify.ErrAlreadyResolved
+		}
+		apiID := stor.GetAPIID(complaintID)
+		if apiID == "" {
+			return fmt.Errorf("no portal ID recorded for complaint %s", complaintID)
+		}
+		if err := resolveFn(apiID, fmt.Sprintf("Resolved via %s by %s", via, by)); err != nil {
+			return err
+		}
+		if health.WSHub != nil {
+			health.WSHub.BroadcastResolved(apiID)
+		}
+		return nil
+	}
+
+	extraRoutes := map[string]http.Handler{}
+	for _, n := range notifier {
+		switch n := n.(type) {
+		case *notify.Slack:
+			if cfg.SlackSigningSecret != "" {
+				extraRoutes["/slack/interactions"] = n.InteractionHandler(resolveFromChat)
+			}
+		case *notify.Discord:
+			if cfg.DiscordPublicKey != "" {
+				extraRoutes["/discord/interactions"] = n.InteractionHandler(resolveFromChat)
+			}
+		}
+	}
+
+	// Live feed: bus events as Server-Sent Events for the control-room
+	// board.
+	feed := health.NewFeed()
+	feed.Redact = cfg.RedactPII
+	bus.Subscribe("live feed", feed.Handle)
+	extraRoutes["/events"] = feed
+	extraRoutes["/reports/sla"] = slaReports
+
+	if cfg.DebugEndpoints {
+		for pattern, h := range health.DebugRoutes() {
+			extraRoutes[pattern] = h
+		}
+		log.Println("🐞 Debug endpoints enabled: /debug/pprof/, /debug/stats")
+	}
+
 	// Step 6: Start health check server in background. Returned *http.Server
 	// is shut down explicitly at the end of main so in-flight requests
 	// (notably /refresh, which holds fetchMu) finish before storage closes.
-	httpServer := health.StartServer(healthMonitor, cfg.HealthCheckPort, sc, stor, refreshFn, resolveFn, registerLocalFn)
+	if standbyServer != nil {
+		shutdownStandbyServer(standbyServer)
+	}
+	httpServer := health.StartServer(healthMonitor, cfg.HealthCheckPort, sc, stor, refreshFn, resolveFn, registerLocalFn, extraRoutes)
+	httpServer.RegisterOnShutdown(feed.Close)
+
+	// Step 6a: gRPC API (optional), with the dashboard's resolve and
+	// refresh actions and a stream of the bus's events.
+	var rpcServer *rpc.Server
+	var grpcServer *grpc.Server
+	if cfg.GRPCAddr != "" {
+		rpcServer = rpc.New(stor, resolveFn, refreshFn, cfg.GRPCToken)
+		rpcServer.Redact = cfg.RedactPII
+		bus.Subscribe("grpc", rpcServer.Handle)
+		if grpcServer, err = rpcServer.Listen(cfg.GRPCAddr); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+	}
 
 	// bgWg tracks long-lived background goroutines that must finish before
 	// storage closes. Telegram + WhatsApp handlers can be mid-DB-write when a
-	// shutdown signal arrives; we wait for them rather than racing.
+	// shutdown signal arrives; we wait for them rather than racing. They
+	// all run under sup, which restarts one that panics and reports their
+	// liveness at /health.
 	var bgWg sync.WaitGroup
+	sup := supervisor.New(&bgWg)
+	healthMonitor.Supervisor = sup
 
 	// Step 7: Telegram + WhatsApp event handlers
-	callbackCancel, waCancel := startBackgroundHandlers(deps, &bgWg)
+	callbackCancel, waCancel := startBackgroundHandlers(deps, sup)
 	defer callbackCancel()
 	defer waCancel()
 
@@ -326,13 +775,11 @@ func main() {
 		if err := loginWithRetry(deps); err != nil {
 			log.Printf("⚠️  Initial login failed: %v. Continuing in offline mode.", err)
 			healthMonitor.UpdateFetchStatus(fmt.Sprintf("error: login failed: %v", err))
-			if tg != nil {
-				_ = tg.SendCriticalAlert(
-					"Startup Login Failure",
-					fmt.Sprintf("Unable to log in during startup: %v", err),
-					cfg.MaxLoginRetries,
-				)
-			}
+			sendCriticalAlert(deps,
+				"Startup Login Failure",
+				fmt.Sprintf("Unable to log in during startup: %v", err),
+				cfg.MaxLoginRetries,
+			)
 		} else {
 			log.Println("✓ Logged in")
 			log.Println("📬 Fetching complaints...")
@@ -351,16 +798,120 @@ func main() {
 	log.Printf("⏰ Running — next check in %v\n", cfg.FetchInterval)
 	log.Println("═══════════════════════════════════════════════════════════")
 
-	// Step 11: Set up graceful shutdown
-	shutdownCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
+	// Step 11: Set up graceful shutdown. Its cause — the signal, or an
+	// installed update — goes in the shutdown notice; an update then runs
+	// the new binary once cleanup is done.
+	shutdownCtx, shutdown := context.WithCancelCause(context.Background())
+	defer shutdown(nil)
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		select {
+		case sig := <-sigCh:
+			shutdown(fmt.Errorf("signal: %s", signalName(sig)))
+		case <-shutdownCtx.Done():
+		}
+	}()
+	var restartRequested atomic.Bool
 
 	// Step 11a: Scheduled summaries (cfg.ScheduledSummaries empty → no-op)
 	if len(cfg.ScheduledSummaries) > 0 {
-		bgWg.Add(1)
+		sup.Go(shutdownCtx, "summary scheduler", func(ctx context.Context) {
+			runScheduledSummaries(ctx, cfg.ScheduledSummaries, loc, runtimeFlags, tg, wa, sc, stor)
+		})
+	}
+
+	// Step 11b: Unseen-complaint reminders (UNSEEN_REMINDER_DELAY=0 → off)
+	if tg != nil && cfg.UnseenReminderDelay > 0 {
+		sup.Go(shutdownCtx, "unseen reminders", func(ctx context.Context) {
+			runUnseenReminders(ctx, cfg.UnseenReminderDelay, runtimeFlags, tg, stor)
+		})
+	}
+
+	// Step 11c: History retention (HISTORY_RETENTION_DAYS=0 → off)
+	if cfg.HistoryRetentionDays > 0 {
+		sup.Go(shutdownCtx, "history retention", func(ctx context.Context) {
+			runHistoryRetention(ctx, cfg, stor)
+		})
+	}
+
+	// Step 11d: Database backups (BACKUP_INTERVAL=0 → off)
+	if cfg.BackupInterval > 0 {
+		sup.Go(shutdownCtx, "backups", func(ctx context.Context) {
+			runBackups(ctx, cfg, stor)
+		})
+	}
+
+	// Step 11e: Session keep-alive (SESSION_KEEPALIVE_INTERVAL=0 → off)
+	if cfg.SessionKeepAlive > 0 {
+		sup.Go(shutdownCtx, "session keep-alive", func(ctx context.Context) {
+			runSessionKeepAlive(ctx, deps)
+		})
+	}
+
+	// Step 11e2: Re-login before the portal session expires (SESSION_TTL=0 → off)
+	if cfg.SessionTTL > 0 {
+		sup.Go(shutdownCtx, "session renewal", func(ctx context.Context) {
+			runSessionRenewal(ctx, deps)
+		})
+	}
+
+	// Step 11f: Age footers on complaint messages (TELEGRAM_AGE_FOOTER)
+	if tg != nil && cfg.AgeFooter {
+		sup.Go(shutdownCtx, "age footers", func(ctx context.Context) {
+			runAgeFooters(ctx, tg, stor)
+		})
+	}
+
+	// Step 11g: Weekly report with trend charts (WEEKLY_REPORT empty → off)
+	if day, hhmm, ok := cfg.WeeklyReportTime(); tg != nil && ok {
+		sup.Go(shutdownCtx, "weekly reports", func(ctx context.Context) {
+			runWeeklyReports(ctx, day, hhmm, loc, runtimeFlags, tg, stor)
+		})
+	}
+
+	// Step 11h: Pinned morning roll-call (ROLL_CALL_TIME empty → off)
+	if tg != nil && cfg.RollCallTime != "" {
+		sup.Go(shutdownCtx, "roll-calls", func(ctx context.Context) {
+			runRollCalls(ctx, cfg.RollCallTime, loc, runtimeFlags, tg, stor)
+		})
+	}
+
+	// Step 11i: Update checks (UPDATE_CHECK_INTERVAL=0 → off). The binary's
+	// path is taken now: on Windows the update renames the running one.
+	exe, exeErr := os.Executable()
+	if exeErr == nil {
+		exe, exeErr = filepath.EvalSymlinks(exe)
+	}
+	if cfg.UpdateCheckInterval > 0 {
+		if exeErr != nil {
+			log.Printf("⚠️  Update checks disabled: cannot locate the running binary: %v", exeErr)
+		} else {
+			sup.Go(shutdownCtx, "update checks", func(ctx context.Context) {
+				if runUpdateChecks(ctx, cfg, tg, exe) {
+					restartRequested.Store(true)
+					shutdown(fmt.Errorf("restarting into an update"))
+				}
+			})
+		}
+	}
+
+	// Step 11j: Old resolved-message cleanup (TELEGRAM_CLEANUP_DAYS=0 → off)
+	if tg != nil && cfg.TelegramCleanupDays > 0 {
+		sup.Go(shutdownCtx, "message cleanup", func(ctx context.Context) {
+			runMessageCleanup(ctx, cfg, tg, stor)
+		})
+	}
+
+	// Step 11k: Keep the leader lease. Losing it means another replica has
+	// taken over, so this one shuts down and restarts as a standby.
+	if lease != nil {
 		go func() {
-			defer bgWg.Done()
-			runScheduledSummaries(shutdownCtx, cfg.ScheduledSummaries, tg, wa, sc, stor)
+			if err := lease.Hold(shutdownCtx); err != nil {
+				restartRequested.Store(true)
+				shutdown(err)
+			}
 		}()
 	}
 
@@ -370,14 +921,24 @@ func main() {
 	// Graceful shutdown — explicit, ordered, never via defer for state that
 	// matters. Each step has a short timeout so a stuck goroutine cannot
 	// indefinitely block process exit.
-	log.Println("🛑 Shutdown signal received, cleaning up...")
+	reason := context.Cause(shutdownCtx).Error()
+	log.Printf("🛑 Shutting down (%s), cleaning up...", reason)
+	if cfg.TelegramLifecycleNotices {
+		if err := tg.SendShutdownNotice(reason); err != nil {
+			log.Printf("⚠️  %v", err)
+		}
+	}
 
-	// 1. Stop accepting new HTTP requests; wait briefly for in-flight ones
-	//    (notably /refresh, which may hold fetchMu) to drain.
+	// 1. Stop accepting new HTTP and gRPC requests; wait briefly for
+	//    in-flight ones (notably /refresh and TriggerFetch, which may hold
+	//    fetchMu) to drain.
 	httpShutdownCtx, httpCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	if err := httpServer.Shutdown(httpShutdownCtx); err != nil {
 		log.Printf("⚠️  HTTP server shutdown error: %v", err)
 	}
+	if rpcServer != nil {
+		rpcServer.Shutdown(httpShutdownCtx, grpcServer)
+	}
 	httpCancel()
 
 	// 2. Cancel handler contexts so Telegram long-poll and WhatsApp event
@@ -385,9 +946,10 @@ func main() {
 	callbackCancel()
 	waCancel()
 
-	// 3. Wait for the handler goroutines to actually exit. Telegram long-poll
-	//    can hang for up to ~30s on its current request; cap the wait so we
-	//    don't block the operator forever on a wedged upstream.
+	// 3. Wait for the handler goroutines to actually exit. Cancellation
+	//    aborts the Telegram long poll, but a job mid-send or mid-DB-write
+	//    still finishes first; cap the wait so we don't block the operator
+	//    forever on a wedged upstream.
 	if waited := waitWithTimeout(&bgWg, 35*time.Second); !waited {
 		log.Println("⚠️  Background handlers did not exit within 35s; closing storage anyway")
 	}
@@ -397,22 +959,62 @@ func main() {
 	//    in-flight scrape finishes. Then we hold it until storage closes.
 	fetchMu.Lock()
 
-	// 5. Disconnect WhatsApp + close translator before storage. WhatsApp's own
-	//    sqlite store is independent of complaint storage, but ordering keeps
-	//    the shutdown log readable.
+	// 5. Disconnect WhatsApp, stop translating (the queued complaints keep
+	//    their English messages) + close translator before storage, and let the
+	//    webhook, hook and MQTT queues drain. WhatsApp's own sqlite store is independent of
+	//    complaint storage, but ordering keeps the shutdown log readable.
 	if wa != nil {
 		wa.Disconnect()
 	}
+	translations.Stop()
 	if translator != nil {
 		translator.Close()
 	}
+	for _, stop := range stopQueues {
+		stop()
+	}
+	if mqttPub != nil {
+		mqttPub.Close()
+	}
 
 	// 6. Close the complaint database last.
 	if err := stor.Close(); err != nil {
 		log.Printf("⚠️  Failed to close database: %v", err)
 	}
 
+	// 7. Hand the lease to a standby now rather than after its TTL.
+	if lease != nil {
+		if err := lease.Release(); err != nil {
+			log.Printf("⚠️  Failed to release the leader lease: %v", err)
+		}
+		lease.Close()
+	}
+
 	log.Println("✅ Cleanup complete, shutting down")
+
+	// 8. Run the updated binary — or, after losing the lease, this one
+	//    again as a standby — in this process's place.
+	if restartRequested.Load() {
+		if exeErr != nil {
+			log.Fatalf("❌ Cannot restart: the running binary could not be located: %v", exeErr)
+		}
+		log.Printf("🔄 Restarting into %s", exe)
+		if err := update.Restart(exe); err != nil {
+			log.Fatalf("❌ Restart failed: %v", err)
+		}
+	}
+}
+
+// signalName is the conventional name of a shutdown signal, such as
+// "SIGTERM", for the shutdown notice.
+func signalName(sig os.Signal) string {
+	switch sig {
+	case os.Interrupt:
+		return "SIGINT"
+	case syscall.SIGTERM:
+		return "SIGTERM"
+	}
+	return sig.String()
 }
 
 // recoverSession is the two-step session recovery the fetch retry loop runs
@@ -478,22 +1080,90 @@ func fetchWithRetry(d *daemonDeps, silent bool) error {
 
 	metrics.FetchAttemptsTotal.Inc()
 
+	// One history entry per cycle, retries included, for /health/history
+	// and /status.
+	cycle := health.FetchCycle{StartedAt: time.Now(), Status: "success"}
+	defer func() {
+		cycle.Duration = time.Since(cycle.StartedAt)
+		d.healthMonitor.RecordFetchCycle(cycle)
+	}()
+
+	// One HAR file per cycle (retries and re-logins included) when
+	// PORTAL_TRACE_HAR_DIR is set; no-op otherwise.
+	defer func() {
+		if _, err := d.sc.FlushTrace("cycle"); err != nil {
+			log.Println("⚠️  Failed to write portal trace:", err)
+		}
+	}()
+
+	// INITIAL_IMPORT: the first fetch into an empty database stores what
+	// is already on the dashboard without a message each.
+	importing := complaint.ImportDue(d.stor, d.cfg.InitialImport)
+	var imported []storage.Record
+
 	for attempt := 0; attempt <= d.cfg.MaxFetchRetries; attempt++ {
 		if attempt > 0 {
 			log.Printf("🔄 Retry attempt %d/%d...", attempt, d.cfg.MaxFetchRetries)
 		}
 
-		fetcher := complaint.New(d.sc, d.stor, d.tg, d.wa, d.cfg, d.translator)
-		activeComplaintIDs, err := fetcher.FetchAll(d.cfg.ComplaintURL)
+		fetcher := complaint.New(d.sc, d.stor, d.bus, d.cfg, d.translations)
+		fetcher.Outage = d.outage
+		fetcher.Feeders = d.feeders
+		fetcher.Categories = d.categories
+		fetcher.Filter = d.filter
+		fetcher.Geocoder = d.geocoder
+		fetcher.Flags = d.flags
+		fetcher.Shard = shard.Shard{Index: d.cfg.ShardIndex, Count: d.cfg.ShardCount}
+		fetcher.Importing = importing
+		activeComplaintIDs, gap, err := fetchViews(d, fetcher, &cycle)
+		imported = append(imported, fetcher.Imported...)
 
 		if err == nil {
-			markResolvedComplaints(d.stor, d.tg, d.wa, activeComplaintIDs)
+			if importing {
+				finishInitialImport(d, imported)
+			}
+			if alert, ok := d.alerts.Recover(time.Now()); ok {
+				log.Println("✅ Fetching recovered")
+				publishAlert(d, alert)
+			}
+			// Complaints a cut-short cycle never reached are unknown, not
+			// resolved; leave them until a cycle sees the whole dashboard.
+			if gap != "" {
+				log.Printf("⚠️  Incomplete complaint list (%s); not marking missing complaints resolved this cycle", gap)
+				cycle.Incomplete = gap
+				d.healthMonitor.SetScrapeGap(gap)
+			} else {
+				d.healthMonitor.SetScrapeGap("")
+				d.absences.Seen(activeComplaintIDs)
+				markResolvedComplaints(d.stor, d.bus, activeComplaintIDs, d.confirmResolved)
+			}
+			d.tgNotifier.RetryFailedSends()
+			d.tgNotifier.FlushDeferred()
+			if d.healthMonitor.MarkPortalAvailable() && !silent {
+				log.Println("✅ DGVCL portal recovered")
+				sendPortalStatusAlert(d, false, "")
+			}
 			d.healthMonitor.UpdateFetchStatus("success")
 			metrics.LastFetchSuccessUnixSeconds.Set(time.Now().Unix())
+			go sendHeartbeat(d.heartbeat)
 			return nil
 		}
 
 		lastErr = err
+		cycle.Errors++
+		cycle.Status = fmt.Sprintf("error: %v", err)
+
+		// Portal error / maintenance pages are a portal-side outage: retrying
+		// immediately (or re-logging in) only hammers a struggling server.
+		// Record it and wait for the next scheduled cycle instead.
+		if perr := errors.AsPortalUnavailable(err); perr != nil {
+			return handlePortalUnavailable(d, perr, silent)
+		}
+
+		// Unrecognised markup parses the same way on every retry.
+		if serr := errors.AsScrapeSchema(err); serr != nil {
+			return handleScrapeSchema(d, serr, silent)
+		}
 
 		if sessionErr, ok := err.(*errors.SessionExpiredError); ok {
 			log.Println("🔄 Session expired:", sessionErr.Message)
@@ -511,21 +1181,273 @@ func fetchWithRetry(d *daemonDeps, silent bool) error {
 	metrics.FetchFailuresTotal.Inc()
 	d.healthMonitor.UpdateFetchStatus(fmt.Sprintf("error: %v", lastErr))
 
-	if !silent && d.tg != nil && d.healthMonitor.GetStatus().ConsecutiveErrors == 1 {
-		log.Println("🚨 Sending critical failure alert...")
-		alertErr := d.tg.SendCriticalAlert(
-			"Fetch/Login Failure",
-			fmt.Sprintf("Unable to fetch complaints after %d attempts. Last error: %v", d.cfg.MaxFetchRetries, lastErr),
-			d.cfg.MaxFetchRetries,
-		)
-		if alertErr != nil {
-			log.Println("⚠️  Failed to send Telegram alert:", alertErr)
-		}
+	if err := d.bus.Publish(eventbus.FetchFailed{
+		Err:      lastErr,
+		Attempts: d.cfg.MaxFetchRetries,
+		Silent:   silent,
+		Time:     time.Now(),
+	}); err != nil {
+		log.Println("⚠️  Failed to report fetch failure:", err)
 	}
 
 	return fmt.Errorf("all %d retry attempts failed: %w", d.cfg.MaxFetchRetries, lastErr)
 }
 
+// fetchViews fetches every dashboard view (one per COMPLAINT_STATUSES
+// entry) and merges their active IDs, so a complaint moving from pending
+// to assigned stays open. gap describes any view that was cut short; ""
+// when all were read in full.
+func fetchViews(d *daemonDeps, fetcher *complaint.Fetcher, cycle *health.FetchCycle) ([]string, string, error) {
+	views := d.cfg.ComplaintViews()
+	var ids, gaps []string
+	statusOf := make(map[string]string)
+	for _, v := range views {
+		viewIDs, err := fetcher.FetchAll(v.URL)
+		stats := fetcher.Stats()
+		cycle.Pages += stats.Pages
+		cycle.NewComplaints += stats.NewComplaints
+		cycle.FailedComplaints += stats.FailedComplaints
+		cycle.Errors += stats.FailedComplaints
+		if err != nil {
+			return nil, "", err
+		}
+
+		if gap := stats.Gap(); gap != "" && v.Status != "" {
+			gaps = append(gaps, fmt.Sprintf("status %s: %s", v.Status, gap))
+		} else if gap != "" {
+			gaps = append(gaps, gap)
+		}
+		for _, id := range viewIDs {
+			if _, dup := statusOf[id]; !dup {
+				ids = append(ids, id)
+			}
+			statusOf[id] = v.Status
+		}
+	}
+
+	if len(views) > 1 {
+		for id, status := range statusOf {
+			if prev, ok := d.complaintStatus[id]; ok && prev != status {
+				log.Printf("🔀 Complaint %s moved from status %s to %s", id, prev, status)
+			}
+		}
+		d.complaintStatus = statusOf
+	}
+	return ids, strings.Join(gaps, "; "), nil
+}
+
+// finishInitialImport marks the initial import done and, with
+// INITIAL_IMPORT=digest, sends the one alert listing what it stored.
+func finishInitialImport(d *daemonDeps, imported []storage.Record) {
+	log.Printf("📥 Initial import stored %d complaints without individual messages", len(imported))
+	if err := complaint.MarkImported(d.stor); err != nil {
+		log.Println("⚠️  Failed to record the initial import:", err)
+	}
+	if d.cfg.InitialImport == "digest" && len(imported) > 0 {
+		publishAlert(d, complaint.ImportDigest(imported))
+	}
+}
+
+// handlePortalUnavailable records a portal outage in health + metrics and
+// sends a one-off alert when the outage starts. It replaces the generic
+// critical alert, which would otherwise fire for what is routine portal
+// maintenance.
+func handlePortalUnavailable(d *daemonDeps, perr *errors.PortalUnavailableError, silent bool) error {
+	log.Println("🛠️  DGVCL portal unavailable, skipping retries until next cycle:", perr)
+
+	metrics.FetchFailuresTotal.Inc()
+	metrics.PortalErrorsTotal.Inc()
+	d.healthMonitor.UpdateFetchStatus(fmt.Sprintf("error: %v", perr))
+
+	if d.healthMonitor.MarkPortalUnavailable(perr.Error()) && !silent {
+		sendPortalStatusAlert(d, true, perr.Error())
+	}
+	return perr
+}
+
+// handleScrapeSchema reports a dashboard page cmon could not parse: a
+// critical alert on every channel plus, in the Telegram admin chat, the
+// page HTML so the selectors can be fixed. The fetch counts as failed, so
+// stored complaints are not marked resolved.
+func handleScrapeSchema(d *daemonDeps, serr *errors.ScrapeSchemaError, silent bool) error {
+	log.Println("🧩 Dashboard markup not recognised, skipping retries:", serr)
+
+	metrics.FetchFailuresTotal.Inc()
+	d.healthMonitor.UpdateFetchStatus(fmt.Sprintf("error: %v", serr))
+	if silent {
+		return serr
+	}
+
+	msg := fmt.Sprintf("Page %d of the complaint dashboard reports %d complaints but none could be read. The portal's table layout has probably changed; complaints are not being fetched until the scraper is updated.", serr.Page, serr.Reported)
+	if !sendCriticalAlert(d, "Dashboard Layout Changed", msg, 0) || d.tg == nil {
+		return serr
+	}
+	// The page lists consumer names and numbers that cannot be masked
+	// without parsing it, so REDACT_PII keeps it out of the chat.
+	if d.cfg.RedactPII {
+		log.Println("🔒 REDACT_PII is on; not sending the dashboard HTML to Telegram")
+		return serr
+	}
+	name := fmt.Sprintf("dashboard-page%d-%s.html", serr.Page, time.Now().Format("20060102-150405"))
+	if err := d.tg.SendDocument(d.cfg.TelegramAdminChatID, name, serr.HTML, "Dashboard page that could not be parsed"); err != nil {
+		log.Println("⚠️  Failed to send dashboard HTML:", err)
+	}
+	return serr
+}
+
+// webhookQueueSize is how many events the webhook and each hook may fall
+// behind before new ones are dropped.
+const webhookQueueSize = 256
+
+// channelEnabled reports whether NOTIFY_CHANNELS allows the named channel
+// (empty = all configured).
+func channelEnabled(cfg *config.Config, name string) bool {
+	if len(cfg.NotifyChannels) == 0 {
+		return true
+	}
+	for _, ch := range cfg.NotifyChannels {
+		if ch == name {
+			return true
+		}
+	}
+	return false
+}
+
+// buildWebhook returns the webhook sink, or nil when it is not configured
+// or not allowed by NOTIFY_CHANNELS.
+func buildWebhook(cfg *config.Config) *notify.Webhook {
+	webhook := notify.NewWebhook(notify.WebhookConfig{
+		URLs:       cfg.WebhookURLs,
+		Secret:     cfg.WebhookSecret,
+		MaxRetries: cfg.WebhookMaxRetries,
+	})
+	if webhook == nil || !channelEnabled(cfg, "webhook") {
+		return nil
+	}
+	return webhook
+}
+
+// buildNotifier assembles the notification fan-out from the channels that
+// are both configured and allowed by NOTIFY_CHANNELS (empty = all
+// configured). WhatsApp and webhooks (see buildWebhook) are wired
+// separately.
+func buildNotifier(cfg *config.Config, tg *telegram.Client, stor *storage.Storage) notify.Multi {
+	enabled := func(name string) bool { return channelEnabled(cfg, name) }
+
+	var out notify.Multi
+	if n := tg.AsNotifier(stor); n != nil && enabled("telegram") {
+		out = append(out, n)
+	}
+	if email := notify.NewEmail(notify.EmailConfig{
+		Host:     cfg.EmailSMTPHost,
+		Port:     cfg.EmailSMTPPort,
+		Username: cfg.EmailSMTPUsername,
+		Password: cfg.EmailSMTPPassword,
+		From:     cfg.EmailFrom,
+		To:       cfg.EmailTo,
+	}); email != nil && enabled("email") {
+		out = append(out, email)
+	}
+	if slack := notify.NewSlack(notify.SlackConfig{
+		BotToken:      cfg.SlackBotToken,
+		ChannelID:     cfg.SlackChannelID,
+		SigningSecret: cfg.SlackSigningSecret,
+		Location:      cfg.Location(),
+	}, stor); slack != nil && enabled("slack") {
+		out = append(out, slack)
+	}
+	if discord := notify.NewDiscord(notify.DiscordConfig{
+		BotToken:  cfg.DiscordBotToken,
+		ChannelID: cfg.DiscordChannelID,
+		PublicKey: cfg.DiscordPublicKey,
+		Location:  cfg.Location(),
+	}, stor); discord != nil && enabled("discord") {
+		out = append(out, discord)
+	}
+	if sms := notify.NewSMS(notify.SMSConfig{
+		Provider:         cfg.SMSProvider,
+		To:               cfg.SMSTo,
+		TwilioAccountSID: cfg.TwilioAccountSID,
+		TwilioAuthToken:  cfg.TwilioAuthToken,
+		TwilioFrom:       cfg.TwilioFrom,
+		MSG91AuthKey:     cfg.MSG91AuthKey,
+		MSG91TemplateID:  cfg.MSG91TemplateID,
+		Cooldown:         cfg.SMSCooldown,
+	}); sms != nil && enabled("sms") {
+		out = append(out, sms)
+	}
+
+	names := make([]string, len(out))
+	for i, n := range out {
+		names[i] = n.Name()
+	}
+	log.Printf("✓ Notification channels: %v", names)
+	return out
+}
+
+// telegramNotifier returns the Telegram channel in m, or nil when it is
+// not enabled.
+func telegramNotifier(m notify.Multi) *telegram.Notifier {
+	for _, n := range m {
+		if tn, ok := n.(*telegram.Notifier); ok {
+			return tn
+		}
+	}
+	return nil
+}
+
+// sendCriticalAlert delivers a critical alert to every notification
+// channel, unless d.alerts suppresses it as a repeat of the ongoing
+// failure. Failures are logged, never returned — an alert path must not
+// mask the error that triggered it. Reports whether the alert went out.
+func sendCriticalAlert(d *daemonDeps, errorType, errorMsg string, retryCount int) bool {
+	alert, ok := d.alerts.Fail(notify.Alert{
+		Kind:       notify.AlertCritical,
+		Title:      errorType,
+		Message:    errorMsg,
+		RetryCount: retryCount,
+		Time:       time.Now(),
+	})
+	if !ok {
+		log.Println("🔕 Critical alert suppressed; already alerted for this failure")
+		return false
+	}
+	log.Println("🚨 Sending critical failure alert...")
+	publishAlert(d, alert)
+	return true
+}
+
+// sendHeartbeat tells the dead-man's-switch service that a fetch cycle
+// just succeeded. Run in its own goroutine: the monitoring service being
+// slow or down must not hold fetchMu.
+func sendHeartbeat(p *heartbeat.Pinger) {
+	if err := p.Ping(context.Background()); err != nil {
+		log.Println("⚠️  Heartbeat ping failed:", err)
+	}
+}
+
+// publishAlert hands alert to the notification channels.
+func publishAlert(d *daemonDeps, alert notify.Alert) {
+	if err := d.bus.Publish(eventbus.AlertRaised{Alert: alert}); err != nil {
+		log.Println("⚠️  Failed to send alert:", err)
+	}
+}
+
+// sendPortalStatusAlert is sendCriticalAlert's counterpart for portal
+// outages and recoveries.
+func sendPortalStatusAlert(d *daemonDeps, down bool, detail string) {
+	alert := notify.Alert{Kind: notify.AlertPortalUp, Title: "DGVCL portal is back", Time: time.Now()}
+	if down {
+		alert = notify.Alert{
+			Kind:    notify.AlertPortalDown,
+			Title:   "DGVCL portal unavailable",
+			Message: detail,
+			Time:    time.Now(),
+		}
+	}
+	publishAlert(d, alert)
+}
+
 // triggerFetch wraps fetchWithRetry with the fetchMu lock held. Every scrape
 // (initial, ticker, dashboard /refresh, scheduled) goes through this so the
 // lock contract is enforced in one place.
@@ -539,6 +1461,12 @@ func triggerFetch(d *daemonDeps, silent bool) error {
 // times with LoginRetryDelay between attempts. Failure is fatal — the
 // caller is expected to log.Fatal on a non-nil return.
 func loginWithRetry(d *daemonDeps) error {
+	defer func() {
+		if _, err := d.sc.FlushTrace("login"); err != nil {
+			log.Println("⚠️  Failed to write portal trace:", err)
+		}
+	}()
+
 	var loginErr error
 	for attempt := 1; attempt <= d.cfg.MaxLoginRetries; attempt++ {
 		loginErr = auth.Login(d.sc, d.cfg.LoginURL, d.cfg.Username, d.cfg.Password)
@@ -554,27 +1482,148 @@ func loginWithRetry(d *daemonDeps) error {
 	return loginErr
 }
 
-// startBackgroundHandlers spawns the long-lived Telegram and WhatsApp event
-// goroutines and adds them to bgWg so the shutdown sequence can wait for
-// them. Returns the cancel funcs the shutdown sequence calls to start the
+// fetchDiff is what a fetch would change: complaints it would add and
+// stored ones it would resolve. Gap is set, and Resolved empty, when the
+// dashboard was only partly read and a real fetch would resolve nothing.
+type fetchDiff struct {
+	New      []string
+	Resolved []string
+	Gap      string
+}
+
+// runFetchOnce implements "cmon fetch-once --diff": it logs in, reads the
+// dashboard like a fetch and prints what that fetch would change, without
+// sending messages or writing storage. Returns the exit code.
+func runFetchOnce(args []string) int {
+	fs := flag.NewFlagSet("fetch-once", flag.ContinueOnError)
+	diff := fs.Bool("diff", false, "print what a fetch would change instead of applying it")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if !*diff {
+		fmt.Fprintln(os.Stderr, "usage: cmon fetch-once --diff (the daemon does the real fetches)")
+		return 2
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Println("❌ Configuration error:", err)
+		return 1
+	}
+	if err := proxy.Setup(cfg.ProxyURL, cfg.ProxyBypass); err != nil {
+		log.Println("❌ Invalid proxy settings:", err)
+		return 1
+	}
+	encKey, err := cfg.EncryptionKey()
+	if err != nil {
+		log.Printf("❌ %v", err)
+		return 1
+	}
+	stor, err := storage.NewEncrypted(encKey)
+	if err != nil {
+		log.Printf("❌ Failed to initialize storage: %v", err)
+		return 1
+	}
+	defer stor.Close()
+	sc, err := session.New(cfg.APIRateLimitRPS, cfg.APIRateLimitBurst, cfg.APIMaxRetries429)
+	if err != nil {
+		log.Println("❌ Failed to create session client:", err)
+		return 1
+	}
+	if err := sc.ConfigureTLS(session.TLSOptions{CAFile: cfg.TLSCAFile, Pins: cfg.TLSPins, Insecure: cfg.InsecureTLS}); err != nil {
+		log.Println("❌ Invalid portal TLS settings:", err)
+		return 1
+	}
+
+	d := &daemonDeps{cfg: cfg, sc: sc, stor: stor}
+	if err := loginWithRetry(d); err != nil {
+		log.Println("❌ Login failed:", err)
+		return 1
+	}
+	fd, err := diffFetch(d)
+	if err != nil {
+		log.Println("❌ Fetch failed:", err)
+		return 1
+	}
+	writeFetchDiff(os.Stdout, stor, fd, cfg.ResolveConfirmations)
+	return 0
+}
+
+// diffFetch reads every dashboard view with a dry-run fetcher and compares
+// it with storage.
+func diffFetch(d *daemonDeps) (fetchDiff, error) {
+	fetcher := complaint.New(d.sc, d.stor, nil, d.cfg, nil)
+	fetcher.DryRun = true
+	fetcher.Shard = shard.Shard{Index: d.cfg.ShardIndex, Count: d.cfg.ShardCount}
+	var cycle health.FetchCycle
+	activeIDs, gap, err := fetchViews(d, fetcher, &cycle)
+	if err != nil {
+		return fetchDiff{}, err
+	}
+
+	fd := fetchDiff{Gap: gap}
+	seen := make(map[string]bool)
+	for _, id := range fetcher.NewIDs {
+		if !seen[id] {
+			seen[id] = true
+			fd.New = append(fd.New, id)
+		}
+	}
+	if gap == "" {
+		fd.Resolved = missingComplaints(d.stor, activeIDs)
+	}
+	sort.Strings(fd.New)
+	sort.Strings(fd.Resolved)
+	return fd, nil
+}
+
+// writeFetchDiff prints fd for people: "+" lines for new complaints, "-"
+// lines for resolved ones with their consumer and belt.
+func writeFetchDiff(w io.Writer, stor *storage.Storage, fd fetchDiff, confirmations int) {
+	fmt.Fprintf(w, "New complaints (%d):\n", len(fd.New))
+	for _, id := range fd.New {
+		fmt.Fprintf(w, "  + %s\n", id)
+	}
+	if fd.Gap != "" {
+		fmt.Fprintf(w, "Would resolve nothing: incomplete complaint list (%s)\n", fd.Gap)
+		return
+	}
+	fmt.Fprintf(w, "Would be resolved (%d):\n", len(fd.Resolved))
+	for _, id := range fd.Resolved {
+		fmt.Fprintf(w, "  - %s  %s (%s)\n", id, stor.GetConsumerName(id), belt.DisplayName(stor.GetBelt(id)))
+	}
+	if confirmations > 1 && len(fd.Resolved) > 0 {
+		fmt.Fprintf(w, "  once missing from %d fetches in a row and closed on the portal (RESOLVE_CONFIRMATIONS)\n", confirmations)
+	}
+}
+
+// shutdownStandbyServer stops the standby probe server, freeing the health
+// port for the dashboard server.
+func shutdownStandbyServer(srv *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("⚠️  Standby probe server shutdown error: %v", err)
+	}
+}
+
+// startBackgroundHandlers starts the long-lived Telegram and WhatsApp
+// event goroutines under sup, whose WaitGroup the shutdown sequence waits
+// on. Returns the cancel funcs the shutdown sequence calls to start the
 // unwind.
-func startBackgroundHandlers(d *daemonDeps, bgWg *sync.WaitGroup) (callbackCancel, waCancel context.CancelFunc) {
+func startBackgroundHandlers(d *daemonDeps, sup *supervisor.Supervisor) (callbackCancel, waCancel context.CancelFunc) {
 	callbackCtx, cbCancel := context.WithCancel(context.Background())
 	if d.tg != nil {
-		bgWg.Add(1)
-		go func() {
-			defer bgWg.Done()
-			d.tg.HandleUpdates(callbackCtx, d.sc, d.stor)
-		}()
+		sup.Go(callbackCtx, "Telegram handler", func(ctx context.Context) {
+			d.tg.HandleUpdates(ctx, d.sc, d.stor)
+		})
 	}
 
 	waCtx, wCancel := context.WithCancel(context.Background())
 	if d.wa != nil {
-		bgWg.Add(1)
-		go func() {
-			defer bgWg.Done()
-			d.wa.HandleEvents(waCtx, d.sc, d.stor, d.tg, d.cfg.WhatsAppResolveEnabled, d.cfg.DebugMode)
-		}()
+		sup.Go(waCtx, "WhatsApp handler", func(ctx context.Context) {
+			d.wa.HandleEvents(ctx, d.sc, d.stor, d.cfg.WhatsAppResolveEnabled, d.cfg.DebugMode)
+		})
 	}
 
 	return cbCancel, wCancel
@@ -592,104 +1641,146 @@ func runFetchLoop(shutdownCtx context.Context, d *daemonDeps) {
 		case <-shutdownCtx.Done():
 			return
 		case <-ticker.C:
-			log.Printf("📬 Refreshing — %s", time.Now().Format("15:04:05"))
-			if err := triggerFetch(d, false); err != nil {
-				log.Println("⚠️  Final error after all retry attempts:", err)
-			} else if health.WSHub != nil {
-				health.WSHub.BroadcastRefresh()
-			}
-			log.Println("═══════════════════════════════════════════════════════════")
+			refresh(d)
 		}
 	}
 }
 
-// markResolvedComplaints checks for complaints that were previously seen
-// but are no longer on the website, and marks them as resolved in Telegram.
-func markResolvedComplaints(stor *storage.Storage, tg *telegram.Client, wa *whatsapp.Client, activeIDs []string) {
-	activeIDsMap := make(map[string]bool)
-	for _, id := range activeIDs {
-		activeIDsMap[id] = true
+// refresh runs one periodic fetch cycle. A panic ends just that cycle —
+// triggerFetch's deferred unlock frees fetchMu — and the next tick tries
+// again.
+func refresh(d *daemonDeps) {
+	defer crash.Recover("fetch loop")
+	log.Printf("📬 Refreshing — %s", time.Now().Format("15:04:05"))
+	if err := triggerFetch(d, false); err != nil {
+		log.Println("⚠️  Final error after all retry attempts:", err)
+	} else if health.WSHub != nil {
+		health.WSHub.BroadcastRefresh()
 	}
+	log.Println("═══════════════════════════════════════════════════════════")
+}
 
-	allSeen := stor.GetAllSeenComplaints()
-
+// markResolvedComplaints checks for complaints that were previously seen
+// but are no longer on the website, and marks them as resolved on every
+// notification channel. confirm, when non-nil, has the last word on each
+// missing complaint and may return its portal record, whose resolution
+// remark then goes on the edited messages; a nil confirm resolves them all.
+func markResolvedComplaints(stor *storage.Storage, bus *eventbus.Bus, activeIDs []string, confirm func(complaintID, apiID string) (*api.Record, bool)) {
 	resolvedCount := 0
-	for _, complaintID := range allSeen {
-		// Skip local complaints from auto-resolution on website sync
+	for _, complaintID := range missingComplaints(stor, activeIDs) {
 		apiID := stor.GetAPIID(complaintID)
-		lowerID := strings.ToLower(complaintID)
-		lowerAPIID := strings.ToLower(apiID)
-		if strings.HasPrefix(lowerAPIID, "local") || 
-			strings.HasPrefix(lowerAPIID, "l-") || 
-			strings.HasPrefix(lowerAPIID, "vld") ||
-			strings.HasPrefix(lowerID, "local") || 
-			strings.HasPrefix(lowerID, "l-") ||
-			strings.HasPrefix(lowerID, "vld") {
-			continue
+		var rec *api.Record
+		if confirm != nil {
+			var ok bool
+			if rec, ok = confirm(complaintID, apiID); !ok {
+				continue
+			}
 		}
+		log.Printf("✅ Marking complaint %s as resolved", complaintID)
 
-		if !activeIDsMap[complaintID] {
-			log.Printf("✅ Marking complaint %s as resolved", complaintID)
+		consumerName := stor.GetConsumerName(complaintID)
+		if consumerName == "" {
+			consumerName = "Unknown"
+		}
 
-			messageID := stor.GetMessageID(complaintID)
-			consumerName := stor.GetConsumerName(complaintID)
-			if consumerName == "" {
-				consumerName = "Unknown"
-			}
+		status := notify.Status{
+			ComplaintID:  complaintID,
+			ConsumerName: consumerName,
+			Belt:         stor.GetBelt(complaintID),
+			Category:     stor.GetCategory(complaintID),
+			Time:         time.Now(),
+		}
+		if rec != nil {
+			status.ResolvedBy, status.Remark = rec.Resolution()
+		}
+		if err := bus.Publish(eventbus.ComplaintResolved{Status: status}); err != nil {
+			log.Printf("⚠️  Failed to update notifications for complaint %s: %v", complaintID, err)
+		}
 
-			resolvedMessage := fmt.Sprintf(
-				"✅ <b>RESOLVED</b>\n\n"+
-					"Complaint #%s\n"+
-					"👤 %s\n"+
-					"🕐 %s",
-				complaintID,
-				consumerName,
-				time.Now().Format("02 Jan 2006, 03:04 PM"),
-			)
+		if rmErr := stor.Remove(complaintID); rmErr != nil {
+			log.Printf("⚠️  Failed to remove complaint %s from storage: %v", complaintID, rmErr)
+		} else {
+			log.Printf("✅ Removed resolved complaint %s from storage", complaintID)
+			resolvedCount++
+		}
+	}
 
-			if tg != nil {
-				if messageID == "" {
-					log.Printf("⚠️  Complaint %s has no Telegram message ID; removing from storage based on website state", complaintID)
-				} else if err := tg.EditMessageText(tg.ChatIDForBelt(stor.GetBelt(complaintID)), messageID, resolvedMessage); err != nil {
-					log.Printf("⚠️  Failed to edit message for complaint %s: %v", complaintID, err)
-				}
-			}
+	if resolvedCount > 0 {
+		log.Printf("🎉 Marked %d complaints as resolved", resolvedCount)
+	}
+}
 
-			if wa != nil {
-				waResolvedMsg := fmt.Sprintf(
-					"✅ RESOLVED\n\nComplaint #%s\n👤 %s\n🕐 %s",
-					complaintID,
-					consumerName,
-					time.Now().Format("02 Jan 2006, 03:04 PM"),
-				)
-				if waErr := wa.SendMessage(waResolvedMsg); waErr != nil {
-					log.Printf("⚠️  Failed to send WhatsApp resolved notice for %s: %v", complaintID, waErr)
-				}
-			}
+// missingComplaints returns the stored complaints not in activeIDs, the
+// candidates for resolution. Local complaints are never on the dashboard
+// and are left out.
+func missingComplaints(stor *storage.Storage, activeIDs []string) []string {
+	active := make(map[string]bool, len(activeIDs))
+	for _, id := range activeIDs {
+		active[id] = true
+	}
+	var missing []string
+	for _, complaintID := range stor.GetAllSeenComplaints() {
+		if active[complaintID] || isLocalComplaint(complaintID, stor.GetAPIID(complaintID)) {
+			continue
+		}
+		missing = append(missing, complaintID)
+	}
+	return missing
+}
 
-			if rmErr := stor.Remove(complaintID); rmErr != nil {
-				log.Printf("⚠️  Failed to remove complaint %s from storage: %v", complaintID, rmErr)
-			} else {
-				log.Printf("✅ Removed resolved complaint %s from storage", complaintID)
-				resolvedCount++
-			}
+// isLocalComplaint reports whether a complaint was registered in CMON
+// rather than scraped from the portal.
+func isLocalComplaint(complaintID, apiID string) bool {
+	for _, id := range []string{strings.ToLower(apiID), strings.ToLower(complaintID)} {
+		if strings.HasPrefix(id, "local") || strings.HasPrefix(id, "l-") || strings.HasPrefix(id, "vld") {
+			return true
 		}
 	}
+	return false
+}
 
-	if resolvedCount > 0 {
-		log.Printf("🎉 Marked %d complaints as resolved", resolvedCount)
+// confirmResolved decides whether a complaint missing from a complete
+// fetch is really resolved: it must have been missing from
+// RESOLVE_CONFIRMATIONS consecutive complete fetches, and its portal record
+// must no longer show it open. The record is returned for its resolution
+// remark (nil for a complaint without an API ID). A record that cannot be
+// read postpones the decision to the next fetch.
+func (d *daemonDeps) confirmResolved(complaintID, apiID string) (*api.Record, bool) {
+	misses := d.absences.Miss(complaintID)
+	if misses < d.cfg.ResolveConfirmations {
+		log.Printf("🔎 Complaint %s missing from the dashboard (%d/%d fetches); not resolved yet", complaintID, misses, d.cfg.ResolveConfirmations)
+		return nil, false
+	}
+	var rec *api.Record
+	if apiID != "" {
+		// The verdict needs the portal's current view, not a cached one.
+		api.InvalidateRecord(apiID)
+		var err error
+		if rec, err = api.FetchComplaintRecord(d.sc, apiID); err != nil {
+			log.Printf("⚠️  Could not verify complaint %s is resolved; will check again next fetch: %v", complaintID, err)
+			return nil, false
+		}
+		if rec.IsOpen() {
+			log.Printf("🔎 Complaint %s is off the dashboard but the portal still shows it %q; not resolving", complaintID, rec.Status())
+			return nil, false
+		}
 	}
+	d.absences.Forget(complaintID)
+	return rec, true
 }
 
 // runScheduledSummaries blocks until ctx is cancelled, firing a Telegram +
-// WhatsApp /summary at each configured HH:MM (IST) entry. The schedule is
+// WhatsApp /summary at each configured HH:MM entry, read in loc. The schedule is
 // re-computed every iteration off time.Now() so a config-driven daemon can
 // be paused for a long time and still pick the right next slot.
 //
 // schedules entries are HH:MM strings; pre-validated by config.parseScheduleList.
+// A slot is skipped while the summaries flag is off.
 func runScheduledSummaries(
 	ctx context.Context,
 	schedules []string,
+	loc *time.Location,
+	ff *flags.Flags,
 	tg *telegram.Client,
 	wa *whatsapp.Client,
 	sc *session.Client,
@@ -697,7 +1788,7 @@ func runScheduledSummaries(
 ) {
 	log.Printf("⏰ Scheduled summaries enabled: %v", schedules)
 	for {
-		nextAt, ok := nextScheduledFire(schedules, time.Now())
+		nextAt, ok := nextScheduledFire(schedules, time.Now().In(loc))
 		if !ok {
 			// No valid schedule entries — bail rather than hot-loop.
 			log.Printf("⚠️  No valid scheduled summary times; scheduler exiting")
@@ -715,6 +1806,10 @@ func runScheduledSummaries(
 		case <-timer.C:
 		}
 
+		if !ff.Enabled(flags.Summaries) {
+			log.Printf("📊 Scheduled summary skipped (summaries flag is off)")
+			continue
+		}
 		log.Printf("📊 Scheduled /summary firing at %s", time.Now().Format("15:04:05"))
 		if tg != nil {
 			tg.PostScheduledSummary(ctx, sc, stor)
@@ -725,8 +1820,353 @@ func runScheduledSummaries(
 	}
 }
 
+// runWeeklyReports blocks until ctx is cancelled, posting the weekly report
+// every week on day at hhmm in loc. Like scheduled summaries, a week is skipped
+// while the summaries flag is off.
+func runWeeklyReports(ctx context.Context, day time.Weekday, hhmm string, loc *time.Location, ff *flags.Flags, tg *telegram.Client, stor *storage.Storage) {
+	log.Printf("📈 Weekly report enabled: %s %s", day, hhmm)
+	for {
+		nextAt := nextWeeklyFire(day, hhmm, time.Now().In(loc))
+		timer := time.NewTimer(time.Until(nextAt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !ff.Enabled(flags.Summaries) {
+			log.Printf("📈 Weekly report skipped (summaries flag is off)")
+			continue
+		}
+		if err := tg.PostWeeklyReport(stor, time.Now().In(loc)); err != nil {
+			log.Printf("⚠️  Weekly report failed: %v", err)
+		}
+	}
+}
+
+// runRollCalls blocks until ctx is cancelled, posting and pinning the
+// morning status every day at hhmm in loc. Skipped while the summaries flag is off.
+func runRollCalls(ctx context.Context, hhmm string, loc *time.Location, ff *flags.Flags, tg *telegram.Client, stor *storage.Storage) {
+	log.Printf("📌 Morning roll-call enabled at %s", hhmm)
+	for {
+		nextAt, ok := nextScheduledFire([]string{hhmm}, time.Now().In(loc))
+		if !ok {
+			log.Printf("⚠️  Invalid roll-call time %q; scheduler exiting", hhmm)
+			return
+		}
+		timer := time.NewTimer(time.Until(nextAt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !ff.Enabled(flags.Summaries) {
+			log.Printf("📌 Roll-call skipped (summaries flag is off)")
+			continue
+		}
+		if err := tg.PostRollCall(stor, time.Now().In(loc)); err != nil {
+			log.Printf("⚠️  Roll-call failed: %v", err)
+		}
+	}
+}
+
+// unseenCheckInterval is how often runUnseenReminders looks for complaints
+// that crossed the reminder delay. A minute keeps the reminder close to the
+// configured delay without meaningful DB load.
+const unseenCheckInterval = time.Minute
+
+// runUnseenReminders blocks until ctx is cancelled, re-surfacing complaints
+// nobody acknowledged within delay. Each complaint is reminded at most once.
+// Nothing is sent while the reminders flag is off.
+func runUnseenReminders(ctx context.Context, delay time.Duration, ff *flags.Flags, tg *telegram.Client, stor *storage.Storage) {
+	log.Printf("👀 Unseen-complaint reminders enabled (after %v)", delay)
+	ticker := time.NewTicker(unseenCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !ff.Enabled(flags.Reminders) {
+			continue
+		}
+
+		unseen, err := stor.GetUnacknowledged(time.Now().Add(-delay))
+		if err != nil {
+			log.Printf("⚠️  Failed to load unacknowledged complaints: %v", err)
+			continue
+		}
+		if len(unseen) == 0 {
+			continue
+		}
+		if err := tg.SendUnseenReminder(unseen); err != nil {
+			log.Printf("⚠️  %v", err)
+			continue
+		}
+		ids := make([]string, len(unseen))
+		for i, u := range unseen {
+			ids[i] = u.ComplaintID
+		}
+		if err := stor.MarkUnseenReminded(ids); err != nil {
+			log.Printf("⚠️  Failed to record unseen reminder: %v", err)
+		}
+		log.Printf("👀 Reminded about %d unseen complaint(s)", len(unseen))
+	}
+}
+
+// ageFooterInterval is how often runAgeFooters checks for complaints whose
+// day count moved on; each message is still edited at most once a day.
+const ageFooterInterval = time.Hour
+
+// runAgeFooters blocks until ctx is cancelled, keeping the "⏳ pending N
+// days" footer on open complaint messages current.
+func runAgeFooters(ctx context.Context, tg *telegram.Client, stor *storage.Storage) {
+	log.Println("⏳ Complaint age footers enabled")
+	ticker := time.NewTicker(ageFooterInterval)
+	defer ticker.Stop()
+	for {
+		if err := tg.RefreshAgeFooters(stor, time.Now()); err != nil {
+			log.Printf("⚠️  %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// retentionInterval is how often runHistoryRetention looks for expired
+// history. Retention is measured in days, so daily is plenty.
+const retentionInterval = 24 * time.Hour
+
+// runHistoryRetention exports and then deletes resolved history older than
+// HISTORY_RETENTION_DAYS, once at startup and then every retentionInterval.
+// A failed export or upload leaves the history in place for the next run.
+func runHistoryRetention(ctx context.Context, cfg *config.Config, stor *storage.Storage) {
+	rc := retention.Config{
+		MaxAge:    time.Duration(cfg.HistoryRetentionDays) * 24 * time.Hour,
+		ExportDir: cfg.RetentionExportDir,
+		Seal:      stor.Seal,
+	}
+	if cfg.BackupUploadURL != "" {
+		rc.Upload = retention.HTTPUploader{URL: cfg.BackupUploadURL}
+	}
+	log.Printf("🗄️  History retention enabled (%d days, exports in %s)", cfg.HistoryRetentionDays, cfg.RetentionExportDir)
+
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+	for {
+		res, err := retention.Run(ctx, stor, rc, time.Now())
+		switch {
+		case err != nil:
+			log.Printf("⚠️  History retention skipped, nothing deleted: %v", err)
+		case res.Exported > 0:
+			log.Printf("🗄️  Exported %d history rows to %s, deleted %d", res.Exported, res.Archive, res.Deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runMessageCleanup removes the Telegram messages of complaints resolved
+// more than TELEGRAM_CLEANUP_DAYS ago, once at startup and then every
+// retentionInterval. Messages that fail for a transient reason are tried
+// again on the next run.
+func runMessageCleanup(ctx context.Context, cfg *config.Config, tg *telegram.Client, stor *storage.Storage) {
+	opts := telegram.CleanupOptions{
+		MaxAge:  time.Duration(cfg.TelegramCleanupDays) * 24 * time.Hour,
+		Unpin:   cfg.TelegramCleanupAction == "unpin",
+		Archive: cfg.TelegramCleanupArchive,
+	}
+	log.Printf("🧹 Telegram message cleanup enabled (%s after %d days, archive: %v)", cfg.TelegramCleanupAction, cfg.TelegramCleanupDays, opts.Archive)
+
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+	for {
+		if err := tg.CleanupResolvedMessages(stor, opts, time.Now()); err != nil {
+			log.Printf("⚠️  Telegram message cleanup incomplete: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runBackups snapshots the database into BACKUP_DIR every BACKUP_INTERVAL,
+// keeping the newest BACKUP_KEEP. The first backup is taken one interval
+// after startup so frequent restarts don't fill the directory.
+func runBackups(ctx context.Context, cfg *config.Config, stor *storage.Storage) {
+	log.Printf("💾 Database backups enabled (every %s, keeping %d in %s)", cfg.BackupInterval, cfg.BackupKeep, cfg.BackupDir)
+
+	ticker := time.NewTicker(cfg.BackupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		path, err := backup.Run(ctx, stor, cfg.BackupDir, cfg.BackupKeep, time.Now())
+		if err != nil {
+			log.Printf("⚠️  Database backup failed: %v", err)
+			continue
+		}
+		log.Printf("💾 Database backed up to %s", path)
+	}
+}
+
+// runUpdateChecks looks for a newer release every UPDATE_CHECK_INTERVAL,
+// starting now, and tells the admin chat once per release. With
+// UPDATE_AUTO_APPLY it installs the release over exe and returns true so
+// main restarts into it after the usual graceful shutdown; otherwise it
+// returns false once ctx is cancelled. A failed install is reported like
+// an available update and retried on the next check.
+func runUpdateChecks(ctx context.Context, cfg *config.Config, tg *telegram.Client, exe string) bool {
+	version := buildinfo.Version
+	log.Printf("⬆️  Update checks enabled (every %s, %s, running %s)", cfg.UpdateCheckInterval, cfg.UpdateRepo, version)
+	if version == "dev" {
+		log.Printf("⚠️  This is a dev build without an embedded version; no release will count as newer")
+	}
+	checker := update.New(cfg.UpdateRepo, version)
+
+	notified := ""
+	ticker := time.NewTicker(cfg.UpdateCheckInterval)
+	defer ticker.Stop()
+	for {
+		rel, newer, err := checker.Check(ctx)
+		if err != nil {
+			log.Printf("⚠️  Update check failed: %v", err)
+		} else if newer {
+			if cfg.UpdateAutoApply {
+				err := checker.Apply(ctx, rel, exe)
+				if err == nil {
+					log.Printf("⬆️  Installed %s over %s", rel.Tag, exe)
+					if err := tg.SendUpdateNotice(version, rel.Tag, rel.URL, true); err != nil {
+						log.Printf("⚠️  %v", err)
+					}
+					return true
+				}
+				log.Printf("⚠️  Failed to install update %s: %v", rel.Tag, err)
+			}
+			if rel.Tag != notified {
+				log.Printf("⬆️  Update available: %s (running %s)", rel.Tag, version)
+				if err := tg.SendUpdateNotice(version, rel.Tag, rel.URL, false); err != nil {
+					log.Printf("⚠️  %v", err)
+				}
+				notified = rel.Tag
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// runSessionKeepAlive calls keepSessionAlive every SESSION_KEEPALIVE_INTERVAL
+// until ctx is cancelled. It only runs between fetches: a tick that finds a
+// scrape — or its re-login and session reset — holding fetchMu is skipped.
+func runSessionKeepAlive(ctx context.Context, d *daemonDeps) {
+	log.Printf("🔐 Session keep-alive enabled (every %s)", d.cfg.SessionKeepAlive)
+
+	ticker := time.NewTicker(d.cfg.SessionKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !fetchMu.TryLock() {
+			continue
+		}
+		keepSessionAlive(d)
+		fetchMu.Unlock()
+	}
+}
+
+// runSessionRenewal logs in again SESSION_RELOGIN_MARGIN before each
+// login's SESSION_TTL runs out, until ctx is cancelled. A renewal that
+// comes due mid-fetch waits for the fetch under fetchMu, and is dropped if
+// the fetch logged in again itself. A failed one is retried a minute
+// later; the fetch loop's recovery still covers the expiry.
+func runSessionRenewal(ctx context.Context, d *daemonDeps) {
+	ttl, margin := d.cfg.SessionTTL, d.cfg.SessionReloginMargin
+	log.Printf("🔐 Session renewal enabled (%s before the %s login expiry)", margin, ttl)
+
+	for {
+		wait := time.Minute // not logged in yet
+		if at := d.sc.RenewAt(ttl, margin); !at.IsZero() {
+			wait = time.Until(at)
+		}
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			continue // re-read: a fetch may have logged in meanwhile
+		}
+
+		fetchMu.Lock()
+		if at := d.sc.RenewAt(ttl, margin); !at.IsZero() && !time.Now().Before(at) {
+			log.Println("🔐 Renewing portal session before it expires...")
+			if err := auth.Login(d.sc, d.cfg.LoginURL, d.cfg.Username, d.cfg.Password); err != nil {
+				log.Printf("⚠️  Session renewal failed, retrying in a minute: %v", err)
+				fetchMu.Unlock()
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Minute):
+				}
+				continue
+			}
+			log.Println("✓ Portal session renewed")
+		}
+		fetchMu.Unlock()
+	}
+}
+
+// keepSessionAlive refreshes the dashboard once to keep the portal session
+// warm. If the session has lapsed anyway it logs in again now, so the next
+// fetch does not start with a re-login. A failed request is left for the
+// fetch loop to classify and alert on.
+func keepSessionAlive(d *daemonDeps) {
+	if !d.sc.Authenticated() {
+		return
+	}
+	doc, err := d.sc.GetDoc(d.cfg.ComplaintURL)
+	if err != nil {
+		log.Printf("⚠️  Session keep-alive request failed: %v", err)
+		return
+	}
+	if doc.Find("#email_or_username").Length() == 0 {
+		return
+	}
+	log.Println("🔐 Portal session expired between fetches")
+	recoverSession(d.sc, d.cfg.LoginURL, d.cfg.Username, d.cfg.Password)
+}
+
 // nextScheduledFire returns the soonest future time at which any HH:MM in
-// schedules will fire, computed in time.Local (IST). Returns ok=false when
+// schedules will fire, computed in now's location. Returns ok=false when
 // schedules contains no valid entries — the caller treats that as fatal.
 func nextScheduledFire(schedules []string, now time.Time) (time.Time, bool) {
 	var best time.Time
@@ -747,7 +2187,18 @@ func nextScheduledFire(schedules []string, now time.Time) (time.Time, bool) {
 	return best, have
 }
 
-// parseHHMMToday converts "09:00" into today's 09:00 in time.Local.
+// nextWeeklyFire returns the next time strictly after now that falls on day
+// at hhmm. hhmm is pre-validated by config.
+func nextWeeklyFire(day time.Weekday, hhmm string, now time.Time) time.Time {
+	t, _ := parseHHMMToday(hhmm, now)
+	t = t.AddDate(0, 0, (int(day)-int(now.Weekday())+7)%7)
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 7)
+	}
+	return t
+}
+
+// parseHHMMToday converts "09:00" into today's 09:00 in now's location.
 func parseHHMMToday(hhmm string, now time.Time) (time.Time, bool) {
 	if len(hhmm) != 5 || hhmm[2] != ':' {
 		return time.Time{}, false
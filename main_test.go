@@ -2,11 +2,26 @@ package main
 
 import (
 	"os"
+	"sort"
+	"strings"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 
+	"cmon/internal/api"
+	"cmon/internal/auth"
+	"cmon/internal/complaint"
+	"cmon/internal/config"
+	"cmon/internal/errors"
+	"cmon/internal/eventbus"
+	"cmon/internal/health"
+	"cmon/internal/notify"
+	"cmon/internal/portaltest"
+	"cmon/internal/session"
 	"cmon/internal/storage"
+	"cmon/internal/telegram"
+	"cmon/internal/telegram/telegramtest"
 )
 
 func withTempCWD(t *testing.T) {
@@ -75,6 +90,15 @@ func TestWaitWithTimeoutReturnsFalseWhenWaitGroupBlocks(t *testing.T) {
 	}
 }
 
+func TestSignalName(t *testing.T) {
+	if got := signalName(os.Interrupt); got != "SIGINT" {
+		t.Errorf("os.Interrupt = %q", got)
+	}
+	if got := signalName(syscall.SIGTERM); got != "SIGTERM" {
+		t.Errorf("SIGTERM = %q", got)
+	}
+}
+
 func TestParseHHMMToday(t *testing.T) {
 	now := time.Date(2026, 5, 10, 12, 30, 45, 0, time.Local)
 
@@ -112,6 +136,25 @@ func TestParseHHMMToday(t *testing.T) {
 	}
 }
 
+func TestNextWeeklyFire(t *testing.T) {
+	// Sunday 10 May 2026, 14:00.
+	now := time.Date(2026, 5, 10, 14, 0, 0, 0, time.Local)
+	for _, tc := range []struct {
+		day  time.Weekday
+		hhmm string
+		want time.Time
+	}{
+		{time.Monday, "09:00", time.Date(2026, 5, 11, 9, 0, 0, 0, time.Local)},
+		{time.Sunday, "18:00", time.Date(2026, 5, 10, 18, 0, 0, 0, time.Local)},
+		{time.Sunday, "14:00", time.Date(2026, 5, 17, 14, 0, 0, 0, time.Local)},
+		{time.Saturday, "23:59", time.Date(2026, 5, 16, 23, 59, 0, 0, time.Local)},
+	} {
+		if got := nextWeeklyFire(tc.day, tc.hhmm, now); !got.Equal(tc.want) {
+			t.Errorf("nextWeeklyFire(%s %s) = %v, want %v", tc.day, tc.hhmm, got, tc.want)
+		}
+	}
+}
+
 func TestNextScheduledFireRollsOverPastTimes(t *testing.T) {
 	now := time.Date(2026, 5, 10, 14, 0, 0, 0, time.Local)
 
@@ -167,3 +210,386 @@ func TestNextScheduledFireRollsOverPastTimes(t *testing.T) {
 		}
 	})
 }
+
+// TestPipelineAgainstFakePortal runs a whole cycle against the fake portal
+// and Bot API: log in through the captcha, scrape a paginated dashboard,
+// announce the new complaints on Telegram, resolve one on the portal and
+// see the next cycle mark it resolved.
+func TestPipelineAgainstFakePortal(t *testing.T) {
+	withTempCWD(t)
+
+	portal := portaltest.NewServer("user", "secret")
+	defer portal.Close()
+	portal.PageSize = 1
+	portal.AddComplaint(portaltest.Complaint{Number: "C-101", APIID: "101", Name: "Asha Patel", MobileNo: "9876543210", ConsumerNo: "11223", Description: "No supply", Location: "Near temple", Area: "Tokarva", Date: "01/03/2026 10:00:00"})
+	portal.AddComplaint(portaltest.Complaint{Number: "C-102", APIID: "102", Name: "Ravi Shah", MobileNo: "9123456780", ConsumerNo: "44556", Description: "Pole sparking", Location: "Main road", Area: "Mota", Date: "01/03/2026 11:00:00"})
+	api.SetRecordEndpoint(portal.RecordURL())
+	api.SetResolveEndpoint(portal.ResolveURL())
+	t.Cleanup(func() {
+		api.SetRecordEndpoint(api.DefaultRecordEndpoint)
+		api.SetResolveEndpoint(api.DefaultResolveEndpoint)
+	})
+
+	bot := telegramtest.NewServer()
+	defer bot.Close()
+	t.Setenv("TELEGRAM_BOT_TOKEN", "test-token")
+	t.Setenv("TELEGRAM_CHAT_ID", "-1001")
+	t.Setenv("TELEGRAM_API_URL", bot.URL)
+	t.Setenv("TELEGRAM_RATE_INTERVAL_MS", "1")
+
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	cfg := &config.Config{MaxPages: 5, WorkerPoolSize: 2, BatchSize: 50}
+	tg := telegram.NewClient()
+	bus := eventbus.New()
+	bus.Subscribe("notify", eventbus.Notify(buildNotifier(cfg, tg, stor)))
+
+	sc, err := session.New(1000, 1000, 0)
+	if err != nil {
+		t.Fatalf("session.New: %v", err)
+	}
+	if err := auth.Login(sc, portal.LoginURL(), "user", "secret"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if portal.Logins() != 1 {
+		t.Fatalf("portal saw %d logins, want 1", portal.Logins())
+	}
+
+	fetcher := complaint.New(sc, stor, bus, cfg, nil)
+	ids, err := fetcher.FetchAll(portal.DashboardURL())
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if len(ids) != 2 || fetcher.Stats().Pages != 2 || fetcher.Stats().NewComplaints != 2 {
+		t.Fatalf("first cycle: ids %v, stats %+v", ids, fetcher.Stats())
+	}
+	sent := bot.Calls("sendMessage")
+	if len(sent) != 2 {
+		t.Fatalf("sent %d Telegram messages, want 2", len(sent))
+	}
+	for i, want := range []struct{ number, name string }{{"C-101", "Asha Patel"}, {"C-102", "Ravi Shah"}} {
+		if text := sent[i].Text(); !strings.Contains(text, want.number) || !strings.Contains(text, want.name) {
+			t.Errorf("message %d does not announce %s: %q", i, want.number, text)
+		}
+	}
+	if stor.GetMessageID("C-101") == "" {
+		t.Error("Telegram message ID not stored for C-101")
+	}
+
+	// A second cycle with nothing new sends nothing.
+	markResolvedComplaints(stor, bus, ids, nil)
+	if ids, err = fetcher.FetchAll(portal.DashboardURL()); err != nil || len(ids) != 2 {
+		t.Fatalf("second cycle: ids %v, err %v", ids, err)
+	}
+	if n := len(bot.Calls("sendMessage")); n != 2 {
+		t.Errorf("repeat cycle sent %d messages in total, want 2", n)
+	}
+
+	if err := api.ResolveComplaint(sc, "101", "fixed at site", false); err != nil {
+		t.Fatalf("ResolveComplaint: %v", err)
+	}
+	if got := portal.Resolved(); len(got) != 1 || got[0] != (portaltest.Resolution{APIID: "101", Remark: "fixed at site"}) {
+		t.Fatalf("portal resolutions: %+v", got)
+	}
+
+	ids, err = fetcher.FetchAll(portal.DashboardURL())
+	if err != nil {
+		t.Fatalf("FetchAll after resolve: %v", err)
+	}
+	markResolvedComplaints(stor, bus, ids, nil)
+	if stor.Exists("C-101") || !stor.Exists("C-102") {
+		t.Errorf("after resolve: C-101 exists=%v, C-102 exists=%v", stor.Exists("C-101"), stor.Exists("C-102"))
+	}
+	if len(bot.Calls("editMessageText")) == 0 {
+		t.Error("resolved complaint's Telegram message was not edited")
+	}
+
+	// An expired session shows the login form, which FetchAll reports.
+	portal.ExpireSessions()
+	if _, err := fetcher.FetchAll(portal.DashboardURL()); err == nil || !strings.Contains(err.Error(), "login form") {
+		t.Errorf("FetchAll with expired session: %v", err)
+	}
+}
+
+func TestConfirmResolvedWaitsForRepeatAbsenceAndPortalRecord(t *testing.T) {
+	withTempCWD(t)
+
+	portal := portaltest.NewServer("user", "secret")
+	defer portal.Close()
+	portal.AddComplaint(portaltest.Complaint{Number: "C-1", APIID: "1"})
+	portal.AddComplaint(portaltest.Complaint{Number: "C-2", APIID: "2"})
+	api.SetRecordEndpoint(portal.RecordURL())
+	api.SetResolveEndpoint(portal.ResolveURL())
+	t.Cleanup(func() {
+		api.SetRecordEndpoint(api.DefaultRecordEndpoint)
+		api.SetResolveEndpoint(api.DefaultResolveEndpoint)
+	})
+
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	// C-3 is unknown to the portal, so its record cannot be read.
+	if err := stor.SaveMultiple([]storage.Record{
+		{ComplaintID: "C-1", APIID: "1"},
+		{ComplaintID: "C-2", APIID: "2"},
+		{ComplaintID: "C-3", APIID: "3"},
+	}); err != nil {
+		t.Fatalf("save complaints: %v", err)
+	}
+
+	sc, err := session.New(1000, 1000, 0)
+	if err != nil {
+		t.Fatalf("session.New: %v", err)
+	}
+	if err := auth.Login(sc, portal.LoginURL(), "user", "secret"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if err := api.ResolveComplaint(sc, "2", "fuse replaced", false); err != nil {
+		t.Fatalf("ResolveComplaint: %v", err)
+	}
+
+	d := &daemonDeps{
+		cfg:      &config.Config{ResolveConfirmations: 2},
+		sc:       sc,
+		stor:     stor,
+		absences: complaint.NewAbsences(),
+	}
+	bus := eventbus.New()
+	var resolved []notify.Status
+	bus.Subscribe("test", func(e eventbus.Event) error {
+		if r, ok := e.(eventbus.ComplaintResolved); ok {
+			resolved = append(resolved, r.Status)
+		}
+		return nil
+	})
+	// Every stored complaint is missing from two complete fetches; C-1
+	// was only hidden from the dashboard and its record is still open.
+	for cycle := 1; cycle <= 2; cycle++ {
+		d.absences.Seen(nil)
+		markResolvedComplaints(stor, bus, nil, d.confirmResolved)
+		if cycle == 1 && (!stor.Exists("C-1") || !stor.Exists("C-2") || !stor.Exists("C-3")) {
+			t.Fatal("a single absence resolved a complaint")
+		}
+	}
+	if !stor.Exists("C-1") {
+		t.Error("C-1 resolved although the portal still shows it open")
+	}
+	if stor.Exists("C-2") {
+		t.Error("C-2 not resolved after two absences and a resolved portal record")
+	}
+	if !stor.Exists("C-3") {
+		t.Error("C-3 resolved although its record could not be read")
+	}
+	if len(resolved) != 1 || resolved[0].Resolution() != "Resolved on portal by user: fuse replaced" {
+		t.Errorf("resolved statuses = %+v", resolved)
+	}
+
+	// Reappearing on the dashboard starts the count over.
+	d.absences.Seen([]string{"C-3"})
+	if n := d.absences.Miss("C-3"); n != 1 {
+		t.Errorf("C-3 misses after reappearing = %d, want 1", n)
+	}
+}
+
+func TestFetchViewsMergesStatuses(t *testing.T) {
+	withTempCWD(t)
+
+	portal := portaltest.NewServer("user", "secret")
+	defer portal.Close()
+	portal.PageSize = 1
+	portal.AddComplaint(portaltest.Complaint{Number: "C-1", APIID: "1", Status: "2"})
+	portal.AddComplaint(portaltest.Complaint{Number: "C-2", APIID: "2", Status: "2"})
+	portal.AddComplaint(portaltest.Complaint{Number: "C-3", APIID: "3", Status: "3"})
+	portal.AddComplaint(portaltest.Complaint{Number: "C-4", APIID: "4", Status: "5"})
+	api.SetRecordEndpoint(portal.RecordURL())
+	t.Cleanup(func() { api.SetRecordEndpoint(api.DefaultRecordEndpoint) })
+
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	sc, err := session.New(1000, 1000, 0)
+	if err != nil {
+		t.Fatalf("session.New: %v", err)
+	}
+	if err := auth.Login(sc, portal.LoginURL(), "user", "secret"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	cfg := &config.Config{
+		ComplaintURL:      portal.DashboardURL() + "?coname=21&cStatus=2",
+		ComplaintStatuses: []string{"2", "3"},
+		MaxPages:          5,
+		WorkerPoolSize:    1,
+		BatchSize:         50,
+	}
+	d := &daemonDeps{cfg: cfg, sc: sc, stor: stor}
+	fetch := func() []string {
+		t.Helper()
+		var cycle health.FetchCycle
+		ids, gap, err := fetchViews(d, complaint.New(sc, stor, nil, cfg, nil), &cycle)
+		if err != nil || gap != "" {
+			t.Fatalf("fetchViews: ids %v, gap %q, err %v", ids, gap, err)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	if ids := fetch(); strings.Join(ids, ",") != "C-1,C-2,C-3" {
+		t.Errorf("pending + assigned = %v, want C-1,C-2,C-3", ids)
+	}
+
+	// Assigning C-1 moves it between views; it stays open.
+	portal.SetStatus("1", "3")
+	if ids := fetch(); strings.Join(ids, ",") != "C-1,C-2,C-3" {
+		t.Errorf("after assignment = %v, want C-1,C-2,C-3", ids)
+	}
+	if d.complaintStatus["C-1"] != "3" || d.complaintStatus["C-2"] != "2" {
+		t.Errorf("complaint statuses = %v", d.complaintStatus)
+	}
+}
+
+func TestDiffFetchChangesNothing(t *testing.T) {
+	withTempCWD(t)
+
+	portal := portaltest.NewServer("user", "secret")
+	defer portal.Close()
+	portal.AddComplaint(portaltest.Complaint{Number: "C-1", APIID: "1", Status: "2"})
+	portal.AddComplaint(portaltest.Complaint{Number: "C-2", APIID: "2", Status: "2"})
+
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+	if err := stor.SaveMultiple([]storage.Record{
+		{ComplaintID: "C-1", APIID: "1"},
+		{ComplaintID: "C-OLD", APIID: "9", ConsumerName: "Ramesh", Belt: "dahod"},
+		{ComplaintID: "VLD2026100101", APIID: "VLD2026100101"},
+	}); err != nil {
+		t.Fatalf("save complaints: %v", err)
+	}
+	sc, err := session.New(1000, 1000, 0)
+	if err != nil {
+		t.Fatalf("session.New: %v", err)
+	}
+	if err := auth.Login(sc, portal.LoginURL(), "user", "secret"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	cfg := &config.Config{
+		ComplaintURL:   portal.DashboardURL() + "?coname=21&cStatus=2",
+		MaxPages:       5,
+		WorkerPoolSize: 1,
+		BatchSize:      50,
+	}
+	fd, err := diffFetch(&daemonDeps{cfg: cfg, sc: sc, stor: stor})
+	if err != nil {
+		t.Fatalf("diffFetch: %v", err)
+	}
+	if strings.Join(fd.New, ",") != "C-2" || strings.Join(fd.Resolved, ",") != "C-OLD" {
+		t.Errorf("diff = new %v, resolved %v; want new C-2, resolved C-OLD", fd.New, fd.Resolved)
+	}
+	if !stor.IsNew("C-2") || !stor.Exists("C-OLD") {
+		t.Error("a dry-run fetch must not save or remove complaints")
+	}
+
+	var out strings.Builder
+	writeFetchDiff(&out, stor, fd, 1)
+	for _, want := range []string{"New complaints (1):\n  + C-2\n", "  - C-OLD  Ramesh ("} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestKeepSessionAliveLogsInAgainOnlyWhenExpired(t *testing.T) {
+	portal := portaltest.NewServer("user", "secret")
+	defer portal.Close()
+
+	sc, err := session.New(1000, 1000, 0)
+	if err != nil {
+		t.Fatalf("session.New: %v", err)
+	}
+	d := &daemonDeps{
+		cfg: &config.Config{LoginURL: portal.LoginURL(), ComplaintURL: portal.DashboardURL(), Username: "user", Password: "secret"},
+		sc:  sc,
+	}
+
+	// Never logged in: nothing to keep alive, and no login attempted.
+	keepSessionAlive(d)
+	if n := portal.Logins(); n != 0 {
+		t.Fatalf("keep-alive logged in %d times before the first login", n)
+	}
+
+	if err := auth.Login(sc, portal.LoginURL(), "user", "secret"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	keepSessionAlive(d)
+	if n := portal.Logins(); n != 1 {
+		t.Errorf("live session: %d logins, want 1", n)
+	}
+
+	portal.ExpireSessions()
+	keepSessionAlive(d)
+	if n := portal.Logins(); n != 2 {
+		t.Errorf("expired session: %d logins, want 2", n)
+	}
+	if sc.IsSessionExpired(portal.DashboardURL()) {
+		t.Error("session still expired after keep-alive")
+	}
+}
+
+func TestHandleScrapeSchemaAlertsWithPageHTML(t *testing.T) {
+	withTempCWD(t)
+
+	bot := telegramtest.NewServer()
+	defer bot.Close()
+	t.Setenv("TELEGRAM_BOT_TOKEN", "test-token")
+	t.Setenv("TELEGRAM_CHAT_ID", "-1001")
+	t.Setenv("TELEGRAM_API_URL", bot.URL)
+	t.Setenv("TELEGRAM_RATE_INTERVAL_MS", "1")
+
+	stor, err := storage.New()
+	if err != nil {
+		t.Fatalf("storage.New: %v", err)
+	}
+	t.Cleanup(func() { _ = stor.Close() })
+
+	cfg := &config.Config{TelegramAdminChatID: "-1002"}
+	tg := telegram.NewClient()
+	bus := eventbus.New()
+	bus.Subscribe("notify", eventbus.Notify(buildNotifier(cfg, tg, stor)))
+	d := &daemonDeps{
+		cfg:           cfg,
+		tg:            tg,
+		bus:           bus,
+		healthMonitor: health.NewMonitor(),
+		alerts:        notify.NewAlertTracker(time.Hour),
+	}
+
+	serr := errors.NewScrapeSchemaError("no links", 1, 57, []byte("<html>changed</html>"))
+	if err := handleScrapeSchema(d, serr, false); err != serr {
+		t.Fatalf("handleScrapeSchema returned %v", err)
+	}
+	if alerts := bot.Calls("sendMessage"); len(alerts) != 1 || !strings.Contains(alerts[0].Text(), "57") {
+		t.Fatalf("alerts sent: %+v", alerts)
+	}
+	docs := bot.Calls("sendDocument")
+	if len(docs) != 1 || docs[0].Params["chat_id"] != "-1002" || !strings.HasSuffix(docs[0].Params["document"].(string), ".html") {
+		t.Fatalf("documents sent: %+v", docs)
+	}
+
+	// The same failure next cycle is not re-sent, page included.
+	handleScrapeSchema(d, serr, false)
+	if n := len(bot.Calls("")); n != 2 {
+		t.Errorf("repeat failure made %d Bot API calls in total, want 2", n)
+	}
+}